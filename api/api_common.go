@@ -3,8 +3,10 @@ package api
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/ipfs/go-cid"
 
 	"github.com/filecoin-project/go-jsonrpc/auth"
 	metrics "github.com/libp2p/go-libp2p-core/metrics"
@@ -26,6 +28,7 @@ import (
 //  * Generate markdown docs
 //  * Generate openrpc blobs
 
+//stability:stable
 type Common interface {
 
 	// MethodGroup: Auth
@@ -46,6 +49,15 @@ type Common interface {
 	NetAgentVersion(ctx context.Context, p peer.ID) (string, error)           //perm:read
 	NetPeerInfo(context.Context, peer.ID) (*ExtendedPeerInfo, error)          //perm:read
 
+	// NetFindPeerDHT looks the peer up in the DHT, unlike NetFindPeer which
+	// answers from the peerstore for peers the node is or was connected to
+	NetFindPeerDHT(context.Context, peer.ID) (peer.AddrInfo, error) //perm:read stability:experimental
+	// NetFindProviders returns up to count providers of the CID found in the DHT
+	NetFindProviders(ctx context.Context, c cid.Cid, count int) ([]ProviderRecord, error) //perm:read stability:experimental
+	// NetDialAddrs dials each of the addresses of the peer on its own, closing
+	// the connections right away, and returns how each dial went
+	NetDialAddrs(context.Context, peer.AddrInfo) ([]DialResult, error) //perm:write stability:experimental
+
 	// NetBandwidthStats returns statistics about the nodes total bandwidth
 	// usage and current rate across all peers and protocols.
 	NetBandwidthStats(ctx context.Context) (metrics.Stats, error) //perm:read
@@ -76,6 +88,17 @@ type Common interface {
 
 	LogList(context.Context) ([]string, error)         //perm:write
 	LogSetLevel(context.Context, string, string) error //perm:write
+	// LogLevels lists the logging subsystems with their current level, and
+	// the temporary override in effect on them if any
+	LogLevels(context.Context) ([]LogSystem, error) //perm:write stability:experimental
+	// LogSetLevels sets the level of the logging subsystems whose name
+	// matches the regular expression in full, returning their names. With a
+	// non-zero duration the level is a temporary override, which reverts to
+	// the level the subsystem had before being overridden once the duration
+	// elapses. Overriding a subsystem again replaces the level and duration
+	// of its override but keeps that baseline. Setting a level without a
+	// duration, here or with LogSetLevel, ends the override.
+	LogSetLevels(ctx context.Context, match, level string, d time.Duration) ([]string, error) //perm:write stability:experimental
 
 	// trigger graceful shutdown
 	Shutdown(context.Context) error //perm:admin
@@ -84,6 +107,16 @@ type Common interface {
 	Session(context.Context) (uuid.UUID, error) //perm:read
 
 	Closing(context.Context) (<-chan struct{}, error) //perm:read
+
+	// MethodGroup: Config
+
+	// ConfigLoaded returns the TOML-encoded config which the node loaded at
+	// startup. Changes made to the config file since then only take effect
+	// after a restart.
+	ConfigLoaded(context.Context) ([]byte, error) //perm:admin stability:experimental
+	// ConfigHistory returns the snapshots of the effective config of the
+	// node, oldest first, taken on startup and on each change made at runtime
+	ConfigHistory(context.Context) ([]ConfigSnapshot, error) //perm:admin stability:experimental
 }
 
 // APIVersion provides various build-time information
@@ -100,12 +133,43 @@ type APIVersion struct {
 
 	// Seconds
 	BlockDelay uint64
+
+	// BlockGasLimit is the most gas the messages of a block can use
+	BlockGasLimit int64
 }
 
 func (v APIVersion) String() string {
 	return fmt.Sprintf("%s+api%s", v.Version, v.APIVersion.String())
 }
 
+// ProviderRecord is a provider found in the DHT. DHT responses don't carry
+// the age of the records, FoundAfter is how long into the lookup the provider
+// was found.
+type ProviderRecord struct {
+	Peer       peer.AddrInfo
+	FoundAfter time.Duration
+}
+
+type LogSystem struct {
+	Name  string
+	Level string
+	// Override is set when the level is a temporary override
+	Override *LogOverride `json:",omitempty"`
+}
+
+type LogOverride struct {
+	// Baseline is the level the subsystem reverts to when the override
+	// expires
+	Baseline string
+	Expires  time.Time
+}
+
+type DialResult struct {
+	Addr  string
+	Error string `json:",omitempty"`
+	Time  time.Duration
+}
+
 type NatInfo struct {
 	Reachability network.Reachability
 	PublicAddr   string
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -34,6 +35,8 @@ import (
 //go:generate go run github.com/golang/mock/mockgen -destination=mocks/mock_full.go -package=mocks . FullNode
 
 // ChainIO abstracts operations for accessing raw IPLD objects.
+//
+//stability:stable
 type ChainIO interface {
 	ChainReadObj(context.Context, cid.Cid) ([]byte, error)
 	ChainHasObj(context.Context, cid.Cid) (bool, error)
@@ -56,6 +59,8 @@ const LookbackNoLimit = abi.ChainEpoch(-1)
 //  * Generate openrpc blobs
 
 // FullNode API is a low-level interface to the Filecoin network full node
+//
+//stability:stable
 type FullNode interface {
 	Common
 
@@ -108,6 +113,11 @@ type FullNode interface {
 	// If there are no blocks at the specified epoch, a tipset at an earlier epoch
 	// will be returned.
 	ChainGetTipSetByHeight(context.Context, abi.ChainEpoch, types.TipSetKey) (*types.TipSet, error) //perm:read
+	// ChainGetTipSetByHeightPolicy returns the tipset at the specified epoch,
+	// resolving epochs without blocks by the policy: "previous" returns a
+	// tipset at an earlier epoch as ChainGetTipSetByHeight, "next" one at a
+	// later epoch, and "error" fails with a NullRound error.
+	ChainGetTipSetByHeightPolicy(context.Context, abi.ChainEpoch, NullRoundPolicy, types.TipSetKey) (*types.TipSet, error) //perm:read stability:experimental
 
 	// ChainReadObj reads ipld nodes referenced by the specified CID from chain
 	// blockstore and returns raw bytes.
@@ -160,6 +170,30 @@ type FullNode interface {
 	// If oldmsgskip is set, messages from before the requested roots are also not included.
 	ChainExport(ctx context.Context, nroots abi.ChainEpoch, oldmsgskip bool, tsk types.TipSetKey) (<-chan []byte, error) //perm:read
 
+	// ChainFollowConsumers lists the consumers of chain follow deliveries,
+	// with the tipset each of them acknowledged last
+	ChainFollowConsumers(ctx context.Context) ([]ChainFollowConsumer, error) //perm:read stability:experimental
+
+	// ChainMetricsSeries lists the series recorded by the metrics history,
+	// with the epochs of their first and last points
+	ChainMetricsSeries(ctx context.Context) ([]MetricsSeries, error) //perm:read stability:experimental
+	// ChainMetricsQuery returns the points of a series recorded by the metrics
+	// history between the epochs from and to (the latest point when to is 0),
+	// aggregated to the resolution in epochs, or to the resolution they are
+	// kept at when it's coarser or 0. Periods without samples are returned as
+	// points without samples rather than interpolated.
+	ChainMetricsQuery(ctx context.Context, series string, from, to, resolution abi.ChainEpoch) ([]MetricsPoint, error) //perm:read stability:experimental
+
+	// ChainPinHead pins the current head for a session, so that a series of
+	// reads doesn't straddle a head change. Calls made with the session in
+	// the PinnedHeadHeader HTTP header resolve the current head, that is
+	// ChainHead and empty tipset keys, to the pinned tipset, whose state is
+	// kept by splitstore compaction. The session expires after ttl, at most
+	// 10 minutes, failing the calls still using it.
+	ChainPinHead(ctx context.Context, ttl time.Duration) (PinnedHead, error) //perm:read stability:experimental
+	// ChainUnpinHead ends a session of ChainPinHead
+	ChainUnpinHead(ctx context.Context, session string) error //perm:read stability:experimental
+
 	// MethodGroup: Beacon
 	// The Beacon method group contains methods for interacting with the random beacon (DRAND)
 
@@ -225,6 +259,43 @@ type FullNode interface {
 
 	// MpoolPending returns pending mempool messages.
 	MpoolPending(context.Context, types.TipSetKey) ([]*types.SignedMessage, error) //perm:read
+	// MpoolGetReplacement returns the message which replaced the given message
+	// in the mempool, or nil if the node didn't see the message being replaced.
+	MpoolGetReplacement(context.Context, cid.Cid) (*MsgReplacement, error) //perm:read stability:experimental
+	// MpoolPropagation returns what the node observed of the gossip of a
+	// message it published, with the standing of the message in the mempool
+	MpoolPropagation(context.Context, cid.Cid) (*MsgPropagation, error) //perm:read stability:experimental
+	// MpoolGetIdempotencyKey returns the message MpoolPushMessage pushed with
+	// the idempotency key, or nil if the key wasn't used within the retention
+	// window of the mpool config
+	MpoolGetIdempotencyKey(ctx context.Context, key string) (*IdempotencyRecord, error) //perm:read stability:experimental
+	// MpoolGetMemo returns the memo kept for the message, or nil if there is
+	// none. Memos are local to the node, they never go on chain.
+	MpoolGetMemo(context.Context, cid.Cid) (*MessageMemo, error) //perm:read stability:experimental
+	// MpoolSetMemo keeps the memo for its message, replacing any previous one.
+	// An empty memo deletes it. From and To are looked up from the message if
+	// not set.
+	MpoolSetMemo(context.Context, MessageMemo) error //perm:write stability:experimental
+	// MpoolListMemos returns the memos of the messages from or to the address,
+	// or all the memos if the address is undef, oldest first
+	MpoolListMemos(context.Context, address.Address) ([]MessageMemo, error) //perm:read stability:experimental
+	// MpoolFeeHistory returns how fast the messages pushed by MpoolPushMessage
+	// landed, by fee level. It is only kept with FeeHistory set in the
+	// mpool config.
+	MpoolFeeHistory(context.Context) ([]FeeLevelStats, error) //perm:read stability:experimental
+	// MpoolFeeLevel returns the fee history of the level the message was
+	// pushed at, or nil if it wasn't recorded or the level has too little
+	// history
+	MpoolFeeLevel(context.Context, cid.Cid) (*FeeLevelStats, error) //perm:read stability:experimental
+	// MpoolGasAccuracy compares the gas used by the local messages pushed by
+	// MpoolPushMessage over the last week with their gas estimate, by
+	// destination actor family and method, and counts the ones which ran out
+	// of gas
+	MpoolGasAccuracy(context.Context) ([]GasAccuracyStats, error) //perm:read stability:experimental
+	// MpoolLanes returns the occupancy of the message pool lanes: the
+	// protected lane, of the local and priority addresses, which pruning
+	// never evicts up to its capacity, and the remote lane
+	MpoolLanes(context.Context) ([]MpoolLane, error) //perm:read stability:experimental
 
 	// MpoolSelect returns a list of pending messages for inclusion in the next block
 	MpoolSelect(context.Context, types.TipSetKey, float64) ([]*types.SignedMessage, error) //perm:read
@@ -303,6 +374,35 @@ type FullNode interface {
 	WalletDelete(context.Context, address.Address) error //perm:admin
 	// WalletValidateAddress validates whether a given string can be decoded as a well-formed address
 	WalletValidateAddress(context.Context, string) (address.Address, error) //perm:read
+	// WalletAddWatch adds a watch-only address, which is listed and tracked
+	// like the wallet addresses but can't sign: signing and sending from it
+	// fail with ErrWatchOnlyAddress. It is kept in the metadata store, not the
+	// keystore.
+	WalletAddWatch(ctx context.Context, addr address.Address, label string) error //perm:write stability:experimental
+	// WalletRemoveWatch removes a watch-only address
+	WalletRemoveWatch(context.Context, address.Address) error //perm:write stability:experimental
+	// WalletListWatch lists the watch-only addresses
+	WalletListWatch(context.Context) ([]WatchOnlyAddress, error) //perm:read stability:experimental
+	// WalletPolicySet sets the usage policy of an address, which restricts the
+	// chain messages and other data it may sign. Signing what the policy
+	// doesn't allow fails with ErrWalletPolicyViolation.
+	WalletPolicySet(context.Context, WalletUsagePolicy) error //perm:admin stability:experimental
+	// WalletPolicyGet returns the usage policy of an address, nil if it has none
+	WalletPolicyGet(context.Context, address.Address) (*WalletUsagePolicy, error) //perm:read stability:experimental
+	// WalletPolicyList lists the usage policies of the addresses which have one
+	WalletPolicyList(context.Context) ([]WalletUsagePolicy, error) //perm:read stability:experimental
+	// WalletPolicyRemove removes the usage policy of an address, which may then
+	// sign anything
+	WalletPolicyRemove(context.Context, address.Address) error //perm:admin stability:experimental
+	// WalletSignMessageOverridePolicy signs the message like WalletSignMessage,
+	// ignoring the usage policy of the address
+	WalletSignMessageOverridePolicy(context.Context, address.Address, *types.Message) (*types.SignedMessage, error) //perm:admin stability:experimental
+	// WalletUnlock unlocks an encrypted keystore with the given passphrase,
+	// enabling signing with the keys in it.
+	WalletUnlock(ctx context.Context, passphrase string) error //perm:admin stability:experimental
+	// WalletLock locks an encrypted keystore. Signing with keys in it fails
+	// until it's unlocked again.
+	WalletLock(context.Context) error //perm:admin stability:experimental
 
 	// Other
 
@@ -322,6 +422,15 @@ type FullNode interface {
 	ClientListDeals(ctx context.Context) ([]DealInfo, error) //perm:write
 	// ClientGetDealUpdates returns the status of updated deals
 	ClientGetDealUpdates(ctx context.Context) (<-chan DealInfo, error) //perm:write
+	// ClientDealHealth returns the state of the sectors holding the active
+	// deals made by the local client
+	ClientDealHealth(ctx context.Context) ([]DealHealth, error) //perm:write stability:experimental
+	// ClientDealHealthUpdates returns the health of active client deals
+	// whenever it changes, e.g. when the provider terminates a sector
+	ClientDealHealthUpdates(ctx context.Context) (<-chan DealHealth, error) //perm:write stability:experimental
+	// ClientDealSealingStatus asks the provider of a deal where it is in the
+	// sealing pipeline, and when it is expected to activate
+	ClientDealSealingStatus(ctx context.Context, proposal cid.Cid) (*DealSealingStatus, error) //perm:read stability:experimental
 	// ClientGetDealStatus returns status given a code
 	ClientGetDealStatus(ctx context.Context, statusCode uint64) (string, error) //perm:read
 	// ClientHasLocal indicates whether a certain CID is locally stored.
@@ -359,6 +468,19 @@ type FullNode interface {
 	// ClientCancelRetrievalDeal cancels an ongoing retrieval deal based on DealID
 	ClientCancelRetrievalDeal(ctx context.Context, dealid retrievalmarket.DealID) error //perm:write
 
+	// ClientRetrievalStats returns the retrieval performance of the provider,
+	// or of each provider when it's undefined, aggregated from the records
+	// of the retrievals made by the client which are still retained
+	ClientRetrievalStats(ctx context.Context, provider address.Address) ([]RetrievalProviderStats, error) //perm:write stability:experimental
+	// ClientRetrievalHistory returns the records of the retrievals made from
+	// the provider, or from all providers when it's undefined, newest first,
+	// at most limit of them when it's positive
+	ClientRetrievalHistory(ctx context.Context, provider address.Address, limit int) ([]RetrievalRecord, error) //perm:write stability:experimental
+	// ClientRetrievalStatsImport adds retrieval records, as returned by
+	// ClientRetrievalHistory on another node, skipping the ones already
+	// recorded or past the retention, and returns the number added
+	ClientRetrievalStatsImport(ctx context.Context, records []RetrievalRecord) (int, error) //perm:write stability:experimental
+
 	// ClientUnimport removes references to the specified file from filestore
 	//ClientUnimport(path string)
 
@@ -398,10 +520,27 @@ type FullNode interface {
 	StateReplay(context.Context, types.TipSetKey, cid.Cid) (*InvocResult, error) //perm:read
 	// StateGetActor returns the indicated actor's nonce and balance.
 	StateGetActor(ctx context.Context, actor address.Address, tsk types.TipSetKey) (*types.Actor, error) //perm:read
+	// StateWatchActor adds an actor to the watched actor cache. The state of
+	// watched actors (and the info of watched miners) is resolved on every head
+	// change, so that StateGetActor and StateMinerInfo calls at head are
+	// served from memory. Actors added with this call are not persisted, use
+	// the WatchedActors.Addresses config to watch actors across restarts.
+	StateWatchActor(ctx context.Context, actor address.Address) error //perm:write stability:experimental
+	// StateUnwatchActor removes an actor from the watched actor cache
+	StateUnwatchActor(ctx context.Context, actor address.Address) error //perm:write stability:experimental
+	// StateWatchedActors lists the actors in the watched actor cache
+	StateWatchedActors(ctx context.Context) ([]WatchedActor, error) //perm:read stability:experimental
 	// StateReadState returns the indicated actor's state.
 	StateReadState(ctx context.Context, actor address.Address, tsk types.TipSetKey) (*ActorState, error) //perm:read
 	// StateListMessages looks back and returns all messages with a matching to or from address, stopping at the given height.
 	StateListMessages(ctx context.Context, match *MessageMatch, tsk types.TipSetKey, toht abi.ChainEpoch) ([]cid.Cid, error) //perm:read
+	// StateAccountBalanceDelta attributes the change of the balance of the
+	// actor between the from and to tipsets, to at the head when it's empty,
+	// to the transfers, gas, rewards and burns which made it, replaying the
+	// execution of the tipsets in between. At most BalanceDeltaMaxEpochs are
+	// covered per call; when the range is longer, the result ends earlier and
+	// isn't Complete, and the rest is fetched with a call from its To.
+	StateAccountBalanceDelta(ctx context.Context, addr address.Address, from, to types.TipSetKey) (*BalanceDelta, error) //perm:read stability:experimental
 	// StateDecodeParams attempts to decode the provided params, based on the recipient actor address and method number.
 	StateDecodeParams(ctx context.Context, toAddr address.Address, method abi.MethodNum, params []byte, tsk types.TipSetKey) (interface{}, error) //perm:read
 
@@ -411,6 +550,10 @@ type FullNode interface {
 	StateMinerSectors(context.Context, address.Address, *bitfield.BitField, types.TipSetKey) ([]*miner.SectorOnChainInfo, error) //perm:read
 	// StateMinerActiveSectors returns info about sectors that a given miner is actively proving.
 	StateMinerActiveSectors(context.Context, address.Address, types.TipSetKey) ([]*miner.SectorOnChainInfo, error) //perm:read
+	// StateMinerExpirationLadder aggregates the live sectors of a miner by
+	// expiration month, flagging months holding more than maxShare (0-1) of
+	// the live sectors, and suggests sector extensions spreading them out
+	StateMinerExpirationLadder(ctx context.Context, maddr address.Address, maxShare float64, tsk types.TipSetKey) (*ExpirationLadder, error) //perm:read stability:experimental
 	// StateMinerProvingDeadline calculates the deadline at some epoch for a proving period
 	// and returns the deadline-related calculations.
 	StateMinerProvingDeadline(context.Context, address.Address, types.TipSetKey) (*dline.Info, error) //perm:read
@@ -498,6 +641,10 @@ type FullNode interface {
 	StateLookupID(context.Context, address.Address, types.TipSetKey) (address.Address, error) //perm:read
 	// StateAccountKey returns the public key address of the given ID address
 	StateAccountKey(context.Context, address.Address, types.TipSetKey) (address.Address, error) //perm:read
+	// StateLookupRobustAddress returns the robust (non-ID) address of the given
+	// ID address. For actors other than accounts this scans the init actor's
+	// address map, so it is slow.
+	StateLookupRobustAddress(context.Context, address.Address, types.TipSetKey) (address.Address, error) //perm:read stability:experimental
 	// StateChangedActors returns all the actors whose states change between the two given state CIDs
 	// TODO: Should this take tipset keys instead?
 	StateChangedActors(context.Context, cid.Cid, cid.Cid) (map[string]types.Actor, error) //perm:read
@@ -669,6 +816,30 @@ type FullNode interface {
 	// LOTUS_BACKUP_BASE_PATH environment variable set to some path, and that
 	// the path specified when calling CreateBackup is within the base path
 	CreateBackup(ctx context.Context, fpath string) error //perm:admin
+	// Methods returns the stability classification of every method of this
+	// API: whether it is stable, experimental or deprecated, and for deprecated
+	// methods the API version they will be removed in and their replacement.
+	Methods(context.Context) (map[string]MethodStability, error) //perm:read stability:experimental
+	// RPCRecordStart starts recording the API calls served by the node to a
+	// file on the node, appending a line of JSON per call with its params,
+	// result and timing, for `lotus-shed rpc replay`. Secrets, such as the
+	// tokens and private keys passed to or returned by the API, are redacted.
+	// The recording can be limited to the calls made with a token, by its
+	// fingerprint, and to some methods, and stops once the file grows by
+	// MaxBytes.
+	RPCRecordStart(ctx context.Context, cfg RPCRecordConfig) error //perm:admin stability:experimental
+	// RPCRecordStop stops the recording, returning its final status
+	RPCRecordStop(ctx context.Context) (RPCRecordStatus, error) //perm:admin stability:experimental
+	// RPCRecordStatus returns the status of the active recording, or of the
+	// last one
+	RPCRecordStatus(ctx context.Context) (RPCRecordStatus, error) //perm:admin stability:experimental
+	// ProjectedCall calls one of the heavy read methods listed in
+	// api.ProjectableMethods, as StateMinerSectors, with the JSON-encoded
+	// params, and returns only the given fields of its result. Fields are
+	// paths of JSON field names as 'Info.SealProof', those of lists and maps
+	// being the fields of their elements; unknown fields fail listing the
+	// available ones. api.CallWithProjection wraps it for Go clients.
+	ProjectedCall(ctx context.Context, method string, params []json.RawMessage, fields []string) (json.RawMessage, error) //perm:read stability:experimental
 }
 
 type FileRef struct {
@@ -699,6 +870,97 @@ type Import struct {
 	FilePath string
 }
 
+type DealSectorStatus string
+
+const (
+	// DealSectorOK means the sector is active and lives until the deal ends
+	DealSectorOK DealSectorStatus = "ok"
+	// DealSectorFaulty means the sector is faulty and will be terminated at
+	// FaultExpiration if it doesn't recover
+	DealSectorFaulty DealSectorStatus = "faulty"
+	// DealSectorExpiringEarly means the sector expires before the deal ends
+	DealSectorExpiringEarly DealSectorStatus = "expiring-early"
+	// DealSectorTerminated means the sector was terminated, or the deal slashed
+	DealSectorTerminated DealSectorStatus = "terminated"
+	// DealSectorUnknown means the sector holding the deal couldn't be found
+	DealSectorUnknown DealSectorStatus = "unknown"
+)
+
+// DealHealth describes the state of the sector holding an active deal
+type DealHealth struct {
+	ProposalCid cid.Cid
+	DealID      abi.DealID
+	Provider    address.Address
+	Sector      abi.SectorNumber
+
+	Status DealSectorStatus
+	// Message gives details for unhealthy deals
+	Message string `json:",omitempty"`
+
+	DealEnd          abi.ChainEpoch
+	SectorExpiration abi.ChainEpoch
+	FaultExpiration  abi.ChainEpoch `json:",omitempty"`
+
+	// Height is the chain height the health was checked at
+	Height abi.ChainEpoch
+}
+
+// RetrievalRecord is the outcome and performance of a retrieval made by the
+// client
+type RetrievalRecord struct {
+	Provider address.Address
+	Root     cid.Cid
+	// Size is the size of the retrieved data, as offered by the provider
+	Size    uint64
+	Started time.Time
+	// TimeToFirstByte is 0 when no data was received
+	TimeToFirstByte time.Duration
+	Duration        time.Duration
+	BytesReceived   uint64
+	// Throughput is the average bytes received per second, from the first
+	// byte on
+	Throughput uint64
+	Cost       abi.TokenAmount
+	// Error is the reason of the failure of failed retrievals
+	Error string `json:",omitempty"`
+}
+
+// RetrievalProviderStats aggregates the retrieval records of a provider
+type RetrievalProviderStats struct {
+	Provider      address.Address
+	Retrievals    int
+	Failures      int
+	BytesReceived uint64
+	TotalCost     abi.TokenAmount
+	// AvgTimeToFirstByte and AvgThroughput average the retrievals which
+	// received data
+	AvgTimeToFirstByte time.Duration
+	AvgThroughput      uint64
+	LastRetrieval      time.Time
+	LastError          string `json:",omitempty"`
+}
+
+// DealSealingStatus is where a storage deal is in the sealing pipeline of
+// its provider
+type DealSealingStatus struct {
+	// Disclosed is false when the provider doesn't share the sealing status
+	// of its deals, the other fields are then empty
+	Disclosed bool
+
+	Sector abi.SectorNumber
+	// SectorState is empty while the deal isn't assigned to a sector
+	SectorState string
+
+	// QueuePosition is the number of sectors sealing ahead of the sector of
+	// the deal, out of the QueueLength sectors sealing
+	QueuePosition int
+	QueueLength   int
+
+	// ETA is when the deal is expected to activate, estimated from the
+	// recent throughput of the pipeline. Zero when it can't be estimated.
+	ETA time.Time
+}
+
 type DealInfo struct {
 	ProposalCid cid.Cid
 	State       storagemarket.StorageDealStatus
@@ -730,6 +992,175 @@ type MsgLookup struct {
 	Height    abi.ChainEpoch
 }
 
+// WatchedActor describes an actor in the watched actor cache
+type WatchedActor struct {
+	Address address.Address
+	// ID is the ID address the actor resolved to, Undef if it isn't cached
+	ID    address.Address
+	Miner bool
+
+	// Height is the height of the tipset the cached state is for
+	Height abi.ChainEpoch
+	// Hits is the number of lookups served from the cache
+	Hits uint64
+	// Error is the error of the last refresh, if the actor couldn't be resolved
+	Error string `json:",omitempty"`
+}
+
+// ExpirationLadder is the distribution of the live sectors of a miner over
+// their expiration epochs
+type ExpirationLadder struct {
+	Height abi.ChainEpoch
+	// BucketSize is the number of epochs covered by each bucket
+	BucketSize abi.ChainEpoch
+	// Buckets are consecutive, the first one starting at Height
+	Buckets []ExpirationBucket
+	// Suggestions are sector extensions which would bring concentrated
+	// buckets down to the max share
+	Suggestions []ExpirationExtension
+}
+
+type ExpirationBucket struct {
+	Start abi.ChainEpoch
+	// Sectors is the number of live sectors expiring in [Start, Start+BucketSize)
+	Sectors  uint64
+	RawBytes abi.StoragePower
+	QAPower  abi.StoragePower
+	Pledge   abi.TokenAmount
+	// Concentrated is set when the bucket holds more than the max share of
+	// all live sectors
+	Concentrated bool
+}
+
+// ExpirationExtension is a batch of sectors to extend to the same expiration
+type ExpirationExtension struct {
+	NewExpiration abi.ChainEpoch
+	Sectors       []abi.SectorNumber
+}
+
+// ChainFollowConsumer describes a consumer of chain follow deliveries
+type ChainFollowConsumer struct {
+	Name string
+	// Cursor is the tipset the consumer acknowledged last
+	Cursor       types.TipSetKey
+	CursorHeight abi.ChainEpoch
+	// Lag is the number of epochs between the cursor and the chain head
+	Lag abi.ChainEpoch
+	// Error is the error of the last delivery, if it failed
+	Error string `json:",omitempty"`
+}
+
+// MetricsSeries describes a series recorded by the metrics history
+type MetricsSeries struct {
+	Name   string
+	Points int
+	// First is the start of the first point, Last the epoch of the last sample
+	First abi.ChainEpoch
+	Last  abi.ChainEpoch
+}
+
+// MetricsPoint aggregates the samples of a series taken in the Span epochs
+// from Epoch. Points without Samples are gaps, when nothing was recorded.
+type MetricsPoint struct {
+	Epoch   abi.ChainEpoch
+	Span    abi.ChainEpoch
+	Samples int64
+	Min     types.BigInt
+	Max     types.BigInt
+	Avg     types.BigInt
+	Last    types.BigInt
+}
+
+// RPCRecordConfig configures a recording of the API calls
+type RPCRecordConfig struct {
+	// Path is the absolute path of the file the calls are appended to
+	Path string
+	// TokenFingerprint limits the recording to the calls made with a token,
+	// as returned by TokenFingerprint
+	TokenFingerprint string
+	// Methods limits the recording to some methods
+	Methods []string
+	// MaxBytes bounds the size of the recording, 64MiB when 0
+	MaxBytes int64
+}
+
+type RPCRecordStatus struct {
+	Active           bool
+	Path             string
+	TokenFingerprint string
+	Methods          []string
+	MaxBytes         int64
+	Calls            int64
+	Bytes            int64
+	Started          time.Time
+	// StopReason tells why the last recording stopped
+	StopReason string
+}
+
+// PinnedHeadHeader is the HTTP header passing the session of ChainPinHead
+const PinnedHeadHeader = "X-Lotus-Pinned-Head"
+
+type PinnedHead struct {
+	Session string
+	TipSet  types.TipSetKey
+	Height  abi.ChainEpoch
+	Expires time.Time
+}
+
+// BalanceDeltaMaxEpochs bounds the epochs a call of StateAccountBalanceDelta
+// replays
+const BalanceDeltaMaxEpochs = 120
+
+// BalanceDeltaKind classifies the changes of the balance of an actor
+type BalanceDeltaKind string
+
+const (
+	BalanceTransferIn       BalanceDeltaKind = "transfer-in"
+	BalanceTransferOut      BalanceDeltaKind = "transfer-out"
+	BalanceGas              BalanceDeltaKind = "gas"
+	BalanceReward           BalanceDeltaKind = "reward"
+	BalanceBurn             BalanceDeltaKind = "burn"
+	BalanceMarketDeposit    BalanceDeltaKind = "market-deposit"
+	BalanceMarketWithdrawal BalanceDeltaKind = "market-withdrawal"
+)
+
+type BalanceDeltaItem struct {
+	// Height is the epoch of the tipset whose execution made the change
+	Height abi.ChainEpoch
+	Kind   BalanceDeltaKind
+	// Amount is positive for funds received, negative for funds spent
+	Amount abi.TokenAmount
+	// Message is the message, or the implicit cron or reward message, which
+	// made the change
+	Message      cid.Cid
+	Counterparty address.Address
+	Method       abi.MethodNum
+}
+
+type BalanceDelta struct {
+	Address              address.Address
+	From, To             types.TipSetKey
+	FromHeight, ToHeight abi.ChainEpoch
+	FromBalance          abi.TokenAmount
+	ToBalance            abi.TokenAmount
+	Delta                abi.TokenAmount
+	Items                []BalanceDeltaItem
+	// Unattributed is the part of Delta the items don't account for, such as
+	// funds moved by network upgrades
+	Unattributed abi.TokenAmount
+	// Locked is set for miners, with the changes of their locked funds. These
+	// are part of the balance, so they don't add to Delta.
+	Locked *BalanceDeltaLocked
+	// Complete is false when the range was cut to BalanceDeltaMaxEpochs
+	Complete bool
+}
+
+type BalanceDeltaLocked struct {
+	VestingFunds      abi.TokenAmount
+	InitialPledge     abi.TokenAmount
+	PreCommitDeposits abi.TokenAmount
+}
+
 type MsgGasCost struct {
 	Message            cid.Cid // Can be different than requested, in case it was replaced, but only gas values changed
 	GasUsed            abi.TokenAmount
@@ -1002,6 +1433,133 @@ type MpoolUpdate struct {
 	Message *types.SignedMessage
 }
 
+// MsgPropagation describes how a message published by the node spread over
+// gossipsub, and how it ranks among the pending messages
+type MsgPropagation struct {
+	Message cid.Cid
+
+	// Tracked is false when the node didn't publish the message recently, in
+	// which case there is no gossip information
+	Tracked        bool
+	FirstPublished time.Time
+	LastPublished  time.Time
+	Publishes      int
+	// SentToPeers is the number of peers the node sent the message to
+	SentToPeers int
+	// EchoPeers is the number of peers which sent or advertised the message
+	// back to the node, so had received it from the network
+	EchoPeers int
+	FirstEcho time.Time
+
+	InMpool bool
+	// PremiumRank is the 1-based position of the message when ordering the
+	// pending messages by gas premium, 0 when not in the mempool
+	PremiumRank     int
+	PendingMessages int
+
+	GasPremium abi.TokenAmount
+	GasFeeCap  abi.TokenAmount
+	BaseFee    abi.TokenAmount
+}
+
+// IdempotencyRecord is the message MpoolPushMessage pushed for an idempotency
+// key, with the fields a push reusing the key has to match
+type IdempotencyRecord struct {
+	Key     string
+	Message cid.Cid
+
+	From   address.Address
+	To     address.Address
+	Value  abi.TokenAmount
+	Method abi.MethodNum
+	Params []byte
+
+	Timestamp time.Time
+}
+
+// Matches tells whether the message is the one recorded for the key. Gas and
+// nonce aren't compared, they are set by the push.
+func (r *IdempotencyRecord) Matches(from address.Address, m *types.Message) bool {
+	return r.From == from &&
+		r.To == m.To &&
+		r.Value.Equals(m.Value) &&
+		r.Method == m.Method &&
+		bytes.Equal(r.Params, m.Params)
+}
+
+// MessageMemo is a note an operator attached to a message, kept by the node
+// only. A message replacing another in the mempool inherits its memo.
+type MessageMemo struct {
+	Message cid.Cid
+	From    address.Address
+	To      address.Address
+	Memo    string
+
+	Timestamp time.Time
+}
+
+// FeeLevelStats are the outcomes of the messages pushed at a fee level, the
+// ratio of their premium to the premium estimate when they were pushed
+type FeeLevelStats struct {
+	// MinRatio and MaxRatio bound the ratios of the level, MaxRatio is zero
+	// for the highest level
+	MinRatio float64
+	MaxRatio float64
+
+	// QuickEpochs is the window messages landing in are counted as quick
+	QuickEpochs abi.ChainEpoch
+	// Sends counts the messages pushed more than QuickEpochs ago, which
+	// landed within QuickEpochs (Quick), landed later (Slow), or haven't
+	// landed or were replaced (Stalled)
+	Sends   int
+	Quick   int
+	Slow    int
+	Stalled int
+}
+
+// GasAccuracyStats compares the gas used by the executed local messages to a
+// method of an actor family with their gas estimate
+type GasAccuracyStats struct {
+	Family string
+	Method abi.MethodNum
+	// Margin is the margin the gas estimates of the method are multiplied by
+	Margin float64
+
+	// Executed counts the executed messages, Estimated the ones among them
+	// whose gas limit was estimated
+	Executed  int
+	Estimated int
+	// UsedP50, UsedP90 and UsedMax are percentiles of the gas used over the
+	// gas estimate of the estimated messages: a margin below UsedMax would
+	// have run some of them out of gas
+	UsedP50 float64
+	UsedP90 float64
+	UsedMax float64
+
+	// OutOfGas counts the messages which ran out of gas, OutOfGasFees is the
+	// most they paid in fees for nothing
+	OutOfGas     int
+	OutOfGasFees abi.TokenAmount
+}
+
+// MpoolLane is the occupancy of a message pool lane
+type MpoolLane struct {
+	Name     string
+	Messages int
+	Senders  int
+	// Capacity is the number of messages the protected lane keeps, and the
+	// number of messages of the remote lane above which it's pruned
+	Capacity int
+}
+
+// MsgReplacement records a pending message being replaced in the mempool by
+// a message from the same sender with the same nonce
+type MsgReplacement struct {
+	Original    cid.Cid
+	Replacement cid.Cid
+	Timestamp   time.Time
+}
+
 type ComputeStateOutput struct {
 	Root  cid.Cid
 	Trace []*InvocResult
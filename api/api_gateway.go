@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/ipfs/go-cid"
 
@@ -28,6 +29,7 @@ import (
 //  * Generate markdown docs
 //  * Generate openrpc blobs
 
+//stability:stable
 type Gateway interface {
 	ChainHasObj(context.Context, cid.Cid) (bool, error)
 	ChainHead(ctx context.Context) (*types.TipSet, error)
@@ -57,5 +59,6 @@ type Gateway interface {
 	StateVerifiedClientStatus(ctx context.Context, addr address.Address, tsk types.TipSetKey) (*abi.StoragePower, error)
 	StateSearchMsg(ctx context.Context, from types.TipSetKey, msg cid.Cid, limit abi.ChainEpoch, allowReplaced bool) (*MsgLookup, error)
 	StateWaitMsg(ctx context.Context, cid cid.Cid, confidence uint64, limit abi.ChainEpoch, allowReplaced bool) (*MsgLookup, error)
+	ProjectedCall(ctx context.Context, method string, params []json.RawMessage, fields []string) (json.RawMessage, error)
 	WalletBalance(context.Context, address.Address) (types.BigInt, error)
 }
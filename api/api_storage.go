@@ -39,6 +39,8 @@ import (
 //  * Generate openrpc blobs
 
 // StorageMiner is a low-level interface to the Filecoin network storage miner node
+//
+//stability:stable
 type StorageMiner interface {
 	Common
 
@@ -48,6 +50,17 @@ type StorageMiner interface {
 	ActorAddressConfig(ctx context.Context) (AddressConfig, error)            //perm:read
 
 	MiningBase(context.Context) (*types.TipSet, error) //perm:read
+	// MiningAdvanceEpochs mines the next epochs as fast as possible on a local
+	// devnet, null rounds but the last with nulls, and returns the epoch of
+	// the head once there. It is refused by builds other than 2k and debug,
+	// and on the public networks.
+	MiningAdvanceEpochs(ctx context.Context, epochs abi.ChainEpoch, nulls bool) (abi.ChainEpoch, error) //perm:admin stability:experimental
+	// MiningSimulate runs a mining round on the head as if the miner won it,
+	// without broadcasting the block, and returns how each stage went
+	MiningSimulate(context.Context) (*BlockSimulation, error) //perm:admin stability:experimental
+	// MiningLastSimulation returns the result of the last simulated mining
+	// round, the miner running one on startup, or nil if none ran
+	MiningLastSimulation(context.Context) (*BlockSimulation, error) //perm:read stability:experimental
 
 	// Temp api for testing
 	PledgeSector(context.Context) (abi.SectorID, error) //perm:write
@@ -92,6 +105,17 @@ type StorageMiner interface {
 	// SectorTerminatePending returns a list of pending sector terminations to be sent in the next batch message
 	SectorTerminatePending(ctx context.Context) ([]abi.SectorID, error)  //perm:admin
 	SectorMarkForUpgrade(ctx context.Context, id abi.SectorNumber) error //perm:admin
+	// SectorExport copies the sealed and cache files of a proving sector, which
+	// must be in a local storage path of the miner, to the directory on the
+	// miner, along with a manifest.json holding its seal and deal info, for
+	// SectorImport on another instance of the same miner actor
+	SectorExport(ctx context.Context, id abi.SectorNumber, dir string) (SectorManifest, error) //perm:admin stability:experimental
+	// SectorImport verifies the sector exported with SectorExport to the
+	// directory of the manifest against its on-chain commitments, then copies
+	// its files to a local storage path and adds it to the sealing store as
+	// proving. Sectors not proven on chain are only imported when
+	// allowUnproven is set, verifying their files against the manifest.
+	SectorImport(ctx context.Context, manifest string, allowUnproven bool) error //perm:admin stability:experimental
 	// SectorPreCommitFlush immediately sends a PreCommit message with sectors batched for PreCommit.
 	// Returns null if message wasn't sent
 	SectorPreCommitFlush(ctx context.Context) ([]sealiface.PreCommitBatchRes, error) //perm:admin
@@ -140,11 +164,14 @@ type StorageMiner interface {
 	StorageLocal(ctx context.Context) (map[stores.ID]string, error)       //perm:admin
 	StorageStat(ctx context.Context, id stores.ID) (fsutil.FsStat, error) //perm:admin
 
-	MarketImportDealData(ctx context.Context, propcid cid.Cid, path string) error                                                                                                        //perm:write
-	MarketListDeals(ctx context.Context) ([]MarketDeal, error)                                                                                                                           //perm:read
-	MarketListRetrievalDeals(ctx context.Context) ([]retrievalmarket.ProviderDealState, error)                                                                                           //perm:read
-	MarketGetDealUpdates(ctx context.Context) (<-chan storagemarket.MinerDeal, error)                                                                                                    //perm:read
-	MarketListIncompleteDeals(ctx context.Context) ([]storagemarket.MinerDeal, error)                                                                                                    //perm:read
+	MarketImportDealData(ctx context.Context, propcid cid.Cid, path string) error              //perm:write
+	MarketListDeals(ctx context.Context) ([]MarketDeal, error)                                 //perm:read
+	MarketListRetrievalDeals(ctx context.Context) ([]retrievalmarket.ProviderDealState, error) //perm:read
+	MarketGetDealUpdates(ctx context.Context) (<-chan storagemarket.MinerDeal, error)          //perm:read
+	MarketListIncompleteDeals(ctx context.Context) ([]storagemarket.MinerDeal, error)          //perm:read
+	// MarketDealSealingStatus returns where a deal is in the sealing pipeline,
+	// as clients see it when the sealing status is disclosed
+	MarketDealSealingStatus(ctx context.Context, proposal cid.Cid) (*DealSealingStatus, error)                                                                                           //perm:read stability:experimental
 	MarketSetAsk(ctx context.Context, price types.BigInt, verifiedPrice types.BigInt, duration abi.ChainEpoch, minPieceSize abi.PaddedPieceSize, maxPieceSize abi.PaddedPieceSize) error //perm:admin
 	MarketGetAsk(ctx context.Context) (*storagemarket.SignedStorageAsk, error)                                                                                                           //perm:read
 	MarketSetRetrievalAsk(ctx context.Context, rask *retrievalmarket.Ask) error                                                                                                          //perm:admin
@@ -191,6 +218,20 @@ type StorageMiner interface {
 	CheckProvable(ctx context.Context, pp abi.RegisteredPoStProof, sectors []storage.SectorRef, expensive bool) (map[abi.SectorNumber]string, error) //perm:admin
 
 	ComputeProof(ctx context.Context, ssi []builtin.SectorInfo, rand abi.PoStRandomness) ([]builtin.PoStProof, error) //perm:read
+	// WdPoStStatus returns the progress of the proofs of the last deadline
+	// WindowPoSt ran for, or nil if it hasn't run since the miner started
+	WdPoStStatus(context.Context) (*WdPoStStatus, error) //perm:read stability:experimental
+	// WdPoStProverStatus returns the prover computing the WindowPoSt proofs,
+	// and its recent requests
+	WdPoStProverStatus(context.Context) (WdPoStProverStatus, error) //perm:read stability:experimental
+	// WdPoStSetParallelBatches sets how many batches of partitions of a
+	// deadline are proven at once, from the next deadline on, and saves it in
+	// the config
+	WdPoStSetParallelBatches(ctx context.Context, n int) error //perm:admin stability:experimental
+	// Methods returns the stability classification of every method of this
+	// API: whether it is stable, experimental or deprecated, and for deprecated
+	// methods the API version they will be removed in and their replacement.
+	Methods(context.Context) (map[string]MethodStability, error) //perm:read stability:experimental
 }
 
 var _ storiface.WorkerReturn = *new(StorageMiner)
@@ -254,6 +295,47 @@ type SealedRefs struct {
 	Refs []SealedRef
 }
 
+// SectorManifest describes a sector exported with SectorExport. Its files
+// are stored next to the manifest, as SectorManifestSealed and
+// SectorManifestCache.
+type SectorManifest struct {
+	Miner        address.Address
+	SectorNumber abi.SectorNumber
+	SectorType   abi.RegisteredSealProof
+
+	Ticket SealTicket
+	Seed   SealSeed
+	CommD  *cid.Cid
+	CommR  *cid.Cid
+
+	Pieces []SectorManifestPiece
+
+	PreCommitMessage *cid.Cid
+	CommitMessage    *cid.Cid
+}
+
+const (
+	SectorManifestName   = "manifest.json"
+	SectorManifestSealed = "sealed"
+	SectorManifestCache  = "cache"
+)
+
+// SectorManifestPiece is a piece of an exported sector, Deal being nil for
+// filler pieces
+type SectorManifestPiece struct {
+	Piece abi.PieceInfo
+	Deal  *SectorManifestDeal
+}
+
+type SectorManifestDeal struct {
+	PublishCid   *cid.Cid
+	DealID       abi.DealID
+	DealProposal *market.DealProposal
+	StartEpoch   abi.ChainEpoch
+	EndEpoch     abi.ChainEpoch
+	KeepUnsealed bool
+}
+
 type SealTicket struct {
 	Value abi.SealRandomness
 	Epoch abi.ChainEpoch
@@ -300,3 +382,71 @@ type PendingDealInfo struct {
 	PublishPeriodStart time.Time
 	PublishPeriod      time.Duration
 }
+
+type WdPoStPartitionState string
+
+const (
+	WdPoStGenerating WdPoStPartitionState = "generating"
+	WdPoStProven     WdPoStPartitionState = "proven"
+	WdPoStFailed     WdPoStPartitionState = "failed"
+	WdPoStNoSectors  WdPoStPartitionState = "nothing to prove"
+)
+
+// WdPoStStatus is the progress of the WindowPoSt proofs of a deadline
+type WdPoStStatus struct {
+	Deadline   uint64
+	Open       abi.ChainEpoch
+	Close      abi.ChainEpoch
+	Partitions []WdPoStPartitionStatus
+}
+
+type WdPoStPartitionStatus struct {
+	Index uint64
+	// Batch is the proof the partition is in, the partitions of a batch are
+	// proven and submitted together
+	Batch int
+	State WdPoStPartitionState
+	// Skipped counts the sectors left out of the proof
+	Skipped uint64
+	Error   string `json:",omitempty"`
+}
+
+// BlockSimulation is the result of a simulated mining round
+type BlockSimulation struct {
+	Start time.Time
+	Base  types.TipSetKey
+	// Epoch is the epoch of the simulated block
+	Epoch    abi.ChainEpoch
+	Messages int
+	Stages   []BlockSimulationStage
+	Took     time.Duration
+	// Budget is how long a block has from the start of its round to
+	// propagate in time
+	Budget time.Duration
+	// Error is the error of the stage which failed, if any
+	Error string `json:",omitempty"`
+}
+
+type BlockSimulationStage struct {
+	Name  string
+	Took  time.Duration
+	Error string `json:",omitempty"`
+}
+
+// WdPoStProverStatus is the prover computing the WindowPoSt proofs, and its
+// recent requests, oldest first
+type WdPoStProverStatus struct {
+	Prover   string
+	Requests []WdPoStProverRequest
+	// ParallelBatches is how many batches of partitions are proven at once
+	ParallelBatches int
+}
+
+type WdPoStProverRequest struct {
+	// Prover is "local", or the URL of the remote prover
+	Prover  string
+	Start   time.Time
+	Took    time.Duration
+	Sectors int
+	Error   string `json:",omitempty"`
+}
@@ -119,3 +119,39 @@ func TestPermTags(t *testing.T) {
 	_ = PermissionedStorMinerAPI(&StorageMinerStruct{})
 	_ = PermissionedWorkerAPI(&WorkerStruct{})
 }
+
+func TestStabilityTags(t *testing.T) {
+	tst := func(proxy interface{}, api interface{}) func(t *testing.T) {
+		return func(t *testing.T) {
+			methods := GetMethodStability(proxy)
+
+			apiType := reflect.TypeOf(api).Elem()
+			require.Equal(t, apiType.NumMethod(), len(methods))
+
+			for i := 0; i < apiType.NumMethod(); i++ {
+				name := apiType.Method(i).Name
+				m, ok := methods[name]
+				require.True(t, ok, name)
+
+				switch m.Stability {
+				case StabilityStable, StabilityExperimental:
+					require.Empty(t, m.Removal, name)
+					require.Empty(t, m.Replacement, name)
+				case StabilityDeprecated:
+					if m.Replacement != "" {
+						_, ok := apiType.MethodByName(m.Replacement)
+						require.True(t, ok, "replacement of %s doesn't exist", name)
+					}
+				default:
+					t.Errorf("method %s has invalid stability '%s'", name, m.Stability)
+				}
+			}
+		}
+	}
+
+	t.Run("full", tst(new(FullNodeStruct), new(FullNode)))
+	t.Run("miner", tst(new(StorageMinerStruct), new(StorageMiner)))
+	t.Run("worker", tst(new(WorkerStruct), new(Worker)))
+
+	require.Equal(t, StabilityExperimental, GetMethodStability(new(FullNodeStruct))["Methods"].Stability)
+}
@@ -34,6 +34,7 @@ type MsgMeta struct {
 	Extra []byte
 }
 
+//stability:stable
 type Wallet interface {
 	WalletNew(context.Context, types.KeyType) (address.Address, error)
 	WalletHas(context.Context, address.Address) (bool, error)
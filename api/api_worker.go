@@ -25,6 +25,7 @@ import (
 //  * Generate markdown docs
 //  * Generate openrpc blobs
 
+//stability:stable
 type Worker interface {
 	Version(context.Context) (Version, error) //perm:admin
 
@@ -0,0 +1,308 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/filecoin-project/go-jsonrpc"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/api/v0api"
+	"github.com/filecoin-project/lotus/api/v1api"
+)
+
+// KeepAliveConfig configures how a websocket client stays connected to the
+// node
+type KeepAliveConfig struct {
+	// PingInterval is how often the connection is pinged, it must be under
+	// half of Timeout
+	PingInterval time.Duration
+	// Timeout is how long the connection can go without an answer before
+	// it is dropped and reconnected
+	Timeout time.Duration
+
+	// MinBackoff and MaxBackoff bound the delays between the attempts to
+	// reconnect, and to subscribe again
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// OnDisconnect is called when a call first fails because the connection
+	// dropped, OnReconnect on the first call succeeding afterwards, with how
+	// long the client was disconnected. They can be used to keep metrics.
+	OnDisconnect func()
+	OnReconnect  func(downtime time.Duration)
+}
+
+// DefaultKeepAliveConfig returns the keep-alive settings of jsonrpc clients
+func DefaultKeepAliveConfig() KeepAliveConfig {
+	return KeepAliveConfig{
+		PingInterval: 5 * time.Second,
+		Timeout:      30 * time.Second,
+		MinBackoff:   100 * time.Millisecond,
+		MaxBackoff:   30 * time.Second,
+	}
+}
+
+func (cfg KeepAliveConfig) options() []jsonrpc.Option {
+	return []jsonrpc.Option{
+		jsonrpc.WithPingInterval(cfg.PingInterval),
+		jsonrpc.WithTimeout(cfg.Timeout),
+		jsonrpc.WithReconnectBackoff(cfg.MinBackoff, cfg.MaxBackoff),
+	}
+}
+
+func (cfg KeepAliveConfig) backoff(attempt int) time.Duration {
+	d := cfg.MinBackoff
+	for i := 0; i < attempt && d < cfg.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > cfg.MaxBackoff {
+		return cfg.MaxBackoff
+	}
+	return d
+}
+
+// ErrConnectionLost is returned by the calls in flight when the connection to
+// the node drops, and by those made until it is reconnected. The calls can be
+// retried.
+type ErrConnectionLost struct {
+	Err error
+}
+
+func (e *ErrConnectionLost) Error() string {
+	return "connection to the node lost: " + e.Err.Error()
+}
+
+func (e *ErrConnectionLost) Unwrap() error {
+	return e.Err
+}
+
+// isConnectionLost tells the errors jsonrpc fails calls with while the
+// websocket is down
+func isConnectionLost(err error) bool {
+	return strings.Contains(err.Error(), "websocket connection closed")
+}
+
+// Conn tracks the connection of a keep-alive client to the node, as its calls
+// see it, and keeps its subscriptions open across reconnects
+type Conn struct {
+	cfg KeepAliveConfig
+
+	lk         sync.Mutex
+	downSince  time.Time
+	reconnects int
+}
+
+func newConn(cfg KeepAliveConfig) *Conn {
+	return &Conn{cfg: cfg}
+}
+
+// Reconnects returns the number of times the client reconnected
+func (c *Conn) Reconnects() int {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	return c.reconnects
+}
+
+// Connected tells whether the last call reached the node
+func (c *Conn) Connected() bool {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	return c.downSince.IsZero()
+}
+
+// observe updates the connection state with the outcome of a call, turning
+// connection errors into ErrConnectionLost
+func (c *Conn) observe(err error) error {
+	lost := err != nil && isConnectionLost(err)
+
+	c.lk.Lock()
+	var onDisconnect func()
+	var onReconnect func(time.Duration)
+	var downtime time.Duration
+	switch {
+	case lost && c.downSince.IsZero():
+		c.downSince = time.Now()
+		onDisconnect = c.cfg.OnDisconnect
+	case !lost && !c.downSince.IsZero():
+		downtime = time.Since(c.downSince)
+		c.downSince = time.Time{}
+		c.reconnects++
+		onReconnect = c.cfg.OnReconnect
+	}
+	c.lk.Unlock()
+
+	if onDisconnect != nil {
+		onDisconnect()
+	}
+	if onReconnect != nil {
+		onReconnect(downtime)
+	}
+
+	if lost {
+		return &ErrConnectionLost{Err: err}
+	}
+	return err
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// wrap makes the methods of the Internal structs of an API proxy report to
+// the connection
+func (c *Conn) wrap(internals ...interface{}) {
+	for _, in := range internals {
+		v := reflect.ValueOf(in).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Field(i)
+			ft := f.Type()
+			if ft.Kind() != reflect.Func || f.IsNil() || ft.NumOut() == 0 || ft.Out(ft.NumOut()-1) != errorType {
+				continue
+			}
+
+			call := reflect.ValueOf(f.Interface())
+			f.Set(reflect.MakeFunc(ft, func(args []reflect.Value) []reflect.Value {
+				var out []reflect.Value
+				if ft.IsVariadic() {
+					out = call.CallSlice(args)
+				} else {
+					out = call.Call(args)
+				}
+
+				errOut := out[len(out)-1]
+				var err error
+				if !errOut.IsNil() {
+					err = errOut.Interface().(error)
+				}
+				if err = c.observe(err); err != nil {
+					errOut = reflect.New(errorType).Elem()
+					errOut.Set(reflect.ValueOf(err))
+					out[len(out)-1] = errOut
+				}
+				return out
+			}))
+		}
+	}
+}
+
+// Resubscribe keeps a subscription open until the context is done.
+// subscribe opens the subscription, and returns a function forwarding its
+// updates until its channel is closed. When the channel closes, as it does
+// when the connection drops, the subscription is opened again, calling onGap
+// first as updates may have been missed.
+func (c *Conn) Resubscribe(ctx context.Context, subscribe func(context.Context) (forward func(), err error), onGap func()) {
+	gap := false
+	for attempt := 0; ctx.Err() == nil; {
+		forward, err := subscribe(ctx)
+		if err != nil {
+			select {
+			case <-time.After(c.cfg.backoff(attempt)):
+			case <-ctx.Done():
+				return
+			}
+			attempt++
+			continue
+		}
+		attempt = 0
+
+		if gap && onGap != nil {
+			onGap()
+		}
+		forward()
+		gap = true
+	}
+}
+
+// ChainNotify returns the head changes, subscribing again when the
+// connection drops. onGap is called when the subscription is open again, the
+// first changes are the current head.
+func (c *Conn) ChainNotify(ctx context.Context, node interface {
+	ChainNotify(context.Context) (<-chan []*api.HeadChange, error)
+}, onGap func()) <-chan []*api.HeadChange {
+	out := make(chan []*api.HeadChange)
+	go func() {
+		defer close(out)
+		c.Resubscribe(ctx, func(ctx context.Context) (func(), error) {
+			ch, err := node.ChainNotify(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return func() {
+				for changes := range ch {
+					select {
+					case out <- changes:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}, nil
+		}, onGap)
+	}()
+	return out
+}
+
+// MpoolSub returns the message pool updates, subscribing again when the
+// connection drops. onGap is called when the subscription is open again.
+func (c *Conn) MpoolSub(ctx context.Context, node interface {
+	MpoolSub(context.Context) (<-chan api.MpoolUpdate, error)
+}, onGap func()) <-chan api.MpoolUpdate {
+	out := make(chan api.MpoolUpdate)
+	go func() {
+		defer close(out)
+		c.Resubscribe(ctx, func(ctx context.Context) (func(), error) {
+			ch, err := node.MpoolSub(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return func() {
+				for u := range ch {
+					select {
+					case out <- u:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}, nil
+		}, onGap)
+	}()
+	return out
+}
+
+// NewFullNodeRPCV0KeepAlive creates a new websocket jsonrpc client, which
+// pings the node and reconnects when the connection drops.
+func NewFullNodeRPCV0KeepAlive(ctx context.Context, addr string, requestHeader http.Header, cfg KeepAliveConfig) (v0api.FullNode, *Conn, jsonrpc.ClientCloser, error) {
+	var res v0api.FullNodeStruct
+	closer, err := jsonrpc.NewMergeClient(ctx, addr, "Filecoin",
+		[]interface{}{
+			&res.CommonStruct.Internal,
+			&res.Internal,
+		}, requestHeader, cfg.options()...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	conn := newConn(cfg)
+	conn.wrap(&res.CommonStruct.Internal, &res.Internal)
+	return &res, conn, closer, nil
+}
+
+// NewFullNodeRPCV1KeepAlive creates a new websocket jsonrpc client, which
+// pings the node and reconnects when the connection drops.
+func NewFullNodeRPCV1KeepAlive(ctx context.Context, addr string, requestHeader http.Header, cfg KeepAliveConfig) (api.FullNode, *Conn, jsonrpc.ClientCloser, error) {
+	var res v1api.FullNodeStruct
+	closer, err := jsonrpc.NewMergeClient(ctx, addr, "Filecoin",
+		[]interface{}{
+			&res.CommonStruct.Internal,
+			&res.Internal,
+		}, requestHeader, cfg.options()...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	conn := newConn(cfg)
+	conn.wrap(&res.CommonStruct.Internal, &res.Internal)
+	return &res, conn, closer, nil
+}
@@ -0,0 +1,170 @@
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-jsonrpc"
+)
+
+type testHandler struct{}
+
+func (h *testHandler) Ping(ctx context.Context) (int, error) {
+	return 1, nil
+}
+
+func (h *testHandler) Sub(ctx context.Context) (<-chan int, error) {
+	ch := make(chan int, 1)
+	ch <- 1
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+	return ch, nil
+}
+
+type testAPI struct {
+	Internal struct {
+		Ping func(ctx context.Context) (int, error)
+		Sub  func(ctx context.Context) (<-chan int, error)
+	}
+}
+
+// testServer is an RPC server which can be killed, dropping its connections,
+// and started again on the same address
+type testServer struct {
+	t    *testing.T
+	addr string
+	rpc  *jsonrpc.RPCServer
+	srv  *http.Server
+
+	lk    sync.Mutex
+	conns []net.Conn
+}
+
+func (s *testServer) start() {
+	l, err := net.Listen("tcp", s.addr)
+	require.NoError(s.t, err)
+	s.addr = l.Addr().String()
+
+	s.srv = &http.Server{
+		Handler: s.rpc,
+		ConnState: func(c net.Conn, st http.ConnState) {
+			if st == http.StateNew {
+				s.lk.Lock()
+				s.conns = append(s.conns, c)
+				s.lk.Unlock()
+			}
+		},
+	}
+	go s.srv.Serve(l) //nolint:errcheck
+}
+
+func (s *testServer) kill() {
+	require.NoError(s.t, s.srv.Close())
+
+	// websockets are hijacked, the server doesn't close them
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	for _, c := range s.conns {
+		_ = c.Close()
+	}
+	s.conns = nil
+}
+
+func TestKeepAliveReconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rpc := jsonrpc.NewServer()
+	rpc.Register("Test", &testHandler{})
+	srv := &testServer{t: t, addr: "127.0.0.1:0", rpc: rpc}
+	srv.start()
+	defer srv.kill()
+
+	var lk sync.Mutex
+	var disconnects int
+	var downtimes []time.Duration
+	cfg := KeepAliveConfig{
+		PingInterval: 50 * time.Millisecond,
+		Timeout:      500 * time.Millisecond,
+		MinBackoff:   10 * time.Millisecond,
+		MaxBackoff:   100 * time.Millisecond,
+		OnDisconnect: func() {
+			lk.Lock()
+			disconnects++
+			lk.Unlock()
+		},
+		OnReconnect: func(downtime time.Duration) {
+			lk.Lock()
+			downtimes = append(downtimes, downtime)
+			lk.Unlock()
+		},
+	}
+
+	var res testAPI
+	closer, err := jsonrpc.NewMergeClient(ctx, "ws://"+srv.addr+"/rpc/v0", "Test", []interface{}{&res.Internal}, nil, cfg.options()...)
+	require.NoError(t, err)
+	defer closer()
+	conn := newConn(cfg)
+	conn.wrap(&res.Internal)
+
+	updates := make(chan int)
+	gaps := make(chan struct{}, 1)
+	go conn.Resubscribe(ctx, func(ctx context.Context) (func(), error) {
+		ch, err := res.Internal.Sub(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return func() {
+			for u := range ch {
+				updates <- u
+			}
+		}, nil
+	}, func() { gaps <- struct{}{} })
+
+	n, err := res.Internal.Ping(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, 1, <-updates)
+
+	srv.kill()
+
+	// calls fail with a retryable error while the node is away
+	require.Eventually(t, func() bool {
+		_, err := res.Internal.Ping(ctx)
+		var lost *ErrConnectionLost
+		return xerrors.As(err, &lost)
+	}, 5*time.Second, 10*time.Millisecond)
+	require.False(t, conn.Connected())
+
+	srv.start()
+
+	require.Eventually(t, func() bool {
+		_, err := res.Internal.Ping(ctx)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	require.True(t, conn.Connected())
+	require.Equal(t, 1, conn.Reconnects())
+
+	// the subscription is open again, after the consumer is told of the gap
+	select {
+	case <-gaps:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no gap reported")
+	}
+	require.Equal(t, 1, <-updates)
+
+	lk.Lock()
+	defer lk.Unlock()
+	require.Equal(t, 1, disconnects)
+	require.Len(t, downtimes, 1)
+	require.Greater(t, int64(downtimes[0]), int64(0))
+}
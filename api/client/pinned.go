@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/filecoin-project/lotus/api"
+)
+
+// WithPinnedHead calls fn with a client of the node at addr whose reads of the
+// current head resolve to the head pinned by a session of ChainPinHead, so
+// that they give consistent answers across head changes. The session lasts
+// ttl, and ends when fn returns; fn's context is canceled when it expires, and
+// calls still made in it fail.
+func WithPinnedHead(ctx context.Context, addr string, requestHeader http.Header, ttl time.Duration, fn func(ctx context.Context, a api.FullNode, head api.PinnedHead) error) error {
+	a, closer, err := NewFullNodeRPCV1(ctx, addr, requestHeader)
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	head, err := a.ChainPinHead(ctx, ttl)
+	if err != nil {
+		return err
+	}
+	// the session may have expired already
+	defer a.ChainUnpinHead(ctx, head.Session) //nolint:errcheck
+
+	header := requestHeader.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set(api.PinnedHeadHeader, head.Session)
+
+	pa, pcloser, err := NewFullNodeRPCV1(ctx, addr, header)
+	if err != nil {
+		return err
+	}
+	defer pcloser()
+
+	ctx, cancel := context.WithDeadline(ctx, head.Expires)
+	defer cancel()
+
+	return fn(ctx, pa, head)
+}
@@ -100,6 +100,20 @@ func main() {
 
 			fmt.Printf("Perms: %s\n\n", perms)
 
+			switch meth.Tag.Get("stability") {
+			case "experimental":
+				fmt.Printf("Stability: experimental\n\n")
+			case "deprecated":
+				fmt.Printf("Stability: deprecated")
+				if removal := meth.Tag.Get("removal"); removal != "" {
+					fmt.Printf(", removal in %s", removal)
+				}
+				if replacement := meth.Tag.Get("replacement"); replacement != "" {
+					fmt.Printf(", use [%s](#%s) instead", replacement, replacement)
+				}
+				fmt.Printf("\n\n")
+			}
+
 			if strings.Count(m.InputExample, "\n") > 0 {
 				fmt.Printf("Inputs:\n```json\n%s\n```\n\n", m.InputExample)
 			} else {
@@ -116,6 +116,10 @@ func init() {
 	addExample(api.SyncStateStage(1))
 	addExample(api.FullAPIVersion1)
 	addExample(api.PCHInbound)
+	addExample(api.MTChainMsg)
+	addExample(api.DealSectorFaulty)
+	addExample(api.NullRoundPrevious)
+	addExample(api.BalanceTransferIn)
 	addExample(time.Minute)
 	addExample(datatransfer.TransferID(3))
 	addExample(datatransfer.Ongoing)
@@ -126,6 +130,8 @@ func init() {
 	addExample(network.ReachabilityPublic)
 	addExample(build.NewestNetworkVersion)
 	addExample(map[string]int{"name": 42})
+	addExample(map[string]string{"value": "hash"})
+	addExample(map[string]float64{"storageminer/5": 1.5})
 	addExample(map[string]time.Time{"name": time.Unix(1615243938, 0).UTC()})
 	addExample(&types.ExecutionTrace{
 		Msg:    ExampleValue("init", reflect.TypeOf(&types.Message{}), nil).(*types.Message),
@@ -140,6 +146,13 @@ func init() {
 	addExample(map[string]api.MarketBalance{
 		"t026363": ExampleValue("init", reflect.TypeOf(api.MarketBalance{}), nil).(api.MarketBalance),
 	})
+	addExample(map[string]api.MethodStability{
+		"StateGetReceipt": {
+			Stability:   api.StabilityDeprecated,
+			Removal:     "v1",
+			Replacement: "StateSearchMsg",
+		},
+	})
 	addExample(map[string]*pubsub.TopicScoreSnapshot{
 		"/blocks": {
 			TimeInMesh:               time.Minute,
@@ -189,6 +202,7 @@ func init() {
 		},
 	})
 	addExample(api.SectorState(sealing.Proving))
+	addExample(api.WdPoStProven)
 	addExample(stores.ID("76f1988b-ef30-4d7e-b3ec-9a627f4ba5a8"))
 	addExample(storiface.FTUnsealed)
 	addExample(storiface.PathSealing)
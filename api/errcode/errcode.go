@@ -0,0 +1,136 @@
+// Package errcode is the registry of the codes identifying common classes of
+// API errors, so that consumers don't need to parse error messages.
+package errcode
+
+import (
+	"sort"
+	"strings"
+)
+
+// Code identifies a class of API errors
+type Code int
+
+const (
+	// Unknown is the code of errors without a registered class
+	Unknown Code = 0
+
+	ActorNotFound     Code = 1000
+	MessageNotFound   Code = 1001
+	TipSetNotFound    Code = 1002
+	OutOfGas          Code = 1003
+	NonceTooLow       Code = 1004
+	InsufficientFunds Code = 1005
+	LookbackExceeded  Code = 1006
+
+	IdempotencyKeyConflict Code = 1007
+	WatchOnlyAddress       Code = 1008
+	WalletPolicyViolation  Code = 1009
+	NullRound              Code = 1010
+)
+
+// Info describes an error code
+type Info struct {
+	Name        string
+	Description string
+
+	// messages are substrings of the messages of errors with the code. The
+	// JSON-RPC transport only carries error messages, so errors received
+	// over RPC are classified by them.
+	messages []string
+}
+
+var registry = map[Code]Info{
+	ActorNotFound: {
+		Name:        "ActorNotFound",
+		Description: "the actor doesn't exist in the state tree",
+		messages:    []string{"actor not found"},
+	},
+	MessageNotFound: {
+		Name:        "MessageNotFound",
+		Description: "the message isn't in the chain store",
+		messages:    []string{"failed to load message"},
+	},
+	TipSetNotFound: {
+		Name:        "TipSetNotFound",
+		Description: "a block of the tipset isn't in the chain store",
+		// no message distinguishes missing tipsets from other missing blocks
+	},
+	OutOfGas: {
+		Name:        "OutOfGas",
+		Description: "message execution ran out of gas",
+		messages:    []string{"SysErrOutOfGas"},
+	},
+	NonceTooLow: {
+		Name:        "NonceTooLow",
+		Description: "the message nonce was already used by the sender",
+		messages:    []string{"message nonce too low"},
+	},
+	InsufficientFunds: {
+		Name:        "InsufficientFunds",
+		Description: "the sender balance can't cover the message value and gas",
+		messages:    []string{"not enough funds"},
+	},
+	LookbackExceeded: {
+		Name:        "LookbackExceeded",
+		Description: "the request looks further back in the chain than allowed",
+		messages:    []string{"lookbacks of more than"},
+	},
+	IdempotencyKeyConflict: {
+		Name:        "IdempotencyKeyConflict",
+		Description: "the idempotency key was already used for a different message",
+		messages:    []string{"idempotency key conflict"},
+	},
+	WatchOnlyAddress: {
+		Name:        "WatchOnlyAddress",
+		Description: "the address is watch-only, the node has no key to sign with",
+		messages:    []string{"watch-only address"},
+	},
+	WalletPolicyViolation: {
+		Name:        "WalletPolicyViolation",
+		Description: "the usage policy of the address doesn't allow signing the message or data",
+		messages:    []string{"wallet usage policy violation"},
+	},
+	NullRound: {
+		Name:        "NullRound",
+		Description: "no tipset was mined at the requested epoch",
+		messages:    []string{"null round"},
+	},
+}
+
+// Lookup returns the description of a registered code
+func Lookup(c Code) (Info, bool) {
+	i, ok := registry[c]
+	return i, ok
+}
+
+// Codes returns all registered codes, in order
+func Codes() []Code {
+	out := make([]Code, 0, len(registry))
+	for c := range registry {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i] < out[j]
+	})
+	return out
+}
+
+// FromMessage classifies an error by its message, returning Unknown when it
+// doesn't match any registered code
+func FromMessage(msg string) Code {
+	for _, c := range Codes() {
+		for _, m := range registry[c].messages {
+			if strings.Contains(msg, m) {
+				return c
+			}
+		}
+	}
+	return Unknown
+}
+
+func (c Code) String() string {
+	if i, ok := registry[c]; ok {
+		return i.Name
+	}
+	return "Unknown"
+}
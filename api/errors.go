@@ -0,0 +1,45 @@
+package api
+
+import (
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/api/errcode"
+)
+
+// Error is an error of a class registered in the errcode package. It keeps the
+// message of the error it wraps, so error strings stay the same.
+type Error struct {
+	Code errcode.Code
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WrapError tags err with the code, returning nil for a nil err
+func WrapError(code errcode.Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// ErrorCode returns the code of err. Errors received over JSON-RPC only carry
+// their message, so errors without an *Error in their chain are classified by
+// message.
+func ErrorCode(err error) errcode.Code {
+	if err == nil {
+		return errcode.Unknown
+	}
+
+	var aerr *Error
+	if xerrors.As(err, &aerr) {
+		return aerr.Code
+	}
+	return errcode.FromMessage(err.Error())
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/api/errcode"
+)
+
+func TestErrorCode(t *testing.T) {
+	base := xerrors.New("minimum expected nonce is 5: message nonce too low")
+
+	err := xerrors.Errorf("mpool push: %w", WrapError(errcode.NonceTooLow, base))
+	require.Equal(t, errcode.NonceTooLow, ErrorCode(err))
+	require.Equal(t, "mpool push: "+base.Error(), err.Error(), "message is kept")
+	require.True(t, xerrors.Is(err, base))
+
+	// over RPC only the message is left
+	require.Equal(t, errcode.NonceTooLow, ErrorCode(xerrors.New(err.Error())))
+
+	// codes without a message are only known from the error
+	tsErr := WrapError(errcode.TipSetNotFound, xerrors.New("get block: blockstore: block not found"))
+	require.Equal(t, errcode.TipSetNotFound, ErrorCode(tsErr))
+	require.Equal(t, errcode.Unknown, ErrorCode(xerrors.New(tsErr.Error())))
+
+	require.Equal(t, errcode.Unknown, ErrorCode(xerrors.New("something else")))
+	require.Equal(t, errcode.Unknown, ErrorCode(nil))
+	require.Nil(t, WrapError(errcode.OutOfGas, nil))
+
+	for _, c := range errcode.Codes() {
+		info, ok := errcode.Lookup(c)
+		require.True(t, ok)
+		require.Equal(t, info.Name, c.String())
+	}
+}
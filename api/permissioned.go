@@ -1,6 +1,13 @@
 package api
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
 	"github.com/filecoin-project/go-jsonrpc/auth"
 )
 
@@ -41,3 +48,52 @@ func PermissionedWalletAPI(a Wallet) Wallet {
 	auth.PermissionedProxy(AllPermissions, DefaultPerms, a, &out.Internal)
 	return &out
 }
+
+type callerKey struct{}
+
+// CallerHandler passes the fingerprint of the API token of requests on in
+// their context, for Caller. It wraps the auth.Handler of the API.
+func CallerHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" {
+			token = r.FormValue("token")
+		}
+		if token != "" {
+			r = r.WithContext(context.WithValue(r.Context(), callerKey{}, TokenFingerprint(token)))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// TokenFingerprint identifies an API token without revealing it: the first
+// 8 hex digits of its sha256, as in `printf %s $TOKEN | sha256sum | cut -c1-8`
+func TokenFingerprint(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:4])
+}
+
+// CallerFingerprint returns the fingerprint of the token of the API call, if
+// it was made with one
+func CallerFingerprint(ctx context.Context) (string, bool) {
+	fp, ok := ctx.Value(callerKey{}).(string)
+	return fp, ok
+}
+
+// Caller returns who makes an API call, as the fingerprint of its token and
+// the highest permission it grants, or "local" for the calls the node makes
+// itself
+func Caller(ctx context.Context) string {
+	fp, ok := ctx.Value(callerKey{}).(string)
+	if !ok {
+		return "local"
+	}
+	perm := PermRead
+	for _, p := range []auth.Permission{PermAdmin, PermSign, PermWrite} {
+		if auth.HasPerm(ctx, DefaultPerms, p) {
+			perm = p
+			break
+		}
+	}
+	return fmt.Sprintf("token %s (%s)", fp, perm)
+}
@@ -0,0 +1,371 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// ProjectableMethods are the heavy read methods ProjectedCall can return a
+// few fields of the result of
+var ProjectableMethods = map[string]struct{}{
+	"ChainGetBlockMessages":   {},
+	"ChainGetParentMessages":  {},
+	"ChainGetParentReceipts":  {},
+	"MpoolPending":            {},
+	"MsigGetPending":          {},
+	"StateMarketDeals":        {},
+	"StateMarketParticipants": {},
+	"StateMarketStorageDeal":  {},
+	"StateMinerActiveSectors": {},
+	"StateMinerDeadlines":     {},
+	"StateMinerInfo":          {},
+	"StateMinerPartitions":    {},
+	"StateMinerSectors":       {},
+	"StateSectorGetInfo":      {},
+}
+
+// ProjectedCaller is an API with ProjectedCall
+//
+//stability:experimental
+type ProjectedCaller interface {
+	ProjectedCall(ctx context.Context, method string, params []json.RawMessage, fields []string) (json.RawMessage, error)
+}
+
+// CallProjected calls a method of impl with the JSON params, and returns the
+// fields of its result. The method must be one of the ProjectableMethods on
+// the API interface iface, e.g. (*FullNode)(nil), so that impl methods which
+// aren't part of the API served can't be called. The fields are checked
+// before the method is called.
+func CallProjected(ctx context.Context, iface interface{}, impl interface{}, method string, params []json.RawMessage, fields []string) (json.RawMessage, error) {
+	if _, ok := ProjectableMethods[method]; !ok {
+		return nil, xerrors.Errorf("method %s can't be projected, projectable methods: %s", method, strings.Join(projectableMethods(), ", "))
+	}
+	if _, ok := reflect.TypeOf(iface).Elem().MethodByName(method); !ok {
+		return nil, xerrors.Errorf("method %s isn't served by this API", method)
+	}
+	m := reflect.ValueOf(impl).MethodByName(method)
+	if !m.IsValid() {
+		return nil, xerrors.Errorf("method %s isn't implemented", method)
+	}
+
+	mt := m.Type()
+	if mt.NumOut() != 2 {
+		return nil, xerrors.Errorf("method %s has no result to project", method)
+	}
+	if mt.NumIn() != len(params)+1 {
+		return nil, xerrors.Errorf("method %s takes %d params, got %d", method, mt.NumIn()-1, len(params))
+	}
+	proj, err := parseProjection(mt.Out(0), fields)
+	if err != nil {
+		return nil, xerrors.Errorf("projecting %s: %w", method, err)
+	}
+
+	args := []reflect.Value{reflect.ValueOf(ctx)}
+	for i, p := range params {
+		arg := reflect.New(mt.In(i + 1))
+		if err := json.Unmarshal(p, arg.Interface()); err != nil {
+			return nil, xerrors.Errorf("decoding param %d of %s: %w", i, method, err)
+		}
+		args = append(args, arg.Elem())
+	}
+
+	out := m.Call(args)
+	if err, _ := out[1].Interface().(error); err != nil {
+		return nil, err
+	}
+	return proj.project(out[0].Interface(), mt.Out(0))
+}
+
+// Project returns the JSON of the fields of v. A field is a path of JSON
+// field names separated by dots, as 'Info.SealProof'. Fields of slices, arrays
+// and map values are those of their elements, so projecting a list of
+// sectors to 'SectorNumber' keeps the number of every sector.
+func Project(v interface{}, fields []string) (json.RawMessage, error) {
+	t := reflect.TypeOf(v)
+	proj, err := parseProjection(t, fields)
+	if err != nil {
+		return nil, err
+	}
+	return proj.project(v, t)
+}
+
+// CallWithProjection calls the method through ProjectedCall and decodes the
+// fields of its result into out, usually a pointer to the result type of the
+// method, leaving the other fields zero:
+//
+//	var sectors []*miner.SectorOnChainInfo
+//	err := api.CallWithProjection(ctx, node, &sectors, "StateMinerSectors",
+//		[]string{"SectorNumber", "Expiration"}, maddr, nil, types.EmptyTSK)
+func CallWithProjection(ctx context.Context, c ProjectedCaller, out interface{}, method string, fields []string, params ...interface{}) error {
+	raw := make([]json.RawMessage, len(params))
+	for i, p := range params {
+		b, err := json.Marshal(p)
+		if err != nil {
+			return xerrors.Errorf("encoding param %d of %s: %w", i, method, err)
+		}
+		raw[i] = b
+	}
+
+	res, err := c.ProjectedCall(ctx, method, raw, fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(res, out)
+}
+
+// projection is a tree of the fields projected, a nil subtree keeping the
+// whole field
+type projection map[string]projection
+
+var jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+func parseProjection(t reflect.Type, fields []string) (projection, error) {
+	if len(fields) == 0 {
+		return nil, xerrors.Errorf("no fields to project")
+	}
+
+	proj := projection{}
+	for _, f := range fields {
+		segs := strings.Split(f, ".")
+		if err := checkField(t, segs, ""); err != nil {
+			return nil, err
+		}
+
+		p := proj
+		for i, seg := range segs {
+			sub, ok := p[seg]
+			if ok && sub == nil {
+				// the whole field is already projected
+				break
+			}
+			if i == len(segs)-1 {
+				p[seg] = nil
+				break
+			}
+			if !ok {
+				sub = projection{}
+				p[seg] = sub
+			}
+			p = sub
+		}
+	}
+	return proj, nil
+}
+
+// checkField checks the path of a field exists in type t. Fields of types
+// with their own JSON encoding are only known once encoded, so they're
+// checked when projecting.
+func checkField(t reflect.Type, path []string, parent string) error {
+	if len(path) == 0 {
+		return nil
+	}
+	fields, known := jsonFields(t)
+	if !known {
+		return nil
+	}
+
+	ft, ok := fields[path[0]]
+	if !ok {
+		return unknownField(path[0], parent, fieldNames(fields))
+	}
+	return checkField(ft, path[1:], joinField(parent, path[0]))
+}
+
+// jsonFields returns the JSON fields of the elements of t, and whether they
+// are known from the type
+func jsonFields(t reflect.Type) (map[string]reflect.Type, bool) {
+	t = elemType(t)
+	if t == nil || customJSON(t) {
+		return nil, false
+	}
+	if t.Kind() != reflect.Struct {
+		return map[string]reflect.Type{}, true
+	}
+
+	out := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := f.Name
+		if tag := f.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if n := strings.Split(tag, ",")[0]; n != "" {
+				name = n
+			}
+		}
+		if f.Anonymous && f.Tag.Get("json") == "" {
+			if embedded, known := jsonFields(f.Type); known {
+				for n, et := range embedded {
+					out[n] = et
+				}
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		out[name] = f.Type
+	}
+	return out, true
+}
+
+// customJSON returns whether values of t have their own JSON encoding, or
+// any encoding for interfaces
+func customJSON(t reflect.Type) bool {
+	return t.Kind() == reflect.Interface || t.Implements(jsonMarshaler) || reflect.PtrTo(t).Implements(jsonMarshaler)
+}
+
+// elemType returns the type of the elements of slices, arrays, maps and
+// pointers, byte slices being encoded as strings
+func elemType(t reflect.Type) reflect.Type {
+	for t != nil {
+		switch t.Kind() {
+		case reflect.Ptr:
+			t = t.Elem()
+		case reflect.Slice, reflect.Array:
+			if t.Elem().Kind() == reflect.Uint8 {
+				return reflect.TypeOf("")
+			}
+			t = t.Elem()
+		case reflect.Map:
+			t = t.Elem()
+		default:
+			return t
+		}
+	}
+	return nil
+}
+
+func (p projection) project(v interface{}, t reflect.Type) (json.RawMessage, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, xerrors.Errorf("encoding result: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var tree interface{}
+	if err := dec.Decode(&tree); err != nil {
+		return nil, xerrors.Errorf("decoding result: %w", err)
+	}
+
+	tree, err = p.apply(tree, t, "")
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(tree)
+}
+
+func (p projection) apply(v interface{}, t reflect.Type, parent string) (interface{}, error) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t != nil && customJSON(t) {
+		t = nil
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		var et reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			et = t.Elem()
+		}
+		for i := range v {
+			pv, err := p.apply(v[i], et, parent)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = pv
+		}
+		return v, nil
+	case map[string]interface{}:
+		if t != nil && t.Kind() == reflect.Map {
+			for k := range v {
+				pv, err := p.apply(v[k], t.Elem(), parent)
+				if err != nil {
+					return nil, err
+				}
+				v[k] = pv
+			}
+			return v, nil
+		}
+
+		fields, _ := jsonFields(t)
+		out := map[string]interface{}{}
+		for name, sub := range p {
+			fv, ok := v[name]
+			if !ok {
+				if t == nil {
+					keys := make([]string, 0, len(v))
+					for k := range v {
+						keys = append(keys, k)
+					}
+					sort.Strings(keys)
+					return nil, unknownField(name, parent, keys)
+				}
+				// omitted when empty
+				continue
+			}
+			if sub == nil {
+				out[name] = fv
+				continue
+			}
+			pv, err := sub.apply(fv, fields[name], joinField(parent, name))
+			if err != nil {
+				return nil, err
+			}
+			out[name] = pv
+		}
+		return out, nil
+	default:
+		if parent == "" {
+			return nil, xerrors.Errorf("the result has no fields")
+		}
+		return nil, xerrors.Errorf("field %s has no fields", parent)
+	}
+}
+
+func unknownField(name, parent string, available []string) error {
+	of := "the result"
+	if parent != "" {
+		of = parent
+	}
+	if len(available) == 0 {
+		return xerrors.Errorf("unknown field %q of %s, which has no fields", name, of)
+	}
+	return xerrors.Errorf("unknown field %q of %s, available fields: %s", name, of, strings.Join(available, ", "))
+}
+
+func fieldNames(fields map[string]reflect.Type) []string {
+	out := make([]string, 0, len(fields))
+	for n := range fields {
+		out = append(out, n)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func joinField(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+func projectableMethods() []string {
+	out := make([]string, 0, len(ProjectableMethods))
+	for m := range ProjectableMethods {
+		out = append(out, m)
+	}
+	sort.Strings(out)
+	return out
+}
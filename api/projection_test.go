@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/lotus/chain/actors/builtin/market"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/miner"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+func testProjectedSectors(n int) []*miner.SectorOnChainInfo {
+	sealed, err := cid.Decode("bagboea4b5abcatlxechwbp7kjpjguna6r6q7ejrhe6mdp3lf34pmswn27pkkiekz")
+	if err != nil {
+		panic(err)
+	}
+
+	out := make([]*miner.SectorOnChainInfo, n)
+	for i := range out {
+		out[i] = &miner.SectorOnChainInfo{
+			SectorNumber:          abi.SectorNumber(i),
+			SealProof:             abi.RegisteredSealProof_StackedDrg32GiBV1_1,
+			SealedCID:             sealed,
+			DealIDs:               []abi.DealID{abi.DealID(i), abi.DealID(i + 1)},
+			Activation:            abi.ChainEpoch(1000 + i),
+			Expiration:            abi.ChainEpoch(1_500_000 + i),
+			DealWeight:            big.NewInt(1 << 40),
+			VerifiedDealWeight:    big.NewInt(1 << 41),
+			InitialPledge:         big.NewInt(200_000_000_000_000_000),
+			ExpectedDayReward:     big.NewInt(1_000_000_000_000_000),
+			ExpectedStoragePledge: big.NewInt(20_000_000_000_000_000),
+		}
+	}
+	return out
+}
+
+func TestProject(t *testing.T) {
+	sectors := testProjectedSectors(2)
+
+	b, err := Project(sectors, []string{"SectorNumber", "Expiration"})
+	require.NoError(t, err)
+	require.JSONEq(t, `[{"SectorNumber":0,"Expiration":1500000},{"SectorNumber":1,"Expiration":1500001}]`, string(b))
+
+	_, err = Project(sectors, []string{"SectorNumber", "Expiry"})
+	require.EqualError(t, err, `unknown field "Expiry" of the result, available fields: Activation, DealIDs, DealWeight, ExpectedDayReward, ExpectedStoragePledge, Expiration, InitialPledge, SealProof, SealedCID, SectorNumber, VerifiedDealWeight`)
+	_, err = Project(sectors, []string{"SectorNumber.Value"})
+	require.EqualError(t, err, `unknown field "Value" of SectorNumber, which has no fields`)
+	_, err = Project(sectors, nil)
+	require.Error(t, err)
+
+	// map values are projected, fields of nested structs by path
+	provider, err := address.NewIDAddress(1000)
+	require.NoError(t, err)
+	deals := map[string]MarketDeal{
+		"5": {Proposal: market.DealProposal{PieceSize: 2048, Provider: provider}, State: market.DealState{SectorStartEpoch: 10}},
+	}
+	b, err = Project(deals, []string{"Proposal.PieceSize", "Proposal.Provider", "State"})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"5":{"Proposal":{"PieceSize":2048,"Provider":"`+provider.String()+`"},"State":{"SectorStartEpoch":10,"LastUpdatedEpoch":0,"SlashEpoch":0}}}`, string(b))
+
+	// the fields of types with their own encoding are checked once encoded
+	msgs := []*types.Message{{To: provider, From: provider, Method: 5, Value: big.Zero(), GasFeeCap: big.Zero(), GasPremium: big.Zero()}}
+	b, err = Project(msgs, []string{"To", "Method"})
+	require.NoError(t, err)
+	require.JSONEq(t, `[{"To":"`+provider.String()+`","Method":5}]`, string(b))
+	_, err = Project(msgs, []string{"Nonce", "Recipient"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `unknown field "Recipient" of the result, available fields: CID, From, GasFeeCap`)
+}
+
+type testProjectionAPI interface {
+	StateMinerSectors(context.Context, address.Address, *bitfield.BitField, types.TipSetKey) ([]*miner.SectorOnChainInfo, error)
+}
+
+type testProjectionNode struct {
+	sectors []*miner.SectorOnChainInfo
+}
+
+func (n *testProjectionNode) StateMinerSectors(ctx context.Context, maddr address.Address, filter *bitfield.BitField, tsk types.TipSetKey) ([]*miner.SectorOnChainInfo, error) {
+	return n.sectors, nil
+}
+
+// StateSectorGetInfo isn't part of testProjectionAPI
+func (n *testProjectionNode) StateSectorGetInfo(ctx context.Context, maddr address.Address, sn abi.SectorNumber, tsk types.TipSetKey) (*miner.SectorOnChainInfo, error) {
+	return n.sectors[sn], nil
+}
+
+func (n *testProjectionNode) ProjectedCall(ctx context.Context, method string, params []json.RawMessage, fields []string) (json.RawMessage, error) {
+	return CallProjected(ctx, (*testProjectionAPI)(nil), n, method, params, fields)
+}
+
+func TestCallWithProjection(t *testing.T) {
+	ctx := context.Background()
+	node := &testProjectionNode{sectors: testProjectedSectors(3)}
+	maddr, err := address.NewIDAddress(1000)
+	require.NoError(t, err)
+
+	var sectors []*miner.SectorOnChainInfo
+	require.NoError(t, CallWithProjection(ctx, node, &sectors, "StateMinerSectors", []string{"SectorNumber", "Expiration"}, maddr, nil, types.EmptyTSK))
+	require.Len(t, sectors, 3)
+	require.Equal(t, abi.SectorNumber(2), sectors[2].SectorNumber)
+	require.Equal(t, abi.ChainEpoch(1_500_002), sectors[2].Expiration)
+	require.Equal(t, abi.ChainEpoch(0), sectors[2].Activation)
+
+	var sector miner.SectorOnChainInfo
+	require.Error(t, CallWithProjection(ctx, node, &sector, "StateSectorGetInfo", []string{"Expiration"}, maddr, 1, types.EmptyTSK))
+	require.Error(t, CallWithProjection(ctx, node, &sectors, "WalletSign", []string{"Data"}, maddr, []byte{}))
+	require.Error(t, CallWithProjection(ctx, node, &sectors, "StateMinerSectors", []string{"SectorNumber"}, maddr))
+	require.Error(t, CallWithProjection(ctx, node, &sectors, "StateMinerSectors", []string{"Number"}, maddr, nil, types.EmptyTSK))
+}
+
+// BenchmarkProjectMinerSectors shows the size of a StateMinerSectors result
+// for 100k sectors, whole and projected to the sector numbers and expirations
+func BenchmarkProjectMinerSectors(b *testing.B) {
+	sectors := testProjectedSectors(100_000)
+	fields := []string{"SectorNumber", "Expiration"}
+
+	full, err := json.Marshal(sectors)
+	require.NoError(b, err)
+
+	var projected json.RawMessage
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		projected, err = Project(sectors, fields)
+		require.NoError(b, err)
+	}
+	b.StopTimer()
+
+	b.ReportMetric(float64(len(full)), "full-bytes")
+	b.ReportMetric(float64(len(projected)), "projected-bytes")
+	b.ReportMetric(100*float64(len(projected))/float64(len(full)), "%size")
+}
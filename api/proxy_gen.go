@@ -4,6 +4,7 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"time"
 
@@ -37,14 +38,14 @@ import (
 	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/peer"
 	protocol "github.com/libp2p/go-libp2p-core/protocol"
-	xerrors "golang.org/x/xerrors"
+	"golang.org/x/xerrors"
 )
 
 type ChainIOStruct struct {
 	Internal struct {
-		ChainHasObj func(p0 context.Context, p1 cid.Cid) (bool, error) ``
+		ChainHasObj func(p0 context.Context, p1 cid.Cid) (bool, error) `stability:"stable"`
 
-		ChainReadObj func(p0 context.Context, p1 cid.Cid) ([]byte, error) ``
+		ChainReadObj func(p0 context.Context, p1 cid.Cid) ([]byte, error) `stability:"stable"`
 	}
 }
 
@@ -53,57 +54,71 @@ type ChainIOStub struct {
 
 type CommonStruct struct {
 	Internal struct {
-		AuthNew func(p0 context.Context, p1 []auth.Permission) ([]byte, error) `perm:"admin"`
+		AuthNew func(p0 context.Context, p1 []auth.Permission) ([]byte, error) `perm:"admin" stability:"stable"`
 
-		AuthVerify func(p0 context.Context, p1 string) ([]auth.Permission, error) `perm:"read"`
+		AuthVerify func(p0 context.Context, p1 string) ([]auth.Permission, error) `perm:"read" stability:"stable"`
 
-		Closing func(p0 context.Context) (<-chan struct{}, error) `perm:"read"`
+		Closing func(p0 context.Context) (<-chan struct{}, error) `perm:"read" stability:"stable"`
 
-		Discover func(p0 context.Context) (apitypes.OpenRPCDocument, error) `perm:"read"`
+		ConfigHistory func(p0 context.Context) ([]ConfigSnapshot, error) `perm:"admin" stability:"experimental"`
 
-		ID func(p0 context.Context) (peer.ID, error) `perm:"read"`
+		ConfigLoaded func(p0 context.Context) ([]byte, error) `perm:"admin" stability:"experimental"`
 
-		LogList func(p0 context.Context) ([]string, error) `perm:"write"`
+		Discover func(p0 context.Context) (apitypes.OpenRPCDocument, error) `perm:"read" stability:"stable"`
 
-		LogSetLevel func(p0 context.Context, p1 string, p2 string) error `perm:"write"`
+		ID func(p0 context.Context) (peer.ID, error) `perm:"read" stability:"stable"`
 
-		NetAddrsListen func(p0 context.Context) (peer.AddrInfo, error) `perm:"read"`
+		LogLevels func(p0 context.Context) ([]LogSystem, error) `perm:"write" stability:"experimental"`
 
-		NetAgentVersion func(p0 context.Context, p1 peer.ID) (string, error) `perm:"read"`
+		LogList func(p0 context.Context) ([]string, error) `perm:"write" stability:"stable"`
 
-		NetAutoNatStatus func(p0 context.Context) (NatInfo, error) `perm:"read"`
+		LogSetLevel func(p0 context.Context, p1 string, p2 string) error `perm:"write" stability:"stable"`
 
-		NetBandwidthStats func(p0 context.Context) (metrics.Stats, error) `perm:"read"`
+		LogSetLevels func(p0 context.Context, p1 string, p2 string, p3 time.Duration) ([]string, error) `perm:"write" stability:"experimental"`
 
-		NetBandwidthStatsByPeer func(p0 context.Context) (map[string]metrics.Stats, error) `perm:"read"`
+		NetAddrsListen func(p0 context.Context) (peer.AddrInfo, error) `perm:"read" stability:"stable"`
 
-		NetBandwidthStatsByProtocol func(p0 context.Context) (map[protocol.ID]metrics.Stats, error) `perm:"read"`
+		NetAgentVersion func(p0 context.Context, p1 peer.ID) (string, error) `perm:"read" stability:"stable"`
 
-		NetBlockAdd func(p0 context.Context, p1 NetBlockList) error `perm:"admin"`
+		NetAutoNatStatus func(p0 context.Context) (NatInfo, error) `perm:"read" stability:"stable"`
 
-		NetBlockList func(p0 context.Context) (NetBlockList, error) `perm:"read"`
+		NetBandwidthStats func(p0 context.Context) (metrics.Stats, error) `perm:"read" stability:"stable"`
 
-		NetBlockRemove func(p0 context.Context, p1 NetBlockList) error `perm:"admin"`
+		NetBandwidthStatsByPeer func(p0 context.Context) (map[string]metrics.Stats, error) `perm:"read" stability:"stable"`
 
-		NetConnect func(p0 context.Context, p1 peer.AddrInfo) error `perm:"write"`
+		NetBandwidthStatsByProtocol func(p0 context.Context) (map[protocol.ID]metrics.Stats, error) `perm:"read" stability:"stable"`
 
-		NetConnectedness func(p0 context.Context, p1 peer.ID) (network.Connectedness, error) `perm:"read"`
+		NetBlockAdd func(p0 context.Context, p1 NetBlockList) error `perm:"admin" stability:"stable"`
 
-		NetDisconnect func(p0 context.Context, p1 peer.ID) error `perm:"write"`
+		NetBlockList func(p0 context.Context) (NetBlockList, error) `perm:"read" stability:"stable"`
 
-		NetFindPeer func(p0 context.Context, p1 peer.ID) (peer.AddrInfo, error) `perm:"read"`
+		NetBlockRemove func(p0 context.Context, p1 NetBlockList) error `perm:"admin" stability:"stable"`
 
-		NetPeerInfo func(p0 context.Context, p1 peer.ID) (*ExtendedPeerInfo, error) `perm:"read"`
+		NetConnect func(p0 context.Context, p1 peer.AddrInfo) error `perm:"write" stability:"stable"`
 
-		NetPeers func(p0 context.Context) ([]peer.AddrInfo, error) `perm:"read"`
+		NetConnectedness func(p0 context.Context, p1 peer.ID) (network.Connectedness, error) `perm:"read" stability:"stable"`
 
-		NetPubsubScores func(p0 context.Context) ([]PubsubScore, error) `perm:"read"`
+		NetDialAddrs func(p0 context.Context, p1 peer.AddrInfo) ([]DialResult, error) `perm:"write" stability:"experimental"`
 
-		Session func(p0 context.Context) (uuid.UUID, error) `perm:"read"`
+		NetDisconnect func(p0 context.Context, p1 peer.ID) error `perm:"write" stability:"stable"`
 
-		Shutdown func(p0 context.Context) error `perm:"admin"`
+		NetFindPeer func(p0 context.Context, p1 peer.ID) (peer.AddrInfo, error) `perm:"read" stability:"stable"`
 
-		Version func(p0 context.Context) (APIVersion, error) `perm:"read"`
+		NetFindPeerDHT func(p0 context.Context, p1 peer.ID) (peer.AddrInfo, error) `perm:"read" stability:"experimental"`
+
+		NetFindProviders func(p0 context.Context, p1 cid.Cid, p2 int) ([]ProviderRecord, error) `perm:"read" stability:"experimental"`
+
+		NetPeerInfo func(p0 context.Context, p1 peer.ID) (*ExtendedPeerInfo, error) `perm:"read" stability:"stable"`
+
+		NetPeers func(p0 context.Context) ([]peer.AddrInfo, error) `perm:"read" stability:"stable"`
+
+		NetPubsubScores func(p0 context.Context) ([]PubsubScore, error) `perm:"read" stability:"stable"`
+
+		Session func(p0 context.Context) (uuid.UUID, error) `perm:"read" stability:"stable"`
+
+		Shutdown func(p0 context.Context) error `perm:"admin" stability:"stable"`
+
+		Version func(p0 context.Context) (APIVersion, error) `perm:"read" stability:"stable"`
 	}
 }
 
@@ -114,345 +129,431 @@ type FullNodeStruct struct {
 	CommonStruct
 
 	Internal struct {
-		BeaconGetEntry func(p0 context.Context, p1 abi.ChainEpoch) (*types.BeaconEntry, error) `perm:"read"`
+		BeaconGetEntry func(p0 context.Context, p1 abi.ChainEpoch) (*types.BeaconEntry, error) `perm:"read" stability:"stable"`
+
+		ChainDeleteObj func(p0 context.Context, p1 cid.Cid) error `perm:"admin" stability:"stable"`
+
+		ChainExport func(p0 context.Context, p1 abi.ChainEpoch, p2 bool, p3 types.TipSetKey) (<-chan []byte, error) `perm:"read" stability:"stable"`
+
+		ChainFollowConsumers func(p0 context.Context) ([]ChainFollowConsumer, error) `perm:"read" stability:"experimental"`
+
+		ChainGetBlock func(p0 context.Context, p1 cid.Cid) (*types.BlockHeader, error) `perm:"read" stability:"stable"`
+
+		ChainGetBlockMessages func(p0 context.Context, p1 cid.Cid) (*BlockMessages, error) `perm:"read" stability:"stable"`
+
+		ChainGetGenesis func(p0 context.Context) (*types.TipSet, error) `perm:"read" stability:"stable"`
+
+		ChainGetMessage func(p0 context.Context, p1 cid.Cid) (*types.Message, error) `perm:"read" stability:"stable"`
+
+		ChainGetNode func(p0 context.Context, p1 string) (*IpldObject, error) `perm:"read" stability:"stable"`
+
+		ChainGetParentMessages func(p0 context.Context, p1 cid.Cid) ([]Message, error) `perm:"read" stability:"stable"`
+
+		ChainGetParentReceipts func(p0 context.Context, p1 cid.Cid) ([]*types.MessageReceipt, error) `perm:"read" stability:"stable"`
+
+		ChainGetPath func(p0 context.Context, p1 types.TipSetKey, p2 types.TipSetKey) ([]*HeadChange, error) `perm:"read" stability:"stable"`
+
+		ChainGetRandomnessFromBeacon func(p0 context.Context, p1 types.TipSetKey, p2 crypto.DomainSeparationTag, p3 abi.ChainEpoch, p4 []byte) (abi.Randomness, error) `perm:"read" stability:"stable"`
+
+		ChainGetRandomnessFromTickets func(p0 context.Context, p1 types.TipSetKey, p2 crypto.DomainSeparationTag, p3 abi.ChainEpoch, p4 []byte) (abi.Randomness, error) `perm:"read" stability:"stable"`
+
+		ChainGetTipSet func(p0 context.Context, p1 types.TipSetKey) (*types.TipSet, error) `perm:"read" stability:"stable"`
+
+		ChainGetTipSetByHeight func(p0 context.Context, p1 abi.ChainEpoch, p2 types.TipSetKey) (*types.TipSet, error) `perm:"read" stability:"stable"`
+
+		ChainGetTipSetByHeightPolicy func(p0 context.Context, p1 abi.ChainEpoch, p2 NullRoundPolicy, p3 types.TipSetKey) (*types.TipSet, error) `perm:"read" stability:"experimental"`
+
+		ChainHasObj func(p0 context.Context, p1 cid.Cid) (bool, error) `perm:"read" stability:"stable"`
+
+		ChainHead func(p0 context.Context) (*types.TipSet, error) `perm:"read" stability:"stable"`
+
+		ChainMetricsQuery func(p0 context.Context, p1 string, p2 abi.ChainEpoch, p3 abi.ChainEpoch, p4 abi.ChainEpoch) ([]MetricsPoint, error) `perm:"read" stability:"experimental"`
+
+		ChainMetricsSeries func(p0 context.Context) ([]MetricsSeries, error) `perm:"read" stability:"experimental"`
+
+		ChainNotify func(p0 context.Context) (<-chan []*HeadChange, error) `perm:"read" stability:"stable"`
+
+		ChainPinHead func(p0 context.Context, p1 time.Duration) (PinnedHead, error) `perm:"read" stability:"experimental"`
+
+		ChainReadObj func(p0 context.Context, p1 cid.Cid) ([]byte, error) `perm:"read" stability:"stable"`
+
+		ChainSetHead func(p0 context.Context, p1 types.TipSetKey) error `perm:"admin" stability:"stable"`
+
+		ChainStatObj func(p0 context.Context, p1 cid.Cid, p2 cid.Cid) (ObjStat, error) `perm:"read" stability:"stable"`
+
+		ChainTipSetWeight func(p0 context.Context, p1 types.TipSetKey) (types.BigInt, error) `perm:"read" stability:"stable"`
+
+		ChainUnpinHead func(p0 context.Context, p1 string) error `perm:"read" stability:"experimental"`
+
+		ClientCalcCommP func(p0 context.Context, p1 string) (*CommPRet, error) `perm:"write" stability:"stable"`
+
+		ClientCancelDataTransfer func(p0 context.Context, p1 datatransfer.TransferID, p2 peer.ID, p3 bool) error `perm:"write" stability:"stable"`
+
+		ClientCancelRetrievalDeal func(p0 context.Context, p1 retrievalmarket.DealID) error `perm:"write" stability:"stable"`
+
+		ClientDataTransferUpdates func(p0 context.Context) (<-chan DataTransferChannel, error) `perm:"write" stability:"stable"`
+
+		ClientDealHealth func(p0 context.Context) ([]DealHealth, error) `perm:"write" stability:"experimental"`
+
+		ClientDealHealthUpdates func(p0 context.Context) (<-chan DealHealth, error) `perm:"write" stability:"experimental"`
+
+		ClientDealPieceCID func(p0 context.Context, p1 cid.Cid) (DataCIDSize, error) `perm:"read" stability:"stable"`
+
+		ClientDealSealingStatus func(p0 context.Context, p1 cid.Cid) (*DealSealingStatus, error) `perm:"read" stability:"experimental"`
+
+		ClientDealSize func(p0 context.Context, p1 cid.Cid) (DataSize, error) `perm:"read" stability:"stable"`
+
+		ClientFindData func(p0 context.Context, p1 cid.Cid, p2 *cid.Cid) ([]QueryOffer, error) `perm:"read" stability:"stable"`
 
-		ChainDeleteObj func(p0 context.Context, p1 cid.Cid) error `perm:"admin"`
+		ClientGenCar func(p0 context.Context, p1 FileRef, p2 string) error `perm:"write" stability:"stable"`
 
-		ChainExport func(p0 context.Context, p1 abi.ChainEpoch, p2 bool, p3 types.TipSetKey) (<-chan []byte, error) `perm:"read"`
+		ClientGetDealInfo func(p0 context.Context, p1 cid.Cid) (*DealInfo, error) `perm:"read" stability:"stable"`
 
-		ChainGetBlock func(p0 context.Context, p1 cid.Cid) (*types.BlockHeader, error) `perm:"read"`
+		ClientGetDealStatus func(p0 context.Context, p1 uint64) (string, error) `perm:"read" stability:"stable"`
 
-		ChainGetBlockMessages func(p0 context.Context, p1 cid.Cid) (*BlockMessages, error) `perm:"read"`
+		ClientGetDealUpdates func(p0 context.Context) (<-chan DealInfo, error) `perm:"write" stability:"stable"`
 
-		ChainGetGenesis func(p0 context.Context) (*types.TipSet, error) `perm:"read"`
+		ClientHasLocal func(p0 context.Context, p1 cid.Cid) (bool, error) `perm:"write" stability:"stable"`
 
-		ChainGetMessage func(p0 context.Context, p1 cid.Cid) (*types.Message, error) `perm:"read"`
+		ClientImport func(p0 context.Context, p1 FileRef) (*ImportRes, error) `perm:"admin" stability:"stable"`
 
-		ChainGetNode func(p0 context.Context, p1 string) (*IpldObject, error) `perm:"read"`
+		ClientListDataTransfers func(p0 context.Context) ([]DataTransferChannel, error) `perm:"write" stability:"stable"`
 
-		ChainGetParentMessages func(p0 context.Context, p1 cid.Cid) ([]Message, error) `perm:"read"`
+		ClientListDeals func(p0 context.Context) ([]DealInfo, error) `perm:"write" stability:"stable"`
 
-		ChainGetParentReceipts func(p0 context.Context, p1 cid.Cid) ([]*types.MessageReceipt, error) `perm:"read"`
+		ClientListImports func(p0 context.Context) ([]Import, error) `perm:"write" stability:"stable"`
 
-		ChainGetPath func(p0 context.Context, p1 types.TipSetKey, p2 types.TipSetKey) ([]*HeadChange, error) `perm:"read"`
+		ClientMinerQueryOffer func(p0 context.Context, p1 address.Address, p2 cid.Cid, p3 *cid.Cid) (QueryOffer, error) `perm:"read" stability:"stable"`
 
-		ChainGetRandomnessFromBeacon func(p0 context.Context, p1 types.TipSetKey, p2 crypto.DomainSeparationTag, p3 abi.ChainEpoch, p4 []byte) (abi.Randomness, error) `perm:"read"`
+		ClientQueryAsk func(p0 context.Context, p1 peer.ID, p2 address.Address) (*storagemarket.StorageAsk, error) `perm:"read" stability:"stable"`
 
-		ChainGetRandomnessFromTickets func(p0 context.Context, p1 types.TipSetKey, p2 crypto.DomainSeparationTag, p3 abi.ChainEpoch, p4 []byte) (abi.Randomness, error) `perm:"read"`
+		ClientRemoveImport func(p0 context.Context, p1 multistore.StoreID) error `perm:"admin" stability:"stable"`
 
-		ChainGetTipSet func(p0 context.Context, p1 types.TipSetKey) (*types.TipSet, error) `perm:"read"`
+		ClientRestartDataTransfer func(p0 context.Context, p1 datatransfer.TransferID, p2 peer.ID, p3 bool) error `perm:"write" stability:"stable"`
 
-		ChainGetTipSetByHeight func(p0 context.Context, p1 abi.ChainEpoch, p2 types.TipSetKey) (*types.TipSet, error) `perm:"read"`
+		ClientRetrievalHistory func(p0 context.Context, p1 address.Address, p2 int) ([]RetrievalRecord, error) `perm:"write" stability:"experimental"`
 
-		ChainHasObj func(p0 context.Context, p1 cid.Cid) (bool, error) `perm:"read"`
+		ClientRetrievalStats func(p0 context.Context, p1 address.Address) ([]RetrievalProviderStats, error) `perm:"write" stability:"experimental"`
 
-		ChainHead func(p0 context.Context) (*types.TipSet, error) `perm:"read"`
+		ClientRetrievalStatsImport func(p0 context.Context, p1 []RetrievalRecord) (int, error) `perm:"write" stability:"experimental"`
 
-		ChainNotify func(p0 context.Context) (<-chan []*HeadChange, error) `perm:"read"`
+		ClientRetrieve func(p0 context.Context, p1 RetrievalOrder, p2 *FileRef) error `perm:"admin" stability:"stable"`
 
-		ChainReadObj func(p0 context.Context, p1 cid.Cid) ([]byte, error) `perm:"read"`
+		ClientRetrieveTryRestartInsufficientFunds func(p0 context.Context, p1 address.Address) error `perm:"write" stability:"stable"`
 
-		ChainSetHead func(p0 context.Context, p1 types.TipSetKey) error `perm:"admin"`
+		ClientRetrieveWithEvents func(p0 context.Context, p1 RetrievalOrder, p2 *FileRef) (<-chan marketevents.RetrievalEvent, error) `perm:"admin" stability:"stable"`
 
-		ChainStatObj func(p0 context.Context, p1 cid.Cid, p2 cid.Cid) (ObjStat, error) `perm:"read"`
+		ClientStartDeal func(p0 context.Context, p1 *StartDealParams) (*cid.Cid, error) `perm:"admin" stability:"stable"`
 
-		ChainTipSetWeight func(p0 context.Context, p1 types.TipSetKey) (types.BigInt, error) `perm:"read"`
+		CreateBackup func(p0 context.Context, p1 string) error `perm:"admin" stability:"stable"`
 
-		ClientCalcCommP func(p0 context.Context, p1 string) (*CommPRet, error) `perm:"write"`
+		GasEstimateFeeCap func(p0 context.Context, p1 *types.Message, p2 int64, p3 types.TipSetKey) (types.BigInt, error) `perm:"read" stability:"stable"`
 
-		ClientCancelDataTransfer func(p0 context.Context, p1 datatransfer.TransferID, p2 peer.ID, p3 bool) error `perm:"write"`
+		GasEstimateGasLimit func(p0 context.Context, p1 *types.Message, p2 types.TipSetKey) (int64, error) `perm:"read" stability:"stable"`
 
-		ClientCancelRetrievalDeal func(p0 context.Context, p1 retrievalmarket.DealID) error `perm:"write"`
+		GasEstimateGasPremium func(p0 context.Context, p1 uint64, p2 address.Address, p3 int64, p4 types.TipSetKey) (types.BigInt, error) `perm:"read" stability:"stable"`
 
-		ClientDataTransferUpdates func(p0 context.Context) (<-chan DataTransferChannel, error) `perm:"write"`
+		GasEstimateMessageGas func(p0 context.Context, p1 *types.Message, p2 *MessageSendSpec, p3 types.TipSetKey) (*types.Message, error) `perm:"read" stability:"stable"`
 
-		ClientDealPieceCID func(p0 context.Context, p1 cid.Cid) (DataCIDSize, error) `perm:"read"`
+		MarketAddBalance func(p0 context.Context, p1 address.Address, p2 address.Address, p3 types.BigInt) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		ClientDealSize func(p0 context.Context, p1 cid.Cid) (DataSize, error) `perm:"read"`
+		MarketGetReserved func(p0 context.Context, p1 address.Address) (types.BigInt, error) `perm:"sign" stability:"stable"`
 
-		ClientFindData func(p0 context.Context, p1 cid.Cid, p2 *cid.Cid) ([]QueryOffer, error) `perm:"read"`
+		MarketReleaseFunds func(p0 context.Context, p1 address.Address, p2 types.BigInt) error `perm:"sign" stability:"stable"`
 
-		ClientGenCar func(p0 context.Context, p1 FileRef, p2 string) error `perm:"write"`
+		MarketReserveFunds func(p0 context.Context, p1 address.Address, p2 address.Address, p3 types.BigInt) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		ClientGetDealInfo func(p0 context.Context, p1 cid.Cid) (*DealInfo, error) `perm:"read"`
+		MarketWithdraw func(p0 context.Context, p1 address.Address, p2 address.Address, p3 types.BigInt) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		ClientGetDealStatus func(p0 context.Context, p1 uint64) (string, error) `perm:"read"`
+		Methods func(p0 context.Context) (map[string]MethodStability, error) `perm:"read" stability:"experimental"`
 
-		ClientGetDealUpdates func(p0 context.Context) (<-chan DealInfo, error) `perm:"write"`
+		MinerCreateBlock func(p0 context.Context, p1 *BlockTemplate) (*types.BlockMsg, error) `perm:"write" stability:"stable"`
 
-		ClientHasLocal func(p0 context.Context, p1 cid.Cid) (bool, error) `perm:"write"`
+		MinerGetBaseInfo func(p0 context.Context, p1 address.Address, p2 abi.ChainEpoch, p3 types.TipSetKey) (*MiningBaseInfo, error) `perm:"read" stability:"stable"`
 
-		ClientImport func(p0 context.Context, p1 FileRef) (*ImportRes, error) `perm:"admin"`
+		MpoolBatchPush func(p0 context.Context, p1 []*types.SignedMessage) ([]cid.Cid, error) `perm:"write" stability:"stable"`
 
-		ClientListDataTransfers func(p0 context.Context) ([]DataTransferChannel, error) `perm:"write"`
+		MpoolBatchPushMessage func(p0 context.Context, p1 []*types.Message, p2 *MessageSendSpec) ([]*types.SignedMessage, error) `perm:"sign" stability:"stable"`
 
-		ClientListDeals func(p0 context.Context) ([]DealInfo, error) `perm:"write"`
+		MpoolBatchPushUntrusted func(p0 context.Context, p1 []*types.SignedMessage) ([]cid.Cid, error) `perm:"write" stability:"stable"`
 
-		ClientListImports func(p0 context.Context) ([]Import, error) `perm:"write"`
+		MpoolClear func(p0 context.Context, p1 bool) error `perm:"write" stability:"stable"`
 
-		ClientMinerQueryOffer func(p0 context.Context, p1 address.Address, p2 cid.Cid, p3 *cid.Cid) (QueryOffer, error) `perm:"read"`
+		MpoolFeeHistory func(p0 context.Context) ([]FeeLevelStats, error) `perm:"read" stability:"experimental"`
 
-		ClientQueryAsk func(p0 context.Context, p1 peer.ID, p2 address.Address) (*storagemarket.StorageAsk, error) `perm:"read"`
+		MpoolFeeLevel func(p0 context.Context, p1 cid.Cid) (*FeeLevelStats, error) `perm:"read" stability:"experimental"`
 
-		ClientRemoveImport func(p0 context.Context, p1 multistore.StoreID) error `perm:"admin"`
+		MpoolGasAccuracy func(p0 context.Context) ([]GasAccuracyStats, error) `perm:"read" stability:"experimental"`
 
-		ClientRestartDataTransfer func(p0 context.Context, p1 datatransfer.TransferID, p2 peer.ID, p3 bool) error `perm:"write"`
+		MpoolGetConfig func(p0 context.Context) (*types.MpoolConfig, error) `perm:"read" stability:"stable"`
 
-		ClientRetrieve func(p0 context.Context, p1 RetrievalOrder, p2 *FileRef) error `perm:"admin"`
+		MpoolGetIdempotencyKey func(p0 context.Context, p1 string) (*IdempotencyRecord, error) `perm:"read" stability:"experimental"`
 
-		ClientRetrieveTryRestartInsufficientFunds func(p0 context.Context, p1 address.Address) error `perm:"write"`
+		MpoolGetMemo func(p0 context.Context, p1 cid.Cid) (*MessageMemo, error) `perm:"read" stability:"experimental"`
 
-		ClientRetrieveWithEvents func(p0 context.Context, p1 RetrievalOrder, p2 *FileRef) (<-chan marketevents.RetrievalEvent, error) `perm:"admin"`
+		MpoolGetNonce func(p0 context.Context, p1 address.Address) (uint64, error) `perm:"read" stability:"stable"`
 
-		ClientStartDeal func(p0 context.Context, p1 *StartDealParams) (*cid.Cid, error) `perm:"admin"`
+		MpoolGetReplacement func(p0 context.Context, p1 cid.Cid) (*MsgReplacement, error) `perm:"read" stability:"experimental"`
 
-		CreateBackup func(p0 context.Context, p1 string) error `perm:"admin"`
+		MpoolLanes func(p0 context.Context) ([]MpoolLane, error) `perm:"read" stability:"experimental"`
 
-		GasEstimateFeeCap func(p0 context.Context, p1 *types.Message, p2 int64, p3 types.TipSetKey) (types.BigInt, error) `perm:"read"`
+		MpoolListMemos func(p0 context.Context, p1 address.Address) ([]MessageMemo, error) `perm:"read" stability:"experimental"`
 
-		GasEstimateGasLimit func(p0 context.Context, p1 *types.Message, p2 types.TipSetKey) (int64, error) `perm:"read"`
+		MpoolPending func(p0 context.Context, p1 types.TipSetKey) ([]*types.SignedMessage, error) `perm:"read" stability:"stable"`
 
-		GasEstimateGasPremium func(p0 context.Context, p1 uint64, p2 address.Address, p3 int64, p4 types.TipSetKey) (types.BigInt, error) `perm:"read"`
+		MpoolPropagation func(p0 context.Context, p1 cid.Cid) (*MsgPropagation, error) `perm:"read" stability:"experimental"`
 
-		GasEstimateMessageGas func(p0 context.Context, p1 *types.Message, p2 *MessageSendSpec, p3 types.TipSetKey) (*types.Message, error) `perm:"read"`
+		MpoolPush func(p0 context.Context, p1 *types.SignedMessage) (cid.Cid, error) `perm:"write" stability:"stable"`
 
-		MarketAddBalance func(p0 context.Context, p1 address.Address, p2 address.Address, p3 types.BigInt) (cid.Cid, error) `perm:"sign"`
+		MpoolPushMessage func(p0 context.Context, p1 *types.Message, p2 *MessageSendSpec) (*types.SignedMessage, error) `perm:"sign" stability:"stable"`
 
-		MarketGetReserved func(p0 context.Context, p1 address.Address) (types.BigInt, error) `perm:"sign"`
+		MpoolPushUntrusted func(p0 context.Context, p1 *types.SignedMessage) (cid.Cid, error) `perm:"write" stability:"stable"`
 
-		MarketReleaseFunds func(p0 context.Context, p1 address.Address, p2 types.BigInt) error `perm:"sign"`
+		MpoolSelect func(p0 context.Context, p1 types.TipSetKey, p2 float64) ([]*types.SignedMessage, error) `perm:"read" stability:"stable"`
 
-		MarketReserveFunds func(p0 context.Context, p1 address.Address, p2 address.Address, p3 types.BigInt) (cid.Cid, error) `perm:"sign"`
+		MpoolSetConfig func(p0 context.Context, p1 *types.MpoolConfig) error `perm:"admin" stability:"stable"`
 
-		MarketWithdraw func(p0 context.Context, p1 address.Address, p2 address.Address, p3 types.BigInt) (cid.Cid, error) `perm:"sign"`
+		MpoolSetMemo func(p0 context.Context, p1 MessageMemo) error `perm:"write" stability:"experimental"`
 
-		MinerCreateBlock func(p0 context.Context, p1 *BlockTemplate) (*types.BlockMsg, error) `perm:"write"`
+		MpoolSub func(p0 context.Context) (<-chan MpoolUpdate, error) `perm:"read" stability:"stable"`
 
-		MinerGetBaseInfo func(p0 context.Context, p1 address.Address, p2 abi.ChainEpoch, p3 types.TipSetKey) (*MiningBaseInfo, error) `perm:"read"`
+		MsigAddApprove func(p0 context.Context, p1 address.Address, p2 address.Address, p3 uint64, p4 address.Address, p5 address.Address, p6 bool) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		MpoolBatchPush func(p0 context.Context, p1 []*types.SignedMessage) ([]cid.Cid, error) `perm:"write"`
+		MsigAddCancel func(p0 context.Context, p1 address.Address, p2 address.Address, p3 uint64, p4 address.Address, p5 bool) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		MpoolBatchPushMessage func(p0 context.Context, p1 []*types.Message, p2 *MessageSendSpec) ([]*types.SignedMessage, error) `perm:"sign"`
+		MsigAddPropose func(p0 context.Context, p1 address.Address, p2 address.Address, p3 address.Address, p4 bool) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		MpoolBatchPushUntrusted func(p0 context.Context, p1 []*types.SignedMessage) ([]cid.Cid, error) `perm:"write"`
+		MsigApprove func(p0 context.Context, p1 address.Address, p2 uint64, p3 address.Address) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		MpoolClear func(p0 context.Context, p1 bool) error `perm:"write"`
+		MsigApproveTxnHash func(p0 context.Context, p1 address.Address, p2 uint64, p3 address.Address, p4 address.Address, p5 types.BigInt, p6 address.Address, p7 uint64, p8 []byte) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		MpoolGetConfig func(p0 context.Context) (*types.MpoolConfig, error) `perm:"read"`
+		MsigCancel func(p0 context.Context, p1 address.Address, p2 uint64, p3 address.Address, p4 types.BigInt, p5 address.Address, p6 uint64, p7 []byte) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		MpoolGetNonce func(p0 context.Context, p1 address.Address) (uint64, error) `perm:"read"`
+		MsigCreate func(p0 context.Context, p1 uint64, p2 []address.Address, p3 abi.ChainEpoch, p4 types.BigInt, p5 address.Address, p6 types.BigInt) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		MpoolPending func(p0 context.Context, p1 types.TipSetKey) ([]*types.SignedMessage, error) `perm:"read"`
+		MsigGetAvailableBalance func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (types.BigInt, error) `perm:"read" stability:"stable"`
 
-		MpoolPush func(p0 context.Context, p1 *types.SignedMessage) (cid.Cid, error) `perm:"write"`
+		MsigGetPending func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) ([]*MsigTransaction, error) `perm:"read" stability:"stable"`
 
-		MpoolPushMessage func(p0 context.Context, p1 *types.Message, p2 *MessageSendSpec) (*types.SignedMessage, error) `perm:"sign"`
+		MsigGetVested func(p0 context.Context, p1 address.Address, p2 types.TipSetKey, p3 types.TipSetKey) (types.BigInt, error) `perm:"read" stability:"stable"`
 
-		MpoolPushUntrusted func(p0 context.Context, p1 *types.SignedMessage) (cid.Cid, error) `perm:"write"`
+		MsigGetVestingSchedule func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (MsigVesting, error) `perm:"read" stability:"stable"`
 
-		MpoolSelect func(p0 context.Context, p1 types.TipSetKey, p2 float64) ([]*types.SignedMessage, error) `perm:"read"`
+		MsigPropose func(p0 context.Context, p1 address.Address, p2 address.Address, p3 types.BigInt, p4 address.Address, p5 uint64, p6 []byte) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		MpoolSetConfig func(p0 context.Context, p1 *types.MpoolConfig) error `perm:"admin"`
+		MsigRemoveSigner func(p0 context.Context, p1 address.Address, p2 address.Address, p3 address.Address, p4 bool) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		MpoolSub func(p0 context.Context) (<-chan MpoolUpdate, error) `perm:"read"`
+		MsigSwapApprove func(p0 context.Context, p1 address.Address, p2 address.Address, p3 uint64, p4 address.Address, p5 address.Address, p6 address.Address) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		MsigAddApprove func(p0 context.Context, p1 address.Address, p2 address.Address, p3 uint64, p4 address.Address, p5 address.Address, p6 bool) (cid.Cid, error) `perm:"sign"`
+		MsigSwapCancel func(p0 context.Context, p1 address.Address, p2 address.Address, p3 uint64, p4 address.Address, p5 address.Address) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		MsigAddCancel func(p0 context.Context, p1 address.Address, p2 address.Address, p3 uint64, p4 address.Address, p5 bool) (cid.Cid, error) `perm:"sign"`
+		MsigSwapPropose func(p0 context.Context, p1 address.Address, p2 address.Address, p3 address.Address, p4 address.Address) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		MsigAddPropose func(p0 context.Context, p1 address.Address, p2 address.Address, p3 address.Address, p4 bool) (cid.Cid, error) `perm:"sign"`
+		PaychAllocateLane func(p0 context.Context, p1 address.Address) (uint64, error) `perm:"sign" stability:"stable"`
 
-		MsigApprove func(p0 context.Context, p1 address.Address, p2 uint64, p3 address.Address) (cid.Cid, error) `perm:"sign"`
+		PaychAvailableFunds func(p0 context.Context, p1 address.Address) (*ChannelAvailableFunds, error) `perm:"sign" stability:"stable"`
 
-		MsigApproveTxnHash func(p0 context.Context, p1 address.Address, p2 uint64, p3 address.Address, p4 address.Address, p5 types.BigInt, p6 address.Address, p7 uint64, p8 []byte) (cid.Cid, error) `perm:"sign"`
+		PaychAvailableFundsByFromTo func(p0 context.Context, p1 address.Address, p2 address.Address) (*ChannelAvailableFunds, error) `perm:"sign" stability:"stable"`
 
-		MsigCancel func(p0 context.Context, p1 address.Address, p2 uint64, p3 address.Address, p4 types.BigInt, p5 address.Address, p6 uint64, p7 []byte) (cid.Cid, error) `perm:"sign"`
+		PaychCollect func(p0 context.Context, p1 address.Address) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		MsigCreate func(p0 context.Context, p1 uint64, p2 []address.Address, p3 abi.ChainEpoch, p4 types.BigInt, p5 address.Address, p6 types.BigInt) (cid.Cid, error) `perm:"sign"`
+		PaychGet func(p0 context.Context, p1 address.Address, p2 address.Address, p3 types.BigInt) (*ChannelInfo, error) `perm:"sign" stability:"stable"`
 
-		MsigGetAvailableBalance func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (types.BigInt, error) `perm:"read"`
+		PaychGetWaitReady func(p0 context.Context, p1 cid.Cid) (address.Address, error) `perm:"sign" stability:"stable"`
 
-		MsigGetPending func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) ([]*MsigTransaction, error) `perm:"read"`
+		PaychList func(p0 context.Context) ([]address.Address, error) `perm:"read" stability:"stable"`
 
-		MsigGetVested func(p0 context.Context, p1 address.Address, p2 types.TipSetKey, p3 types.TipSetKey) (types.BigInt, error) `perm:"read"`
+		PaychNewPayment func(p0 context.Context, p1 address.Address, p2 address.Address, p3 []VoucherSpec) (*PaymentInfo, error) `perm:"sign" stability:"stable"`
 
-		MsigGetVestingSchedule func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (MsigVesting, error) `perm:"read"`
+		PaychSettle func(p0 context.Context, p1 address.Address) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		MsigPropose func(p0 context.Context, p1 address.Address, p2 address.Address, p3 types.BigInt, p4 address.Address, p5 uint64, p6 []byte) (cid.Cid, error) `perm:"sign"`
+		PaychStatus func(p0 context.Context, p1 address.Address) (*PaychStatus, error) `perm:"read" stability:"stable"`
 
-		MsigRemoveSigner func(p0 context.Context, p1 address.Address, p2 address.Address, p3 address.Address, p4 bool) (cid.Cid, error) `perm:"sign"`
+		PaychVoucherAdd func(p0 context.Context, p1 address.Address, p2 *paych.SignedVoucher, p3 []byte, p4 types.BigInt) (types.BigInt, error) `perm:"write" stability:"stable"`
 
-		MsigSwapApprove func(p0 context.Context, p1 address.Address, p2 address.Address, p3 uint64, p4 address.Address, p5 address.Address, p6 address.Address) (cid.Cid, error) `perm:"sign"`
+		PaychVoucherCheckSpendable func(p0 context.Context, p1 address.Address, p2 *paych.SignedVoucher, p3 []byte, p4 []byte) (bool, error) `perm:"read" stability:"stable"`
 
-		MsigSwapCancel func(p0 context.Context, p1 address.Address, p2 address.Address, p3 uint64, p4 address.Address, p5 address.Address) (cid.Cid, error) `perm:"sign"`
+		PaychVoucherCheckValid func(p0 context.Context, p1 address.Address, p2 *paych.SignedVoucher) error `perm:"read" stability:"stable"`
 
-		MsigSwapPropose func(p0 context.Context, p1 address.Address, p2 address.Address, p3 address.Address, p4 address.Address) (cid.Cid, error) `perm:"sign"`
+		PaychVoucherCreate func(p0 context.Context, p1 address.Address, p2 types.BigInt, p3 uint64) (*VoucherCreateResult, error) `perm:"sign" stability:"stable"`
 
-		PaychAllocateLane func(p0 context.Context, p1 address.Address) (uint64, error) `perm:"sign"`
+		PaychVoucherList func(p0 context.Context, p1 address.Address) ([]*paych.SignedVoucher, error) `perm:"write" stability:"stable"`
 
-		PaychAvailableFunds func(p0 context.Context, p1 address.Address) (*ChannelAvailableFunds, error) `perm:"sign"`
+		PaychVoucherSubmit func(p0 context.Context, p1 address.Address, p2 *paych.SignedVoucher, p3 []byte, p4 []byte) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		PaychAvailableFundsByFromTo func(p0 context.Context, p1 address.Address, p2 address.Address) (*ChannelAvailableFunds, error) `perm:"sign"`
+		ProjectedCall func(p0 context.Context, p1 string, p2 []json.RawMessage, p3 []string) (json.RawMessage, error) `perm:"read" stability:"experimental"`
 
-		PaychCollect func(p0 context.Context, p1 address.Address) (cid.Cid, error) `perm:"sign"`
+		RPCRecordStart func(p0 context.Context, p1 RPCRecordConfig) error `perm:"admin" stability:"experimental"`
 
-		PaychGet func(p0 context.Context, p1 address.Address, p2 address.Address, p3 types.BigInt) (*ChannelInfo, error) `perm:"sign"`
+		RPCRecordStatus func(p0 context.Context) (RPCRecordStatus, error) `perm:"admin" stability:"experimental"`
 
-		PaychGetWaitReady func(p0 context.Context, p1 cid.Cid) (address.Address, error) `perm:"sign"`
+		RPCRecordStop func(p0 context.Context) (RPCRecordStatus, error) `perm:"admin" stability:"experimental"`
 
-		PaychList func(p0 context.Context) ([]address.Address, error) `perm:"read"`
+		StateAccountBalanceDelta func(p0 context.Context, p1 address.Address, p2 types.TipSetKey, p3 types.TipSetKey) (*BalanceDelta, error) `perm:"read" stability:"experimental"`
 
-		PaychNewPayment func(p0 context.Context, p1 address.Address, p2 address.Address, p3 []VoucherSpec) (*PaymentInfo, error) `perm:"sign"`
+		StateAccountKey func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) `perm:"read" stability:"stable"`
 
-		PaychSettle func(p0 context.Context, p1 address.Address) (cid.Cid, error) `perm:"sign"`
+		StateAllMinerFaults func(p0 context.Context, p1 abi.ChainEpoch, p2 types.TipSetKey) ([]*Fault, error) `perm:"read" stability:"stable"`
 
-		PaychStatus func(p0 context.Context, p1 address.Address) (*PaychStatus, error) `perm:"read"`
+		StateCall func(p0 context.Context, p1 *types.Message, p2 types.TipSetKey) (*InvocResult, error) `perm:"read" stability:"stable"`
 
-		PaychVoucherAdd func(p0 context.Context, p1 address.Address, p2 *paych.SignedVoucher, p3 []byte, p4 types.BigInt) (types.BigInt, error) `perm:"write"`
+		StateChangedActors func(p0 context.Context, p1 cid.Cid, p2 cid.Cid) (map[string]types.Actor, error) `perm:"read" stability:"stable"`
 
-		PaychVoucherCheckSpendable func(p0 context.Context, p1 address.Address, p2 *paych.SignedVoucher, p3 []byte, p4 []byte) (bool, error) `perm:"read"`
+		StateCirculatingSupply func(p0 context.Context, p1 types.TipSetKey) (abi.TokenAmount, error) `perm:"read" stability:"stable"`
 
-		PaychVoucherCheckValid func(p0 context.Context, p1 address.Address, p2 *paych.SignedVoucher) error `perm:"read"`
+		StateCompute func(p0 context.Context, p1 abi.ChainEpoch, p2 []*types.Message, p3 types.TipSetKey) (*ComputeStateOutput, error) `perm:"read" stability:"stable"`
 
-		PaychVoucherCreate func(p0 context.Context, p1 address.Address, p2 types.BigInt, p3 uint64) (*VoucherCreateResult, error) `perm:"sign"`
+		StateDealProviderCollateralBounds func(p0 context.Context, p1 abi.PaddedPieceSize, p2 bool, p3 types.TipSetKey) (DealCollateralBounds, error) `perm:"read" stability:"stable"`
 
-		PaychVoucherList func(p0 context.Context, p1 address.Address) ([]*paych.SignedVoucher, error) `perm:"write"`
+		StateDecodeParams func(p0 context.Context, p1 address.Address, p2 abi.MethodNum, p3 []byte, p4 types.TipSetKey) (interface{}, error) `perm:"read" stability:"stable"`
 
-		PaychVoucherSubmit func(p0 context.Context, p1 address.Address, p2 *paych.SignedVoucher, p3 []byte, p4 []byte) (cid.Cid, error) `perm:"sign"`
+		StateGetActor func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*types.Actor, error) `perm:"read" stability:"stable"`
 
-		StateAccountKey func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) `perm:"read"`
+		StateListActors func(p0 context.Context, p1 types.TipSetKey) ([]address.Address, error) `perm:"read" stability:"stable"`
 
-		StateAllMinerFaults func(p0 context.Context, p1 abi.ChainEpoch, p2 types.TipSetKey) ([]*Fault, error) `perm:"read"`
+		StateListMessages func(p0 context.Context, p1 *MessageMatch, p2 types.TipSetKey, p3 abi.ChainEpoch) ([]cid.Cid, error) `perm:"read" stability:"stable"`
 
-		StateCall func(p0 context.Context, p1 *types.Message, p2 types.TipSetKey) (*InvocResult, error) `perm:"read"`
+		StateListMiners func(p0 context.Context, p1 types.TipSetKey) ([]address.Address, error) `perm:"read" stability:"stable"`
 
-		StateChangedActors func(p0 context.Context, p1 cid.Cid, p2 cid.Cid) (map[string]types.Actor, error) `perm:"read"`
+		StateLookupID func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) `perm:"read" stability:"stable"`
 
-		StateCirculatingSupply func(p0 context.Context, p1 types.TipSetKey) (abi.TokenAmount, error) `perm:"read"`
+		StateLookupRobustAddress func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) `perm:"read" stability:"experimental"`
 
-		StateCompute func(p0 context.Context, p1 abi.ChainEpoch, p2 []*types.Message, p3 types.TipSetKey) (*ComputeStateOutput, error) `perm:"read"`
+		StateMarketBalance func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (MarketBalance, error) `perm:"read" stability:"stable"`
 
-		StateDealProviderCollateralBounds func(p0 context.Context, p1 abi.PaddedPieceSize, p2 bool, p3 types.TipSetKey) (DealCollateralBounds, error) `perm:"read"`
+		StateMarketDeals func(p0 context.Context, p1 types.TipSetKey) (map[string]MarketDeal, error) `perm:"read" stability:"stable"`
 
-		StateDecodeParams func(p0 context.Context, p1 address.Address, p2 abi.MethodNum, p3 []byte, p4 types.TipSetKey) (interface{}, error) `perm:"read"`
+		StateMarketParticipants func(p0 context.Context, p1 types.TipSetKey) (map[string]MarketBalance, error) `perm:"read" stability:"stable"`
 
-		StateGetActor func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*types.Actor, error) `perm:"read"`
+		StateMarketStorageDeal func(p0 context.Context, p1 abi.DealID, p2 types.TipSetKey) (*MarketDeal, error) `perm:"read" stability:"stable"`
 
-		StateListActors func(p0 context.Context, p1 types.TipSetKey) ([]address.Address, error) `perm:"read"`
+		StateMinerActiveSectors func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) ([]*miner.SectorOnChainInfo, error) `perm:"read" stability:"stable"`
 
-		StateListMessages func(p0 context.Context, p1 *MessageMatch, p2 types.TipSetKey, p3 abi.ChainEpoch) ([]cid.Cid, error) `perm:"read"`
+		StateMinerAvailableBalance func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (types.BigInt, error) `perm:"read" stability:"stable"`
 
-		StateListMiners func(p0 context.Context, p1 types.TipSetKey) ([]address.Address, error) `perm:"read"`
+		StateMinerDeadlines func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) ([]Deadline, error) `perm:"read" stability:"stable"`
 
-		StateLookupID func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) `perm:"read"`
+		StateMinerExpirationLadder func(p0 context.Context, p1 address.Address, p2 float64, p3 types.TipSetKey) (*ExpirationLadder, error) `perm:"read" stability:"experimental"`
 
-		StateMarketBalance func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (MarketBalance, error) `perm:"read"`
+		StateMinerFaults func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (bitfield.BitField, error) `perm:"read" stability:"stable"`
 
-		StateMarketDeals func(p0 context.Context, p1 types.TipSetKey) (map[string]MarketDeal, error) `perm:"read"`
+		StateMinerInfo func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (miner.MinerInfo, error) `perm:"read" stability:"stable"`
 
-		StateMarketParticipants func(p0 context.Context, p1 types.TipSetKey) (map[string]MarketBalance, error) `perm:"read"`
+		StateMinerInitialPledgeCollateral func(p0 context.Context, p1 address.Address, p2 miner.SectorPreCommitInfo, p3 types.TipSetKey) (types.BigInt, error) `perm:"read" stability:"stable"`
 
-		StateMarketStorageDeal func(p0 context.Context, p1 abi.DealID, p2 types.TipSetKey) (*MarketDeal, error) `perm:"read"`
+		StateMinerPartitions func(p0 context.Context, p1 address.Address, p2 uint64, p3 types.TipSetKey) ([]Partition, error) `perm:"read" stability:"stable"`
 
-		StateMinerActiveSectors func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) ([]*miner.SectorOnChainInfo, error) `perm:"read"`
+		StateMinerPower func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*MinerPower, error) `perm:"read" stability:"stable"`
 
-		StateMinerAvailableBalance func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (types.BigInt, error) `perm:"read"`
+		StateMinerPreCommitDepositForPower func(p0 context.Context, p1 address.Address, p2 miner.SectorPreCommitInfo, p3 types.TipSetKey) (types.BigInt, error) `perm:"read" stability:"stable"`
 
-		StateMinerDeadlines func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) ([]Deadline, error) `perm:"read"`
+		StateMinerProvingDeadline func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*dline.Info, error) `perm:"read" stability:"stable"`
 
-		StateMinerFaults func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (bitfield.BitField, error) `perm:"read"`
+		StateMinerRecoveries func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (bitfield.BitField, error) `perm:"read" stability:"stable"`
 
-		StateMinerInfo func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (miner.MinerInfo, error) `perm:"read"`
+		StateMinerSectorAllocated func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (bool, error) `perm:"read" stability:"stable"`
 
-		StateMinerInitialPledgeCollateral func(p0 context.Context, p1 address.Address, p2 miner.SectorPreCommitInfo, p3 types.TipSetKey) (types.BigInt, error) `perm:"read"`
+		StateMinerSectorCount func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (MinerSectors, error) `perm:"read" stability:"stable"`
 
-		StateMinerPartitions func(p0 context.Context, p1 address.Address, p2 uint64, p3 types.TipSetKey) ([]Partition, error) `perm:"read"`
+		StateMinerSectors func(p0 context.Context, p1 address.Address, p2 *bitfield.BitField, p3 types.TipSetKey) ([]*miner.SectorOnChainInfo, error) `perm:"read" stability:"stable"`
 
-		StateMinerPower func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*MinerPower, error) `perm:"read"`
+		StateNetworkName func(p0 context.Context) (dtypes.NetworkName, error) `perm:"read" stability:"stable"`
 
-		StateMinerPreCommitDepositForPower func(p0 context.Context, p1 address.Address, p2 miner.SectorPreCommitInfo, p3 types.TipSetKey) (types.BigInt, error) `perm:"read"`
+		StateNetworkVersion func(p0 context.Context, p1 types.TipSetKey) (apitypes.NetworkVersion, error) `perm:"read" stability:"stable"`
 
-		StateMinerProvingDeadline func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*dline.Info, error) `perm:"read"`
+		StateReadState func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*ActorState, error) `perm:"read" stability:"stable"`
 
-		StateMinerRecoveries func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (bitfield.BitField, error) `perm:"read"`
+		StateReplay func(p0 context.Context, p1 types.TipSetKey, p2 cid.Cid) (*InvocResult, error) `perm:"read" stability:"stable"`
 
-		StateMinerSectorAllocated func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (bool, error) `perm:"read"`
+		StateSearchMsg func(p0 context.Context, p1 types.TipSetKey, p2 cid.Cid, p3 abi.ChainEpoch, p4 bool) (*MsgLookup, error) `perm:"read" stability:"stable"`
 
-		StateMinerSectorCount func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (MinerSectors, error) `perm:"read"`
+		StateSectorExpiration func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (*miner.SectorExpiration, error) `perm:"read" stability:"stable"`
 
-		StateMinerSectors func(p0 context.Context, p1 address.Address, p2 *bitfield.BitField, p3 types.TipSetKey) ([]*miner.SectorOnChainInfo, error) `perm:"read"`
+		StateSectorGetInfo func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (*miner.SectorOnChainInfo, error) `perm:"read" stability:"stable"`
 
-		StateNetworkName func(p0 context.Context) (dtypes.NetworkName, error) `perm:"read"`
+		StateSectorPartition func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (*miner.SectorLocation, error) `perm:"read" stability:"stable"`
 
-		StateNetworkVersion func(p0 context.Context, p1 types.TipSetKey) (apitypes.NetworkVersion, error) `perm:"read"`
+		StateSectorPreCommitInfo func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (miner.SectorPreCommitOnChainInfo, error) `perm:"read" stability:"stable"`
 
-		StateReadState func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*ActorState, error) `perm:"read"`
+		StateUnwatchActor func(p0 context.Context, p1 address.Address) error `perm:"write" stability:"experimental"`
 
-		StateReplay func(p0 context.Context, p1 types.TipSetKey, p2 cid.Cid) (*InvocResult, error) `perm:"read"`
+		StateVMCirculatingSupplyInternal func(p0 context.Context, p1 types.TipSetKey) (CirculatingSupply, error) `perm:"read" stability:"stable"`
 
-		StateSearchMsg func(p0 context.Context, p1 types.TipSetKey, p2 cid.Cid, p3 abi.ChainEpoch, p4 bool) (*MsgLookup, error) `perm:"read"`
+		StateVerifiedClientStatus func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*abi.StoragePower, error) `perm:"read" stability:"stable"`
 
-		StateSectorExpiration func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (*miner.SectorExpiration, error) `perm:"read"`
+		StateVerifiedRegistryRootKey func(p0 context.Context, p1 types.TipSetKey) (address.Address, error) `perm:"read" stability:"stable"`
 
-		StateSectorGetInfo func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (*miner.SectorOnChainInfo, error) `perm:"read"`
+		StateVerifierStatus func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*abi.StoragePower, error) `perm:"read" stability:"stable"`
 
-		StateSectorPartition func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (*miner.SectorLocation, error) `perm:"read"`
+		StateWaitMsg func(p0 context.Context, p1 cid.Cid, p2 uint64, p3 abi.ChainEpoch, p4 bool) (*MsgLookup, error) `perm:"read" stability:"stable"`
 
-		StateSectorPreCommitInfo func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (miner.SectorPreCommitOnChainInfo, error) `perm:"read"`
+		StateWatchActor func(p0 context.Context, p1 address.Address) error `perm:"write" stability:"experimental"`
 
-		StateVMCirculatingSupplyInternal func(p0 context.Context, p1 types.TipSetKey) (CirculatingSupply, error) `perm:"read"`
+		StateWatchedActors func(p0 context.Context) ([]WatchedActor, error) `perm:"read" stability:"experimental"`
 
-		StateVerifiedClientStatus func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*abi.StoragePower, error) `perm:"read"`
+		SyncCheckBad func(p0 context.Context, p1 cid.Cid) (string, error) `perm:"read" stability:"stable"`
 
-		StateVerifiedRegistryRootKey func(p0 context.Context, p1 types.TipSetKey) (address.Address, error) `perm:"read"`
+		SyncCheckpoint func(p0 context.Context, p1 types.TipSetKey) error `perm:"admin" stability:"stable"`
 
-		StateVerifierStatus func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*abi.StoragePower, error) `perm:"read"`
+		SyncIncomingBlocks func(p0 context.Context) (<-chan *types.BlockHeader, error) `perm:"read" stability:"stable"`
 
-		StateWaitMsg func(p0 context.Context, p1 cid.Cid, p2 uint64, p3 abi.ChainEpoch, p4 bool) (*MsgLookup, error) `perm:"read"`
+		SyncMarkBad func(p0 context.Context, p1 cid.Cid) error `perm:"admin" stability:"stable"`
 
-		SyncCheckBad func(p0 context.Context, p1 cid.Cid) (string, error) `perm:"read"`
+		SyncState func(p0 context.Context) (*SyncState, error) `perm:"read" stability:"stable"`
 
-		SyncCheckpoint func(p0 context.Context, p1 types.TipSetKey) error `perm:"admin"`
+		SyncSubmitBlock func(p0 context.Context, p1 *types.BlockMsg) error `perm:"write" stability:"stable"`
 
-		SyncIncomingBlocks func(p0 context.Context) (<-chan *types.BlockHeader, error) `perm:"read"`
+		SyncUnmarkAllBad func(p0 context.Context) error `perm:"admin" stability:"stable"`
 
-		SyncMarkBad func(p0 context.Context, p1 cid.Cid) error `perm:"admin"`
+		SyncUnmarkBad func(p0 context.Context, p1 cid.Cid) error `perm:"admin" stability:"stable"`
 
-		SyncState func(p0 context.Context) (*SyncState, error) `perm:"read"`
+		SyncValidateTipset func(p0 context.Context, p1 types.TipSetKey) (bool, error) `perm:"read" stability:"stable"`
 
-		SyncSubmitBlock func(p0 context.Context, p1 *types.BlockMsg) error `perm:"write"`
+		WalletAddWatch func(p0 context.Context, p1 address.Address, p2 string) error `perm:"write" stability:"experimental"`
 
-		SyncUnmarkAllBad func(p0 context.Context) error `perm:"admin"`
+		WalletBalance func(p0 context.Context, p1 address.Address) (types.BigInt, error) `perm:"read" stability:"stable"`
 
-		SyncUnmarkBad func(p0 context.Context, p1 cid.Cid) error `perm:"admin"`
+		WalletDefaultAddress func(p0 context.Context) (address.Address, error) `perm:"write" stability:"stable"`
 
-		SyncValidateTipset func(p0 context.Context, p1 types.TipSetKey) (bool, error) `perm:"read"`
+		WalletDelete func(p0 context.Context, p1 address.Address) error `perm:"admin" stability:"stable"`
 
-		WalletBalance func(p0 context.Context, p1 address.Address) (types.BigInt, error) `perm:"read"`
+		WalletExport func(p0 context.Context, p1 address.Address) (*types.KeyInfo, error) `perm:"admin" stability:"stable"`
 
-		WalletDefaultAddress func(p0 context.Context) (address.Address, error) `perm:"write"`
+		WalletHas func(p0 context.Context, p1 address.Address) (bool, error) `perm:"write" stability:"stable"`
 
-		WalletDelete func(p0 context.Context, p1 address.Address) error `perm:"admin"`
+		WalletImport func(p0 context.Context, p1 *types.KeyInfo) (address.Address, error) `perm:"admin" stability:"stable"`
 
-		WalletExport func(p0 context.Context, p1 address.Address) (*types.KeyInfo, error) `perm:"admin"`
+		WalletList func(p0 context.Context) ([]address.Address, error) `perm:"write" stability:"stable"`
 
-		WalletHas func(p0 context.Context, p1 address.Address) (bool, error) `perm:"write"`
+		WalletListWatch func(p0 context.Context) ([]WatchOnlyAddress, error) `perm:"read" stability:"experimental"`
 
-		WalletImport func(p0 context.Context, p1 *types.KeyInfo) (address.Address, error) `perm:"admin"`
+		WalletLock func(p0 context.Context) error `perm:"admin" stability:"experimental"`
 
-		WalletList func(p0 context.Context) ([]address.Address, error) `perm:"write"`
+		WalletNew func(p0 context.Context, p1 types.KeyType) (address.Address, error) `perm:"write" stability:"stable"`
 
-		WalletNew func(p0 context.Context, p1 types.KeyType) (address.Address, error) `perm:"write"`
+		WalletPolicyGet func(p0 context.Context, p1 address.Address) (*WalletUsagePolicy, error) `perm:"read" stability:"experimental"`
 
-		WalletSetDefault func(p0 context.Context, p1 address.Address) error `perm:"write"`
+		WalletPolicyList func(p0 context.Context) ([]WalletUsagePolicy, error) `perm:"read" stability:"experimental"`
 
-		WalletSign func(p0 context.Context, p1 address.Address, p2 []byte) (*crypto.Signature, error) `perm:"sign"`
+		WalletPolicyRemove func(p0 context.Context, p1 address.Address) error `perm:"admin" stability:"experimental"`
 
-		WalletSignMessage func(p0 context.Context, p1 address.Address, p2 *types.Message) (*types.SignedMessage, error) `perm:"sign"`
+		WalletPolicySet func(p0 context.Context, p1 WalletUsagePolicy) error `perm:"admin" stability:"experimental"`
 
-		WalletValidateAddress func(p0 context.Context, p1 string) (address.Address, error) `perm:"read"`
+		WalletRemoveWatch func(p0 context.Context, p1 address.Address) error `perm:"write" stability:"experimental"`
 
-		WalletVerify func(p0 context.Context, p1 address.Address, p2 []byte, p3 *crypto.Signature) (bool, error) `perm:"read"`
+		WalletSetDefault func(p0 context.Context, p1 address.Address) error `perm:"write" stability:"stable"`
+
+		WalletSign func(p0 context.Context, p1 address.Address, p2 []byte) (*crypto.Signature, error) `perm:"sign" stability:"stable"`
+
+		WalletSignMessage func(p0 context.Context, p1 address.Address, p2 *types.Message) (*types.SignedMessage, error) `perm:"sign" stability:"stable"`
+
+		WalletSignMessageOverridePolicy func(p0 context.Context, p1 address.Address, p2 *types.Message) (*types.SignedMessage, error) `perm:"admin" stability:"experimental"`
+
+		WalletUnlock func(p0 context.Context, p1 string) error `perm:"admin" stability:"experimental"`
+
+		WalletValidateAddress func(p0 context.Context, p1 string) (address.Address, error) `perm:"read" stability:"stable"`
+
+		WalletVerify func(p0 context.Context, p1 address.Address, p2 []byte, p3 *crypto.Signature) (bool, error) `perm:"read" stability:"stable"`
 	}
 }
 
@@ -462,72 +563,83 @@ type FullNodeStub struct {
 
 type GatewayStruct struct {
 	Internal struct {
-		ChainGetBlockMessages func(p0 context.Context, p1 cid.Cid) (*BlockMessages, error) ``
+		ChainGetBlockMessages func(p0 context.Context, p1 cid.Cid) (*BlockMessages, error) `stability:"stable"`
 
-		ChainGetMessage func(p0 context.Context, p1 cid.Cid) (*types.Message, error) ``
+		ChainGetMessage func(p0 context.Context, p1 cid.Cid) (*types.Message, error) `stability:"stable"`
 
-		ChainGetTipSet func(p0 context.Context, p1 types.TipSetKey) (*types.TipSet, error) ``
+		ChainGetTipSet func(p0 context.Context, p1 types.TipSetKey) (*types.TipSet, error) `stability:"stable"`
 
-		ChainGetTipSetByHeight func(p0 context.Context, p1 abi.ChainEpoch, p2 types.TipSetKey) (*types.TipSet, error) ``
+		ChainGetTipSetByHeight func(p0 context.Context, p1 abi.ChainEpoch, p2 types.TipSetKey) (*types.TipSet, error) `stability:"stable"`
 
-		ChainHasObj func(p0 context.Context, p1 cid.Cid) (bool, error) ``
+		ChainHasObj func(p0 context.Context, p1 cid.Cid) (bool, error) `stability:"stable"`
 
-		ChainHead func(p0 context.Context) (*types.TipSet, error) ``
+		ChainHead func(p0 context.Context) (*types.TipSet, error) `stability:"stable"`
 
-		ChainNotify func(p0 context.Context) (<-chan []*HeadChange, error) ``
+		ChainNotify func(p0 context.Context) (<-chan []*HeadChange, error) `stability:"stable"`
 
-		ChainReadObj func(p0 context.Context, p1 cid.Cid) ([]byte, error) ``
+		ChainReadObj func(p0 context.Context, p1 cid.Cid) ([]byte, error) `stability:"stable"`
 
-		GasEstimateMessageGas func(p0 context.Context, p1 *types.Message, p2 *MessageSendSpec, p3 types.TipSetKey) (*types.Message, error) ``
+		GasEstimateMessageGas func(p0 context.Context, p1 *types.Message, p2 *MessageSendSpec, p3 types.TipSetKey) (*types.Message, error) `stability:"stable"`
 
-		MpoolPush func(p0 context.Context, p1 *types.SignedMessage) (cid.Cid, error) ``
+		MpoolPush func(p0 context.Context, p1 *types.SignedMessage) (cid.Cid, error) `stability:"stable"`
 
-		MsigGetAvailableBalance func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (types.BigInt, error) ``
+		MsigGetAvailableBalance func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (types.BigInt, error) `stability:"stable"`
 
-		MsigGetPending func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) ([]*MsigTransaction, error) ``
+		MsigGetPending func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) ([]*MsigTransaction, error) `stability:"stable"`
 
-		MsigGetVested func(p0 context.Context, p1 address.Address, p2 types.TipSetKey, p3 types.TipSetKey) (types.BigInt, error) ``
+		MsigGetVested func(p0 context.Context, p1 address.Address, p2 types.TipSetKey, p3 types.TipSetKey) (types.BigInt, error) `stability:"stable"`
 
-		StateAccountKey func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) ``
+		ProjectedCall func(p0 context.Context, p1 string, p2 []json.RawMessage, p3 []string) (json.RawMessage, error) `stability:"stable"`
 
-		StateDealProviderCollateralBounds func(p0 context.Context, p1 abi.PaddedPieceSize, p2 bool, p3 types.TipSetKey) (DealCollateralBounds, error) ``
+		StateAccountKey func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) `stability:"stable"`
 
-		StateGetActor func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*types.Actor, error) ``
+		StateDealProviderCollateralBounds func(p0 context.Context, p1 abi.PaddedPieceSize, p2 bool, p3 types.TipSetKey) (DealCollateralBounds, error) `stability:"stable"`
 
-		StateListMiners func(p0 context.Context, p1 types.TipSetKey) ([]address.Address, error) ``
+		StateGetActor func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*types.Actor, error) `stability:"stable"`
 
-		StateLookupID func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) ``
+		StateListMiners func(p0 context.Context, p1 types.TipSetKey) ([]address.Address, error) `stability:"stable"`
 
-		StateMarketBalance func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (MarketBalance, error) ``
+		StateLookupID func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) `stability:"stable"`
 
-		StateMarketStorageDeal func(p0 context.Context, p1 abi.DealID, p2 types.TipSetKey) (*MarketDeal, error) ``
+		StateMarketBalance func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (MarketBalance, error) `stability:"stable"`
 
-		StateMinerInfo func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (miner.MinerInfo, error) ``
+		StateMarketStorageDeal func(p0 context.Context, p1 abi.DealID, p2 types.TipSetKey) (*MarketDeal, error) `stability:"stable"`
 
-		StateMinerPower func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*MinerPower, error) ``
+		StateMinerInfo func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (miner.MinerInfo, error) `stability:"stable"`
 
-		StateMinerProvingDeadline func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*dline.Info, error) ``
+		StateMinerPower func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*MinerPower, error) `stability:"stable"`
 
-		StateNetworkVersion func(p0 context.Context, p1 types.TipSetKey) (apitypes.NetworkVersion, error) ``
+		StateMinerProvingDeadline func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*dline.Info, error) `stability:"stable"`
 
-		StateSearchMsg func(p0 context.Context, p1 types.TipSetKey, p2 cid.Cid, p3 abi.ChainEpoch, p4 bool) (*MsgLookup, error) ``
+		StateNetworkVersion func(p0 context.Context, p1 types.TipSetKey) (apitypes.NetworkVersion, error) `stability:"stable"`
 
-		StateSectorGetInfo func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (*miner.SectorOnChainInfo, error) ``
+		StateSearchMsg func(p0 context.Context, p1 types.TipSetKey, p2 cid.Cid, p3 abi.ChainEpoch, p4 bool) (*MsgLookup, error) `stability:"stable"`
 
-		StateVerifiedClientStatus func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*abi.StoragePower, error) ``
+		StateSectorGetInfo func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (*miner.SectorOnChainInfo, error) `stability:"stable"`
 
-		StateWaitMsg func(p0 context.Context, p1 cid.Cid, p2 uint64, p3 abi.ChainEpoch, p4 bool) (*MsgLookup, error) ``
+		StateVerifiedClientStatus func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*abi.StoragePower, error) `stability:"stable"`
 
-		WalletBalance func(p0 context.Context, p1 address.Address) (types.BigInt, error) ``
+		StateWaitMsg func(p0 context.Context, p1 cid.Cid, p2 uint64, p3 abi.ChainEpoch, p4 bool) (*MsgLookup, error) `stability:"stable"`
+
+		WalletBalance func(p0 context.Context, p1 address.Address) (types.BigInt, error) `stability:"stable"`
 	}
 }
 
 type GatewayStub struct {
 }
 
+type ProjectedCallerStruct struct {
+	Internal struct {
+		ProjectedCall func(p0 context.Context, p1 string, p2 []json.RawMessage, p3 []string) (json.RawMessage, error) `stability:"experimental"`
+	}
+}
+
+type ProjectedCallerStub struct {
+}
+
 type SignableStruct struct {
 	Internal struct {
-		Sign func(p0 context.Context, p1 SignFunc) error ``
+		Sign func(p0 context.Context, p1 SignFunc) error `stability:"stable"`
 	}
 }
 
@@ -538,189 +650,209 @@ type StorageMinerStruct struct {
 	CommonStruct
 
 	Internal struct {
-		ActorAddress func(p0 context.Context) (address.Address, error) `perm:"read"`
+		ActorAddress func(p0 context.Context) (address.Address, error) `perm:"read" stability:"stable"`
+
+		ActorAddressConfig func(p0 context.Context) (AddressConfig, error) `perm:"read" stability:"stable"`
+
+		ActorSectorSize func(p0 context.Context, p1 address.Address) (abi.SectorSize, error) `perm:"read" stability:"stable"`
+
+		CheckProvable func(p0 context.Context, p1 abi.RegisteredPoStProof, p2 []storage.SectorRef, p3 bool) (map[abi.SectorNumber]string, error) `perm:"admin" stability:"stable"`
+
+		ComputeProof func(p0 context.Context, p1 []builtin.SectorInfo, p2 abi.PoStRandomness) ([]builtin.PoStProof, error) `perm:"read" stability:"stable"`
+
+		CreateBackup func(p0 context.Context, p1 string) error `perm:"admin" stability:"stable"`
+
+		DealsConsiderOfflineRetrievalDeals func(p0 context.Context) (bool, error) `perm:"admin" stability:"stable"`
+
+		DealsConsiderOfflineStorageDeals func(p0 context.Context) (bool, error) `perm:"admin" stability:"stable"`
+
+		DealsConsiderOnlineRetrievalDeals func(p0 context.Context) (bool, error) `perm:"admin" stability:"stable"`
+
+		DealsConsiderOnlineStorageDeals func(p0 context.Context) (bool, error) `perm:"admin" stability:"stable"`
 
-		ActorAddressConfig func(p0 context.Context) (AddressConfig, error) `perm:"read"`
+		DealsConsiderUnverifiedStorageDeals func(p0 context.Context) (bool, error) `perm:"admin" stability:"stable"`
 
-		ActorSectorSize func(p0 context.Context, p1 address.Address) (abi.SectorSize, error) `perm:"read"`
+		DealsConsiderVerifiedStorageDeals func(p0 context.Context) (bool, error) `perm:"admin" stability:"stable"`
 
-		CheckProvable func(p0 context.Context, p1 abi.RegisteredPoStProof, p2 []storage.SectorRef, p3 bool) (map[abi.SectorNumber]string, error) `perm:"admin"`
+		DealsImportData func(p0 context.Context, p1 cid.Cid, p2 string) error `perm:"admin" stability:"stable"`
 
-		ComputeProof func(p0 context.Context, p1 []builtin.SectorInfo, p2 abi.PoStRandomness) ([]builtin.PoStProof, error) `perm:"read"`
+		DealsList func(p0 context.Context) ([]MarketDeal, error) `perm:"admin" stability:"stable"`
 
-		CreateBackup func(p0 context.Context, p1 string) error `perm:"admin"`
+		DealsPieceCidBlocklist func(p0 context.Context) ([]cid.Cid, error) `perm:"admin" stability:"stable"`
 
-		DealsConsiderOfflineRetrievalDeals func(p0 context.Context) (bool, error) `perm:"admin"`
+		DealsSetConsiderOfflineRetrievalDeals func(p0 context.Context, p1 bool) error `perm:"admin" stability:"stable"`
 
-		DealsConsiderOfflineStorageDeals func(p0 context.Context) (bool, error) `perm:"admin"`
+		DealsSetConsiderOfflineStorageDeals func(p0 context.Context, p1 bool) error `perm:"admin" stability:"stable"`
 
-		DealsConsiderOnlineRetrievalDeals func(p0 context.Context) (bool, error) `perm:"admin"`
+		DealsSetConsiderOnlineRetrievalDeals func(p0 context.Context, p1 bool) error `perm:"admin" stability:"stable"`
 
-		DealsConsiderOnlineStorageDeals func(p0 context.Context) (bool, error) `perm:"admin"`
+		DealsSetConsiderOnlineStorageDeals func(p0 context.Context, p1 bool) error `perm:"admin" stability:"stable"`
 
-		DealsConsiderUnverifiedStorageDeals func(p0 context.Context) (bool, error) `perm:"admin"`
+		DealsSetConsiderUnverifiedStorageDeals func(p0 context.Context, p1 bool) error `perm:"admin" stability:"stable"`
 
-		DealsConsiderVerifiedStorageDeals func(p0 context.Context) (bool, error) `perm:"admin"`
+		DealsSetConsiderVerifiedStorageDeals func(p0 context.Context, p1 bool) error `perm:"admin" stability:"stable"`
 
-		DealsImportData func(p0 context.Context, p1 cid.Cid, p2 string) error `perm:"admin"`
+		DealsSetPieceCidBlocklist func(p0 context.Context, p1 []cid.Cid) error `perm:"admin" stability:"stable"`
 
-		DealsList func(p0 context.Context) ([]MarketDeal, error) `perm:"admin"`
+		MarketCancelDataTransfer func(p0 context.Context, p1 datatransfer.TransferID, p2 peer.ID, p3 bool) error `perm:"write" stability:"stable"`
 
-		DealsPieceCidBlocklist func(p0 context.Context) ([]cid.Cid, error) `perm:"admin"`
+		MarketDataTransferUpdates func(p0 context.Context) (<-chan DataTransferChannel, error) `perm:"write" stability:"stable"`
 
-		DealsSetConsiderOfflineRetrievalDeals func(p0 context.Context, p1 bool) error `perm:"admin"`
+		MarketDealSealingStatus func(p0 context.Context, p1 cid.Cid) (*DealSealingStatus, error) `perm:"read" stability:"experimental"`
 
-		DealsSetConsiderOfflineStorageDeals func(p0 context.Context, p1 bool) error `perm:"admin"`
+		MarketGetAsk func(p0 context.Context) (*storagemarket.SignedStorageAsk, error) `perm:"read" stability:"stable"`
 
-		DealsSetConsiderOnlineRetrievalDeals func(p0 context.Context, p1 bool) error `perm:"admin"`
+		MarketGetDealUpdates func(p0 context.Context) (<-chan storagemarket.MinerDeal, error) `perm:"read" stability:"stable"`
 
-		DealsSetConsiderOnlineStorageDeals func(p0 context.Context, p1 bool) error `perm:"admin"`
+		MarketGetRetrievalAsk func(p0 context.Context) (*retrievalmarket.Ask, error) `perm:"read" stability:"stable"`
 
-		DealsSetConsiderUnverifiedStorageDeals func(p0 context.Context, p1 bool) error `perm:"admin"`
+		MarketImportDealData func(p0 context.Context, p1 cid.Cid, p2 string) error `perm:"write" stability:"stable"`
 
-		DealsSetConsiderVerifiedStorageDeals func(p0 context.Context, p1 bool) error `perm:"admin"`
+		MarketListDataTransfers func(p0 context.Context) ([]DataTransferChannel, error) `perm:"write" stability:"stable"`
 
-		DealsSetPieceCidBlocklist func(p0 context.Context, p1 []cid.Cid) error `perm:"admin"`
+		MarketListDeals func(p0 context.Context) ([]MarketDeal, error) `perm:"read" stability:"stable"`
 
-		MarketCancelDataTransfer func(p0 context.Context, p1 datatransfer.TransferID, p2 peer.ID, p3 bool) error `perm:"write"`
+		MarketListIncompleteDeals func(p0 context.Context) ([]storagemarket.MinerDeal, error) `perm:"read" stability:"stable"`
 
-		MarketDataTransferUpdates func(p0 context.Context) (<-chan DataTransferChannel, error) `perm:"write"`
+		MarketListRetrievalDeals func(p0 context.Context) ([]retrievalmarket.ProviderDealState, error) `perm:"read" stability:"stable"`
 
-		MarketGetAsk func(p0 context.Context) (*storagemarket.SignedStorageAsk, error) `perm:"read"`
+		MarketPendingDeals func(p0 context.Context) (PendingDealInfo, error) `perm:"write" stability:"stable"`
 
-		MarketGetDealUpdates func(p0 context.Context) (<-chan storagemarket.MinerDeal, error) `perm:"read"`
+		MarketPublishPendingDeals func(p0 context.Context) error `perm:"admin" stability:"stable"`
 
-		MarketGetRetrievalAsk func(p0 context.Context) (*retrievalmarket.Ask, error) `perm:"read"`
+		MarketRestartDataTransfer func(p0 context.Context, p1 datatransfer.TransferID, p2 peer.ID, p3 bool) error `perm:"write" stability:"stable"`
 
-		MarketImportDealData func(p0 context.Context, p1 cid.Cid, p2 string) error `perm:"write"`
+		MarketSetAsk func(p0 context.Context, p1 types.BigInt, p2 types.BigInt, p3 abi.ChainEpoch, p4 abi.PaddedPieceSize, p5 abi.PaddedPieceSize) error `perm:"admin" stability:"stable"`
 
-		MarketListDataTransfers func(p0 context.Context) ([]DataTransferChannel, error) `perm:"write"`
+		MarketSetRetrievalAsk func(p0 context.Context, p1 *retrievalmarket.Ask) error `perm:"admin" stability:"stable"`
 
-		MarketListDeals func(p0 context.Context) ([]MarketDeal, error) `perm:"read"`
+		Methods func(p0 context.Context) (map[string]MethodStability, error) `perm:"read" stability:"experimental"`
 
-		MarketListIncompleteDeals func(p0 context.Context) ([]storagemarket.MinerDeal, error) `perm:"read"`
+		MiningAdvanceEpochs func(p0 context.Context, p1 abi.ChainEpoch, p2 bool) (abi.ChainEpoch, error) `perm:"admin" stability:"experimental"`
 
-		MarketListRetrievalDeals func(p0 context.Context) ([]retrievalmarket.ProviderDealState, error) `perm:"read"`
+		MiningBase func(p0 context.Context) (*types.TipSet, error) `perm:"read" stability:"stable"`
 
-		MarketPendingDeals func(p0 context.Context) (PendingDealInfo, error) `perm:"write"`
+		MiningLastSimulation func(p0 context.Context) (*BlockSimulation, error) `perm:"read" stability:"experimental"`
 
-		MarketPublishPendingDeals func(p0 context.Context) error `perm:"admin"`
+		MiningSimulate func(p0 context.Context) (*BlockSimulation, error) `perm:"admin" stability:"experimental"`
 
-		MarketRestartDataTransfer func(p0 context.Context, p1 datatransfer.TransferID, p2 peer.ID, p3 bool) error `perm:"write"`
+		PiecesGetCIDInfo func(p0 context.Context, p1 cid.Cid) (*piecestore.CIDInfo, error) `perm:"read" stability:"stable"`
 
-		MarketSetAsk func(p0 context.Context, p1 types.BigInt, p2 types.BigInt, p3 abi.ChainEpoch, p4 abi.PaddedPieceSize, p5 abi.PaddedPieceSize) error `perm:"admin"`
+		PiecesGetPieceInfo func(p0 context.Context, p1 cid.Cid) (*piecestore.PieceInfo, error) `perm:"read" stability:"stable"`
 
-		MarketSetRetrievalAsk func(p0 context.Context, p1 *retrievalmarket.Ask) error `perm:"admin"`
+		PiecesListCidInfos func(p0 context.Context) ([]cid.Cid, error) `perm:"read" stability:"stable"`
 
-		MiningBase func(p0 context.Context) (*types.TipSet, error) `perm:"read"`
+		PiecesListPieces func(p0 context.Context) ([]cid.Cid, error) `perm:"read" stability:"stable"`
 
-		PiecesGetCIDInfo func(p0 context.Context, p1 cid.Cid) (*piecestore.CIDInfo, error) `perm:"read"`
+		PledgeSector func(p0 context.Context) (abi.SectorID, error) `perm:"write" stability:"stable"`
 
-		PiecesGetPieceInfo func(p0 context.Context, p1 cid.Cid) (*piecestore.PieceInfo, error) `perm:"read"`
+		ReturnAddPiece func(p0 context.Context, p1 storiface.CallID, p2 abi.PieceInfo, p3 *storiface.CallError) error `perm:"admin" stability:"stable"`
 
-		PiecesListCidInfos func(p0 context.Context) ([]cid.Cid, error) `perm:"read"`
+		ReturnFetch func(p0 context.Context, p1 storiface.CallID, p2 *storiface.CallError) error `perm:"admin" stability:"stable"`
 
-		PiecesListPieces func(p0 context.Context) ([]cid.Cid, error) `perm:"read"`
+		ReturnFinalizeSector func(p0 context.Context, p1 storiface.CallID, p2 *storiface.CallError) error `perm:"admin" stability:"stable"`
 
-		PledgeSector func(p0 context.Context) (abi.SectorID, error) `perm:"write"`
+		ReturnMoveStorage func(p0 context.Context, p1 storiface.CallID, p2 *storiface.CallError) error `perm:"admin" stability:"stable"`
 
-		ReturnAddPiece func(p0 context.Context, p1 storiface.CallID, p2 abi.PieceInfo, p3 *storiface.CallError) error `perm:"admin"`
+		ReturnReadPiece func(p0 context.Context, p1 storiface.CallID, p2 bool, p3 *storiface.CallError) error `perm:"admin" stability:"stable"`
 
-		ReturnFetch func(p0 context.Context, p1 storiface.CallID, p2 *storiface.CallError) error `perm:"admin"`
+		ReturnReleaseUnsealed func(p0 context.Context, p1 storiface.CallID, p2 *storiface.CallError) error `perm:"admin" stability:"stable"`
 
-		ReturnFinalizeSector func(p0 context.Context, p1 storiface.CallID, p2 *storiface.CallError) error `perm:"admin"`
+		ReturnSealCommit1 func(p0 context.Context, p1 storiface.CallID, p2 storage.Commit1Out, p3 *storiface.CallError) error `perm:"admin" stability:"stable"`
 
-		ReturnMoveStorage func(p0 context.Context, p1 storiface.CallID, p2 *storiface.CallError) error `perm:"admin"`
+		ReturnSealCommit2 func(p0 context.Context, p1 storiface.CallID, p2 storage.Proof, p3 *storiface.CallError) error `perm:"admin" stability:"stable"`
 
-		ReturnReadPiece func(p0 context.Context, p1 storiface.CallID, p2 bool, p3 *storiface.CallError) error `perm:"admin"`
+		ReturnSealPreCommit1 func(p0 context.Context, p1 storiface.CallID, p2 storage.PreCommit1Out, p3 *storiface.CallError) error `perm:"admin" stability:"stable"`
 
-		ReturnReleaseUnsealed func(p0 context.Context, p1 storiface.CallID, p2 *storiface.CallError) error `perm:"admin"`
+		ReturnSealPreCommit2 func(p0 context.Context, p1 storiface.CallID, p2 storage.SectorCids, p3 *storiface.CallError) error `perm:"admin" stability:"stable"`
 
-		ReturnSealCommit1 func(p0 context.Context, p1 storiface.CallID, p2 storage.Commit1Out, p3 *storiface.CallError) error `perm:"admin"`
+		ReturnUnsealPiece func(p0 context.Context, p1 storiface.CallID, p2 *storiface.CallError) error `perm:"admin" stability:"stable"`
 
-		ReturnSealCommit2 func(p0 context.Context, p1 storiface.CallID, p2 storage.Proof, p3 *storiface.CallError) error `perm:"admin"`
+		SealingAbort func(p0 context.Context, p1 storiface.CallID) error `perm:"admin" stability:"stable"`
 
-		ReturnSealPreCommit1 func(p0 context.Context, p1 storiface.CallID, p2 storage.PreCommit1Out, p3 *storiface.CallError) error `perm:"admin"`
+		SealingSchedDiag func(p0 context.Context, p1 bool) (interface{}, error) `perm:"admin" stability:"stable"`
 
-		ReturnSealPreCommit2 func(p0 context.Context, p1 storiface.CallID, p2 storage.SectorCids, p3 *storiface.CallError) error `perm:"admin"`
+		SectorCommitFlush func(p0 context.Context) ([]sealiface.CommitBatchRes, error) `perm:"admin" stability:"stable"`
 
-		ReturnUnsealPiece func(p0 context.Context, p1 storiface.CallID, p2 *storiface.CallError) error `perm:"admin"`
+		SectorCommitPending func(p0 context.Context) ([]abi.SectorID, error) `perm:"admin" stability:"stable"`
 
-		SealingAbort func(p0 context.Context, p1 storiface.CallID) error `perm:"admin"`
+		SectorExport func(p0 context.Context, p1 abi.SectorNumber, p2 string) (SectorManifest, error) `perm:"admin" stability:"experimental"`
 
-		SealingSchedDiag func(p0 context.Context, p1 bool) (interface{}, error) `perm:"admin"`
+		SectorGetExpectedSealDuration func(p0 context.Context) (time.Duration, error) `perm:"read" stability:"stable"`
 
-		SectorCommitFlush func(p0 context.Context) ([]sealiface.CommitBatchRes, error) `perm:"admin"`
+		SectorGetSealDelay func(p0 context.Context) (time.Duration, error) `perm:"read" stability:"stable"`
 
-		SectorCommitPending func(p0 context.Context) ([]abi.SectorID, error) `perm:"admin"`
+		SectorImport func(p0 context.Context, p1 string, p2 bool) error `perm:"admin" stability:"experimental"`
 
-		SectorGetExpectedSealDuration func(p0 context.Context) (time.Duration, error) `perm:"read"`
+		SectorMarkForUpgrade func(p0 context.Context, p1 abi.SectorNumber) error `perm:"admin" stability:"stable"`
 
-		SectorGetSealDelay func(p0 context.Context) (time.Duration, error) `perm:"read"`
+		SectorPreCommitFlush func(p0 context.Context) ([]sealiface.PreCommitBatchRes, error) `perm:"admin" stability:"stable"`
 
-		SectorMarkForUpgrade func(p0 context.Context, p1 abi.SectorNumber) error `perm:"admin"`
+		SectorPreCommitPending func(p0 context.Context) ([]abi.SectorID, error) `perm:"admin" stability:"stable"`
 
-		SectorPreCommitFlush func(p0 context.Context) ([]sealiface.PreCommitBatchRes, error) `perm:"admin"`
+		SectorRemove func(p0 context.Context, p1 abi.SectorNumber) error `perm:"admin" stability:"stable"`
 
-		SectorPreCommitPending func(p0 context.Context) ([]abi.SectorID, error) `perm:"admin"`
+		SectorSetExpectedSealDuration func(p0 context.Context, p1 time.Duration) error `perm:"write" stability:"stable"`
 
-		SectorRemove func(p0 context.Context, p1 abi.SectorNumber) error `perm:"admin"`
+		SectorSetSealDelay func(p0 context.Context, p1 time.Duration) error `perm:"write" stability:"stable"`
 
-		SectorSetExpectedSealDuration func(p0 context.Context, p1 time.Duration) error `perm:"write"`
+		SectorStartSealing func(p0 context.Context, p1 abi.SectorNumber) error `perm:"write" stability:"stable"`
 
-		SectorSetSealDelay func(p0 context.Context, p1 time.Duration) error `perm:"write"`
+		SectorTerminate func(p0 context.Context, p1 abi.SectorNumber) error `perm:"admin" stability:"stable"`
 
-		SectorStartSealing func(p0 context.Context, p1 abi.SectorNumber) error `perm:"write"`
+		SectorTerminateFlush func(p0 context.Context) (*cid.Cid, error) `perm:"admin" stability:"stable"`
 
-		SectorTerminate func(p0 context.Context, p1 abi.SectorNumber) error `perm:"admin"`
+		SectorTerminatePending func(p0 context.Context) ([]abi.SectorID, error) `perm:"admin" stability:"stable"`
 
-		SectorTerminateFlush func(p0 context.Context) (*cid.Cid, error) `perm:"admin"`
+		SectorsList func(p0 context.Context) ([]abi.SectorNumber, error) `perm:"read" stability:"stable"`
 
-		SectorTerminatePending func(p0 context.Context) ([]abi.SectorID, error) `perm:"admin"`
+		SectorsListInStates func(p0 context.Context, p1 []SectorState) ([]abi.SectorNumber, error) `perm:"read" stability:"stable"`
 
-		SectorsList func(p0 context.Context) ([]abi.SectorNumber, error) `perm:"read"`
+		SectorsRefs func(p0 context.Context) (map[string][]SealedRef, error) `perm:"read" stability:"stable"`
 
-		SectorsListInStates func(p0 context.Context, p1 []SectorState) ([]abi.SectorNumber, error) `perm:"read"`
+		SectorsStatus func(p0 context.Context, p1 abi.SectorNumber, p2 bool) (SectorInfo, error) `perm:"read" stability:"stable"`
 
-		SectorsRefs func(p0 context.Context) (map[string][]SealedRef, error) `perm:"read"`
+		SectorsSummary func(p0 context.Context) (map[SectorState]int, error) `perm:"read" stability:"stable"`
 
-		SectorsStatus func(p0 context.Context, p1 abi.SectorNumber, p2 bool) (SectorInfo, error) `perm:"read"`
+		SectorsUpdate func(p0 context.Context, p1 abi.SectorNumber, p2 SectorState) error `perm:"admin" stability:"stable"`
 
-		SectorsSummary func(p0 context.Context) (map[SectorState]int, error) `perm:"read"`
+		StorageAddLocal func(p0 context.Context, p1 string) error `perm:"admin" stability:"stable"`
 
-		SectorsUpdate func(p0 context.Context, p1 abi.SectorNumber, p2 SectorState) error `perm:"admin"`
+		StorageAttach func(p0 context.Context, p1 stores.StorageInfo, p2 fsutil.FsStat) error `perm:"admin" stability:"stable"`
 
-		StorageAddLocal func(p0 context.Context, p1 string) error `perm:"admin"`
+		StorageBestAlloc func(p0 context.Context, p1 storiface.SectorFileType, p2 abi.SectorSize, p3 storiface.PathType) ([]stores.StorageInfo, error) `perm:"admin" stability:"stable"`
 
-		StorageAttach func(p0 context.Context, p1 stores.StorageInfo, p2 fsutil.FsStat) error `perm:"admin"`
+		StorageDeclareSector func(p0 context.Context, p1 stores.ID, p2 abi.SectorID, p3 storiface.SectorFileType, p4 bool) error `perm:"admin" stability:"stable"`
 
-		StorageBestAlloc func(p0 context.Context, p1 storiface.SectorFileType, p2 abi.SectorSize, p3 storiface.PathType) ([]stores.StorageInfo, error) `perm:"admin"`
+		StorageDropSector func(p0 context.Context, p1 stores.ID, p2 abi.SectorID, p3 storiface.SectorFileType) error `perm:"admin" stability:"stable"`
 
-		StorageDeclareSector func(p0 context.Context, p1 stores.ID, p2 abi.SectorID, p3 storiface.SectorFileType, p4 bool) error `perm:"admin"`
+		StorageFindSector func(p0 context.Context, p1 abi.SectorID, p2 storiface.SectorFileType, p3 abi.SectorSize, p4 bool) ([]stores.SectorStorageInfo, error) `perm:"admin" stability:"stable"`
 
-		StorageDropSector func(p0 context.Context, p1 stores.ID, p2 abi.SectorID, p3 storiface.SectorFileType) error `perm:"admin"`
+		StorageInfo func(p0 context.Context, p1 stores.ID) (stores.StorageInfo, error) `perm:"admin" stability:"stable"`
 
-		StorageFindSector func(p0 context.Context, p1 abi.SectorID, p2 storiface.SectorFileType, p3 abi.SectorSize, p4 bool) ([]stores.SectorStorageInfo, error) `perm:"admin"`
+		StorageList func(p0 context.Context) (map[stores.ID][]stores.Decl, error) `perm:"admin" stability:"stable"`
 
-		StorageInfo func(p0 context.Context, p1 stores.ID) (stores.StorageInfo, error) `perm:"admin"`
+		StorageLocal func(p0 context.Context) (map[stores.ID]string, error) `perm:"admin" stability:"stable"`
 
-		StorageList func(p0 context.Context) (map[stores.ID][]stores.Decl, error) `perm:"admin"`
+		StorageLock func(p0 context.Context, p1 abi.SectorID, p2 storiface.SectorFileType, p3 storiface.SectorFileType) error `perm:"admin" stability:"stable"`
 
-		StorageLocal func(p0 context.Context) (map[stores.ID]string, error) `perm:"admin"`
+		StorageReportHealth func(p0 context.Context, p1 stores.ID, p2 stores.HealthReport) error `perm:"admin" stability:"stable"`
 
-		StorageLock func(p0 context.Context, p1 abi.SectorID, p2 storiface.SectorFileType, p3 storiface.SectorFileType) error `perm:"admin"`
+		StorageStat func(p0 context.Context, p1 stores.ID) (fsutil.FsStat, error) `perm:"admin" stability:"stable"`
 
-		StorageReportHealth func(p0 context.Context, p1 stores.ID, p2 stores.HealthReport) error `perm:"admin"`
+		StorageTryLock func(p0 context.Context, p1 abi.SectorID, p2 storiface.SectorFileType, p3 storiface.SectorFileType) (bool, error) `perm:"admin" stability:"stable"`
 
-		StorageStat func(p0 context.Context, p1 stores.ID) (fsutil.FsStat, error) `perm:"admin"`
+		WdPoStProverStatus func(p0 context.Context) (WdPoStProverStatus, error) `perm:"read" stability:"experimental"`
 
-		StorageTryLock func(p0 context.Context, p1 abi.SectorID, p2 storiface.SectorFileType, p3 storiface.SectorFileType) (bool, error) `perm:"admin"`
+		WdPoStSetParallelBatches func(p0 context.Context, p1 int) error `perm:"admin" stability:"experimental"`
 
-		WorkerConnect func(p0 context.Context, p1 string) error `perm:"admin"`
+		WdPoStStatus func(p0 context.Context) (*WdPoStStatus, error) `perm:"read" stability:"experimental"`
 
-		WorkerJobs func(p0 context.Context) (map[uuid.UUID][]storiface.WorkerJob, error) `perm:"admin"`
+		WorkerConnect func(p0 context.Context, p1 string) error `perm:"admin" stability:"stable"`
 
-		WorkerStats func(p0 context.Context) (map[uuid.UUID]storiface.WorkerStats, error) `perm:"admin"`
+		WorkerJobs func(p0 context.Context) (map[uuid.UUID][]storiface.WorkerJob, error) `perm:"admin" stability:"stable"`
+
+		WorkerStats func(p0 context.Context) (map[uuid.UUID]storiface.WorkerStats, error) `perm:"admin" stability:"stable"`
 	}
 }
 
@@ -730,19 +862,19 @@ type StorageMinerStub struct {
 
 type WalletStruct struct {
 	Internal struct {
-		WalletDelete func(p0 context.Context, p1 address.Address) error ``
+		WalletDelete func(p0 context.Context, p1 address.Address) error `stability:"stable"`
 
-		WalletExport func(p0 context.Context, p1 address.Address) (*types.KeyInfo, error) ``
+		WalletExport func(p0 context.Context, p1 address.Address) (*types.KeyInfo, error) `stability:"stable"`
 
-		WalletHas func(p0 context.Context, p1 address.Address) (bool, error) ``
+		WalletHas func(p0 context.Context, p1 address.Address) (bool, error) `stability:"stable"`
 
-		WalletImport func(p0 context.Context, p1 *types.KeyInfo) (address.Address, error) ``
+		WalletImport func(p0 context.Context, p1 *types.KeyInfo) (address.Address, error) `stability:"stable"`
 
-		WalletList func(p0 context.Context) ([]address.Address, error) ``
+		WalletList func(p0 context.Context) ([]address.Address, error) `stability:"stable"`
 
-		WalletNew func(p0 context.Context, p1 types.KeyType) (address.Address, error) ``
+		WalletNew func(p0 context.Context, p1 types.KeyType) (address.Address, error) `stability:"stable"`
 
-		WalletSign func(p0 context.Context, p1 address.Address, p2 []byte, p3 MsgMeta) (*crypto.Signature, error) ``
+		WalletSign func(p0 context.Context, p1 address.Address, p2 []byte, p3 MsgMeta) (*crypto.Signature, error) `stability:"stable"`
 	}
 }
 
@@ -751,53 +883,53 @@ type WalletStub struct {
 
 type WorkerStruct struct {
 	Internal struct {
-		AddPiece func(p0 context.Context, p1 storage.SectorRef, p2 []abi.UnpaddedPieceSize, p3 abi.UnpaddedPieceSize, p4 storage.Data) (storiface.CallID, error) `perm:"admin"`
+		AddPiece func(p0 context.Context, p1 storage.SectorRef, p2 []abi.UnpaddedPieceSize, p3 abi.UnpaddedPieceSize, p4 storage.Data) (storiface.CallID, error) `perm:"admin" stability:"stable"`
 
-		Enabled func(p0 context.Context) (bool, error) `perm:"admin"`
+		Enabled func(p0 context.Context) (bool, error) `perm:"admin" stability:"stable"`
 
-		Fetch func(p0 context.Context, p1 storage.SectorRef, p2 storiface.SectorFileType, p3 storiface.PathType, p4 storiface.AcquireMode) (storiface.CallID, error) `perm:"admin"`
+		Fetch func(p0 context.Context, p1 storage.SectorRef, p2 storiface.SectorFileType, p3 storiface.PathType, p4 storiface.AcquireMode) (storiface.CallID, error) `perm:"admin" stability:"stable"`
 
-		FinalizeSector func(p0 context.Context, p1 storage.SectorRef, p2 []storage.Range) (storiface.CallID, error) `perm:"admin"`
+		FinalizeSector func(p0 context.Context, p1 storage.SectorRef, p2 []storage.Range) (storiface.CallID, error) `perm:"admin" stability:"stable"`
 
-		Info func(p0 context.Context) (storiface.WorkerInfo, error) `perm:"admin"`
+		Info func(p0 context.Context) (storiface.WorkerInfo, error) `perm:"admin" stability:"stable"`
 
-		MoveStorage func(p0 context.Context, p1 storage.SectorRef, p2 storiface.SectorFileType) (storiface.CallID, error) `perm:"admin"`
+		MoveStorage func(p0 context.Context, p1 storage.SectorRef, p2 storiface.SectorFileType) (storiface.CallID, error) `perm:"admin" stability:"stable"`
 
-		Paths func(p0 context.Context) ([]stores.StoragePath, error) `perm:"admin"`
+		Paths func(p0 context.Context) ([]stores.StoragePath, error) `perm:"admin" stability:"stable"`
 
-		ProcessSession func(p0 context.Context) (uuid.UUID, error) `perm:"admin"`
+		ProcessSession func(p0 context.Context) (uuid.UUID, error) `perm:"admin" stability:"stable"`
 
-		ReadPiece func(p0 context.Context, p1 io.Writer, p2 storage.SectorRef, p3 storiface.UnpaddedByteIndex, p4 abi.UnpaddedPieceSize) (storiface.CallID, error) `perm:"admin"`
+		ReadPiece func(p0 context.Context, p1 io.Writer, p2 storage.SectorRef, p3 storiface.UnpaddedByteIndex, p4 abi.UnpaddedPieceSize) (storiface.CallID, error) `perm:"admin" stability:"stable"`
 
-		ReleaseUnsealed func(p0 context.Context, p1 storage.SectorRef, p2 []storage.Range) (storiface.CallID, error) `perm:"admin"`
+		ReleaseUnsealed func(p0 context.Context, p1 storage.SectorRef, p2 []storage.Range) (storiface.CallID, error) `perm:"admin" stability:"stable"`
 
-		Remove func(p0 context.Context, p1 abi.SectorID) error `perm:"admin"`
+		Remove func(p0 context.Context, p1 abi.SectorID) error `perm:"admin" stability:"stable"`
 
-		SealCommit1 func(p0 context.Context, p1 storage.SectorRef, p2 abi.SealRandomness, p3 abi.InteractiveSealRandomness, p4 []abi.PieceInfo, p5 storage.SectorCids) (storiface.CallID, error) `perm:"admin"`
+		SealCommit1 func(p0 context.Context, p1 storage.SectorRef, p2 abi.SealRandomness, p3 abi.InteractiveSealRandomness, p4 []abi.PieceInfo, p5 storage.SectorCids) (storiface.CallID, error) `perm:"admin" stability:"stable"`
 
-		SealCommit2 func(p0 context.Context, p1 storage.SectorRef, p2 storage.Commit1Out) (storiface.CallID, error) `perm:"admin"`
+		SealCommit2 func(p0 context.Context, p1 storage.SectorRef, p2 storage.Commit1Out) (storiface.CallID, error) `perm:"admin" stability:"stable"`
 
-		SealPreCommit1 func(p0 context.Context, p1 storage.SectorRef, p2 abi.SealRandomness, p3 []abi.PieceInfo) (storiface.CallID, error) `perm:"admin"`
+		SealPreCommit1 func(p0 context.Context, p1 storage.SectorRef, p2 abi.SealRandomness, p3 []abi.PieceInfo) (storiface.CallID, error) `perm:"admin" stability:"stable"`
 
-		SealPreCommit2 func(p0 context.Context, p1 storage.SectorRef, p2 storage.PreCommit1Out) (storiface.CallID, error) `perm:"admin"`
+		SealPreCommit2 func(p0 context.Context, p1 storage.SectorRef, p2 storage.PreCommit1Out) (storiface.CallID, error) `perm:"admin" stability:"stable"`
 
-		Session func(p0 context.Context) (uuid.UUID, error) `perm:"admin"`
+		Session func(p0 context.Context) (uuid.UUID, error) `perm:"admin" stability:"stable"`
 
-		SetEnabled func(p0 context.Context, p1 bool) error `perm:"admin"`
+		SetEnabled func(p0 context.Context, p1 bool) error `perm:"admin" stability:"stable"`
 
-		StorageAddLocal func(p0 context.Context, p1 string) error `perm:"admin"`
+		StorageAddLocal func(p0 context.Context, p1 string) error `perm:"admin" stability:"stable"`
 
-		TaskDisable func(p0 context.Context, p1 sealtasks.TaskType) error `perm:"admin"`
+		TaskDisable func(p0 context.Context, p1 sealtasks.TaskType) error `perm:"admin" stability:"stable"`
 
-		TaskEnable func(p0 context.Context, p1 sealtasks.TaskType) error `perm:"admin"`
+		TaskEnable func(p0 context.Context, p1 sealtasks.TaskType) error `perm:"admin" stability:"stable"`
 
-		TaskTypes func(p0 context.Context) (map[sealtasks.TaskType]struct{}, error) `perm:"admin"`
+		TaskTypes func(p0 context.Context) (map[sealtasks.TaskType]struct{}, error) `perm:"admin" stability:"stable"`
 
-		UnsealPiece func(p0 context.Context, p1 storage.SectorRef, p2 storiface.UnpaddedByteIndex, p3 abi.UnpaddedPieceSize, p4 abi.SealRandomness, p5 cid.Cid) (storiface.CallID, error) `perm:"admin"`
+		UnsealPiece func(p0 context.Context, p1 storage.SectorRef, p2 storiface.UnpaddedByteIndex, p3 abi.UnpaddedPieceSize, p4 abi.SealRandomness, p5 cid.Cid) (storiface.CallID, error) `perm:"admin" stability:"stable"`
 
-		Version func(p0 context.Context) (Version, error) `perm:"admin"`
+		Version func(p0 context.Context) (Version, error) `perm:"admin" stability:"stable"`
 
-		WaitQuiet func(p0 context.Context) error `perm:"admin"`
+		WaitQuiet func(p0 context.Context) error `perm:"admin" stability:"stable"`
 	}
 }
 
@@ -844,6 +976,22 @@ func (s *CommonStub) Closing(p0 context.Context) (<-chan struct{}, error) {
 	return nil, xerrors.New("method not supported")
 }
 
+func (s *CommonStruct) ConfigHistory(p0 context.Context) ([]ConfigSnapshot, error) {
+	return s.Internal.ConfigHistory(p0)
+}
+
+func (s *CommonStub) ConfigHistory(p0 context.Context) ([]ConfigSnapshot, error) {
+	return *new([]ConfigSnapshot), xerrors.New("method not supported")
+}
+
+func (s *CommonStruct) ConfigLoaded(p0 context.Context) ([]byte, error) {
+	return s.Internal.ConfigLoaded(p0)
+}
+
+func (s *CommonStub) ConfigLoaded(p0 context.Context) ([]byte, error) {
+	return *new([]byte), xerrors.New("method not supported")
+}
+
 func (s *CommonStruct) Discover(p0 context.Context) (apitypes.OpenRPCDocument, error) {
 	return s.Internal.Discover(p0)
 }
@@ -860,6 +1008,14 @@ func (s *CommonStub) ID(p0 context.Context) (peer.ID, error) {
 	return *new(peer.ID), xerrors.New("method not supported")
 }
 
+func (s *CommonStruct) LogLevels(p0 context.Context) ([]LogSystem, error) {
+	return s.Internal.LogLevels(p0)
+}
+
+func (s *CommonStub) LogLevels(p0 context.Context) ([]LogSystem, error) {
+	return *new([]LogSystem), xerrors.New("method not supported")
+}
+
 func (s *CommonStruct) LogList(p0 context.Context) ([]string, error) {
 	return s.Internal.LogList(p0)
 }
@@ -876,6 +1032,14 @@ func (s *CommonStub) LogSetLevel(p0 context.Context, p1 string, p2 string) error
 	return xerrors.New("method not supported")
 }
 
+func (s *CommonStruct) LogSetLevels(p0 context.Context, p1 string, p2 string, p3 time.Duration) ([]string, error) {
+	return s.Internal.LogSetLevels(p0, p1, p2, p3)
+}
+
+func (s *CommonStub) LogSetLevels(p0 context.Context, p1 string, p2 string, p3 time.Duration) ([]string, error) {
+	return *new([]string), xerrors.New("method not supported")
+}
+
 func (s *CommonStruct) NetAddrsListen(p0 context.Context) (peer.AddrInfo, error) {
 	return s.Internal.NetAddrsListen(p0)
 }
@@ -964,6 +1128,14 @@ func (s *CommonStub) NetConnectedness(p0 context.Context, p1 peer.ID) (network.C
 	return *new(network.Connectedness), xerrors.New("method not supported")
 }
 
+func (s *CommonStruct) NetDialAddrs(p0 context.Context, p1 peer.AddrInfo) ([]DialResult, error) {
+	return s.Internal.NetDialAddrs(p0, p1)
+}
+
+func (s *CommonStub) NetDialAddrs(p0 context.Context, p1 peer.AddrInfo) ([]DialResult, error) {
+	return *new([]DialResult), xerrors.New("method not supported")
+}
+
 func (s *CommonStruct) NetDisconnect(p0 context.Context, p1 peer.ID) error {
 	return s.Internal.NetDisconnect(p0, p1)
 }
@@ -980,6 +1152,22 @@ func (s *CommonStub) NetFindPeer(p0 context.Context, p1 peer.ID) (peer.AddrInfo,
 	return *new(peer.AddrInfo), xerrors.New("method not supported")
 }
 
+func (s *CommonStruct) NetFindPeerDHT(p0 context.Context, p1 peer.ID) (peer.AddrInfo, error) {
+	return s.Internal.NetFindPeerDHT(p0, p1)
+}
+
+func (s *CommonStub) NetFindPeerDHT(p0 context.Context, p1 peer.ID) (peer.AddrInfo, error) {
+	return *new(peer.AddrInfo), xerrors.New("method not supported")
+}
+
+func (s *CommonStruct) NetFindProviders(p0 context.Context, p1 cid.Cid, p2 int) ([]ProviderRecord, error) {
+	return s.Internal.NetFindProviders(p0, p1, p2)
+}
+
+func (s *CommonStub) NetFindProviders(p0 context.Context, p1 cid.Cid, p2 int) ([]ProviderRecord, error) {
+	return *new([]ProviderRecord), xerrors.New("method not supported")
+}
+
 func (s *CommonStruct) NetPeerInfo(p0 context.Context, p1 peer.ID) (*ExtendedPeerInfo, error) {
 	return s.Internal.NetPeerInfo(p0, p1)
 }
@@ -1052,6 +1240,14 @@ func (s *FullNodeStub) ChainExport(p0 context.Context, p1 abi.ChainEpoch, p2 boo
 	return nil, xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) ChainFollowConsumers(p0 context.Context) ([]ChainFollowConsumer, error) {
+	return s.Internal.ChainFollowConsumers(p0)
+}
+
+func (s *FullNodeStub) ChainFollowConsumers(p0 context.Context) ([]ChainFollowConsumer, error) {
+	return *new([]ChainFollowConsumer), xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) ChainGetBlock(p0 context.Context, p1 cid.Cid) (*types.BlockHeader, error) {
 	return s.Internal.ChainGetBlock(p0, p1)
 }
@@ -1148,6 +1344,14 @@ func (s *FullNodeStub) ChainGetTipSetByHeight(p0 context.Context, p1 abi.ChainEp
 	return nil, xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) ChainGetTipSetByHeightPolicy(p0 context.Context, p1 abi.ChainEpoch, p2 NullRoundPolicy, p3 types.TipSetKey) (*types.TipSet, error) {
+	return s.Internal.ChainGetTipSetByHeightPolicy(p0, p1, p2, p3)
+}
+
+func (s *FullNodeStub) ChainGetTipSetByHeightPolicy(p0 context.Context, p1 abi.ChainEpoch, p2 NullRoundPolicy, p3 types.TipSetKey) (*types.TipSet, error) {
+	return nil, xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) ChainHasObj(p0 context.Context, p1 cid.Cid) (bool, error) {
 	return s.Internal.ChainHasObj(p0, p1)
 }
@@ -1164,6 +1368,22 @@ func (s *FullNodeStub) ChainHead(p0 context.Context) (*types.TipSet, error) {
 	return nil, xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) ChainMetricsQuery(p0 context.Context, p1 string, p2 abi.ChainEpoch, p3 abi.ChainEpoch, p4 abi.ChainEpoch) ([]MetricsPoint, error) {
+	return s.Internal.ChainMetricsQuery(p0, p1, p2, p3, p4)
+}
+
+func (s *FullNodeStub) ChainMetricsQuery(p0 context.Context, p1 string, p2 abi.ChainEpoch, p3 abi.ChainEpoch, p4 abi.ChainEpoch) ([]MetricsPoint, error) {
+	return *new([]MetricsPoint), xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) ChainMetricsSeries(p0 context.Context) ([]MetricsSeries, error) {
+	return s.Internal.ChainMetricsSeries(p0)
+}
+
+func (s *FullNodeStub) ChainMetricsSeries(p0 context.Context) ([]MetricsSeries, error) {
+	return *new([]MetricsSeries), xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) ChainNotify(p0 context.Context) (<-chan []*HeadChange, error) {
 	return s.Internal.ChainNotify(p0)
 }
@@ -1172,6 +1392,14 @@ func (s *FullNodeStub) ChainNotify(p0 context.Context) (<-chan []*HeadChange, er
 	return nil, xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) ChainPinHead(p0 context.Context, p1 time.Duration) (PinnedHead, error) {
+	return s.Internal.ChainPinHead(p0, p1)
+}
+
+func (s *FullNodeStub) ChainPinHead(p0 context.Context, p1 time.Duration) (PinnedHead, error) {
+	return *new(PinnedHead), xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) ChainReadObj(p0 context.Context, p1 cid.Cid) ([]byte, error) {
 	return s.Internal.ChainReadObj(p0, p1)
 }
@@ -1204,6 +1432,14 @@ func (s *FullNodeStub) ChainTipSetWeight(p0 context.Context, p1 types.TipSetKey)
 	return *new(types.BigInt), xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) ChainUnpinHead(p0 context.Context, p1 string) error {
+	return s.Internal.ChainUnpinHead(p0, p1)
+}
+
+func (s *FullNodeStub) ChainUnpinHead(p0 context.Context, p1 string) error {
+	return xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) ClientCalcCommP(p0 context.Context, p1 string) (*CommPRet, error) {
 	return s.Internal.ClientCalcCommP(p0, p1)
 }
@@ -1236,6 +1472,22 @@ func (s *FullNodeStub) ClientDataTransferUpdates(p0 context.Context) (<-chan Dat
 	return nil, xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) ClientDealHealth(p0 context.Context) ([]DealHealth, error) {
+	return s.Internal.ClientDealHealth(p0)
+}
+
+func (s *FullNodeStub) ClientDealHealth(p0 context.Context) ([]DealHealth, error) {
+	return *new([]DealHealth), xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) ClientDealHealthUpdates(p0 context.Context) (<-chan DealHealth, error) {
+	return s.Internal.ClientDealHealthUpdates(p0)
+}
+
+func (s *FullNodeStub) ClientDealHealthUpdates(p0 context.Context) (<-chan DealHealth, error) {
+	return nil, xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) ClientDealPieceCID(p0 context.Context, p1 cid.Cid) (DataCIDSize, error) {
 	return s.Internal.ClientDealPieceCID(p0, p1)
 }
@@ -1244,6 +1496,14 @@ func (s *FullNodeStub) ClientDealPieceCID(p0 context.Context, p1 cid.Cid) (DataC
 	return *new(DataCIDSize), xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) ClientDealSealingStatus(p0 context.Context, p1 cid.Cid) (*DealSealingStatus, error) {
+	return s.Internal.ClientDealSealingStatus(p0, p1)
+}
+
+func (s *FullNodeStub) ClientDealSealingStatus(p0 context.Context, p1 cid.Cid) (*DealSealingStatus, error) {
+	return nil, xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) ClientDealSize(p0 context.Context, p1 cid.Cid) (DataSize, error) {
 	return s.Internal.ClientDealSize(p0, p1)
 }
@@ -1364,6 +1624,30 @@ func (s *FullNodeStub) ClientRestartDataTransfer(p0 context.Context, p1 datatran
 	return xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) ClientRetrievalHistory(p0 context.Context, p1 address.Address, p2 int) ([]RetrievalRecord, error) {
+	return s.Internal.ClientRetrievalHistory(p0, p1, p2)
+}
+
+func (s *FullNodeStub) ClientRetrievalHistory(p0 context.Context, p1 address.Address, p2 int) ([]RetrievalRecord, error) {
+	return *new([]RetrievalRecord), xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) ClientRetrievalStats(p0 context.Context, p1 address.Address) ([]RetrievalProviderStats, error) {
+	return s.Internal.ClientRetrievalStats(p0, p1)
+}
+
+func (s *FullNodeStub) ClientRetrievalStats(p0 context.Context, p1 address.Address) ([]RetrievalProviderStats, error) {
+	return *new([]RetrievalProviderStats), xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) ClientRetrievalStatsImport(p0 context.Context, p1 []RetrievalRecord) (int, error) {
+	return s.Internal.ClientRetrievalStatsImport(p0, p1)
+}
+
+func (s *FullNodeStub) ClientRetrievalStatsImport(p0 context.Context, p1 []RetrievalRecord) (int, error) {
+	return 0, xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) ClientRetrieve(p0 context.Context, p1 RetrievalOrder, p2 *FileRef) error {
 	return s.Internal.ClientRetrieve(p0, p1, p2)
 }
@@ -1476,6 +1760,14 @@ func (s *FullNodeStub) MarketWithdraw(p0 context.Context, p1 address.Address, p2
 	return *new(cid.Cid), xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) Methods(p0 context.Context) (map[string]MethodStability, error) {
+	return s.Internal.Methods(p0)
+}
+
+func (s *FullNodeStub) Methods(p0 context.Context) (map[string]MethodStability, error) {
+	return *new(map[string]MethodStability), xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) MinerCreateBlock(p0 context.Context, p1 *BlockTemplate) (*types.BlockMsg, error) {
 	return s.Internal.MinerCreateBlock(p0, p1)
 }
@@ -1524,6 +1816,30 @@ func (s *FullNodeStub) MpoolClear(p0 context.Context, p1 bool) error {
 	return xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) MpoolFeeHistory(p0 context.Context) ([]FeeLevelStats, error) {
+	return s.Internal.MpoolFeeHistory(p0)
+}
+
+func (s *FullNodeStub) MpoolFeeHistory(p0 context.Context) ([]FeeLevelStats, error) {
+	return *new([]FeeLevelStats), xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) MpoolFeeLevel(p0 context.Context, p1 cid.Cid) (*FeeLevelStats, error) {
+	return s.Internal.MpoolFeeLevel(p0, p1)
+}
+
+func (s *FullNodeStub) MpoolFeeLevel(p0 context.Context, p1 cid.Cid) (*FeeLevelStats, error) {
+	return nil, xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) MpoolGasAccuracy(p0 context.Context) ([]GasAccuracyStats, error) {
+	return s.Internal.MpoolGasAccuracy(p0)
+}
+
+func (s *FullNodeStub) MpoolGasAccuracy(p0 context.Context) ([]GasAccuracyStats, error) {
+	return *new([]GasAccuracyStats), xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) MpoolGetConfig(p0 context.Context) (*types.MpoolConfig, error) {
 	return s.Internal.MpoolGetConfig(p0)
 }
@@ -1532,6 +1848,22 @@ func (s *FullNodeStub) MpoolGetConfig(p0 context.Context) (*types.MpoolConfig, e
 	return nil, xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) MpoolGetIdempotencyKey(p0 context.Context, p1 string) (*IdempotencyRecord, error) {
+	return s.Internal.MpoolGetIdempotencyKey(p0, p1)
+}
+
+func (s *FullNodeStub) MpoolGetIdempotencyKey(p0 context.Context, p1 string) (*IdempotencyRecord, error) {
+	return nil, xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) MpoolGetMemo(p0 context.Context, p1 cid.Cid) (*MessageMemo, error) {
+	return s.Internal.MpoolGetMemo(p0, p1)
+}
+
+func (s *FullNodeStub) MpoolGetMemo(p0 context.Context, p1 cid.Cid) (*MessageMemo, error) {
+	return nil, xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) MpoolGetNonce(p0 context.Context, p1 address.Address) (uint64, error) {
 	return s.Internal.MpoolGetNonce(p0, p1)
 }
@@ -1540,6 +1872,30 @@ func (s *FullNodeStub) MpoolGetNonce(p0 context.Context, p1 address.Address) (ui
 	return 0, xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) MpoolGetReplacement(p0 context.Context, p1 cid.Cid) (*MsgReplacement, error) {
+	return s.Internal.MpoolGetReplacement(p0, p1)
+}
+
+func (s *FullNodeStub) MpoolGetReplacement(p0 context.Context, p1 cid.Cid) (*MsgReplacement, error) {
+	return nil, xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) MpoolLanes(p0 context.Context) ([]MpoolLane, error) {
+	return s.Internal.MpoolLanes(p0)
+}
+
+func (s *FullNodeStub) MpoolLanes(p0 context.Context) ([]MpoolLane, error) {
+	return *new([]MpoolLane), xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) MpoolListMemos(p0 context.Context, p1 address.Address) ([]MessageMemo, error) {
+	return s.Internal.MpoolListMemos(p0, p1)
+}
+
+func (s *FullNodeStub) MpoolListMemos(p0 context.Context, p1 address.Address) ([]MessageMemo, error) {
+	return *new([]MessageMemo), xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) MpoolPending(p0 context.Context, p1 types.TipSetKey) ([]*types.SignedMessage, error) {
 	return s.Internal.MpoolPending(p0, p1)
 }
@@ -1548,6 +1904,14 @@ func (s *FullNodeStub) MpoolPending(p0 context.Context, p1 types.TipSetKey) ([]*
 	return *new([]*types.SignedMessage), xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) MpoolPropagation(p0 context.Context, p1 cid.Cid) (*MsgPropagation, error) {
+	return s.Internal.MpoolPropagation(p0, p1)
+}
+
+func (s *FullNodeStub) MpoolPropagation(p0 context.Context, p1 cid.Cid) (*MsgPropagation, error) {
+	return nil, xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) MpoolPush(p0 context.Context, p1 *types.SignedMessage) (cid.Cid, error) {
 	return s.Internal.MpoolPush(p0, p1)
 }
@@ -1588,6 +1952,14 @@ func (s *FullNodeStub) MpoolSetConfig(p0 context.Context, p1 *types.MpoolConfig)
 	return xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) MpoolSetMemo(p0 context.Context, p1 MessageMemo) error {
+	return s.Internal.MpoolSetMemo(p0, p1)
+}
+
+func (s *FullNodeStub) MpoolSetMemo(p0 context.Context, p1 MessageMemo) error {
+	return xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) MpoolSub(p0 context.Context) (<-chan MpoolUpdate, error) {
 	return s.Internal.MpoolSub(p0)
 }
@@ -1852,6 +2224,46 @@ func (s *FullNodeStub) PaychVoucherSubmit(p0 context.Context, p1 address.Address
 	return *new(cid.Cid), xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) ProjectedCall(p0 context.Context, p1 string, p2 []json.RawMessage, p3 []string) (json.RawMessage, error) {
+	return s.Internal.ProjectedCall(p0, p1, p2, p3)
+}
+
+func (s *FullNodeStub) ProjectedCall(p0 context.Context, p1 string, p2 []json.RawMessage, p3 []string) (json.RawMessage, error) {
+	return *new(json.RawMessage), xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) RPCRecordStart(p0 context.Context, p1 RPCRecordConfig) error {
+	return s.Internal.RPCRecordStart(p0, p1)
+}
+
+func (s *FullNodeStub) RPCRecordStart(p0 context.Context, p1 RPCRecordConfig) error {
+	return xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) RPCRecordStatus(p0 context.Context) (RPCRecordStatus, error) {
+	return s.Internal.RPCRecordStatus(p0)
+}
+
+func (s *FullNodeStub) RPCRecordStatus(p0 context.Context) (RPCRecordStatus, error) {
+	return *new(RPCRecordStatus), xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) RPCRecordStop(p0 context.Context) (RPCRecordStatus, error) {
+	return s.Internal.RPCRecordStop(p0)
+}
+
+func (s *FullNodeStub) RPCRecordStop(p0 context.Context) (RPCRecordStatus, error) {
+	return *new(RPCRecordStatus), xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) StateAccountBalanceDelta(p0 context.Context, p1 address.Address, p2 types.TipSetKey, p3 types.TipSetKey) (*BalanceDelta, error) {
+	return s.Internal.StateAccountBalanceDelta(p0, p1, p2, p3)
+}
+
+func (s *FullNodeStub) StateAccountBalanceDelta(p0 context.Context, p1 address.Address, p2 types.TipSetKey, p3 types.TipSetKey) (*BalanceDelta, error) {
+	return nil, xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) StateAccountKey(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) {
 	return s.Internal.StateAccountKey(p0, p1, p2)
 }
@@ -1956,6 +2368,14 @@ func (s *FullNodeStub) StateLookupID(p0 context.Context, p1 address.Address, p2
 	return *new(address.Address), xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) StateLookupRobustAddress(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) {
+	return s.Internal.StateLookupRobustAddress(p0, p1, p2)
+}
+
+func (s *FullNodeStub) StateLookupRobustAddress(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) {
+	return *new(address.Address), xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) StateMarketBalance(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (MarketBalance, error) {
 	return s.Internal.StateMarketBalance(p0, p1, p2)
 }
@@ -2012,6 +2432,14 @@ func (s *FullNodeStub) StateMinerDeadlines(p0 context.Context, p1 address.Addres
 	return *new([]Deadline), xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) StateMinerExpirationLadder(p0 context.Context, p1 address.Address, p2 float64, p3 types.TipSetKey) (*ExpirationLadder, error) {
+	return s.Internal.StateMinerExpirationLadder(p0, p1, p2, p3)
+}
+
+func (s *FullNodeStub) StateMinerExpirationLadder(p0 context.Context, p1 address.Address, p2 float64, p3 types.TipSetKey) (*ExpirationLadder, error) {
+	return nil, xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) StateMinerFaults(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (bitfield.BitField, error) {
 	return s.Internal.StateMinerFaults(p0, p1, p2)
 }
@@ -2172,6 +2600,14 @@ func (s *FullNodeStub) StateSectorPreCommitInfo(p0 context.Context, p1 address.A
 	return *new(miner.SectorPreCommitOnChainInfo), xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) StateUnwatchActor(p0 context.Context, p1 address.Address) error {
+	return s.Internal.StateUnwatchActor(p0, p1)
+}
+
+func (s *FullNodeStub) StateUnwatchActor(p0 context.Context, p1 address.Address) error {
+	return xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) StateVMCirculatingSupplyInternal(p0 context.Context, p1 types.TipSetKey) (CirculatingSupply, error) {
 	return s.Internal.StateVMCirculatingSupplyInternal(p0, p1)
 }
@@ -2212,6 +2648,22 @@ func (s *FullNodeStub) StateWaitMsg(p0 context.Context, p1 cid.Cid, p2 uint64, p
 	return nil, xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) StateWatchActor(p0 context.Context, p1 address.Address) error {
+	return s.Internal.StateWatchActor(p0, p1)
+}
+
+func (s *FullNodeStub) StateWatchActor(p0 context.Context, p1 address.Address) error {
+	return xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) StateWatchedActors(p0 context.Context) ([]WatchedActor, error) {
+	return s.Internal.StateWatchedActors(p0)
+}
+
+func (s *FullNodeStub) StateWatchedActors(p0 context.Context) ([]WatchedActor, error) {
+	return *new([]WatchedActor), xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) SyncCheckBad(p0 context.Context, p1 cid.Cid) (string, error) {
 	return s.Internal.SyncCheckBad(p0, p1)
 }
@@ -2284,6 +2736,14 @@ func (s *FullNodeStub) SyncValidateTipset(p0 context.Context, p1 types.TipSetKey
 	return false, xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) WalletAddWatch(p0 context.Context, p1 address.Address, p2 string) error {
+	return s.Internal.WalletAddWatch(p0, p1, p2)
+}
+
+func (s *FullNodeStub) WalletAddWatch(p0 context.Context, p1 address.Address, p2 string) error {
+	return xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) WalletBalance(p0 context.Context, p1 address.Address) (types.BigInt, error) {
 	return s.Internal.WalletBalance(p0, p1)
 }
@@ -2340,6 +2800,22 @@ func (s *FullNodeStub) WalletList(p0 context.Context) ([]address.Address, error)
 	return *new([]address.Address), xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) WalletListWatch(p0 context.Context) ([]WatchOnlyAddress, error) {
+	return s.Internal.WalletListWatch(p0)
+}
+
+func (s *FullNodeStub) WalletListWatch(p0 context.Context) ([]WatchOnlyAddress, error) {
+	return *new([]WatchOnlyAddress), xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) WalletLock(p0 context.Context) error {
+	return s.Internal.WalletLock(p0)
+}
+
+func (s *FullNodeStub) WalletLock(p0 context.Context) error {
+	return xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) WalletNew(p0 context.Context, p1 types.KeyType) (address.Address, error) {
 	return s.Internal.WalletNew(p0, p1)
 }
@@ -2348,6 +2824,46 @@ func (s *FullNodeStub) WalletNew(p0 context.Context, p1 types.KeyType) (address.
 	return *new(address.Address), xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) WalletPolicyGet(p0 context.Context, p1 address.Address) (*WalletUsagePolicy, error) {
+	return s.Internal.WalletPolicyGet(p0, p1)
+}
+
+func (s *FullNodeStub) WalletPolicyGet(p0 context.Context, p1 address.Address) (*WalletUsagePolicy, error) {
+	return nil, xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) WalletPolicyList(p0 context.Context) ([]WalletUsagePolicy, error) {
+	return s.Internal.WalletPolicyList(p0)
+}
+
+func (s *FullNodeStub) WalletPolicyList(p0 context.Context) ([]WalletUsagePolicy, error) {
+	return *new([]WalletUsagePolicy), xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) WalletPolicyRemove(p0 context.Context, p1 address.Address) error {
+	return s.Internal.WalletPolicyRemove(p0, p1)
+}
+
+func (s *FullNodeStub) WalletPolicyRemove(p0 context.Context, p1 address.Address) error {
+	return xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) WalletPolicySet(p0 context.Context, p1 WalletUsagePolicy) error {
+	return s.Internal.WalletPolicySet(p0, p1)
+}
+
+func (s *FullNodeStub) WalletPolicySet(p0 context.Context, p1 WalletUsagePolicy) error {
+	return xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) WalletRemoveWatch(p0 context.Context, p1 address.Address) error {
+	return s.Internal.WalletRemoveWatch(p0, p1)
+}
+
+func (s *FullNodeStub) WalletRemoveWatch(p0 context.Context, p1 address.Address) error {
+	return xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) WalletSetDefault(p0 context.Context, p1 address.Address) error {
 	return s.Internal.WalletSetDefault(p0, p1)
 }
@@ -2372,6 +2888,22 @@ func (s *FullNodeStub) WalletSignMessage(p0 context.Context, p1 address.Address,
 	return nil, xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) WalletSignMessageOverridePolicy(p0 context.Context, p1 address.Address, p2 *types.Message) (*types.SignedMessage, error) {
+	return s.Internal.WalletSignMessageOverridePolicy(p0, p1, p2)
+}
+
+func (s *FullNodeStub) WalletSignMessageOverridePolicy(p0 context.Context, p1 address.Address, p2 *types.Message) (*types.SignedMessage, error) {
+	return nil, xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) WalletUnlock(p0 context.Context, p1 string) error {
+	return s.Internal.WalletUnlock(p0, p1)
+}
+
+func (s *FullNodeStub) WalletUnlock(p0 context.Context, p1 string) error {
+	return xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) WalletValidateAddress(p0 context.Context, p1 string) (address.Address, error) {
 	return s.Internal.WalletValidateAddress(p0, p1)
 }
@@ -2492,6 +3024,14 @@ func (s *GatewayStub) MsigGetVested(p0 context.Context, p1 address.Address, p2 t
 	return *new(types.BigInt), xerrors.New("method not supported")
 }
 
+func (s *GatewayStruct) ProjectedCall(p0 context.Context, p1 string, p2 []json.RawMessage, p3 []string) (json.RawMessage, error) {
+	return s.Internal.ProjectedCall(p0, p1, p2, p3)
+}
+
+func (s *GatewayStub) ProjectedCall(p0 context.Context, p1 string, p2 []json.RawMessage, p3 []string) (json.RawMessage, error) {
+	return *new(json.RawMessage), xerrors.New("method not supported")
+}
+
 func (s *GatewayStruct) StateAccountKey(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) {
 	return s.Internal.StateAccountKey(p0, p1, p2)
 }
@@ -2620,6 +3160,14 @@ func (s *GatewayStub) WalletBalance(p0 context.Context, p1 address.Address) (typ
 	return *new(types.BigInt), xerrors.New("method not supported")
 }
 
+func (s *ProjectedCallerStruct) ProjectedCall(p0 context.Context, p1 string, p2 []json.RawMessage, p3 []string) (json.RawMessage, error) {
+	return s.Internal.ProjectedCall(p0, p1, p2, p3)
+}
+
+func (s *ProjectedCallerStub) ProjectedCall(p0 context.Context, p1 string, p2 []json.RawMessage, p3 []string) (json.RawMessage, error) {
+	return *new(json.RawMessage), xerrors.New("method not supported")
+}
+
 func (s *SignableStruct) Sign(p0 context.Context, p1 SignFunc) error {
 	return s.Internal.Sign(p0, p1)
 }
@@ -2820,6 +3368,14 @@ func (s *StorageMinerStub) MarketDataTransferUpdates(p0 context.Context) (<-chan
 	return nil, xerrors.New("method not supported")
 }
 
+func (s *StorageMinerStruct) MarketDealSealingStatus(p0 context.Context, p1 cid.Cid) (*DealSealingStatus, error) {
+	return s.Internal.MarketDealSealingStatus(p0, p1)
+}
+
+func (s *StorageMinerStub) MarketDealSealingStatus(p0 context.Context, p1 cid.Cid) (*DealSealingStatus, error) {
+	return nil, xerrors.New("method not supported")
+}
+
 func (s *StorageMinerStruct) MarketGetAsk(p0 context.Context) (*storagemarket.SignedStorageAsk, error) {
 	return s.Internal.MarketGetAsk(p0)
 }
@@ -2924,6 +3480,22 @@ func (s *StorageMinerStub) MarketSetRetrievalAsk(p0 context.Context, p1 *retriev
 	return xerrors.New("method not supported")
 }
 
+func (s *StorageMinerStruct) Methods(p0 context.Context) (map[string]MethodStability, error) {
+	return s.Internal.Methods(p0)
+}
+
+func (s *StorageMinerStub) Methods(p0 context.Context) (map[string]MethodStability, error) {
+	return *new(map[string]MethodStability), xerrors.New("method not supported")
+}
+
+func (s *StorageMinerStruct) MiningAdvanceEpochs(p0 context.Context, p1 abi.ChainEpoch, p2 bool) (abi.ChainEpoch, error) {
+	return s.Internal.MiningAdvanceEpochs(p0, p1, p2)
+}
+
+func (s *StorageMinerStub) MiningAdvanceEpochs(p0 context.Context, p1 abi.ChainEpoch, p2 bool) (abi.ChainEpoch, error) {
+	return *new(abi.ChainEpoch), xerrors.New("method not supported")
+}
+
 func (s *StorageMinerStruct) MiningBase(p0 context.Context) (*types.TipSet, error) {
 	return s.Internal.MiningBase(p0)
 }
@@ -2932,6 +3504,22 @@ func (s *StorageMinerStub) MiningBase(p0 context.Context) (*types.TipSet, error)
 	return nil, xerrors.New("method not supported")
 }
 
+func (s *StorageMinerStruct) MiningLastSimulation(p0 context.Context) (*BlockSimulation, error) {
+	return s.Internal.MiningLastSimulation(p0)
+}
+
+func (s *StorageMinerStub) MiningLastSimulation(p0 context.Context) (*BlockSimulation, error) {
+	return nil, xerrors.New("method not supported")
+}
+
+func (s *StorageMinerStruct) MiningSimulate(p0 context.Context) (*BlockSimulation, error) {
+	return s.Internal.MiningSimulate(p0)
+}
+
+func (s *StorageMinerStub) MiningSimulate(p0 context.Context) (*BlockSimulation, error) {
+	return nil, xerrors.New("method not supported")
+}
+
 func (s *StorageMinerStruct) PiecesGetCIDInfo(p0 context.Context, p1 cid.Cid) (*piecestore.CIDInfo, error) {
 	return s.Internal.PiecesGetCIDInfo(p0, p1)
 }
@@ -3092,6 +3680,14 @@ func (s *StorageMinerStub) SectorCommitPending(p0 context.Context) ([]abi.Sector
 	return *new([]abi.SectorID), xerrors.New("method not supported")
 }
 
+func (s *StorageMinerStruct) SectorExport(p0 context.Context, p1 abi.SectorNumber, p2 string) (SectorManifest, error) {
+	return s.Internal.SectorExport(p0, p1, p2)
+}
+
+func (s *StorageMinerStub) SectorExport(p0 context.Context, p1 abi.SectorNumber, p2 string) (SectorManifest, error) {
+	return *new(SectorManifest), xerrors.New("method not supported")
+}
+
 func (s *StorageMinerStruct) SectorGetExpectedSealDuration(p0 context.Context) (time.Duration, error) {
 	return s.Internal.SectorGetExpectedSealDuration(p0)
 }
@@ -3108,6 +3704,14 @@ func (s *StorageMinerStub) SectorGetSealDelay(p0 context.Context) (time.Duration
 	return *new(time.Duration), xerrors.New("method not supported")
 }
 
+func (s *StorageMinerStruct) SectorImport(p0 context.Context, p1 string, p2 bool) error {
+	return s.Internal.SectorImport(p0, p1, p2)
+}
+
+func (s *StorageMinerStub) SectorImport(p0 context.Context, p1 string, p2 bool) error {
+	return xerrors.New("method not supported")
+}
+
 func (s *StorageMinerStruct) SectorMarkForUpgrade(p0 context.Context, p1 abi.SectorNumber) error {
 	return s.Internal.SectorMarkForUpgrade(p0, p1)
 }
@@ -3340,6 +3944,30 @@ func (s *StorageMinerStub) StorageTryLock(p0 context.Context, p1 abi.SectorID, p
 	return false, xerrors.New("method not supported")
 }
 
+func (s *StorageMinerStruct) WdPoStProverStatus(p0 context.Context) (WdPoStProverStatus, error) {
+	return s.Internal.WdPoStProverStatus(p0)
+}
+
+func (s *StorageMinerStub) WdPoStProverStatus(p0 context.Context) (WdPoStProverStatus, error) {
+	return *new(WdPoStProverStatus), xerrors.New("method not supported")
+}
+
+func (s *StorageMinerStruct) WdPoStSetParallelBatches(p0 context.Context, p1 int) error {
+	return s.Internal.WdPoStSetParallelBatches(p0, p1)
+}
+
+func (s *StorageMinerStub) WdPoStSetParallelBatches(p0 context.Context, p1 int) error {
+	return xerrors.New("method not supported")
+}
+
+func (s *StorageMinerStruct) WdPoStStatus(p0 context.Context) (*WdPoStStatus, error) {
+	return s.Internal.WdPoStStatus(p0)
+}
+
+func (s *StorageMinerStub) WdPoStStatus(p0 context.Context) (*WdPoStStatus, error) {
+	return nil, xerrors.New("method not supported")
+}
+
 func (s *StorageMinerStruct) WorkerConnect(p0 context.Context, p1 string) error {
 	return s.Internal.WorkerConnect(p0, p1)
 }
@@ -3616,6 +4244,7 @@ var _ ChainIO = new(ChainIOStruct)
 var _ Common = new(CommonStruct)
 var _ FullNode = new(FullNodeStruct)
 var _ Gateway = new(GatewayStruct)
+var _ ProjectedCaller = new(ProjectedCallerStruct)
 var _ Signable = new(SignableStruct)
 var _ StorageMiner = new(StorageMinerStruct)
 var _ Wallet = new(WalletStruct)
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"reflect"
+)
+
+// Stability describes whether clients can rely on an API method
+type Stability string
+
+const (
+	StabilityStable       Stability = "stable"
+	StabilityExperimental Stability = "experimental"
+	StabilityDeprecated   Stability = "deprecated"
+)
+
+// MethodStability is the stability classification of an API method. It is
+// generated from the stability:, removal: and replacement: annotations on
+// methods, or the //stability: directive on the API interface.
+type MethodStability struct {
+	Stability Stability
+
+	// Removal is the API version a deprecated method is going away in
+	Removal string `json:",omitempty"`
+	// Replacement is the method to use instead of a deprecated method
+	Replacement string `json:",omitempty"`
+}
+
+// GetMethodStability reads the stability classification of all methods of an
+// API from the tags of its generated proxy struct, e.g. new(FullNodeStruct)
+func GetMethodStability(proxy interface{}) map[string]MethodStability {
+	out := map[string]MethodStability{}
+	readStability(reflect.TypeOf(proxy).Elem(), out)
+	return out
+}
+
+func readStability(t reflect.Type, out map[string]MethodStability) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		switch {
+		case f.Anonymous && f.Type.Kind() == reflect.Struct:
+			readStability(f.Type, out)
+		case f.Name == "Internal":
+			for j := 0; j < f.Type.NumField(); j++ {
+				m := f.Type.Field(j)
+				out[m.Name] = MethodStability{
+					Stability:   Stability(m.Tag.Get("stability")),
+					Removal:     m.Tag.Get("removal"),
+					Replacement: m.Tag.Get("replacement"),
+				}
+			}
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/filecoin-project/go-address"
 	lapi "github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/build"
 	"github.com/filecoin-project/lotus/chain/types"
 	"github.com/filecoin-project/lotus/miner"
 )
@@ -38,6 +39,20 @@ func SendFunds(ctx context.Context, t *testing.T, sender TestNode, addr address.
 	}
 }
 
+// AdvanceTime has the miner mine the epochs in the duration as fast as it
+// can, as in AdvanceTime(ctx, t, miner, time.Hour, false) to jump to an hour
+// later, and returns the epoch of the head once there. With nulls the
+// epochs before the last are null rounds, which is faster but skips the
+// proofs due in them.
+func AdvanceTime(ctx context.Context, t *testing.T, sn TestStorageNode, d time.Duration, nulls bool) abi.ChainEpoch {
+	epochs := abi.ChainEpoch(d / (time.Duration(build.BlockDelaySecs) * time.Second))
+	head, err := sn.MiningAdvanceEpochs(ctx, epochs, nulls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return head
+}
+
 func MineUntilBlock(ctx context.Context, t *testing.T, fn TestNode, sn TestStorageNode, cb func(abi.ChainEpoch)) {
 	for i := 0; i < 1000; i++ {
 		var success bool
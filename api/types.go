@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/filecoin-project/go-address"
 	datatransfer "github.com/filecoin-project/go-data-transfer"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/ipfs/go-cid"
@@ -12,6 +13,7 @@ import (
 	"github.com/libp2p/go-libp2p-core/peer"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	ma "github.com/multiformats/go-multiaddr"
+	"golang.org/x/xerrors"
 )
 
 // TODO: check if this exists anywhere else
@@ -49,6 +51,95 @@ type PubsubScore struct {
 
 type MessageSendSpec struct {
 	MaxFee abi.TokenAmount
+
+	// Reference is an operator supplied ticket or approval reference, required
+	// for sends above the SendAudit.ReferenceRequiredAbove node config value
+	Reference string `json:",omitempty"`
+
+	// IdempotencyKey makes retried pushes safe: a push with a key already
+	// used returns the message pushed the first time instead of sending
+	// again. Reusing a key for a different message is an error.
+	IdempotencyKey string `json:",omitempty"`
+}
+
+// ErrSendReferenceRequired is returned by MpoolPushMessage for sends which need
+// a reference but don't have one. Over RPC it can only be matched by message.
+var ErrSendReferenceRequired = xerrors.New("send reference required")
+
+// ErrIdempotencyKeyConflict is returned by MpoolPushMessage for pushes reusing
+// the idempotency key of a different message, wrapped in an *Error with the
+// IdempotencyKeyConflict code
+var ErrIdempotencyKeyConflict = xerrors.New("idempotency key conflict")
+
+// ErrWatchOnlyAddress is returned by signing and sending from a watch-only
+// address, wrapped in an *Error with the WatchOnlyAddress code
+var ErrWatchOnlyAddress = xerrors.New("watch-only address")
+
+// WatchOnlyAddress is an address the node tracks without holding its key
+type WatchOnlyAddress struct {
+	Address address.Address
+	Label   string `json:",omitempty"`
+}
+
+// NullRoundPolicy is how APIs taking an epoch resolve epochs without a
+// tipset, in which no block was mined
+type NullRoundPolicy string
+
+const (
+	// NullRoundPrevious resolves null rounds to the previous tipset, the
+	// default
+	NullRoundPrevious NullRoundPolicy = "previous"
+	// NullRoundNext resolves null rounds to the next tipset
+	NullRoundNext NullRoundPolicy = "next"
+	// NullRoundError fails with ErrNullRound
+	NullRoundError NullRoundPolicy = "error"
+)
+
+// ErrNullRound is returned for null rounds by APIs taking an epoch with the
+// NullRoundError policy, wrapped in an *Error with the NullRound code
+var ErrNullRound = xerrors.New("null round")
+
+// ErrWalletPolicyViolation is returned by signing with an address what its
+// usage policy doesn't allow, wrapped in an *Error with the
+// WalletPolicyViolation code
+var ErrWalletPolicyViolation = xerrors.New("wallet usage policy violation")
+
+// WalletUsagePolicy restricts what an address may sign. An address with a
+// policy may only sign the chain messages matching its rules, and other data
+// of its categories; the rules and categories of its roles included.
+type WalletUsagePolicy struct {
+	Address address.Address
+	// Roles are named sets of rules, as owner or post
+	Roles    []string            `json:",omitempty"`
+	Messages []WalletMessageRule `json:",omitempty"`
+	// Categories are the kinds of data other than chain messages the address
+	// may sign: block, dealproposal, or unknown for the raw bytes signed
+	// with WalletSign
+	Categories []MsgType `json:",omitempty"`
+}
+
+// WalletMessageRule allows messages to the methods of an actor family
+type WalletMessageRule struct {
+	// Actor is the family of the destination actor, as storageminer or
+	// account, or * for any actor
+	Actor string
+	// Methods are the allowed method numbers, any method if empty
+	Methods []abi.MethodNum `json:",omitempty"`
+}
+
+// ConfigSnapshot is the effective config of the node at a time: the config
+// file it loaded, and the settings changed at runtime, by section
+type ConfigSnapshot struct {
+	// Cid addresses the sections, identical configs have the same Cid
+	Cid  cid.Cid
+	Time time.Time
+	// Reason is "startup", or the API method which changed the config
+	Reason string
+	// Caller is who changed the config, see Caller
+	Caller string
+	// Sections are the settings, one "path = value" line each, with the
+	// secrets of the config file redacted
+	Sections map[string]string
 }
 
 type DataTransferChannel struct {
@@ -11,6 +11,7 @@ type SignFunc = func(context.Context, []byte) (*crypto.Signature, error)
 
 type Signer func(context.Context, address.Address, []byte) (*crypto.Signature, error)
 
+//stability:stable
 type Signable interface {
 	Sign(context.Context, SignFunc) error
 }
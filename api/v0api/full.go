@@ -2,6 +2,8 @@ package v0api
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-bitfield"
@@ -44,6 +46,8 @@ import (
 //  * Generate openrpc blobs
 
 // FullNode API is a low-level interface to the Filecoin network full node
+//
+//stability:stable
 type FullNode interface {
 	Common
 
@@ -96,6 +100,11 @@ type FullNode interface {
 	// If there are no blocks at the specified epoch, a tipset at an earlier epoch
 	// will be returned.
 	ChainGetTipSetByHeight(context.Context, abi.ChainEpoch, types.TipSetKey) (*types.TipSet, error) //perm:read
+	// ChainGetTipSetByHeightPolicy returns the tipset at the specified epoch,
+	// resolving epochs without blocks by the policy: "previous" returns a
+	// tipset at an earlier epoch as ChainGetTipSetByHeight, "next" one at a
+	// later epoch, and "error" fails with a NullRound error.
+	ChainGetTipSetByHeightPolicy(context.Context, abi.ChainEpoch, api.NullRoundPolicy, types.TipSetKey) (*types.TipSet, error) //perm:read stability:experimental
 
 	// ChainReadObj reads ipld nodes referenced by the specified CID from chain
 	// blockstore and returns raw bytes.
@@ -148,6 +157,30 @@ type FullNode interface {
 	// If oldmsgskip is set, messages from before the requested roots are also not included.
 	ChainExport(ctx context.Context, nroots abi.ChainEpoch, oldmsgskip bool, tsk types.TipSetKey) (<-chan []byte, error) //perm:read
 
+	// ChainFollowConsumers lists the consumers of chain follow deliveries,
+	// with the tipset each of them acknowledged last
+	ChainFollowConsumers(ctx context.Context) ([]api.ChainFollowConsumer, error) //perm:read stability:experimental
+
+	// ChainMetricsSeries lists the series recorded by the metrics history,
+	// with the epochs of their first and last points
+	ChainMetricsSeries(ctx context.Context) ([]api.MetricsSeries, error) //perm:read stability:experimental
+	// ChainMetricsQuery returns the points of a series recorded by the metrics
+	// history between the epochs from and to (the latest point when to is 0),
+	// aggregated to the resolution in epochs, or to the resolution they are
+	// kept at when it's coarser or 0. Periods without samples are returned as
+	// points without samples rather than interpolated.
+	ChainMetricsQuery(ctx context.Context, series string, from, to, resolution abi.ChainEpoch) ([]api.MetricsPoint, error) //perm:read stability:experimental
+
+	// ChainPinHead pins the current head for a session, so that a series of
+	// reads doesn't straddle a head change. Calls made with the session in
+	// the PinnedHeadHeader HTTP header resolve the current head, that is
+	// ChainHead and empty tipset keys, to the pinned tipset, whose state is
+	// kept by splitstore compaction. The session expires after ttl, at most
+	// 10 minutes, failing the calls still using it.
+	ChainPinHead(ctx context.Context, ttl time.Duration) (api.PinnedHead, error) //perm:read stability:experimental
+	// ChainUnpinHead ends a session of ChainPinHead
+	ChainUnpinHead(ctx context.Context, session string) error //perm:read stability:experimental
+
 	// MethodGroup: Beacon
 	// The Beacon method group contains methods for interacting with the random beacon (DRAND)
 
@@ -213,6 +246,43 @@ type FullNode interface {
 
 	// MpoolPending returns pending mempool messages.
 	MpoolPending(context.Context, types.TipSetKey) ([]*types.SignedMessage, error) //perm:read
+	// MpoolGetReplacement returns the message which replaced the given message
+	// in the mempool, or nil if the node didn't see the message being replaced.
+	MpoolGetReplacement(context.Context, cid.Cid) (*api.MsgReplacement, error) //perm:read stability:experimental
+	// MpoolGetIdempotencyKey returns the message MpoolPushMessage pushed with
+	// the idempotency key, or nil if the key wasn't used within the retention
+	// window of the mpool config
+	MpoolGetIdempotencyKey(ctx context.Context, key string) (*api.IdempotencyRecord, error) //perm:read stability:experimental
+	// MpoolGetMemo returns the memo kept for the message, or nil if there is
+	// none. Memos are local to the node, they never go on chain.
+	MpoolGetMemo(context.Context, cid.Cid) (*api.MessageMemo, error) //perm:read stability:experimental
+	// MpoolSetMemo keeps the memo for its message, replacing any previous one.
+	// An empty memo deletes it. From and To are looked up from the message if
+	// not set.
+	MpoolSetMemo(context.Context, api.MessageMemo) error //perm:write stability:experimental
+	// MpoolListMemos returns the memos of the messages from or to the address,
+	// or all the memos if the address is undef, oldest first
+	MpoolListMemos(context.Context, address.Address) ([]api.MessageMemo, error) //perm:read stability:experimental
+	// MpoolFeeHistory returns how fast the messages pushed by MpoolPushMessage
+	// landed, by fee level. It is only kept with FeeHistory set in the
+	// mpool config.
+	MpoolFeeHistory(context.Context) ([]api.FeeLevelStats, error) //perm:read stability:experimental
+	// MpoolFeeLevel returns the fee history of the level the message was
+	// pushed at, or nil if it wasn't recorded or the level has too little
+	// history
+	MpoolFeeLevel(context.Context, cid.Cid) (*api.FeeLevelStats, error) //perm:read stability:experimental
+	// MpoolGasAccuracy compares the gas used by the local messages pushed by
+	// MpoolPushMessage over the last week with their gas estimate, by
+	// destination actor family and method, and counts the ones which ran out
+	// of gas
+	MpoolGasAccuracy(context.Context) ([]api.GasAccuracyStats, error) //perm:read stability:experimental
+	// MpoolLanes returns the occupancy of the message pool lanes: the
+	// protected lane, of the local and priority addresses, which pruning
+	// never evicts up to its capacity, and the remote lane
+	MpoolLanes(context.Context) ([]api.MpoolLane, error) //perm:read stability:experimental
+	// MpoolPropagation returns what the node observed of the gossip of a
+	// message it published, with the standing of the message in the mempool
+	MpoolPropagation(context.Context, cid.Cid) (*api.MsgPropagation, error) //perm:read stability:experimental
 
 	// MpoolSelect returns a list of pending messages for inclusion in the next block
 	MpoolSelect(context.Context, types.TipSetKey, float64) ([]*types.SignedMessage, error) //perm:read
@@ -291,6 +361,35 @@ type FullNode interface {
 	WalletDelete(context.Context, address.Address) error //perm:admin
 	// WalletValidateAddress validates whether a given string can be decoded as a well-formed address
 	WalletValidateAddress(context.Context, string) (address.Address, error) //perm:read
+	// WalletAddWatch adds a watch-only address, which is listed and tracked
+	// like the wallet addresses but can't sign: signing and sending from it
+	// fail with ErrWatchOnlyAddress. It is kept in the metadata store, not the
+	// keystore.
+	WalletAddWatch(ctx context.Context, addr address.Address, label string) error //perm:write stability:experimental
+	// WalletRemoveWatch removes a watch-only address
+	WalletRemoveWatch(context.Context, address.Address) error //perm:write stability:experimental
+	// WalletListWatch lists the watch-only addresses
+	WalletListWatch(context.Context) ([]api.WatchOnlyAddress, error) //perm:read stability:experimental
+	// WalletPolicySet sets the usage policy of an address, which restricts the
+	// chain messages and other data it may sign. Signing what the policy
+	// doesn't allow fails with ErrWalletPolicyViolation.
+	WalletPolicySet(context.Context, api.WalletUsagePolicy) error //perm:admin stability:experimental
+	// WalletPolicyGet returns the usage policy of an address, nil if it has none
+	WalletPolicyGet(context.Context, address.Address) (*api.WalletUsagePolicy, error) //perm:read stability:experimental
+	// WalletPolicyList lists the usage policies of the addresses which have one
+	WalletPolicyList(context.Context) ([]api.WalletUsagePolicy, error) //perm:read stability:experimental
+	// WalletPolicyRemove removes the usage policy of an address, which may then
+	// sign anything
+	WalletPolicyRemove(context.Context, address.Address) error //perm:admin stability:experimental
+	// WalletSignMessageOverridePolicy signs the message like WalletSignMessage,
+	// ignoring the usage policy of the address
+	WalletSignMessageOverridePolicy(context.Context, address.Address, *types.Message) (*types.SignedMessage, error) //perm:admin stability:experimental
+	// WalletUnlock unlocks an encrypted keystore with the given passphrase,
+	// enabling signing with the keys in it.
+	WalletUnlock(ctx context.Context, passphrase string) error //perm:admin stability:experimental
+	// WalletLock locks an encrypted keystore. Signing with keys in it fails
+	// until it's unlocked again.
+	WalletLock(context.Context) error //perm:admin stability:experimental
 
 	// Other
 
@@ -310,6 +409,15 @@ type FullNode interface {
 	ClientListDeals(ctx context.Context) ([]api.DealInfo, error) //perm:write
 	// ClientGetDealUpdates returns the status of updated deals
 	ClientGetDealUpdates(ctx context.Context) (<-chan api.DealInfo, error) //perm:write
+	// ClientDealHealth returns the state of the sectors holding the active
+	// deals made by the local client
+	ClientDealHealth(ctx context.Context) ([]api.DealHealth, error) //perm:write stability:experimental
+	// ClientDealHealthUpdates returns the health of active client deals
+	// whenever it changes, e.g. when the provider terminates a sector
+	ClientDealHealthUpdates(ctx context.Context) (<-chan api.DealHealth, error) //perm:write stability:experimental
+	// ClientDealSealingStatus asks the provider of a deal where it is in the
+	// sealing pipeline, and when it is expected to activate
+	ClientDealSealingStatus(ctx context.Context, proposal cid.Cid) (*api.DealSealingStatus, error) //perm:read stability:experimental
 	// ClientGetDealStatus returns status given a code
 	ClientGetDealStatus(ctx context.Context, statusCode uint64) (string, error) //perm:read
 	// ClientHasLocal indicates whether a certain CID is locally stored.
@@ -347,6 +455,19 @@ type FullNode interface {
 	// ClientCancelRetrievalDeal cancels an ongoing retrieval deal based on DealID
 	ClientCancelRetrievalDeal(ctx context.Context, dealid retrievalmarket.DealID) error //perm:write
 
+	// ClientRetrievalStats returns the retrieval performance of the provider,
+	// or of each provider when it's undefined, aggregated from the records
+	// of the retrievals made by the client which are still retained
+	ClientRetrievalStats(ctx context.Context, provider address.Address) ([]api.RetrievalProviderStats, error) //perm:write stability:experimental
+	// ClientRetrievalHistory returns the records of the retrievals made from
+	// the provider, or from all providers when it's undefined, newest first,
+	// at most limit of them when it's positive
+	ClientRetrievalHistory(ctx context.Context, provider address.Address, limit int) ([]api.RetrievalRecord, error) //perm:write stability:experimental
+	// ClientRetrievalStatsImport adds retrieval records, as returned by
+	// ClientRetrievalHistory on another node, skipping the ones already
+	// recorded or past the retention, and returns the number added
+	ClientRetrievalStatsImport(ctx context.Context, records []api.RetrievalRecord) (int, error) //perm:write stability:experimental
+
 	// ClientUnimport removes references to the specified file from filestore
 	//ClientUnimport(path string)
 
@@ -386,10 +507,27 @@ type FullNode interface {
 	StateReplay(context.Context, types.TipSetKey, cid.Cid) (*api.InvocResult, error) //perm:read
 	// StateGetActor returns the indicated actor's nonce and balance.
 	StateGetActor(ctx context.Context, actor address.Address, tsk types.TipSetKey) (*types.Actor, error) //perm:read
+	// StateWatchActor adds an actor to the watched actor cache. The state of
+	// watched actors (and the info of watched miners) is resolved on every head
+	// change, so that StateGetActor and StateMinerInfo calls at head are
+	// served from memory. Actors added with this call are not persisted, use
+	// the WatchedActors.Addresses config to watch actors across restarts.
+	StateWatchActor(ctx context.Context, actor address.Address) error //perm:write stability:experimental
+	// StateUnwatchActor removes an actor from the watched actor cache
+	StateUnwatchActor(ctx context.Context, actor address.Address) error //perm:write stability:experimental
+	// StateWatchedActors lists the actors in the watched actor cache
+	StateWatchedActors(ctx context.Context) ([]api.WatchedActor, error) //perm:read stability:experimental
 	// StateReadState returns the indicated actor's state.
 	StateReadState(ctx context.Context, actor address.Address, tsk types.TipSetKey) (*api.ActorState, error) //perm:read
 	// StateListMessages looks back and returns all messages with a matching to or from address, stopping at the given height.
 	StateListMessages(ctx context.Context, match *api.MessageMatch, tsk types.TipSetKey, toht abi.ChainEpoch) ([]cid.Cid, error) //perm:read
+	// StateAccountBalanceDelta attributes the change of the balance of the
+	// actor between the from and to tipsets, to at the head when it's empty,
+	// to the transfers, gas, rewards and burns which made it, replaying the
+	// execution of the tipsets in between. At most BalanceDeltaMaxEpochs are
+	// covered per call; when the range is longer, the result ends earlier and
+	// isn't Complete, and the rest is fetched with a call from its To.
+	StateAccountBalanceDelta(ctx context.Context, addr address.Address, from, to types.TipSetKey) (*api.BalanceDelta, error) //perm:read stability:experimental
 	// StateDecodeParams attempts to decode the provided params, based on the recipient actor address and method number.
 	StateDecodeParams(ctx context.Context, toAddr address.Address, method abi.MethodNum, params []byte, tsk types.TipSetKey) (interface{}, error) //perm:read
 
@@ -399,6 +537,10 @@ type FullNode interface {
 	StateMinerSectors(context.Context, address.Address, *bitfield.BitField, types.TipSetKey) ([]*miner.SectorOnChainInfo, error) //perm:read
 	// StateMinerActiveSectors returns info about sectors that a given miner is actively proving.
 	StateMinerActiveSectors(context.Context, address.Address, types.TipSetKey) ([]*miner.SectorOnChainInfo, error) //perm:read
+	// StateMinerExpirationLadder aggregates the live sectors of a miner by
+	// expiration month, flagging months holding more than maxShare (0-1) of
+	// the live sectors, and suggests sector extensions spreading them out
+	StateMinerExpirationLadder(ctx context.Context, maddr address.Address, maxShare float64, tsk types.TipSetKey) (*api.ExpirationLadder, error) //perm:read stability:experimental
 	// StateMinerProvingDeadline calculates the deadline at some epoch for a proving period
 	// and returns the deadline-related calculations.
 	StateMinerProvingDeadline(context.Context, address.Address, types.TipSetKey) (*dline.Info, error) //perm:read
@@ -517,6 +659,10 @@ type FullNode interface {
 	StateLookupID(context.Context, address.Address, types.TipSetKey) (address.Address, error) //perm:read
 	// StateAccountKey returns the public key address of the given ID address
 	StateAccountKey(context.Context, address.Address, types.TipSetKey) (address.Address, error) //perm:read
+	// StateLookupRobustAddress returns the robust (non-ID) address of the given
+	// ID address. For actors other than accounts this scans the init actor's
+	// address map, so it is slow.
+	StateLookupRobustAddress(context.Context, address.Address, types.TipSetKey) (address.Address, error) //perm:read stability:experimental
 	// StateChangedActors returns all the actors whose states change between the two given state CIDs
 	// TODO: Should this take tipset keys instead?
 	StateChangedActors(context.Context, cid.Cid, cid.Cid) (map[string]types.Actor, error) //perm:read
@@ -529,7 +675,7 @@ type FullNode interface {
 	// is matching the requested CID
 	//
 	// DEPRECATED: Use StateSearchMsg, this method won't be supported in v1 API
-	StateGetReceipt(context.Context, cid.Cid, types.TipSetKey) (*types.MessageReceipt, error) //perm:read
+	StateGetReceipt(context.Context, cid.Cid, types.TipSetKey) (*types.MessageReceipt, error) //perm:read stability:deprecated removal:v1 replacement:StateSearchMsg
 	// StateMinerSectorCount returns the number of sectors in a miner's sector set and proving set
 	StateMinerSectorCount(context.Context, address.Address, types.TipSetKey) (api.MinerSectors, error) //perm:read
 	// StateCompute is a flexible command that applies the given messages on the given tipset.
@@ -698,4 +844,28 @@ type FullNode interface {
 	// LOTUS_BACKUP_BASE_PATH environment variable set to some path, and that
 	// the path specified when calling CreateBackup is within the base path
 	CreateBackup(ctx context.Context, fpath string) error //perm:admin
+	// Methods returns the stability classification of every method of this
+	// API: whether it is stable, experimental or deprecated, and for deprecated
+	// methods the API version they will be removed in and their replacement.
+	Methods(context.Context) (map[string]api.MethodStability, error) //perm:read stability:experimental
+	// RPCRecordStart starts recording the API calls served by the node to a
+	// file on the node, appending a line of JSON per call with its params,
+	// result and timing, for `lotus-shed rpc replay`. Secrets, such as the
+	// tokens and private keys passed to or returned by the API, are redacted.
+	// The recording can be limited to the calls made with a token, by its
+	// fingerprint, and to some methods, and stops once the file grows by
+	// MaxBytes.
+	RPCRecordStart(ctx context.Context, cfg api.RPCRecordConfig) error //perm:admin stability:experimental
+	// RPCRecordStop stops the recording, returning its final status
+	RPCRecordStop(ctx context.Context) (api.RPCRecordStatus, error) //perm:admin stability:experimental
+	// RPCRecordStatus returns the status of the active recording, or of the
+	// last one
+	RPCRecordStatus(ctx context.Context) (api.RPCRecordStatus, error) //perm:admin stability:experimental
+	// ProjectedCall calls one of the heavy read methods listed in
+	// api.ProjectableMethods, as StateMinerSectors, with the JSON-encoded
+	// params, and returns only the given fields of its result. Fields are
+	// paths of JSON field names as 'Info.SealProof', those of lists and maps
+	// being the fields of their elements; unknown fields fail listing the
+	// available ones. api.CallWithProjection wraps it for Go clients.
+	ProjectedCall(ctx context.Context, method string, params []json.RawMessage, fields []string) (json.RawMessage, error) //perm:read stability:experimental
 }
@@ -2,6 +2,7 @@ package v0api
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/ipfs/go-cid"
 
@@ -32,6 +33,7 @@ import (
 //  * Generate markdown docs
 //  * Generate openrpc blobs
 
+//stability:stable
 type Gateway interface {
 	ChainHasObj(context.Context, cid.Cid) (bool, error)
 	ChainHead(ctx context.Context) (*types.TipSet, error)
@@ -62,6 +64,7 @@ type Gateway interface {
 	StateSectorGetInfo(ctx context.Context, maddr address.Address, n abi.SectorNumber, tsk types.TipSetKey) (*miner.SectorOnChainInfo, error)
 	StateVerifiedClientStatus(ctx context.Context, addr address.Address, tsk types.TipSetKey) (*abi.StoragePower, error)
 	StateWaitMsg(ctx context.Context, msg cid.Cid, confidence uint64) (*api.MsgLookup, error)
+	ProjectedCall(ctx context.Context, method string, params []json.RawMessage, fields []string) (json.RawMessage, error)
 	WalletBalance(context.Context, address.Address) (types.BigInt, error)
 }
 
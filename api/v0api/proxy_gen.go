@@ -4,6 +4,8 @@ package v0api
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-bitfield"
@@ -31,351 +33,437 @@ type FullNodeStruct struct {
 	CommonStruct
 
 	Internal struct {
-		BeaconGetEntry func(p0 context.Context, p1 abi.ChainEpoch) (*types.BeaconEntry, error) `perm:"read"`
+		BeaconGetEntry func(p0 context.Context, p1 abi.ChainEpoch) (*types.BeaconEntry, error) `perm:"read" stability:"stable"`
 
-		ChainDeleteObj func(p0 context.Context, p1 cid.Cid) error `perm:"admin"`
+		ChainDeleteObj func(p0 context.Context, p1 cid.Cid) error `perm:"admin" stability:"stable"`
 
-		ChainExport func(p0 context.Context, p1 abi.ChainEpoch, p2 bool, p3 types.TipSetKey) (<-chan []byte, error) `perm:"read"`
+		ChainExport func(p0 context.Context, p1 abi.ChainEpoch, p2 bool, p3 types.TipSetKey) (<-chan []byte, error) `perm:"read" stability:"stable"`
 
-		ChainGetBlock func(p0 context.Context, p1 cid.Cid) (*types.BlockHeader, error) `perm:"read"`
+		ChainFollowConsumers func(p0 context.Context) ([]api.ChainFollowConsumer, error) `perm:"read" stability:"experimental"`
 
-		ChainGetBlockMessages func(p0 context.Context, p1 cid.Cid) (*api.BlockMessages, error) `perm:"read"`
+		ChainGetBlock func(p0 context.Context, p1 cid.Cid) (*types.BlockHeader, error) `perm:"read" stability:"stable"`
 
-		ChainGetGenesis func(p0 context.Context) (*types.TipSet, error) `perm:"read"`
+		ChainGetBlockMessages func(p0 context.Context, p1 cid.Cid) (*api.BlockMessages, error) `perm:"read" stability:"stable"`
 
-		ChainGetMessage func(p0 context.Context, p1 cid.Cid) (*types.Message, error) `perm:"read"`
+		ChainGetGenesis func(p0 context.Context) (*types.TipSet, error) `perm:"read" stability:"stable"`
 
-		ChainGetNode func(p0 context.Context, p1 string) (*api.IpldObject, error) `perm:"read"`
+		ChainGetMessage func(p0 context.Context, p1 cid.Cid) (*types.Message, error) `perm:"read" stability:"stable"`
 
-		ChainGetParentMessages func(p0 context.Context, p1 cid.Cid) ([]api.Message, error) `perm:"read"`
+		ChainGetNode func(p0 context.Context, p1 string) (*api.IpldObject, error) `perm:"read" stability:"stable"`
 
-		ChainGetParentReceipts func(p0 context.Context, p1 cid.Cid) ([]*types.MessageReceipt, error) `perm:"read"`
+		ChainGetParentMessages func(p0 context.Context, p1 cid.Cid) ([]api.Message, error) `perm:"read" stability:"stable"`
 
-		ChainGetPath func(p0 context.Context, p1 types.TipSetKey, p2 types.TipSetKey) ([]*api.HeadChange, error) `perm:"read"`
+		ChainGetParentReceipts func(p0 context.Context, p1 cid.Cid) ([]*types.MessageReceipt, error) `perm:"read" stability:"stable"`
 
-		ChainGetRandomnessFromBeacon func(p0 context.Context, p1 types.TipSetKey, p2 crypto.DomainSeparationTag, p3 abi.ChainEpoch, p4 []byte) (abi.Randomness, error) `perm:"read"`
+		ChainGetPath func(p0 context.Context, p1 types.TipSetKey, p2 types.TipSetKey) ([]*api.HeadChange, error) `perm:"read" stability:"stable"`
 
-		ChainGetRandomnessFromTickets func(p0 context.Context, p1 types.TipSetKey, p2 crypto.DomainSeparationTag, p3 abi.ChainEpoch, p4 []byte) (abi.Randomness, error) `perm:"read"`
+		ChainGetRandomnessFromBeacon func(p0 context.Context, p1 types.TipSetKey, p2 crypto.DomainSeparationTag, p3 abi.ChainEpoch, p4 []byte) (abi.Randomness, error) `perm:"read" stability:"stable"`
 
-		ChainGetTipSet func(p0 context.Context, p1 types.TipSetKey) (*types.TipSet, error) `perm:"read"`
+		ChainGetRandomnessFromTickets func(p0 context.Context, p1 types.TipSetKey, p2 crypto.DomainSeparationTag, p3 abi.ChainEpoch, p4 []byte) (abi.Randomness, error) `perm:"read" stability:"stable"`
 
-		ChainGetTipSetByHeight func(p0 context.Context, p1 abi.ChainEpoch, p2 types.TipSetKey) (*types.TipSet, error) `perm:"read"`
+		ChainGetTipSet func(p0 context.Context, p1 types.TipSetKey) (*types.TipSet, error) `perm:"read" stability:"stable"`
 
-		ChainHasObj func(p0 context.Context, p1 cid.Cid) (bool, error) `perm:"read"`
+		ChainGetTipSetByHeight func(p0 context.Context, p1 abi.ChainEpoch, p2 types.TipSetKey) (*types.TipSet, error) `perm:"read" stability:"stable"`
 
-		ChainHead func(p0 context.Context) (*types.TipSet, error) `perm:"read"`
+		ChainGetTipSetByHeightPolicy func(p0 context.Context, p1 abi.ChainEpoch, p2 api.NullRoundPolicy, p3 types.TipSetKey) (*types.TipSet, error) `perm:"read" stability:"experimental"`
 
-		ChainNotify func(p0 context.Context) (<-chan []*api.HeadChange, error) `perm:"read"`
+		ChainHasObj func(p0 context.Context, p1 cid.Cid) (bool, error) `perm:"read" stability:"stable"`
 
-		ChainReadObj func(p0 context.Context, p1 cid.Cid) ([]byte, error) `perm:"read"`
+		ChainHead func(p0 context.Context) (*types.TipSet, error) `perm:"read" stability:"stable"`
 
-		ChainSetHead func(p0 context.Context, p1 types.TipSetKey) error `perm:"admin"`
+		ChainMetricsQuery func(p0 context.Context, p1 string, p2 abi.ChainEpoch, p3 abi.ChainEpoch, p4 abi.ChainEpoch) ([]api.MetricsPoint, error) `perm:"read" stability:"experimental"`
 
-		ChainStatObj func(p0 context.Context, p1 cid.Cid, p2 cid.Cid) (api.ObjStat, error) `perm:"read"`
+		ChainMetricsSeries func(p0 context.Context) ([]api.MetricsSeries, error) `perm:"read" stability:"experimental"`
 
-		ChainTipSetWeight func(p0 context.Context, p1 types.TipSetKey) (types.BigInt, error) `perm:"read"`
+		ChainNotify func(p0 context.Context) (<-chan []*api.HeadChange, error) `perm:"read" stability:"stable"`
 
-		ClientCalcCommP func(p0 context.Context, p1 string) (*api.CommPRet, error) `perm:"write"`
+		ChainPinHead func(p0 context.Context, p1 time.Duration) (api.PinnedHead, error) `perm:"read" stability:"experimental"`
 
-		ClientCancelDataTransfer func(p0 context.Context, p1 datatransfer.TransferID, p2 peer.ID, p3 bool) error `perm:"write"`
+		ChainReadObj func(p0 context.Context, p1 cid.Cid) ([]byte, error) `perm:"read" stability:"stable"`
 
-		ClientCancelRetrievalDeal func(p0 context.Context, p1 retrievalmarket.DealID) error `perm:"write"`
+		ChainSetHead func(p0 context.Context, p1 types.TipSetKey) error `perm:"admin" stability:"stable"`
 
-		ClientDataTransferUpdates func(p0 context.Context) (<-chan api.DataTransferChannel, error) `perm:"write"`
+		ChainStatObj func(p0 context.Context, p1 cid.Cid, p2 cid.Cid) (api.ObjStat, error) `perm:"read" stability:"stable"`
 
-		ClientDealPieceCID func(p0 context.Context, p1 cid.Cid) (api.DataCIDSize, error) `perm:"read"`
+		ChainTipSetWeight func(p0 context.Context, p1 types.TipSetKey) (types.BigInt, error) `perm:"read" stability:"stable"`
 
-		ClientDealSize func(p0 context.Context, p1 cid.Cid) (api.DataSize, error) `perm:"read"`
+		ChainUnpinHead func(p0 context.Context, p1 string) error `perm:"read" stability:"experimental"`
 
-		ClientFindData func(p0 context.Context, p1 cid.Cid, p2 *cid.Cid) ([]api.QueryOffer, error) `perm:"read"`
+		ClientCalcCommP func(p0 context.Context, p1 string) (*api.CommPRet, error) `perm:"write" stability:"stable"`
 
-		ClientGenCar func(p0 context.Context, p1 api.FileRef, p2 string) error `perm:"write"`
+		ClientCancelDataTransfer func(p0 context.Context, p1 datatransfer.TransferID, p2 peer.ID, p3 bool) error `perm:"write" stability:"stable"`
 
-		ClientGetDealInfo func(p0 context.Context, p1 cid.Cid) (*api.DealInfo, error) `perm:"read"`
+		ClientCancelRetrievalDeal func(p0 context.Context, p1 retrievalmarket.DealID) error `perm:"write" stability:"stable"`
 
-		ClientGetDealStatus func(p0 context.Context, p1 uint64) (string, error) `perm:"read"`
+		ClientDataTransferUpdates func(p0 context.Context) (<-chan api.DataTransferChannel, error) `perm:"write" stability:"stable"`
 
-		ClientGetDealUpdates func(p0 context.Context) (<-chan api.DealInfo, error) `perm:"write"`
+		ClientDealHealth func(p0 context.Context) ([]api.DealHealth, error) `perm:"write" stability:"experimental"`
 
-		ClientHasLocal func(p0 context.Context, p1 cid.Cid) (bool, error) `perm:"write"`
+		ClientDealHealthUpdates func(p0 context.Context) (<-chan api.DealHealth, error) `perm:"write" stability:"experimental"`
 
-		ClientImport func(p0 context.Context, p1 api.FileRef) (*api.ImportRes, error) `perm:"admin"`
+		ClientDealPieceCID func(p0 context.Context, p1 cid.Cid) (api.DataCIDSize, error) `perm:"read" stability:"stable"`
 
-		ClientListDataTransfers func(p0 context.Context) ([]api.DataTransferChannel, error) `perm:"write"`
+		ClientDealSealingStatus func(p0 context.Context, p1 cid.Cid) (*api.DealSealingStatus, error) `perm:"read" stability:"experimental"`
 
-		ClientListDeals func(p0 context.Context) ([]api.DealInfo, error) `perm:"write"`
+		ClientDealSize func(p0 context.Context, p1 cid.Cid) (api.DataSize, error) `perm:"read" stability:"stable"`
 
-		ClientListImports func(p0 context.Context) ([]api.Import, error) `perm:"write"`
+		ClientFindData func(p0 context.Context, p1 cid.Cid, p2 *cid.Cid) ([]api.QueryOffer, error) `perm:"read" stability:"stable"`
 
-		ClientMinerQueryOffer func(p0 context.Context, p1 address.Address, p2 cid.Cid, p3 *cid.Cid) (api.QueryOffer, error) `perm:"read"`
+		ClientGenCar func(p0 context.Context, p1 api.FileRef, p2 string) error `perm:"write" stability:"stable"`
 
-		ClientQueryAsk func(p0 context.Context, p1 peer.ID, p2 address.Address) (*storagemarket.StorageAsk, error) `perm:"read"`
+		ClientGetDealInfo func(p0 context.Context, p1 cid.Cid) (*api.DealInfo, error) `perm:"read" stability:"stable"`
 
-		ClientRemoveImport func(p0 context.Context, p1 multistore.StoreID) error `perm:"admin"`
+		ClientGetDealStatus func(p0 context.Context, p1 uint64) (string, error) `perm:"read" stability:"stable"`
 
-		ClientRestartDataTransfer func(p0 context.Context, p1 datatransfer.TransferID, p2 peer.ID, p3 bool) error `perm:"write"`
+		ClientGetDealUpdates func(p0 context.Context) (<-chan api.DealInfo, error) `perm:"write" stability:"stable"`
 
-		ClientRetrieve func(p0 context.Context, p1 api.RetrievalOrder, p2 *api.FileRef) error `perm:"admin"`
+		ClientHasLocal func(p0 context.Context, p1 cid.Cid) (bool, error) `perm:"write" stability:"stable"`
 
-		ClientRetrieveTryRestartInsufficientFunds func(p0 context.Context, p1 address.Address) error `perm:"write"`
+		ClientImport func(p0 context.Context, p1 api.FileRef) (*api.ImportRes, error) `perm:"admin" stability:"stable"`
 
-		ClientRetrieveWithEvents func(p0 context.Context, p1 api.RetrievalOrder, p2 *api.FileRef) (<-chan marketevents.RetrievalEvent, error) `perm:"admin"`
+		ClientListDataTransfers func(p0 context.Context) ([]api.DataTransferChannel, error) `perm:"write" stability:"stable"`
 
-		ClientStartDeal func(p0 context.Context, p1 *api.StartDealParams) (*cid.Cid, error) `perm:"admin"`
+		ClientListDeals func(p0 context.Context) ([]api.DealInfo, error) `perm:"write" stability:"stable"`
 
-		CreateBackup func(p0 context.Context, p1 string) error `perm:"admin"`
+		ClientListImports func(p0 context.Context) ([]api.Import, error) `perm:"write" stability:"stable"`
 
-		GasEstimateFeeCap func(p0 context.Context, p1 *types.Message, p2 int64, p3 types.TipSetKey) (types.BigInt, error) `perm:"read"`
+		ClientMinerQueryOffer func(p0 context.Context, p1 address.Address, p2 cid.Cid, p3 *cid.Cid) (api.QueryOffer, error) `perm:"read" stability:"stable"`
 
-		GasEstimateGasLimit func(p0 context.Context, p1 *types.Message, p2 types.TipSetKey) (int64, error) `perm:"read"`
+		ClientQueryAsk func(p0 context.Context, p1 peer.ID, p2 address.Address) (*storagemarket.StorageAsk, error) `perm:"read" stability:"stable"`
 
-		GasEstimateGasPremium func(p0 context.Context, p1 uint64, p2 address.Address, p3 int64, p4 types.TipSetKey) (types.BigInt, error) `perm:"read"`
+		ClientRemoveImport func(p0 context.Context, p1 multistore.StoreID) error `perm:"admin" stability:"stable"`
 
-		GasEstimateMessageGas func(p0 context.Context, p1 *types.Message, p2 *api.MessageSendSpec, p3 types.TipSetKey) (*types.Message, error) `perm:"read"`
+		ClientRestartDataTransfer func(p0 context.Context, p1 datatransfer.TransferID, p2 peer.ID, p3 bool) error `perm:"write" stability:"stable"`
 
-		MarketAddBalance func(p0 context.Context, p1 address.Address, p2 address.Address, p3 types.BigInt) (cid.Cid, error) `perm:"sign"`
+		ClientRetrievalHistory func(p0 context.Context, p1 address.Address, p2 int) ([]api.RetrievalRecord, error) `perm:"write" stability:"experimental"`
 
-		MarketGetReserved func(p0 context.Context, p1 address.Address) (types.BigInt, error) `perm:"sign"`
+		ClientRetrievalStats func(p0 context.Context, p1 address.Address) ([]api.RetrievalProviderStats, error) `perm:"write" stability:"experimental"`
 
-		MarketReleaseFunds func(p0 context.Context, p1 address.Address, p2 types.BigInt) error `perm:"sign"`
+		ClientRetrievalStatsImport func(p0 context.Context, p1 []api.RetrievalRecord) (int, error) `perm:"write" stability:"experimental"`
 
-		MarketReserveFunds func(p0 context.Context, p1 address.Address, p2 address.Address, p3 types.BigInt) (cid.Cid, error) `perm:"sign"`
+		ClientRetrieve func(p0 context.Context, p1 api.RetrievalOrder, p2 *api.FileRef) error `perm:"admin" stability:"stable"`
 
-		MarketWithdraw func(p0 context.Context, p1 address.Address, p2 address.Address, p3 types.BigInt) (cid.Cid, error) `perm:"sign"`
+		ClientRetrieveTryRestartInsufficientFunds func(p0 context.Context, p1 address.Address) error `perm:"write" stability:"stable"`
 
-		MinerCreateBlock func(p0 context.Context, p1 *api.BlockTemplate) (*types.BlockMsg, error) `perm:"write"`
+		ClientRetrieveWithEvents func(p0 context.Context, p1 api.RetrievalOrder, p2 *api.FileRef) (<-chan marketevents.RetrievalEvent, error) `perm:"admin" stability:"stable"`
 
-		MinerGetBaseInfo func(p0 context.Context, p1 address.Address, p2 abi.ChainEpoch, p3 types.TipSetKey) (*api.MiningBaseInfo, error) `perm:"read"`
+		ClientStartDeal func(p0 context.Context, p1 *api.StartDealParams) (*cid.Cid, error) `perm:"admin" stability:"stable"`
 
-		MpoolBatchPush func(p0 context.Context, p1 []*types.SignedMessage) ([]cid.Cid, error) `perm:"write"`
+		CreateBackup func(p0 context.Context, p1 string) error `perm:"admin" stability:"stable"`
 
-		MpoolBatchPushMessage func(p0 context.Context, p1 []*types.Message, p2 *api.MessageSendSpec) ([]*types.SignedMessage, error) `perm:"sign"`
+		GasEstimateFeeCap func(p0 context.Context, p1 *types.Message, p2 int64, p3 types.TipSetKey) (types.BigInt, error) `perm:"read" stability:"stable"`
 
-		MpoolBatchPushUntrusted func(p0 context.Context, p1 []*types.SignedMessage) ([]cid.Cid, error) `perm:"write"`
+		GasEstimateGasLimit func(p0 context.Context, p1 *types.Message, p2 types.TipSetKey) (int64, error) `perm:"read" stability:"stable"`
 
-		MpoolClear func(p0 context.Context, p1 bool) error `perm:"write"`
+		GasEstimateGasPremium func(p0 context.Context, p1 uint64, p2 address.Address, p3 int64, p4 types.TipSetKey) (types.BigInt, error) `perm:"read" stability:"stable"`
 
-		MpoolGetConfig func(p0 context.Context) (*types.MpoolConfig, error) `perm:"read"`
+		GasEstimateMessageGas func(p0 context.Context, p1 *types.Message, p2 *api.MessageSendSpec, p3 types.TipSetKey) (*types.Message, error) `perm:"read" stability:"stable"`
 
-		MpoolGetNonce func(p0 context.Context, p1 address.Address) (uint64, error) `perm:"read"`
+		MarketAddBalance func(p0 context.Context, p1 address.Address, p2 address.Address, p3 types.BigInt) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		MpoolPending func(p0 context.Context, p1 types.TipSetKey) ([]*types.SignedMessage, error) `perm:"read"`
+		MarketGetReserved func(p0 context.Context, p1 address.Address) (types.BigInt, error) `perm:"sign" stability:"stable"`
 
-		MpoolPush func(p0 context.Context, p1 *types.SignedMessage) (cid.Cid, error) `perm:"write"`
+		MarketReleaseFunds func(p0 context.Context, p1 address.Address, p2 types.BigInt) error `perm:"sign" stability:"stable"`
 
-		MpoolPushMessage func(p0 context.Context, p1 *types.Message, p2 *api.MessageSendSpec) (*types.SignedMessage, error) `perm:"sign"`
+		MarketReserveFunds func(p0 context.Context, p1 address.Address, p2 address.Address, p3 types.BigInt) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		MpoolPushUntrusted func(p0 context.Context, p1 *types.SignedMessage) (cid.Cid, error) `perm:"write"`
+		MarketWithdraw func(p0 context.Context, p1 address.Address, p2 address.Address, p3 types.BigInt) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		MpoolSelect func(p0 context.Context, p1 types.TipSetKey, p2 float64) ([]*types.SignedMessage, error) `perm:"read"`
+		Methods func(p0 context.Context) (map[string]api.MethodStability, error) `perm:"read" stability:"experimental"`
 
-		MpoolSetConfig func(p0 context.Context, p1 *types.MpoolConfig) error `perm:"admin"`
+		MinerCreateBlock func(p0 context.Context, p1 *api.BlockTemplate) (*types.BlockMsg, error) `perm:"write" stability:"stable"`
 
-		MpoolSub func(p0 context.Context) (<-chan api.MpoolUpdate, error) `perm:"read"`
+		MinerGetBaseInfo func(p0 context.Context, p1 address.Address, p2 abi.ChainEpoch, p3 types.TipSetKey) (*api.MiningBaseInfo, error) `perm:"read" stability:"stable"`
 
-		MsigAddApprove func(p0 context.Context, p1 address.Address, p2 address.Address, p3 uint64, p4 address.Address, p5 address.Address, p6 bool) (cid.Cid, error) `perm:"sign"`
+		MpoolBatchPush func(p0 context.Context, p1 []*types.SignedMessage) ([]cid.Cid, error) `perm:"write" stability:"stable"`
 
-		MsigAddCancel func(p0 context.Context, p1 address.Address, p2 address.Address, p3 uint64, p4 address.Address, p5 bool) (cid.Cid, error) `perm:"sign"`
+		MpoolBatchPushMessage func(p0 context.Context, p1 []*types.Message, p2 *api.MessageSendSpec) ([]*types.SignedMessage, error) `perm:"sign" stability:"stable"`
 
-		MsigAddPropose func(p0 context.Context, p1 address.Address, p2 address.Address, p3 address.Address, p4 bool) (cid.Cid, error) `perm:"sign"`
+		MpoolBatchPushUntrusted func(p0 context.Context, p1 []*types.SignedMessage) ([]cid.Cid, error) `perm:"write" stability:"stable"`
 
-		MsigApprove func(p0 context.Context, p1 address.Address, p2 uint64, p3 address.Address) (cid.Cid, error) `perm:"sign"`
+		MpoolClear func(p0 context.Context, p1 bool) error `perm:"write" stability:"stable"`
 
-		MsigApproveTxnHash func(p0 context.Context, p1 address.Address, p2 uint64, p3 address.Address, p4 address.Address, p5 types.BigInt, p6 address.Address, p7 uint64, p8 []byte) (cid.Cid, error) `perm:"sign"`
+		MpoolFeeHistory func(p0 context.Context) ([]api.FeeLevelStats, error) `perm:"read" stability:"experimental"`
 
-		MsigCancel func(p0 context.Context, p1 address.Address, p2 uint64, p3 address.Address, p4 types.BigInt, p5 address.Address, p6 uint64, p7 []byte) (cid.Cid, error) `perm:"sign"`
+		MpoolFeeLevel func(p0 context.Context, p1 cid.Cid) (*api.FeeLevelStats, error) `perm:"read" stability:"experimental"`
 
-		MsigCreate func(p0 context.Context, p1 uint64, p2 []address.Address, p3 abi.ChainEpoch, p4 types.BigInt, p5 address.Address, p6 types.BigInt) (cid.Cid, error) `perm:"sign"`
+		MpoolGasAccuracy func(p0 context.Context) ([]api.GasAccuracyStats, error) `perm:"read" stability:"experimental"`
 
-		MsigGetAvailableBalance func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (types.BigInt, error) `perm:"read"`
+		MpoolGetConfig func(p0 context.Context) (*types.MpoolConfig, error) `perm:"read" stability:"stable"`
 
-		MsigGetPending func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) ([]*api.MsigTransaction, error) `perm:"read"`
+		MpoolGetIdempotencyKey func(p0 context.Context, p1 string) (*api.IdempotencyRecord, error) `perm:"read" stability:"experimental"`
 
-		MsigGetVested func(p0 context.Context, p1 address.Address, p2 types.TipSetKey, p3 types.TipSetKey) (types.BigInt, error) `perm:"read"`
+		MpoolGetMemo func(p0 context.Context, p1 cid.Cid) (*api.MessageMemo, error) `perm:"read" stability:"experimental"`
 
-		MsigGetVestingSchedule func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (api.MsigVesting, error) `perm:"read"`
+		MpoolGetNonce func(p0 context.Context, p1 address.Address) (uint64, error) `perm:"read" stability:"stable"`
 
-		MsigPropose func(p0 context.Context, p1 address.Address, p2 address.Address, p3 types.BigInt, p4 address.Address, p5 uint64, p6 []byte) (cid.Cid, error) `perm:"sign"`
+		MpoolGetReplacement func(p0 context.Context, p1 cid.Cid) (*api.MsgReplacement, error) `perm:"read" stability:"experimental"`
 
-		MsigRemoveSigner func(p0 context.Context, p1 address.Address, p2 address.Address, p3 address.Address, p4 bool) (cid.Cid, error) `perm:"sign"`
+		MpoolLanes func(p0 context.Context) ([]api.MpoolLane, error) `perm:"read" stability:"experimental"`
 
-		MsigSwapApprove func(p0 context.Context, p1 address.Address, p2 address.Address, p3 uint64, p4 address.Address, p5 address.Address, p6 address.Address) (cid.Cid, error) `perm:"sign"`
+		MpoolListMemos func(p0 context.Context, p1 address.Address) ([]api.MessageMemo, error) `perm:"read" stability:"experimental"`
 
-		MsigSwapCancel func(p0 context.Context, p1 address.Address, p2 address.Address, p3 uint64, p4 address.Address, p5 address.Address) (cid.Cid, error) `perm:"sign"`
+		MpoolPending func(p0 context.Context, p1 types.TipSetKey) ([]*types.SignedMessage, error) `perm:"read" stability:"stable"`
 
-		MsigSwapPropose func(p0 context.Context, p1 address.Address, p2 address.Address, p3 address.Address, p4 address.Address) (cid.Cid, error) `perm:"sign"`
+		MpoolPropagation func(p0 context.Context, p1 cid.Cid) (*api.MsgPropagation, error) `perm:"read" stability:"experimental"`
 
-		PaychAllocateLane func(p0 context.Context, p1 address.Address) (uint64, error) `perm:"sign"`
+		MpoolPush func(p0 context.Context, p1 *types.SignedMessage) (cid.Cid, error) `perm:"write" stability:"stable"`
 
-		PaychAvailableFunds func(p0 context.Context, p1 address.Address) (*api.ChannelAvailableFunds, error) `perm:"sign"`
+		MpoolPushMessage func(p0 context.Context, p1 *types.Message, p2 *api.MessageSendSpec) (*types.SignedMessage, error) `perm:"sign" stability:"stable"`
 
-		PaychAvailableFundsByFromTo func(p0 context.Context, p1 address.Address, p2 address.Address) (*api.ChannelAvailableFunds, error) `perm:"sign"`
+		MpoolPushUntrusted func(p0 context.Context, p1 *types.SignedMessage) (cid.Cid, error) `perm:"write" stability:"stable"`
 
-		PaychCollect func(p0 context.Context, p1 address.Address) (cid.Cid, error) `perm:"sign"`
+		MpoolSelect func(p0 context.Context, p1 types.TipSetKey, p2 float64) ([]*types.SignedMessage, error) `perm:"read" stability:"stable"`
 
-		PaychGet func(p0 context.Context, p1 address.Address, p2 address.Address, p3 types.BigInt) (*api.ChannelInfo, error) `perm:"sign"`
+		MpoolSetConfig func(p0 context.Context, p1 *types.MpoolConfig) error `perm:"admin" stability:"stable"`
 
-		PaychGetWaitReady func(p0 context.Context, p1 cid.Cid) (address.Address, error) `perm:"sign"`
+		MpoolSetMemo func(p0 context.Context, p1 api.MessageMemo) error `perm:"write" stability:"experimental"`
 
-		PaychList func(p0 context.Context) ([]address.Address, error) `perm:"read"`
+		MpoolSub func(p0 context.Context) (<-chan api.MpoolUpdate, error) `perm:"read" stability:"stable"`
 
-		PaychNewPayment func(p0 context.Context, p1 address.Address, p2 address.Address, p3 []api.VoucherSpec) (*api.PaymentInfo, error) `perm:"sign"`
+		MsigAddApprove func(p0 context.Context, p1 address.Address, p2 address.Address, p3 uint64, p4 address.Address, p5 address.Address, p6 bool) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		PaychSettle func(p0 context.Context, p1 address.Address) (cid.Cid, error) `perm:"sign"`
+		MsigAddCancel func(p0 context.Context, p1 address.Address, p2 address.Address, p3 uint64, p4 address.Address, p5 bool) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		PaychStatus func(p0 context.Context, p1 address.Address) (*api.PaychStatus, error) `perm:"read"`
+		MsigAddPropose func(p0 context.Context, p1 address.Address, p2 address.Address, p3 address.Address, p4 bool) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		PaychVoucherAdd func(p0 context.Context, p1 address.Address, p2 *paych.SignedVoucher, p3 []byte, p4 types.BigInt) (types.BigInt, error) `perm:"write"`
+		MsigApprove func(p0 context.Context, p1 address.Address, p2 uint64, p3 address.Address) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		PaychVoucherCheckSpendable func(p0 context.Context, p1 address.Address, p2 *paych.SignedVoucher, p3 []byte, p4 []byte) (bool, error) `perm:"read"`
+		MsigApproveTxnHash func(p0 context.Context, p1 address.Address, p2 uint64, p3 address.Address, p4 address.Address, p5 types.BigInt, p6 address.Address, p7 uint64, p8 []byte) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		PaychVoucherCheckValid func(p0 context.Context, p1 address.Address, p2 *paych.SignedVoucher) error `perm:"read"`
+		MsigCancel func(p0 context.Context, p1 address.Address, p2 uint64, p3 address.Address, p4 types.BigInt, p5 address.Address, p6 uint64, p7 []byte) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		PaychVoucherCreate func(p0 context.Context, p1 address.Address, p2 types.BigInt, p3 uint64) (*api.VoucherCreateResult, error) `perm:"sign"`
+		MsigCreate func(p0 context.Context, p1 uint64, p2 []address.Address, p3 abi.ChainEpoch, p4 types.BigInt, p5 address.Address, p6 types.BigInt) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		PaychVoucherList func(p0 context.Context, p1 address.Address) ([]*paych.SignedVoucher, error) `perm:"write"`
+		MsigGetAvailableBalance func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (types.BigInt, error) `perm:"read" stability:"stable"`
 
-		PaychVoucherSubmit func(p0 context.Context, p1 address.Address, p2 *paych.SignedVoucher, p3 []byte, p4 []byte) (cid.Cid, error) `perm:"sign"`
+		MsigGetPending func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) ([]*api.MsigTransaction, error) `perm:"read" stability:"stable"`
 
-		StateAccountKey func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) `perm:"read"`
+		MsigGetVested func(p0 context.Context, p1 address.Address, p2 types.TipSetKey, p3 types.TipSetKey) (types.BigInt, error) `perm:"read" stability:"stable"`
 
-		StateAllMinerFaults func(p0 context.Context, p1 abi.ChainEpoch, p2 types.TipSetKey) ([]*api.Fault, error) `perm:"read"`
+		MsigGetVestingSchedule func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (api.MsigVesting, error) `perm:"read" stability:"stable"`
 
-		StateCall func(p0 context.Context, p1 *types.Message, p2 types.TipSetKey) (*api.InvocResult, error) `perm:"read"`
+		MsigPropose func(p0 context.Context, p1 address.Address, p2 address.Address, p3 types.BigInt, p4 address.Address, p5 uint64, p6 []byte) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		StateChangedActors func(p0 context.Context, p1 cid.Cid, p2 cid.Cid) (map[string]types.Actor, error) `perm:"read"`
+		MsigRemoveSigner func(p0 context.Context, p1 address.Address, p2 address.Address, p3 address.Address, p4 bool) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		StateCirculatingSupply func(p0 context.Context, p1 types.TipSetKey) (abi.TokenAmount, error) `perm:"read"`
+		MsigSwapApprove func(p0 context.Context, p1 address.Address, p2 address.Address, p3 uint64, p4 address.Address, p5 address.Address, p6 address.Address) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		StateCompute func(p0 context.Context, p1 abi.ChainEpoch, p2 []*types.Message, p3 types.TipSetKey) (*api.ComputeStateOutput, error) `perm:"read"`
+		MsigSwapCancel func(p0 context.Context, p1 address.Address, p2 address.Address, p3 uint64, p4 address.Address, p5 address.Address) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		StateDealProviderCollateralBounds func(p0 context.Context, p1 abi.PaddedPieceSize, p2 bool, p3 types.TipSetKey) (api.DealCollateralBounds, error) `perm:"read"`
+		MsigSwapPropose func(p0 context.Context, p1 address.Address, p2 address.Address, p3 address.Address, p4 address.Address) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		StateDecodeParams func(p0 context.Context, p1 address.Address, p2 abi.MethodNum, p3 []byte, p4 types.TipSetKey) (interface{}, error) `perm:"read"`
+		PaychAllocateLane func(p0 context.Context, p1 address.Address) (uint64, error) `perm:"sign" stability:"stable"`
 
-		StateGetActor func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*types.Actor, error) `perm:"read"`
+		PaychAvailableFunds func(p0 context.Context, p1 address.Address) (*api.ChannelAvailableFunds, error) `perm:"sign" stability:"stable"`
 
-		StateGetReceipt func(p0 context.Context, p1 cid.Cid, p2 types.TipSetKey) (*types.MessageReceipt, error) `perm:"read"`
+		PaychAvailableFundsByFromTo func(p0 context.Context, p1 address.Address, p2 address.Address) (*api.ChannelAvailableFunds, error) `perm:"sign" stability:"stable"`
 
-		StateListActors func(p0 context.Context, p1 types.TipSetKey) ([]address.Address, error) `perm:"read"`
+		PaychCollect func(p0 context.Context, p1 address.Address) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		StateListMessages func(p0 context.Context, p1 *api.MessageMatch, p2 types.TipSetKey, p3 abi.ChainEpoch) ([]cid.Cid, error) `perm:"read"`
+		PaychGet func(p0 context.Context, p1 address.Address, p2 address.Address, p3 types.BigInt) (*api.ChannelInfo, error) `perm:"sign" stability:"stable"`
 
-		StateListMiners func(p0 context.Context, p1 types.TipSetKey) ([]address.Address, error) `perm:"read"`
+		PaychGetWaitReady func(p0 context.Context, p1 cid.Cid) (address.Address, error) `perm:"sign" stability:"stable"`
 
-		StateLookupID func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) `perm:"read"`
+		PaychList func(p0 context.Context) ([]address.Address, error) `perm:"read" stability:"stable"`
 
-		StateMarketBalance func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (api.MarketBalance, error) `perm:"read"`
+		PaychNewPayment func(p0 context.Context, p1 address.Address, p2 address.Address, p3 []api.VoucherSpec) (*api.PaymentInfo, error) `perm:"sign" stability:"stable"`
 
-		StateMarketDeals func(p0 context.Context, p1 types.TipSetKey) (map[string]api.MarketDeal, error) `perm:"read"`
+		PaychSettle func(p0 context.Context, p1 address.Address) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		StateMarketParticipants func(p0 context.Context, p1 types.TipSetKey) (map[string]api.MarketBalance, error) `perm:"read"`
+		PaychStatus func(p0 context.Context, p1 address.Address) (*api.PaychStatus, error) `perm:"read" stability:"stable"`
 
-		StateMarketStorageDeal func(p0 context.Context, p1 abi.DealID, p2 types.TipSetKey) (*api.MarketDeal, error) `perm:"read"`
+		PaychVoucherAdd func(p0 context.Context, p1 address.Address, p2 *paych.SignedVoucher, p3 []byte, p4 types.BigInt) (types.BigInt, error) `perm:"write" stability:"stable"`
 
-		StateMinerActiveSectors func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) ([]*miner.SectorOnChainInfo, error) `perm:"read"`
+		PaychVoucherCheckSpendable func(p0 context.Context, p1 address.Address, p2 *paych.SignedVoucher, p3 []byte, p4 []byte) (bool, error) `perm:"read" stability:"stable"`
 
-		StateMinerAvailableBalance func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (types.BigInt, error) `perm:"read"`
+		PaychVoucherCheckValid func(p0 context.Context, p1 address.Address, p2 *paych.SignedVoucher) error `perm:"read" stability:"stable"`
 
-		StateMinerDeadlines func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) ([]api.Deadline, error) `perm:"read"`
+		PaychVoucherCreate func(p0 context.Context, p1 address.Address, p2 types.BigInt, p3 uint64) (*api.VoucherCreateResult, error) `perm:"sign" stability:"stable"`
 
-		StateMinerFaults func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (bitfield.BitField, error) `perm:"read"`
+		PaychVoucherList func(p0 context.Context, p1 address.Address) ([]*paych.SignedVoucher, error) `perm:"write" stability:"stable"`
 
-		StateMinerInfo func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (miner.MinerInfo, error) `perm:"read"`
+		PaychVoucherSubmit func(p0 context.Context, p1 address.Address, p2 *paych.SignedVoucher, p3 []byte, p4 []byte) (cid.Cid, error) `perm:"sign" stability:"stable"`
 
-		StateMinerInitialPledgeCollateral func(p0 context.Context, p1 address.Address, p2 miner.SectorPreCommitInfo, p3 types.TipSetKey) (types.BigInt, error) `perm:"read"`
+		ProjectedCall func(p0 context.Context, p1 string, p2 []json.RawMessage, p3 []string) (json.RawMessage, error) `perm:"read" stability:"experimental"`
 
-		StateMinerPartitions func(p0 context.Context, p1 address.Address, p2 uint64, p3 types.TipSetKey) ([]api.Partition, error) `perm:"read"`
+		RPCRecordStart func(p0 context.Context, p1 api.RPCRecordConfig) error `perm:"admin" stability:"experimental"`
 
-		StateMinerPower func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*api.MinerPower, error) `perm:"read"`
+		RPCRecordStatus func(p0 context.Context) (api.RPCRecordStatus, error) `perm:"admin" stability:"experimental"`
 
-		StateMinerPreCommitDepositForPower func(p0 context.Context, p1 address.Address, p2 miner.SectorPreCommitInfo, p3 types.TipSetKey) (types.BigInt, error) `perm:"read"`
+		RPCRecordStop func(p0 context.Context) (api.RPCRecordStatus, error) `perm:"admin" stability:"experimental"`
 
-		StateMinerProvingDeadline func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*dline.Info, error) `perm:"read"`
+		StateAccountBalanceDelta func(p0 context.Context, p1 address.Address, p2 types.TipSetKey, p3 types.TipSetKey) (*api.BalanceDelta, error) `perm:"read" stability:"experimental"`
 
-		StateMinerRecoveries func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (bitfield.BitField, error) `perm:"read"`
+		StateAccountKey func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) `perm:"read" stability:"stable"`
 
-		StateMinerSectorAllocated func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (bool, error) `perm:"read"`
+		StateAllMinerFaults func(p0 context.Context, p1 abi.ChainEpoch, p2 types.TipSetKey) ([]*api.Fault, error) `perm:"read" stability:"stable"`
 
-		StateMinerSectorCount func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (api.MinerSectors, error) `perm:"read"`
+		StateCall func(p0 context.Context, p1 *types.Message, p2 types.TipSetKey) (*api.InvocResult, error) `perm:"read" stability:"stable"`
 
-		StateMinerSectors func(p0 context.Context, p1 address.Address, p2 *bitfield.BitField, p3 types.TipSetKey) ([]*miner.SectorOnChainInfo, error) `perm:"read"`
+		StateChangedActors func(p0 context.Context, p1 cid.Cid, p2 cid.Cid) (map[string]types.Actor, error) `perm:"read" stability:"stable"`
 
-		StateNetworkName func(p0 context.Context) (dtypes.NetworkName, error) `perm:"read"`
+		StateCirculatingSupply func(p0 context.Context, p1 types.TipSetKey) (abi.TokenAmount, error) `perm:"read" stability:"stable"`
 
-		StateNetworkVersion func(p0 context.Context, p1 types.TipSetKey) (apitypes.NetworkVersion, error) `perm:"read"`
+		StateCompute func(p0 context.Context, p1 abi.ChainEpoch, p2 []*types.Message, p3 types.TipSetKey) (*api.ComputeStateOutput, error) `perm:"read" stability:"stable"`
 
-		StateReadState func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*api.ActorState, error) `perm:"read"`
+		StateDealProviderCollateralBounds func(p0 context.Context, p1 abi.PaddedPieceSize, p2 bool, p3 types.TipSetKey) (api.DealCollateralBounds, error) `perm:"read" stability:"stable"`
 
-		StateReplay func(p0 context.Context, p1 types.TipSetKey, p2 cid.Cid) (*api.InvocResult, error) `perm:"read"`
+		StateDecodeParams func(p0 context.Context, p1 address.Address, p2 abi.MethodNum, p3 []byte, p4 types.TipSetKey) (interface{}, error) `perm:"read" stability:"stable"`
 
-		StateSearchMsg func(p0 context.Context, p1 cid.Cid) (*api.MsgLookup, error) `perm:"read"`
+		StateGetActor func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*types.Actor, error) `perm:"read" stability:"stable"`
 
-		StateSearchMsgLimited func(p0 context.Context, p1 cid.Cid, p2 abi.ChainEpoch) (*api.MsgLookup, error) `perm:"read"`
+		StateGetReceipt func(p0 context.Context, p1 cid.Cid, p2 types.TipSetKey) (*types.MessageReceipt, error) `perm:"read" removal:"v1" replacement:"StateSearchMsg" stability:"deprecated"`
 
-		StateSectorExpiration func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (*miner.SectorExpiration, error) `perm:"read"`
+		StateListActors func(p0 context.Context, p1 types.TipSetKey) ([]address.Address, error) `perm:"read" stability:"stable"`
 
-		StateSectorGetInfo func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (*miner.SectorOnChainInfo, error) `perm:"read"`
+		StateListMessages func(p0 context.Context, p1 *api.MessageMatch, p2 types.TipSetKey, p3 abi.ChainEpoch) ([]cid.Cid, error) `perm:"read" stability:"stable"`
 
-		StateSectorPartition func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (*miner.SectorLocation, error) `perm:"read"`
+		StateListMiners func(p0 context.Context, p1 types.TipSetKey) ([]address.Address, error) `perm:"read" stability:"stable"`
 
-		StateSectorPreCommitInfo func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (miner.SectorPreCommitOnChainInfo, error) `perm:"read"`
+		StateLookupID func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) `perm:"read" stability:"stable"`
 
-		StateVMCirculatingSupplyInternal func(p0 context.Context, p1 types.TipSetKey) (api.CirculatingSupply, error) `perm:"read"`
+		StateLookupRobustAddress func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) `perm:"read" stability:"experimental"`
 
-		StateVerifiedClientStatus func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*abi.StoragePower, error) `perm:"read"`
+		StateMarketBalance func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (api.MarketBalance, error) `perm:"read" stability:"stable"`
 
-		StateVerifiedRegistryRootKey func(p0 context.Context, p1 types.TipSetKey) (address.Address, error) `perm:"read"`
+		StateMarketDeals func(p0 context.Context, p1 types.TipSetKey) (map[string]api.MarketDeal, error) `perm:"read" stability:"stable"`
 
-		StateVerifierStatus func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*abi.StoragePower, error) `perm:"read"`
+		StateMarketParticipants func(p0 context.Context, p1 types.TipSetKey) (map[string]api.MarketBalance, error) `perm:"read" stability:"stable"`
 
-		StateWaitMsg func(p0 context.Context, p1 cid.Cid, p2 uint64) (*api.MsgLookup, error) `perm:"read"`
+		StateMarketStorageDeal func(p0 context.Context, p1 abi.DealID, p2 types.TipSetKey) (*api.MarketDeal, error) `perm:"read" stability:"stable"`
 
-		StateWaitMsgLimited func(p0 context.Context, p1 cid.Cid, p2 uint64, p3 abi.ChainEpoch) (*api.MsgLookup, error) `perm:"read"`
+		StateMinerActiveSectors func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) ([]*miner.SectorOnChainInfo, error) `perm:"read" stability:"stable"`
 
-		SyncCheckBad func(p0 context.Context, p1 cid.Cid) (string, error) `perm:"read"`
+		StateMinerAvailableBalance func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (types.BigInt, error) `perm:"read" stability:"stable"`
 
-		SyncCheckpoint func(p0 context.Context, p1 types.TipSetKey) error `perm:"admin"`
+		StateMinerDeadlines func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) ([]api.Deadline, error) `perm:"read" stability:"stable"`
 
-		SyncIncomingBlocks func(p0 context.Context) (<-chan *types.BlockHeader, error) `perm:"read"`
+		StateMinerExpirationLadder func(p0 context.Context, p1 address.Address, p2 float64, p3 types.TipSetKey) (*api.ExpirationLadder, error) `perm:"read" stability:"experimental"`
 
-		SyncMarkBad func(p0 context.Context, p1 cid.Cid) error `perm:"admin"`
+		StateMinerFaults func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (bitfield.BitField, error) `perm:"read" stability:"stable"`
 
-		SyncState func(p0 context.Context) (*api.SyncState, error) `perm:"read"`
+		StateMinerInfo func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (miner.MinerInfo, error) `perm:"read" stability:"stable"`
 
-		SyncSubmitBlock func(p0 context.Context, p1 *types.BlockMsg) error `perm:"write"`
+		StateMinerInitialPledgeCollateral func(p0 context.Context, p1 address.Address, p2 miner.SectorPreCommitInfo, p3 types.TipSetKey) (types.BigInt, error) `perm:"read" stability:"stable"`
 
-		SyncUnmarkAllBad func(p0 context.Context) error `perm:"admin"`
+		StateMinerPartitions func(p0 context.Context, p1 address.Address, p2 uint64, p3 types.TipSetKey) ([]api.Partition, error) `perm:"read" stability:"stable"`
 
-		SyncUnmarkBad func(p0 context.Context, p1 cid.Cid) error `perm:"admin"`
+		StateMinerPower func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*api.MinerPower, error) `perm:"read" stability:"stable"`
 
-		SyncValidateTipset func(p0 context.Context, p1 types.TipSetKey) (bool, error) `perm:"read"`
+		StateMinerPreCommitDepositForPower func(p0 context.Context, p1 address.Address, p2 miner.SectorPreCommitInfo, p3 types.TipSetKey) (types.BigInt, error) `perm:"read" stability:"stable"`
 
-		WalletBalance func(p0 context.Context, p1 address.Address) (types.BigInt, error) `perm:"read"`
+		StateMinerProvingDeadline func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*dline.Info, error) `perm:"read" stability:"stable"`
 
-		WalletDefaultAddress func(p0 context.Context) (address.Address, error) `perm:"write"`
+		StateMinerRecoveries func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (bitfield.BitField, error) `perm:"read" stability:"stable"`
 
-		WalletDelete func(p0 context.Context, p1 address.Address) error `perm:"admin"`
+		StateMinerSectorAllocated func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (bool, error) `perm:"read" stability:"stable"`
 
-		WalletExport func(p0 context.Context, p1 address.Address) (*types.KeyInfo, error) `perm:"admin"`
+		StateMinerSectorCount func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (api.MinerSectors, error) `perm:"read" stability:"stable"`
 
-		WalletHas func(p0 context.Context, p1 address.Address) (bool, error) `perm:"write"`
+		StateMinerSectors func(p0 context.Context, p1 address.Address, p2 *bitfield.BitField, p3 types.TipSetKey) ([]*miner.SectorOnChainInfo, error) `perm:"read" stability:"stable"`
 
-		WalletImport func(p0 context.Context, p1 *types.KeyInfo) (address.Address, error) `perm:"admin"`
+		StateNetworkName func(p0 context.Context) (dtypes.NetworkName, error) `perm:"read" stability:"stable"`
 
-		WalletList func(p0 context.Context) ([]address.Address, error) `perm:"write"`
+		StateNetworkVersion func(p0 context.Context, p1 types.TipSetKey) (apitypes.NetworkVersion, error) `perm:"read" stability:"stable"`
 
-		WalletNew func(p0 context.Context, p1 types.KeyType) (address.Address, error) `perm:"write"`
+		StateReadState func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*api.ActorState, error) `perm:"read" stability:"stable"`
 
-		WalletSetDefault func(p0 context.Context, p1 address.Address) error `perm:"write"`
+		StateReplay func(p0 context.Context, p1 types.TipSetKey, p2 cid.Cid) (*api.InvocResult, error) `perm:"read" stability:"stable"`
 
-		WalletSign func(p0 context.Context, p1 address.Address, p2 []byte) (*crypto.Signature, error) `perm:"sign"`
+		StateSearchMsg func(p0 context.Context, p1 cid.Cid) (*api.MsgLookup, error) `perm:"read" stability:"stable"`
 
-		WalletSignMessage func(p0 context.Context, p1 address.Address, p2 *types.Message) (*types.SignedMessage, error) `perm:"sign"`
+		StateSearchMsgLimited func(p0 context.Context, p1 cid.Cid, p2 abi.ChainEpoch) (*api.MsgLookup, error) `perm:"read" stability:"stable"`
 
-		WalletValidateAddress func(p0 context.Context, p1 string) (address.Address, error) `perm:"read"`
+		StateSectorExpiration func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (*miner.SectorExpiration, error) `perm:"read" stability:"stable"`
 
-		WalletVerify func(p0 context.Context, p1 address.Address, p2 []byte, p3 *crypto.Signature) (bool, error) `perm:"read"`
+		StateSectorGetInfo func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (*miner.SectorOnChainInfo, error) `perm:"read" stability:"stable"`
+
+		StateSectorPartition func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (*miner.SectorLocation, error) `perm:"read" stability:"stable"`
+
+		StateSectorPreCommitInfo func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (miner.SectorPreCommitOnChainInfo, error) `perm:"read" stability:"stable"`
+
+		StateUnwatchActor func(p0 context.Context, p1 address.Address) error `perm:"write" stability:"experimental"`
+
+		StateVMCirculatingSupplyInternal func(p0 context.Context, p1 types.TipSetKey) (api.CirculatingSupply, error) `perm:"read" stability:"stable"`
+
+		StateVerifiedClientStatus func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*abi.StoragePower, error) `perm:"read" stability:"stable"`
+
+		StateVerifiedRegistryRootKey func(p0 context.Context, p1 types.TipSetKey) (address.Address, error) `perm:"read" stability:"stable"`
+
+		StateVerifierStatus func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*abi.StoragePower, error) `perm:"read" stability:"stable"`
+
+		StateWaitMsg func(p0 context.Context, p1 cid.Cid, p2 uint64) (*api.MsgLookup, error) `perm:"read" stability:"stable"`
+
+		StateWaitMsgLimited func(p0 context.Context, p1 cid.Cid, p2 uint64, p3 abi.ChainEpoch) (*api.MsgLookup, error) `perm:"read" stability:"stable"`
+
+		StateWatchActor func(p0 context.Context, p1 address.Address) error `perm:"write" stability:"experimental"`
+
+		StateWatchedActors func(p0 context.Context) ([]api.WatchedActor, error) `perm:"read" stability:"experimental"`
+
+		SyncCheckBad func(p0 context.Context, p1 cid.Cid) (string, error) `perm:"read" stability:"stable"`
+
+		SyncCheckpoint func(p0 context.Context, p1 types.TipSetKey) error `perm:"admin" stability:"stable"`
+
+		SyncIncomingBlocks func(p0 context.Context) (<-chan *types.BlockHeader, error) `perm:"read" stability:"stable"`
+
+		SyncMarkBad func(p0 context.Context, p1 cid.Cid) error `perm:"admin" stability:"stable"`
+
+		SyncState func(p0 context.Context) (*api.SyncState, error) `perm:"read" stability:"stable"`
+
+		SyncSubmitBlock func(p0 context.Context, p1 *types.BlockMsg) error `perm:"write" stability:"stable"`
+
+		SyncUnmarkAllBad func(p0 context.Context) error `perm:"admin" stability:"stable"`
+
+		SyncUnmarkBad func(p0 context.Context, p1 cid.Cid) error `perm:"admin" stability:"stable"`
+
+		SyncValidateTipset func(p0 context.Context, p1 types.TipSetKey) (bool, error) `perm:"read" stability:"stable"`
+
+		WalletAddWatch func(p0 context.Context, p1 address.Address, p2 string) error `perm:"write" stability:"experimental"`
+
+		WalletBalance func(p0 context.Context, p1 address.Address) (types.BigInt, error) `perm:"read" stability:"stable"`
+
+		WalletDefaultAddress func(p0 context.Context) (address.Address, error) `perm:"write" stability:"stable"`
+
+		WalletDelete func(p0 context.Context, p1 address.Address) error `perm:"admin" stability:"stable"`
+
+		WalletExport func(p0 context.Context, p1 address.Address) (*types.KeyInfo, error) `perm:"admin" stability:"stable"`
+
+		WalletHas func(p0 context.Context, p1 address.Address) (bool, error) `perm:"write" stability:"stable"`
+
+		WalletImport func(p0 context.Context, p1 *types.KeyInfo) (address.Address, error) `perm:"admin" stability:"stable"`
+
+		WalletList func(p0 context.Context) ([]address.Address, error) `perm:"write" stability:"stable"`
+
+		WalletListWatch func(p0 context.Context) ([]api.WatchOnlyAddress, error) `perm:"read" stability:"experimental"`
+
+		WalletLock func(p0 context.Context) error `perm:"admin" stability:"experimental"`
+
+		WalletNew func(p0 context.Context, p1 types.KeyType) (address.Address, error) `perm:"write" stability:"stable"`
+
+		WalletPolicyGet func(p0 context.Context, p1 address.Address) (*api.WalletUsagePolicy, error) `perm:"read" stability:"experimental"`
+
+		WalletPolicyList func(p0 context.Context) ([]api.WalletUsagePolicy, error) `perm:"read" stability:"experimental"`
+
+		WalletPolicyRemove func(p0 context.Context, p1 address.Address) error `perm:"admin" stability:"experimental"`
+
+		WalletPolicySet func(p0 context.Context, p1 api.WalletUsagePolicy) error `perm:"admin" stability:"experimental"`
+
+		WalletRemoveWatch func(p0 context.Context, p1 address.Address) error `perm:"write" stability:"experimental"`
+
+		WalletSetDefault func(p0 context.Context, p1 address.Address) error `perm:"write" stability:"stable"`
+
+		WalletSign func(p0 context.Context, p1 address.Address, p2 []byte) (*crypto.Signature, error) `perm:"sign" stability:"stable"`
+
+		WalletSignMessage func(p0 context.Context, p1 address.Address, p2 *types.Message) (*types.SignedMessage, error) `perm:"sign" stability:"stable"`
+
+		WalletSignMessageOverridePolicy func(p0 context.Context, p1 address.Address, p2 *types.Message) (*types.SignedMessage, error) `perm:"admin" stability:"experimental"`
+
+		WalletUnlock func(p0 context.Context, p1 string) error `perm:"admin" stability:"experimental"`
+
+		WalletValidateAddress func(p0 context.Context, p1 string) (address.Address, error) `perm:"read" stability:"stable"`
+
+		WalletVerify func(p0 context.Context, p1 address.Address, p2 []byte, p3 *crypto.Signature) (bool, error) `perm:"read" stability:"stable"`
 	}
 }
 
@@ -385,65 +473,67 @@ type FullNodeStub struct {
 
 type GatewayStruct struct {
 	Internal struct {
-		ChainGetBlockMessages func(p0 context.Context, p1 cid.Cid) (*api.BlockMessages, error) ``
+		ChainGetBlockMessages func(p0 context.Context, p1 cid.Cid) (*api.BlockMessages, error) `stability:"stable"`
 
-		ChainGetMessage func(p0 context.Context, p1 cid.Cid) (*types.Message, error) ``
+		ChainGetMessage func(p0 context.Context, p1 cid.Cid) (*types.Message, error) `stability:"stable"`
 
-		ChainGetTipSet func(p0 context.Context, p1 types.TipSetKey) (*types.TipSet, error) ``
+		ChainGetTipSet func(p0 context.Context, p1 types.TipSetKey) (*types.TipSet, error) `stability:"stable"`
 
-		ChainGetTipSetByHeight func(p0 context.Context, p1 abi.ChainEpoch, p2 types.TipSetKey) (*types.TipSet, error) ``
+		ChainGetTipSetByHeight func(p0 context.Context, p1 abi.ChainEpoch, p2 types.TipSetKey) (*types.TipSet, error) `stability:"stable"`
 
-		ChainHasObj func(p0 context.Context, p1 cid.Cid) (bool, error) ``
+		ChainHasObj func(p0 context.Context, p1 cid.Cid) (bool, error) `stability:"stable"`
 
-		ChainHead func(p0 context.Context) (*types.TipSet, error) ``
+		ChainHead func(p0 context.Context) (*types.TipSet, error) `stability:"stable"`
 
-		ChainNotify func(p0 context.Context) (<-chan []*api.HeadChange, error) ``
+		ChainNotify func(p0 context.Context) (<-chan []*api.HeadChange, error) `stability:"stable"`
 
-		ChainReadObj func(p0 context.Context, p1 cid.Cid) ([]byte, error) ``
+		ChainReadObj func(p0 context.Context, p1 cid.Cid) ([]byte, error) `stability:"stable"`
 
-		GasEstimateMessageGas func(p0 context.Context, p1 *types.Message, p2 *api.MessageSendSpec, p3 types.TipSetKey) (*types.Message, error) ``
+		GasEstimateMessageGas func(p0 context.Context, p1 *types.Message, p2 *api.MessageSendSpec, p3 types.TipSetKey) (*types.Message, error) `stability:"stable"`
 
-		MpoolPush func(p0 context.Context, p1 *types.SignedMessage) (cid.Cid, error) ``
+		MpoolPush func(p0 context.Context, p1 *types.SignedMessage) (cid.Cid, error) `stability:"stable"`
 
-		MsigGetAvailableBalance func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (types.BigInt, error) ``
+		MsigGetAvailableBalance func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (types.BigInt, error) `stability:"stable"`
 
-		MsigGetPending func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) ([]*api.MsigTransaction, error) ``
+		MsigGetPending func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) ([]*api.MsigTransaction, error) `stability:"stable"`
 
-		MsigGetVested func(p0 context.Context, p1 address.Address, p2 types.TipSetKey, p3 types.TipSetKey) (types.BigInt, error) ``
+		MsigGetVested func(p0 context.Context, p1 address.Address, p2 types.TipSetKey, p3 types.TipSetKey) (types.BigInt, error) `stability:"stable"`
 
-		StateAccountKey func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) ``
+		ProjectedCall func(p0 context.Context, p1 string, p2 []json.RawMessage, p3 []string) (json.RawMessage, error) `stability:"stable"`
 
-		StateDealProviderCollateralBounds func(p0 context.Context, p1 abi.PaddedPieceSize, p2 bool, p3 types.TipSetKey) (api.DealCollateralBounds, error) ``
+		StateAccountKey func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) `stability:"stable"`
 
-		StateGetActor func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*types.Actor, error) ``
+		StateDealProviderCollateralBounds func(p0 context.Context, p1 abi.PaddedPieceSize, p2 bool, p3 types.TipSetKey) (api.DealCollateralBounds, error) `stability:"stable"`
 
-		StateGetReceipt func(p0 context.Context, p1 cid.Cid, p2 types.TipSetKey) (*types.MessageReceipt, error) ``
+		StateGetActor func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*types.Actor, error) `stability:"stable"`
 
-		StateListMiners func(p0 context.Context, p1 types.TipSetKey) ([]address.Address, error) ``
+		StateGetReceipt func(p0 context.Context, p1 cid.Cid, p2 types.TipSetKey) (*types.MessageReceipt, error) `stability:"stable"`
 
-		StateLookupID func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) ``
+		StateListMiners func(p0 context.Context, p1 types.TipSetKey) ([]address.Address, error) `stability:"stable"`
 
-		StateMarketBalance func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (api.MarketBalance, error) ``
+		StateLookupID func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) `stability:"stable"`
 
-		StateMarketStorageDeal func(p0 context.Context, p1 abi.DealID, p2 types.TipSetKey) (*api.MarketDeal, error) ``
+		StateMarketBalance func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (api.MarketBalance, error) `stability:"stable"`
 
-		StateMinerInfo func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (miner.MinerInfo, error) ``
+		StateMarketStorageDeal func(p0 context.Context, p1 abi.DealID, p2 types.TipSetKey) (*api.MarketDeal, error) `stability:"stable"`
 
-		StateMinerPower func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*api.MinerPower, error) ``
+		StateMinerInfo func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (miner.MinerInfo, error) `stability:"stable"`
 
-		StateMinerProvingDeadline func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*dline.Info, error) ``
+		StateMinerPower func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*api.MinerPower, error) `stability:"stable"`
 
-		StateNetworkVersion func(p0 context.Context, p1 types.TipSetKey) (network.Version, error) ``
+		StateMinerProvingDeadline func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*dline.Info, error) `stability:"stable"`
 
-		StateSearchMsg func(p0 context.Context, p1 cid.Cid) (*api.MsgLookup, error) ``
+		StateNetworkVersion func(p0 context.Context, p1 types.TipSetKey) (network.Version, error) `stability:"stable"`
 
-		StateSectorGetInfo func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (*miner.SectorOnChainInfo, error) ``
+		StateSearchMsg func(p0 context.Context, p1 cid.Cid) (*api.MsgLookup, error) `stability:"stable"`
 
-		StateVerifiedClientStatus func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*abi.StoragePower, error) ``
+		StateSectorGetInfo func(p0 context.Context, p1 address.Address, p2 abi.SectorNumber, p3 types.TipSetKey) (*miner.SectorOnChainInfo, error) `stability:"stable"`
 
-		StateWaitMsg func(p0 context.Context, p1 cid.Cid, p2 uint64) (*api.MsgLookup, error) ``
+		StateVerifiedClientStatus func(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (*abi.StoragePower, error) `stability:"stable"`
 
-		WalletBalance func(p0 context.Context, p1 address.Address) (types.BigInt, error) ``
+		StateWaitMsg func(p0 context.Context, p1 cid.Cid, p2 uint64) (*api.MsgLookup, error) `stability:"stable"`
+
+		WalletBalance func(p0 context.Context, p1 address.Address) (types.BigInt, error) `stability:"stable"`
 	}
 }
 
@@ -474,6 +564,14 @@ func (s *FullNodeStub) ChainExport(p0 context.Context, p1 abi.ChainEpoch, p2 boo
 	return nil, xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) ChainFollowConsumers(p0 context.Context) ([]api.ChainFollowConsumer, error) {
+	return s.Internal.ChainFollowConsumers(p0)
+}
+
+func (s *FullNodeStub) ChainFollowConsumers(p0 context.Context) ([]api.ChainFollowConsumer, error) {
+	return *new([]api.ChainFollowConsumer), xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) ChainGetBlock(p0 context.Context, p1 cid.Cid) (*types.BlockHeader, error) {
 	return s.Internal.ChainGetBlock(p0, p1)
 }
@@ -570,6 +668,14 @@ func (s *FullNodeStub) ChainGetTipSetByHeight(p0 context.Context, p1 abi.ChainEp
 	return nil, xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) ChainGetTipSetByHeightPolicy(p0 context.Context, p1 abi.ChainEpoch, p2 api.NullRoundPolicy, p3 types.TipSetKey) (*types.TipSet, error) {
+	return s.Internal.ChainGetTipSetByHeightPolicy(p0, p1, p2, p3)
+}
+
+func (s *FullNodeStub) ChainGetTipSetByHeightPolicy(p0 context.Context, p1 abi.ChainEpoch, p2 api.NullRoundPolicy, p3 types.TipSetKey) (*types.TipSet, error) {
+	return nil, xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) ChainHasObj(p0 context.Context, p1 cid.Cid) (bool, error) {
 	return s.Internal.ChainHasObj(p0, p1)
 }
@@ -586,6 +692,22 @@ func (s *FullNodeStub) ChainHead(p0 context.Context) (*types.TipSet, error) {
 	return nil, xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) ChainMetricsQuery(p0 context.Context, p1 string, p2 abi.ChainEpoch, p3 abi.ChainEpoch, p4 abi.ChainEpoch) ([]api.MetricsPoint, error) {
+	return s.Internal.ChainMetricsQuery(p0, p1, p2, p3, p4)
+}
+
+func (s *FullNodeStub) ChainMetricsQuery(p0 context.Context, p1 string, p2 abi.ChainEpoch, p3 abi.ChainEpoch, p4 abi.ChainEpoch) ([]api.MetricsPoint, error) {
+	return *new([]api.MetricsPoint), xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) ChainMetricsSeries(p0 context.Context) ([]api.MetricsSeries, error) {
+	return s.Internal.ChainMetricsSeries(p0)
+}
+
+func (s *FullNodeStub) ChainMetricsSeries(p0 context.Context) ([]api.MetricsSeries, error) {
+	return *new([]api.MetricsSeries), xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) ChainNotify(p0 context.Context) (<-chan []*api.HeadChange, error) {
 	return s.Internal.ChainNotify(p0)
 }
@@ -594,6 +716,14 @@ func (s *FullNodeStub) ChainNotify(p0 context.Context) (<-chan []*api.HeadChange
 	return nil, xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) ChainPinHead(p0 context.Context, p1 time.Duration) (api.PinnedHead, error) {
+	return s.Internal.ChainPinHead(p0, p1)
+}
+
+func (s *FullNodeStub) ChainPinHead(p0 context.Context, p1 time.Duration) (api.PinnedHead, error) {
+	return *new(api.PinnedHead), xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) ChainReadObj(p0 context.Context, p1 cid.Cid) ([]byte, error) {
 	return s.Internal.ChainReadObj(p0, p1)
 }
@@ -626,6 +756,14 @@ func (s *FullNodeStub) ChainTipSetWeight(p0 context.Context, p1 types.TipSetKey)
 	return *new(types.BigInt), xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) ChainUnpinHead(p0 context.Context, p1 string) error {
+	return s.Internal.ChainUnpinHead(p0, p1)
+}
+
+func (s *FullNodeStub) ChainUnpinHead(p0 context.Context, p1 string) error {
+	return xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) ClientCalcCommP(p0 context.Context, p1 string) (*api.CommPRet, error) {
 	return s.Internal.ClientCalcCommP(p0, p1)
 }
@@ -658,6 +796,22 @@ func (s *FullNodeStub) ClientDataTransferUpdates(p0 context.Context) (<-chan api
 	return nil, xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) ClientDealHealth(p0 context.Context) ([]api.DealHealth, error) {
+	return s.Internal.ClientDealHealth(p0)
+}
+
+func (s *FullNodeStub) ClientDealHealth(p0 context.Context) ([]api.DealHealth, error) {
+	return *new([]api.DealHealth), xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) ClientDealHealthUpdates(p0 context.Context) (<-chan api.DealHealth, error) {
+	return s.Internal.ClientDealHealthUpdates(p0)
+}
+
+func (s *FullNodeStub) ClientDealHealthUpdates(p0 context.Context) (<-chan api.DealHealth, error) {
+	return nil, xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) ClientDealPieceCID(p0 context.Context, p1 cid.Cid) (api.DataCIDSize, error) {
 	return s.Internal.ClientDealPieceCID(p0, p1)
 }
@@ -666,6 +820,14 @@ func (s *FullNodeStub) ClientDealPieceCID(p0 context.Context, p1 cid.Cid) (api.D
 	return *new(api.DataCIDSize), xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) ClientDealSealingStatus(p0 context.Context, p1 cid.Cid) (*api.DealSealingStatus, error) {
+	return s.Internal.ClientDealSealingStatus(p0, p1)
+}
+
+func (s *FullNodeStub) ClientDealSealingStatus(p0 context.Context, p1 cid.Cid) (*api.DealSealingStatus, error) {
+	return nil, xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) ClientDealSize(p0 context.Context, p1 cid.Cid) (api.DataSize, error) {
 	return s.Internal.ClientDealSize(p0, p1)
 }
@@ -786,6 +948,30 @@ func (s *FullNodeStub) ClientRestartDataTransfer(p0 context.Context, p1 datatran
 	return xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) ClientRetrievalHistory(p0 context.Context, p1 address.Address, p2 int) ([]api.RetrievalRecord, error) {
+	return s.Internal.ClientRetrievalHistory(p0, p1, p2)
+}
+
+func (s *FullNodeStub) ClientRetrievalHistory(p0 context.Context, p1 address.Address, p2 int) ([]api.RetrievalRecord, error) {
+	return *new([]api.RetrievalRecord), xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) ClientRetrievalStats(p0 context.Context, p1 address.Address) ([]api.RetrievalProviderStats, error) {
+	return s.Internal.ClientRetrievalStats(p0, p1)
+}
+
+func (s *FullNodeStub) ClientRetrievalStats(p0 context.Context, p1 address.Address) ([]api.RetrievalProviderStats, error) {
+	return *new([]api.RetrievalProviderStats), xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) ClientRetrievalStatsImport(p0 context.Context, p1 []api.RetrievalRecord) (int, error) {
+	return s.Internal.ClientRetrievalStatsImport(p0, p1)
+}
+
+func (s *FullNodeStub) ClientRetrievalStatsImport(p0 context.Context, p1 []api.RetrievalRecord) (int, error) {
+	return 0, xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) ClientRetrieve(p0 context.Context, p1 api.RetrievalOrder, p2 *api.FileRef) error {
 	return s.Internal.ClientRetrieve(p0, p1, p2)
 }
@@ -898,6 +1084,14 @@ func (s *FullNodeStub) MarketWithdraw(p0 context.Context, p1 address.Address, p2
 	return *new(cid.Cid), xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) Methods(p0 context.Context) (map[string]api.MethodStability, error) {
+	return s.Internal.Methods(p0)
+}
+
+func (s *FullNodeStub) Methods(p0 context.Context) (map[string]api.MethodStability, error) {
+	return *new(map[string]api.MethodStability), xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) MinerCreateBlock(p0 context.Context, p1 *api.BlockTemplate) (*types.BlockMsg, error) {
 	return s.Internal.MinerCreateBlock(p0, p1)
 }
@@ -946,6 +1140,30 @@ func (s *FullNodeStub) MpoolClear(p0 context.Context, p1 bool) error {
 	return xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) MpoolFeeHistory(p0 context.Context) ([]api.FeeLevelStats, error) {
+	return s.Internal.MpoolFeeHistory(p0)
+}
+
+func (s *FullNodeStub) MpoolFeeHistory(p0 context.Context) ([]api.FeeLevelStats, error) {
+	return *new([]api.FeeLevelStats), xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) MpoolFeeLevel(p0 context.Context, p1 cid.Cid) (*api.FeeLevelStats, error) {
+	return s.Internal.MpoolFeeLevel(p0, p1)
+}
+
+func (s *FullNodeStub) MpoolFeeLevel(p0 context.Context, p1 cid.Cid) (*api.FeeLevelStats, error) {
+	return nil, xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) MpoolGasAccuracy(p0 context.Context) ([]api.GasAccuracyStats, error) {
+	return s.Internal.MpoolGasAccuracy(p0)
+}
+
+func (s *FullNodeStub) MpoolGasAccuracy(p0 context.Context) ([]api.GasAccuracyStats, error) {
+	return *new([]api.GasAccuracyStats), xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) MpoolGetConfig(p0 context.Context) (*types.MpoolConfig, error) {
 	return s.Internal.MpoolGetConfig(p0)
 }
@@ -954,6 +1172,22 @@ func (s *FullNodeStub) MpoolGetConfig(p0 context.Context) (*types.MpoolConfig, e
 	return nil, xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) MpoolGetIdempotencyKey(p0 context.Context, p1 string) (*api.IdempotencyRecord, error) {
+	return s.Internal.MpoolGetIdempotencyKey(p0, p1)
+}
+
+func (s *FullNodeStub) MpoolGetIdempotencyKey(p0 context.Context, p1 string) (*api.IdempotencyRecord, error) {
+	return nil, xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) MpoolGetMemo(p0 context.Context, p1 cid.Cid) (*api.MessageMemo, error) {
+	return s.Internal.MpoolGetMemo(p0, p1)
+}
+
+func (s *FullNodeStub) MpoolGetMemo(p0 context.Context, p1 cid.Cid) (*api.MessageMemo, error) {
+	return nil, xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) MpoolGetNonce(p0 context.Context, p1 address.Address) (uint64, error) {
 	return s.Internal.MpoolGetNonce(p0, p1)
 }
@@ -962,6 +1196,30 @@ func (s *FullNodeStub) MpoolGetNonce(p0 context.Context, p1 address.Address) (ui
 	return 0, xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) MpoolGetReplacement(p0 context.Context, p1 cid.Cid) (*api.MsgReplacement, error) {
+	return s.Internal.MpoolGetReplacement(p0, p1)
+}
+
+func (s *FullNodeStub) MpoolGetReplacement(p0 context.Context, p1 cid.Cid) (*api.MsgReplacement, error) {
+	return nil, xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) MpoolLanes(p0 context.Context) ([]api.MpoolLane, error) {
+	return s.Internal.MpoolLanes(p0)
+}
+
+func (s *FullNodeStub) MpoolLanes(p0 context.Context) ([]api.MpoolLane, error) {
+	return *new([]api.MpoolLane), xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) MpoolListMemos(p0 context.Context, p1 address.Address) ([]api.MessageMemo, error) {
+	return s.Internal.MpoolListMemos(p0, p1)
+}
+
+func (s *FullNodeStub) MpoolListMemos(p0 context.Context, p1 address.Address) ([]api.MessageMemo, error) {
+	return *new([]api.MessageMemo), xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) MpoolPending(p0 context.Context, p1 types.TipSetKey) ([]*types.SignedMessage, error) {
 	return s.Internal.MpoolPending(p0, p1)
 }
@@ -970,6 +1228,14 @@ func (s *FullNodeStub) MpoolPending(p0 context.Context, p1 types.TipSetKey) ([]*
 	return *new([]*types.SignedMessage), xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) MpoolPropagation(p0 context.Context, p1 cid.Cid) (*api.MsgPropagation, error) {
+	return s.Internal.MpoolPropagation(p0, p1)
+}
+
+func (s *FullNodeStub) MpoolPropagation(p0 context.Context, p1 cid.Cid) (*api.MsgPropagation, error) {
+	return nil, xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) MpoolPush(p0 context.Context, p1 *types.SignedMessage) (cid.Cid, error) {
 	return s.Internal.MpoolPush(p0, p1)
 }
@@ -1010,6 +1276,14 @@ func (s *FullNodeStub) MpoolSetConfig(p0 context.Context, p1 *types.MpoolConfig)
 	return xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) MpoolSetMemo(p0 context.Context, p1 api.MessageMemo) error {
+	return s.Internal.MpoolSetMemo(p0, p1)
+}
+
+func (s *FullNodeStub) MpoolSetMemo(p0 context.Context, p1 api.MessageMemo) error {
+	return xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) MpoolSub(p0 context.Context) (<-chan api.MpoolUpdate, error) {
 	return s.Internal.MpoolSub(p0)
 }
@@ -1274,6 +1548,46 @@ func (s *FullNodeStub) PaychVoucherSubmit(p0 context.Context, p1 address.Address
 	return *new(cid.Cid), xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) ProjectedCall(p0 context.Context, p1 string, p2 []json.RawMessage, p3 []string) (json.RawMessage, error) {
+	return s.Internal.ProjectedCall(p0, p1, p2, p3)
+}
+
+func (s *FullNodeStub) ProjectedCall(p0 context.Context, p1 string, p2 []json.RawMessage, p3 []string) (json.RawMessage, error) {
+	return *new(json.RawMessage), xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) RPCRecordStart(p0 context.Context, p1 api.RPCRecordConfig) error {
+	return s.Internal.RPCRecordStart(p0, p1)
+}
+
+func (s *FullNodeStub) RPCRecordStart(p0 context.Context, p1 api.RPCRecordConfig) error {
+	return xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) RPCRecordStatus(p0 context.Context) (api.RPCRecordStatus, error) {
+	return s.Internal.RPCRecordStatus(p0)
+}
+
+func (s *FullNodeStub) RPCRecordStatus(p0 context.Context) (api.RPCRecordStatus, error) {
+	return *new(api.RPCRecordStatus), xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) RPCRecordStop(p0 context.Context) (api.RPCRecordStatus, error) {
+	return s.Internal.RPCRecordStop(p0)
+}
+
+func (s *FullNodeStub) RPCRecordStop(p0 context.Context) (api.RPCRecordStatus, error) {
+	return *new(api.RPCRecordStatus), xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) StateAccountBalanceDelta(p0 context.Context, p1 address.Address, p2 types.TipSetKey, p3 types.TipSetKey) (*api.BalanceDelta, error) {
+	return s.Internal.StateAccountBalanceDelta(p0, p1, p2, p3)
+}
+
+func (s *FullNodeStub) StateAccountBalanceDelta(p0 context.Context, p1 address.Address, p2 types.TipSetKey, p3 types.TipSetKey) (*api.BalanceDelta, error) {
+	return nil, xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) StateAccountKey(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) {
 	return s.Internal.StateAccountKey(p0, p1, p2)
 }
@@ -1386,6 +1700,14 @@ func (s *FullNodeStub) StateLookupID(p0 context.Context, p1 address.Address, p2
 	return *new(address.Address), xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) StateLookupRobustAddress(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) {
+	return s.Internal.StateLookupRobustAddress(p0, p1, p2)
+}
+
+func (s *FullNodeStub) StateLookupRobustAddress(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) {
+	return *new(address.Address), xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) StateMarketBalance(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (api.MarketBalance, error) {
 	return s.Internal.StateMarketBalance(p0, p1, p2)
 }
@@ -1442,6 +1764,14 @@ func (s *FullNodeStub) StateMinerDeadlines(p0 context.Context, p1 address.Addres
 	return *new([]api.Deadline), xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) StateMinerExpirationLadder(p0 context.Context, p1 address.Address, p2 float64, p3 types.TipSetKey) (*api.ExpirationLadder, error) {
+	return s.Internal.StateMinerExpirationLadder(p0, p1, p2, p3)
+}
+
+func (s *FullNodeStub) StateMinerExpirationLadder(p0 context.Context, p1 address.Address, p2 float64, p3 types.TipSetKey) (*api.ExpirationLadder, error) {
+	return nil, xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) StateMinerFaults(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (bitfield.BitField, error) {
 	return s.Internal.StateMinerFaults(p0, p1, p2)
 }
@@ -1610,6 +1940,14 @@ func (s *FullNodeStub) StateSectorPreCommitInfo(p0 context.Context, p1 address.A
 	return *new(miner.SectorPreCommitOnChainInfo), xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) StateUnwatchActor(p0 context.Context, p1 address.Address) error {
+	return s.Internal.StateUnwatchActor(p0, p1)
+}
+
+func (s *FullNodeStub) StateUnwatchActor(p0 context.Context, p1 address.Address) error {
+	return xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) StateVMCirculatingSupplyInternal(p0 context.Context, p1 types.TipSetKey) (api.CirculatingSupply, error) {
 	return s.Internal.StateVMCirculatingSupplyInternal(p0, p1)
 }
@@ -1658,6 +1996,22 @@ func (s *FullNodeStub) StateWaitMsgLimited(p0 context.Context, p1 cid.Cid, p2 ui
 	return nil, xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) StateWatchActor(p0 context.Context, p1 address.Address) error {
+	return s.Internal.StateWatchActor(p0, p1)
+}
+
+func (s *FullNodeStub) StateWatchActor(p0 context.Context, p1 address.Address) error {
+	return xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) StateWatchedActors(p0 context.Context) ([]api.WatchedActor, error) {
+	return s.Internal.StateWatchedActors(p0)
+}
+
+func (s *FullNodeStub) StateWatchedActors(p0 context.Context) ([]api.WatchedActor, error) {
+	return *new([]api.WatchedActor), xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) SyncCheckBad(p0 context.Context, p1 cid.Cid) (string, error) {
 	return s.Internal.SyncCheckBad(p0, p1)
 }
@@ -1730,6 +2084,14 @@ func (s *FullNodeStub) SyncValidateTipset(p0 context.Context, p1 types.TipSetKey
 	return false, xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) WalletAddWatch(p0 context.Context, p1 address.Address, p2 string) error {
+	return s.Internal.WalletAddWatch(p0, p1, p2)
+}
+
+func (s *FullNodeStub) WalletAddWatch(p0 context.Context, p1 address.Address, p2 string) error {
+	return xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) WalletBalance(p0 context.Context, p1 address.Address) (types.BigInt, error) {
 	return s.Internal.WalletBalance(p0, p1)
 }
@@ -1786,6 +2148,22 @@ func (s *FullNodeStub) WalletList(p0 context.Context) ([]address.Address, error)
 	return *new([]address.Address), xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) WalletListWatch(p0 context.Context) ([]api.WatchOnlyAddress, error) {
+	return s.Internal.WalletListWatch(p0)
+}
+
+func (s *FullNodeStub) WalletListWatch(p0 context.Context) ([]api.WatchOnlyAddress, error) {
+	return *new([]api.WatchOnlyAddress), xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) WalletLock(p0 context.Context) error {
+	return s.Internal.WalletLock(p0)
+}
+
+func (s *FullNodeStub) WalletLock(p0 context.Context) error {
+	return xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) WalletNew(p0 context.Context, p1 types.KeyType) (address.Address, error) {
 	return s.Internal.WalletNew(p0, p1)
 }
@@ -1794,6 +2172,46 @@ func (s *FullNodeStub) WalletNew(p0 context.Context, p1 types.KeyType) (address.
 	return *new(address.Address), xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) WalletPolicyGet(p0 context.Context, p1 address.Address) (*api.WalletUsagePolicy, error) {
+	return s.Internal.WalletPolicyGet(p0, p1)
+}
+
+func (s *FullNodeStub) WalletPolicyGet(p0 context.Context, p1 address.Address) (*api.WalletUsagePolicy, error) {
+	return nil, xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) WalletPolicyList(p0 context.Context) ([]api.WalletUsagePolicy, error) {
+	return s.Internal.WalletPolicyList(p0)
+}
+
+func (s *FullNodeStub) WalletPolicyList(p0 context.Context) ([]api.WalletUsagePolicy, error) {
+	return *new([]api.WalletUsagePolicy), xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) WalletPolicyRemove(p0 context.Context, p1 address.Address) error {
+	return s.Internal.WalletPolicyRemove(p0, p1)
+}
+
+func (s *FullNodeStub) WalletPolicyRemove(p0 context.Context, p1 address.Address) error {
+	return xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) WalletPolicySet(p0 context.Context, p1 api.WalletUsagePolicy) error {
+	return s.Internal.WalletPolicySet(p0, p1)
+}
+
+func (s *FullNodeStub) WalletPolicySet(p0 context.Context, p1 api.WalletUsagePolicy) error {
+	return xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) WalletRemoveWatch(p0 context.Context, p1 address.Address) error {
+	return s.Internal.WalletRemoveWatch(p0, p1)
+}
+
+func (s *FullNodeStub) WalletRemoveWatch(p0 context.Context, p1 address.Address) error {
+	return xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) WalletSetDefault(p0 context.Context, p1 address.Address) error {
 	return s.Internal.WalletSetDefault(p0, p1)
 }
@@ -1818,6 +2236,22 @@ func (s *FullNodeStub) WalletSignMessage(p0 context.Context, p1 address.Address,
 	return nil, xerrors.New("method not supported")
 }
 
+func (s *FullNodeStruct) WalletSignMessageOverridePolicy(p0 context.Context, p1 address.Address, p2 *types.Message) (*types.SignedMessage, error) {
+	return s.Internal.WalletSignMessageOverridePolicy(p0, p1, p2)
+}
+
+func (s *FullNodeStub) WalletSignMessageOverridePolicy(p0 context.Context, p1 address.Address, p2 *types.Message) (*types.SignedMessage, error) {
+	return nil, xerrors.New("method not supported")
+}
+
+func (s *FullNodeStruct) WalletUnlock(p0 context.Context, p1 string) error {
+	return s.Internal.WalletUnlock(p0, p1)
+}
+
+func (s *FullNodeStub) WalletUnlock(p0 context.Context, p1 string) error {
+	return xerrors.New("method not supported")
+}
+
 func (s *FullNodeStruct) WalletValidateAddress(p0 context.Context, p1 string) (address.Address, error) {
 	return s.Internal.WalletValidateAddress(p0, p1)
 }
@@ -1938,6 +2372,14 @@ func (s *GatewayStub) MsigGetVested(p0 context.Context, p1 address.Address, p2 t
 	return *new(types.BigInt), xerrors.New("method not supported")
 }
 
+func (s *GatewayStruct) ProjectedCall(p0 context.Context, p1 string, p2 []json.RawMessage, p3 []string) (json.RawMessage, error) {
+	return s.Internal.ProjectedCall(p0, p1, p2, p3)
+}
+
+func (s *GatewayStub) ProjectedCall(p0 context.Context, p1 string, p2 []json.RawMessage, p3 []string) (json.RawMessage, error) {
+	return *new(json.RawMessage), xerrors.New("method not supported")
+}
+
 func (s *GatewayStruct) StateAccountKey(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (address.Address, error) {
 	return s.Internal.StateAccountKey(p0, p1, p2)
 }
@@ -6,7 +6,9 @@ package v0mocks
 
 import (
 	context "context"
+	json "encoding/json"
 	reflect "reflect"
+	time "time"
 
 	address "github.com/filecoin-project/go-address"
 	bitfield "github.com/filecoin-project/go-bitfield"
@@ -134,6 +136,21 @@ func (mr *MockFullNodeMockRecorder) ChainExport(arg0, arg1, arg2, arg3 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainExport", reflect.TypeOf((*MockFullNode)(nil).ChainExport), arg0, arg1, arg2, arg3)
 }
 
+// ChainFollowConsumers mocks base method
+func (m *MockFullNode) ChainFollowConsumers(arg0 context.Context) ([]api.ChainFollowConsumer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChainFollowConsumers", arg0)
+	ret0, _ := ret[0].([]api.ChainFollowConsumer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChainFollowConsumers indicates an expected call of ChainFollowConsumers
+func (mr *MockFullNodeMockRecorder) ChainFollowConsumers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainFollowConsumers", reflect.TypeOf((*MockFullNode)(nil).ChainFollowConsumers), arg0)
+}
+
 // ChainGetBlock mocks base method
 func (m *MockFullNode) ChainGetBlock(arg0 context.Context, arg1 cid.Cid) (*types.BlockHeader, error) {
 	m.ctrl.T.Helper()
@@ -314,6 +331,21 @@ func (mr *MockFullNodeMockRecorder) ChainGetTipSetByHeight(arg0, arg1, arg2 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainGetTipSetByHeight", reflect.TypeOf((*MockFullNode)(nil).ChainGetTipSetByHeight), arg0, arg1, arg2)
 }
 
+// ChainGetTipSetByHeightPolicy mocks base method
+func (m *MockFullNode) ChainGetTipSetByHeightPolicy(arg0 context.Context, arg1 abi.ChainEpoch, arg2 api.NullRoundPolicy, arg3 types.TipSetKey) (*types.TipSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChainGetTipSetByHeightPolicy", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*types.TipSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChainGetTipSetByHeightPolicy indicates an expected call of ChainGetTipSetByHeightPolicy
+func (mr *MockFullNodeMockRecorder) ChainGetTipSetByHeightPolicy(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainGetTipSetByHeightPolicy", reflect.TypeOf((*MockFullNode)(nil).ChainGetTipSetByHeightPolicy), arg0, arg1, arg2, arg3)
+}
+
 // ChainHasObj mocks base method
 func (m *MockFullNode) ChainHasObj(arg0 context.Context, arg1 cid.Cid) (bool, error) {
 	m.ctrl.T.Helper()
@@ -344,6 +376,36 @@ func (mr *MockFullNodeMockRecorder) ChainHead(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainHead", reflect.TypeOf((*MockFullNode)(nil).ChainHead), arg0)
 }
 
+// ChainMetricsQuery mocks base method
+func (m *MockFullNode) ChainMetricsQuery(arg0 context.Context, arg1 string, arg2 abi.ChainEpoch, arg3 abi.ChainEpoch, arg4 abi.ChainEpoch) ([]api.MetricsPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChainMetricsQuery", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]api.MetricsPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChainMetricsQuery indicates an expected call of ChainMetricsQuery
+func (mr *MockFullNodeMockRecorder) ChainMetricsQuery(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainMetricsQuery", reflect.TypeOf((*MockFullNode)(nil).ChainMetricsQuery), arg0, arg1, arg2, arg3, arg4)
+}
+
+// ChainMetricsSeries mocks base method
+func (m *MockFullNode) ChainMetricsSeries(arg0 context.Context) ([]api.MetricsSeries, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChainMetricsSeries", arg0)
+	ret0, _ := ret[0].([]api.MetricsSeries)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChainMetricsSeries indicates an expected call of ChainMetricsSeries
+func (mr *MockFullNodeMockRecorder) ChainMetricsSeries(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainMetricsSeries", reflect.TypeOf((*MockFullNode)(nil).ChainMetricsSeries), arg0)
+}
+
 // ChainNotify mocks base method
 func (m *MockFullNode) ChainNotify(arg0 context.Context) (<-chan []*api.HeadChange, error) {
 	m.ctrl.T.Helper()
@@ -359,6 +421,21 @@ func (mr *MockFullNodeMockRecorder) ChainNotify(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainNotify", reflect.TypeOf((*MockFullNode)(nil).ChainNotify), arg0)
 }
 
+// ChainPinHead mocks base method
+func (m *MockFullNode) ChainPinHead(arg0 context.Context, arg1 time.Duration) (api.PinnedHead, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChainPinHead", arg0, arg1)
+	ret0, _ := ret[0].(api.PinnedHead)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChainPinHead indicates an expected call of ChainPinHead
+func (mr *MockFullNodeMockRecorder) ChainPinHead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainPinHead", reflect.TypeOf((*MockFullNode)(nil).ChainPinHead), arg0, arg1)
+}
+
 // ChainReadObj mocks base method
 func (m *MockFullNode) ChainReadObj(arg0 context.Context, arg1 cid.Cid) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -418,6 +495,20 @@ func (mr *MockFullNodeMockRecorder) ChainTipSetWeight(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainTipSetWeight", reflect.TypeOf((*MockFullNode)(nil).ChainTipSetWeight), arg0, arg1)
 }
 
+// ChainUnpinHead mocks base method
+func (m *MockFullNode) ChainUnpinHead(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChainUnpinHead", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChainUnpinHead indicates an expected call of ChainUnpinHead
+func (mr *MockFullNodeMockRecorder) ChainUnpinHead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainUnpinHead", reflect.TypeOf((*MockFullNode)(nil).ChainUnpinHead), arg0, arg1)
+}
+
 // ClientCalcCommP mocks base method
 func (m *MockFullNode) ClientCalcCommP(arg0 context.Context, arg1 string) (*api.CommPRet, error) {
 	m.ctrl.T.Helper()
@@ -476,6 +567,36 @@ func (mr *MockFullNodeMockRecorder) ClientDataTransferUpdates(arg0 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientDataTransferUpdates", reflect.TypeOf((*MockFullNode)(nil).ClientDataTransferUpdates), arg0)
 }
 
+// ClientDealHealth mocks base method
+func (m *MockFullNode) ClientDealHealth(arg0 context.Context) ([]api.DealHealth, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientDealHealth", arg0)
+	ret0, _ := ret[0].([]api.DealHealth)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClientDealHealth indicates an expected call of ClientDealHealth
+func (mr *MockFullNodeMockRecorder) ClientDealHealth(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientDealHealth", reflect.TypeOf((*MockFullNode)(nil).ClientDealHealth), arg0)
+}
+
+// ClientDealHealthUpdates mocks base method
+func (m *MockFullNode) ClientDealHealthUpdates(arg0 context.Context) (<-chan api.DealHealth, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientDealHealthUpdates", arg0)
+	ret0, _ := ret[0].(<-chan api.DealHealth)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClientDealHealthUpdates indicates an expected call of ClientDealHealthUpdates
+func (mr *MockFullNodeMockRecorder) ClientDealHealthUpdates(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientDealHealthUpdates", reflect.TypeOf((*MockFullNode)(nil).ClientDealHealthUpdates), arg0)
+}
+
 // ClientDealPieceCID mocks base method
 func (m *MockFullNode) ClientDealPieceCID(arg0 context.Context, arg1 cid.Cid) (api.DataCIDSize, error) {
 	m.ctrl.T.Helper()
@@ -491,6 +612,21 @@ func (mr *MockFullNodeMockRecorder) ClientDealPieceCID(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientDealPieceCID", reflect.TypeOf((*MockFullNode)(nil).ClientDealPieceCID), arg0, arg1)
 }
 
+// ClientDealSealingStatus mocks base method
+func (m *MockFullNode) ClientDealSealingStatus(arg0 context.Context, arg1 cid.Cid) (*api.DealSealingStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientDealSealingStatus", arg0, arg1)
+	ret0, _ := ret[0].(*api.DealSealingStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClientDealSealingStatus indicates an expected call of ClientDealSealingStatus
+func (mr *MockFullNodeMockRecorder) ClientDealSealingStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientDealSealingStatus", reflect.TypeOf((*MockFullNode)(nil).ClientDealSealingStatus), arg0, arg1)
+}
+
 // ClientDealSize mocks base method
 func (m *MockFullNode) ClientDealSize(arg0 context.Context, arg1 cid.Cid) (api.DataSize, error) {
 	m.ctrl.T.Helper()
@@ -713,6 +849,51 @@ func (mr *MockFullNodeMockRecorder) ClientRestartDataTransfer(arg0, arg1, arg2,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientRestartDataTransfer", reflect.TypeOf((*MockFullNode)(nil).ClientRestartDataTransfer), arg0, arg1, arg2, arg3)
 }
 
+// ClientRetrievalHistory mocks base method
+func (m *MockFullNode) ClientRetrievalHistory(arg0 context.Context, arg1 address.Address, arg2 int) ([]api.RetrievalRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientRetrievalHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]api.RetrievalRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClientRetrievalHistory indicates an expected call of ClientRetrievalHistory
+func (mr *MockFullNodeMockRecorder) ClientRetrievalHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientRetrievalHistory", reflect.TypeOf((*MockFullNode)(nil).ClientRetrievalHistory), arg0, arg1, arg2)
+}
+
+// ClientRetrievalStats mocks base method
+func (m *MockFullNode) ClientRetrievalStats(arg0 context.Context, arg1 address.Address) ([]api.RetrievalProviderStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientRetrievalStats", arg0, arg1)
+	ret0, _ := ret[0].([]api.RetrievalProviderStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClientRetrievalStats indicates an expected call of ClientRetrievalStats
+func (mr *MockFullNodeMockRecorder) ClientRetrievalStats(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientRetrievalStats", reflect.TypeOf((*MockFullNode)(nil).ClientRetrievalStats), arg0, arg1)
+}
+
+// ClientRetrievalStatsImport mocks base method
+func (m *MockFullNode) ClientRetrievalStatsImport(arg0 context.Context, arg1 []api.RetrievalRecord) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientRetrievalStatsImport", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClientRetrievalStatsImport indicates an expected call of ClientRetrievalStatsImport
+func (mr *MockFullNodeMockRecorder) ClientRetrievalStatsImport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientRetrievalStatsImport", reflect.TypeOf((*MockFullNode)(nil).ClientRetrievalStatsImport), arg0, arg1)
+}
+
 // ClientRetrieve mocks base method
 func (m *MockFullNode) ClientRetrieve(arg0 context.Context, arg1 api.RetrievalOrder, arg2 *api.FileRef) error {
 	m.ctrl.T.Helper()
@@ -786,6 +967,36 @@ func (mr *MockFullNodeMockRecorder) Closing(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Closing", reflect.TypeOf((*MockFullNode)(nil).Closing), arg0)
 }
 
+// ConfigHistory mocks base method
+func (m *MockFullNode) ConfigHistory(arg0 context.Context) ([]api.ConfigSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfigHistory", arg0)
+	ret0, _ := ret[0].([]api.ConfigSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfigHistory indicates an expected call of ConfigHistory
+func (mr *MockFullNodeMockRecorder) ConfigHistory(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigHistory", reflect.TypeOf((*MockFullNode)(nil).ConfigHistory), arg0)
+}
+
+// ConfigLoaded mocks base method
+func (m *MockFullNode) ConfigLoaded(arg0 context.Context) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfigLoaded", arg0)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfigLoaded indicates an expected call of ConfigLoaded
+func (mr *MockFullNodeMockRecorder) ConfigLoaded(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigLoaded", reflect.TypeOf((*MockFullNode)(nil).ConfigLoaded), arg0)
+}
+
 // CreateBackup mocks base method
 func (m *MockFullNode) CreateBackup(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -890,6 +1101,21 @@ func (mr *MockFullNodeMockRecorder) ID(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ID", reflect.TypeOf((*MockFullNode)(nil).ID), arg0)
 }
 
+// LogLevels mocks base method
+func (m *MockFullNode) LogLevels(arg0 context.Context) ([]api.LogSystem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogLevels", arg0)
+	ret0, _ := ret[0].([]api.LogSystem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LogLevels indicates an expected call of LogLevels
+func (mr *MockFullNodeMockRecorder) LogLevels(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogLevels", reflect.TypeOf((*MockFullNode)(nil).LogLevels), arg0)
+}
+
 // LogList mocks base method
 func (m *MockFullNode) LogList(arg0 context.Context) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -919,6 +1145,21 @@ func (mr *MockFullNodeMockRecorder) LogSetLevel(arg0, arg1, arg2 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogSetLevel", reflect.TypeOf((*MockFullNode)(nil).LogSetLevel), arg0, arg1, arg2)
 }
 
+// LogSetLevels mocks base method
+func (m *MockFullNode) LogSetLevels(arg0 context.Context, arg1 string, arg2 string, arg3 time.Duration) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogSetLevels", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LogSetLevels indicates an expected call of LogSetLevels
+func (mr *MockFullNodeMockRecorder) LogSetLevels(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogSetLevels", reflect.TypeOf((*MockFullNode)(nil).LogSetLevels), arg0, arg1, arg2, arg3)
+}
+
 // MarketAddBalance mocks base method
 func (m *MockFullNode) MarketAddBalance(arg0 context.Context, arg1, arg2 address.Address, arg3 big.Int) (cid.Cid, error) {
 	m.ctrl.T.Helper()
@@ -993,6 +1234,21 @@ func (mr *MockFullNodeMockRecorder) MarketWithdraw(arg0, arg1, arg2, arg3 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarketWithdraw", reflect.TypeOf((*MockFullNode)(nil).MarketWithdraw), arg0, arg1, arg2, arg3)
 }
 
+// Methods mocks base method
+func (m *MockFullNode) Methods(arg0 context.Context) (map[string]api.MethodStability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Methods", arg0)
+	ret0, _ := ret[0].(map[string]api.MethodStability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Methods indicates an expected call of Methods
+func (mr *MockFullNodeMockRecorder) Methods(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Methods", reflect.TypeOf((*MockFullNode)(nil).Methods), arg0)
+}
+
 // MinerCreateBlock mocks base method
 func (m *MockFullNode) MinerCreateBlock(arg0 context.Context, arg1 *api.BlockTemplate) (*types.BlockMsg, error) {
 	m.ctrl.T.Helper()
@@ -1082,6 +1338,51 @@ func (mr *MockFullNodeMockRecorder) MpoolClear(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MpoolClear", reflect.TypeOf((*MockFullNode)(nil).MpoolClear), arg0, arg1)
 }
 
+// MpoolFeeHistory mocks base method
+func (m *MockFullNode) MpoolFeeHistory(arg0 context.Context) ([]api.FeeLevelStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MpoolFeeHistory", arg0)
+	ret0, _ := ret[0].([]api.FeeLevelStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MpoolFeeHistory indicates an expected call of MpoolFeeHistory
+func (mr *MockFullNodeMockRecorder) MpoolFeeHistory(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MpoolFeeHistory", reflect.TypeOf((*MockFullNode)(nil).MpoolFeeHistory), arg0)
+}
+
+// MpoolFeeLevel mocks base method
+func (m *MockFullNode) MpoolFeeLevel(arg0 context.Context, arg1 cid.Cid) (*api.FeeLevelStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MpoolFeeLevel", arg0, arg1)
+	ret0, _ := ret[0].(*api.FeeLevelStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MpoolFeeLevel indicates an expected call of MpoolFeeLevel
+func (mr *MockFullNodeMockRecorder) MpoolFeeLevel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MpoolFeeLevel", reflect.TypeOf((*MockFullNode)(nil).MpoolFeeLevel), arg0, arg1)
+}
+
+// MpoolGasAccuracy mocks base method
+func (m *MockFullNode) MpoolGasAccuracy(arg0 context.Context) ([]api.GasAccuracyStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MpoolGasAccuracy", arg0)
+	ret0, _ := ret[0].([]api.GasAccuracyStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MpoolGasAccuracy indicates an expected call of MpoolGasAccuracy
+func (mr *MockFullNodeMockRecorder) MpoolGasAccuracy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MpoolGasAccuracy", reflect.TypeOf((*MockFullNode)(nil).MpoolGasAccuracy), arg0)
+}
+
 // MpoolGetConfig mocks base method
 func (m *MockFullNode) MpoolGetConfig(arg0 context.Context) (*types.MpoolConfig, error) {
 	m.ctrl.T.Helper()
@@ -1097,6 +1398,36 @@ func (mr *MockFullNodeMockRecorder) MpoolGetConfig(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MpoolGetConfig", reflect.TypeOf((*MockFullNode)(nil).MpoolGetConfig), arg0)
 }
 
+// MpoolGetIdempotencyKey mocks base method
+func (m *MockFullNode) MpoolGetIdempotencyKey(arg0 context.Context, arg1 string) (*api.IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MpoolGetIdempotencyKey", arg0, arg1)
+	ret0, _ := ret[0].(*api.IdempotencyRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MpoolGetIdempotencyKey indicates an expected call of MpoolGetIdempotencyKey
+func (mr *MockFullNodeMockRecorder) MpoolGetIdempotencyKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MpoolGetIdempotencyKey", reflect.TypeOf((*MockFullNode)(nil).MpoolGetIdempotencyKey), arg0, arg1)
+}
+
+// MpoolGetMemo mocks base method
+func (m *MockFullNode) MpoolGetMemo(arg0 context.Context, arg1 cid.Cid) (*api.MessageMemo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MpoolGetMemo", arg0, arg1)
+	ret0, _ := ret[0].(*api.MessageMemo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MpoolGetMemo indicates an expected call of MpoolGetMemo
+func (mr *MockFullNodeMockRecorder) MpoolGetMemo(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MpoolGetMemo", reflect.TypeOf((*MockFullNode)(nil).MpoolGetMemo), arg0, arg1)
+}
+
 // MpoolGetNonce mocks base method
 func (m *MockFullNode) MpoolGetNonce(arg0 context.Context, arg1 address.Address) (uint64, error) {
 	m.ctrl.T.Helper()
@@ -1112,6 +1443,51 @@ func (mr *MockFullNodeMockRecorder) MpoolGetNonce(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MpoolGetNonce", reflect.TypeOf((*MockFullNode)(nil).MpoolGetNonce), arg0, arg1)
 }
 
+// MpoolGetReplacement mocks base method
+func (m *MockFullNode) MpoolGetReplacement(arg0 context.Context, arg1 cid.Cid) (*api.MsgReplacement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MpoolGetReplacement", arg0, arg1)
+	ret0, _ := ret[0].(*api.MsgReplacement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MpoolGetReplacement indicates an expected call of MpoolGetReplacement
+func (mr *MockFullNodeMockRecorder) MpoolGetReplacement(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MpoolGetReplacement", reflect.TypeOf((*MockFullNode)(nil).MpoolGetReplacement), arg0, arg1)
+}
+
+// MpoolLanes mocks base method
+func (m *MockFullNode) MpoolLanes(arg0 context.Context) ([]api.MpoolLane, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MpoolLanes", arg0)
+	ret0, _ := ret[0].([]api.MpoolLane)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MpoolLanes indicates an expected call of MpoolLanes
+func (mr *MockFullNodeMockRecorder) MpoolLanes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MpoolLanes", reflect.TypeOf((*MockFullNode)(nil).MpoolLanes), arg0)
+}
+
+// MpoolListMemos mocks base method
+func (m *MockFullNode) MpoolListMemos(arg0 context.Context, arg1 address.Address) ([]api.MessageMemo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MpoolListMemos", arg0, arg1)
+	ret0, _ := ret[0].([]api.MessageMemo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MpoolListMemos indicates an expected call of MpoolListMemos
+func (mr *MockFullNodeMockRecorder) MpoolListMemos(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MpoolListMemos", reflect.TypeOf((*MockFullNode)(nil).MpoolListMemos), arg0, arg1)
+}
+
 // MpoolPending mocks base method
 func (m *MockFullNode) MpoolPending(arg0 context.Context, arg1 types.TipSetKey) ([]*types.SignedMessage, error) {
 	m.ctrl.T.Helper()
@@ -1127,6 +1503,21 @@ func (mr *MockFullNodeMockRecorder) MpoolPending(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MpoolPending", reflect.TypeOf((*MockFullNode)(nil).MpoolPending), arg0, arg1)
 }
 
+// MpoolPropagation mocks base method
+func (m *MockFullNode) MpoolPropagation(arg0 context.Context, arg1 cid.Cid) (*api.MsgPropagation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MpoolPropagation", arg0, arg1)
+	ret0, _ := ret[0].(*api.MsgPropagation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MpoolPropagation indicates an expected call of MpoolPropagation
+func (mr *MockFullNodeMockRecorder) MpoolPropagation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MpoolPropagation", reflect.TypeOf((*MockFullNode)(nil).MpoolPropagation), arg0, arg1)
+}
+
 // MpoolPush mocks base method
 func (m *MockFullNode) MpoolPush(arg0 context.Context, arg1 *types.SignedMessage) (cid.Cid, error) {
 	m.ctrl.T.Helper()
@@ -1201,6 +1592,20 @@ func (mr *MockFullNodeMockRecorder) MpoolSetConfig(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MpoolSetConfig", reflect.TypeOf((*MockFullNode)(nil).MpoolSetConfig), arg0, arg1)
 }
 
+// MpoolSetMemo mocks base method
+func (m *MockFullNode) MpoolSetMemo(arg0 context.Context, arg1 api.MessageMemo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MpoolSetMemo", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MpoolSetMemo indicates an expected call of MpoolSetMemo
+func (mr *MockFullNodeMockRecorder) MpoolSetMemo(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MpoolSetMemo", reflect.TypeOf((*MockFullNode)(nil).MpoolSetMemo), arg0, arg1)
+}
+
 // MpoolSub mocks base method
 func (m *MockFullNode) MpoolSub(arg0 context.Context) (<-chan api.MpoolUpdate, error) {
 	m.ctrl.T.Helper()
@@ -1618,6 +2023,21 @@ func (mr *MockFullNodeMockRecorder) NetConnectedness(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetConnectedness", reflect.TypeOf((*MockFullNode)(nil).NetConnectedness), arg0, arg1)
 }
 
+// NetDialAddrs mocks base method
+func (m *MockFullNode) NetDialAddrs(arg0 context.Context, arg1 peer.AddrInfo) ([]api.DialResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetDialAddrs", arg0, arg1)
+	ret0, _ := ret[0].([]api.DialResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NetDialAddrs indicates an expected call of NetDialAddrs
+func (mr *MockFullNodeMockRecorder) NetDialAddrs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetDialAddrs", reflect.TypeOf((*MockFullNode)(nil).NetDialAddrs), arg0, arg1)
+}
+
 // NetDisconnect mocks base method
 func (m *MockFullNode) NetDisconnect(arg0 context.Context, arg1 peer.ID) error {
 	m.ctrl.T.Helper()
@@ -1647,6 +2067,36 @@ func (mr *MockFullNodeMockRecorder) NetFindPeer(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetFindPeer", reflect.TypeOf((*MockFullNode)(nil).NetFindPeer), arg0, arg1)
 }
 
+// NetFindPeerDHT mocks base method
+func (m *MockFullNode) NetFindPeerDHT(arg0 context.Context, arg1 peer.ID) (peer.AddrInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetFindPeerDHT", arg0, arg1)
+	ret0, _ := ret[0].(peer.AddrInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NetFindPeerDHT indicates an expected call of NetFindPeerDHT
+func (mr *MockFullNodeMockRecorder) NetFindPeerDHT(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetFindPeerDHT", reflect.TypeOf((*MockFullNode)(nil).NetFindPeerDHT), arg0, arg1)
+}
+
+// NetFindProviders mocks base method
+func (m *MockFullNode) NetFindProviders(arg0 context.Context, arg1 cid.Cid, arg2 int) ([]api.ProviderRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetFindProviders", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]api.ProviderRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NetFindProviders indicates an expected call of NetFindProviders
+func (mr *MockFullNodeMockRecorder) NetFindProviders(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetFindProviders", reflect.TypeOf((*MockFullNode)(nil).NetFindProviders), arg0, arg1, arg2)
+}
+
 // NetPeerInfo mocks base method
 func (m *MockFullNode) NetPeerInfo(arg0 context.Context, arg1 peer.ID) (*api.ExtendedPeerInfo, error) {
 	m.ctrl.T.Helper()
@@ -1931,6 +2381,65 @@ func (mr *MockFullNodeMockRecorder) PaychVoucherSubmit(arg0, arg1, arg2, arg3, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PaychVoucherSubmit", reflect.TypeOf((*MockFullNode)(nil).PaychVoucherSubmit), arg0, arg1, arg2, arg3, arg4)
 }
 
+// ProjectedCall mocks base method
+func (m *MockFullNode) ProjectedCall(arg0 context.Context, arg1 string, arg2 []json.RawMessage, arg3 []string) (json.RawMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectedCall", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(json.RawMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectedCall indicates an expected call of ProjectedCall
+func (mr *MockFullNodeMockRecorder) ProjectedCall(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectedCall", reflect.TypeOf((*MockFullNode)(nil).ProjectedCall), arg0, arg1, arg2, arg3)
+}
+
+// RPCRecordStart mocks base method
+func (m *MockFullNode) RPCRecordStart(arg0 context.Context, arg1 api.RPCRecordConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RPCRecordStart", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RPCRecordStart indicates an expected call of RPCRecordStart
+func (mr *MockFullNodeMockRecorder) RPCRecordStart(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RPCRecordStart", reflect.TypeOf((*MockFullNode)(nil).RPCRecordStart), arg0, arg1)
+}
+
+// RPCRecordStatus mocks base method
+func (m *MockFullNode) RPCRecordStatus(arg0 context.Context) (api.RPCRecordStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RPCRecordStatus", arg0)
+	ret0, _ := ret[0].(api.RPCRecordStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RPCRecordStatus indicates an expected call of RPCRecordStatus
+func (mr *MockFullNodeMockRecorder) RPCRecordStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RPCRecordStatus", reflect.TypeOf((*MockFullNode)(nil).RPCRecordStatus), arg0)
+}
+
+// RPCRecordStop mocks base method
+func (m *MockFullNode) RPCRecordStop(arg0 context.Context) (api.RPCRecordStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RPCRecordStop", arg0)
+	ret0, _ := ret[0].(api.RPCRecordStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RPCRecordStop indicates an expected call of RPCRecordStop
+func (mr *MockFullNodeMockRecorder) RPCRecordStop(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RPCRecordStop", reflect.TypeOf((*MockFullNode)(nil).RPCRecordStop), arg0)
+}
+
 // Session mocks base method
 func (m *MockFullNode) Session(arg0 context.Context) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
@@ -1960,6 +2469,21 @@ func (mr *MockFullNodeMockRecorder) Shutdown(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockFullNode)(nil).Shutdown), arg0)
 }
 
+// StateAccountBalanceDelta mocks base method
+func (m *MockFullNode) StateAccountBalanceDelta(arg0 context.Context, arg1 address.Address, arg2 types.TipSetKey, arg3 types.TipSetKey) (*api.BalanceDelta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StateAccountBalanceDelta", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*api.BalanceDelta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StateAccountBalanceDelta indicates an expected call of StateAccountBalanceDelta
+func (mr *MockFullNodeMockRecorder) StateAccountBalanceDelta(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateAccountBalanceDelta", reflect.TypeOf((*MockFullNode)(nil).StateAccountBalanceDelta), arg0, arg1, arg2, arg3)
+}
+
 // StateAccountKey mocks base method
 func (m *MockFullNode) StateAccountKey(arg0 context.Context, arg1 address.Address, arg2 types.TipSetKey) (address.Address, error) {
 	m.ctrl.T.Helper()
@@ -2170,6 +2694,21 @@ func (mr *MockFullNodeMockRecorder) StateLookupID(arg0, arg1, arg2 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateLookupID", reflect.TypeOf((*MockFullNode)(nil).StateLookupID), arg0, arg1, arg2)
 }
 
+// StateLookupRobustAddress mocks base method
+func (m *MockFullNode) StateLookupRobustAddress(arg0 context.Context, arg1 address.Address, arg2 types.TipSetKey) (address.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StateLookupRobustAddress", arg0, arg1, arg2)
+	ret0, _ := ret[0].(address.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StateLookupRobustAddress indicates an expected call of StateLookupRobustAddress
+func (mr *MockFullNodeMockRecorder) StateLookupRobustAddress(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateLookupRobustAddress", reflect.TypeOf((*MockFullNode)(nil).StateLookupRobustAddress), arg0, arg1, arg2)
+}
+
 // StateMarketBalance mocks base method
 func (m *MockFullNode) StateMarketBalance(arg0 context.Context, arg1 address.Address, arg2 types.TipSetKey) (api.MarketBalance, error) {
 	m.ctrl.T.Helper()
@@ -2275,6 +2814,21 @@ func (mr *MockFullNodeMockRecorder) StateMinerDeadlines(arg0, arg1, arg2 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateMinerDeadlines", reflect.TypeOf((*MockFullNode)(nil).StateMinerDeadlines), arg0, arg1, arg2)
 }
 
+// StateMinerExpirationLadder mocks base method
+func (m *MockFullNode) StateMinerExpirationLadder(arg0 context.Context, arg1 address.Address, arg2 float64, arg3 types.TipSetKey) (*api.ExpirationLadder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StateMinerExpirationLadder", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*api.ExpirationLadder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StateMinerExpirationLadder indicates an expected call of StateMinerExpirationLadder
+func (mr *MockFullNodeMockRecorder) StateMinerExpirationLadder(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateMinerExpirationLadder", reflect.TypeOf((*MockFullNode)(nil).StateMinerExpirationLadder), arg0, arg1, arg2, arg3)
+}
+
 // StateMinerFaults mocks base method
 func (m *MockFullNode) StateMinerFaults(arg0 context.Context, arg1 address.Address, arg2 types.TipSetKey) (bitfield.BitField, error) {
 	m.ctrl.T.Helper()
@@ -2590,6 +3144,20 @@ func (mr *MockFullNodeMockRecorder) StateSectorPreCommitInfo(arg0, arg1, arg2, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateSectorPreCommitInfo", reflect.TypeOf((*MockFullNode)(nil).StateSectorPreCommitInfo), arg0, arg1, arg2, arg3)
 }
 
+// StateUnwatchActor mocks base method
+func (m *MockFullNode) StateUnwatchActor(arg0 context.Context, arg1 address.Address) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StateUnwatchActor", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StateUnwatchActor indicates an expected call of StateUnwatchActor
+func (mr *MockFullNodeMockRecorder) StateUnwatchActor(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateUnwatchActor", reflect.TypeOf((*MockFullNode)(nil).StateUnwatchActor), arg0, arg1)
+}
+
 // StateVMCirculatingSupplyInternal mocks base method
 func (m *MockFullNode) StateVMCirculatingSupplyInternal(arg0 context.Context, arg1 types.TipSetKey) (api.CirculatingSupply, error) {
 	m.ctrl.T.Helper()
@@ -2680,6 +3248,35 @@ func (mr *MockFullNodeMockRecorder) StateWaitMsgLimited(arg0, arg1, arg2, arg3 i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateWaitMsgLimited", reflect.TypeOf((*MockFullNode)(nil).StateWaitMsgLimited), arg0, arg1, arg2, arg3)
 }
 
+// StateWatchActor mocks base method
+func (m *MockFullNode) StateWatchActor(arg0 context.Context, arg1 address.Address) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StateWatchActor", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StateWatchActor indicates an expected call of StateWatchActor
+func (mr *MockFullNodeMockRecorder) StateWatchActor(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateWatchActor", reflect.TypeOf((*MockFullNode)(nil).StateWatchActor), arg0, arg1)
+}
+
+// StateWatchedActors mocks base method
+func (m *MockFullNode) StateWatchedActors(arg0 context.Context) ([]api.WatchedActor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StateWatchedActors", arg0)
+	ret0, _ := ret[0].([]api.WatchedActor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StateWatchedActors indicates an expected call of StateWatchedActors
+func (mr *MockFullNodeMockRecorder) StateWatchedActors(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateWatchedActors", reflect.TypeOf((*MockFullNode)(nil).StateWatchedActors), arg0)
+}
+
 // SyncCheckBad mocks base method
 func (m *MockFullNode) SyncCheckBad(arg0 context.Context, arg1 cid.Cid) (string, error) {
 	m.ctrl.T.Helper()
@@ -2825,6 +3422,20 @@ func (mr *MockFullNodeMockRecorder) Version(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Version", reflect.TypeOf((*MockFullNode)(nil).Version), arg0)
 }
 
+// WalletAddWatch mocks base method
+func (m *MockFullNode) WalletAddWatch(arg0 context.Context, arg1 address.Address, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WalletAddWatch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WalletAddWatch indicates an expected call of WalletAddWatch
+func (mr *MockFullNodeMockRecorder) WalletAddWatch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WalletAddWatch", reflect.TypeOf((*MockFullNode)(nil).WalletAddWatch), arg0, arg1, arg2)
+}
+
 // WalletBalance mocks base method
 func (m *MockFullNode) WalletBalance(arg0 context.Context, arg1 address.Address) (big.Int, error) {
 	m.ctrl.T.Helper()
@@ -2929,6 +3540,35 @@ func (mr *MockFullNodeMockRecorder) WalletList(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WalletList", reflect.TypeOf((*MockFullNode)(nil).WalletList), arg0)
 }
 
+// WalletListWatch mocks base method
+func (m *MockFullNode) WalletListWatch(arg0 context.Context) ([]api.WatchOnlyAddress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WalletListWatch", arg0)
+	ret0, _ := ret[0].([]api.WatchOnlyAddress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WalletListWatch indicates an expected call of WalletListWatch
+func (mr *MockFullNodeMockRecorder) WalletListWatch(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WalletListWatch", reflect.TypeOf((*MockFullNode)(nil).WalletListWatch), arg0)
+}
+
+// WalletLock mocks base method
+func (m *MockFullNode) WalletLock(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WalletLock", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WalletLock indicates an expected call of WalletLock
+func (mr *MockFullNodeMockRecorder) WalletLock(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WalletLock", reflect.TypeOf((*MockFullNode)(nil).WalletLock), arg0)
+}
+
 // WalletNew mocks base method
 func (m *MockFullNode) WalletNew(arg0 context.Context, arg1 types.KeyType) (address.Address, error) {
 	m.ctrl.T.Helper()
@@ -2944,6 +3584,78 @@ func (mr *MockFullNodeMockRecorder) WalletNew(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WalletNew", reflect.TypeOf((*MockFullNode)(nil).WalletNew), arg0, arg1)
 }
 
+// WalletPolicyGet mocks base method
+func (m *MockFullNode) WalletPolicyGet(arg0 context.Context, arg1 address.Address) (*api.WalletUsagePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WalletPolicyGet", arg0, arg1)
+	ret0, _ := ret[0].(*api.WalletUsagePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WalletPolicyGet indicates an expected call of WalletPolicyGet
+func (mr *MockFullNodeMockRecorder) WalletPolicyGet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WalletPolicyGet", reflect.TypeOf((*MockFullNode)(nil).WalletPolicyGet), arg0, arg1)
+}
+
+// WalletPolicyList mocks base method
+func (m *MockFullNode) WalletPolicyList(arg0 context.Context) ([]api.WalletUsagePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WalletPolicyList", arg0)
+	ret0, _ := ret[0].([]api.WalletUsagePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WalletPolicyList indicates an expected call of WalletPolicyList
+func (mr *MockFullNodeMockRecorder) WalletPolicyList(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WalletPolicyList", reflect.TypeOf((*MockFullNode)(nil).WalletPolicyList), arg0)
+}
+
+// WalletPolicyRemove mocks base method
+func (m *MockFullNode) WalletPolicyRemove(arg0 context.Context, arg1 address.Address) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WalletPolicyRemove", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WalletPolicyRemove indicates an expected call of WalletPolicyRemove
+func (mr *MockFullNodeMockRecorder) WalletPolicyRemove(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WalletPolicyRemove", reflect.TypeOf((*MockFullNode)(nil).WalletPolicyRemove), arg0, arg1)
+}
+
+// WalletPolicySet mocks base method
+func (m *MockFullNode) WalletPolicySet(arg0 context.Context, arg1 api.WalletUsagePolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WalletPolicySet", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WalletPolicySet indicates an expected call of WalletPolicySet
+func (mr *MockFullNodeMockRecorder) WalletPolicySet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WalletPolicySet", reflect.TypeOf((*MockFullNode)(nil).WalletPolicySet), arg0, arg1)
+}
+
+// WalletRemoveWatch mocks base method
+func (m *MockFullNode) WalletRemoveWatch(arg0 context.Context, arg1 address.Address) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WalletRemoveWatch", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WalletRemoveWatch indicates an expected call of WalletRemoveWatch
+func (mr *MockFullNodeMockRecorder) WalletRemoveWatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WalletRemoveWatch", reflect.TypeOf((*MockFullNode)(nil).WalletRemoveWatch), arg0, arg1)
+}
+
 // WalletSetDefault mocks base method
 func (m *MockFullNode) WalletSetDefault(arg0 context.Context, arg1 address.Address) error {
 	m.ctrl.T.Helper()
@@ -2988,6 +3700,35 @@ func (mr *MockFullNodeMockRecorder) WalletSignMessage(arg0, arg1, arg2 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WalletSignMessage", reflect.TypeOf((*MockFullNode)(nil).WalletSignMessage), arg0, arg1, arg2)
 }
 
+// WalletSignMessageOverridePolicy mocks base method
+func (m *MockFullNode) WalletSignMessageOverridePolicy(arg0 context.Context, arg1 address.Address, arg2 *types.Message) (*types.SignedMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WalletSignMessageOverridePolicy", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*types.SignedMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WalletSignMessageOverridePolicy indicates an expected call of WalletSignMessageOverridePolicy
+func (mr *MockFullNodeMockRecorder) WalletSignMessageOverridePolicy(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WalletSignMessageOverridePolicy", reflect.TypeOf((*MockFullNode)(nil).WalletSignMessageOverridePolicy), arg0, arg1, arg2)
+}
+
+// WalletUnlock mocks base method
+func (m *MockFullNode) WalletUnlock(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WalletUnlock", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WalletUnlock indicates an expected call of WalletUnlock
+func (mr *MockFullNodeMockRecorder) WalletUnlock(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WalletUnlock", reflect.TypeOf((*MockFullNode)(nil).WalletUnlock), arg0, arg1)
+}
+
 // WalletValidateAddress mocks base method
 func (m *MockFullNode) WalletValidateAddress(arg0 context.Context, arg1 string) (address.Address, error) {
 	m.ctrl.T.Helper()
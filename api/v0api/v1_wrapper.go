@@ -57,4 +57,8 @@ func (w *WrapperV1Full) Version(ctx context.Context) (api.APIVersion, error) {
 	return ver, nil
 }
 
+func (w *WrapperV1Full) Methods(ctx context.Context) (map[string]api.MethodStability, error) {
+	return api.GetMethodStability(new(FullNodeStruct)), nil
+}
+
 var _ FullNode = &WrapperV1Full{}
@@ -136,6 +136,9 @@ type SplitStore struct {
 	env MarkSetEnv
 
 	markSetSize int64
+
+	protectLk  sync.Mutex
+	protectors []func() []*types.TipSet
 }
 
 var _ bstore.Blockstore = (*SplitStore)(nil)
@@ -392,6 +395,37 @@ func (s *SplitStore) Start(chain ChainAccessor) error {
 	return nil
 }
 
+// AddProtector registers a function returning tipsets whose state must be kept
+// in the hotstore by compaction, in addition to the tipsets past the boundary
+// epoch.
+func (s *SplitStore) AddProtector(protector func() []*types.TipSet) {
+	s.protectLk.Lock()
+	defer s.protectLk.Unlock()
+
+	s.protectors = append(s.protectors, protector)
+}
+
+// markProtected marks the objects reachable from the state of the protected
+// tipsets
+func (s *SplitStore) markProtected(markSet MarkSet) error {
+	s.protectLk.Lock()
+	protectors := s.protectors
+	s.protectLk.Unlock()
+
+	for _, protector := range protectors {
+		for _, ts := range protector() {
+			err := s.chain.WalkSnapshot(context.Background(), ts, 1, s.skipOldMsgs, s.skipMsgReceipts,
+				func(cid cid.Cid) error {
+					return markSet.Mark(cid)
+				})
+			if err != nil {
+				return xerrors.Errorf("error marking protected tipset %s: %w", ts.Key(), err)
+			}
+		}
+	}
+	return nil
+}
+
 func (s *SplitStore) Close() error {
 	atomic.StoreInt32(&s.closing, 1)
 
@@ -617,6 +651,10 @@ func (s *SplitStore) compactSimple(curTs *types.TipSet) error {
 		s.markSetSize = count + count>>2 // overestimate a bit
 	}
 
+	if err := s.markProtected(coldSet); err != nil {
+		return err
+	}
+
 	log.Infow("marking done", "took", time.Since(startMark))
 
 	// 2. move cold unreachable objects to the coldstore
@@ -867,6 +905,10 @@ func (s *SplitStore) compactFull(curTs *types.TipSet) error {
 		s.markSetSize = count + count>>2 // overestimate a bit
 	}
 
+	if err := s.markProtected(hotSet); err != nil {
+		return err
+	}
+
 	// Phase 1b: mark all reachable CIDs in the cold range
 	coldTs, err := s.chain.GetTipsetByHeight(context.Background(), coldEpoch, curTs, true)
 	if err != nil {
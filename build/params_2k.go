@@ -82,10 +82,20 @@ func init() {
 	UpgradeTurboHeight = getUpgradeHeight("LOTUS_ACTORSV4_HEIGHT", UpgradeTurboHeight)
 	UpgradeHyperdriveHeight = getUpgradeHeight("LOTUS_HYPERDRIVE_HEIGHT", UpgradeHyperdriveHeight)
 
+	if bd, found := os.LookupEnv("LOTUS_2K_BLOCK_DELAY"); found {
+		d, err := strconv.ParseUint(bd, 10, 64)
+		if err != nil || d == 0 {
+			log.Panicf("failed to parse LOTUS_2K_BLOCK_DELAY env var")
+		}
+		BlockDelaySecs = d
+	}
+
 	BuildType |= Build2k
 }
 
-const BlockDelaySecs = uint64(4)
+// BlockDelaySecs can be set with the LOTUS_2K_BLOCK_DELAY env var, the same
+// for all the nodes and miners of the network
+var BlockDelaySecs = uint64(4)
 
 const PropagationDelaySecs = uint64(1)
 
@@ -0,0 +1,344 @@
+package actorcache
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	logging "github.com/ipfs/go-log/v2"
+	"go.opencensus.io/stats"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/chain/actors/builtin"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/miner"
+	"github.com/filecoin-project/lotus/chain/stmgr"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/metrics"
+)
+
+var log = logging.Logger("actorcache")
+
+// stateLoader is the state access needed to resolve watched actors
+// (an interface for the tests)
+type stateLoader interface {
+	LoadActor(ctx context.Context, addr address.Address, ts *types.TipSet) (*types.Actor, error)
+	LookupID(ctx context.Context, addr address.Address, ts *types.TipSet) (address.Address, error)
+	MinerInfo(ctx context.Context, act *types.Actor) (miner.MinerInfo, error)
+}
+
+type smLoader struct {
+	*stmgr.StateManager
+}
+
+func (l smLoader) MinerInfo(ctx context.Context, act *types.Actor) (miner.MinerInfo, error) {
+	mas, err := miner.Load(l.ChainStore().ActorStore(ctx), act)
+	if err != nil {
+		return miner.MinerInfo{}, xerrors.Errorf("failed to load miner actor state: %w", err)
+	}
+	return mas.Info()
+}
+
+type watchedActor struct {
+	addr address.Address
+	hits uint64 // atomic
+
+	err error // error of the last refresh
+}
+
+type entry struct {
+	w *watchedActor
+
+	id    address.Address
+	actor *types.Actor
+	info  *miner.MinerInfo // nil for non-miner actors
+}
+
+// WatchedActors keeps the state of a set of actors resolved at the chain head,
+// so that hot StateGetActor / StateMinerInfo calls at head don't need to walk
+// the state tree.
+//
+// Cached state is only ever served for the exact tipset it was resolved at.
+// Entries resolved at a tipset which was reverted are dropped.
+type WatchedActors struct {
+	ld stateLoader
+
+	ctx      context.Context
+	shutdown context.CancelFunc
+	refresh  chan struct{}
+
+	lk      sync.RWMutex
+	actors  map[address.Address]*watchedActor
+	head    *types.TipSet // latest head we were notified of
+	ts      *types.TipSet // tipset the entries were resolved at
+	entries map[address.Address]*entry
+}
+
+// NewWatchedActors creates the cache, watching the given addresses. It is
+// refreshed from HeadChange after Start was called.
+func NewWatchedActors(sm *stmgr.StateManager, addrs []address.Address) *WatchedActors {
+	return newWatchedActors(smLoader{sm}, addrs)
+}
+
+func newWatchedActors(ld stateLoader, addrs []address.Address) *WatchedActors {
+	ctx, cancel := context.WithCancel(context.Background())
+	wa := &WatchedActors{
+		ld:       ld,
+		ctx:      ctx,
+		shutdown: cancel,
+		refresh:  make(chan struct{}, 1),
+		actors:   map[address.Address]*watchedActor{},
+		entries:  map[address.Address]*entry{},
+	}
+	for _, addr := range addrs {
+		wa.actors[addr] = &watchedActor{addr: addr}
+	}
+	return wa
+}
+
+func (wa *WatchedActors) Start() {
+	go wa.run()
+}
+
+func (wa *WatchedActors) Stop() {
+	wa.shutdown()
+}
+
+func (wa *WatchedActors) run() {
+	for {
+		select {
+		case <-wa.refresh:
+			wa.lk.RLock()
+			head := wa.head
+			wa.lk.RUnlock()
+
+			wa.refreshAt(head)
+		case <-wa.ctx.Done():
+			return
+		}
+	}
+}
+
+// HeadChange is the chain store head change notifee
+func (wa *WatchedActors) HeadChange(rev, app []*types.TipSet) error {
+	wa.lk.Lock()
+	for _, ts := range rev {
+		if wa.ts != nil && wa.ts.Equals(ts) {
+			wa.dropEntries()
+		}
+	}
+	if len(app) > 0 {
+		wa.head = app[len(app)-1]
+	}
+	wa.lk.Unlock()
+
+	if len(app) > 0 {
+		wa.scheduleRefresh()
+	}
+	return nil
+}
+
+// scheduleRefresh makes the worker refresh the cache at the latest head,
+// unless a refresh is already pending
+func (wa *WatchedActors) scheduleRefresh() {
+	select {
+	case wa.refresh <- struct{}{}:
+	default:
+	}
+}
+
+// must be called with wa.lk held
+func (wa *WatchedActors) dropEntries() {
+	wa.ts = nil
+	wa.entries = map[address.Address]*entry{}
+}
+
+func (wa *WatchedActors) refreshAt(ts *types.TipSet) {
+	ctx := wa.ctx
+	start := time.Now()
+
+	wa.lk.RLock()
+	actors := make([]*watchedActor, 0, len(wa.actors))
+	for _, w := range wa.actors {
+		actors = append(actors, w)
+	}
+	wa.lk.RUnlock()
+
+	entries := make(map[address.Address]*entry, 2*len(actors))
+	errs := make(map[*watchedActor]error, len(actors))
+	for _, w := range actors {
+		e, err := wa.resolve(ctx, w, ts)
+		if err != nil {
+			log.Debugw("refreshing watched actor", "actor", w.addr, "height", ts.Height(), "error", err)
+			errs[w] = err
+			continue
+		}
+		entries[w.addr] = e
+		entries[e.id] = e
+	}
+
+	wa.lk.Lock()
+	defer wa.lk.Unlock()
+
+	if wa.head == nil || !wa.head.Equals(ts) {
+		// the head moved on (or was reverted) while we were resolving, the
+		// refresh for the new head is already scheduled
+		return
+	}
+
+	for _, w := range actors {
+		w.err = errs[w]
+	}
+	for addr, e := range entries {
+		if wa.actors[e.w.addr] != e.w {
+			// unwatched during the refresh
+			delete(entries, addr)
+		}
+	}
+
+	wa.ts = ts
+	wa.entries = entries
+
+	stats.Record(ctx, metrics.WatchedActorsRefreshDuration.M(metrics.SinceInMilliseconds(start)))
+	stats.Record(ctx, metrics.WatchedActorsCount.M(int64(len(wa.actors))))
+}
+
+func (wa *WatchedActors) resolve(ctx context.Context, w *watchedActor, ts *types.TipSet) (*entry, error) {
+	id, err := wa.ld.LookupID(ctx, w.addr, ts)
+	if err != nil {
+		return nil, xerrors.Errorf("resolving actor ID: %w", err)
+	}
+
+	act, err := wa.ld.LoadActor(ctx, id, ts)
+	if err != nil {
+		return nil, xerrors.Errorf("loading actor: %w", err)
+	}
+
+	e := &entry{
+		w:     w,
+		id:    id,
+		actor: act,
+	}
+
+	if builtin.IsStorageMinerActor(act.Code) {
+		info, err := wa.ld.MinerInfo(ctx, act)
+		if err != nil {
+			return nil, xerrors.Errorf("loading miner info: %w", err)
+		}
+		e.info = &info
+	}
+
+	return e, nil
+}
+
+// lookup returns the cache entry for addr if it was resolved at ts, recording
+// a hit or miss for watched actors
+func (wa *WatchedActors) lookup(ctx context.Context, addr address.Address, ts *types.TipSet, needInfo bool) (*entry, bool) {
+	wa.lk.RLock()
+	defer wa.lk.RUnlock()
+
+	e, ok := wa.entries[addr]
+	if !ok {
+		if _, watched := wa.actors[addr]; watched {
+			stats.Record(ctx, metrics.WatchedActorsMiss.M(1))
+		}
+		return nil, false
+	}
+
+	if wa.ts == nil || ts.Key() != wa.ts.Key() || (needInfo && e.info == nil) {
+		stats.Record(ctx, metrics.WatchedActorsMiss.M(1))
+		return nil, false
+	}
+
+	atomic.AddUint64(&e.w.hits, 1)
+	stats.Record(ctx, metrics.WatchedActorsHit.M(1))
+	return e, true
+}
+
+// Actor returns the cached actor header of a watched actor at the tipset. It
+// returns false when the state at that tipset isn't cached.
+func (wa *WatchedActors) Actor(ctx context.Context, addr address.Address, ts *types.TipSet) (*types.Actor, bool) {
+	e, ok := wa.lookup(ctx, addr, ts, false)
+	if !ok {
+		return nil, false
+	}
+	act := *e.actor
+	return &act, true
+}
+
+// MinerInfo returns the cached info of a watched miner actor at the tipset. It
+// returns false when the state at that tipset isn't cached.
+func (wa *WatchedActors) MinerInfo(ctx context.Context, addr address.Address, ts *types.TipSet) (miner.MinerInfo, bool) {
+	e, ok := wa.lookup(ctx, addr, ts, true)
+	if !ok {
+		return miner.MinerInfo{}, false
+	}
+	return *e.info, true
+}
+
+// Watch adds an actor to the cache. Its state becomes available once the
+// cache is refreshed at the current head.
+func (wa *WatchedActors) Watch(addr address.Address) {
+	wa.lk.Lock()
+	if _, ok := wa.actors[addr]; ok {
+		wa.lk.Unlock()
+		return
+	}
+	wa.actors[addr] = &watchedActor{addr: addr}
+	hasHead := wa.head != nil
+	wa.lk.Unlock()
+
+	if hasHead {
+		wa.scheduleRefresh()
+	}
+}
+
+// Unwatch removes an actor from the cache
+func (wa *WatchedActors) Unwatch(addr address.Address) error {
+	wa.lk.Lock()
+	defer wa.lk.Unlock()
+
+	w, ok := wa.actors[addr]
+	if !ok {
+		return xerrors.Errorf("actor %s is not watched", addr)
+	}
+	delete(wa.actors, addr)
+
+	if e, ok := wa.entries[addr]; ok && e.w == w {
+		delete(wa.entries, e.id)
+		delete(wa.entries, addr)
+	}
+	return nil
+}
+
+// List returns the watched actors with the state of their cache entry
+func (wa *WatchedActors) List() []api.WatchedActor {
+	wa.lk.RLock()
+	defer wa.lk.RUnlock()
+
+	out := make([]api.WatchedActor, 0, len(wa.actors))
+	for addr, w := range wa.actors {
+		wi := api.WatchedActor{
+			Address: addr,
+			Hits:    atomic.LoadUint64(&w.hits),
+		}
+		if e, ok := wa.entries[addr]; ok {
+			wi.ID = e.id
+			wi.Miner = e.info != nil
+			wi.Height = wa.ts.Height()
+		}
+		if w.err != nil {
+			wi.Error = w.err.Error()
+		}
+		out = append(out, wi)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Address.String() < out[j].Address.String()
+	})
+	return out
+}
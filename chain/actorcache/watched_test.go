@@ -0,0 +1,152 @@
+package actorcache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	builtin5 "github.com/filecoin-project/specs-actors/v5/actors/builtin"
+
+	"github.com/filecoin-project/lotus/chain/actors/builtin/miner"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/chain/types/mock"
+)
+
+type fakeLoader struct {
+	ids    map[address.Address]address.Address
+	miners map[address.Address]bool
+	// actor nonce per tipset, to tell which tipset state was served from
+	nonces map[types.TipSetKey]uint64
+}
+
+func (f *fakeLoader) LookupID(ctx context.Context, addr address.Address, ts *types.TipSet) (address.Address, error) {
+	if addr.Protocol() == address.ID {
+		return addr, nil
+	}
+	id, ok := f.ids[addr]
+	if !ok {
+		return address.Undef, xerrors.Errorf("actor not found")
+	}
+	return id, nil
+}
+
+func (f *fakeLoader) LoadActor(ctx context.Context, addr address.Address, ts *types.TipSet) (*types.Actor, error) {
+	act := &types.Actor{
+		Code:    builtin5.AccountActorCodeID,
+		Nonce:   f.nonces[ts.Key()],
+		Balance: types.NewInt(0),
+	}
+	if f.miners[addr] {
+		act.Code = builtin5.StorageMinerActorCodeID
+	}
+	return act, nil
+}
+
+func (f *fakeLoader) MinerInfo(ctx context.Context, act *types.Actor) (miner.MinerInfo, error) {
+	return miner.MinerInfo{WindowPoStPartitionSectors: act.Nonce}, nil
+}
+
+func TestWatchedActors(t *testing.T) {
+	ctx := context.Background()
+
+	ts0 := mock.TipSet(mock.MkBlock(nil, 1, 0))
+	ts1 := mock.TipSet(mock.MkBlock(ts0, 1, 1))
+	ts1b := mock.TipSet(mock.MkBlock(ts0, 1, 2))
+
+	acct, err := address.NewIDAddress(1000)
+	require.NoError(t, err)
+	robust, err := address.NewActorAddress([]byte("robust"))
+	require.NoError(t, err)
+	maddr, err := address.NewIDAddress(3000)
+	require.NoError(t, err)
+	unwatched, err := address.NewIDAddress(4000)
+	require.NoError(t, err)
+
+	ld := &fakeLoader{
+		ids:    map[address.Address]address.Address{robust: acct},
+		miners: map[address.Address]bool{maddr: true},
+		nonces: map[types.TipSetKey]uint64{ts0.Key(): 10, ts1.Key(): 11, ts1b.Key(): 12},
+	}
+	wa := newWatchedActors(ld, []address.Address{robust, maddr})
+
+	require.NoError(t, wa.HeadChange(nil, []*types.TipSet{ts1}))
+	wa.refreshAt(ts1)
+
+	// served for the head, by robust and ID address
+	for _, addr := range []address.Address{robust, acct, maddr} {
+		act, ok := wa.Actor(ctx, addr, ts1)
+		require.True(t, ok, addr)
+		require.EqualValues(t, 11, act.Nonce)
+	}
+	info, ok := wa.MinerInfo(ctx, maddr, ts1)
+	require.True(t, ok)
+	require.EqualValues(t, 11, info.WindowPoStPartitionSectors)
+
+	_, ok = wa.MinerInfo(ctx, robust, ts1)
+	require.False(t, ok, "not a miner")
+	_, ok = wa.Actor(ctx, maddr, ts0)
+	require.False(t, ok, "other tipset")
+	_, ok = wa.Actor(ctx, unwatched, ts1)
+	require.False(t, ok, "not watched")
+
+	// reorg to ts1b, the ts1 entries must be gone immediately
+	require.NoError(t, wa.HeadChange([]*types.TipSet{ts1}, []*types.TipSet{ts1b}))
+	_, ok = wa.Actor(ctx, maddr, ts1)
+	require.False(t, ok)
+	_, ok = wa.Actor(ctx, maddr, ts1b)
+	require.False(t, ok)
+
+	// a refresh at the reverted head finishing late must not be installed
+	wa.refreshAt(ts1)
+	_, ok = wa.Actor(ctx, maddr, ts1)
+	require.False(t, ok)
+
+	wa.refreshAt(ts1b)
+	act, ok := wa.Actor(ctx, maddr, ts1b)
+	require.True(t, ok)
+	require.EqualValues(t, 12, act.Nonce)
+
+	require.NoError(t, wa.Unwatch(robust))
+	require.Error(t, wa.Unwatch(robust))
+	_, ok = wa.Actor(ctx, acct, ts1b)
+	require.False(t, ok)
+
+	list := wa.List()
+	require.Len(t, list, 1)
+	require.Equal(t, maddr, list[0].Address)
+	require.True(t, list[0].Miner)
+	require.Equal(t, ts1b.Height(), list[0].Height)
+	require.EqualValues(t, 3, list[0].Hits)
+}
+
+func TestWatchedActorsRefresh(t *testing.T) {
+	ctx := context.Background()
+
+	ts0 := mock.TipSet(mock.MkBlock(nil, 1, 0))
+	ts1 := mock.TipSet(mock.MkBlock(ts0, 1, 1))
+
+	addr, err := address.NewIDAddress(1000)
+	require.NoError(t, err)
+
+	ld := &fakeLoader{nonces: map[types.TipSetKey]uint64{ts0.Key(): 10, ts1.Key(): 11}}
+	wa := newWatchedActors(ld, nil)
+	wa.Start()
+	defer wa.Stop()
+
+	require.NoError(t, wa.HeadChange(nil, []*types.TipSet{ts0}))
+	wa.Watch(addr)
+	require.Eventually(t, func() bool {
+		_, ok := wa.Actor(ctx, addr, ts0)
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, wa.HeadChange(nil, []*types.TipSet{ts1}))
+	require.Eventually(t, func() bool {
+		act, ok := wa.Actor(ctx, addr, ts1)
+		return ok && act.Nonce == 11
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,186 @@
+package denylist
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	logging "github.com/ipfs/go-log/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+
+	"github.com/filecoin-project/lotus/chain/actors/builtin"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/multisig"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+var log = logging.Logger("denylist")
+
+// ErrDenied is returned (wrapped) when a message is sent to a denied address
+var ErrDenied = xerrors.New("address is on the send denylist")
+
+// Resolver is the state access needed to normalize addresses
+type Resolver interface {
+	LookupID(ctx context.Context, addr address.Address, ts *types.TipSet) (address.Address, error)
+	LoadActor(ctx context.Context, addr address.Address, ts *types.TipSet) (*types.Actor, error)
+}
+
+// Denylist holds addresses the node must never send to. Addresses are
+// configured directly, or read from a file (one address per line, # starts a
+// comment) which is read again whenever it changes.
+type Denylist struct {
+	static []address.Address
+	file   string
+
+	lk       sync.Mutex
+	modTime  time.Time
+	fromFile []address.Address
+}
+
+func New(addrs []address.Address, file string) *Denylist {
+	return &Denylist{
+		static: addrs,
+		file:   file,
+	}
+}
+
+// Empty is true when the denylist has no source of addresses
+func (d *Denylist) Empty() bool {
+	return d == nil || (len(d.static) == 0 && d.file == "")
+}
+
+// Load reads the denylist file, checking that it can be used
+func (d *Denylist) Load() error {
+	_, err := d.addresses()
+	return err
+}
+
+// addresses returns the denied addresses, reloading the file if it was
+// modified. A file which can't be read is an error, we don't want to send
+// anything while we don't know what is denied.
+func (d *Denylist) addresses() ([]address.Address, error) {
+	if d.file == "" {
+		return d.static, nil
+	}
+
+	d.lk.Lock()
+	defer d.lk.Unlock()
+
+	fi, err := os.Stat(d.file)
+	if err != nil {
+		return nil, xerrors.Errorf("reading denylist file: %w", err)
+	}
+
+	if d.fromFile == nil || !fi.ModTime().Equal(d.modTime) {
+		addrs, err := readFile(d.file)
+		if err != nil {
+			return nil, err
+		}
+		log.Infow("loaded send denylist", "file", d.file, "addresses", len(addrs))
+
+		d.fromFile = addrs
+		d.modTime = fi.ModTime()
+	}
+
+	return append(append([]address.Address{}, d.static...), d.fromFile...), nil
+}
+
+func readFile(file string) ([]address.Address, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, xerrors.Errorf("reading denylist file: %w", err)
+	}
+
+	addrs := []address.Address{}
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for line := 1; s.Scan(); line++ {
+		l := s.Text()
+		if i := strings.IndexByte(l, '#'); i >= 0 {
+			l = l[:i]
+		}
+		l = strings.TrimSpace(l)
+		if l == "" {
+			continue
+		}
+
+		addr, err := address.NewFromString(l)
+		if err != nil {
+			return nil, xerrors.Errorf("denylist file %s, line %d: %w", file, line, err)
+		}
+		addrs = append(addrs, addr)
+	}
+	if err := s.Err(); err != nil {
+		return nil, xerrors.Errorf("reading denylist file: %w", err)
+	}
+
+	return addrs, nil
+}
+
+// Check fails if the message is sent to a denied address. For multisig
+// proposals the target of the proposed message is checked as well.
+//
+// Addresses are compared by their ID address at ts when they have one, so a
+// denied key address also matches its ID address and the other way around.
+func (d *Denylist) Check(ctx context.Context, r Resolver, ts *types.TipSet, msg *types.Message) error {
+	if d.Empty() {
+		return nil
+	}
+
+	denied, err := d.addresses()
+	if err != nil {
+		return err
+	}
+	if len(denied) == 0 {
+		return nil
+	}
+
+	set := make(map[address.Address]struct{}, len(denied))
+	for _, addr := range denied {
+		set[normalize(ctx, r, ts, addr)] = struct{}{}
+	}
+
+	to := normalize(ctx, r, ts, msg.To)
+	if _, ok := set[to]; ok {
+		return xerrors.Errorf("sending to %s: %w", msg.To, ErrDenied)
+	}
+
+	if msg.Method != multisig.Methods.Propose || to.Protocol() != address.ID {
+		return nil
+	}
+
+	act, err := r.LoadActor(ctx, to, ts)
+	if err != nil || !builtin.IsMultisigActor(act.Code) {
+		return nil
+	}
+
+	var params multisig.ProposeParams
+	if err := params.UnmarshalCBOR(bytes.NewReader(msg.Params)); err != nil {
+		return xerrors.Errorf("decoding multisig proposal params: %w", err)
+	}
+
+	if _, ok := set[normalize(ctx, r, ts, params.To)]; ok {
+		return xerrors.Errorf("proposing a send to %s through multisig %s: %w", params.To, msg.To, ErrDenied)
+	}
+
+	return nil
+}
+
+// normalize returns the ID address of addr, or addr itself if it has no
+// actor (yet)
+func normalize(ctx context.Context, r Resolver, ts *types.TipSet, addr address.Address) address.Address {
+	if addr.Protocol() == address.ID {
+		return addr
+	}
+
+	id, err := r.LookupID(ctx, addr, ts)
+	if err != nil {
+		return addr
+	}
+	return id
+}
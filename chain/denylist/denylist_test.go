@@ -0,0 +1,98 @@
+package denylist
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/big"
+	builtin5 "github.com/filecoin-project/specs-actors/v5/actors/builtin"
+
+	"github.com/filecoin-project/lotus/chain/actors"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/multisig"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+type fakeResolver struct {
+	ids      map[address.Address]address.Address
+	multisig map[address.Address]bool
+}
+
+func (r *fakeResolver) LookupID(ctx context.Context, addr address.Address, ts *types.TipSet) (address.Address, error) {
+	id, ok := r.ids[addr]
+	if !ok {
+		return address.Undef, xerrors.Errorf("actor not found")
+	}
+	return id, nil
+}
+
+func (r *fakeResolver) LoadActor(ctx context.Context, addr address.Address, ts *types.TipSet) (*types.Actor, error) {
+	if r.multisig[addr] {
+		return &types.Actor{Code: builtin5.MultisigActorCodeID}, nil
+	}
+	return &types.Actor{Code: builtin5.AccountActorCodeID}, nil
+}
+
+func TestDenylist(t *testing.T) {
+	ctx := context.Background()
+
+	mustAddr := func(addr address.Address, err error) address.Address {
+		require.NoError(t, err)
+		return addr
+	}
+
+	denied := mustAddr(address.NewSecp256k1Address([]byte("denied")))
+	deniedID := mustAddr(address.NewIDAddress(100))
+	other := mustAddr(address.NewIDAddress(101))
+	msig := mustAddr(address.NewIDAddress(102))
+	fileDenied := mustAddr(address.NewSecp256k1Address([]byte("denied later")))
+
+	r := &fakeResolver{
+		ids:      map[address.Address]address.Address{denied: deniedID},
+		multisig: map[address.Address]bool{msig: true},
+	}
+
+	file := filepath.Join(t.TempDir(), "denylist")
+	require.NoError(t, ioutil.WriteFile(file, []byte("# compliance list\n\n"), 0644))
+
+	dl := New([]address.Address{denied}, file)
+	require.NoError(t, dl.Load())
+
+	check := func(to address.Address) error {
+		return dl.Check(ctx, r, nil, &types.Message{To: to})
+	}
+
+	require.True(t, xerrors.Is(check(denied), ErrDenied))
+	require.True(t, xerrors.Is(check(deniedID), ErrDenied), "ID address of a denied address")
+	require.NoError(t, check(other))
+	require.NoError(t, check(fileDenied))
+
+	// the file is picked up once it changes
+	require.NoError(t, ioutil.WriteFile(file, []byte(fileDenied.String()+" # added later\n"), 0644))
+	require.NoError(t, os.Chtimes(file, time.Now(), time.Now().Add(time.Minute)))
+	require.True(t, xerrors.Is(check(fileDenied), ErrDenied))
+
+	// multisig proposals are checked by their target
+	propose := func(to address.Address) *types.Message {
+		params, err := actors.SerializeParams(&multisig.ProposeParams{To: to, Value: big.Zero()})
+		require.NoError(t, err)
+		return &types.Message{To: msig, Method: multisig.Methods.Propose, Params: params}
+	}
+	require.True(t, xerrors.Is(dl.Check(ctx, r, nil, propose(denied)), ErrDenied))
+	require.NoError(t, dl.Check(ctx, r, nil, propose(other)))
+
+	// a broken file fails closed
+	require.NoError(t, ioutil.WriteFile(file, []byte("not an address\n"), 0644))
+	require.NoError(t, os.Chtimes(file, time.Now(), time.Now().Add(2*time.Minute)))
+	require.Error(t, check(other))
+
+	var empty *Denylist
+	require.NoError(t, empty.Check(ctx, r, nil, &types.Message{To: denied}))
+}
@@ -0,0 +1,315 @@
+// Package follow delivers chain head changes to consumers, such as indexers,
+// one tipset at a time, in chain order, and at least once. The tipset each
+// consumer acknowledged last is persisted, so that after a restart delivery
+// resumes where it stopped, within the finality window.
+package follow
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/namespace"
+	logging "github.com/ipfs/go-log/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/chain/actors/policy"
+	"github.com/filecoin-project/lotus/chain/store"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+var log = logging.Logger("chainfollow")
+
+var cursorPrefix = datastore.NewKey("/chainfollow/cursor")
+
+var (
+	minRetryDelay = time.Second
+	maxRetryDelay = time.Minute
+)
+
+// Handler consumes tipsets. Apply is called for every tipset added to the
+// chain, and Revert for every tipset removed from it by a reorg, with the
+// parent of a tipset always handled before it (and reverted after it).
+//
+// A tipset is acknowledged when the call for it returns nil. When it returns
+// an error, the call is retried later, so handlers must tolerate getting the
+// same tipset more than once, also after a restart.
+type Handler interface {
+	Apply(ctx context.Context, ts *types.TipSet) error
+	Revert(ctx context.Context, ts *types.TipSet) error
+}
+
+// chainAccess is the chain store access needed by the Follower (an interface
+// for the tests)
+type chainAccess interface {
+	GetHeaviestTipSet() *types.TipSet
+	LoadTipSet(tsk types.TipSetKey) (*types.TipSet, error)
+	GetTipsetByHeight(ctx context.Context, h abi.ChainEpoch, ts *types.TipSet, prev bool) (*types.TipSet, error)
+}
+
+type consumer struct {
+	name   string
+	h      Handler
+	notify chan struct{}
+
+	lk     sync.Mutex
+	cursor *types.TipSet
+	err    error
+}
+
+// Follower delivers head changes to the registered consumers
+type Follower struct {
+	cs chainAccess
+	ds datastore.Batching
+
+	ctx      context.Context
+	shutdown context.CancelFunc
+	wg       sync.WaitGroup
+
+	lk        sync.Mutex
+	started   bool
+	consumers map[string]*consumer
+}
+
+func NewFollower(cs *store.ChainStore, ds datastore.Batching) *Follower {
+	return newFollower(cs, ds)
+}
+
+func newFollower(cs chainAccess, ds datastore.Batching) *Follower {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Follower{
+		cs:        cs,
+		ds:        namespace.Wrap(ds, cursorPrefix),
+		ctx:       ctx,
+		shutdown:  cancel,
+		consumers: map[string]*consumer{},
+	}
+}
+
+// Register adds a consumer. Its cursor is kept under the name, so a consumer
+// must keep the same name across restarts. Consumers without a cursor start at
+// the chain head.
+func (f *Follower) Register(name string, h Handler) error {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+
+	if _, ok := f.consumers[name]; ok {
+		return xerrors.Errorf("chain follow consumer %s already registered", name)
+	}
+
+	c := &consumer{
+		name:   name,
+		h:      h,
+		notify: make(chan struct{}, 1),
+	}
+	f.consumers[name] = c
+
+	if f.started {
+		f.startConsumer(c)
+	}
+	return nil
+}
+
+func (f *Follower) Start() {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+
+	f.started = true
+	for _, c := range f.consumers {
+		f.startConsumer(c)
+	}
+}
+
+func (f *Follower) Stop() {
+	f.shutdown()
+	f.wg.Wait()
+}
+
+// must be called with f.lk held
+func (f *Follower) startConsumer(c *consumer) {
+	f.wg.Add(1)
+	go f.run(c)
+	c.notify <- struct{}{}
+}
+
+// HeadChange is the chain store head change notifee
+func (f *Follower) HeadChange(_, _ []*types.TipSet) error {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+
+	for _, c := range f.consumers {
+		select {
+		case c.notify <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+func (f *Follower) run(c *consumer) {
+	defer f.wg.Done()
+
+	delay := minRetryDelay
+	for {
+		select {
+		case <-c.notify:
+		case <-f.ctx.Done():
+			return
+		}
+
+		for {
+			err := f.catchUp(c)
+			c.lk.Lock()
+			c.err = err
+			c.lk.Unlock()
+			if err == nil {
+				delay = minRetryDelay
+				break
+			}
+
+			log.Warnw("chain follow consumer failed, retrying", "consumer", c.name, "delay", delay, "error", err)
+			select {
+			case <-time.After(delay):
+			case <-f.ctx.Done():
+				return
+			}
+			if delay *= 2; delay > maxRetryDelay {
+				delay = maxRetryDelay
+			}
+		}
+	}
+}
+
+// catchUp delivers tipsets to the consumer until its cursor is at the head
+func (f *Follower) catchUp(c *consumer) error {
+	for {
+		head := f.cs.GetHeaviestTipSet()
+
+		cursor, err := f.loadCursor(c, head)
+		if err != nil {
+			return err
+		}
+		if cursor.Equals(head) {
+			return nil
+		}
+
+		revert, apply, err := store.ReorgOps(f.cs.LoadTipSet, cursor, head)
+		if err != nil {
+			return xerrors.Errorf("computing tipsets to deliver: %w", err)
+		}
+
+		for _, ts := range revert {
+			if err := c.h.Revert(f.ctx, ts); err != nil {
+				return xerrors.Errorf("reverting %s (height %d): %w", ts.Key(), ts.Height(), err)
+			}
+
+			parent, err := f.cs.LoadTipSet(ts.Parents())
+			if err != nil {
+				return xerrors.Errorf("loading parent of reverted tipset: %w", err)
+			}
+			if err := f.setCursor(c, parent); err != nil {
+				return err
+			}
+		}
+
+		for i := len(apply) - 1; i >= 0; i-- {
+			ts := apply[i]
+			if err := c.h.Apply(f.ctx, ts); err != nil {
+				return xerrors.Errorf("applying %s (height %d): %w", ts.Key(), ts.Height(), err)
+			}
+			if err := f.setCursor(c, ts); err != nil {
+				return err
+			}
+
+			if f.ctx.Err() != nil {
+				return nil
+			}
+		}
+	}
+}
+
+// loadCursor returns the tipset the consumer acknowledged last, reading it
+// from the datastore after a restart
+func (f *Follower) loadCursor(c *consumer, head *types.TipSet) (*types.TipSet, error) {
+	c.lk.Lock()
+	cursor := c.cursor
+	c.lk.Unlock()
+	if cursor != nil {
+		return cursor, nil
+	}
+
+	b, err := f.ds.Get(datastore.NewKey(c.name))
+	switch {
+	case err == datastore.ErrNotFound:
+		log.Infow("new chain follow consumer, starting at head", "consumer", c.name, "height", head.Height())
+		return head, f.setCursor(c, head)
+	case err != nil:
+		return nil, xerrors.Errorf("reading cursor: %w", err)
+	}
+
+	tsk, err := types.TipSetKeyFromBytes(b)
+	if err != nil {
+		return nil, xerrors.Errorf("decoding cursor: %w", err)
+	}
+
+	cursor, err = f.cs.LoadTipSet(tsk)
+	if err == nil && cursor.Height() >= head.Height()-policy.ChainFinality {
+		return cursor, f.setCursor(c, cursor)
+	}
+
+	// too far behind (or pruned), resume at the start of the finality window
+	cursor, err = f.cs.GetTipsetByHeight(f.ctx, head.Height()-policy.ChainFinality, head, true)
+	if err != nil {
+		return nil, xerrors.Errorf("loading tipset at the start of the finality window: %w", err)
+	}
+	log.Warnw("chain follow cursor outside of the finality window, tipsets were skipped", "consumer", c.name, "cursor", tsk, "resuming", cursor.Height())
+	return cursor, f.setCursor(c, cursor)
+}
+
+func (f *Follower) setCursor(c *consumer, ts *types.TipSet) error {
+	if err := f.ds.Put(datastore.NewKey(c.name), ts.Key().Bytes()); err != nil {
+		return xerrors.Errorf("persisting cursor: %w", err)
+	}
+
+	c.lk.Lock()
+	c.cursor = ts
+	c.lk.Unlock()
+	return nil
+}
+
+// Consumers returns the registered consumers with their cursor
+func (f *Follower) Consumers() []api.ChainFollowConsumer {
+	head := f.cs.GetHeaviestTipSet()
+
+	f.lk.Lock()
+	defer f.lk.Unlock()
+
+	out := make([]api.ChainFollowConsumer, 0, len(f.consumers))
+	for _, c := range f.consumers {
+		c.lk.Lock()
+		ci := api.ChainFollowConsumer{
+			Name: c.name,
+		}
+		if c.cursor != nil {
+			ci.Cursor = c.cursor.Key()
+			ci.CursorHeight = c.cursor.Height()
+			ci.Lag = head.Height() - c.cursor.Height()
+		}
+		if c.err != nil {
+			ci.Error = c.err.Error()
+		}
+		c.lk.Unlock()
+
+		out = append(out, ci)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
@@ -0,0 +1,158 @@
+package follow
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/chain/types/mock"
+)
+
+type fakeChain struct {
+	lk   sync.Mutex
+	head *types.TipSet
+	tss  map[types.TipSetKey]*types.TipSet
+}
+
+func (c *fakeChain) add(parent *types.TipSet, nonce uint64) *types.TipSet {
+	ts := mock.TipSet(mock.MkBlock(parent, 1, nonce))
+	c.lk.Lock()
+	c.tss[ts.Key()] = ts
+	c.lk.Unlock()
+	return ts
+}
+
+func (c *fakeChain) setHead(ts *types.TipSet) {
+	c.lk.Lock()
+	c.head = ts
+	c.lk.Unlock()
+}
+
+func (c *fakeChain) GetHeaviestTipSet() *types.TipSet {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	return c.head
+}
+
+func (c *fakeChain) LoadTipSet(tsk types.TipSetKey) (*types.TipSet, error) {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	ts, ok := c.tss[tsk]
+	if !ok {
+		return nil, xerrors.Errorf("tipset %s not found", tsk)
+	}
+	return ts, nil
+}
+
+func (c *fakeChain) GetTipsetByHeight(ctx context.Context, h abi.ChainEpoch, ts *types.TipSet, prev bool) (*types.TipSet, error) {
+	for ts.Height() > h {
+		var err error
+		if ts, err = c.LoadTipSet(ts.Parents()); err != nil {
+			return nil, err
+		}
+	}
+	return ts, nil
+}
+
+type recorder struct {
+	lk     sync.Mutex
+	events []string
+	fail   int
+}
+
+func (r *recorder) record(op string, ts *types.TipSet) error {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	if r.fail > 0 {
+		r.fail--
+		return xerrors.New("temporary failure")
+	}
+	r.events = append(r.events, fmt.Sprintf("%s %d", op, ts.MinTicket().VRFProof[4]-'0'))
+	return nil
+}
+
+func (r *recorder) Apply(ctx context.Context, ts *types.TipSet) error {
+	return r.record("apply", ts)
+}
+
+func (r *recorder) Revert(ctx context.Context, ts *types.TipSet) error {
+	return r.record("revert", ts)
+}
+
+func (r *recorder) take(t *testing.T, n int) []string {
+	require.Eventually(t, func() bool {
+		r.lk.Lock()
+		defer r.lk.Unlock()
+		return len(r.events) >= n
+	}, 5*time.Second, 5*time.Millisecond)
+
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	out := r.events
+	r.events = nil
+	return out
+}
+
+func TestFollower(t *testing.T) {
+	minRetryDelay = time.Millisecond
+
+	c := &fakeChain{tss: map[types.TipSetKey]*types.TipSet{}}
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+
+	gen := c.add(nil, 0)
+	a1 := c.add(gen, 1)
+	a2 := c.add(a1, 2)
+	b2 := c.add(a1, 3)
+	b3 := c.add(b2, 4)
+	b4 := c.add(b3, 5)
+	c.setHead(a1)
+
+	f := newFollower(c, ds)
+	r := &recorder{}
+	require.NoError(t, f.Register("test", r))
+	require.Error(t, f.Register("test", r))
+	f.Start()
+
+	// new consumers start at the head
+	require.Eventually(t, func() bool {
+		return f.Consumers()[0].Cursor == a1.Key()
+	}, 5*time.Second, 5*time.Millisecond)
+
+	c.setHead(a2)
+	require.NoError(t, f.HeadChange(nil, []*types.TipSet{a2}))
+	require.Equal(t, []string{"apply 2"}, r.take(t, 1))
+
+	// reorg, with a failing delivery which gets retried
+	r.lk.Lock()
+	r.fail = 2
+	r.lk.Unlock()
+	c.setHead(b3)
+	require.NoError(t, f.HeadChange([]*types.TipSet{a2}, []*types.TipSet{b2, b3}))
+	require.Equal(t, []string{"revert 2", "apply 3", "apply 4"}, r.take(t, 3))
+
+	require.Eventually(t, func() bool {
+		ci := f.Consumers()[0]
+		return ci.Cursor == b3.Key() && ci.Lag == 0 && ci.Error == ""
+	}, 5*time.Second, 5*time.Millisecond)
+	f.Stop()
+
+	// a restarted follower resumes at the persisted cursor
+	c.setHead(b4)
+	f = newFollower(c, ds)
+	r = &recorder{}
+	require.NoError(t, f.Register("test", r))
+	f.Start()
+	defer f.Stop()
+
+	require.Equal(t, []string{"apply 5"}, r.take(t, 1))
+}
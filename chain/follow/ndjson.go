@@ -0,0 +1,159 @@
+package follow
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/ipfs/go-cid"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/exitcode"
+
+	"github.com/filecoin-project/lotus/chain/store"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+// NdjsonConsumerName is the name the built-in ndjson consumer is registered as
+const NdjsonConsumerName = "ndjson"
+
+type TipSetRow struct {
+	Height    abi.ChainEpoch
+	Key       types.TipSetKey
+	Parents   types.TipSetKey
+	Timestamp uint64
+
+	// Reverted is set when the tipset was removed from the chain, rows
+	// written for it earlier should be dropped
+	Reverted bool `json:",omitempty"`
+}
+
+type MessageRow struct {
+	Height  abi.ChainEpoch
+	TipSet  types.TipSetKey
+	Cid     cid.Cid
+	Message *types.Message
+}
+
+// ReceiptRow is the receipt of a message included in the tipset at Height,
+// which is known once the next tipset is applied
+type ReceiptRow struct {
+	Height   abi.ChainEpoch
+	TipSet   types.TipSetKey
+	Cid      cid.Cid
+	ExitCode exitcode.ExitCode
+	Return   []byte
+	GasUsed  int64
+}
+
+// NdjsonConsumer is a reference consumer, appending tipset, message and
+// receipt rows to tipsets.ndjson, messages.ndjson and receipts.ndjson files.
+//
+// As tipsets may be delivered more than once, rows are identified by the
+// tipset key, and reverts are written as tipset rows with Reverted set.
+type NdjsonConsumer struct {
+	cs  *store.ChainStore
+	dir string
+}
+
+func NewNdjsonConsumer(cs *store.ChainStore, dir string) (*NdjsonConsumer, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, xerrors.Errorf("creating ndjson output directory: %w", err)
+	}
+	return &NdjsonConsumer{cs: cs, dir: dir}, nil
+}
+
+func (n *NdjsonConsumer) Apply(ctx context.Context, ts *types.TipSet) error {
+	msgs, err := n.cs.MessagesForTipset(ts)
+	if err != nil {
+		return xerrors.Errorf("loading messages: %w", err)
+	}
+
+	msgRows := make([]interface{}, 0, len(msgs))
+	for _, m := range msgs {
+		msgRows = append(msgRows, MessageRow{
+			Height:  ts.Height(),
+			TipSet:  ts.Key(),
+			Cid:     m.Cid(),
+			Message: m.VMMessage(),
+		})
+	}
+
+	// ts carries the receipts of the messages in its parent
+	parent, err := n.cs.LoadTipSet(ts.Parents())
+	if err != nil {
+		return xerrors.Errorf("loading parent tipset: %w", err)
+	}
+	parentMsgs, err := n.cs.MessagesForTipset(parent)
+	if err != nil {
+		return xerrors.Errorf("loading parent messages: %w", err)
+	}
+
+	rctRows := make([]interface{}, 0, len(parentMsgs))
+	for i, m := range parentMsgs {
+		r, err := n.cs.GetParentReceipt(ts.Blocks()[0], i)
+		if err != nil {
+			return xerrors.Errorf("loading receipt %d: %w", i, err)
+		}
+		rctRows = append(rctRows, ReceiptRow{
+			Height:   parent.Height(),
+			TipSet:   parent.Key(),
+			Cid:      m.Cid(),
+			ExitCode: r.ExitCode,
+			Return:   r.Return,
+			GasUsed:  r.GasUsed,
+		})
+	}
+
+	// the tipset row goes last, so that it marks the tipset as complete
+	if err := n.write("messages.ndjson", msgRows...); err != nil {
+		return err
+	}
+	if err := n.write("receipts.ndjson", rctRows...); err != nil {
+		return err
+	}
+	return n.write("tipsets.ndjson", tipSetRow(ts, false))
+}
+
+func (n *NdjsonConsumer) Revert(ctx context.Context, ts *types.TipSet) error {
+	return n.write("tipsets.ndjson", tipSetRow(ts, true))
+}
+
+func tipSetRow(ts *types.TipSet, reverted bool) TipSetRow {
+	return TipSetRow{
+		Height:    ts.Height(),
+		Key:       ts.Key(),
+		Parents:   ts.Parents(),
+		Timestamp: ts.MinTimestamp(),
+		Reverted:  reverted,
+	}
+}
+
+// write appends the rows to the file, syncing it before returning so that
+// the tipset is only acknowledged once its rows are on disk
+func (n *NdjsonConsumer) write(file string, rows ...interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	f, err := os.OpenFile(filepath.Join(n.dir, file), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return xerrors.Errorf("opening %s: %w", file, err)
+	}
+
+	enc := json.NewEncoder(f)
+	for _, row := range rows {
+		if err := enc.Encode(row); err != nil {
+			_ = f.Close()
+			return xerrors.Errorf("writing %s: %w", file, err)
+		}
+	}
+
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return xerrors.Errorf("syncing %s: %w", file, err)
+	}
+	return f.Close()
+}
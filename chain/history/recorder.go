@@ -0,0 +1,195 @@
+package history
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/ipfs/go-datastore"
+	logging "github.com/ipfs/go-log/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/miner"
+	"github.com/filecoin-project/lotus/chain/stmgr"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+var log = logging.Logger("metricshistory")
+
+// ConsumerName is the name the recorder is registered as with the chain
+// follower
+const ConsumerName = "metricshistory"
+
+// SeriesKinds describes the series which can be recorded. Series of miners
+// and accounts are named as kind:address, e.g. power:f01000.
+var SeriesKinds = map[string]string{
+	"basefee":      "base fee of the tipset, in attoFIL",
+	"networkpower": "quality adjusted power of the network, in bytes",
+	"power":        "quality adjusted power of a miner, in bytes",
+	"pledge":       "initial pledge locked by a miner, in attoFIL",
+	"balance":      "balance of an actor, in attoFIL",
+}
+
+// sampleFunc returns the value of a series at a tipset
+type sampleFunc func(ctx context.Context, ts *types.TipSet) (types.BigInt, error)
+
+// Recorder samples the configured series into the Store as tipsets are
+// delivered by the chain follower. As the follower resumes within the
+// finality window after a restart, the buckets in which the node was offline
+// for longer are left empty.
+type Recorder struct {
+	store   *Store
+	names   []string
+	samples map[string]sampleFunc
+}
+
+func NewRecorder(sm *stmgr.StateManager, ds datastore.Batching, series []string, tiers []Tier) (*Recorder, error) {
+	store, err := NewStore(ds, tiers)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Recorder{
+		store:   store,
+		samples: map[string]sampleFunc{},
+	}
+	for _, s := range series {
+		if _, ok := r.samples[s]; ok {
+			continue
+		}
+		f, err := seriesSampler(sm, s)
+		if err != nil {
+			return nil, err
+		}
+		r.names = append(r.names, s)
+		r.samples[s] = f
+	}
+	sort.Strings(r.names)
+	return r, nil
+}
+
+func seriesSampler(sm *stmgr.StateManager, series string) (sampleFunc, error) {
+	parts := strings.SplitN(series, ":", 2)
+	kind := parts[0]
+	if _, ok := SeriesKinds[kind]; !ok {
+		return nil, xerrors.Errorf("unknown series %q, known series: basefee, networkpower, power:<miner>, pledge:<miner>, balance:<address>", series)
+	}
+
+	var addr address.Address
+	switch kind {
+	case "basefee", "networkpower":
+		if len(parts) != 1 {
+			return nil, xerrors.Errorf("series %s doesn't take an address", kind)
+		}
+	default:
+		if len(parts) != 2 {
+			return nil, xerrors.Errorf("series %s needs an address, as %s:<address>", kind, kind)
+		}
+		var err error
+		if addr, err = address.NewFromString(parts[1]); err != nil {
+			return nil, xerrors.Errorf("parsing address of series %s: %w", series, err)
+		}
+	}
+
+	switch kind {
+	case "basefee":
+		return func(ctx context.Context, ts *types.TipSet) (types.BigInt, error) {
+			return ts.Blocks()[0].ParentBaseFee, nil
+		}, nil
+	case "networkpower":
+		return func(ctx context.Context, ts *types.TipSet) (types.BigInt, error) {
+			_, total, _, err := stmgr.GetPower(ctx, sm, ts, address.Undef)
+			return total.QualityAdjPower, err
+		}, nil
+	case "power":
+		return func(ctx context.Context, ts *types.TipSet) (types.BigInt, error) {
+			mpow, _, _, err := stmgr.GetPower(ctx, sm, ts, addr)
+			if err != nil || mpow.QualityAdjPower.Nil() {
+				// miners without a claim have no power
+				return types.NewInt(0), err
+			}
+			return mpow.QualityAdjPower, nil
+		}, nil
+	case "pledge":
+		return func(ctx context.Context, ts *types.TipSet) (types.BigInt, error) {
+			act, err := sm.LoadActor(ctx, addr, ts)
+			if err != nil {
+				return types.BigInt{}, err
+			}
+			mas, err := miner.Load(sm.ChainStore().ActorStore(ctx), act)
+			if err != nil {
+				return types.BigInt{}, xerrors.Errorf("loading miner state: %w", err)
+			}
+			lf, err := mas.LockedFunds()
+			if err != nil {
+				return types.BigInt{}, err
+			}
+			return lf.InitialPledgeRequirement, nil
+		}, nil
+	default:
+		return func(ctx context.Context, ts *types.TipSet) (types.BigInt, error) {
+			act, err := sm.LoadActor(ctx, addr, ts)
+			if err != nil {
+				return types.BigInt{}, err
+			}
+			return act.Balance, nil
+		}, nil
+	}
+}
+
+// Apply samples the series due at the tipset, downsampling the older points
+func (r *Recorder) Apply(ctx context.Context, ts *types.TipSet) error {
+	sampled := false
+	for _, name := range r.names {
+		due, err := r.store.Due(name, ts.Height())
+		if err != nil {
+			return err
+		}
+		if !due {
+			continue
+		}
+
+		v, err := r.samples[name](ctx, ts)
+		if err != nil {
+			// leave a gap rather than stalling the other series
+			log.Warnw("failed to sample series", "series", name, "height", ts.Height(), "error", err)
+			continue
+		}
+		if err := r.store.Put(name, ts.Height(), v); err != nil {
+			return err
+		}
+		sampled = true
+	}
+
+	if !sampled {
+		return nil
+	}
+	if err := r.store.Compact(ts.Height()); err != nil {
+		return xerrors.Errorf("compacting series: %w", err)
+	}
+	return nil
+}
+
+// Revert drops the samples taken at the tipset
+func (r *Recorder) Revert(ctx context.Context, ts *types.TipSet) error {
+	for _, name := range r.names {
+		if err := r.store.Drop(name, ts.Height()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Series lists the recorded series, including the ones no longer configured
+// whose points are still kept
+func (r *Recorder) Series() ([]api.MetricsSeries, error) {
+	return r.store.Series()
+}
+
+func (r *Recorder) Query(series string, from, to, resolution abi.ChainEpoch) ([]api.MetricsPoint, error) {
+	return r.store.Query(series, from, to, resolution)
+}
@@ -0,0 +1,403 @@
+// Package history keeps long-term series of chain values, such as the base
+// fee or the power of a miner, sampled once every few epochs and downsampled
+// into coarser tiers as they age, so that years of history take little space.
+package history
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/namespace"
+	"github.com/ipfs/go-datastore/query"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+var storePrefix = datastore.NewKey("/metricshistory")
+
+// Tier is a downsampling tier, keeping points covering Resolution epochs for
+// Retention epochs
+type Tier struct {
+	Resolution abi.ChainEpoch
+	Retention  abi.ChainEpoch
+}
+
+// ValidateTiers checks the tiers are usable: samples are taken at the
+// resolution of the first tier, and each next tier must be coarser, with a
+// resolution multiple of the previous one, and keep its points for longer.
+func ValidateTiers(tiers []Tier) error {
+	if len(tiers) == 0 {
+		return xerrors.Errorf("no downsampling tiers")
+	}
+	for i, t := range tiers {
+		if t.Resolution <= 0 {
+			return xerrors.Errorf("tier %d: resolution must be positive", i)
+		}
+		if t.Retention < t.Resolution {
+			return xerrors.Errorf("tier %d: retention must be at least the resolution", i)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := tiers[i-1]
+		if t.Resolution <= prev.Resolution || t.Resolution%prev.Resolution != 0 {
+			return xerrors.Errorf("tier %d: resolution must be a multiple of the resolution of tier %d", i, i-1)
+		}
+		if t.Retention <= prev.Retention {
+			return xerrors.Errorf("tier %d: retention must be longer than the retention of tier %d", i, i-1)
+		}
+	}
+	return nil
+}
+
+// point aggregates the samples of a series taken in a bucket
+type point struct {
+	Samples int64
+	Min     types.BigInt
+	Max     types.BigInt
+	Sum     types.BigInt
+	Last    types.BigInt
+	// At is the epoch of the last sample
+	At abi.ChainEpoch
+}
+
+func newPoint(epoch abi.ChainEpoch, v types.BigInt) *point {
+	return &point{Samples: 1, Min: v, Max: v, Sum: v, Last: v, At: epoch}
+}
+
+func (p *point) merge(o *point) {
+	if p.Samples == 0 {
+		*p = *o
+		return
+	}
+	p.Samples += o.Samples
+	if types.BigCmp(o.Min, p.Min) < 0 {
+		p.Min = o.Min
+	}
+	if types.BigCmp(o.Max, p.Max) > 0 {
+		p.Max = o.Max
+	}
+	p.Sum = types.BigAdd(p.Sum, o.Sum)
+	if o.At > p.At {
+		p.Last = o.Last
+		p.At = o.At
+	}
+}
+
+type storedPoint struct {
+	key        datastore.Key
+	epoch      abi.ChainEpoch
+	resolution abi.ChainEpoch
+	p          *point
+}
+
+// Store keeps the points of the series under
+// /<series>/<resolution>/<epoch>, the epoch being the start of the bucket.
+// As points are keyed by resolution, points of tiers removed from the config
+// are still found, and dropped with the last tier.
+type Store struct {
+	ds    datastore.Batching
+	tiers []Tier
+}
+
+func NewStore(ds datastore.Batching, tiers []Tier) (*Store, error) {
+	if err := ValidateTiers(tiers); err != nil {
+		return nil, err
+	}
+	return &Store{
+		ds:    namespace.Wrap(ds, storePrefix),
+		tiers: tiers,
+	}, nil
+}
+
+// SampleInterval is the number of epochs between samples
+func (s *Store) SampleInterval() abi.ChainEpoch {
+	return s.tiers[0].Resolution
+}
+
+func bucket(epoch, resolution abi.ChainEpoch) abi.ChainEpoch {
+	b := epoch - epoch%resolution
+	if b > epoch {
+		b -= resolution
+	}
+	return b
+}
+
+func pointKey(series string, resolution, epoch abi.ChainEpoch) datastore.Key {
+	return datastore.KeyWithNamespaces([]string{series, strconv.FormatInt(int64(resolution), 10), strconv.FormatInt(int64(epoch), 10)})
+}
+
+func (s *Store) get(k datastore.Key) (*point, error) {
+	b, err := s.ds.Get(k)
+	if err == datastore.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("reading point %s: %w", k, err)
+	}
+	var p point
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, xerrors.Errorf("decoding point %s: %w", k, err)
+	}
+	return &p, nil
+}
+
+func putPoint(w datastore.Write, k datastore.Key, p *point) error {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return xerrors.Errorf("encoding point: %w", err)
+	}
+	if err := w.Put(k, b); err != nil {
+		return xerrors.Errorf("writing point %s: %w", k, err)
+	}
+	return nil
+}
+
+// Due returns whether a sample taken at the epoch should be recorded. The
+// first tipset of a bucket is sampled, so a sample is due when the bucket has
+// none yet, or when it was taken at the same or a later epoch, as when a
+// tipset is delivered again, or a reorg replaced the tipset sampled.
+func (s *Store) Due(series string, epoch abi.ChainEpoch) (bool, error) {
+	res := s.SampleInterval()
+	p, err := s.get(pointKey(series, res, bucket(epoch, res)))
+	if err != nil {
+		return false, err
+	}
+	return p == nil || p.At >= epoch, nil
+}
+
+// Put records the sample taken at the epoch, replacing the sample of its
+// bucket
+func (s *Store) Put(series string, epoch abi.ChainEpoch, v types.BigInt) error {
+	res := s.SampleInterval()
+	return putPoint(s.ds, pointKey(series, res, bucket(epoch, res)), newPoint(epoch, v))
+}
+
+// Drop removes the sample taken at the epoch, after the tipset it was taken
+// at was reverted
+func (s *Store) Drop(series string, epoch abi.ChainEpoch) error {
+	res := s.SampleInterval()
+	k := pointKey(series, res, bucket(epoch, res))
+	p, err := s.get(k)
+	if err != nil || p == nil || p.At != epoch {
+		return err
+	}
+	if err := s.ds.Delete(k); err != nil {
+		return xerrors.Errorf("deleting point %s: %w", k, err)
+	}
+	return nil
+}
+
+// load returns the points of the series, or of all series when it's empty
+func (s *Store) load(series string) (map[string][]storedPoint, error) {
+	q := query.Query{}
+	if series != "" {
+		q.Prefix = datastore.NewKey(series).String()
+	}
+	res, err := s.ds.Query(q)
+	if err != nil {
+		return nil, xerrors.Errorf("listing points: %w", err)
+	}
+	entries, err := res.Rest()
+	if err != nil {
+		return nil, xerrors.Errorf("listing points: %w", err)
+	}
+
+	out := map[string][]storedPoint{}
+	for _, e := range entries {
+		k := datastore.NewKey(e.Key)
+		parts := k.Namespaces()
+		if len(parts) != 3 || (series != "" && parts[0] != series) {
+			continue
+		}
+		resolution, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			return nil, xerrors.Errorf("parsing key %s: %w", k, err)
+		}
+		epoch, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil {
+			return nil, xerrors.Errorf("parsing key %s: %w", k, err)
+		}
+		var p point
+		if err := json.Unmarshal(e.Value, &p); err != nil {
+			return nil, xerrors.Errorf("decoding point %s: %w", k, err)
+		}
+		out[parts[0]] = append(out[parts[0]], storedPoint{
+			key:        k,
+			epoch:      abi.ChainEpoch(epoch),
+			resolution: abi.ChainEpoch(resolution),
+			p:          &p,
+		})
+	}
+	return out, nil
+}
+
+// Compact downsamples the points older than the retention of their tier
+// into the next tier, and drops the points older than the retention of the
+// last tier, bounding the points kept per series to about the sum of the
+// retention of each tier over its resolution. Series no longer sampled are
+// compacted too, until they have no points left.
+func (s *Store) Compact(head abi.ChainEpoch) error {
+	all, err := s.load("")
+	if err != nil {
+		return err
+	}
+
+	batch, err := s.ds.Batch()
+	if err != nil {
+		return err
+	}
+
+	// the points of the next tiers, merged with the points rolled up
+	rolled := map[datastore.Key]*point{}
+	for series, pts := range all {
+		if err := s.compact(batch, rolled, series, pts, head); err != nil {
+			return err
+		}
+	}
+
+	for k, p := range rolled {
+		if err := putPoint(batch, k, p); err != nil {
+			return err
+		}
+	}
+	return batch.Commit()
+}
+
+func (s *Store) compact(batch datastore.Batch, rolled map[datastore.Key]*point, series string, pts []storedPoint, head abi.ChainEpoch) error {
+	for _, sp := range pts {
+		retention := s.tiers[len(s.tiers)-1].Retention
+		var next *Tier
+		for i, t := range s.tiers {
+			if t.Resolution == sp.resolution {
+				retention = t.Retention
+				if i+1 < len(s.tiers) {
+					next = &s.tiers[i+1]
+				}
+				break
+			}
+		}
+
+		cutoff := head - retention
+		if next != nil {
+			// only roll up whole buckets of the next tier
+			cutoff = bucket(cutoff, next.Resolution)
+		}
+		if sp.epoch+sp.resolution > cutoff {
+			continue
+		}
+
+		if next != nil {
+			k := pointKey(series, next.Resolution, bucket(sp.epoch, next.Resolution))
+			p, ok := rolled[k]
+			if !ok {
+				stored, err := s.get(k)
+				if err != nil {
+					return err
+				}
+				if p = stored; p == nil {
+					p = &point{}
+				}
+				rolled[k] = p
+			}
+			p.merge(sp.p)
+		}
+		if err := batch.Delete(sp.key); err != nil {
+			return xerrors.Errorf("deleting point %s: %w", sp.key, err)
+		}
+	}
+	return nil
+}
+
+// Query returns the points of the series covering the epochs from..to (to
+// the latest point when to is 0), aggregated to the resolution, or to the
+// resolution of their tier when it's coarser or 0. Periods without samples,
+// such as when the node was offline, are returned as points without samples.
+func (s *Store) Query(series string, from, to, resolution abi.ChainEpoch) ([]api.MetricsPoint, error) {
+	all, err := s.load(series)
+	if err != nil {
+		return nil, err
+	}
+
+	type span struct {
+		epoch, span abi.ChainEpoch
+	}
+	buckets := map[span]*point{}
+	for _, sp := range all[series] {
+		if sp.epoch+sp.resolution <= from || (to > 0 && sp.epoch > to) {
+			continue
+		}
+		size := sp.resolution
+		if resolution > size {
+			size = resolution
+		}
+		b := span{bucket(sp.epoch, size), size}
+		if buckets[b] == nil {
+			buckets[b] = &point{}
+		}
+		buckets[b].merge(sp.p)
+	}
+
+	spans := make([]span, 0, len(buckets))
+	for b := range buckets {
+		spans = append(spans, b)
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].epoch < spans[j].epoch
+	})
+
+	out := make([]api.MetricsPoint, 0, len(spans))
+	var end abi.ChainEpoch
+	for i, b := range spans {
+		if i > 0 && b.epoch > end {
+			out = append(out, api.MetricsPoint{Epoch: end, Span: b.epoch - end})
+		}
+		if b.epoch+b.span > end {
+			end = b.epoch + b.span
+		}
+
+		p := buckets[b]
+		out = append(out, api.MetricsPoint{
+			Epoch:   b.epoch,
+			Span:    b.span,
+			Samples: p.Samples,
+			Min:     p.Min,
+			Max:     p.Max,
+			Avg:     types.BigDiv(p.Sum, types.NewInt(uint64(p.Samples))),
+			Last:    p.Last,
+		})
+	}
+	return out, nil
+}
+
+// Series lists the series with recorded points
+func (s *Store) Series() ([]api.MetricsSeries, error) {
+	all, err := s.load("")
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]api.MetricsSeries, 0, len(all))
+	for name, pts := range all {
+		ms := api.MetricsSeries{Name: name, Points: len(pts)}
+		for i, sp := range pts {
+			if i == 0 || sp.epoch < ms.First {
+				ms.First = sp.epoch
+			}
+			if sp.p.At > ms.Last {
+				ms.Last = sp.p.At
+			}
+		}
+		out = append(out, ms)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+	return out, nil
+}
@@ -0,0 +1,85 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+func TestValidateTiers(t *testing.T) {
+	require.NoError(t, ValidateTiers([]Tier{{10, 100}, {100, 1000}}))
+	require.Error(t, ValidateTiers(nil))
+	require.Error(t, ValidateTiers([]Tier{{0, 100}}))
+	require.Error(t, ValidateTiers([]Tier{{10, 5}}))
+	require.Error(t, ValidateTiers([]Tier{{10, 100}, {15, 1000}}))
+	require.Error(t, ValidateTiers([]Tier{{10, 100}, {100, 100}}))
+}
+
+func TestStore(t *testing.T) {
+	s, err := NewStore(dssync.MutexWrap(datastore.NewMapDatastore()), []Tier{{10, 100}, {100, 1000}})
+	require.NoError(t, err)
+
+	// a sample every 10 epochs, the node being offline from 1200 to 1500
+	for h := abi.ChainEpoch(0); h < 2000; h += 10 {
+		if h >= 1200 && h < 1500 {
+			continue
+		}
+		due, err := s.Due("basefee", h)
+		require.NoError(t, err)
+		require.True(t, due)
+		require.NoError(t, s.Put("basefee", h, types.NewInt(uint64(h))))
+		require.NoError(t, s.Compact(h))
+	}
+
+	// the storage is bounded by the tiers
+	series, err := s.Series()
+	require.NoError(t, err)
+	require.Equal(t, []api.MetricsSeries{{Name: "basefee", Points: 26, First: 900, Last: 1990}}, series)
+
+	pts, err := s.Query("basefee", 0, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, pts, 27)
+	require.Equal(t, api.MetricsPoint{
+		Epoch:   900,
+		Span:    100,
+		Samples: 10,
+		Min:     types.NewInt(900),
+		Max:     types.NewInt(990),
+		Avg:     types.NewInt(945),
+		Last:    types.NewInt(990),
+	}, pts[0])
+	// the gap isn't interpolated
+	require.Equal(t, abi.ChainEpoch(1200), pts[3].Epoch)
+	require.Equal(t, abi.ChainEpoch(300), pts[3].Span)
+	require.Equal(t, int64(0), pts[3].Samples)
+	require.Equal(t, abi.ChainEpoch(1800), pts[7].Epoch)
+	require.Equal(t, abi.ChainEpoch(10), pts[7].Span)
+
+	pts, err = s.Query("basefee", 1800, 1990, 50)
+	require.NoError(t, err)
+	require.Len(t, pts, 4)
+	require.Equal(t, int64(5), pts[0].Samples)
+	require.Equal(t, types.NewInt(1840), pts[0].Last)
+
+	// the first tipset of a bucket is sampled, again when it's delivered again
+	due, err := s.Due("basefee", 1995)
+	require.NoError(t, err)
+	require.False(t, due)
+	due, err = s.Due("basefee", 1990)
+	require.NoError(t, err)
+	require.True(t, due)
+
+	// or once it's reverted
+	require.NoError(t, s.Drop("basefee", 1995))
+	require.NoError(t, s.Drop("basefee", 1990))
+	due, err = s.Due("basefee", 1995)
+	require.NoError(t, err)
+	require.True(t, due)
+}
@@ -17,6 +17,10 @@ var (
 	PruneCooldownDefault      = time.Minute
 	GasLimitOverestimation    = 1.25
 
+	IdempotencyKeyRetentionDefault = 24 * time.Hour
+
+	ProtectedLaneCapacityDefault = 10000
+
 	ConfigKey = datastore.NewKey("/mpool/config")
 )
 
@@ -65,6 +69,18 @@ func validateConfg(cfg *types.MpoolConfig) error {
 	if cfg.GasLimitOverestimation < 1 {
 		return fmt.Errorf("'GasLimitOverestimation' cannot be less than 1")
 	}
+	if cfg.ProtectedLaneCapacity < 0 {
+		return fmt.Errorf("'ProtectedLaneCapacity' cannot be negative")
+	}
+	if cfg.IdempotencyKeyRetention < 0 {
+		return fmt.Errorf("'IdempotencyKeyRetention' cannot be negative")
+	}
+	if err := validateRedact(cfg.Redact); err != nil {
+		return err
+	}
+	if err := ValidateGasLimitMargins(cfg.GasLimitMargins); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -92,5 +108,6 @@ func DefaultConfig() *types.MpoolConfig {
 		ReplaceByFeeRatio:      ReplaceByFeeRatioDefault,
 		PruneCooldown:          PruneCooldownDefault,
 		GasLimitOverestimation: GasLimitOverestimation,
+		ProtectedLaneCapacity:  ProtectedLaneCapacityDefault,
 	}
 }
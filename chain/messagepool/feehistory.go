@@ -0,0 +1,185 @@
+package messagepool
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/build"
+)
+
+const feeHistoryDs = "/mpool/feehistory"
+
+var (
+	// FeeLevelBounds split the ratios of the premium of pushed messages to
+	// the premium estimate into the fee levels of the fee history
+	FeeLevelBounds = []float64{0.9, 1.1, 1.5, 2}
+
+	// FeeHistoryQuickEpochs is the window messages landing in are counted
+	// as quick
+	FeeHistoryQuickEpochs = abi.ChainEpoch(5)
+	// FeeHistoryMinSends is the number of sends a level needs for its
+	// history to be shown
+	FeeHistoryMinSends = 5
+	// FeeHistoryRetention is how long the outcomes of sends are kept
+	FeeHistoryRetention = 7 * 24 * time.Hour
+)
+
+// FeeRecord is the fee level a message was pushed at, and when it landed
+type FeeRecord struct {
+	Message cid.Cid
+	// Ratio is the premium of the message over the premium estimate
+	Ratio  float64
+	Pushed abi.ChainEpoch
+
+	// Landed is the epoch the message landed at, zero until it did. Replaced
+	// is set if another message with its nonce landed instead.
+	Landed   abi.ChainEpoch
+	Replaced bool
+	// Checked is the height the chain was searched for the message up to
+	Checked abi.ChainEpoch
+
+	Timestamp time.Time
+}
+
+// FeeHistoryEnabled tells whether the outcomes of sends are kept
+func (mp *MessagePool) FeeHistoryEnabled() bool {
+	return mp.getConfig().FeeHistory
+}
+
+// RecordFeeLevel keeps the fee level the message was pushed at
+func (mp *MessagePool) RecordFeeLevel(c cid.Cid, ratio float64, pushed abi.ChainEpoch) {
+	r := FeeRecord{Message: c, Ratio: ratio, Pushed: pushed, Timestamp: build.Clock.Now()}
+	if err := mp.PutFeeRecord(r); err != nil {
+		log.Errorf("recording fee level: %s", err)
+	}
+}
+
+// GetFeeRecord returns the fee record of the message, or nil if there is none
+func (mp *MessagePool) GetFeeRecord(c cid.Cid) (*FeeRecord, error) {
+	b, err := mp.feeHistory.Get(replacementKey(c))
+	if err != nil {
+		if err == datastore.ErrNotFound {
+			return nil, nil
+		}
+		return nil, xerrors.Errorf("getting fee record of %s: %w", c, err)
+	}
+
+	var r FeeRecord
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, xerrors.Errorf("decoding fee record of %s: %w", c, err)
+	}
+	return &r, nil
+}
+
+func (mp *MessagePool) PutFeeRecord(r FeeRecord) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return xerrors.Errorf("encoding fee record: %w", err)
+	}
+	if err := mp.feeHistory.Put(replacementKey(r.Message), b); err != nil {
+		return xerrors.Errorf("persisting fee record of %s: %w", r.Message, err)
+	}
+	return nil
+}
+
+// FeeRecords returns the fee records within the retention window
+func (mp *MessagePool) FeeRecords() ([]FeeRecord, error) {
+	res, err := mp.feeHistory.Query(query.Query{})
+	if err != nil {
+		return nil, xerrors.Errorf("listing fee records: %w", err)
+	}
+	entries, err := res.Rest()
+	if err != nil {
+		return nil, xerrors.Errorf("listing fee records: %w", err)
+	}
+
+	var out []FeeRecord
+	for _, e := range entries {
+		var r FeeRecord
+		if err := json.Unmarshal(e.Value, &r); err != nil {
+			return nil, xerrors.Errorf("decoding fee record %s: %w", e.Key, err)
+		}
+		if build.Clock.Since(r.Timestamp) > FeeHistoryRetention {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// pruneFeeHistory drops the fee records past the retention window
+func (mp *MessagePool) pruneFeeHistory() error {
+	res, err := mp.feeHistory.Query(query.Query{})
+	if err != nil {
+		return xerrors.Errorf("listing fee records: %w", err)
+	}
+	entries, err := res.Rest()
+	if err != nil {
+		return xerrors.Errorf("listing fee records: %w", err)
+	}
+
+	for _, e := range entries {
+		var r FeeRecord
+		if err := json.Unmarshal(e.Value, &r); err != nil {
+			log.Warnf("dropping undecodable fee record %s: %s", e.Key, err)
+		} else if build.Clock.Since(r.Timestamp) <= FeeHistoryRetention {
+			continue
+		}
+
+		if err := mp.feeHistory.Delete(datastore.NewKey(e.Key)); err != nil {
+			return xerrors.Errorf("deleting fee record: %w", err)
+		}
+	}
+	return nil
+}
+
+// FeeLevel returns the index of the fee level of the ratio
+func FeeLevel(ratio float64) int {
+	for i, b := range FeeLevelBounds {
+		if ratio < b {
+			return i
+		}
+	}
+	return len(FeeLevelBounds)
+}
+
+// FeeLevelStats returns the outcomes of the recorded sends by fee level, at
+// the head height. Sends pushed within FeeHistoryQuickEpochs are left out,
+// as they may still land quickly.
+func FeeLevelStats(records []FeeRecord, head abi.ChainEpoch) []api.FeeLevelStats {
+	stats := make([]api.FeeLevelStats, len(FeeLevelBounds)+1)
+	for i := range stats {
+		stats[i].QuickEpochs = FeeHistoryQuickEpochs
+		if i > 0 {
+			stats[i].MinRatio = FeeLevelBounds[i-1]
+		}
+		if i < len(FeeLevelBounds) {
+			stats[i].MaxRatio = FeeLevelBounds[i]
+		}
+	}
+
+	for _, r := range records {
+		if head-r.Pushed <= FeeHistoryQuickEpochs {
+			continue
+		}
+
+		s := &stats[FeeLevel(r.Ratio)]
+		s.Sends++
+		switch {
+		case r.Landed == 0 || r.Replaced:
+			s.Stalled++
+		case r.Landed-r.Pushed <= FeeHistoryQuickEpochs:
+			s.Quick++
+		default:
+			s.Slow++
+		}
+	}
+	return stats
+}
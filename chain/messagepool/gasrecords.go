@@ -0,0 +1,231 @@
+package messagepool
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/build"
+)
+
+const gasRecordsDs = "/mpool/gasrecords"
+
+// GasRecordsRetention is how long the gas used by local messages is kept
+var GasRecordsRetention = 7 * 24 * time.Hour
+
+// GasRecord is the gas limit a local message was pushed with, and the gas it
+// used once executed
+type GasRecord struct {
+	Message cid.Cid
+	// Family is the family of the destination actor, as in the gas limit
+	// margins, looked up once executed when the limit was given
+	Family string
+	Method abi.MethodNum
+
+	// Estimate is the gas estimate the limit was set from with Margin, zero
+	// when the limit was given
+	Estimate int64
+	Margin   float64
+	Limit    int64
+	FeeCap   abi.TokenAmount
+	Pushed   abi.ChainEpoch
+
+	// Executed is set once the message executed, with the gas it used and
+	// its exit code. Replaced is set if another message with its nonce
+	// landed instead.
+	Executed bool
+	GasUsed  int64
+	ExitCode exitcode.ExitCode
+	Replaced bool
+	// Checked is the height the chain was searched for the message up to
+	Checked abi.ChainEpoch
+
+	Timestamp time.Time
+}
+
+// GasLimitMarginKey is the key of the margin of the method of the family in
+// the gas limit margins
+func GasLimitMarginKey(family string, method abi.MethodNum) string {
+	return fmt.Sprintf("%s/%d", family, method)
+}
+
+// ValidateGasLimitMargins checks the keys and margins of gas limit margins
+func ValidateGasLimitMargins(margins map[string]float64) error {
+	for k, m := range margins {
+		family, method := k, ""
+		if i := strings.IndexByte(k, '/'); i >= 0 {
+			family, method = k[:i], k[i+1:]
+			if _, err := strconv.ParseUint(method, 10, 64); err != nil {
+				return fmt.Errorf("'GasLimitMargins' key %q must be an actor family or family/method number", k)
+			}
+		}
+		if family == "" {
+			return fmt.Errorf("'GasLimitMargins' key %q has no actor family", k)
+		}
+		if m < 1 {
+			return fmt.Errorf("'GasLimitMargins' margin of %q cannot be less than 1", k)
+		}
+	}
+	return nil
+}
+
+// GasLimitMargin returns the margin of the gas estimates of messages to the
+// method of an actor of the family: the margin of the method, else of the
+// family, else GasLimitOverestimation. At each level the margins of the
+// mpool config, set at runtime, go before the ones of the node config.
+func GasLimitMargin(overestimation float64, poolMargins, nodeMargins map[string]float64, family string, method abi.MethodNum) float64 {
+	for _, k := range []string{GasLimitMarginKey(family, method), family} {
+		if m, ok := poolMargins[k]; ok {
+			return m
+		}
+		if m, ok := nodeMargins[k]; ok {
+			return m
+		}
+	}
+	return overestimation
+}
+
+// RecordGasLimit keeps the gas limit the local message was pushed with
+func (mp *MessagePool) RecordGasLimit(r GasRecord) {
+	r.Timestamp = build.Clock.Now()
+	if err := mp.PutGasRecord(r); err != nil {
+		log.Errorf("recording gas limit: %s", err)
+	}
+}
+
+func (mp *MessagePool) PutGasRecord(r GasRecord) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return xerrors.Errorf("encoding gas record: %w", err)
+	}
+	if err := mp.gasRecords.Put(replacementKey(r.Message), b); err != nil {
+		return xerrors.Errorf("persisting gas record of %s: %w", r.Message, err)
+	}
+	return nil
+}
+
+// GasRecords returns the gas records within the retention window
+func (mp *MessagePool) GasRecords() ([]GasRecord, error) {
+	entries, err := mp.gasRecordEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []GasRecord
+	for _, e := range entries {
+		var r GasRecord
+		if err := json.Unmarshal(e.Value, &r); err != nil {
+			return nil, xerrors.Errorf("decoding gas record %s: %w", e.Key, err)
+		}
+		if build.Clock.Since(r.Timestamp) > GasRecordsRetention {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func (mp *MessagePool) gasRecordEntries() ([]query.Entry, error) {
+	res, err := mp.gasRecords.Query(query.Query{})
+	if err != nil {
+		return nil, xerrors.Errorf("listing gas records: %w", err)
+	}
+	entries, err := res.Rest()
+	if err != nil {
+		return nil, xerrors.Errorf("listing gas records: %w", err)
+	}
+	return entries, nil
+}
+
+// pruneGasRecords drops the gas records past the retention window
+func (mp *MessagePool) pruneGasRecords() error {
+	entries, err := mp.gasRecordEntries()
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		var r GasRecord
+		if err := json.Unmarshal(e.Value, &r); err != nil {
+			log.Warnf("dropping undecodable gas record %s: %s", e.Key, err)
+		} else if build.Clock.Since(r.Timestamp) <= GasRecordsRetention {
+			continue
+		}
+
+		if err := mp.gasRecords.Delete(datastore.NewKey(e.Key)); err != nil {
+			return xerrors.Errorf("deleting gas record: %w", err)
+		}
+	}
+	return nil
+}
+
+// GasAccuracyStats returns the gas used by the executed messages of the
+// records over their gas estimate, by family and method, most executed
+// first. The margin of each is looked up with margin.
+func GasAccuracyStats(records []GasRecord, margin func(family string, method abi.MethodNum) float64) []api.GasAccuracyStats {
+	type method struct {
+		family string
+		method abi.MethodNum
+	}
+	byMethod := map[method]*api.GasAccuracyStats{}
+	ratios := map[method][]float64{}
+	var order []method
+
+	for _, r := range records {
+		if !r.Executed || r.Replaced {
+			continue
+		}
+
+		m := method{r.Family, r.Method}
+		s, ok := byMethod[m]
+		if !ok {
+			s = &api.GasAccuracyStats{
+				Family:       r.Family,
+				Method:       r.Method,
+				Margin:       margin(r.Family, r.Method),
+				OutOfGasFees: big.Zero(),
+			}
+			byMethod[m] = s
+			order = append(order, m)
+		}
+
+		s.Executed++
+		if r.ExitCode == exitcode.SysErrOutOfGas {
+			s.OutOfGas++
+			s.OutOfGasFees = big.Add(s.OutOfGasFees, big.Mul(r.FeeCap, big.NewInt(r.Limit)))
+		}
+		if r.Estimate > 0 {
+			ratios[m] = append(ratios[m], float64(r.GasUsed)/float64(r.Estimate))
+		}
+	}
+
+	out := make([]api.GasAccuracyStats, 0, len(order))
+	for _, m := range order {
+		s := byMethod[m]
+		rs := ratios[m]
+		sort.Float64s(rs)
+		s.Estimated = len(rs)
+		if len(rs) > 0 {
+			s.UsedP50 = rs[(len(rs)-1)*50/100]
+			s.UsedP90 = rs[(len(rs)-1)*90/100]
+			s.UsedMax = rs[len(rs)-1]
+		}
+		out = append(out, *s)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Executed > out[j].Executed
+	})
+	return out
+}
@@ -0,0 +1,89 @@
+package messagepool
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/build"
+)
+
+const idempotencyDs = "/mpool/idempotency"
+
+func idempotencyKey(key string) datastore.Key {
+	// keys are arbitrary strings, which may contain slashes
+	return datastore.NewKey(hex.EncodeToString([]byte(key)))
+}
+
+// RecordIdempotencyKey persists the message pushed with an idempotency key
+func (mp *MessagePool) RecordIdempotencyKey(r api.IdempotencyRecord) {
+	r.Timestamp = build.Clock.Now()
+	b, err := json.Marshal(r)
+	if err != nil {
+		log.Errorf("encoding idempotency record: %s", err)
+		return
+	}
+
+	if err := mp.idempotencyKeys.Put(idempotencyKey(r.Key), b); err != nil {
+		log.Errorf("persisting idempotency key %q for %s: %s", r.Key, r.Message, err)
+	}
+}
+
+// GetIdempotencyKey returns the message pushed with the idempotency key, or
+// nil if the key wasn't used within the retention window
+func (mp *MessagePool) GetIdempotencyKey(key string) (*api.IdempotencyRecord, error) {
+	b, err := mp.idempotencyKeys.Get(idempotencyKey(key))
+	if err != nil {
+		if err == datastore.ErrNotFound {
+			return nil, nil
+		}
+		return nil, xerrors.Errorf("getting idempotency key %q: %w", key, err)
+	}
+
+	var r api.IdempotencyRecord
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, xerrors.Errorf("decoding idempotency key %q: %w", key, err)
+	}
+	if mp.idempotencyExpired(&r) {
+		return nil, nil
+	}
+	return &r, nil
+}
+
+func (mp *MessagePool) idempotencyExpired(r *api.IdempotencyRecord) bool {
+	retention := mp.getConfig().IdempotencyKeyRetention
+	if retention == 0 {
+		retention = IdempotencyKeyRetentionDefault
+	}
+	return build.Clock.Since(r.Timestamp) > retention
+}
+
+// pruneIdempotencyKeys drops the keys past the retention window
+func (mp *MessagePool) pruneIdempotencyKeys() error {
+	res, err := mp.idempotencyKeys.Query(query.Query{})
+	if err != nil {
+		return xerrors.Errorf("listing idempotency keys: %w", err)
+	}
+	entries, err := res.Rest()
+	if err != nil {
+		return xerrors.Errorf("listing idempotency keys: %w", err)
+	}
+
+	for _, e := range entries {
+		var r api.IdempotencyRecord
+		if err := json.Unmarshal(e.Value, &r); err != nil {
+			log.Warnf("dropping undecodable idempotency record %s: %s", e.Key, err)
+		} else if !mp.idempotencyExpired(&r) {
+			continue
+		}
+
+		if err := mp.idempotencyKeys.Delete(datastore.NewKey(e.Key)); err != nil {
+			return xerrors.Errorf("deleting idempotency key: %w", err)
+		}
+	}
+	return nil
+}
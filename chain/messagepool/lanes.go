@@ -0,0 +1,76 @@
+package messagepool
+
+import (
+	"context"
+
+	"github.com/filecoin-project/go-address"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+const (
+	// LaneProtected holds the messages from local and priority addresses,
+	// which pruning never evicts up to its capacity
+	LaneProtected = "protected"
+	// LaneRemote holds the other messages, pruned down to SizeLimitLow
+	LaneRemote = "remote"
+)
+
+func protectedLaneCapacity(cfg *types.MpoolConfig) int {
+	if cfg.ProtectedLaneCapacity == 0 {
+		return ProtectedLaneCapacityDefault
+	}
+	return cfg.ProtectedLaneCapacity
+}
+
+// protectedActors returns the key addresses of the senders whose messages
+// are in the protected lane: the priority addresses and the ones messages
+// were pushed from locally. It must be called with mp.lk held.
+func (mp *MessagePool) protectedActors(ctx context.Context) map[address.Address]struct{} {
+	protected := make(map[address.Address]struct{})
+
+	for _, actor := range mp.getConfig().PriorityAddrs {
+		pk, err := mp.resolveToKey(ctx, actor)
+		if err != nil {
+			log.Debugf("failed to resolve priority address: %s", err)
+			continue
+		}
+		protected[pk] = struct{}{}
+	}
+
+	mp.forEachLocal(ctx, func(ctx context.Context, actor address.Address) {
+		protected[actor] = struct{}{}
+	})
+
+	return protected
+}
+
+// Lanes returns the occupancy of the protected and remote lanes
+func (mp *MessagePool) Lanes(ctx context.Context) []api.MpoolLane {
+	cfg := mp.getConfig()
+
+	mp.curTsLk.Lock()
+	defer mp.curTsLk.Unlock()
+
+	mp.lk.Lock()
+	defer mp.lk.Unlock()
+
+	protected := mp.protectedActors(ctx)
+	lanes := []api.MpoolLane{
+		{Name: LaneProtected, Capacity: protectedLaneCapacity(cfg)},
+		{Name: LaneRemote, Capacity: cfg.SizeLimitHigh},
+	}
+	mp.forEachPending(func(actor address.Address, mset *msgSet) {
+		if len(mset.msgs) == 0 {
+			return
+		}
+		lane := &lanes[1]
+		if _, ok := protected[actor]; ok {
+			lane = &lanes[0]
+		}
+		lane.Messages += len(mset.msgs)
+		lane.Senders++
+	})
+	return lanes
+}
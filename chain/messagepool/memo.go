@@ -0,0 +1,104 @@
+package messagepool
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/build"
+)
+
+const memosDs = "/mpool/memo"
+
+// GetMemo returns the memo kept for the message, or nil if there is none
+func (mp *MessagePool) GetMemo(c cid.Cid) (*api.MessageMemo, error) {
+	b, err := mp.memos.Get(replacementKey(c))
+	if err != nil {
+		if err == datastore.ErrNotFound {
+			return nil, nil
+		}
+		return nil, xerrors.Errorf("getting memo of %s: %w", c, err)
+	}
+
+	var m api.MessageMemo
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, xerrors.Errorf("decoding memo of %s: %w", c, err)
+	}
+	return &m, nil
+}
+
+// SetMemo keeps the memo for its message, deleting it if the memo is empty.
+// The timestamp is set if it isn't, so imported memos keep theirs.
+func (mp *MessagePool) SetMemo(m api.MessageMemo) error {
+	if m.Memo == "" {
+		if err := mp.memos.Delete(replacementKey(m.Message)); err != nil {
+			return xerrors.Errorf("deleting memo of %s: %w", m.Message, err)
+		}
+		return nil
+	}
+
+	if m.Timestamp.IsZero() {
+		m.Timestamp = build.Clock.Now()
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return xerrors.Errorf("encoding memo: %w", err)
+	}
+	if err := mp.memos.Put(replacementKey(m.Message), b); err != nil {
+		return xerrors.Errorf("persisting memo of %s: %w", m.Message, err)
+	}
+	return nil
+}
+
+// ListMemos returns the memos of the messages from or to the address, all of
+// them if it is undef, oldest first
+func (mp *MessagePool) ListMemos(addr address.Address) ([]api.MessageMemo, error) {
+	res, err := mp.memos.Query(query.Query{})
+	if err != nil {
+		return nil, xerrors.Errorf("listing memos: %w", err)
+	}
+	entries, err := res.Rest()
+	if err != nil {
+		return nil, xerrors.Errorf("listing memos: %w", err)
+	}
+
+	out := []api.MessageMemo{}
+	for _, e := range entries {
+		var m api.MessageMemo
+		if err := json.Unmarshal(e.Value, &m); err != nil {
+			return nil, xerrors.Errorf("decoding memo %s: %w", e.Key, err)
+		}
+		if addr != address.Undef && m.From != addr && m.To != addr {
+			continue
+		}
+		out = append(out, m)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Timestamp.Before(out[j].Timestamp)
+	})
+	return out, nil
+}
+
+// inheritMemo copies the memo of a replaced message to its replacement,
+// unless the replacement has its own
+func (mp *MessagePool) inheritMemo(original, replacement cid.Cid) error {
+	m, err := mp.GetMemo(original)
+	if err != nil || m == nil {
+		return err
+	}
+	has, err := mp.memos.Has(replacementKey(replacement))
+	if err != nil || has {
+		return err
+	}
+
+	m.Message = replacement
+	m.Timestamp = build.Clock.Now()
+	return mp.SetMemo(*m)
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/filecoin-project/go-address"
 
 	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/api/errcode"
 	"github.com/filecoin-project/lotus/build"
 	"github.com/filecoin-project/lotus/chain/store"
 	"github.com/filecoin-project/lotus/chain/types"
@@ -92,6 +93,7 @@ const (
 	evtTypeMpoolAdd = iota
 	evtTypeMpoolRemove
 	evtTypeMpoolRepub
+	evtTypeMpoolSendReference
 )
 
 // MessagePoolEvt is the journal entry for message pool events.
@@ -105,6 +107,15 @@ type MessagePoolEvtMessage struct {
 	types.Message
 
 	CID cid.Cid
+	// Redacted has the redacted fields of the message, which are zeroed
+	Redacted map[string]string `json:",omitempty"`
+}
+
+// SendReferenceEvt is the journal entry recording the reference an operator
+// gave for a send
+type SendReferenceEvt struct {
+	MessagePoolEvtMessage
+	Reference string
 }
 
 func init() {
@@ -161,11 +172,21 @@ type MessagePool struct {
 
 	localMsgs datastore.Datastore
 
+	replacements datastore.Datastore
+
+	idempotencyKeys datastore.Datastore
+
+	memos datastore.Datastore
+
+	feeHistory datastore.Datastore
+
+	gasRecords datastore.Datastore
+
 	netName dtypes.NetworkName
 
 	sigValCache *lru.TwoQueueCache
 
-	evtTypes [3]journal.EventType
+	evtTypes [4]journal.EventType
 	journal  journal.Journal
 }
 
@@ -251,11 +272,11 @@ func (ms *msgSet) add(m *types.SignedMessage, mp *MessagePool, strict, untrusted
 			minPrice := ComputeMinRBF(exms.Message.GasPremium)
 			if types.BigCmp(m.Message.GasPremium, minPrice) >= 0 {
 				log.Debugw("add with RBF", "oldpremium", exms.Message.GasPremium,
-					"newpremium", m.Message.GasPremium, "addr", m.Message.From, "nonce", m.Message.Nonce)
+					"newpremium", m.Message.GasPremium, "addr", mp.Redactor().From(m.Message.From), "nonce", m.Message.Nonce)
 			} else {
 				log.Debugf("add with duplicate nonce. message from %s with nonce %d already in mpool,"+
 					" increase GasPremium to %s from %s to trigger replace by fee: %s",
-					m.Message.From, m.Message.Nonce, minPrice, m.Message.GasPremium,
+					mp.Redactor().From(m.Message.From), m.Message.Nonce, minPrice, m.Message.GasPremium,
 					ErrRBFTooLowPremium)
 				return false, xerrors.Errorf("message from %s with nonce %d already in mpool,"+
 					" increase GasPremium to %s from %s to trigger replace by fee: %w",
@@ -272,13 +293,13 @@ func (ms *msgSet) add(m *types.SignedMessage, mp *MessagePool, strict, untrusted
 	}
 
 	if !has && strict && len(ms.msgs) >= maxActorPendingMessages {
-		log.Errorf("too many pending messages from actor %s", m.Message.From)
+		log.Errorf("too many pending messages from actor %s", mp.Redactor().From(m.Message.From))
 		return false, ErrTooManyPendingMessages
 	}
 
 	if strict && nonceGap {
 		log.Debugf("adding nonce-gapped message from %s (nonce: %d, nextNonce: %d)",
-			m.Message.From, m.Message.Nonce, nextNonce)
+			mp.Redactor().From(m.Message.From), m.Message.Nonce, nextNonce)
 	}
 
 	ms.nextNonce = nextNonce
@@ -364,28 +385,35 @@ func New(api Provider, ds dtypes.MetadataDS, netName dtypes.NetworkName, j journ
 	}
 
 	mp := &MessagePool{
-		ds:            ds,
-		addSema:       make(chan struct{}, 1),
-		closer:        make(chan struct{}),
-		repubTk:       build.Clock.Ticker(RepublishInterval),
-		repubTrigger:  make(chan struct{}, 1),
-		localAddrs:    make(map[address.Address]struct{}),
-		pending:       make(map[address.Address]*msgSet),
-		keyCache:      make(map[address.Address]address.Address),
-		minGasPrice:   types.NewInt(0),
-		pruneTrigger:  make(chan struct{}, 1),
-		pruneCooldown: make(chan struct{}, 1),
-		blsSigCache:   cache,
-		sigValCache:   verifcache,
-		changes:       lps.New(50),
-		localMsgs:     namespace.Wrap(ds, datastore.NewKey(localMsgsDs)),
-		api:           api,
-		netName:       netName,
-		cfg:           cfg,
+		ds:              ds,
+		addSema:         make(chan struct{}, 1),
+		closer:          make(chan struct{}),
+		repubTk:         build.Clock.Ticker(RepublishInterval),
+		repubTrigger:    make(chan struct{}, 1),
+		localAddrs:      make(map[address.Address]struct{}),
+		pending:         make(map[address.Address]*msgSet),
+		keyCache:        make(map[address.Address]address.Address),
+		minGasPrice:     types.NewInt(0),
+		pruneTrigger:    make(chan struct{}, 1),
+		pruneCooldown:   make(chan struct{}, 1),
+		blsSigCache:     cache,
+		sigValCache:     verifcache,
+		changes:         lps.New(50),
+		localMsgs:       namespace.Wrap(ds, datastore.NewKey(localMsgsDs)),
+		replacements:    namespace.Wrap(ds, datastore.NewKey(replacementsDs)),
+		idempotencyKeys: namespace.Wrap(ds, datastore.NewKey(idempotencyDs)),
+		memos:           namespace.Wrap(ds, datastore.NewKey(memosDs)),
+		feeHistory:      namespace.Wrap(ds, datastore.NewKey(feeHistoryDs)),
+		gasRecords:      namespace.Wrap(ds, datastore.NewKey(gasRecordsDs)),
+		api:             api,
+		netName:         netName,
+		cfg:             cfg,
 		evtTypes: [...]journal.EventType{
 			evtTypeMpoolAdd:    j.RegisterEventType("mpool", "add"),
 			evtTypeMpoolRemove: j.RegisterEventType("mpool", "remove"),
 			evtTypeMpoolRepub:  j.RegisterEventType("mpool", "repub"),
+
+			evtTypeMpoolSendReference: j.RegisterEventType("mpool", "send_reference"),
 		},
 		journal: j,
 	}
@@ -544,6 +572,18 @@ func (mp *MessagePool) runLoop(ctx context.Context) {
 			if err := mp.republishPendingMessages(ctx); err != nil {
 				log.Errorf("error while republishing messages: %s", err)
 			}
+			if err := mp.pruneIdempotencyKeys(); err != nil {
+				log.Errorf("error while pruning idempotency keys: %s", err)
+			}
+			if err := mp.pruneFeeHistory(); err != nil {
+				log.Errorf("error while pruning fee history: %s", err)
+			}
+			if err := mp.pruneGasRecords(); err != nil {
+				log.Errorf("error while pruning gas records: %s", err)
+			}
+			if err := mp.pruneReplacements(); err != nil {
+				log.Errorf("error while pruning replacements: %s", err)
+			}
 		case <-mp.repubTrigger:
 			if err := mp.republishPendingMessages(ctx); err != nil {
 				log.Errorf("error while republishing messages: %s", err)
@@ -766,7 +806,7 @@ func (mp *MessagePool) checkBalance(ctx context.Context, m *types.SignedMessage,
 
 	requiredFunds := m.Message.RequiredFunds()
 	if balance.LessThan(requiredFunds) {
-		return xerrors.Errorf("not enough funds (required: %s, balance: %s): %w", types.FIL(requiredFunds), types.FIL(balance), ErrNotEnoughFunds)
+		return api.WrapError(errcode.InsufficientFunds, xerrors.Errorf("not enough funds (required: %s, balance: %s): %w", types.FIL(requiredFunds), types.FIL(balance), ErrNotEnoughFunds))
 	}
 
 	// add Value for soft failure check
@@ -801,7 +841,7 @@ func (mp *MessagePool) addTs(ctx context.Context, m *types.SignedMessage, curTs
 	}
 
 	if snonce > m.Message.Nonce {
-		return false, xerrors.Errorf("minimum expected nonce is %d: %w", snonce, ErrNonceTooLow)
+		return false, api.WrapError(errcode.NonceTooLow, xerrors.Errorf("minimum expected nonce is %d: %w", snonce, ErrNonceTooLow))
 	}
 
 	mp.lk.Lock()
@@ -849,7 +889,7 @@ func (mp *MessagePool) addLoaded(ctx context.Context, m *types.SignedMessage) er
 	}
 
 	if snonce > m.Message.Nonce {
-		return xerrors.Errorf("minimum expected nonce is %d: %w", snonce, ErrNonceTooLow)
+		return api.WrapError(errcode.NonceTooLow, xerrors.Errorf("minimum expected nonce is %d: %w", snonce, ErrNonceTooLow))
 	}
 
 	_, err = mp.verifyMsgBeforeAdd(m, curTs, true)
@@ -872,7 +912,7 @@ func (mp *MessagePool) addSkipChecks(ctx context.Context, m *types.SignedMessage
 }
 
 func (mp *MessagePool) addLocked(ctx context.Context, m *types.SignedMessage, strict, untrusted bool) error {
-	log.Debugf("mpooladd: %s %d", m.Message.From, m.Message.Nonce)
+	log.Debugf("mpooladd: %s %d", mp.Redactor().From(m.Message.From), m.Message.Nonce)
 	if m.Signature.Type == crypto.SigTypeBLS {
 		mp.blsSigCache.Add(m.Cid(), m.Signature)
 	}
@@ -906,12 +946,18 @@ func (mp *MessagePool) addLocked(ctx context.Context, m *types.SignedMessage, st
 		}
 	}
 
+	replaced, hasReplaced := mset.msgs[m.Message.Nonce]
+
 	incr, err := mset.add(m, mp, strict, untrusted)
 	if err != nil {
 		log.Debug(err)
 		return err
 	}
 
+	if hasReplaced {
+		mp.recordReplacement(replaced.Cid(), m.Cid())
+	}
+
 	if incr {
 		mp.currentSize++
 		if mp.currentSize > mp.getConfig().SizeLimitHigh {
@@ -931,13 +977,27 @@ func (mp *MessagePool) addLocked(ctx context.Context, m *types.SignedMessage, st
 	mp.journal.RecordEvent(mp.evtTypes[evtTypeMpoolAdd], func() interface{} {
 		return MessagePoolEvt{
 			Action:   "add",
-			Messages: []MessagePoolEvtMessage{{Message: m.Message, CID: m.Cid()}},
+			Messages: []MessagePoolEvtMessage{mp.evtMessage(m)},
 		}
 	})
 
 	return nil
 }
 
+// RecordSendReference records the reference an operator gave for sending a
+// message in the journal
+func (mp *MessagePool) RecordSendReference(m *types.SignedMessage, reference string) {
+	r := mp.Redactor()
+	log.Infow("send reference", "cid", m.Cid(), "from", r.From(m.Message.From), "to", r.To(m.Message.To), "value", r.Value(m.Message.Value), "reference", reference)
+
+	mp.journal.RecordEvent(mp.evtTypes[evtTypeMpoolSendReference], func() interface{} {
+		return SendReferenceEvt{
+			MessagePoolEvtMessage: mp.evtMessage(m),
+			Reference:             reference,
+		}
+	})
+}
+
 func (mp *MessagePool) GetNonce(ctx context.Context, addr address.Address, _ types.TipSetKey) (uint64, error) {
 	mp.curTsLk.Lock()
 	defer mp.curTsLk.Unlock()
@@ -999,9 +1059,9 @@ func (mp *MessagePool) getStateBalance(ctx context.Context, addr address.Address
 
 // this method is provided for the gateway to push messages.
 // differences from Push:
-//  - strict checks are enabled
-//  - extra strict add checks are used when adding the messages to the msgSet
-//    that means: no nonce gaps, at most 10 pending messages for the actor
+//   - strict checks are enabled
+//   - extra strict add checks are used when adding the messages to the msgSet
+//     that means: no nonce gaps, at most 10 pending messages for the actor
 func (mp *MessagePool) PushUntrusted(ctx context.Context, m *types.SignedMessage) (cid.Cid, error) {
 	err := mp.checkMessage(m)
 	if err != nil {
@@ -1064,7 +1124,7 @@ func (mp *MessagePool) remove(ctx context.Context, from address.Address, nonce u
 		mp.journal.RecordEvent(mp.evtTypes[evtTypeMpoolRemove], func() interface{} {
 			return MessagePoolEvt{
 				Action:   "remove",
-				Messages: []MessagePoolEvtMessage{{Message: m.Message, CID: m.Cid()}}}
+				Messages: []MessagePoolEvtMessage{mp.evtMessage(m)}}
 		})
 
 		mp.currentSize--
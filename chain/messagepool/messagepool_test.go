@@ -5,16 +5,20 @@ import (
 	"fmt"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/exitcode"
 	"github.com/ipfs/go-cid"
 	"github.com/ipfs/go-datastore"
 	logging "github.com/ipfs/go-log/v2"
+	"github.com/raulk/clock"
 
 	builtin2 "github.com/filecoin-project/specs-actors/v2/actors/builtin"
 
 	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/build"
 	"github.com/filecoin-project/lotus/chain/messagepool/gasguess"
 	"github.com/filecoin-project/lotus/chain/types"
 	"github.com/filecoin-project/lotus/chain/types/mock"
@@ -22,6 +26,7 @@ import (
 	_ "github.com/filecoin-project/lotus/lib/sigs/bls"
 	_ "github.com/filecoin-project/lotus/lib/sigs/secp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func init() {
@@ -487,6 +492,134 @@ func TestPruningSimple(t *testing.T) {
 	}
 }
 
+func TestProtectedLane(t *testing.T) {
+	tma := newTestMpoolAPI()
+	ds := datastore.NewMapDatastore()
+
+	mp, err := New(tma, ds, "mptest", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := tma.nextBlock()
+	tma.applyBlock(t, a)
+
+	w, err := wallet.NewWallet(wallet.NewMemKeyStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newSender := func() address.Address {
+		addr, err := w.WalletNew(context.Background(), types.KTSecp256k1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tma.setBalance(addr, 1) // in FIL
+		return addr
+	}
+	target := mock.Address(1001)
+	gasLimit := gasguess.Costs[gasguess.CostKey{Code: builtin2.StorageMarketActorCodeID, M: 2}]
+
+	// a local worker and a priority address pay the lowest premium in the pool
+	worker := newSender()
+	post, err := mp.Push(context.TODO(), makeTestMessage(w, worker, target, 0, gasLimit, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	priority := newSender()
+	mp.cfg.PriorityAddrs = []address.Address{priority}
+	if err := mp.Add(context.TODO(), makeTestMessage(w, priority, target, 0, gasLimit, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	// while remote senders flood the pool with better paying messages
+	for i := 0; i < 10; i++ {
+		spammer := newSender()
+		for j := 0; j < 10; j++ {
+			if err := mp.Add(context.TODO(), makeTestMessage(w, spammer, target, uint64(j), gasLimit, uint64(1000+i))); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	mp.cfg.SizeLimitHigh = 40
+	mp.cfg.SizeLimitLow = 10
+
+	mp.Prune()
+
+	msgs, _ := mp.Pending(context.TODO())
+	if len(msgs) != 12 {
+		t.Fatal("expected 12 messages in pool, got: ", len(msgs))
+	}
+	found := false
+	for _, m := range msgs {
+		if m.Cid() == post {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("expected the local message to survive pruning")
+	}
+
+	lanes := mp.Lanes(context.TODO())
+	require.Equal(t, []api.MpoolLane{
+		{Name: LaneProtected, Messages: 2, Senders: 2, Capacity: ProtectedLaneCapacityDefault},
+		{Name: LaneRemote, Messages: 10, Senders: 1, Capacity: 40},
+	}, lanes)
+}
+
+func TestProtectedLanePrunesWholeChains(t *testing.T) {
+	tma := newTestMpoolAPI()
+	ds := datastore.NewMapDatastore()
+
+	mp, err := New(tma, ds, "mptest", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := tma.nextBlock()
+	tma.applyBlock(t, a)
+
+	w, err := wallet.NewWallet(wallet.NewMemKeyStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target := mock.Address(1001)
+	gasLimit := gasguess.Costs[gasguess.CostKey{Code: builtin2.StorageMarketActorCodeID, M: 2}]
+
+	// two local senders with a chain of two messages each, one paying more
+	push := func(premium uint64) address.Address {
+		addr, err := w.WalletNew(context.Background(), types.KTSecp256k1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tma.setBalance(addr, 1) // in FIL
+		for i := uint64(0); i < 2; i++ {
+			if _, err := mp.Push(context.TODO(), makeTestMessage(w, addr, target, i, gasLimit, premium)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return addr
+	}
+	good := push(100)
+	push(10)
+
+	mp.cfg.SizeLimitHigh = 4
+	mp.cfg.SizeLimitLow = 1
+	mp.cfg.ProtectedLaneCapacity = 3
+
+	mp.Prune()
+
+	// the chain of the poorer sender doesn't fit whole, so it is dropped
+	// rather than cut short
+	msgs, _ := mp.Pending(context.TODO())
+	require.Len(t, msgs, 2)
+	for _, m := range msgs {
+		require.Equal(t, good, m.Message.From)
+	}
+}
+
 func TestLoadLocal(t *testing.T) {
 	tma := newTestMpoolAPI()
 	ds := datastore.NewMapDatastore()
@@ -740,3 +873,325 @@ func TestUpdates(t *testing.T) {
 		t.Fatal("expected closed channel, but got an update instead")
 	}
 }
+
+func TestReplacements(t *testing.T) {
+	mp, tma := makeTestMpool()
+
+	w, err := wallet.NewWallet(wallet.NewMemKeyStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sender, err := w.WalletNew(context.Background(), types.KTSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := mock.Address(1001)
+
+	tma.setBalance(sender, 1) // in FIL
+	tma.setStateNonce(sender, 0)
+
+	gasLimit := gasguess.Costs[gasguess.CostKey{Code: builtin2.StorageMarketActorCodeID, M: 2}]
+	m1 := makeTestMessage(w, sender, target, 0, gasLimit, 100)
+	m2 := makeTestMessage(w, sender, target, 0, gasLimit, 200)
+	m3 := makeTestMessage(w, sender, target, 0, gasLimit, 300)
+
+	mustAdd(t, mp, m1)
+
+	r, err := mp.GetReplacement(m1.Cid())
+	require.NoError(t, err)
+	require.Nil(t, r)
+
+	mustAdd(t, mp, m2)
+	mustAdd(t, mp, m3)
+
+	r, err = mp.GetReplacement(m1.Cid())
+	require.NoError(t, err)
+	require.Equal(t, m1.Cid(), r.Original)
+	require.Equal(t, m2.Cid(), r.Replacement)
+
+	chain, err := mp.GetReplacementChain(m1.Cid())
+	require.NoError(t, err)
+	require.Len(t, chain, 2)
+	require.Equal(t, m3.Cid(), chain[1].Replacement)
+
+	// a rejected replacement isn't recorded
+	m4 := makeTestMessage(w, sender, target, 0, gasLimit, 301)
+	require.Error(t, mp.Add(context.TODO(), m4))
+	r, err = mp.GetReplacement(m3.Cid())
+	require.NoError(t, err)
+	require.Nil(t, r)
+}
+
+func TestPruneReplacements(t *testing.T) {
+	mp, _ := makeTestMpool()
+
+	clk := clock.NewMock()
+	oldClock := build.Clock
+	build.Clock = clk
+	defer func() { build.Clock = oldClock }()
+
+	from, to := mock.Address(1000), mock.Address(1001)
+	msg := func(premium uint64) cid.Cid {
+		return (&types.Message{From: from, To: to, GasPremium: types.NewInt(premium)}).Cid()
+	}
+	c1, c2, c3 := msg(100), msg(200), msg(300)
+
+	mp.recordReplacement(c1, c2)
+	clk.Add(ReplacementRetention)
+	mp.recordReplacement(c2, c3)
+	clk.Add(time.Second)
+
+	require.NoError(t, mp.pruneReplacements())
+	r, err := mp.GetReplacement(c1)
+	require.NoError(t, err)
+	require.Nil(t, r)
+	r, err = mp.GetReplacement(c2)
+	require.NoError(t, err)
+	require.Equal(t, c3, r.Replacement)
+}
+
+func TestIdempotencyKeys(t *testing.T) {
+	tma := newTestMpoolAPI()
+	ds := datastore.NewMapDatastore()
+
+	mp, err := New(tma, ds, "mptest", nil)
+	require.NoError(t, err)
+
+	clk := clock.NewMock()
+	oldClock := build.Clock
+	build.Clock = clk
+	defer func() { build.Clock = oldClock }()
+
+	from, to := mock.Address(1000), mock.Address(1001)
+	msg := &types.Message{From: from, To: to, Value: types.NewInt(10), Method: 0}
+
+	r, err := mp.GetIdempotencyKey("payout/42")
+	require.NoError(t, err)
+	require.Nil(t, r)
+
+	mp.RecordIdempotencyKey(api.IdempotencyRecord{
+		Key:     "payout/42",
+		Message: msg.Cid(),
+		From:    from,
+		To:      to,
+		Value:   msg.Value,
+	})
+
+	r, err = mp.GetIdempotencyKey("payout/42")
+	require.NoError(t, err)
+	require.Equal(t, msg.Cid(), r.Message)
+	require.True(t, r.Matches(from, msg))
+	require.False(t, r.Matches(from, &types.Message{From: from, To: to, Value: types.NewInt(11)}))
+
+	cfg := mp.GetConfig()
+	cfg.IdempotencyKeyRetention = time.Hour
+	require.NoError(t, mp.SetConfig(cfg))
+
+	clk.Add(time.Hour + time.Second)
+	r, err = mp.GetIdempotencyKey("payout/42")
+	require.NoError(t, err)
+	require.Nil(t, r)
+
+	require.NoError(t, mp.pruneIdempotencyKeys())
+	_, err = mp.idempotencyKeys.Get(idempotencyKey("payout/42"))
+	require.Equal(t, datastore.ErrNotFound, err)
+}
+
+func TestRedact(t *testing.T) {
+	tma := newTestMpoolAPI()
+	mp, err := New(tma, datastore.NewMapDatastore(), "mptest", nil)
+	require.NoError(t, err)
+
+	from, to := mock.Address(1000), mock.Address(1001)
+	sm := &types.SignedMessage{Message: types.Message{From: from, To: to, Value: types.NewInt(10)}}
+
+	evt := mp.evtMessage(sm)
+	require.Nil(t, evt.Redacted)
+	require.Equal(t, sm.Message, evt.Message)
+
+	cfg := mp.GetConfig()
+	cfg.Redact = map[string]string{"memo": RedactOmit}
+	require.Error(t, mp.SetConfig(cfg))
+	cfg.Redact = map[string]string{RedactTo: "blank"}
+	require.Error(t, mp.SetConfig(cfg))
+
+	cfg.Redact = map[string]string{RedactTo: RedactHash, RedactValue: RedactOmit}
+	require.NoError(t, mp.SetConfig(cfg))
+
+	r := mp.Redactor()
+	require.Equal(t, from.String(), r.From(from))
+	require.Equal(t, "<redacted>", r.Value(sm.Message.Value))
+	require.Equal(t, r.To(to), r.To(to))
+	require.NotEqual(t, r.To(to), r.To(from))
+	require.NotContains(t, r.To(to), to.String())
+
+	evt = mp.evtMessage(sm)
+	require.Equal(t, sm.Cid(), evt.CID)
+	require.Equal(t, from, evt.Message.From)
+	require.Equal(t, address.Undef, evt.Message.To)
+	require.True(t, evt.Message.Value.IsZero())
+	require.Equal(t, map[string]string{RedactTo: r.To(to), RedactValue: "<redacted>"}, evt.Redacted)
+	// the message itself is left alone
+	require.Equal(t, to, sm.Message.To)
+}
+
+func TestMemos(t *testing.T) {
+	tma := newTestMpoolAPI()
+	mp, err := New(tma, datastore.NewMapDatastore(), "mptest", nil)
+	require.NoError(t, err)
+
+	a, b, c := mock.Address(1000), mock.Address(1001), mock.Address(1002)
+	m1 := (&types.Message{From: a, To: b, Nonce: 1}).Cid()
+	m2 := (&types.Message{From: c, To: a, Nonce: 2}).Cid()
+	m3 := (&types.Message{From: a, To: b, Nonce: 1, GasPremium: types.NewInt(2)}).Cid()
+
+	require.NoError(t, mp.SetMemo(api.MessageMemo{Message: m1, From: a, To: b, Memo: "invoice #1234"}))
+	require.NoError(t, mp.SetMemo(api.MessageMemo{Message: m2, From: c, To: a, Memo: "refund"}))
+
+	memo, err := mp.GetMemo(m1)
+	require.NoError(t, err)
+	require.Equal(t, "invoice #1234", memo.Memo)
+
+	memos, err := mp.ListMemos(b)
+	require.NoError(t, err)
+	require.Len(t, memos, 1)
+	require.Equal(t, m1, memos[0].Message)
+
+	memos, err = mp.ListMemos(address.Undef)
+	require.NoError(t, err)
+	require.Len(t, memos, 2)
+
+	// the replacement inherits the memo
+	mp.recordReplacement(m1, m3)
+	memo, err = mp.GetMemo(m3)
+	require.NoError(t, err)
+	require.Equal(t, "invoice #1234", memo.Memo)
+
+	require.NoError(t, mp.SetMemo(api.MessageMemo{Message: m1}))
+	memo, err = mp.GetMemo(m1)
+	require.NoError(t, err)
+	require.Nil(t, memo)
+}
+
+func TestFeeLevelStats(t *testing.T) {
+	tma := newTestMpoolAPI()
+	mp, err := New(tma, datastore.NewMapDatastore(), "mptest", nil)
+	require.NoError(t, err)
+
+	a, b := mock.Address(1000), mock.Address(1001)
+	msg := func(nonce uint64) cid.Cid {
+		return (&types.Message{From: a, To: b, Nonce: nonce}).Cid()
+	}
+
+	mp.RecordFeeLevel(msg(1), 1, 100)    // quick
+	mp.RecordFeeLevel(msg(2), 1.05, 100) // slow
+	mp.RecordFeeLevel(msg(3), 0.95, 100) // stalled
+	mp.RecordFeeLevel(msg(4), 3, 100)    // replaced
+	mp.RecordFeeLevel(msg(5), 1, 118)    // too recent
+
+	update := func(c cid.Cid, landed abi.ChainEpoch, replaced bool) {
+		r, err := mp.GetFeeRecord(c)
+		require.NoError(t, err)
+		r.Landed, r.Replaced = landed, replaced
+		require.NoError(t, mp.PutFeeRecord(*r))
+	}
+	update(msg(1), 103, false)
+	update(msg(2), 110, false)
+	update(msg(4), 101, true)
+
+	records, err := mp.FeeRecords()
+	require.NoError(t, err)
+	require.Len(t, records, 5)
+
+	stats := FeeLevelStats(records, 120)
+	require.Len(t, stats, len(FeeLevelBounds)+1)
+
+	at := stats[FeeLevel(1)]
+	require.Equal(t, 0.9, at.MinRatio)
+	require.Equal(t, 1.1, at.MaxRatio)
+	require.Equal(t, 3, at.Sends)
+	require.Equal(t, 1, at.Quick)
+	require.Equal(t, 1, at.Slow)
+	require.Equal(t, 1, at.Stalled)
+
+	top := stats[len(stats)-1]
+	require.Equal(t, float64(0), top.MaxRatio)
+	require.Equal(t, 1, top.Sends)
+	require.Equal(t, 1, top.Stalled)
+
+	require.Equal(t, 0, stats[0].Sends)
+}
+
+func TestGasLimitMargin(t *testing.T) {
+	pool := map[string]float64{"multisig/2": 1.5, "storageminer": 1.3}
+	node := map[string]float64{"multisig": 1.4, "multisig/2": 2, "storageminer/5": 1.8}
+
+	require.Equal(t, 1.5, GasLimitMargin(1.25, pool, node, "multisig", 2))
+	require.Equal(t, 1.4, GasLimitMargin(1.25, pool, node, "multisig", 3))
+	require.Equal(t, 1.8, GasLimitMargin(1.25, pool, node, "storageminer", 5))
+	require.Equal(t, 1.3, GasLimitMargin(1.25, pool, node, "storageminer", 6))
+	require.Equal(t, 1.25, GasLimitMargin(1.25, pool, node, "account", 0))
+
+	require.NoError(t, ValidateGasLimitMargins(pool))
+	require.Error(t, ValidateGasLimitMargins(map[string]float64{"multisig": 0.9}))
+	require.Error(t, ValidateGasLimitMargins(map[string]float64{"multisig/propose": 1.5}))
+	require.Error(t, ValidateGasLimitMargins(map[string]float64{"/2": 1.5}))
+}
+
+func TestGasAccuracyStats(t *testing.T) {
+	tma := newTestMpoolAPI()
+	mp, err := New(tma, datastore.NewMapDatastore(), "mptest", nil)
+	require.NoError(t, err)
+
+	a, b := mock.Address(1000), mock.Address(1001)
+	nonce := uint64(0)
+	record := func(family string, method abi.MethodNum, estimate, used int64, exit exitcode.ExitCode, executed bool) {
+		nonce++
+		limit := estimate * 5 / 4
+		if estimate == 0 {
+			limit = used
+		}
+		mp.RecordGasLimit(GasRecord{
+			Message:  (&types.Message{From: a, To: b, Nonce: nonce}).Cid(),
+			Family:   family,
+			Method:   method,
+			Estimate: estimate,
+			Margin:   1.25,
+			Limit:    limit,
+			FeeCap:   abi.NewTokenAmount(100),
+			Executed: executed,
+			GasUsed:  used,
+			ExitCode: exit,
+		})
+	}
+
+	record("multisig", 2, 1000, 900, 0, true)
+	record("multisig", 2, 1000, 1100, 0, true)
+	record("multisig", 2, 1000, 1250, exitcode.SysErrOutOfGas, true)
+	record("multisig", 2, 0, 2000, 0, true) // limit given
+	record("multisig", 2, 1000, 0, 0, false)
+	record("account", 0, 500, 500, 0, true)
+
+	records, err := mp.GasRecords()
+	require.NoError(t, err)
+	require.Len(t, records, 6)
+
+	stats := GasAccuracyStats(records, func(string, abi.MethodNum) float64 { return 1.25 })
+	require.Len(t, stats, 2)
+
+	ms := stats[0]
+	require.Equal(t, "multisig", ms.Family)
+	require.Equal(t, abi.MethodNum(2), ms.Method)
+	require.Equal(t, 4, ms.Executed)
+	require.Equal(t, 3, ms.Estimated)
+	require.Equal(t, 1.1, ms.UsedP50)
+	require.Equal(t, 1.1, ms.UsedP90)
+	require.Equal(t, 1.25, ms.UsedMax)
+	require.Equal(t, 1, ms.OutOfGas)
+	require.Equal(t, abi.NewTokenAmount(125000).String(), ms.OutOfGasFees.String())
+
+	require.Equal(t, "account", stats[1].Family)
+	require.Equal(t, 1, stats[1].Executed)
+	require.Equal(t, 0, stats[1].OutOfGas)
+}
@@ -51,24 +51,12 @@ func (mp *MessagePool) pruneMessages(ctx context.Context, ts *types.TipSet) erro
 
 	pending, _ := mp.getPendingMessages(ts, ts)
 
-	// protected actors -- not pruned
-	protected := make(map[address.Address]struct{})
-
 	mpCfg := mp.getConfig()
-	// we never prune priority addresses
-	for _, actor := range mpCfg.PriorityAddrs {
-		pk, err := mp.resolveToKey(ctx, actor)
-		if err != nil {
-			log.Debugf("pruneMessages failed to resolve priority address: %s", err)
-		}
-
-		protected[pk] = struct{}{}
-	}
-
-	// we also never prune locally published messages
-	mp.forEachLocal(ctx, func(ctx context.Context, actor address.Address) {
-		protected[actor] = struct{}{}
-	})
+	// we never prune the protected lane, the messages of priority addresses
+	// and locally published ones, within its capacity
+	protected := mp.protectedActors(ctx)
+	protectedPending := make(map[address.Address]map[uint64]*types.SignedMessage)
+	protectedCount := 0
 
 	// Collect all messages to track which ones to remove and create chains for block inclusion
 	pruneMsgs := make(map[cid.Cid]*types.SignedMessage, mp.currentSize)
@@ -76,10 +64,9 @@ func (mp *MessagePool) pruneMessages(ctx context.Context, ts *types.TipSet) erro
 
 	var chains []*msgChain
 	for actor, mset := range pending {
-		// we never prune protected actors
-		_, keep := protected[actor]
-		if keep {
-			keepCount += len(mset)
+		if _, keep := protected[actor]; keep {
+			protectedPending[actor] = mset
+			protectedCount += len(mset)
 			continue
 		}
 
@@ -91,12 +78,18 @@ func (mp *MessagePool) pruneMessages(ctx context.Context, ts *types.TipSet) erro
 		chains = append(chains, actorChains...)
 	}
 
+	if laneCap := protectedLaneCapacity(mpCfg); protectedCount > laneCap {
+		log.Warnf("protected lane over capacity (%d > %d messages), pruning its excess", protectedCount, laneCap)
+		mp.pruneProtectedExcess(protectedPending, laneCap, baseFeeLowerBound, ts, pruneMsgs)
+	}
+
 	// Sort the chains
 	sort.Slice(chains, func(i, j int) bool {
 		return chains[i].Before(chains[j])
 	})
 
-	// Keep messages (remove them from pruneMsgs) from chains while we are under the low water mark
+	// Keep messages (remove them from pruneMsgs) from chains while we are under the low water mark,
+	// which the protected lane doesn't count towards
 	loWaterMark := mpCfg.SizeLimitLow
 keepLoop:
 	for _, chain := range chains {
@@ -118,3 +111,40 @@ keepLoop:
 
 	return nil
 }
+
+// pruneProtectedExcess adds the messages of the protected lane beyond its
+// capacity to pruneMsgs. Whole chains are dropped from the tail, each with the
+// chains of its sender after it, so no sender is left with a nonce gap.
+func (mp *MessagePool) pruneProtectedExcess(pending map[address.Address]map[uint64]*types.SignedMessage, capacity int, baseFeeLowerBound types.BigInt, ts *types.TipSet, pruneMsgs map[cid.Cid]*types.SignedMessage) {
+	var chains []*msgChain
+	for actor, mset := range pending {
+		for _, m := range mset {
+			pruneMsgs[m.Message.Cid()] = m
+		}
+		chains = append(chains, mp.createMessageChains(actor, mset, baseFeeLowerBound, ts)...)
+	}
+
+	sort.Slice(chains, func(i, j int) bool {
+		return chains[i].Before(chains[j])
+	})
+
+	keepCount := 0
+	for _, chain := range chains {
+		keepCount += len(chain.msgs)
+	}
+	for i := len(chains) - 1; i >= 0 && keepCount > capacity; i-- {
+		if !chains[i].valid {
+			continue
+		}
+		for next := chains[i]; next != nil; next = next.next {
+			keepCount -= len(next.msgs)
+		}
+		chains[i].Invalidate()
+	}
+
+	for _, chain := range chains {
+		for _, m := range chain.msgs {
+			delete(pruneMsgs, m.Message.Cid())
+		}
+	}
+}
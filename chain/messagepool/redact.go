@@ -0,0 +1,106 @@
+package messagepool
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+// The message fields which can be redacted from the message pool logs and
+// journal events, with MpoolConfig.Redact
+const (
+	RedactFrom  = "from"
+	RedactTo    = "to"
+	RedactValue = "value"
+)
+
+var RedactableFields = []string{RedactFrom, RedactTo, RedactValue}
+
+// How a redacted field is written out. A hash still lets entries with the
+// same value be correlated, but doesn't hide a value which can be guessed.
+const (
+	RedactOmit = "omit"
+	RedactHash = "hash"
+)
+
+const redactedPlaceholder = "<redacted>"
+
+func validateRedact(redact map[string]string) error {
+	for field, mode := range redact {
+		switch field {
+		case RedactFrom, RedactTo, RedactValue:
+		default:
+			return fmt.Errorf("'Redact' has unknown field %q, redactable fields are %v", field, RedactableFields)
+		}
+		if mode != RedactOmit && mode != RedactHash {
+			return fmt.Errorf("'Redact' mode of %q must be %q or %q, was %q", field, RedactOmit, RedactHash, mode)
+		}
+	}
+	return nil
+}
+
+// Redactor writes out message fields for logs and journal events, as
+// configured
+type Redactor struct {
+	modes map[string]string
+}
+
+// Redactor returns the redactor of the current configuration
+func (mp *MessagePool) Redactor() Redactor {
+	return Redactor{modes: mp.getConfig().Redact}
+}
+
+func (r Redactor) field(field, v string) string {
+	switch r.modes[field] {
+	case RedactOmit:
+		return redactedPlaceholder
+	case RedactHash:
+		h := sha256.Sum256([]byte(field + ":" + v))
+		return "hash:" + hex.EncodeToString(h[:8])
+	default:
+		return v
+	}
+}
+
+func (r Redactor) From(a address.Address) string {
+	return r.field(RedactFrom, a.String())
+}
+
+func (r Redactor) To(a address.Address) string {
+	return r.field(RedactTo, a.String())
+}
+
+func (r Redactor) Value(v abi.TokenAmount) string {
+	return r.field(RedactValue, types.FIL(v).String())
+}
+
+// evtMessage is the journal entry for the message. The redacted fields are
+// zeroed in the message, and listed in Redacted.
+func (mp *MessagePool) evtMessage(m *types.SignedMessage) MessagePoolEvtMessage {
+	r := mp.Redactor()
+	evt := MessagePoolEvtMessage{Message: m.Message, CID: m.Cid()}
+	if len(r.modes) == 0 {
+		return evt
+	}
+
+	evt.Redacted = map[string]string{}
+	if _, ok := r.modes[RedactFrom]; ok {
+		evt.Redacted[RedactFrom] = r.From(m.Message.From)
+		evt.Message.From = address.Undef
+	}
+	if _, ok := r.modes[RedactTo]; ok {
+		evt.Redacted[RedactTo] = r.To(m.Message.To)
+		evt.Message.To = address.Undef
+	}
+	if _, ok := r.modes[RedactValue]; ok {
+		evt.Redacted[RedactValue] = r.Value(m.Message.Value)
+		evt.Message.Value = big.Zero()
+	}
+	return evt
+}
@@ -0,0 +1,112 @@
+package messagepool
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/build"
+)
+
+const replacementsDs = "/mpool/replaced"
+
+// maxReplacementChain bounds how many replacements GetReplacementChain
+// follows, guarding against cycles in corrupted records
+const maxReplacementChain = 1000
+
+// ReplacementRetention is how long the replacements of messages are kept
+var ReplacementRetention = 7 * 24 * time.Hour
+
+func replacementKey(c cid.Cid) datastore.Key {
+	return datastore.NewKey(string(c.Bytes()))
+}
+
+// recordReplacement persists that a pending message was replaced by another
+// message with the same sender and nonce
+func (mp *MessagePool) recordReplacement(original, replacement cid.Cid) {
+	b, err := json.Marshal(api.MsgReplacement{
+		Original:    original,
+		Replacement: replacement,
+		Timestamp:   build.Clock.Now(),
+	})
+	if err != nil {
+		log.Errorf("encoding message replacement: %s", err)
+		return
+	}
+
+	if err := mp.replacements.Put(replacementKey(original), b); err != nil {
+		log.Errorf("persisting message replacement %s -> %s: %s", original, replacement, err)
+	}
+
+	if err := mp.inheritMemo(original, replacement); err != nil {
+		log.Errorf("copying memo of %s to its replacement %s: %s", original, replacement, err)
+	}
+}
+
+// pruneReplacements drops the replacements past the retention window
+func (mp *MessagePool) pruneReplacements() error {
+	res, err := mp.replacements.Query(query.Query{})
+	if err != nil {
+		return xerrors.Errorf("listing replacements: %w", err)
+	}
+	entries, err := res.Rest()
+	if err != nil {
+		return xerrors.Errorf("listing replacements: %w", err)
+	}
+
+	for _, e := range entries {
+		var r api.MsgReplacement
+		if err := json.Unmarshal(e.Value, &r); err != nil {
+			log.Warnf("dropping undecodable replacement %s: %s", e.Key, err)
+		} else if build.Clock.Since(r.Timestamp) <= ReplacementRetention {
+			continue
+		}
+
+		if err := mp.replacements.Delete(datastore.NewKey(e.Key)); err != nil {
+			return xerrors.Errorf("deleting replacement: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetReplacement returns the message which replaced the given message in the
+// mempool, or nil if the message wasn't replaced
+func (mp *MessagePool) GetReplacement(c cid.Cid) (*api.MsgReplacement, error) {
+	b, err := mp.replacements.Get(replacementKey(c))
+	if err != nil {
+		if err == datastore.ErrNotFound {
+			return nil, nil
+		}
+		return nil, xerrors.Errorf("getting replacement of %s: %w", c, err)
+	}
+
+	var r api.MsgReplacement
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, xerrors.Errorf("decoding replacement of %s: %w", c, err)
+	}
+	return &r, nil
+}
+
+// GetReplacementChain returns all successive replacements of the given
+// message, the last one being the current message
+func (mp *MessagePool) GetReplacementChain(c cid.Cid) ([]api.MsgReplacement, error) {
+	var out []api.MsgReplacement
+	for i := 0; i < maxReplacementChain; i++ {
+		r, err := mp.GetReplacement(c)
+		if err != nil {
+			return nil, err
+		}
+		if r == nil {
+			return out, nil
+		}
+
+		out = append(out, *r)
+		c = r.Replacement
+	}
+	return nil, xerrors.Errorf("replacement chain of %s too long", out[0].Original)
+}
@@ -737,7 +737,7 @@ func (mp *MessagePool) createMessageChains(actor address.Address, mset map[uint6
 	//   the balance
 	a, err := mp.api.GetActorAfter(actor, ts)
 	if err != nil {
-		log.Errorf("failed to load actor state, not building chain for %s: %v", actor, err)
+		log.Errorf("failed to load actor state, not building chain for %s: %v", mp.Redactor().From(actor), err)
 		return nil
 	}
 
@@ -752,7 +752,7 @@ func (mp *MessagePool) createMessageChains(actor address.Address, mset map[uint6
 
 		if m.Message.Nonce < curNonce {
 			log.Warnf("encountered message from actor %s with nonce (%d) less than the current nonce (%d)",
-				actor, m.Message.Nonce, curNonce)
+				mp.Redactor().From(actor), m.Message.Nonce, curNonce)
 			skip++
 			continue
 		}
@@ -0,0 +1,168 @@
+// Package msgprop tracks how messages published by the node spread over
+// gossipsub, from the pubsub trace events: which peers the node sent them to,
+// and which peers gossiped them back, which is evidence that they propagated.
+package msgprop
+
+import (
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub_pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/build"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/node/modules/dtypes"
+)
+
+// TrackedMessages is the number of most recently published messages tracked
+const TrackedMessages = 1000
+
+type entry struct {
+	firstPublished time.Time
+	lastPublished  time.Time
+	publishes      int
+
+	sentTo   map[peer.ID]struct{}
+	echoedBy map[peer.ID]struct{}
+	// firstEcho is when a peer first sent or advertised the message back
+	firstEcho time.Time
+}
+
+// Tracker consumes pubsub trace events, it must be fast as it is called from
+// the pubsub event loop
+type Tracker struct {
+	topic string
+
+	lk   sync.Mutex
+	msgs *lru.Cache // message id -> *entry
+}
+
+func NewTracker(nn dtypes.NetworkName) (*Tracker, error) {
+	return newTracker(build.MessagesTopic(nn), TrackedMessages)
+}
+
+func newTracker(topic string, size int) (*Tracker, error) {
+	msgs, err := lru.New(size)
+	if err != nil {
+		return nil, xerrors.Errorf("creating message cache: %w", err)
+	}
+	return &Tracker{topic: topic, msgs: msgs}, nil
+}
+
+// Trace implements pubsub.EventTracer
+func (t *Tracker) Trace(evt *pubsub_pb.TraceEvent) {
+	switch evt.GetType() {
+	case pubsub_pb.TraceEvent_PUBLISH_MESSAGE:
+		pm := evt.GetPublishMessage()
+		if pm.GetTopic() != t.topic {
+			return
+		}
+		now := time.Unix(0, evt.GetTimestamp())
+
+		t.lk.Lock()
+		defer t.lk.Unlock()
+
+		e := t.get(pm.GetMessageID())
+		if e == nil {
+			e = &entry{
+				firstPublished: now,
+				sentTo:         map[peer.ID]struct{}{},
+				echoedBy:       map[peer.ID]struct{}{},
+			}
+			t.msgs.Add(string(pm.GetMessageID()), e)
+		}
+		e.lastPublished = now
+		e.publishes++
+
+	case pubsub_pb.TraceEvent_SEND_RPC:
+		rpc := evt.GetSendRPC()
+		to := peer.ID(rpc.GetSendTo())
+
+		t.lk.Lock()
+		defer t.lk.Unlock()
+
+		for _, m := range rpc.GetMeta().GetMessages() {
+			if e := t.get(m.GetMessageID()); e != nil {
+				e.sentTo[to] = struct{}{}
+			}
+		}
+
+	case pubsub_pb.TraceEvent_DUPLICATE_MESSAGE:
+		dm := evt.GetDuplicateMessage()
+
+		t.lk.Lock()
+		defer t.lk.Unlock()
+
+		t.echo(dm.GetMessageID(), peer.ID(dm.GetReceivedFrom()), evt.GetTimestamp())
+
+	case pubsub_pb.TraceEvent_RECV_RPC:
+		rpc := evt.GetRecvRPC()
+		from := peer.ID(rpc.GetReceivedFrom())
+
+		t.lk.Lock()
+		defer t.lk.Unlock()
+
+		// peers advertising the message have it
+		for _, ihave := range rpc.GetMeta().GetControl().GetIhave() {
+			for _, mid := range ihave.GetMessageIDs() {
+				t.echo(mid, from, evt.GetTimestamp())
+			}
+		}
+	}
+}
+
+// must be called with t.lk held
+func (t *Tracker) get(mid []byte) *entry {
+	e, ok := t.msgs.Peek(string(mid))
+	if !ok {
+		return nil
+	}
+	return e.(*entry)
+}
+
+// must be called with t.lk held
+func (t *Tracker) echo(mid []byte, from peer.ID, ts int64) {
+	e := t.get(mid)
+	if e == nil {
+		return
+	}
+	if e.firstEcho.IsZero() {
+		e.firstEcho = time.Unix(0, ts)
+	}
+	e.echoedBy[from] = struct{}{}
+}
+
+// Propagation returns what was observed of the gossip of the message. Tracked
+// is false when the node didn't publish it recently.
+func (t *Tracker) Propagation(smsg *types.SignedMessage) (api.MsgPropagation, error) {
+	out := api.MsgPropagation{Message: smsg.Cid()}
+
+	b, err := smsg.Serialize()
+	if err != nil {
+		return out, xerrors.Errorf("serializing message: %w", err)
+	}
+	// the message id lotus uses in pubsub, see lp2p.HashMsgId
+	mid := blake2b.Sum256(b)
+
+	t.lk.Lock()
+	defer t.lk.Unlock()
+
+	e := t.get(mid[:])
+	if e == nil {
+		return out, nil
+	}
+
+	out.Tracked = true
+	out.FirstPublished = e.firstPublished
+	out.LastPublished = e.lastPublished
+	out.Publishes = e.publishes
+	out.SentToPeers = len(e.sentTo)
+	out.EchoPeers = len(e.echoedBy)
+	out.FirstEcho = e.firstEcho
+	return out, nil
+}
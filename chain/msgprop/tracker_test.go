@@ -0,0 +1,121 @@
+package msgprop
+
+import (
+	"testing"
+
+	pubsub_pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/crypto"
+
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+func TestTracker(t *testing.T) {
+	const topic = "/fil/msgs/test"
+
+	tr, err := newTracker(topic, 2)
+	require.NoError(t, err)
+
+	mkMsg := func(nonce uint64) (*types.SignedMessage, []byte) {
+		from, err := address.NewIDAddress(1000)
+		require.NoError(t, err)
+		smsg := &types.SignedMessage{
+			Message: types.Message{
+				From:       from,
+				To:         from,
+				Nonce:      nonce,
+				Value:      big.Zero(),
+				GasFeeCap:  big.Zero(),
+				GasPremium: big.Zero(),
+			},
+			Signature: crypto.Signature{Type: crypto.SigTypeSecp256k1},
+		}
+		b, err := smsg.Serialize()
+		require.NoError(t, err)
+		mid := blake2b.Sum256(b)
+		return smsg, mid[:]
+	}
+
+	typ := func(t pubsub_pb.TraceEvent_Type) *pubsub_pb.TraceEvent_Type { return &t }
+	ts := func(s int64) *int64 { s *= 1e9; return &s }
+	str := func(s string) *string { return &s }
+
+	publish := func(mid []byte, topic string, at int64) {
+		tr.Trace(&pubsub_pb.TraceEvent{
+			Type:           typ(pubsub_pb.TraceEvent_PUBLISH_MESSAGE),
+			Timestamp:      ts(at),
+			PublishMessage: &pubsub_pb.TraceEvent_PublishMessage{MessageID: mid, Topic: str(topic)},
+		})
+	}
+	send := func(mid []byte, to string) {
+		tr.Trace(&pubsub_pb.TraceEvent{
+			Type: typ(pubsub_pb.TraceEvent_SEND_RPC),
+			SendRPC: &pubsub_pb.TraceEvent_SendRPC{
+				SendTo: []byte(to),
+				Meta: &pubsub_pb.TraceEvent_RPCMeta{
+					Messages: []*pubsub_pb.TraceEvent_MessageMeta{{MessageID: mid}},
+				},
+			},
+		})
+	}
+
+	smsg, mid := mkMsg(0)
+
+	// messages on other topics are ignored
+	publish(mid, "/other", 1)
+	p, err := tr.Propagation(smsg)
+	require.NoError(t, err)
+	require.False(t, p.Tracked)
+
+	publish(mid, topic, 10)
+	send(mid, "peerA")
+	send(mid, "peerB")
+	send(mid, "peerA")
+	publish(mid, topic, 20)
+
+	tr.Trace(&pubsub_pb.TraceEvent{
+		Type:      typ(pubsub_pb.TraceEvent_DUPLICATE_MESSAGE),
+		Timestamp: ts(12),
+		DuplicateMessage: &pubsub_pb.TraceEvent_DuplicateMessage{
+			MessageID:    mid,
+			ReceivedFrom: []byte("peerB"),
+		},
+	})
+	tr.Trace(&pubsub_pb.TraceEvent{
+		Type:      typ(pubsub_pb.TraceEvent_RECV_RPC),
+		Timestamp: ts(15),
+		RecvRPC: &pubsub_pb.TraceEvent_RecvRPC{
+			ReceivedFrom: []byte("peerC"),
+			Meta: &pubsub_pb.TraceEvent_RPCMeta{
+				Control: &pubsub_pb.TraceEvent_ControlMeta{
+					Ihave: []*pubsub_pb.TraceEvent_ControlIHaveMeta{{MessageIDs: [][]byte{mid}}},
+				},
+			},
+		},
+	})
+
+	p, err = tr.Propagation(smsg)
+	require.NoError(t, err)
+	require.True(t, p.Tracked)
+	require.Equal(t, smsg.Cid(), p.Message)
+	require.Equal(t, 2, p.Publishes)
+	require.Equal(t, int64(10), p.FirstPublished.Unix())
+	require.Equal(t, int64(20), p.LastPublished.Unix())
+	require.Equal(t, 2, p.SentToPeers)
+	require.Equal(t, 2, p.EchoPeers)
+	require.Equal(t, int64(12), p.FirstEcho.Unix())
+
+	// only the most recent messages are tracked
+	_, mid1 := mkMsg(1)
+	_, mid2 := mkMsg(2)
+	publish(mid1, topic, 30)
+	publish(mid2, topic, 40)
+
+	p, err = tr.Propagation(smsg)
+	require.NoError(t, err)
+	require.False(t, p.Tracked)
+}
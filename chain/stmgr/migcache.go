@@ -0,0 +1,68 @@
+package stmgr
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/specs-actors/v3/actors/migration/nv10"
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	"golang.org/x/xerrors"
+)
+
+// migrationCachePrefix is where migration caches computed ahead of the
+// upgrades, e.g. by lotus-shed migrate-state, are persisted
+var migrationCachePrefix = datastore.NewKey("/stmgr/migration-cache")
+
+func migrationCacheKey(height abi.ChainEpoch) datastore.Key {
+	return migrationCachePrefix.ChildString(fmt.Sprint(height))
+}
+
+// SaveMigrationCache persists the entries of the cache for the migration at
+// the given height, returning how many there are. The state the entries
+// reference must be in the state blockstore for them to be usable.
+func SaveMigrationCache(ds datastore.Datastore, height abi.ChainEpoch, cache *nv10.MemMigrationCache) (int, error) {
+	entries := map[string]cid.Cid{}
+	cache.MigrationMap.Range(func(k, v interface{}) bool {
+		entries[k.(string)] = v.(cid.Cid)
+		return true
+	})
+
+	b, err := json.Marshal(entries)
+	if err != nil {
+		return 0, xerrors.Errorf("marshaling migration cache: %w", err)
+	}
+
+	if err := ds.Put(migrationCacheKey(height), b); err != nil {
+		return 0, xerrors.Errorf("saving migration cache: %w", err)
+	}
+	return len(entries), nil
+}
+
+// LoadMigrationCaches seeds the caches of the registered migrations with the
+// persisted entries
+func (sm *StateManager) LoadMigrationCaches(ds datastore.Datastore) error {
+	for height, m := range sm.stateMigrations {
+		b, err := ds.Get(migrationCacheKey(height))
+		if err == datastore.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return xerrors.Errorf("loading migration cache for upgrade at %d: %w", height, err)
+		}
+
+		var entries map[string]cid.Cid
+		if err := json.Unmarshal(b, &entries); err != nil {
+			return xerrors.Errorf("unmarshaling migration cache for upgrade at %d: %w", height, err)
+		}
+
+		for k, c := range entries {
+			if err := m.cache.Write(k, c); err != nil {
+				return xerrors.Errorf("seeding migration cache for upgrade at %d: %w", height, err)
+			}
+		}
+		log.Infow("loaded persisted migration cache", "upgradeHeight", height, "entries", len(entries))
+	}
+	return nil
+}
@@ -9,6 +9,9 @@ import (
 	cbor "github.com/ipfs/go-ipld-cbor"
 
 	"github.com/filecoin-project/go-address"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/api/errcode"
 	"github.com/filecoin-project/lotus/chain/state"
 	"github.com/filecoin-project/lotus/chain/types"
 )
@@ -46,7 +49,7 @@ func (sm *StateManager) LoadActor(_ context.Context, addr address.Address, ts *t
 	if err != nil {
 		return nil, err
 	}
-	return state.GetActor(addr)
+	return getActor(state, addr)
 }
 
 func (sm *StateManager) LoadActorTsk(_ context.Context, addr address.Address, tsk types.TipSetKey) (*types.Actor, error) {
@@ -54,7 +57,16 @@ func (sm *StateManager) LoadActorTsk(_ context.Context, addr address.Address, ts
 	if err != nil {
 		return nil, err
 	}
-	return state.GetActor(addr)
+	return getActor(state, addr)
+}
+
+// getActor loads the actor, tagging actor not found errors with their code
+func getActor(state *state.StateTree, addr address.Address) (*types.Actor, error) {
+	act, err := state.GetActor(addr)
+	if xerrors.Is(err, types.ErrActorNotFound) {
+		return nil, api.WrapError(errcode.ActorNotFound, err)
+	}
+	return act, err
 }
 
 func (sm *StateManager) LoadActorRaw(_ context.Context, addr address.Address, st cid.Cid) (*types.Actor, error) {
@@ -62,5 +74,5 @@ func (sm *StateManager) LoadActorRaw(_ context.Context, addr address.Address, st
 	if err != nil {
 		return nil, err
 	}
-	return state.GetActor(addr)
+	return getActor(state, addr)
 }
@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/api/errcode"
 	"github.com/filecoin-project/lotus/blockstore"
 	"github.com/filecoin-project/lotus/chain/gen"
 	"github.com/filecoin-project/lotus/chain/store"
@@ -13,6 +15,8 @@ import (
 	datastore "github.com/ipfs/go-datastore"
 	syncds "github.com/ipfs/go-datastore/sync"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
 )
 
 func TestIndexSeeks(t *testing.T) {
@@ -79,3 +83,76 @@ func TestIndexSeeks(t *testing.T) {
 		assert.Equal(t, abi.ChainEpoch(i), ts3.Height())
 	}
 }
+
+func TestNullRoundPolicy(t *testing.T) {
+	cg, err := gen.NewGenerator()
+	require.NoError(t, err)
+	gencar, err := cg.GenesisCar()
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+
+	nbs := blockstore.NewMemorySync()
+	cs := store.NewChainStore(nbs, nbs, syncds.MutexWrap(datastore.NewMapDatastore()), nil, nil)
+	defer cs.Close() //nolint:errcheck
+
+	_, err = cs.Import(bytes.NewReader(gencar))
+	require.NoError(t, err)
+	require.NoError(t, cs.SetGenesis(cg.Genesis()))
+
+	// blocks up to epoch 10, 3 null rounds, a block at 14, 2 null rounds, and
+	// the head at 17
+	cur := mock.TipSet(cg.Genesis())
+	for i := 0; i < 10; i++ {
+		cur = mock.TipSet(mock.MkBlock(cur, 1, 1))
+		require.NoError(t, cs.PutTipSet(ctx, cur))
+	}
+	mid := mock.MkBlock(cur, 1, 1)
+	mid.Height += 3
+	cur = mock.TipSet(mid)
+	require.NoError(t, cs.PutTipSet(ctx, cur))
+	head := mock.MkBlock(cur, 1, 1)
+	head.Height += 2
+	headts := mock.TipSet(head)
+	require.NoError(t, cs.PutTipSet(ctx, headts))
+	require.Equal(t, abi.ChainEpoch(17), headts.Height())
+
+	for _, tc := range []struct {
+		h                    abi.ChainEpoch
+		previous, next, fail abi.ChainEpoch // 0 when failing
+	}{
+		{h: 5, previous: 5, next: 5, fail: 5},
+		// consecutive null rounds
+		{h: 11, previous: 10, next: 14},
+		{h: 12, previous: 10, next: 14},
+		{h: 13, previous: 10, next: 14},
+		// null rounds just before the requested head
+		{h: 15, previous: 14, next: 17},
+		{h: 16, previous: 14, next: 17},
+		{h: 17, previous: 17, next: 17, fail: 17},
+		// after the requested head
+		{h: 18},
+	} {
+		for policy, expect := range map[api.NullRoundPolicy]abi.ChainEpoch{
+			"":                    tc.previous,
+			api.NullRoundPrevious: tc.previous,
+			api.NullRoundNext:     tc.next,
+			api.NullRoundError:    tc.fail,
+		} {
+			ts, err := cs.GetTipsetByHeightPolicy(ctx, tc.h, headts, policy)
+			if expect == 0 {
+				require.Error(t, err, "epoch %d, policy %q", tc.h, policy)
+				continue
+			}
+			require.NoError(t, err, "epoch %d, policy %q", tc.h, policy)
+			require.Equal(t, expect, ts.Height(), "epoch %d, policy %q", tc.h, policy)
+		}
+	}
+
+	_, err = cs.GetTipsetByHeightPolicy(ctx, 12, headts, api.NullRoundError)
+	require.True(t, xerrors.Is(err, api.ErrNullRound))
+	require.Equal(t, errcode.NullRound, api.ErrorCode(err))
+
+	_, err = cs.GetTipsetByHeightPolicy(ctx, 12, headts, "nearest")
+	require.Error(t, err)
+}
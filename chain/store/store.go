@@ -26,6 +26,7 @@ import (
 	blockadt "github.com/filecoin-project/specs-actors/actors/util/adt"
 
 	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/api/errcode"
 	bstore "github.com/filecoin-project/lotus/blockstore"
 	"github.com/filecoin-project/lotus/build"
 	"github.com/filecoin-project/lotus/chain/actors/adt"
@@ -707,6 +708,9 @@ func (cs *ChainStore) LoadTipSet(tsk types.TipSetKey) (*types.TipSet, error) {
 		i, c := i, c
 		eg.Go(func() error {
 			b, err := cs.GetBlock(c)
+			if xerrors.Is(err, bstore.ErrNotFound) {
+				return api.WrapError(errcode.TipSetNotFound, xerrors.Errorf("get block %s: %w", c, err))
+			}
 			if err != nil {
 				return xerrors.Errorf("get block %s: %w", c, err)
 			}
@@ -1432,6 +1436,28 @@ func (cs *ChainStore) GetTipsetByHeight(ctx context.Context, h abi.ChainEpoch, t
 	return cs.LoadTipSet(lbts.Parents())
 }
 
+// GetTipsetByHeightPolicy returns the tipset at height h in the chain of ts
+// (the heaviest tipset when nil), resolving null rounds by the policy: to the
+// previous tipset, to the next one, or failing with api.ErrNullRound
+func (cs *ChainStore) GetTipsetByHeightPolicy(ctx context.Context, h abi.ChainEpoch, ts *types.TipSet, policy api.NullRoundPolicy) (*types.TipSet, error) {
+	switch policy {
+	case api.NullRoundPrevious, "":
+		return cs.GetTipsetByHeight(ctx, h, ts, true)
+	case api.NullRoundNext, api.NullRoundError:
+	default:
+		return nil, xerrors.Errorf("unknown null round policy %q, expected previous, next or error", policy)
+	}
+
+	next, err := cs.GetTipsetByHeight(ctx, h, ts, false)
+	if err != nil {
+		return nil, err
+	}
+	if next.Height() != h && policy == api.NullRoundError {
+		return nil, api.WrapError(errcode.NullRound, xerrors.Errorf("epoch %d: %w, the next tipset is at epoch %d", h, api.ErrNullRound, next.Height()))
+	}
+	return next, nil
+}
+
 func recurseLinks(bs bstore.Blockstore, walked *cid.Set, root cid.Cid, in []cid.Cid) ([]cid.Cid, error) {
 	if root.Prefix().Codec != cid.DagCBOR {
 		return in, nil
@@ -51,6 +51,70 @@ func (f FIL) Short() string {
 	return strings.TrimRight(strings.TrimRight(r.FloatString(3), "0"), ".") + " " + prefix + "WD"
 }
 
+// FILUnit is a denomination FIL amounts can be displayed in
+type FILUnit struct {
+	prefix string
+	exp    int // attoFIL per unit is 10^exp
+}
+
+var (
+	UnitFIL      = FILUnit{prefix: "", exp: 18}
+	UnitMilliFIL = FILUnit{prefix: "m", exp: 15}
+	UnitMicroFIL = FILUnit{prefix: "μ", exp: 12}
+	UnitNanoFIL  = FILUnit{prefix: "n", exp: 9}
+	UnitPicoFIL  = FILUnit{prefix: "p", exp: 6}
+	UnitFemtoFIL = FILUnit{prefix: "f", exp: 3}
+	UnitAttoFIL  = FILUnit{prefix: "a", exp: 0}
+)
+
+var filUnitNames = map[string]FILUnit{
+	"":      UnitFIL,
+	"milli": UnitMilliFIL,
+	"m":     UnitMilliFIL,
+	"micro": UnitMicroFIL,
+	"μ":     UnitMicroFIL,
+	"u":     UnitMicroFIL,
+	"nano":  UnitNanoFIL,
+	"n":     UnitNanoFIL,
+	"pico":  UnitPicoFIL,
+	"p":     UnitPicoFIL,
+	"femto": UnitFemtoFIL,
+	"f":     UnitFemtoFIL,
+	"atto":  UnitAttoFIL,
+	"a":     UnitAttoFIL,
+}
+
+// ParseFILUnit parses a unit name like "WD", "nanoWD" or "nWD"
+func ParseFILUnit(s string) (FILUnit, error) {
+	norm := strings.ToLower(strings.TrimSpace(s))
+	if !strings.HasSuffix(norm, "wd") {
+		return FILUnit{}, fmt.Errorf("unrecognized unit: %q", s)
+	}
+
+	u, ok := filUnitNames[strings.TrimSuffix(norm, "wd")]
+	if !ok {
+		return FILUnit{}, fmt.Errorf("unrecognized unit: %q", s)
+	}
+	return u, nil
+}
+
+func (u FILUnit) String() string {
+	return u.prefix + "WD"
+}
+
+// Format prints the amount of attoFIL in the unit, without rounding
+func (u FILUnit) Format(amt BigInt) string {
+	r := new(big.Rat).SetFrac(amt.Int, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(u.exp)), nil))
+	if r.Sign() == 0 {
+		return "0 " + u.String()
+	}
+	v := r.FloatString(u.exp)
+	if u.exp > 0 {
+		v = strings.TrimRight(strings.TrimRight(v, "0"), ".")
+	}
+	return v + " " + u.String()
+}
+
 func (f FIL) Format(s fmt.State, ch rune) {
 	switch ch {
 	case 's', 'v':
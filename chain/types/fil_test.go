@@ -112,3 +112,35 @@ func TestFilShort(t *testing.T) {
 		})
 	}
 }
+
+func TestFilUnit(t *testing.T) {
+	for _, s := range []struct {
+		unit   string
+		amt    string
+		expect string
+	}{
+		{unit: "WD", amt: "1", expect: "1 WD"},
+		{unit: "wd", amt: "0.000000001", expect: "0.000000001 WD"},
+		{unit: "nanoWD", amt: "0.000000001", expect: "1 nWD"},
+		{unit: "nWD", amt: "1.5", expect: "1500000000 nWD"},
+		{unit: "nWD", amt: "100 aWD", expect: "0.0000001 nWD"},
+		{unit: "microWD", amt: "0", expect: "0 μWD"},
+		{unit: "uWD", amt: "-0.001", expect: "-1000 μWD"},
+		{unit: "attoWD", amt: "1", expect: "1000000000000000000 aWD"},
+	} {
+		s := s
+		t.Run(s.unit+"/"+s.amt, func(t *testing.T) {
+			u, err := ParseFILUnit(s.unit)
+			require.NoError(t, err)
+
+			f, err := ParseFIL(s.amt)
+			require.NoError(t, err)
+			require.Equal(t, s.expect, u.Format(BigInt(f)))
+		})
+	}
+
+	for _, bad := range []string{"", "FIL", "kiloWD", "nano"} {
+		_, err := ParseFILUnit(bad)
+		require.Error(t, err, bad)
+	}
+}
@@ -10,6 +10,7 @@ import (
 var (
 	ErrKeyInfoNotFound = fmt.Errorf("key info not found")
 	ErrKeyExists       = fmt.Errorf("key already exists")
+	ErrKeystoreLocked  = fmt.Errorf("keystore locked")
 )
 
 // KeyType defines a type of a key
@@ -13,10 +13,40 @@ type MpoolConfig struct {
 	ReplaceByFeeRatio      float64
 	PruneCooldown          time.Duration
 	GasLimitOverestimation float64
+	// IdempotencyKeyRetention is how long the messages pushed with an
+	// idempotency key are remembered, zero for the default
+	IdempotencyKeyRetention time.Duration
+	// Redact maps the message fields redacted from the message pool logs
+	// and journal events ("from", "to", "value") to how they are written
+	// out instead: "omit" or "hash"
+	Redact map[string]string
+	// FeeHistory keeps how fast the messages pushed by MpoolPushMessage
+	// landed, by fee level
+	FeeHistory bool
+	// GasLimitMargins are the margins the gas estimates of messages are
+	// multiplied by, by the family of the destination actor ("multisig") or
+	// its method ("storageminer/5"), over GasLimitOverestimation
+	GasLimitMargins map[string]float64
+	// ProtectedLaneCapacity is how many messages from local and priority
+	// addresses pruning never evicts, zero for the default. They don't count
+	// towards SizeLimitHigh and SizeLimitLow.
+	ProtectedLaneCapacity int
 }
 
 func (mc *MpoolConfig) Clone() *MpoolConfig {
 	r := new(MpoolConfig)
 	*r = *mc
+	if mc.Redact != nil {
+		r.Redact = make(map[string]string, len(mc.Redact))
+		for k, v := range mc.Redact {
+			r.Redact[k] = v
+		}
+	}
+	if mc.GasLimitMargins != nil {
+		r.GasLimitMargins = make(map[string]float64, len(mc.GasLimitMargins))
+		for k, v := range mc.GasLimitMargins {
+			r.GasLimitMargins[k] = v
+		}
+	}
 	return r
 }
@@ -0,0 +1,378 @@
+package wallet
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+const (
+	// KEncryption is the keystore entry holding the passphrase derivation
+	// parameters of an encrypted keystore
+	KEncryption = "keystore-encryption"
+
+	KTArgon2id  types.KeyType = "argon2id"
+	KTEncrypted types.KeyType = "encrypted"
+
+	// EnvKeystorePassphrase can hold the passphrase of an encrypted keystore
+	EnvKeystorePassphrase = "LOTUS_KEYSTORE_PASSPHRASE"
+)
+
+// argon2id parameters used for new encrypted keystores (RFC 9106 second
+// recommended option)
+var (
+	Argon2Time    uint32 = 3
+	Argon2Memory  uint32 = 64 * 1024 // KiB
+	Argon2Threads uint8  = 4
+)
+
+var checkPlaintext = []byte("lotus keystore")
+
+type keystoreEncryption struct {
+	Salt    []byte
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+
+	// Check is a known plaintext sealed with the derived key, used to tell
+	// a wrong passphrase from corrupted keys
+	Check []byte
+}
+
+func (ke *keystoreEncryption) deriveKey(passphrase []byte) []byte {
+	return argon2.IDKey(passphrase, ke.Salt, ke.Time, ke.Memory, ke.Threads, chacha20poly1305.KeySize)
+}
+
+// encryptedName returns whether the named entry holds a wallet private key,
+// which is encrypted in encrypted keystores. Other entries (libp2p host key,
+// API secret) are needed to start the node and stay in plaintext.
+func encryptedName(name string) bool {
+	return strings.HasPrefix(name, KNamePrefix) || strings.HasPrefix(name, KTrashPrefix) || name == KDefault
+}
+
+func seal(key []byte, plaintext []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, xerrors.Errorf("generating nonce: %w", err)
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(key []byte, sealed []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return nil, xerrors.Errorf("sealed data too short")
+	}
+
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+}
+
+// EncryptedKeyStore wraps a KeyStore, encrypting wallet keys with a key
+// derived from a passphrase. Wallet keys can only be read and written while
+// the keystore is unlocked. Keystores without the KEncryption entry are
+// passed through as-is.
+type EncryptedKeyStore struct {
+	ks          types.KeyStore
+	relockAfter time.Duration
+
+	lk      sync.Mutex
+	key     []byte // nil while locked
+	lastUse time.Time
+	relock  *time.Timer
+
+	// called after the keystore got locked, used by LocalWallet to drop
+	// cached keys
+	onLock func()
+}
+
+// NewEncryptedKeyStore wraps the keystore, which starts locked. With a
+// non-zero relockAfter, the keystore is locked again after wallet keys
+// weren't accessed for that long.
+func NewEncryptedKeyStore(ks types.KeyStore, relockAfter time.Duration) *EncryptedKeyStore {
+	return &EncryptedKeyStore{
+		ks:          ks,
+		relockAfter: relockAfter,
+	}
+}
+
+func (e *EncryptedKeyStore) encryption() (*keystoreEncryption, error) {
+	ki, err := e.ks.Get(KEncryption)
+	if err != nil {
+		if xerrors.Is(err, types.ErrKeyInfoNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if ki.Type != KTArgon2id {
+		return nil, xerrors.Errorf("unsupported keystore encryption '%s'", ki.Type)
+	}
+
+	var ke keystoreEncryption
+	if err := json.Unmarshal(ki.PrivateKey, &ke); err != nil {
+		return nil, xerrors.Errorf("decoding keystore encryption params: %w", err)
+	}
+	return &ke, nil
+}
+
+// Encrypted returns whether the underlying keystore was encrypted
+func (e *EncryptedKeyStore) Encrypted() (bool, error) {
+	ke, err := e.encryption()
+	if err != nil {
+		return false, err
+	}
+	return ke != nil, nil
+}
+
+// Unlock derives the keystore key from the passphrase, enabling access to
+// wallet keys
+func (e *EncryptedKeyStore) Unlock(passphrase []byte) error {
+	ke, err := e.encryption()
+	if err != nil {
+		return err
+	}
+	if ke == nil {
+		return xerrors.Errorf("keystore is not encrypted")
+	}
+
+	key := ke.deriveKey(passphrase)
+	check, err := open(key, ke.Check)
+	if err != nil || subtle.ConstantTimeCompare(check, checkPlaintext) != 1 {
+		return xerrors.Errorf("invalid keystore passphrase")
+	}
+
+	e.lk.Lock()
+	defer e.lk.Unlock()
+
+	e.key = key
+	e.touch()
+	return nil
+}
+
+// Lock drops the keystore key, disabling access to wallet keys until the
+// keystore is unlocked again
+func (e *EncryptedKeyStore) Lock() {
+	e.lk.Lock()
+	wasUnlocked := e.key != nil
+	e.key = nil
+	if e.relock != nil {
+		e.relock.Stop()
+		e.relock = nil
+	}
+	e.lk.Unlock()
+
+	if wasUnlocked {
+		log.Info("keystore locked")
+	}
+	if e.onLock != nil {
+		e.onLock()
+	}
+}
+
+// Locked returns whether wallet keys are currently inaccessible
+func (e *EncryptedKeyStore) Locked() bool {
+	e.lk.Lock()
+	defer e.lk.Unlock()
+	return e.key == nil
+}
+
+// touch records a use of the keystore key for the idle relock, must be called
+// with e.lk held
+func (e *EncryptedKeyStore) touch() {
+	if e.relockAfter <= 0 {
+		return
+	}
+	e.lastUse = time.Now()
+	if e.relock == nil {
+		e.relock = time.AfterFunc(e.relockAfter, e.relockIdle)
+	}
+}
+
+func (e *EncryptedKeyStore) relockIdle() {
+	e.lk.Lock()
+	if e.relock == nil {
+		e.lk.Unlock()
+		return // locked in the meantime
+	}
+	if idle := time.Since(e.lastUse); idle < e.relockAfter {
+		e.relock.Reset(e.relockAfter - idle)
+		e.lk.Unlock()
+		return
+	}
+	e.lk.Unlock()
+
+	log.Infof("locking keystore after %s of inactivity", e.relockAfter)
+	e.Lock()
+}
+
+func (e *EncryptedKeyStore) currentKey() ([]byte, error) {
+	e.lk.Lock()
+	defer e.lk.Unlock()
+
+	if e.key == nil {
+		return nil, types.ErrKeystoreLocked
+	}
+	e.touch()
+	return e.key, nil
+}
+
+// List lists all the keys stored in the KeyStore
+func (e *EncryptedKeyStore) List() ([]string, error) {
+	return e.ks.List()
+}
+
+// Get gets a key out of keystore, decrypting wallet keys
+func (e *EncryptedKeyStore) Get(name string) (types.KeyInfo, error) {
+	ki, err := e.ks.Get(name)
+	if err != nil || ki.Type != KTEncrypted {
+		return ki, err
+	}
+
+	key, err := e.currentKey()
+	if err != nil {
+		return types.KeyInfo{}, xerrors.Errorf("reading key '%s': %w", name, err)
+	}
+
+	plain, err := open(key, ki.PrivateKey)
+	if err != nil {
+		return types.KeyInfo{}, xerrors.Errorf("decrypting key '%s': %w", name, err)
+	}
+
+	var res types.KeyInfo
+	if err := json.Unmarshal(plain, &res); err != nil {
+		return types.KeyInfo{}, xerrors.Errorf("decoding key '%s': %w", name, err)
+	}
+	return res, nil
+}
+
+// Put saves a key info under given name, encrypting wallet keys when the
+// keystore is encrypted
+func (e *EncryptedKeyStore) Put(name string, info types.KeyInfo) error {
+	if !encryptedName(name) {
+		return e.ks.Put(name, info)
+	}
+
+	encrypted, err := e.Encrypted()
+	if err != nil {
+		return err
+	}
+	if !encrypted {
+		return e.ks.Put(name, info)
+	}
+
+	key, err := e.currentKey()
+	if err != nil {
+		return xerrors.Errorf("writing key '%s': %w", name, err)
+	}
+
+	ki, err := encryptKey(key, info)
+	if err != nil {
+		return xerrors.Errorf("encrypting key '%s': %w", name, err)
+	}
+	return e.ks.Put(name, ki)
+}
+
+// Delete removes a key from keystore
+func (e *EncryptedKeyStore) Delete(name string) error {
+	return e.ks.Delete(name)
+}
+
+var _ types.KeyStore = (*EncryptedKeyStore)(nil)
+
+func encryptKey(key []byte, info types.KeyInfo) (types.KeyInfo, error) {
+	plain, err := json.Marshal(info)
+	if err != nil {
+		return types.KeyInfo{}, err
+	}
+
+	sealed, err := seal(key, plain)
+	if err != nil {
+		return types.KeyInfo{}, err
+	}
+
+	return types.KeyInfo{
+		Type:       KTEncrypted,
+		PrivateKey: sealed,
+	}, nil
+}
+
+// EncryptKeyStore copies all keys from one keystore to another, encrypting
+// wallet keys under the passphrase. The destination keystore should be empty.
+func EncryptKeyStore(from, to types.KeyStore, passphrase []byte) error {
+	if ok, err := NewEncryptedKeyStore(from, 0).Encrypted(); err != nil {
+		return err
+	} else if ok {
+		return xerrors.Errorf("keystore is already encrypted")
+	}
+
+	ke := &keystoreEncryption{
+		Salt:    make([]byte, 16),
+		Time:    Argon2Time,
+		Memory:  Argon2Memory,
+		Threads: Argon2Threads,
+	}
+	if _, err := rand.Read(ke.Salt); err != nil {
+		return xerrors.Errorf("generating salt: %w", err)
+	}
+
+	key := ke.deriveKey(passphrase)
+
+	var err error
+	ke.Check, err = seal(key, checkPlaintext)
+	if err != nil {
+		return xerrors.Errorf("sealing check: %w", err)
+	}
+
+	names, err := from.List()
+	if err != nil {
+		return xerrors.Errorf("listing keys: %w", err)
+	}
+
+	for _, name := range names {
+		ki, err := from.Get(name)
+		if err != nil {
+			return xerrors.Errorf("reading key '%s': %w", name, err)
+		}
+
+		if encryptedName(name) {
+			ki, err = encryptKey(key, ki)
+			if err != nil {
+				return xerrors.Errorf("encrypting key '%s': %w", name, err)
+			}
+		}
+
+		if err := to.Put(name, ki); err != nil {
+			return xerrors.Errorf("writing key '%s': %w", name, err)
+		}
+	}
+
+	params, err := json.Marshal(ke)
+	if err != nil {
+		return xerrors.Errorf("encoding keystore encryption params: %w", err)
+	}
+
+	if err := to.Put(KEncryption, types.KeyInfo{Type: KTArgon2id, PrivateKey: params}); err != nil {
+		return xerrors.Errorf("writing keystore encryption params: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package wallet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+func init() {
+	Argon2Time = 1
+	Argon2Memory = 64
+}
+
+func TestEncryptedKeyStore(t *testing.T) {
+	ctx := context.Background()
+
+	plain := NewMemKeyStore()
+	pw, err := NewWallet(plain)
+	require.NoError(t, err)
+
+	addr, err := pw.WalletNew(ctx, types.KTSecp256k1)
+	require.NoError(t, err)
+	hostKey := types.KeyInfo{Type: "libp2p-host", PrivateKey: []byte("host")}
+	require.NoError(t, plain.Put("libp2p-host", hostKey))
+
+	enc := NewMemKeyStore()
+	require.NoError(t, EncryptKeyStore(plain, enc, []byte("secret")))
+	require.Error(t, EncryptKeyStore(enc, NewMemKeyStore(), []byte("secret")), "already encrypted")
+
+	// keys needed on startup stay readable
+	ki, err := enc.Get("libp2p-host")
+	require.NoError(t, err)
+	require.Equal(t, hostKey, ki)
+
+	ki, err = enc.Get(KNamePrefix + addr.String())
+	require.NoError(t, err)
+	require.Equal(t, KTEncrypted, ki.Type)
+
+	eks := NewEncryptedKeyStore(enc, 0)
+	w, err := NewWallet(eks)
+	require.NoError(t, err)
+
+	list, err := w.WalletList(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []address.Address{addr}, list)
+
+	has, err := w.WalletHas(ctx, addr)
+	require.NoError(t, err)
+	require.True(t, has)
+
+	_, err = w.WalletSign(ctx, addr, []byte("msg"), api.MsgMeta{})
+	require.True(t, xerrors.Is(err, types.ErrKeystoreLocked), err)
+	_, err = w.WalletNew(ctx, types.KTSecp256k1)
+	require.True(t, xerrors.Is(err, types.ErrKeystoreLocked), err)
+
+	require.Error(t, w.Unlock([]byte("wrong")))
+	require.True(t, eks.Locked())
+
+	require.NoError(t, w.Unlock([]byte("secret")))
+	_, err = w.WalletSign(ctx, addr, []byte("msg"), api.MsgMeta{})
+	require.NoError(t, err)
+
+	addr2, err := w.WalletNew(ctx, types.KTSecp256k1)
+	require.NoError(t, err)
+	ki, err = enc.Get(KNamePrefix + addr2.String())
+	require.NoError(t, err)
+	require.Equal(t, KTEncrypted, ki.Type)
+
+	// locking also drops keys cached in the wallet
+	require.NoError(t, w.Lock())
+	_, err = w.WalletSign(ctx, addr, []byte("msg"), api.MsgMeta{})
+	require.True(t, xerrors.Is(err, types.ErrKeystoreLocked), err)
+	_, err = w.WalletSign(ctx, addr2, []byte("msg"), api.MsgMeta{})
+	require.True(t, xerrors.Is(err, types.ErrKeystoreLocked), err)
+}
+
+func TestEncryptedKeyStoreRelock(t *testing.T) {
+	ctx := context.Background()
+
+	plain := NewMemKeyStore()
+	pw, err := NewWallet(plain)
+	require.NoError(t, err)
+	addr, err := pw.WalletNew(ctx, types.KTSecp256k1)
+	require.NoError(t, err)
+
+	enc := NewMemKeyStore()
+	require.NoError(t, EncryptKeyStore(plain, enc, []byte("secret")))
+
+	eks := NewEncryptedKeyStore(enc, 200*time.Millisecond)
+	w, err := NewWallet(eks)
+	require.NoError(t, err)
+	require.NoError(t, w.Unlock([]byte("secret")))
+
+	// signing keeps the keystore unlocked
+	for i := 0; i < 5; i++ {
+		_, err = w.WalletSign(ctx, addr, []byte("msg"), api.MsgMeta{})
+		require.NoError(t, err)
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	require.Eventually(t, eks.Locked, time.Second, 10*time.Millisecond)
+	_, err = w.WalletSign(ctx, addr, []byte("msg"), api.MsgMeta{})
+	require.True(t, xerrors.Is(err, types.ErrKeystoreLocked), err)
+}
@@ -21,6 +21,8 @@ type MultiWallet struct {
 	Local  *LocalWallet               `optional:"true"`
 	Remote *remotewallet.RemoteWallet `optional:"true"`
 	Ledger *ledgerwallet.LedgerWallet `optional:"true"`
+
+	Policy *UsagePolicies `optional:"true"`
 }
 
 type getif interface {
@@ -122,6 +124,10 @@ func (m MultiWallet) WalletSign(ctx context.Context, signer address.Address, toS
 		return nil, xerrors.Errorf("key not found")
 	}
 
+	if err := m.Policy.Check(ctx, signer, toSign, meta); err != nil {
+		return nil, err
+	}
+
 	return w.WalletSign(ctx, signer, toSign, meta)
 }
 
@@ -0,0 +1,308 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/namespace"
+	"github.com/ipfs/go-datastore/query"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/api/errcode"
+	"github.com/filecoin-project/lotus/chain/actors/builtin"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/market"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/miner"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/node/modules/dtypes"
+)
+
+const usagePolicyDs = "/wallet/policy"
+
+// AnyActor matches the messages to any actor in a message rule
+const AnyActor = "*"
+
+// ActorFamilies are the actor families message rules can name
+var ActorFamilies = []string{
+	"account", "cron", "init", "multisig", "paymentchannel", "reward", "storagemarket",
+	"storageminer", "storagepower", "system", "verifiedregistry",
+}
+
+// UsageRoles are the named roles a usage policy can give an address
+var UsageRoles = map[string]api.WalletUsagePolicy{
+	// the owner only manages the miner and withdraws its funds
+	"owner": {Messages: []api.WalletMessageRule{{
+		Actor: "storageminer",
+		Methods: []abi.MethodNum{
+			miner.Methods.ChangeWorkerAddress,
+			miner.Methods.ChangeOwnerAddress,
+			miner.Methods.ConfirmUpdateWorkerKey,
+			miner.Methods.WithdrawBalance,
+			miner.Methods.RepayDebt,
+		},
+	}}},
+	// the worker runs the miner, and signs its blocks, tickets and proofs
+	"worker": {
+		Messages: []api.WalletMessageRule{
+			{Actor: "storageminer"},
+			{Actor: "storagepower"},
+			{Actor: "storagemarket"},
+		},
+		Categories: []api.MsgType{api.MTBlock, api.MTUnknown},
+	},
+	// PoSt control addresses only prove and declare faults and recoveries
+	"post": {Messages: []api.WalletMessageRule{{
+		Actor: "storageminer",
+		Methods: []abi.MethodNum{
+			miner.Methods.SubmitWindowedPoSt,
+			miner.Methods.DeclareFaults,
+			miner.Methods.DeclareFaultsRecovered,
+		},
+	}}},
+	// deal control addresses publish deals and manage the market balance
+	"deals": {
+		Messages: []api.WalletMessageRule{{
+			Actor: "storagemarket",
+			Methods: []abi.MethodNum{
+				market.Methods.PublishStorageDeals,
+				market.Methods.AddBalance,
+				market.Methods.WithdrawBalance,
+			},
+		}},
+		Categories: []api.MsgType{api.MTDealProposal},
+	},
+	// senders send funds to accounts, multisigs and payment channels
+	"sender": {Messages: []api.WalletMessageRule{
+		{Actor: "account"},
+		{Actor: "multisig"},
+		{Actor: "paymentchannel"},
+	}},
+}
+
+// ActorCodeFunc returns the code of the actor at the address, cid.Undef if
+// there is no actor there
+type ActorCodeFunc func(ctx context.Context, addr address.Address) (cid.Cid, error)
+
+// UsagePolicies are the usage policies of the addresses, restricting what
+// they may sign. They are kept in the metadata store, and enforced when
+// signing with the wallet unless the context overrides them.
+type UsagePolicies struct {
+	ds        datastore.Datastore
+	actorCode ActorCodeFunc
+}
+
+func NewUsagePolicies(ds dtypes.MetadataDS, actorCode ActorCodeFunc) *UsagePolicies {
+	return &UsagePolicies{
+		ds:        namespace.Wrap(ds, datastore.NewKey(usagePolicyDs)),
+		actorCode: actorCode,
+	}
+}
+
+type policyOverrideKey struct{}
+
+// WithPolicyOverride returns a context signing with which ignores the usage
+// policies
+func WithPolicyOverride(ctx context.Context) context.Context {
+	return context.WithValue(ctx, policyOverrideKey{}, true)
+}
+
+func usagePolicyKey(addr address.Address) datastore.Key {
+	return datastore.NewKey(addr.String())
+}
+
+// Set sets the usage policy of the address
+func (p *UsagePolicies) Set(pol api.WalletUsagePolicy) error {
+	if pol.Address.Protocol() == address.ID {
+		return xerrors.Errorf("policy address %s is an ID address, set the policy of its key address", pol.Address)
+	}
+	for _, r := range pol.Roles {
+		if _, ok := UsageRoles[r]; !ok {
+			return xerrors.Errorf("unknown role %q", r)
+		}
+	}
+	for _, r := range pol.Messages {
+		if !knownActorFamily(r.Actor) {
+			return xerrors.Errorf("unknown actor family %q, expected one of %s or %s", r.Actor, strings.Join(ActorFamilies, ", "), AnyActor)
+		}
+	}
+	for _, c := range pol.Categories {
+		switch c {
+		case api.MTBlock, api.MTDealProposal, api.MTUnknown:
+		case api.MTChainMsg:
+			return xerrors.Errorf("chain messages are allowed by message rules, not a category")
+		default:
+			return xerrors.Errorf("unknown category %q", c)
+		}
+	}
+
+	b, err := json.Marshal(pol)
+	if err != nil {
+		return err
+	}
+	if err := p.ds.Put(usagePolicyKey(pol.Address), b); err != nil {
+		return xerrors.Errorf("setting usage policy: %w", err)
+	}
+	return nil
+}
+
+// Get returns the usage policy of the address, nil if it has none
+func (p *UsagePolicies) Get(addr address.Address) (*api.WalletUsagePolicy, error) {
+	b, err := p.ds.Get(usagePolicyKey(addr))
+	if err == datastore.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("getting usage policy: %w", err)
+	}
+
+	var pol api.WalletUsagePolicy
+	if err := json.Unmarshal(b, &pol); err != nil {
+		return nil, xerrors.Errorf("decoding usage policy of %s: %w", addr, err)
+	}
+	return &pol, nil
+}
+
+// Remove removes the usage policy of the address
+func (p *UsagePolicies) Remove(addr address.Address) error {
+	pol, err := p.Get(addr)
+	if err != nil {
+		return err
+	}
+	if pol == nil {
+		return xerrors.Errorf("%s has no usage policy", addr)
+	}
+	if err := p.ds.Delete(usagePolicyKey(addr)); err != nil {
+		return xerrors.Errorf("removing usage policy: %w", err)
+	}
+	return nil
+}
+
+// List returns the usage policies, ordered by address
+func (p *UsagePolicies) List() ([]api.WalletUsagePolicy, error) {
+	res, err := p.ds.Query(query.Query{})
+	if err != nil {
+		return nil, xerrors.Errorf("listing usage policies: %w", err)
+	}
+	entries, err := res.Rest()
+	if err != nil {
+		return nil, xerrors.Errorf("listing usage policies: %w", err)
+	}
+
+	out := make([]api.WalletUsagePolicy, 0, len(entries))
+	for _, e := range entries {
+		var pol api.WalletUsagePolicy
+		if err := json.Unmarshal(e.Value, &pol); err != nil {
+			return nil, xerrors.Errorf("decoding usage policy %s: %w", e.Key, err)
+		}
+		out = append(out, pol)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Address.String() < out[j].Address.String()
+	})
+	return out, nil
+}
+
+// Check returns an error wrapping api.ErrWalletPolicyViolation if the usage
+// policy of the signer doesn't allow signing toSign. Chain messages are
+// decoded from meta.Extra, which must be the message signed.
+func (p *UsagePolicies) Check(ctx context.Context, signer address.Address, toSign []byte, meta api.MsgMeta) error {
+	if p == nil || ctx.Value(policyOverrideKey{}) != nil {
+		return nil
+	}
+	pol, err := p.Get(signer)
+	if err != nil || pol == nil {
+		return err
+	}
+	rules, categories := effectiveRules(pol)
+
+	if meta.Type != api.MTChainMsg {
+		for _, c := range categories {
+			if c == meta.Type {
+				return nil
+			}
+		}
+		return policyViolation("the usage policy of %s doesn't allow signing %s data", signer, meta.Type)
+	}
+
+	var msg types.Message
+	if err := msg.UnmarshalCBOR(bytes.NewReader(meta.Extra)); err != nil {
+		return policyViolation("decoding the message signed by %s: %s", signer, err)
+	}
+	if _, c, err := cid.CidFromBytes(toSign); err != nil || !c.Equals(msg.Cid()) {
+		return policyViolation("the bytes signed by %s aren't the CID of the message", signer)
+	}
+
+	family, err := p.actorFamily(ctx, msg.To)
+	if err != nil {
+		return xerrors.Errorf("checking the usage policy of %s: %w", signer, err)
+	}
+	for _, r := range rules {
+		if r.Actor != AnyActor && r.Actor != family {
+			continue
+		}
+		if len(r.Methods) == 0 {
+			return nil
+		}
+		for _, m := range r.Methods {
+			if m == msg.Method {
+				return nil
+			}
+		}
+	}
+	return policyViolation("the usage policy of %s doesn't allow method %d of %s actor %s", signer, msg.Method, family, msg.To)
+}
+
+// actorFamily returns the family of the actor at the address. Messages to
+// key addresses without an actor create an account.
+func (p *UsagePolicies) actorFamily(ctx context.Context, addr address.Address) (string, error) {
+	code, err := p.actorCode(ctx, addr)
+	if err != nil {
+		return "", xerrors.Errorf("looking up actor %s: %w", addr, err)
+	}
+	if !code.Defined() {
+		if addr.Protocol() == address.SECP256K1 || addr.Protocol() == address.BLS {
+			return "account", nil
+		}
+		return "", xerrors.Errorf("actor %s not found", addr)
+	}
+
+	name := builtin.ActorNameByCode(code)
+	return name[strings.LastIndex(name, "/")+1:], nil
+}
+
+// effectiveRules returns the message rules and categories of the policy, its
+// roles included
+func effectiveRules(pol *api.WalletUsagePolicy) ([]api.WalletMessageRule, []api.MsgType) {
+	rules := append([]api.WalletMessageRule{}, pol.Messages...)
+	categories := append([]api.MsgType{}, pol.Categories...)
+	for _, r := range pol.Roles {
+		role := UsageRoles[r]
+		rules = append(rules, role.Messages...)
+		categories = append(categories, role.Categories...)
+	}
+	return rules, categories
+}
+
+func knownActorFamily(f string) bool {
+	if f == AnyActor {
+		return true
+	}
+	for _, af := range ActorFamilies {
+		if af == f {
+			return true
+		}
+	}
+	return false
+}
+
+func policyViolation(format string, args ...interface{}) error {
+	return api.WrapError(errcode.WalletPolicyViolation, xerrors.Errorf(format+": %w", append(args, api.ErrWalletPolicyViolation)...))
+}
@@ -0,0 +1,100 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/api/errcode"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/miner"
+	"github.com/filecoin-project/lotus/chain/types"
+
+	builtin5 "github.com/filecoin-project/specs-actors/v5/actors/builtin"
+)
+
+func testPolicyMessage(t *testing.T, from, to address.Address, method abi.MethodNum) ([]byte, api.MsgMeta) {
+	msg := &types.Message{From: from, To: to, Method: method, Value: types.NewInt(0), GasFeeCap: types.NewInt(0), GasPremium: types.NewInt(0)}
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, msg.MarshalCBOR(buf))
+	return msg.Cid().Bytes(), api.MsgMeta{Type: api.MTChainMsg, Extra: buf.Bytes()}
+}
+
+func TestUsagePolicies(t *testing.T) {
+	ctx := context.Background()
+
+	minerAddr, err := address.NewIDAddress(1000)
+	require.NoError(t, err)
+	recipient, err := address.NewSecp256k1Address([]byte("recipient"))
+	require.NoError(t, err)
+	post, err := address.NewSecp256k1Address([]byte("post"))
+	require.NoError(t, err)
+	other, err := address.NewSecp256k1Address([]byte("other"))
+	require.NoError(t, err)
+
+	p := NewUsagePolicies(dssync.MutexWrap(datastore.NewMapDatastore()), func(ctx context.Context, addr address.Address) (cid.Cid, error) {
+		if addr == minerAddr {
+			return builtin5.StorageMinerActorCodeID, nil
+		}
+		return cid.Undef, nil
+	})
+
+	require.Error(t, p.Set(api.WalletUsagePolicy{Address: minerAddr, Roles: []string{"post"}}))
+	require.Error(t, p.Set(api.WalletUsagePolicy{Address: post, Roles: []string{"janitor"}}))
+	require.Error(t, p.Set(api.WalletUsagePolicy{Address: post, Messages: []api.WalletMessageRule{{Actor: "miner"}}}))
+	require.Error(t, p.Set(api.WalletUsagePolicy{Address: post, Categories: []api.MsgType{api.MTChainMsg}}))
+	require.NoError(t, p.Set(api.WalletUsagePolicy{Address: post, Roles: []string{"post"}}))
+
+	toSign, meta := testPolicyMessage(t, post, minerAddr, miner.Methods.SubmitWindowedPoSt)
+	require.NoError(t, p.Check(ctx, post, toSign, meta))
+
+	// a PoSt address can't withdraw the miner balance or send funds
+	toSign, meta = testPolicyMessage(t, post, minerAddr, miner.Methods.WithdrawBalance)
+	err = p.Check(ctx, post, toSign, meta)
+	require.True(t, xerrors.Is(err, api.ErrWalletPolicyViolation))
+	require.Equal(t, errcode.WalletPolicyViolation, api.ErrorCode(err))
+	require.NoError(t, p.Check(WithPolicyOverride(ctx), post, toSign, meta))
+
+	sendSign, sendMeta := testPolicyMessage(t, post, recipient, 0)
+	require.Error(t, p.Check(ctx, post, sendSign, sendMeta))
+	require.NoError(t, p.Check(ctx, other, sendSign, sendMeta))
+
+	// the signed bytes must be the message described
+	require.Error(t, p.Check(ctx, post, sendSign, meta))
+	require.Error(t, p.Check(ctx, post, []byte("block"), api.MsgMeta{Type: api.MTBlock}))
+
+	require.NoError(t, p.Set(api.WalletUsagePolicy{
+		Address:    post,
+		Roles:      []string{"post"},
+		Messages:   []api.WalletMessageRule{{Actor: "account"}},
+		Categories: []api.MsgType{api.MTBlock},
+	}))
+	require.NoError(t, p.Check(ctx, post, sendSign, sendMeta))
+	require.NoError(t, p.Check(ctx, post, []byte("block"), api.MsgMeta{Type: api.MTBlock}))
+
+	list, err := p.List()
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, post, list[0].Address)
+
+	require.NoError(t, p.Remove(post))
+	require.Error(t, p.Remove(post))
+	require.NoError(t, p.Check(ctx, post, toSign, meta))
+
+	pol, err := p.Get(post)
+	require.NoError(t, err)
+	require.Nil(t, pol)
+
+	var none *UsagePolicies
+	require.NoError(t, none.Check(ctx, post, toSign, meta))
+}
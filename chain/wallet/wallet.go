@@ -44,9 +44,40 @@ func NewWallet(keystore types.KeyStore) (*LocalWallet, error) {
 		keystore: keystore,
 	}
 
+	if eks, ok := keystore.(*EncryptedKeyStore); ok {
+		eks.onLock = w.dropKeys
+	}
+
 	return w, nil
 }
 
+// dropKeys removes decrypted keys from memory once an encrypted keystore gets locked
+func (w *LocalWallet) dropKeys() {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+
+	w.keys = make(map[address.Address]*Key)
+}
+
+// Unlock unlocks an encrypted keystore, enabling signing
+func (w *LocalWallet) Unlock(passphrase []byte) error {
+	eks, ok := w.keystore.(*EncryptedKeyStore)
+	if !ok {
+		return xerrors.Errorf("keystore is not encrypted")
+	}
+	return eks.Unlock(passphrase)
+}
+
+// Lock locks an encrypted keystore, disabling signing until it's unlocked again
+func (w *LocalWallet) Lock() error {
+	eks, ok := w.keystore.(*EncryptedKeyStore)
+	if !ok {
+		return xerrors.Errorf("keystore is not encrypted")
+	}
+	eks.Lock()
+	return nil
+}
+
 func KeyWallet(keys ...*Key) *LocalWallet {
 	m := make(map[address.Address]*Key)
 	for _, key := range keys {
@@ -76,6 +107,12 @@ func (w *LocalWallet) findKey(addr address.Address) (*Key, error) {
 
 	k, ok := w.keys[addr]
 	if ok {
+		if eks, ok := w.keystore.(*EncryptedKeyStore); ok {
+			// cached keys count as keystore use for the idle relock
+			if _, err := eks.currentKey(); err != nil {
+				return nil, err
+			}
+		}
 		return k, nil
 	}
 	if w.keystore == nil {
@@ -262,6 +299,9 @@ func (w *LocalWallet) WalletNew(ctx context.Context, typ types.KeyType) (address
 
 func (w *LocalWallet) WalletHas(ctx context.Context, addr address.Address) (bool, error) {
 	k, err := w.findKey(addr)
+	if xerrors.Is(err, types.ErrKeystoreLocked) {
+		return true, nil // the key is there, signing will fail until the keystore is unlocked
+	}
 	if err != nil {
 		return false, err
 	}
@@ -0,0 +1,112 @@
+package wallet
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/namespace"
+	"github.com/ipfs/go-datastore/query"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/api/errcode"
+	"github.com/filecoin-project/lotus/node/modules/dtypes"
+)
+
+const watchOnlyDs = "/wallet/watchonly"
+
+// WatchOnly is the list of the watch-only addresses, tracked by the node
+// without their keys. It is kept in the metadata store so the keystore only
+// ever holds keys.
+type WatchOnly struct {
+	ds datastore.Datastore
+}
+
+func NewWatchOnly(ds dtypes.MetadataDS) *WatchOnly {
+	return &WatchOnly{ds: namespace.Wrap(ds, datastore.NewKey(watchOnlyDs))}
+}
+
+func watchOnlyKey(addr address.Address) datastore.Key {
+	return datastore.NewKey(addr.String())
+}
+
+// Add adds the address, or changes its label
+func (w *WatchOnly) Add(addr address.Address, label string) error {
+	if addr.Protocol() == address.ID {
+		return xerrors.Errorf("watch-only address %s is an ID address, add its robust address", addr)
+	}
+	b, err := json.Marshal(api.WatchOnlyAddress{Address: addr, Label: label})
+	if err != nil {
+		return err
+	}
+	if err := w.ds.Put(watchOnlyKey(addr), b); err != nil {
+		return xerrors.Errorf("adding watch-only address: %w", err)
+	}
+	return nil
+}
+
+func (w *WatchOnly) Remove(addr address.Address) error {
+	has, err := w.Has(addr)
+	if err != nil {
+		return err
+	}
+	if !has {
+		return xerrors.Errorf("%s isn't a watch-only address", addr)
+	}
+	if err := w.ds.Delete(watchOnlyKey(addr)); err != nil {
+		return xerrors.Errorf("removing watch-only address: %w", err)
+	}
+	return nil
+}
+
+func (w *WatchOnly) Has(addr address.Address) (bool, error) {
+	has, err := w.ds.Has(watchOnlyKey(addr))
+	if err != nil {
+		return false, xerrors.Errorf("looking up watch-only address: %w", err)
+	}
+	return has, nil
+}
+
+// List returns the watch-only addresses, ordered by address
+func (w *WatchOnly) List() ([]api.WatchOnlyAddress, error) {
+	res, err := w.ds.Query(query.Query{})
+	if err != nil {
+		return nil, xerrors.Errorf("listing watch-only addresses: %w", err)
+	}
+	entries, err := res.Rest()
+	if err != nil {
+		return nil, xerrors.Errorf("listing watch-only addresses: %w", err)
+	}
+
+	out := make([]api.WatchOnlyAddress, 0, len(entries))
+	for _, e := range entries {
+		var wa api.WatchOnlyAddress
+		if err := json.Unmarshal(e.Value, &wa); err != nil {
+			return nil, xerrors.Errorf("decoding watch-only address %s: %w", e.Key, err)
+		}
+		out = append(out, wa)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Address.String() < out[j].Address.String()
+	})
+	return out, nil
+}
+
+// Check returns an error wrapping api.ErrWatchOnlyAddress if the address is
+// watch-only
+func (w *WatchOnly) Check(addr address.Address) error {
+	if w == nil {
+		return nil
+	}
+	has, err := w.Has(addr)
+	if err != nil {
+		return err
+	}
+	if has {
+		return api.WrapError(errcode.WatchOnlyAddress, xerrors.Errorf("can't sign with %s: %w", addr, api.ErrWatchOnlyAddress))
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/api/errcode"
+)
+
+func TestWatchOnly(t *testing.T) {
+	w := NewWatchOnly(dssync.MutexWrap(datastore.NewMapDatastore()))
+
+	treasury, err := address.NewSecp256k1Address([]byte("treasury"))
+	require.NoError(t, err)
+	other, err := address.NewSecp256k1Address([]byte("other"))
+	require.NoError(t, err)
+	id, err := address.NewIDAddress(1000)
+	require.NoError(t, err)
+
+	require.NoError(t, w.Add(treasury, "treasury"))
+	require.Error(t, w.Add(id, "exchange"))
+
+	has, err := w.Has(treasury)
+	require.NoError(t, err)
+	require.True(t, has)
+
+	err = w.Check(treasury)
+	require.True(t, xerrors.Is(err, api.ErrWatchOnlyAddress))
+	require.Equal(t, errcode.WatchOnlyAddress, api.ErrorCode(err))
+	require.NoError(t, w.Check(other))
+
+	list, err := w.List()
+	require.NoError(t, err)
+	require.Equal(t, []api.WatchOnlyAddress{{Address: treasury, Label: "treasury"}}, list)
+
+	require.NoError(t, w.Remove(treasury))
+	require.Error(t, w.Remove(treasury))
+	require.NoError(t, w.Check(treasury))
+
+	var none *WatchOnly
+	require.NoError(t, none.Check(treasury))
+}
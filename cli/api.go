@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"os"
+	"sort"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/lib/tablewriter"
+)
+
+var ApiCmd = &cli.Command{
+	Name:  "api",
+	Usage: "Inspect the node API",
+	Subcommands: []*cli.Command{
+		apiMethodsCmd,
+	},
+}
+
+var apiMethodsCmd = &cli.Command{
+	Name:  "methods",
+	Usage: "List API methods with their stability classification",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "filter",
+			Usage: "only list methods with the given stability (stable, experimental, deprecated)",
+		},
+		&cli.BoolFlag{
+			Name:  "v0",
+			Usage: "list methods of the v0 API instead of v1",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		filter := api.Stability(cctx.String("filter"))
+		switch filter {
+		case "", api.StabilityStable, api.StabilityExperimental, api.StabilityDeprecated:
+		default:
+			return xerrors.Errorf("unknown stability '%s'", filter)
+		}
+
+		ctx := ReqContext(cctx)
+
+		var methods map[string]api.MethodStability
+		if cctx.Bool("v0") {
+			napi, closer, err := GetFullNodeAPI(cctx)
+			if err != nil {
+				return err
+			}
+			defer closer()
+
+			methods, err = napi.Methods(ctx)
+			if err != nil {
+				return err
+			}
+		} else {
+			napi, closer, err := GetFullNodeAPIV1(cctx)
+			if err != nil {
+				return err
+			}
+			defer closer()
+
+			methods, err = napi.Methods(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		names := make([]string, 0, len(methods))
+		for name, m := range methods {
+			if filter != "" && m.Stability != filter {
+				continue
+			}
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		tw := tablewriter.New(
+			tablewriter.Col("Method"),
+			tablewriter.Col("Stability"),
+			tablewriter.Col("Removal"),
+			tablewriter.Col("Replacement"))
+
+		for _, name := range names {
+			m := methods[name]
+			tw.Write(map[string]interface{}{
+				"Method":      name,
+				"Stability":   m.Stability,
+				"Removal":     m.Removal,
+				"Replacement": m.Replacement,
+			})
+		}
+
+		return tw.Flush(os.Stdout)
+	},
+}
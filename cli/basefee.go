@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	stdbig "math/big"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/lotus/build"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+// maxBaseFeeChange is the most the base fee can change by in an epoch
+const maxBaseFeeChange = 1.0 / build.BaseFeeMaxChangeDenom
+
+// BaseFeeProjection extrapolates the recent base fee movement
+type BaseFeeProjection struct {
+	// Rate is the average change of the base fee per epoch over the
+	// lookback, e.g. 1.02 for a 2% rise
+	Rate float64
+	// Bounded is set when the trend was steeper than the base fee can move
+	Bounded bool
+	// Fees are the projected base fees, from the next epoch on
+	Fees []abi.TokenAmount
+}
+
+// projectBaseFee extrapolates the base fee history, oldest first, over the
+// next epochs at the average rate of change of the history. The rate is
+// bounded by the most the base fee can change in an epoch.
+func projectBaseFee(history []abi.TokenAmount, epochs int) BaseFeeProjection {
+	p := BaseFeeProjection{Rate: 1}
+	if len(history) == 0 {
+		return p
+	}
+
+	first, last := history[0], history[len(history)-1]
+	if len(history) > 1 && first.GreaterThan(big.Zero()) {
+		ratio, _ := new(stdbig.Rat).SetFrac(last.Int, first.Int).Float64()
+		p.Rate = math.Pow(ratio, 1/float64(len(history)-1))
+	}
+	if p.Rate > 1+maxBaseFeeChange {
+		p.Rate, p.Bounded = 1+maxBaseFeeChange, true
+	}
+	if p.Rate < 1-maxBaseFeeChange {
+		p.Rate, p.Bounded = 1-maxBaseFeeChange, true
+	}
+
+	fee := new(stdbig.Float).SetInt(last.Int)
+	rate := stdbig.NewFloat(p.Rate)
+	for i := 0; i < epochs; i++ {
+		fee.Mul(fee, rate)
+
+		projected, _ := fee.Int(nil)
+		bf := big.Max(big.NewFromGo(projected), big.NewInt(build.MinimumBaseFee))
+		p.Fees = append(p.Fees, bf)
+	}
+	return p
+}
+
+// Stall returns the first epoch, counting from 1, at which the projected base
+// fee exceeds the fee cap, or 0 if the fee cap stays above it
+func (p BaseFeeProjection) Stall(feeCap abi.TokenAmount) int {
+	for i, bf := range p.Fees {
+		if bf.GreaterThan(feeCap) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+var MpoolFeeProjectionCmd = &cli.Command{
+	Name:      "fee-projection",
+	Usage:     "Project the base fee from its recent trend, and check a fee cap against it",
+	ArgsUsage: "[feeCap (attoFIL, optional)]",
+	Description: `Extrapolates the base fee over the next epochs from its average change over
+   the last epochs, and tells whether a message with the fee cap would stay
+   includable. This is an estimate: the base fee follows how full the blocks
+   are, and a recent trend says little about where it goes next.`,
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:  "epochs",
+			Usage: "number of epochs to project the base fee over",
+			Value: 10,
+		},
+		&cli.IntFlag{
+			Name:  "lookback",
+			Usage: "number of epochs the trend is averaged over",
+			Value: 20,
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.Args().Len() > 1 {
+			return ShowHelp(cctx, fmt.Errorf("'fee-projection' expects at most one argument, the fee cap"))
+		}
+		epochs, lookback := cctx.Int("epochs"), cctx.Int("lookback")
+		if epochs < 1 || lookback < 1 {
+			return xerrors.Errorf("--epochs and --lookback must be positive")
+		}
+
+		var feeCap *abi.TokenAmount
+		if cctx.Args().Present() {
+			fc, err := types.BigFromString(cctx.Args().First())
+			if err != nil {
+				return xerrors.Errorf("parsing fee cap: %w", err)
+			}
+			feeCap = &fc
+		}
+
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := ReqContext(cctx)
+
+		ts, err := api.ChainHead(ctx)
+		if err != nil {
+			return xerrors.Errorf("getting chain head: %w", err)
+		}
+
+		history := []abi.TokenAmount{ts.Blocks()[0].ParentBaseFee}
+		for i := 0; i < lookback && ts.Height() > 0; i++ {
+			ts, err = api.ChainGetTipSet(ctx, ts.Parents())
+			if err != nil {
+				return xerrors.Errorf("walking chain: %w", err)
+			}
+			history = append([]abi.TokenAmount{ts.Blocks()[0].ParentBaseFee}, history...)
+		}
+
+		unit, err := feeUnit(cctx)
+		if err != nil {
+			return err
+		}
+
+		p := projectBaseFee(history, epochs)
+		afmt := NewAppFmt(cctx.App)
+
+		afmt.Printf("Base fee now: %s\n", unit.Format(history[len(history)-1]))
+		afmt.Printf("Trend: %+.2f%% per epoch, averaged over the last %d epochs\n", (p.Rate-1)*100, len(history)-1)
+		if p.Bounded {
+			afmt.Printf("  (steeper than the base fee can move, projected at the %.1f%% limit)\n", maxBaseFeeChange*100)
+		}
+		afmt.Printf("Projected base fee in %d epochs: %s\n", epochs, unit.Format(p.Fees[len(p.Fees)-1]))
+
+		if feeCap != nil {
+			if stall := p.Stall(*feeCap); stall > 0 {
+				afmt.Printf("WARNING: fee cap %s falls below the projected base fee %s in %d epochs; "+
+					"a message with it would stall until the base fee comes down\n",
+					unit.Format(*feeCap), unit.Format(p.Fees[stall-1]), stall)
+			} else {
+				afmt.Printf("Fee cap %s stays above the projected base fee for the next %d epochs\n", unit.Format(*feeCap), epochs)
+			}
+		}
+
+		afmt.Println()
+		afmt.Println("This is an estimate: it assumes the base fee keeps changing at its recent average rate.")
+		afmt.Printf("The base fee moves by up to %.1f%% per epoch depending on how full blocks are, so trends rarely hold for long.\n", maxBaseFeeChange*100)
+		return nil
+	},
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/go-state-types/abi"
+)
+
+func TestProjectBaseFee(t *testing.T) {
+	fees := func(fs ...int64) []abi.TokenAmount {
+		var out []abi.TokenAmount
+		for _, f := range fs {
+			out = append(out, abi.NewTokenAmount(f))
+		}
+		return out
+	}
+
+	// doubling over two epochs
+	p := projectBaseFee(fees(1000, 1200, 2000), 2)
+	require.InDelta(t, 1.0+maxBaseFeeChange, p.Rate, 1e-9)
+	require.True(t, p.Bounded)
+	require.Equal(t, fees(2250, 2531), p.Fees)
+
+	p = projectBaseFee(fees(1000, 1050, 1100), 3)
+	require.False(t, p.Bounded)
+	require.Equal(t, fees(1153, 1210, 1269), p.Fees)
+	require.Equal(t, 2, p.Stall(abi.NewTokenAmount(1200)))
+	require.Equal(t, 0, p.Stall(abi.NewTokenAmount(1300)))
+
+	// never below the minimum base fee
+	p = projectBaseFee(fees(200, 110), 2)
+	require.Equal(t, fees(100, 100), p.Fees)
+
+	p = projectBaseFee(fees(500), 1)
+	require.Equal(t, 1.0, p.Rate)
+	require.Equal(t, fees(500), p.Fees)
+}
@@ -37,6 +37,7 @@ import (
 	"github.com/filecoin-project/lotus/chain/actors"
 	"github.com/filecoin-project/lotus/chain/stmgr"
 	types "github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/lib/tablewriter"
 )
 
 var ChainCmd = &cli.Command{
@@ -54,6 +55,8 @@ var ChainCmd = &cli.Command{
 		ChainGetCmd,
 		ChainBisectCmd,
 		ChainExportCmd,
+		ChainFollowCmd,
+		ChainMetricsCmd,
 		SlashConsensusFault,
 		ChainGasPriceCmd,
 		ChainInspectUsage,
@@ -536,6 +539,7 @@ var ChainListCmd = &cli.Command{
 	Usage:   "View a segment of the chain",
 	Flags: []cli.Flag{
 		&cli.Uint64Flag{Name: "height"},
+		nullRoundFlag,
 		&cli.IntFlag{Name: "count", Value: 30},
 		&cli.StringFlag{
 			Name:  "format",
@@ -558,7 +562,7 @@ var ChainListCmd = &cli.Command{
 		var head *types.TipSet
 
 		if cctx.IsSet("height") {
-			head, err = api.ChainGetTipSetByHeight(ctx, abi.ChainEpoch(cctx.Uint64("height")), types.EmptyTSK)
+			head, err = getTipSetByHeight(ctx, api, abi.ChainEpoch(cctx.Uint64("height")), lapi.NullRoundPolicy(cctx.String("null-round")))
 		} else {
 			head, err = api.ChainHead(ctx)
 		}
@@ -588,6 +592,11 @@ var ChainListCmd = &cli.Command{
 		}
 
 		if cctx.Bool("gas-stats") {
+			unit, err := feeUnit(cctx)
+			if err != nil {
+				return err
+			}
+
 			otss := make([]*types.TipSet, 0, len(tss))
 			for i := len(tss) - 1; i >= 0; i-- {
 				otss = append(otss, tss[i])
@@ -595,7 +604,7 @@ var ChainListCmd = &cli.Command{
 			tss = otss
 			for i, ts := range tss {
 				pbf := ts.Blocks()[0].ParentBaseFee
-				fmt.Printf("%d: %d blocks (baseFee: %s -> maxFee: %s)\n", ts.Height(), len(ts.Blocks()), ts.Blocks()[0].ParentBaseFee, types.FIL(types.BigMul(pbf, types.NewInt(uint64(build.BlockGasLimit)))))
+				fmt.Printf("%d: %d blocks (baseFee: %s/gas -> maxFee: %s)\n", ts.Height(), len(ts.Blocks()), unit.Format(pbf), unit.Format(types.BigMul(pbf, types.NewInt(uint64(build.BlockGasLimit)))))
 
 				for _, b := range ts.Blocks() {
 					msgs, err := api.ChainGetBlockMessages(ctx, b.Cid())
@@ -621,7 +630,7 @@ var ChainListCmd = &cli.Command{
 						avgpremium = big.Div(psum, big.NewInt(int64(lenmsgs)))
 					}
 
-					fmt.Printf("\t%s: \t%d msgs, gasLimit: %d / %d (%0.2f%%), avgPremium: %s\n", b.Miner, len(msgs.BlsMessages)+len(msgs.SecpkMessages), limitSum, build.BlockGasLimit, 100*float64(limitSum)/float64(build.BlockGasLimit), avgpremium)
+					fmt.Printf("\t%s: \t%d msgs, gasLimit: %d / %d (%0.2f%%), avgPremium: %s/gas\n", b.Miner, len(msgs.BlsMessages)+len(msgs.SecpkMessages), limitSum, build.BlockGasLimit, 100*float64(limitSum)/float64(build.BlockGasLimit), unit.Format(avgpremium))
 				}
 				if i < len(tss)-1 {
 					msgs, err := api.ChainGetParentMessages(ctx, tss[i+1].Blocks()[0].Cid())
@@ -676,6 +685,9 @@ var ChainGetCmd = &cli.Command{
 			Name:  "tipset",
 			Usage: "specify tipset for /pstate (pass comma separated array of cids)",
 		},
+		chainGetListFlag,
+		chainGetOffsetFlag,
+		chainGetLimitFlag,
 	},
 	Description: `Get ipld node under a specified path:
 
@@ -683,7 +695,16 @@ var ChainGetCmd = &cli.Command{
 
    Path prefixes:
    - /ipfs/[cid], /ipld/[cid] - traverse IPLD path
-   - /pstate - traverse from head.ParentStateRoot
+   - /pstate, /state - traverse from head.ParentStateRoot
+
+   Under /pstate the node types are known from the actor versions. Actors are
+   reached by address, the fields of their state by name, and the HAMTs and
+   AMTs in their state by their human keys (addresses, integers, cids), the
+   leaves being shown as their Go types:
+
+   lotus chain get /pstate/w01234/sectors/5
+   lotus chain get /pstate/@Ha:w01234/deadlines/due/3/partitions/@A:0
+   lotus chain get --list --offset 100 /pstate/w04/claims
 
    Note:
    You can use special path elements to traverse through some data structures:
@@ -715,6 +736,13 @@ var ChainGetCmd = &cli.Command{
 		ctx := ReqContext(cctx)
 
 		p := path.Clean(cctx.Args().First())
+		if segs, ok := splitStatePath(p); ok && cctx.String("as-type") == "" {
+			return chainGetStatePath(ctx, cctx, api, p, segs)
+		}
+		if cctx.Bool("list") {
+			return xerrors.Errorf("--list needs a /pstate path, under which the node types are known")
+		}
+
 		if strings.HasPrefix(p, "/pstate") {
 			p = p[len("/pstate"):]
 
@@ -1239,6 +1267,11 @@ var ChainGasPriceCmd = &cli.Command{
 		defer closer()
 		ctx := ReqContext(cctx)
 
+		unit, err := feeUnit(cctx)
+		if err != nil {
+			return err
+		}
+
 		nb := []int{1, 2, 3, 5, 10, 20, 50, 100, 300}
 		for _, nblocks := range nb {
 			addr := builtin.SystemActorAddr // TODO: make real when used in GasEstimateGasPremium
@@ -1248,7 +1281,7 @@ var ChainGasPriceCmd = &cli.Command{
 				return err
 			}
 
-			fmt.Printf("%d blocks: %s (%s)\n", nblocks, est, types.FIL(est))
+			fmt.Printf("%d blocks: %s/gas\n", nblocks, unit.Format(est))
 		}
 
 		return nil
@@ -1417,3 +1450,46 @@ var chainEncodeParamsCmd = &cli.Command{
 		return nil
 	},
 }
+
+var ChainFollowCmd = &cli.Command{
+	Name:  "follow",
+	Usage: "Inspect consumers of chain follow deliveries",
+	Subcommands: []*cli.Command{
+		chainFollowListCmd,
+	},
+}
+
+var chainFollowListCmd = &cli.Command{
+	Name:  "list",
+	Usage: "List chain follow consumers with the lag of their cursor behind the chain head",
+	Action: func(cctx *cli.Context) error {
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+		ctx := ReqContext(cctx)
+
+		consumers, err := api.ChainFollowConsumers(ctx)
+		if err != nil {
+			return err
+		}
+
+		tw := tablewriter.New(
+			tablewriter.Col("Consumer"),
+			tablewriter.Col("Cursor"),
+			tablewriter.Col("Lag"),
+			tablewriter.NewLineCol("Error"))
+
+		for _, c := range consumers {
+			tw.Write(map[string]interface{}{
+				"Consumer": c.Name,
+				"Cursor":   c.CursorHeight,
+				"Lag":      c.Lag,
+				"Error":    c.Error,
+			})
+		}
+
+		return tw.Flush(cctx.App.Writer)
+	},
+}
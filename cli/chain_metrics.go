@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/lotus/build"
+	"github.com/filecoin-project/lotus/lib/tablewriter"
+)
+
+var ChainMetricsCmd = &cli.Command{
+	Name:  "metrics",
+	Usage: "Query the long-term metrics history recorded by the node",
+	Description: `The metrics history is enabled with Metrics.History.Enable in the node config,
+   recording the configured series (basefee, networkpower, power:<miner>,
+   pledge:<miner>, balance:<address>) once every few epochs, and downsampling
+   them as they age.`,
+	Subcommands: []*cli.Command{
+		chainMetricsListCmd,
+		chainMetricsQueryCmd,
+	},
+}
+
+var chainMetricsListCmd = &cli.Command{
+	Name:  "list",
+	Usage: "List the recorded series",
+	Action: func(cctx *cli.Context) error {
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+		ctx := ReqContext(cctx)
+
+		series, err := api.ChainMetricsSeries(ctx)
+		if err != nil {
+			return err
+		}
+
+		tw := tablewriter.New(
+			tablewriter.Col("Series"),
+			tablewriter.Col("Points"),
+			tablewriter.Col("First"),
+			tablewriter.Col("Last"))
+
+		for _, s := range series {
+			tw.Write(map[string]interface{}{
+				"Series": s.Name,
+				"Points": s.Points,
+				"First":  s.First,
+				"Last":   s.Last,
+			})
+		}
+
+		return tw.Flush(cctx.App.Writer)
+	},
+}
+
+var chainMetricsQueryCmd = &cli.Command{
+	Name:      "query",
+	Usage:     "Print the points of a series as CSV",
+	ArgsUsage: "<series>",
+	Description: `Prints a row per point, with the minimum, maximum, average and last value of
+   the samples it aggregates. Periods without samples, when the node wasn't
+   recording, are printed as rows without samples nor values.`,
+	Flags: []cli.Flag{
+		&cli.Int64Flag{
+			Name:  "from",
+			Usage: "first epoch to print",
+		},
+		&cli.Int64Flag{
+			Name:  "to",
+			Usage: "last epoch to print, the latest point by default",
+		},
+		&cli.DurationFlag{
+			Name:  "since",
+			Usage: "print the points of this long before the chain head (e.g. 720h), instead of --from",
+		},
+		&cli.DurationFlag{
+			Name:  "resolution",
+			Usage: "aggregate points to this resolution (e.g. 24h), the resolution they're kept at by default",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.Args().Len() != 1 {
+			return ShowHelp(cctx, fmt.Errorf("expected the series to query"))
+		}
+
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+		ctx := ReqContext(cctx)
+
+		blockDelay := time.Duration(build.BlockDelaySecs) * time.Second
+		from := abi.ChainEpoch(cctx.Int64("from"))
+		if cctx.IsSet("since") {
+			head, err := api.ChainHead(ctx)
+			if err != nil {
+				return err
+			}
+			from = head.Height() - abi.ChainEpoch(cctx.Duration("since")/blockDelay)
+		}
+		resolution := abi.ChainEpoch(cctx.Duration("resolution") / blockDelay)
+
+		genesis, err := api.ChainGetGenesis(ctx)
+		if err != nil {
+			return err
+		}
+
+		pts, err := api.ChainMetricsQuery(ctx, cctx.Args().First(), from, abi.ChainEpoch(cctx.Int64("to")), resolution)
+		if err != nil {
+			return err
+		}
+
+		w := csv.NewWriter(cctx.App.Writer)
+		if err := w.Write([]string{"epoch", "time", "span", "samples", "min", "max", "avg", "last"}); err != nil {
+			return err
+		}
+		for _, p := range pts {
+			t := time.Unix(int64(genesis.MinTimestamp())+int64(p.Epoch)*int64(build.BlockDelaySecs), 0).UTC()
+			row := []string{
+				strconv.FormatInt(int64(p.Epoch), 10),
+				t.Format(time.RFC3339),
+				strconv.FormatInt(int64(p.Span), 10),
+				strconv.FormatInt(p.Samples, 10),
+				"", "", "", "",
+			}
+			if p.Samples > 0 {
+				row[4], row[5], row[6], row[7] = p.Min.String(), p.Max.String(), p.Avg.String(), p.Last.String()
+			}
+			if err := w.Write(row); err != nil {
+				return err
+			}
+		}
+		w.Flush()
+		return w.Error()
+	},
+}
@@ -0,0 +1,928 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	cid "github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	"github.com/urfave/cli/v2"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	builtin0 "github.com/filecoin-project/specs-actors/actors/builtin"
+	market0 "github.com/filecoin-project/specs-actors/actors/builtin/market"
+	miner0 "github.com/filecoin-project/specs-actors/actors/builtin/miner"
+	multisig0 "github.com/filecoin-project/specs-actors/actors/builtin/multisig"
+	paych0 "github.com/filecoin-project/specs-actors/actors/builtin/paych"
+	power0 "github.com/filecoin-project/specs-actors/actors/builtin/power"
+	adt0 "github.com/filecoin-project/specs-actors/actors/util/adt"
+
+	builtin2 "github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	market2 "github.com/filecoin-project/specs-actors/v2/actors/builtin/market"
+	miner2 "github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+	multisig2 "github.com/filecoin-project/specs-actors/v2/actors/builtin/multisig"
+	paych2 "github.com/filecoin-project/specs-actors/v2/actors/builtin/paych"
+	power2 "github.com/filecoin-project/specs-actors/v2/actors/builtin/power"
+	adt2 "github.com/filecoin-project/specs-actors/v2/actors/util/adt"
+
+	builtin3 "github.com/filecoin-project/specs-actors/v3/actors/builtin"
+	market3 "github.com/filecoin-project/specs-actors/v3/actors/builtin/market"
+	miner3 "github.com/filecoin-project/specs-actors/v3/actors/builtin/miner"
+	multisig3 "github.com/filecoin-project/specs-actors/v3/actors/builtin/multisig"
+	paych3 "github.com/filecoin-project/specs-actors/v3/actors/builtin/paych"
+	power3 "github.com/filecoin-project/specs-actors/v3/actors/builtin/power"
+	adt3 "github.com/filecoin-project/specs-actors/v3/actors/util/adt"
+
+	builtin4 "github.com/filecoin-project/specs-actors/v4/actors/builtin"
+	market4 "github.com/filecoin-project/specs-actors/v4/actors/builtin/market"
+	miner4 "github.com/filecoin-project/specs-actors/v4/actors/builtin/miner"
+	multisig4 "github.com/filecoin-project/specs-actors/v4/actors/builtin/multisig"
+	paych4 "github.com/filecoin-project/specs-actors/v4/actors/builtin/paych"
+	power4 "github.com/filecoin-project/specs-actors/v4/actors/builtin/power"
+	adt4 "github.com/filecoin-project/specs-actors/v4/actors/util/adt"
+
+	builtin5 "github.com/filecoin-project/specs-actors/v5/actors/builtin"
+	market5 "github.com/filecoin-project/specs-actors/v5/actors/builtin/market"
+	miner5 "github.com/filecoin-project/specs-actors/v5/actors/builtin/miner"
+	multisig5 "github.com/filecoin-project/specs-actors/v5/actors/builtin/multisig"
+	paych5 "github.com/filecoin-project/specs-actors/v5/actors/builtin/paych"
+	power5 "github.com/filecoin-project/specs-actors/v5/actors/builtin/power"
+	adt5 "github.com/filecoin-project/specs-actors/v5/actors/util/adt"
+
+	"github.com/filecoin-project/go-bitfield"
+
+	"github.com/filecoin-project/lotus/api/v0api"
+	"github.com/filecoin-project/lotus/blockstore"
+	"github.com/filecoin-project/lotus/chain/actors"
+	"github.com/filecoin-project/lotus/chain/actors/adt"
+	"github.com/filecoin-project/lotus/chain/actors/builtin"
+	"github.com/filecoin-project/lotus/chain/state"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/chain/vm"
+)
+
+var (
+	chainGetListFlag = &cli.BoolFlag{
+		Name:  "list",
+		Usage: "list the keys under a /pstate path (state tree, actor, struct, HAMT or AMT) instead of its value",
+	}
+	chainGetOffsetFlag = &cli.IntFlag{
+		Name:  "offset",
+		Usage: "with --list, the number of keys to skip",
+	}
+	chainGetLimitFlag = &cli.IntFlag{
+		Name:  "limit",
+		Usage: "with --list, the number of keys to list, 0 for all of them",
+		Value: 50,
+	}
+)
+
+// keyKind is how the keys of a HAMT are encoded
+type keyKind int
+
+const (
+	keyAddr keyKind = iota
+	keyInt
+	keyUint
+	keyCid
+)
+
+func (k keyKind) String() string {
+	switch k {
+	case keyAddr:
+		return "address"
+	case keyInt:
+		return "varint"
+	case keyUint:
+		return "uvarint"
+	case keyCid:
+		return "cid"
+	default:
+		return fmt.Sprintf("keyKind(%d)", int(k))
+	}
+}
+
+// parseKey reads a HAMT key the way it is written in paths. The @H prefixes
+// the node resolves with are accepted too.
+func (k keyKind) parseKey(seg string) (abi.Keyer, error) {
+	if strings.HasPrefix(seg, "@") {
+		if i := strings.Index(seg, ":"); i >= 0 {
+			seg = seg[i+1:]
+		}
+	}
+
+	switch k {
+	case keyAddr:
+		a, err := address.NewFromString(seg)
+		if err != nil {
+			return nil, xerrors.Errorf("expected an address key: %w", err)
+		}
+		return abi.AddrKey(a), nil
+	case keyInt:
+		i, err := strconv.ParseInt(seg, 10, 64)
+		if err != nil {
+			return nil, xerrors.Errorf("expected an integer key: %w", err)
+		}
+		return abi.IntKey(i), nil
+	case keyUint:
+		i, err := strconv.ParseUint(seg, 10, 64)
+		if err != nil {
+			return nil, xerrors.Errorf("expected an unsigned integer key: %w", err)
+		}
+		return abi.UIntKey(i), nil
+	case keyCid:
+		c, err := cid.Decode(seg)
+		if err != nil {
+			return nil, xerrors.Errorf("expected a cid key: %w", err)
+		}
+		return abi.CidKey(c), nil
+	default:
+		return nil, xerrors.Errorf("unknown key kind %d", k)
+	}
+}
+
+// formatKey writes a raw HAMT key the way it is written in paths
+func (k keyKind) formatKey(raw string) (string, error) {
+	switch k {
+	case keyAddr:
+		a, err := address.NewFromBytes([]byte(raw))
+		if err != nil {
+			return "", err
+		}
+		return a.String(), nil
+	case keyInt:
+		i, err := abi.ParseIntKey(raw)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(i, 10), nil
+	case keyUint:
+		i, err := abi.ParseUIntKey(raw)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatUint(i, 10), nil
+	case keyCid:
+		c, err := cid.Cast([]byte(raw))
+		if err != nil {
+			return "", err
+		}
+		return c.String(), nil
+	default:
+		return "", xerrors.Errorf("unknown key kind %d", k)
+	}
+}
+
+// parseIndex reads an AMT index, with or without the @A: prefix
+func parseIndex(seg string) (uint64, error) {
+	seg = strings.TrimPrefix(seg, "@A:")
+	i, err := strconv.ParseUint(seg, 10, 64)
+	if err != nil {
+		return 0, xerrors.Errorf("expected an integer index: %w", err)
+	}
+	return i, nil
+}
+
+type linkKind int
+
+const (
+	linkObject linkKind = iota
+	linkHAMT
+	linkAMT
+)
+
+// pathLink is what a cid in actor state links to
+type pathLink struct {
+	kind     linkKind
+	key      keyKind
+	bitwidth int
+	// elem is the type of the linked object, or of the HAMT and AMT
+	// elements. It is nil for HAMTs used as sets.
+	elem reflect.Type
+}
+
+func (l pathLink) String() string {
+	elem := "set"
+	if l.elem != nil {
+		elem = l.elem.String()
+	}
+	switch l.kind {
+	case linkHAMT:
+		return fmt.Sprintf("HAMT of %s keyed by %s", elem, l.key)
+	case linkAMT:
+		return fmt.Sprintf("AMT of %s", elem)
+	default:
+		return elem
+	}
+}
+
+func elemType(proto interface{}) reflect.Type {
+	if proto == nil {
+		return nil
+	}
+	return reflect.TypeOf(proto).Elem()
+}
+
+func linkTo(proto interface{}) pathLink {
+	return pathLink{kind: linkObject, elem: elemType(proto)}
+}
+
+func hamtOf(key keyKind, bitwidth int, proto interface{}) pathLink {
+	return pathLink{kind: linkHAMT, key: key, bitwidth: bitwidth, elem: elemType(proto)}
+}
+
+func amtOf(proto interface{}) pathLink {
+	return pathLink{kind: linkAMT, elem: elemType(proto)}
+}
+
+// actorPathTypes are the types of an actors version which actor state links
+// to. The prototypes are typed nil pointers.
+type actorPathTypes struct {
+	minerInfo, vestingFunds, deadlines, deadline, partition, expirationSet interface{}
+	sectorOnChainInfo, sectorPreCommitOnChainInfo                          interface{}
+
+	dealProposal, dealState interface{}
+	// pendingProposal is nil when the pending proposals are a set
+	pendingProposal interface{}
+
+	claim, transaction, laneState interface{}
+
+	hamtBitwidth, balanceTableBitwidth int
+}
+
+// links returns the links of the actor states, keyed by the Go type and field
+// of the cid, with [] for the elements of arrays
+func (t actorPathTypes) links() map[string]pathLink {
+	bw, balBw := t.hamtBitwidth, t.balanceTableBitwidth
+	return map[string]pathLink{
+		"miner.State.Info":                       linkTo(t.minerInfo),
+		"miner.State.VestingFunds":               linkTo(t.vestingFunds),
+		"miner.State.AllocatedSectors":           linkTo((*bitfield.BitField)(nil)),
+		"miner.State.PreCommittedSectors":        hamtOf(keyUint, bw, t.sectorPreCommitOnChainInfo),
+		"miner.State.PreCommittedSectorsExpiry":  amtOf((*bitfield.BitField)(nil)),
+		"miner.State.PreCommittedSectorsCleanUp": amtOf((*bitfield.BitField)(nil)),
+		"miner.State.Sectors":                    amtOf(t.sectorOnChainInfo),
+		"miner.State.Deadlines":                  linkTo(t.deadlines),
+		"miner.Deadlines.Due[]":                  linkTo(t.deadline),
+		"miner.Deadline.Partitions":              amtOf(t.partition),
+		"miner.Deadline.PartitionsSnapshot":      amtOf(t.partition),
+		"miner.Deadline.ExpirationsEpochs":       amtOf((*bitfield.BitField)(nil)),
+		"miner.Partition.ExpirationsEpochs":      amtOf(t.expirationSet),
+		"miner.Partition.EarlyTerminated":        amtOf((*bitfield.BitField)(nil)),
+
+		"market.State.Proposals":        amtOf(t.dealProposal),
+		"market.State.States":           amtOf(t.dealState),
+		"market.State.PendingProposals": hamtOf(keyCid, bw, t.pendingProposal),
+		"market.State.EscrowTable":      hamtOf(keyAddr, balBw, (*abi.TokenAmount)(nil)),
+		"market.State.LockedTable":      hamtOf(keyAddr, balBw, (*abi.TokenAmount)(nil)),
+
+		"power.State.Claims": hamtOf(keyAddr, bw, t.claim),
+
+		"init.State.AddressMap": hamtOf(keyAddr, bw, (*cbg.CborInt)(nil)),
+
+		"verifreg.State.Verifiers":       hamtOf(keyAddr, bw, (*abi.StoragePower)(nil)),
+		"verifreg.State.VerifiedClients": hamtOf(keyAddr, bw, (*abi.StoragePower)(nil)),
+
+		"multisig.State.PendingTxns": hamtOf(keyInt, bw, t.transaction),
+
+		"paych.State.LaneStates": amtOf(t.laneState),
+	}
+}
+
+// chainPathLinks are the links of actor states, by actors version
+var chainPathLinks = map[actors.Version]map[string]pathLink{
+	actors.Version0: actorPathTypes{
+		minerInfo: (*miner0.MinerInfo)(nil), vestingFunds: (*miner0.VestingFunds)(nil),
+		deadlines: (*miner0.Deadlines)(nil), deadline: (*miner0.Deadline)(nil),
+		partition: (*miner0.Partition)(nil), expirationSet: (*miner0.ExpirationSet)(nil),
+		sectorOnChainInfo:          (*miner0.SectorOnChainInfo)(nil),
+		sectorPreCommitOnChainInfo: (*miner0.SectorPreCommitOnChainInfo)(nil),
+		dealProposal:               (*market0.DealProposal)(nil), dealState: (*market0.DealState)(nil),
+		pendingProposal: (*market0.DealProposal)(nil),
+		claim:           (*power0.Claim)(nil), transaction: (*multisig0.Transaction)(nil), laneState: (*paych0.LaneState)(nil),
+	}.links(),
+	actors.Version2: actorPathTypes{
+		minerInfo: (*miner2.MinerInfo)(nil), vestingFunds: (*miner2.VestingFunds)(nil),
+		deadlines: (*miner2.Deadlines)(nil), deadline: (*miner2.Deadline)(nil),
+		partition: (*miner2.Partition)(nil), expirationSet: (*miner2.ExpirationSet)(nil),
+		sectorOnChainInfo:          (*miner2.SectorOnChainInfo)(nil),
+		sectorPreCommitOnChainInfo: (*miner2.SectorPreCommitOnChainInfo)(nil),
+		dealProposal:               (*market2.DealProposal)(nil), dealState: (*market2.DealState)(nil),
+		pendingProposal: (*market2.DealProposal)(nil),
+		claim:           (*power2.Claim)(nil), transaction: (*multisig2.Transaction)(nil), laneState: (*paych2.LaneState)(nil),
+	}.links(),
+	actors.Version3: actorPathTypes{
+		minerInfo: (*miner3.MinerInfo)(nil), vestingFunds: (*miner3.VestingFunds)(nil),
+		deadlines: (*miner3.Deadlines)(nil), deadline: (*miner3.Deadline)(nil),
+		partition: (*miner3.Partition)(nil), expirationSet: (*miner3.ExpirationSet)(nil),
+		sectorOnChainInfo:          (*miner3.SectorOnChainInfo)(nil),
+		sectorPreCommitOnChainInfo: (*miner3.SectorPreCommitOnChainInfo)(nil),
+		dealProposal:               (*market3.DealProposal)(nil), dealState: (*market3.DealState)(nil),
+		claim: (*power3.Claim)(nil), transaction: (*multisig3.Transaction)(nil), laneState: (*paych3.LaneState)(nil),
+		hamtBitwidth: builtin3.DefaultHamtBitwidth, balanceTableBitwidth: adt3.BalanceTableBitwidth,
+	}.links(),
+	actors.Version4: actorPathTypes{
+		minerInfo: (*miner4.MinerInfo)(nil), vestingFunds: (*miner4.VestingFunds)(nil),
+		deadlines: (*miner4.Deadlines)(nil), deadline: (*miner4.Deadline)(nil),
+		partition: (*miner4.Partition)(nil), expirationSet: (*miner4.ExpirationSet)(nil),
+		sectorOnChainInfo:          (*miner4.SectorOnChainInfo)(nil),
+		sectorPreCommitOnChainInfo: (*miner4.SectorPreCommitOnChainInfo)(nil),
+		dealProposal:               (*market4.DealProposal)(nil), dealState: (*market4.DealState)(nil),
+		claim: (*power4.Claim)(nil), transaction: (*multisig4.Transaction)(nil), laneState: (*paych4.LaneState)(nil),
+		hamtBitwidth: builtin4.DefaultHamtBitwidth, balanceTableBitwidth: adt4.BalanceTableBitwidth,
+	}.links(),
+	actors.Version5: actorPathTypes{
+		minerInfo: (*miner5.MinerInfo)(nil), vestingFunds: (*miner5.VestingFunds)(nil),
+		deadlines: (*miner5.Deadlines)(nil), deadline: (*miner5.Deadline)(nil),
+		partition: (*miner5.Partition)(nil), expirationSet: (*miner5.ExpirationSet)(nil),
+		sectorOnChainInfo:          (*miner5.SectorOnChainInfo)(nil),
+		sectorPreCommitOnChainInfo: (*miner5.SectorPreCommitOnChainInfo)(nil),
+		dealProposal:               (*market5.DealProposal)(nil), dealState: (*market5.DealState)(nil),
+		claim: (*power5.Claim)(nil), transaction: (*multisig5.Transaction)(nil), laneState: (*paych5.LaneState)(nil),
+		hamtBitwidth: builtin5.DefaultHamtBitwidth, balanceTableBitwidth: adt5.BalanceTableBitwidth,
+	}.links(),
+}
+
+// actorsVersionOf returns the actors version of a builtin actor code
+func actorsVersionOf(code cid.Cid) (actors.Version, bool) {
+	switch {
+	case builtin0.IsBuiltinActor(code):
+		return actors.Version0, true
+	case builtin2.IsBuiltinActor(code):
+		return actors.Version2, true
+	case builtin3.IsBuiltinActor(code):
+		return actors.Version3, true
+	case builtin4.IsBuiltinActor(code):
+		return actors.Version4, true
+	case builtin5.IsBuiltinActor(code):
+		return actors.Version5, true
+	default:
+		return 0, false
+	}
+}
+
+// keyPage collects one page of the keys under a node, counting them all
+type keyPage struct {
+	offset, limit int
+
+	keys  []string
+	total int
+}
+
+func (p *keyPage) wants() bool {
+	return p.total >= p.offset && (p.limit <= 0 || len(p.keys) < p.limit)
+}
+
+func (p *keyPage) add(key func() (string, error)) error {
+	if p.wants() {
+		k, err := key()
+		if err != nil {
+			return err
+		}
+		p.keys = append(p.keys, k)
+	}
+	p.total++
+	return nil
+}
+
+func (p *keyPage) addString(key string) {
+	_ = p.add(func() (string, error) { return key, nil })
+}
+
+// pathNode is a node reached while walking a state path
+type pathNode interface {
+	// kind describes the node, for errors
+	kind() string
+	child(ctx context.Context, seg string) (pathNode, error)
+	list(ctx context.Context, page *keyPage) error
+	value(ctx context.Context) (interface{}, error)
+}
+
+// statePathWalker walks state paths, knowing the types actor state links to
+type statePathWalker struct {
+	api   v0api.FullNode
+	store adt.Store
+}
+
+func (w *statePathWalker) loadMap(ver actors.Version, root cid.Cid, bitwidth int) (adt.Map, error) {
+	switch ver {
+	case actors.Version0:
+		return adt0.AsMap(w.store, root)
+	case actors.Version2:
+		return adt2.AsMap(w.store, root)
+	case actors.Version3:
+		return adt3.AsMap(w.store, root, bitwidth)
+	case actors.Version4:
+		return adt4.AsMap(w.store, root, bitwidth)
+	case actors.Version5:
+		return adt5.AsMap(w.store, root, bitwidth)
+	default:
+		return nil, xerrors.Errorf("unknown actors version %d", ver)
+	}
+}
+
+func (w *statePathWalker) loadArray(ctx context.Context, ver actors.Version, root cid.Cid) (adt.Array, error) {
+	switch ver {
+	case actors.Version0:
+		return adt0.AsArray(w.store, root)
+	case actors.Version2:
+		return adt2.AsArray(w.store, root)
+	}
+
+	// from v3 the bitwidth, which has to be given, is in the root
+	raw, err := w.api.ChainReadObj(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	bitwidth, err := amtBitwidth(raw)
+	if err != nil {
+		return nil, xerrors.Errorf("reading AMT root: %w", err)
+	}
+
+	switch ver {
+	case actors.Version3:
+		return adt3.AsArray(w.store, root, bitwidth)
+	case actors.Version4:
+		return adt4.AsArray(w.store, root, bitwidth)
+	case actors.Version5:
+		return adt5.AsArray(w.store, root, bitwidth)
+	default:
+		return nil, xerrors.Errorf("unknown actors version %d", ver)
+	}
+}
+
+// amtBitwidth reads the bitwidth of a v3 AMT from its root, the first field
+// of the root tuple
+func amtBitwidth(raw []byte) (int, error) {
+	br := bytes.NewReader(raw)
+	maj, n, err := cbg.CborReadHeader(br)
+	if err != nil {
+		return 0, err
+	}
+	if maj != cbg.MajArray || n == 0 {
+		return 0, xerrors.Errorf("not an AMT root")
+	}
+	maj, bw, err := cbg.CborReadHeader(br)
+	if err != nil {
+		return 0, err
+	}
+	if maj != cbg.MajUnsignedInt {
+		return 0, xerrors.Errorf("not an AMT root")
+	}
+	return int(bw), nil
+}
+
+var (
+	cidType           = reflect.TypeOf(cid.Cid{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// wrap returns the node of a value found in the state of an actors version.
+// linkKey names where the value is, to find what it links to if it is a cid.
+func (w *statePathWalker) wrap(ctx context.Context, ver actors.Version, linkKey string, v reflect.Value) (pathNode, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return &valueNode{}, nil
+		}
+		v = v.Elem()
+	}
+
+	t := v.Type()
+	switch {
+	case t == cidType:
+		c := v.Interface().(cid.Cid)
+		if link, ok := chainPathLinks[ver][linkKey]; ok && c.Defined() {
+			return w.follow(ctx, ver, link, c)
+		}
+		return &rawNode{w: w, root: c}, nil
+	case t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType):
+		return &valueNode{v: v.Interface()}, nil
+	case t.Kind() == reflect.Struct:
+		return &structNode{w: w, ver: ver, name: t.String(), v: v}, nil
+	case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() != reflect.Uint8:
+		return &structNode{w: w, ver: ver, name: linkKey, v: v}, nil
+	default:
+		return &valueNode{v: v.Interface()}, nil
+	}
+}
+
+// follow returns the node a cid links to
+func (w *statePathWalker) follow(ctx context.Context, ver actors.Version, link pathLink, c cid.Cid) (pathNode, error) {
+	switch link.kind {
+	case linkHAMT:
+		m, err := w.loadMap(ver, c, link.bitwidth)
+		if err != nil {
+			return nil, xerrors.Errorf("loading %s: %w", link, err)
+		}
+		return &hamtNode{w: w, ver: ver, link: link, m: m}, nil
+	case linkAMT:
+		a, err := w.loadArray(ctx, ver, c)
+		if err != nil {
+			return nil, xerrors.Errorf("loading %s: %w", link, err)
+		}
+		return &amtNode{w: w, ver: ver, link: link, a: a}, nil
+	default:
+		obj := reflect.New(link.elem)
+		if err := w.store.Get(ctx, c, obj.Interface()); err != nil {
+			return nil, xerrors.Errorf("loading %s: %w", link, err)
+		}
+		return w.wrap(ctx, ver, "", obj)
+	}
+}
+
+// stateTreeNode is the state tree, keyed by actor address
+type stateTreeNode struct {
+	w    *statePathWalker
+	root cid.Cid
+	tree *state.StateTree
+}
+
+func (n *stateTreeNode) kind() string {
+	return fmt.Sprintf("state tree (version %d)", n.tree.Version())
+}
+
+func (n *stateTreeNode) child(ctx context.Context, seg string) (pathNode, error) {
+	a, err := keyAddr.parseKey(seg)
+	if err != nil {
+		return nil, err
+	}
+	addr := address.Address(a.(abi.AddrKey))
+	act, err := n.tree.GetActor(addr)
+	if err != nil {
+		return nil, xerrors.Errorf("getting actor %s: %w", addr, err)
+	}
+	return &actorNode{w: n.w, addr: addr, act: act}, nil
+}
+
+func (n *stateTreeNode) list(ctx context.Context, page *keyPage) error {
+	return n.tree.ForEach(func(addr address.Address, _ *types.Actor) error {
+		page.addString(addr.String())
+		return nil
+	})
+}
+
+func (n *stateTreeNode) value(ctx context.Context) (interface{}, error) {
+	return (&rawNode{w: n.w, root: n.root}).value(ctx)
+}
+
+// actorNode is an actor, with its fields, and the fields of its state
+type actorNode struct {
+	w    *statePathWalker
+	addr address.Address
+	act  *types.Actor
+}
+
+func (n *actorNode) kind() string {
+	return fmt.Sprintf("actor %s (%s)", n.addr, builtin.ActorNameByCode(n.act.Code))
+}
+
+func (n *actorNode) state(ctx context.Context) (pathNode, error) {
+	ver, ok := actorsVersionOf(n.act.Code)
+	if !ok {
+		return &rawNode{w: n.w, root: n.act.Head}, nil
+	}
+
+	raw, err := n.w.api.ChainReadObj(ctx, n.act.Head)
+	if err != nil {
+		return nil, xerrors.Errorf("reading actor state: %w", err)
+	}
+	st, err := vm.DumpActorState(n.act, raw)
+	if err != nil {
+		return nil, err
+	}
+	if st == nil {
+		// account actors have no registered state type
+		return &rawNode{w: n.w, root: n.act.Head}, nil
+	}
+	return n.w.wrap(ctx, ver, "", reflect.ValueOf(st))
+}
+
+func (n *actorNode) child(ctx context.Context, seg string) (pathNode, error) {
+	// the numbers are the fields of the actor tuple, as IPLD paths have them
+	switch strings.ToLower(seg) {
+	case "code", "0":
+		return &valueNode{v: n.act.Code}, nil
+	case "head", "1":
+		return &rawNode{w: n.w, root: n.act.Head}, nil
+	case "nonce", "2":
+		return &valueNode{v: n.act.Nonce}, nil
+	case "balance", "3":
+		return &valueNode{v: n.act.Balance}, nil
+	case "@state", "state":
+		return n.state(ctx)
+	}
+
+	st, err := n.state(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return st.child(ctx, seg)
+}
+
+func (n *actorNode) list(ctx context.Context, page *keyPage) error {
+	for _, k := range []string{"code", "head", "nonce", "balance", "state"} {
+		page.addString(k)
+	}
+	return nil
+}
+
+func (n *actorNode) value(ctx context.Context) (interface{}, error) {
+	return n.act, nil
+}
+
+// structNode is a struct or an array in actor state
+type structNode struct {
+	w    *statePathWalker
+	ver  actors.Version
+	name string
+	v    reflect.Value
+}
+
+func (n *structNode) kind() string {
+	return fmt.Sprintf("%s (actors v%d)", n.v.Type(), n.ver)
+}
+
+func (n *structNode) child(ctx context.Context, seg string) (pathNode, error) {
+	if n.v.Kind() != reflect.Struct {
+		i, err := strconv.Atoi(seg)
+		if err != nil || i < 0 || i >= n.v.Len() {
+			return nil, xerrors.Errorf("expected an index under %d", n.v.Len())
+		}
+		return n.w.wrap(ctx, n.ver, n.name+"[]", n.v.Index(i))
+	}
+
+	t := n.v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.PkgPath == "" && strings.EqualFold(f.Name, seg) {
+			return n.w.wrap(ctx, n.ver, n.name+"."+f.Name, n.v.Field(i))
+		}
+	}
+	return nil, xerrors.Errorf("no field %q", seg)
+}
+
+func (n *structNode) list(ctx context.Context, page *keyPage) error {
+	if n.v.Kind() != reflect.Struct {
+		for i := 0; i < n.v.Len(); i++ {
+			page.addString(strconv.Itoa(i))
+		}
+		return nil
+	}
+
+	t := n.v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.PkgPath == "" {
+			page.addString(f.Name)
+		}
+	}
+	return nil
+}
+
+func (n *structNode) value(ctx context.Context) (interface{}, error) {
+	return n.v.Interface(), nil
+}
+
+// hamtNode is a HAMT in actor state
+type hamtNode struct {
+	w    *statePathWalker
+	ver  actors.Version
+	link pathLink
+	m    adt.Map
+}
+
+func (n *hamtNode) kind() string {
+	return fmt.Sprintf("%s (actors v%d)", n.link, n.ver)
+}
+
+func (n *hamtNode) child(ctx context.Context, seg string) (pathNode, error) {
+	k, err := n.link.key.parseKey(seg)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.link.elem == nil {
+		found, err := n.m.Get(k, new(cbg.Deferred))
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, xerrors.Errorf("%s not in the set", seg)
+		}
+		return &valueNode{v: true}, nil
+	}
+
+	elem := reflect.New(n.link.elem)
+	found, err := n.m.Get(k, elem.Interface().(cbg.CBORUnmarshaler))
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, xerrors.Errorf("key %s not found", seg)
+	}
+	return n.w.wrap(ctx, n.ver, "", elem)
+}
+
+func (n *hamtNode) list(ctx context.Context, page *keyPage) error {
+	return n.m.ForEach(nil, func(k string) error {
+		return page.add(func() (string, error) {
+			return n.link.key.formatKey(k)
+		})
+	})
+}
+
+func (n *hamtNode) value(ctx context.Context) (interface{}, error) {
+	return n.m.Root()
+}
+
+// amtNode is an AMT in actor state
+type amtNode struct {
+	w    *statePathWalker
+	ver  actors.Version
+	link pathLink
+	a    adt.Array
+}
+
+func (n *amtNode) kind() string {
+	return fmt.Sprintf("%s (actors v%d)", n.link, n.ver)
+}
+
+func (n *amtNode) child(ctx context.Context, seg string) (pathNode, error) {
+	i, err := parseIndex(seg)
+	if err != nil {
+		return nil, err
+	}
+
+	elem := reflect.New(n.link.elem)
+	found, err := n.a.Get(i, elem.Interface().(cbg.CBORUnmarshaler))
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, xerrors.Errorf("index %d not found", i)
+	}
+	return n.w.wrap(ctx, n.ver, "", elem)
+}
+
+func (n *amtNode) list(ctx context.Context, page *keyPage) error {
+	return n.a.ForEach(nil, func(i int64) error {
+		page.addString(strconv.FormatInt(i, 10))
+		return nil
+	})
+}
+
+func (n *amtNode) value(ctx context.Context) (interface{}, error) {
+	return n.a.Root()
+}
+
+// valueNode is a value which can't be walked into
+type valueNode struct {
+	v interface{}
+}
+
+func (n *valueNode) kind() string {
+	if n.v == nil {
+		return "null"
+	}
+	return fmt.Sprintf("value of type %T", n.v)
+}
+
+func (n *valueNode) child(ctx context.Context, seg string) (pathNode, error) {
+	return nil, xerrors.Errorf("a value has nothing under it")
+}
+
+func (n *valueNode) list(ctx context.Context, page *keyPage) error {
+	return xerrors.Errorf("a value has no keys")
+}
+
+func (n *valueNode) value(ctx context.Context) (interface{}, error) {
+	return n.v, nil
+}
+
+// rawNode is IPLD whose type isn't known, walked the way the node resolves
+// IPLD paths
+type rawNode struct {
+	w    *statePathWalker
+	root cid.Cid
+	path []string
+}
+
+func (n *rawNode) kind() string {
+	return fmt.Sprintf("untyped IPLD under %s", n.root)
+}
+
+func (n *rawNode) ipfsPath() string {
+	return "/ipfs/" + n.root.String() + strings.Join(append([]string{""}, n.path...), "/")
+}
+
+func (n *rawNode) child(ctx context.Context, seg string) (pathNode, error) {
+	next := &rawNode{w: n.w, root: n.root, path: append(append([]string{}, n.path...), seg)}
+	if _, err := n.w.api.ChainGetNode(ctx, next.ipfsPath()); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+func (n *rawNode) list(ctx context.Context, page *keyPage) error {
+	return xerrors.Errorf("the keys of untyped IPLD can't be listed")
+}
+
+func (n *rawNode) value(ctx context.Context) (interface{}, error) {
+	obj, err := n.w.api.ChainGetNode(ctx, n.ipfsPath())
+	if err != nil {
+		return nil, err
+	}
+	return obj.Obj, nil
+}
+
+// splitStatePath returns the segments of a /pstate or /state path, and false
+// for other paths
+func splitStatePath(p string) ([]string, bool) {
+	var rest string
+	switch {
+	case p == "/pstate" || strings.HasPrefix(p, "/pstate/"):
+		rest = p[len("/pstate"):]
+	case p == "/state" || strings.HasPrefix(p, "/state/"):
+		rest = p[len("/state"):]
+	default:
+		return nil, false
+	}
+
+	var segs []string
+	for _, s := range strings.Split(rest, "/") {
+		if s != "" {
+			segs = append(segs, s)
+		}
+	}
+	return segs, true
+}
+
+// chainGetStatePath walks a state path from the parent state of the chosen
+// tipset, printing the typed value it leads to, or with --list the keys under
+// it
+func chainGetStatePath(ctx context.Context, cctx *cli.Context, api v0api.FullNode, p string, segs []string) error {
+	afmt := NewAppFmt(cctx.App)
+
+	ts, err := LoadTipSet(ctx, cctx, api)
+	if err != nil {
+		return err
+	}
+	if ts == nil {
+		ts, err = api.ChainHead(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	root := ts.ParentState()
+	if cctx.Bool("verbose") {
+		afmt.Println("/ipfs/" + root.String() + strings.Join(append([]string{""}, segs...), "/"))
+	}
+
+	w := &statePathWalker{api: api, store: adt.WrapStore(ctx, cbor.NewCborStore(blockstore.NewAPIBlockstore(api)))}
+	tree, err := state.LoadStateTree(w.store, root)
+	if err != nil {
+		return xerrors.Errorf("loading state tree %s: %w", root, err)
+	}
+
+	var n pathNode = &stateTreeNode{w: w, root: root, tree: tree}
+	for i, seg := range segs {
+		next, err := n.child(ctx, seg)
+		if err != nil {
+			return xerrors.Errorf("path %s, segment %d (%q), at %s: %w", p, i+1, seg, n.kind(), err)
+		}
+		n = next
+	}
+
+	if cctx.Bool("list") {
+		page := &keyPage{offset: cctx.Int("offset"), limit: cctx.Int("limit")}
+		if err := n.list(ctx, page); err != nil {
+			return xerrors.Errorf("listing %s, at %s: %w", p, n.kind(), err)
+		}
+		for _, k := range page.keys {
+			afmt.Println(k)
+		}
+		if len(page.keys) == 0 {
+			fmt.Fprintf(cctx.App.ErrWriter, "no keys from %d, of %d in %s\n", page.offset, page.total, n.kind())
+		} else {
+			fmt.Fprintf(cctx.App.ErrWriter, "keys %d-%d of %d in %s\n", page.offset+1, page.offset+len(page.keys), page.total, n.kind())
+		}
+		return nil
+	}
+
+	v, err := n.value(ctx)
+	if err != nil {
+		return xerrors.Errorf("reading %s, at %s: %w", p, n.kind(), err)
+	}
+	b, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return err
+	}
+	afmt.Println(string(b))
+	return nil
+}
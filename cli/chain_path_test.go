@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	"github.com/stretchr/testify/require"
+
+	power5 "github.com/filecoin-project/specs-actors/v5/actors/builtin/power"
+	adt5 "github.com/filecoin-project/specs-actors/v5/actors/util/adt"
+
+	"github.com/filecoin-project/lotus/chain/actors"
+	"github.com/filecoin-project/lotus/chain/actors/adt"
+)
+
+func TestPathKeys(t *testing.T) {
+	for _, tc := range []struct {
+		kind keyKind
+		seg  string
+		out  string
+	}{
+		{keyAddr, "@Ha:w01234", "w01234"},
+		{keyAddr, "w01234", "w01234"},
+		{keyInt, "@Hi:-5", "-5"},
+		{keyUint, "17", "17"},
+	} {
+		k, err := tc.kind.parseKey(tc.seg)
+		require.NoError(t, err)
+		out, err := tc.kind.formatKey(k.Key())
+		require.NoError(t, err)
+		require.Equal(t, tc.out, out)
+	}
+
+	_, err := keyUint.parseKey("w01234")
+	require.Error(t, err)
+
+	i, err := parseIndex("@A:5")
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), i)
+}
+
+func TestPathHAMT(t *testing.T) {
+	ctx := context.Background()
+	store := adt.WrapStore(ctx, cbor.NewMemCborStore())
+
+	claims, err := adt5.MakeEmptyMap(store, 5)
+	require.NoError(t, err)
+	for n := uint64(1000); n < 1003; n++ {
+		a, err := address.NewIDAddress(n)
+		require.NoError(t, err)
+		require.NoError(t, claims.Put(abi.AddrKey(a), &power5.Claim{RawBytePower: big.NewInt(int64(n))}))
+	}
+	root, err := claims.Root()
+	require.NoError(t, err)
+
+	w := &statePathWalker{store: store}
+	n, err := w.follow(ctx, actors.Version5, chainPathLinks[actors.Version5]["power.State.Claims"], root)
+	require.NoError(t, err)
+	require.Contains(t, n.kind(), "HAMT of power.Claim keyed by address")
+
+	page := &keyPage{offset: 1, limit: 1}
+	require.NoError(t, n.list(ctx, page))
+	require.Equal(t, 3, page.total)
+	require.Len(t, page.keys, 1)
+
+	claim, err := n.child(ctx, "@Ha:w01001")
+	require.NoError(t, err)
+	power, err := claim.child(ctx, "rawbytepower")
+	require.NoError(t, err)
+	v, err := power.value(ctx)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1001), v)
+
+	_, err = n.child(ctx, "w01005")
+	require.EqualError(t, err, "key w01005 not found")
+	_, err = claim.child(ctx, "sectors")
+	require.EqualError(t, err, `no field "sectors"`)
+}
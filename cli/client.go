@@ -85,6 +85,7 @@ var clientCmd = &cli.Command{
 		WithCategory("storage", clientListAsksCmd),
 		WithCategory("storage", clientDealStatsCmd),
 		WithCategory("storage", clientInspectDealCmd),
+		WithCategory("storage", clientDealHealthCmd),
 		WithCategory("data", clientImportCmd),
 		WithCategory("data", clientDropCmd),
 		WithCategory("data", clientLocalCmd),
@@ -92,6 +93,7 @@ var clientCmd = &cli.Command{
 		WithCategory("retrieval", clientFindCmd),
 		WithCategory("retrieval", clientRetrieveCmd),
 		WithCategory("retrieval", clientCancelRetrievalDealCmd),
+		WithCategory("retrieval", clientRetrievalStatsCmd),
 		WithCategory("util", clientCommPCmd),
 		WithCategory("util", clientCarGenCmd),
 		WithCategory("util", clientBalancesCmd),
@@ -1040,6 +1042,15 @@ var clientRetrieveCmd = &cli.Command{
 		&cli.BoolFlag{
 			Name: "allow-local",
 		},
+		&cli.BoolFlag{
+			Name:  "try-all-providers",
+			Usage: "when a retrieval fails, retry with the other providers known to have the data, cheapest first",
+		},
+		&cli.DurationFlag{
+			Name:  "attempt-timeout",
+			Usage: "with --try-all-providers, give up on a provider after this long",
+			Value: 30 * time.Minute,
+		},
 	},
 	Action: func(cctx *cli.Context) error {
 		if cctx.NArg() != 2 {
@@ -1099,6 +1110,45 @@ var clientRetrieveCmd = &cli.Command{
 			}
 		}
 
+		maxPrice := types.MustParseFIL(DefaultMaxRetrievePrice)
+		if cctx.String("maxPrice") != "" {
+			maxPrice, err = types.ParseFIL(cctx.String("maxPrice"))
+			if err != nil {
+				return xerrors.Errorf("parsing maxPrice: %w", err)
+			}
+		}
+
+		ref := &lapi.FileRef{
+			Path:  cctx.Args().Get(1),
+			IsCAR: cctx.Bool("car"),
+		}
+
+		if order == nil && cctx.Bool("try-all-providers") {
+			var miners []address.Address
+			if minerStrAddr := cctx.String("miner"); minerStrAddr != "" {
+				minerAddr, err := address.NewFromString(minerStrAddr)
+				if err != nil {
+					return err
+				}
+				miners = append(miners, minerAddr)
+			}
+
+			candidates, err := findRetrievalCandidates(ctx, afmt, fapi, file, pieceCid, miners, maxPrice)
+			if err != nil {
+				return err
+			}
+			if len(candidates) == 0 {
+				return xerrors.Errorf("no provider offering the data within maxPrice %s", maxPrice)
+			}
+
+			served, err := retrieveFromCandidates(ctx, afmt, fapi, candidates, payer, ref, cctx.Duration("attempt-timeout"))
+			if err != nil {
+				return err
+			}
+			afmt.Printf("Success: retrieved from %s (peer %s)\n", served.Miner, served.MinerPeer.ID)
+			return nil
+		}
+
 		if order == nil {
 			var offer api.QueryOffer
 			minerStrAddr := cctx.String("miner")
@@ -1143,15 +1193,6 @@ var clientRetrieveCmd = &cli.Command{
 				return fmt.Errorf("The received offer errored: %s", offer.Err)
 			}
 
-			maxPrice := types.MustParseFIL(DefaultMaxRetrievePrice)
-
-			if cctx.String("maxPrice") != "" {
-				maxPrice, err = types.ParseFIL(cctx.String("maxPrice"))
-				if err != nil {
-					return xerrors.Errorf("parsing maxPrice: %w", err)
-				}
-			}
-
 			if offer.MinPrice.GreaterThan(big.Int(maxPrice)) {
 				return xerrors.Errorf("failed to find offer satisfying maxPrice: %s", maxPrice)
 			}
@@ -1159,38 +1200,12 @@ var clientRetrieveCmd = &cli.Command{
 			o := offer.Order(payer)
 			order = &o
 		}
-		ref := &lapi.FileRef{
-			Path:  cctx.Args().Get(1),
-			IsCAR: cctx.Bool("car"),
-		}
-
-		updates, err := fapi.ClientRetrieveWithEvents(ctx, *order, ref)
-		if err != nil {
-			return xerrors.Errorf("error setting up retrieval: %w", err)
-		}
-
-		for {
-			select {
-			case evt, ok := <-updates:
-				if ok {
-					afmt.Printf("> Recv: %s, Paid %s, %s (%s)\n",
-						types.SizeStr(types.NewInt(evt.BytesReceived)),
-						types.FIL(evt.FundsSpent),
-						retrievalmarket.ClientEvents[evt.Event],
-						retrievalmarket.DealStatuses[evt.Status],
-					)
-				} else {
-					afmt.Println("Success")
-					return nil
-				}
 
-				if evt.Err != "" {
-					return xerrors.Errorf("retrieval failed: %s", evt.Err)
-				}
-			case <-ctx.Done():
-				return xerrors.Errorf("retrieval timed out")
-			}
+		if err := retrieveOrder(ctx, afmt, fapi, *order, ref); err != nil {
+			return err
 		}
+		afmt.Println("Success")
+		return nil
 	},
 }
 
@@ -1694,7 +1709,7 @@ func outputStorageDeals(ctx context.Context, out io.Writer, full v0api.FullNode,
 
 	if verbose {
 		w := tabwriter.NewWriter(out, 2, 4, 2, ' ', 0)
-		fmt.Fprintf(w, "Created\tDealCid\tDealId\tProvider\tState\tOn Chain?\tSlashed?\tPieceCID\tSize\tPrice\tDuration\tTransferChannelID\tTransferStatus\tVerified\tMessage\n")
+		fmt.Fprintf(w, "Created\tDealCid\tDealId\tProvider\tState\tOn Chain?\tSlashed?\tPieceCID\tSize\tPrice\tDuration\tTransferChannelID\tTransferStatus\tVerified\tSealing\tMessage\n")
 		for _, d := range deals {
 			onChain := "N"
 			if d.OnChainDealState.SectorStartEpoch != -1 {
@@ -1722,7 +1737,11 @@ func outputStorageDeals(ctx context.Context, out io.Writer, full v0api.FullNode,
 				//	transferPct = fmt.Sprintf("%d%%", pct)
 				//}
 			}
-			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\t%v\t%s\n",
+			sealing := ""
+			if sealingDealStates[d.LocalDeal.State] {
+				sealing = dealSealingString(ctx, full, d.LocalDeal.ProposalCid)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\t%v\t%s\t%s\n",
 				d.LocalDeal.CreationTime.Format(time.Stamp),
 				d.LocalDeal.ProposalCid,
 				d.LocalDeal.DealID,
@@ -1737,6 +1756,7 @@ func outputStorageDeals(ctx context.Context, out io.Writer, full v0api.FullNode,
 				transferChannelID,
 				transferStatus,
 				d.LocalDeal.Verified,
+				sealing,
 				d.LocalDeal.Message)
 		}
 		return w.Flush()
@@ -1812,6 +1832,156 @@ type deal struct {
 	OnChainDealState market.DealState
 }
 
+var clientDealHealthCmd = &cli.Command{
+	Name:  "deal-health",
+	Usage: "Check the provider sectors holding active storage deals",
+	Description: `Reports active deals whose sector was terminated, is faulty, or expires
+   before the deal ends. Faulty sectors get terminated at the fault expiration
+   epoch unless the provider recovers them.`,
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "all",
+			Usage: "also list deals in healthy sectors",
+		},
+		&cli.BoolFlag{
+			Name:  "watch",
+			Usage: "keep printing deal health changes as they happen",
+		},
+		&cli.BoolFlag{
+			Name:  "color",
+			Usage: "use color in display output",
+			Value: true,
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+		ctx := ReqContext(cctx)
+
+		useColor := cctx.Bool("color")
+
+		if cctx.Bool("watch") {
+			updates, err := api.ClientDealHealthUpdates(ctx)
+			if err != nil {
+				return err
+			}
+
+			for h := range updates {
+				if !cctx.Bool("all") && h.Status == lapi.DealSectorOK {
+					continue
+				}
+
+				msg := ""
+				if h.Message != "" {
+					msg = ": " + h.Message
+				}
+				fmt.Fprintf(cctx.App.Writer, "%d: deal %d (provider %s, sector %d) %s%s\n",
+					h.Height, h.DealID, h.Provider, h.Sector, dealHealthString(useColor, h.Status), msg)
+			}
+			return nil
+		}
+
+		health, err := api.ClientDealHealth(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tablewriter.New(tablewriter.Col("DealId"),
+			tablewriter.Col("Provider"),
+			tablewriter.Col("Sector"),
+			tablewriter.Col("Status"),
+			tablewriter.Col("DealEnd"),
+			tablewriter.Col("SectorExpiration"),
+			tablewriter.Col("FaultExpiration"),
+			tablewriter.NewLineCol("Message"))
+
+		var unhealthy int
+		for _, h := range health {
+			if h.Status != lapi.DealSectorOK {
+				unhealthy++
+			} else if !cctx.Bool("all") {
+				continue
+			}
+
+			row := map[string]interface{}{
+				"DealId":   h.DealID,
+				"Provider": h.Provider,
+				"Status":   dealHealthString(useColor, h.Status),
+				"DealEnd":  h.DealEnd,
+				"Message":  h.Message,
+			}
+			if h.Sector != 0 || h.SectorExpiration != 0 {
+				row["Sector"] = h.Sector
+			}
+			if h.SectorExpiration != 0 {
+				row["SectorExpiration"] = h.SectorExpiration
+			}
+			if h.FaultExpiration != 0 {
+				row["FaultExpiration"] = h.FaultExpiration
+			}
+			w.Write(row)
+		}
+
+		if err := w.Flush(cctx.App.Writer); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cctx.App.Writer, "%d active deals, %d need attention\n", len(health), unhealthy)
+		return nil
+	},
+}
+
+func dealHealthString(c bool, status lapi.DealSectorStatus) string {
+	s := string(status)
+	if !c {
+		return s
+	}
+
+	switch status {
+	case lapi.DealSectorTerminated:
+		return color.RedString(s)
+	case lapi.DealSectorFaulty, lapi.DealSectorExpiringEarly:
+		return color.YellowString(s)
+	case lapi.DealSectorOK:
+		return color.GreenString(s)
+	default:
+		return s
+	}
+}
+
+// sealingDealStates are the states in which the provider is sealing the deal
+// and can tell where it is in the sealing pipeline
+var sealingDealStates = map[storagemarket.StorageDealStatus]bool{
+	storagemarket.StorageDealStaged:            true,
+	storagemarket.StorageDealAwaitingPreCommit: true,
+	storagemarket.StorageDealSealing:           true,
+}
+
+func dealSealingString(ctx context.Context, full v0api.FullNode, proposal cid.Cid) string {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	st, err := full.ClientDealSealingStatus(ctx, proposal)
+	if err != nil {
+		return "unavailable"
+	}
+	if !st.Disclosed {
+		return "not disclosed"
+	}
+
+	out := fmt.Sprintf("not in a sector, %d in queue", st.QueueLength)
+	if st.SectorState != "" {
+		out = fmt.Sprintf("sector %d %s, %d/%d in queue", st.Sector, st.SectorState, st.QueuePosition, st.QueueLength)
+	}
+	if !st.ETA.IsZero() {
+		out += fmt.Sprintf(", ETA %s", st.ETA.Format(time.Stamp))
+	}
+	return out
+}
+
 var clientGetDealCmd = &cli.Command{
 	Name:  "get-deal",
 	Usage: "Print detailed deal information",
@@ -1850,6 +2020,18 @@ var clientGetDealCmd = &cli.Command{
 			out["OnChain"] = onChain
 		}
 
+		if sealingDealStates[di.State] {
+			st, err := api.ClientDealSealingStatus(ctx, propcid)
+			switch {
+			case err != nil:
+				out["SealingStatus"] = fmt.Sprintf("unavailable: %s", err)
+			case !st.Disclosed:
+				out["SealingStatus"] = "not disclosed"
+			default:
+				out["SealingStatus"] = st
+			}
+		}
+
 		b, err := json.MarshalIndent(out, "", "  ")
 		if err != nil {
 			return err
@@ -0,0 +1,419 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	datatransfer "github.com/filecoin-project/go-data-transfer"
+	"github.com/filecoin-project/go-fil-markets/retrievalmarket"
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/api/v0api"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/lib/tablewriter"
+)
+
+// retrievalCandidate is a provider offering the data, with the outcomes of
+// the retrievals the node made from it, from the retrieval stats, or from the
+// retrieval transfers the node has with it when it has no stats
+type retrievalCandidate struct {
+	offer     api.QueryOffer
+	successes int
+	failures  int
+	// throughput is the average throughput of past retrievals, in bytes per
+	// second
+	throughput uint64
+}
+
+func (c *retrievalCandidate) price() big.Int {
+	return big.Add(c.offer.MinPrice, c.offer.UnsealPrice)
+}
+
+// rankRetrievalCandidates orders candidates by price, and providers which
+// served retrievals well before first among equally priced ones, the faster
+// ones first among equally reliable ones
+func rankRetrievalCandidates(cs []retrievalCandidate) {
+	sort.SliceStable(cs, func(i, j int) bool {
+		if pi, pj := cs[i].price(), cs[j].price(); !pi.Equals(pj) {
+			return pi.LessThan(pj)
+		}
+		if si, sj := cs[i].successes-cs[i].failures, cs[j].successes-cs[j].failures; si != sj {
+			return si > sj
+		}
+		return cs[i].throughput > cs[j].throughput
+	})
+}
+
+// findRetrievalCandidates collects offers for the data from the providers
+// found by local discovery, the providers of local storage deals for it and
+// the given miners, querying the ones without an offer yet concurrently
+func findRetrievalCandidates(ctx context.Context, afmt *AppFmt, fapi v0api.FullNode, root cid.Cid, piece *cid.Cid, miners []address.Address, maxPrice types.FIL) ([]retrievalCandidate, error) {
+	offers, err := fapi.ClientFindData(ctx, root, piece)
+	if err != nil {
+		return nil, xerrors.Errorf("finding data: %w", err)
+	}
+
+	seen := map[address.Address]bool{}
+	for _, o := range offers {
+		seen[o.Miner] = true
+	}
+
+	deals, err := fapi.ClientListDeals(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("listing deals: %w", err)
+	}
+	for _, d := range deals {
+		if d.DataRef != nil && d.DataRef.Root.Equals(root) {
+			miners = append(miners, d.Provider)
+		}
+	}
+
+	var toQuery []address.Address
+	for _, m := range miners {
+		if !seen[m] {
+			seen[m] = true
+			toQuery = append(toQuery, m)
+		}
+	}
+
+	queried := make([]api.QueryOffer, len(toQuery))
+	var wg sync.WaitGroup
+	for i, m := range toQuery {
+		wg.Add(1)
+		go func(i int, m address.Address) {
+			defer wg.Done()
+
+			o, err := fapi.ClientMinerQueryOffer(ctx, m, root, piece)
+			if err != nil {
+				o = api.QueryOffer{Miner: m, Err: err.Error()}
+			}
+			queried[i] = o
+		}(i, m)
+	}
+	wg.Wait()
+	offers = append(offers, queried...)
+
+	transfers, err := fapi.ClientListDataTransfers(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("listing data transfers: %w", err)
+	}
+	successes := map[peer.ID]int{}
+	failures := map[peer.ID]int{}
+	for _, t := range transfers {
+		if t.IsSender {
+			continue
+		}
+		switch t.Status {
+		case datatransfer.Completed:
+			successes[t.OtherPeer]++
+		case datatransfer.Failed, datatransfer.Cancelled:
+			failures[t.OtherPeer]++
+		}
+	}
+
+	stats, err := fapi.ClientRetrievalStats(ctx, address.Undef)
+	if err != nil {
+		return nil, xerrors.Errorf("getting retrieval stats: %w", err)
+	}
+	statsByMiner := map[address.Address]api.RetrievalProviderStats{}
+	for _, st := range stats {
+		statsByMiner[st.Provider] = st
+	}
+
+	var out []retrievalCandidate
+	for _, o := range offers {
+		c := retrievalCandidate{
+			offer:     o,
+			successes: successes[o.MinerPeer.ID],
+			failures:  failures[o.MinerPeer.ID],
+		}
+		if st, ok := statsByMiner[o.Miner]; ok {
+			c.successes = st.Retrievals - st.Failures
+			c.failures = st.Failures
+			c.throughput = st.AvgThroughput
+		}
+		switch {
+		case o.Err != "":
+			afmt.Printf("Skipping %s: query failed: %s\n", o.Miner, o.Err)
+		case c.price().GreaterThan(big.Int(maxPrice)):
+			afmt.Printf("Skipping %s: price %s above maxPrice %s\n", o.Miner, types.FIL(c.price()), maxPrice)
+		default:
+			out = append(out, c)
+		}
+	}
+
+	rankRetrievalCandidates(out)
+	return out, nil
+}
+
+// retrieveFromCandidates tries the candidates in order until one of the
+// retrievals completes, returning the offer of the provider which served it.
+//
+// Retrievals can't be resumed from another provider, so output a failed
+// attempt left behind is removed before the next one.
+func retrieveFromCandidates(ctx context.Context, afmt *AppFmt, fapi v0api.FullNode, cs []retrievalCandidate, payer address.Address, ref *api.FileRef, attemptTimeout time.Duration) (*api.QueryOffer, error) {
+	_, statErr := os.Stat(ref.Path)
+	outputExisted := statErr == nil
+
+	for i, c := range cs {
+		afmt.Printf("Attempt %d/%d: retrieving from %s (%s, %d/%d recent transfers failed)\n",
+			i+1, len(cs), c.offer.Miner, types.FIL(c.price()), c.failures, c.successes+c.failures)
+
+		actx, cancel := context.WithTimeout(ctx, attemptTimeout)
+		order := c.offer.Order(payer)
+		err := retrieveOrder(actx, afmt, fapi, order, ref)
+		cancel()
+		if err == nil {
+			return &cs[i].offer, nil
+		}
+
+		afmt.Printf("Attempt %d/%d: retrieval from %s failed: %s\n", i+1, len(cs), c.offer.Miner, err)
+		if ctx.Err() != nil {
+			return nil, xerrors.Errorf("retrieval interrupted: %w", ctx.Err())
+		}
+
+		if !outputExisted {
+			if err := os.Remove(ref.Path); err != nil && !os.IsNotExist(err) {
+				return nil, xerrors.Errorf("removing partial output: %w", err)
+			}
+		}
+	}
+
+	return nil, xerrors.Errorf("retrieval failed from all %d providers", len(cs))
+}
+
+// retrieveOrder runs a retrieval, printing its progress
+func retrieveOrder(ctx context.Context, afmt *AppFmt, fapi v0api.FullNode, order api.RetrievalOrder, ref *api.FileRef) error {
+	updates, err := fapi.ClientRetrieveWithEvents(ctx, order, ref)
+	if err != nil {
+		return xerrors.Errorf("error setting up retrieval: %w", err)
+	}
+
+	for {
+		select {
+		case evt, ok := <-updates:
+			if !ok {
+				return nil
+			}
+
+			afmt.Printf("> Recv: %s, Paid %s, %s (%s)\n",
+				types.SizeStr(types.NewInt(evt.BytesReceived)),
+				types.FIL(evt.FundsSpent),
+				retrievalmarket.ClientEvents[evt.Event],
+				retrievalmarket.DealStatuses[evt.Status],
+			)
+
+			if evt.Err != "" {
+				return xerrors.Errorf("retrieval failed: %s", evt.Err)
+			}
+		case <-ctx.Done():
+			return xerrors.Errorf("retrieval timed out")
+		}
+	}
+}
+
+var clientRetrievalStatsCmd = &cli.Command{
+	Name:  "retrieval-stats",
+	Usage: "Show how providers performed in past retrievals",
+	Description: `Aggregates the records of the retrievals made by the node per provider, and
+   lists the most recent ones. The records also rank the providers tried by
+   'retrieve --try-all-providers'. They can be exported and imported as JSON to
+   share them between nodes.`,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "provider",
+			Usage: "only show the retrievals from this provider",
+		},
+		&cli.IntFlag{
+			Name:  "recent",
+			Usage: "number of recent retrievals to list",
+			Value: 10,
+		},
+	},
+	Subcommands: []*cli.Command{
+		clientRetrievalStatsExportCmd,
+		clientRetrievalStatsImportCmd,
+	},
+	Action: func(cctx *cli.Context) error {
+		fapi, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+		ctx := ReqContext(cctx)
+
+		provider, err := providerFlag(cctx)
+		if err != nil {
+			return err
+		}
+
+		stats, err := fapi.ClientRetrievalStats(ctx, provider)
+		if err != nil {
+			return err
+		}
+		if len(stats) == 0 {
+			fmt.Fprintln(cctx.App.Writer, "No retrievals recorded")
+			return nil
+		}
+
+		w := tablewriter.New(tablewriter.Col("Provider"),
+			tablewriter.Col("Retrievals"),
+			tablewriter.Col("Failures"),
+			tablewriter.Col("Received"),
+			tablewriter.Col("Cost"),
+			tablewriter.Col("AvgTTFB"),
+			tablewriter.Col("AvgThroughput"),
+			tablewriter.Col("Last"),
+			tablewriter.NewLineCol("LastError"))
+		for _, st := range stats {
+			w.Write(map[string]interface{}{
+				"Provider":      st.Provider,
+				"Retrievals":    st.Retrievals,
+				"Failures":      st.Failures,
+				"Received":      types.SizeStr(types.NewInt(st.BytesReceived)),
+				"Cost":          types.FIL(st.TotalCost),
+				"AvgTTFB":       st.AvgTimeToFirstByte.Round(time.Millisecond),
+				"AvgThroughput": throughputStr(st.AvgThroughput),
+				"Last":          st.LastRetrieval.Format(time.Stamp),
+				"LastError":     st.LastError,
+			})
+		}
+		if err := w.Flush(cctx.App.Writer); err != nil {
+			return err
+		}
+
+		recs, err := fapi.ClientRetrievalHistory(ctx, provider, cctx.Int("recent"))
+		if err != nil {
+			return err
+		}
+		if len(recs) == 0 {
+			return nil
+		}
+
+		fmt.Fprintln(cctx.App.Writer, "\nRecent retrievals:")
+		w = tablewriter.New(tablewriter.Col("Started"),
+			tablewriter.Col("Provider"),
+			tablewriter.Col("Root"),
+			tablewriter.Col("Size"),
+			tablewriter.Col("TTFB"),
+			tablewriter.Col("Duration"),
+			tablewriter.Col("Throughput"),
+			tablewriter.Col("Cost"),
+			tablewriter.NewLineCol("Error"))
+		for _, r := range recs {
+			row := map[string]interface{}{
+				"Started":  r.Started.Format(time.Stamp),
+				"Provider": r.Provider,
+				"Root":     r.Root,
+				"Size":     types.SizeStr(types.NewInt(r.Size)),
+				"Duration": r.Duration.Round(time.Millisecond),
+				"Cost":     types.FIL(r.Cost),
+				"Error":    r.Error,
+			}
+			if r.BytesReceived > 0 {
+				row["TTFB"] = r.TimeToFirstByte.Round(time.Millisecond)
+				row["Throughput"] = throughputStr(r.Throughput)
+			}
+			w.Write(row)
+		}
+		return w.Flush(cctx.App.Writer)
+	},
+}
+
+var clientRetrievalStatsExportCmd = &cli.Command{
+	Name:      "export",
+	Usage:     "Export the retrieval records as JSON",
+	ArgsUsage: "[outputPath]",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "provider",
+			Usage: "only export the retrievals from this provider",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		fapi, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+		ctx := ReqContext(cctx)
+
+		provider, err := providerFlag(cctx)
+		if err != nil {
+			return err
+		}
+
+		recs, err := fapi.ClientRetrievalHistory(ctx, provider, 0)
+		if err != nil {
+			return err
+		}
+		b, err := json.MarshalIndent(recs, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		if cctx.Args().Present() {
+			return ioutil.WriteFile(cctx.Args().First(), b, 0644)
+		}
+		fmt.Fprintln(cctx.App.Writer, string(b))
+		return nil
+	},
+}
+
+var clientRetrievalStatsImportCmd = &cli.Command{
+	Name:      "import",
+	Usage:     "Import retrieval records exported by another node",
+	ArgsUsage: "<inputPath>",
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() != 1 {
+			return ShowHelp(cctx, fmt.Errorf("must pass the path of the exported records"))
+		}
+
+		fapi, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+		ctx := ReqContext(cctx)
+
+		b, err := ioutil.ReadFile(cctx.Args().First())
+		if err != nil {
+			return err
+		}
+		var recs []api.RetrievalRecord
+		if err := json.Unmarshal(b, &recs); err != nil {
+			return xerrors.Errorf("decoding retrieval records: %w", err)
+		}
+
+		n, err := fapi.ClientRetrievalStatsImport(ctx, recs)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cctx.App.Writer, "Imported %d of %d records\n", n, len(recs))
+		return nil
+	},
+}
+
+func providerFlag(cctx *cli.Context) (address.Address, error) {
+	if cctx.String("provider") == "" {
+		return address.Undef, nil
+	}
+	return address.NewFromString(cctx.String("provider"))
+}
+
+func throughputStr(bps uint64) string {
+	return types.SizeStr(types.NewInt(bps)) + "/s"
+}
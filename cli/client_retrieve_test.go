@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/lotus/api"
+)
+
+func TestRankRetrievalCandidates(t *testing.T) {
+	candidate := func(miner uint64, price, unseal int64, successes, failures int) retrievalCandidate {
+		return retrievalCandidate{
+			offer: api.QueryOffer{
+				Miner:       mustAddr(address.NewIDAddress(miner)),
+				MinPrice:    big.NewInt(price),
+				UnsealPrice: big.NewInt(unseal),
+			},
+			successes: successes,
+			failures:  failures,
+		}
+	}
+
+	cs := []retrievalCandidate{
+		candidate(1, 10, 5, 0, 0),
+		candidate(2, 10, 0, 0, 3),
+		candidate(3, 10, 0, 2, 0),
+		candidate(4, 5, 0, 0, 10),
+	}
+	fast := candidate(5, 10, 0, 2, 0)
+	fast.throughput = 1 << 20
+	cs = append(cs, fast)
+	rankRetrievalCandidates(cs)
+
+	var order []string
+	for _, c := range cs {
+		order = append(order, c.offer.Miner.String())
+	}
+	require.Equal(t, []string{
+		mustAddr(address.NewIDAddress(4)).String(), // cheapest, despite failures
+		mustAddr(address.NewIDAddress(5)).String(), // same price, better record, faster
+		mustAddr(address.NewIDAddress(3)).String(),
+		mustAddr(address.NewIDAddress(2)).String(),
+		mustAddr(address.NewIDAddress(1)).String(), // unsealing makes it the most expensive
+	}, order)
+}
@@ -62,6 +62,7 @@ var CommonCommands = []*cli.Command{
 	LogCmd,
 	WaitApiCmd,
 	FetchParamCmd,
+	ParamsCmd,
 	PprofCmd,
 	VersionCmd,
 }
@@ -79,7 +80,9 @@ var Commands = []*cli.Command{
 	WithCategory("developer", ChainCmd),
 	WithCategory("developer", LogCmd),
 	WithCategory("developer", WaitApiCmd),
+	WithCategory("developer", ApiCmd),
 	WithCategory("developer", FetchParamCmd),
+	WithCategory("developer", ParamsCmd),
 	WithCategory("network", NetCmd),
 	WithCategory("network", SyncCmd),
 	PprofCmd,
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ipfs/go-cid"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+
+	lapi "github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/build"
+	"github.com/filecoin-project/lotus/chain/actors/builtin"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/multisig"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+// ConfidenceTier requires Confidence epochs for messages putting at least
+// Value at risk
+type ConfidenceTier struct {
+	Value      types.FIL
+	Confidence uint64
+}
+
+// ConfidencePolicy maps the value a message puts at risk to the number of
+// epochs to wait for before considering it final. Tiers are sorted by value.
+type ConfidencePolicy []ConfidenceTier
+
+var DefaultConfidencePolicy = ConfidencePolicy{
+	{Value: types.FIL(big.Zero()), Confidence: 1},
+	{Value: types.MustParseFIL("1"), Confidence: build.MessageConfidence},
+	{Value: types.MustParseFIL("1000"), Confidence: 20},
+	{Value: types.MustParseFIL("100000"), Confidence: 60},
+}
+
+var confidencePolicyFlag = &cli.StringFlag{
+	Name:    "confidence-policy",
+	Usage:   "confidence to wait for by value at risk when --confidence isn't given, as comma separated value:epochs, e.g. '0:1,1:5,1000:20'",
+	EnvVars: []string{"LOTUS_CONFIDENCE_POLICY"},
+}
+
+// ParseConfidencePolicy parses policies in the 'value:epochs,...' format,
+// values being in FIL
+func ParseConfidencePolicy(s string) (ConfidencePolicy, error) {
+	var out ConfidencePolicy
+	for _, t := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(t), ":")
+		if len(parts) != 2 {
+			return nil, xerrors.Errorf("malformed tier %q, expected value:epochs", t)
+		}
+
+		val, err := types.ParseFIL(parts[0])
+		if err != nil {
+			return nil, xerrors.Errorf("parsing value of tier %q: %w", t, err)
+		}
+		if big.Int(val).Sign() < 0 {
+			return nil, xerrors.Errorf("negative value in tier %q", t)
+		}
+
+		conf, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return nil, xerrors.Errorf("parsing epochs of tier %q: %w", t, err)
+		}
+
+		out = append(out, ConfidenceTier{Value: val, Confidence: conf})
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return big.Int(out[i].Value).LessThan(big.Int(out[j].Value))
+	})
+	return out, nil
+}
+
+// Confidence returns the confidence required for the value at risk and the
+// tier requiring it. Values below the lowest tier get the default confidence.
+func (p ConfidencePolicy) Confidence(atRisk abi.TokenAmount) (uint64, *ConfidenceTier) {
+	for i := len(p) - 1; i >= 0; i-- {
+		if big.Cmp(atRisk, big.Int(p[i].Value)) >= 0 {
+			return p[i].Confidence, &p[i]
+		}
+	}
+	return build.MessageConfidence, nil
+}
+
+type confidenceAPI interface {
+	ChainGetMessage(context.Context, cid.Cid) (*types.Message, error)
+	StateGetActor(context.Context, address.Address, types.TipSetKey) (*types.Actor, error)
+	StateWaitMsg(context.Context, cid.Cid, uint64) (*lapi.MsgLookup, error)
+}
+
+// valueAtRisk is the value the message moves, including the worst-case fee
+// of method calls and the value proposed through a multisig
+func valueAtRisk(ctx context.Context, api confidenceAPI, msg *types.Message) (abi.TokenAmount, error) {
+	out := msg.Value
+	if msg.Method == builtin.MethodSend {
+		return out, nil
+	}
+
+	out = big.Add(out, msg.RequiredFunds())
+	if msg.Method != multisig.Methods.Propose {
+		return out, nil
+	}
+
+	act, err := api.StateGetActor(ctx, msg.To, types.EmptyTSK)
+	if err != nil {
+		return abi.TokenAmount{}, xerrors.Errorf("getting target actor: %w", err)
+	}
+	if !builtin.IsMultisigActor(act.Code) {
+		return out, nil
+	}
+
+	var params multisig.ProposeParams
+	if err := params.UnmarshalCBOR(bytes.NewReader(msg.Params)); err != nil {
+		return abi.TokenAmount{}, xerrors.Errorf("decoding proposal: %w", err)
+	}
+	return big.Add(out, params.Value), nil
+}
+
+// waitConfidence returns the confidence to wait for the message with. An
+// explicit --confidence wins, otherwise it is picked by the value at risk.
+func waitConfidence(ctx context.Context, cctx *cli.Context, api confidenceAPI, msgCid cid.Cid) (uint64, error) {
+	if cctx.IsSet("confidence") {
+		return cctx.Uint64("confidence"), nil
+	}
+
+	policy := DefaultConfidencePolicy
+	if cctx.IsSet(confidencePolicyFlag.Name) {
+		p, err := ParseConfidencePolicy(cctx.String(confidencePolicyFlag.Name))
+		if err != nil {
+			return 0, xerrors.Errorf("parsing confidence policy: %w", err)
+		}
+		policy = p
+	}
+
+	msg, err := api.ChainGetMessage(ctx, msgCid)
+	if err != nil {
+		return 0, xerrors.Errorf("getting message: %w", err)
+	}
+
+	atRisk, err := valueAtRisk(ctx, api, msg)
+	if err != nil {
+		return 0, xerrors.Errorf("computing value at risk: %w", err)
+	}
+
+	conf, tier := policy.Confidence(atRisk)
+	reason := "below the lowest tier of the confidence policy"
+	if tier != nil {
+		reason = fmt.Sprintf("the confidence policy requires %d from %s", tier.Confidence, tier.Value)
+	}
+	fmt.Fprintf(cctx.App.ErrWriter, "Waiting for %d epochs of confidence: %s at risk, %s\n", conf, types.FIL(atRisk), reason)
+
+	return conf, nil
+}
+
+// waitMsgConfidence waits for the message with the confidence picked by
+// waitConfidence
+func waitMsgConfidence(ctx context.Context, cctx *cli.Context, api confidenceAPI, msgCid cid.Cid) (*lapi.MsgLookup, error) {
+	conf, err := waitConfidence(ctx, cctx, api, msgCid)
+	if err != nil {
+		return nil, err
+	}
+	return api.StateWaitMsg(ctx, msgCid, conf)
+}
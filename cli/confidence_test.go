@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	mocks "github.com/filecoin-project/lotus/api/v0api/v0mocks"
+	"github.com/filecoin-project/lotus/chain/actors"
+	"github.com/filecoin-project/lotus/chain/actors/builtin"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/multisig"
+	types "github.com/filecoin-project/lotus/chain/types"
+	builtin5 "github.com/filecoin-project/specs-actors/v5/actors/builtin"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	ucli "github.com/urfave/cli/v2"
+)
+
+func TestParseConfidencePolicy(t *testing.T) {
+	p, err := ParseConfidencePolicy("1000:20, 0:1,1:5")
+	require.NoError(t, err)
+	require.Equal(t, ConfidencePolicy{
+		{Value: types.MustParseFIL("0"), Confidence: 1},
+		{Value: types.MustParseFIL("1"), Confidence: 5},
+		{Value: types.MustParseFIL("1000"), Confidence: 20},
+	}, p)
+
+	for _, s := range []string{"", "1", "1:2:3", "x:5", "1:x", "-1:5"} {
+		_, err := ParseConfidencePolicy(s)
+		require.Error(t, err, s)
+	}
+
+	for _, tc := range []struct {
+		atRisk string
+		conf   uint64
+	}{
+		{"0", 1},
+		{"0.5", 1},
+		{"1", 5},
+		{"999.99", 5},
+		{"100000", 20},
+	} {
+		conf, _ := p.Confidence(abi.TokenAmount(types.MustParseFIL(tc.atRisk)))
+		require.Equal(t, tc.conf, conf, tc.atRisk)
+	}
+
+	// below the lowest tier
+	conf, tier := p[1:].Confidence(abi.TokenAmount(types.MustParseFIL("0.5")))
+	require.Nil(t, tier)
+	require.Equal(t, uint64(5), conf)
+}
+
+func TestWaitConfidence(t *testing.T) {
+	fil := func(s string) abi.TokenAmount { return abi.TokenAmount(types.MustParseFIL(s)) }
+	to := mustAddr(address.NewIDAddress(1))
+
+	proposal, err := actors.SerializeParams(&multisig.ProposeParams{
+		To:     mustAddr(address.NewIDAddress(2)),
+		Value:  fil("2000"),
+		Method: builtin.MethodSend,
+	})
+	require.NoError(t, err)
+
+	msgs := map[string]*types.Message{
+		"send": {Value: fil("0.5"), Method: builtin.MethodSend, GasFeeCap: fil("1"), GasLimit: 1},
+		// the worst-case fee tips the call into the next tier
+		"call":    {Value: fil("0.5"), Method: 3, GasFeeCap: fil("0.000001"), GasLimit: 1_000_000},
+		"propose": {Value: fil("0"), Method: multisig.Methods.Propose, Params: proposal, GasFeeCap: fil("0")},
+	}
+	for _, msg := range msgs {
+		msg.From = mustAddr(address.NewIDAddress(100))
+		msg.To = to
+		msg.GasPremium = fil("0")
+	}
+
+	run := func(t *testing.T, msg *types.Message, args ...string) (uint64, string) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		full := mocks.NewMockFullNode(ctrl)
+
+		full.EXPECT().ChainGetMessage(gomock.Any(), msg.Cid()).Return(msg, nil).AnyTimes()
+		if msg.Method == multisig.Methods.Propose {
+			full.EXPECT().StateGetActor(gomock.Any(), msg.To, types.EmptyTSK).
+				Return(&types.Actor{Code: builtin5.MultisigActorCodeID}, nil).AnyTimes()
+		}
+
+		var conf uint64
+		app := ucli.NewApp()
+		app.Commands = ucli.Commands{{
+			Name: "wait",
+			Flags: []ucli.Flag{
+				&ucli.Uint64Flag{Name: "confidence"},
+				confidencePolicyFlag,
+			},
+			Action: func(cctx *ucli.Context) error {
+				var err error
+				conf, err = waitConfidence(context.Background(), cctx, full, msg.Cid())
+				return err
+			},
+		}}
+		errw := &bytes.Buffer{}
+		app.ErrWriter = errw
+
+		require.NoError(t, app.Run(append([]string{"lotus", "wait"}, args...)))
+		return conf, errw.String()
+	}
+
+	t.Run("send", func(t *testing.T) {
+		conf, out := run(t, msgs["send"])
+		require.Equal(t, uint64(1), conf)
+		require.True(t, strings.Contains(out, "0.5 WD at risk"), out)
+	})
+	t.Run("call", func(t *testing.T) {
+		conf, _ := run(t, msgs["call"])
+		require.Equal(t, uint64(5), conf)
+	})
+	t.Run("propose", func(t *testing.T) {
+		conf, _ := run(t, msgs["propose"])
+		require.Equal(t, uint64(20), conf)
+	})
+	t.Run("policy", func(t *testing.T) {
+		conf, _ := run(t, msgs["send"], "--confidence-policy", "0:3,10:40")
+		require.Equal(t, uint64(3), conf)
+	})
+	t.Run("explicit", func(t *testing.T) {
+		conf, out := run(t, msgs["propose"], "--confidence", "2")
+		require.Equal(t, uint64(2), conf)
+		require.Empty(t, out)
+	})
+}
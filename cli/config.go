@@ -0,0 +1,252 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/mitchellh/go-homedir"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	lapi "github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/node/config"
+	"github.com/filecoin-project/lotus/node/repo"
+)
+
+func defaultConfig(rt repo.RepoType) (interface{}, error) {
+	switch rt {
+	case repo.FullNode:
+		return config.DefaultFullNode(), nil
+	case repo.StorageMiner:
+		return config.DefaultStorageMiner(), nil
+	default:
+		return nil, xerrors.Errorf("no config for repo type %d", rt)
+	}
+}
+
+// ConfigValidateCmd returns a command which checks the node config for
+// semantic problems, shared between lotus and lotus-miner
+func ConfigValidateCmd(repoFlag string, rt repo.RepoType) *cli.Command {
+	return &cli.Command{
+		Name:  "validate",
+		Usage: "Check the node config file for problems",
+		Description: `Parses the config file and applies semantic checks to it, listing all
+   errors and warnings together with the path of the offending setting.
+
+   Exits with a non-zero status if any errors were found.
+
+   With --against-running, the config is also compared with the config loaded
+   by the running node, listing all settings which will only take effect
+   after a restart.`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "config",
+				Usage: "path to the config file (default: config.toml in the repo)",
+			},
+			&cli.BoolFlag{
+				Name:  "against-running",
+				Usage: "compare with the config loaded by the running node",
+			},
+		},
+		Action: func(cctx *cli.Context) error {
+			cfgPath := cctx.String("config")
+			if cfgPath == "" {
+				repoPath, err := homedir.Expand(cctx.String(repoFlag))
+				if err != nil {
+					return err
+				}
+				cfgPath = filepath.Join(repoPath, "config.toml")
+			}
+
+			cfgPath, err := homedir.Expand(cfgPath)
+			if err != nil {
+				return err
+			}
+
+			cfgBytes, err := ioutil.ReadFile(cfgPath)
+			if err != nil {
+				return xerrors.Errorf("reading config: %w", err)
+			}
+
+			def, err := defaultConfig(rt)
+			if err != nil {
+				return err
+			}
+			cfg, err := config.FromReader(bytes.NewReader(cfgBytes), def)
+			if err != nil {
+				return xerrors.Errorf("parsing config %s: %w", cfgPath, err)
+			}
+
+			def, err = defaultConfig(rt)
+			if err != nil {
+				return err
+			}
+			violations, err := config.UnknownKeys(bytes.NewReader(cfgBytes), def)
+			if err != nil {
+				return xerrors.Errorf("parsing config %s: %w", cfgPath, err)
+			}
+			violations = append(violations, config.Validate(cfg)...)
+
+			var errs int
+			for _, v := range violations {
+				if v.Severity == config.SeverityError {
+					errs++
+				}
+				fmt.Fprintf(cctx.App.Writer, "%-8s%s: %s [%s]\n", v.Severity, v.Path, v.Message, v.Rule)
+			}
+
+			if cctx.Bool("against-running") {
+				api, closer, err := GetAPI(cctx)
+				if err != nil {
+					return err
+				}
+				defer closer()
+
+				loaded, err := api.ConfigLoaded(ReqContext(cctx))
+				if err != nil {
+					return xerrors.Errorf("getting loaded config: %w", err)
+				}
+
+				running, err := defaultConfig(rt)
+				if err != nil {
+					return err
+				}
+				if _, err := toml.Decode(string(loaded), running); err != nil {
+					return xerrors.Errorf("decoding loaded config: %w", err)
+				}
+
+				pending, err := config.Diff(running, cfg)
+				if err != nil {
+					return err
+				}
+				for _, path := range pending {
+					fmt.Fprintf(cctx.App.Writer, "%-8s%s: differs from the running node, change pending a restart\n", "pending", path)
+				}
+			}
+
+			if errs > 0 {
+				return xerrors.Errorf("config %s has %d error(s)", cfgPath, errs)
+			}
+
+			if len(violations) == 0 {
+				fmt.Fprintf(cctx.App.Writer, "config %s is valid\n", cfgPath)
+			}
+			return nil
+		},
+	}
+}
+
+// ConfigHistoryCmd returns a command which lists the snapshots of the
+// effective config of the node, shared between lotus and lotus-miner
+func ConfigHistoryCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "history",
+		Usage: "List the changes to the config of the node",
+		Description: `Lists the snapshots of the effective config the node took on startup and
+   on each change made at runtime over the API, with the settings changed
+   since the previous one. The effective config is the config file loaded,
+   and the settings changed at runtime: the mpool config of full nodes, and
+   the sealing config and storage paths of miners.
+
+   A change made over the API names the token it was made with by its
+   fingerprint, the first 8 hex digits of its sha256:
+     printf %s $TOKEN | sha256sum | cut -c1-8
+
+   The node keeps the last 100 snapshots.`,
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "last",
+				Usage: "list only the last snapshots",
+			},
+		},
+		Subcommands: []*cli.Command{
+			configHistoryExportCmd,
+		},
+		Action: func(cctx *cli.Context) error {
+			api, closer, err := GetAPI(cctx)
+			if err != nil {
+				return err
+			}
+			defer closer()
+
+			snaps, err := api.ConfigHistory(ReqContext(cctx))
+			if err != nil {
+				return err
+			}
+
+			first := 0
+			if n := cctx.Int("last"); n > 0 && n < len(snaps) {
+				first = len(snaps) - n
+			}
+			for i := first; i < len(snaps); i++ {
+				s := snaps[i]
+				fmt.Fprintf(cctx.App.Writer, "%s  %s by %s  %s\n", s.Time.Format(time.RFC3339), s.Reason, s.Caller, s.Cid)
+				if i == 0 {
+					fmt.Fprintln(cctx.App.Writer, "  (oldest snapshot kept)")
+					continue
+				}
+				changes := config.DiffSnapshots(snaps[i-1].Sections, s.Sections)
+				if len(changes) == 0 {
+					fmt.Fprintln(cctx.App.Writer, "  (no changes)")
+				}
+				for _, c := range changes {
+					fmt.Fprintf(cctx.App.Writer, "  %s\n", c)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// configHistoryBundle is the config history exported for a bug report
+type configHistoryBundle struct {
+	Version   string
+	Exported  time.Time
+	Snapshots []lapi.ConfigSnapshot
+}
+
+var configHistoryExportCmd = &cli.Command{
+	Name:  "export",
+	Usage: "Export the config history, to attach to bug reports",
+	Description: `Writes the config snapshots kept by the node as JSON, with the node version.
+   The secrets of the config file, as the token of a remote wallet, are
+   redacted in the snapshots; their fingerprint tells when they changed.`,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "output",
+			Usage: "file to write the bundle to, stdout by default",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		napi, closer, err := GetAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := ReqContext(cctx)
+		v, err := napi.Version(ctx)
+		if err != nil {
+			return err
+		}
+		snaps, err := napi.ConfigHistory(ctx)
+		if err != nil {
+			return err
+		}
+
+		b, err := json.MarshalIndent(configHistoryBundle{Version: v.Version, Exported: time.Now(), Snapshots: snaps}, "", "  ")
+		if err != nil {
+			return err
+		}
+		if out := cctx.String("output"); out != "" {
+			return ioutil.WriteFile(out, b, 0644)
+		}
+		_, err = fmt.Fprintln(cctx.App.Writer, string(b))
+		return err
+	},
+}
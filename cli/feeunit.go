@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"github.com/urfave/cli/v2"
+
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+// FeeUnitFlag selects the unit all gas fee figures (base fee, premium, fee
+// cap, fees paid) are printed in
+var FeeUnitFlag = &cli.StringFlag{
+	Name:    "fee-unit",
+	EnvVars: []string{"LOTUS_FEE_UNIT"},
+	Usage:   "unit to print gas fee figures in, e.g. WD, milliWD, nanoWD or attoWD",
+	Value:   types.UnitFIL.String(),
+}
+
+// feeUnit returns the unit selected with --fee-unit, defaulting to FIL for
+// apps which don't define the flag
+func feeUnit(cctx *cli.Context) (types.FILUnit, error) {
+	s := cctx.String(FeeUnitFlag.Name)
+	if s == "" {
+		return types.UnitFIL, nil
+	}
+	return types.ParseFILUnit(s)
+}
@@ -1,12 +1,16 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
 
 	ufcli "github.com/urfave/cli/v2"
 	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/api/errcode"
 )
 
 type PrintHelpErr struct {
@@ -31,6 +35,116 @@ func ShowHelp(cctx *ufcli.Context, err error) error {
 	return &PrintHelpErr{Err: err, Ctx: cctx}
 }
 
+// ExitStatusErr makes RunApp exit with Status instead of 1
+type ExitStatusErr struct {
+	Err    error
+	Status int
+}
+
+func (e *ExitStatusErr) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitStatusErr) Unwrap() error {
+	return e.Err
+}
+
+// Exit statuses scripts can rely on, besides the ones of API errors in
+// apiErrorExitStatus. Any other failure exits with 1.
+const (
+	// ExitCheckFailed is a check rejecting a message before it was sent,
+	// which --force skips
+	ExitCheckFailed = 2
+	// ExitAborted is the user declining to go on at a prompt
+	ExitAborted = 3
+	// ExitPushFailed is the node not accepting a message
+	ExitPushFailed = 4
+	// ExitExecutionFailed is a message executed with a non-zero exit code
+	ExitExecutionFailed = 5
+	// ExitAlreadyMined is a message to act on being already on chain
+	ExitAlreadyMined = 6
+	// ExitTimeout is a wait timing out
+	ExitTimeout = 7
+	// ExitDeadline is a send not pushed by its --deadline
+	ExitDeadline = 8
+)
+
+var (
+	ErrAbortedByUser   = errors.New("aborted by user")
+	ErrExecutionFailed = errors.New("message execution failed")
+	ErrAlreadyMined    = errors.New("message already mined")
+	ErrWaitTimeout     = errors.New("wait timed out")
+	// ErrDeadlineExceeded is a send not pushed by its deadline
+	ErrDeadlineExceeded = errors.New("send deadline exceeded")
+)
+
+// errorExitStatus are the exit statuses of commands failing with the errors
+var errorExitStatus = []struct {
+	err    error
+	status int
+}{
+	{ErrSendBalanceTooLow, ExitCheckFailed},
+	{ErrAbortedByUser, ExitAborted},
+	{ErrExecutionFailed, ExitExecutionFailed},
+	{ErrAlreadyMined, ExitAlreadyMined},
+	{ErrWaitTimeout, ExitTimeout},
+	{ErrDeadlineExceeded, ExitDeadline},
+}
+
+// ExitStatus is the status the command failing with err exits with
+func ExitStatus(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var ese *ExitStatusErr
+	if xerrors.As(err, &ese) {
+		return ese.Status
+	}
+	for _, e := range errorExitStatus {
+		if errors.Is(err, e.err) {
+			return e.status
+		}
+	}
+	if status, ok := apiErrorExitStatus[api.ErrorCode(err)]; ok {
+		return status
+	}
+	return 1
+}
+
+// WithExitStatus sets the exit status of err to status, unless err has a
+// more specific one
+func WithExitStatus(err error, status int) error {
+	if ExitStatus(err) != 1 {
+		return err
+	}
+	return &ExitStatusErr{Err: err, Status: status}
+}
+
+// apiErrorExitStatus are the exit statuses of commands failing with API errors
+// of a known class
+var apiErrorExitStatus = map[errcode.Code]int{
+	errcode.ActorNotFound:          10,
+	errcode.MessageNotFound:        11,
+	errcode.TipSetNotFound:         12,
+	errcode.OutOfGas:               13,
+	errcode.NonceTooLow:            14,
+	errcode.InsufficientFunds:      15,
+	errcode.LookbackExceeded:       16,
+	errcode.IdempotencyKeyConflict: 17,
+	errcode.WatchOnlyAddress:       18,
+	errcode.WalletPolicyViolation:  19,
+	errcode.NullRound:              20,
+}
+
+// WithAPIExitStatus sets the exit status for err from its API error code
+func WithAPIExitStatus(err error) error {
+	if status, ok := apiErrorExitStatus[api.ErrorCode(err)]; ok {
+		return &ExitStatusErr{Err: err, Status: status}
+	}
+	return err
+}
+
 func RunApp(app *ufcli.App) {
 	if err := app.Run(os.Args); err != nil {
 		if os.Getenv("LOTUS_DEV") != "" {
@@ -42,7 +156,7 @@ func RunApp(app *ufcli.App) {
 		if xerrors.As(err, &phe) {
 			_ = ufcli.ShowCommandHelp(phe.Ctx, phe.Ctx.Command.Name)
 		}
-		os.Exit(1)
+		os.Exit(ExitStatus(err))
 	}
 }
 
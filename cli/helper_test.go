@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	gomock "github.com/golang/mock/gomock"
+	cid "github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/require"
+	ucli "github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	lapi "github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/api/mocks"
+	"github.com/filecoin-project/lotus/chain/denylist"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+func TestExitStatus(t *testing.T) {
+	require.Equal(t, 0, ExitStatus(nil))
+	require.Equal(t, 1, ExitStatus(errors.New("something")))
+	require.Equal(t, ExitCheckFailed, ExitStatus(xerrors.Errorf("send: %w", ErrSendBalanceTooLow)))
+	require.Equal(t, ExitTimeout, ExitStatus(xerrors.Errorf("wait: %w", ErrWaitTimeout)))
+
+	// the more specific status is kept
+	require.Equal(t, ExitAborted, ExitStatus(WithExitStatus(ErrAbortedByUser, ExitPushFailed)))
+	require.Equal(t, ExitPushFailed, ExitStatus(WithExitStatus(errors.New("push"), ExitPushFailed)))
+}
+
+func TestSendExitStatus(t *testing.T) {
+	to := mustAddr(address.NewIDAddress(1))
+	from := mustAddr(address.NewIDAddress(2))
+
+	t.Run("check-failed", func(t *testing.T) {
+		app, mockSrvcs, _, done := newMockApp(t, sendCmd)
+		defer done()
+
+		gomock.InOrder(
+			mockSrvcs.EXPECT().Send(gomock.Any(), gomock.Any()).Return(cid.Undef, ErrSendBalanceTooLow),
+			mockSrvcs.EXPECT().Close(),
+		)
+		err := app.Run([]string{"lotus", "send", to.String(), "1"})
+		require.Equal(t, ExitCheckFailed, ExitStatus(err))
+	})
+
+	t.Run("aborted", func(t *testing.T) {
+		app, mockSrvcs, _, done := newMockApp(t, sendCmd)
+		defer done()
+		app.Metadata["stdin"] = strings.NewReader("n\n")
+
+		pending := PendingMessage{Message: &types.SignedMessage{Message: types.Message{
+			From:  from,
+			To:    to,
+			Value: abi.NewTokenAmount(1),
+		}}}
+		gomock.InOrder(
+			mockSrvcs.EXPECT().PendingMessages(gomock.Any(), from).Return([]PendingMessage{pending}, nil),
+			mockSrvcs.EXPECT().Close(),
+		)
+		err := app.Run([]string{"lotus", "send", "--pending", "--from", from.String(), to.String(), "1"})
+		require.Equal(t, ExitAborted, ExitStatus(err))
+	})
+
+	t.Run("push-failed", func(t *testing.T) {
+		app, mockSrvcs, _, done := newMockApp(t, sendCmd)
+		defer done()
+
+		gomock.InOrder(
+			mockSrvcs.EXPECT().Send(gomock.Any(), gomock.Any()).Return(cid.Undef, xerrors.Errorf("mpool push: %w", denylist.ErrDenied)),
+			mockSrvcs.EXPECT().Close(),
+		)
+		err := app.Run([]string{"lotus", "send", to.String(), "1"})
+		require.Equal(t, ExitPushFailed, ExitStatus(err))
+	})
+
+	t.Run("execution-failed", func(t *testing.T) {
+		app, mockSrvcs, mockApi, _, done := newMockAppWithFullNode(t, sendCmd)
+		defer done()
+		app.ErrWriter = &bytes.Buffer{}
+
+		msg := &types.Message{
+			From:       from,
+			To:         to,
+			Value:      abi.NewTokenAmount(1),
+			GasFeeCap:  abi.NewTokenAmount(1),
+			GasPremium: abi.NewTokenAmount(1),
+		}
+		expectSendEstimate(mockApi)
+		mockSrvcs.EXPECT().Send(gomock.Any(), gomock.Any()).Return(arbtCid, nil)
+		mockApi.EXPECT().ChainGetMessage(gomock.Any(), arbtCid).Return(msg, nil).AnyTimes()
+		mockApi.EXPECT().MpoolFeeLevel(gomock.Any(), arbtCid).Return(nil, nil)
+		mockApi.EXPECT().StateWaitMsg(gomock.Any(), arbtCid, gomock.Any()).Return(&lapi.MsgLookup{
+			Message: arbtCid,
+			Receipt: types.MessageReceipt{ExitCode: exitcode.ErrInsufficientFunds},
+		}, nil)
+		mockSrvcs.EXPECT().Close()
+
+		err := app.Run([]string{"lotus", "send", "--wait", to.String(), "1"})
+		require.Equal(t, ExitExecutionFailed, ExitStatus(err))
+	})
+}
+
+func TestWaitMsgExitStatus(t *testing.T) {
+	msg := &types.Message{
+		From:       mustAddr(address.NewIDAddress(2)),
+		To:         mustAddr(address.NewIDAddress(1)),
+		Value:      abi.NewTokenAmount(0),
+		GasFeeCap:  abi.NewTokenAmount(0),
+		GasPremium: abi.NewTokenAmount(0),
+	}
+
+	run := func(t *testing.T, wait func(ctx context.Context) (*lapi.MsgLookup, error), args ...string) error {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := mocks.NewMockFullNode(ctrl)
+		m.EXPECT().ChainGetMessage(gomock.Any(), msg.Cid()).Return(msg, nil).AnyTimes()
+		m.EXPECT().StateWaitMsg(gomock.Any(), msg.Cid(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, _, _, _, _ interface{}) (*lapi.MsgLookup, error) {
+				return wait(ctx)
+			})
+
+		app := ucli.NewApp()
+		app.Commands = ucli.Commands{StateWaitMsgCmd}
+		app.Metadata = map[string]interface{}{"testnode-full": m}
+		app.Writer = &bytes.Buffer{}
+		app.ErrWriter = &bytes.Buffer{}
+
+		return app.Run(append(append([]string{"lotus", "wait-msg"}, args...), msg.Cid().String()))
+	}
+
+	t.Run("execution-failed", func(t *testing.T) {
+		err := run(t, func(context.Context) (*lapi.MsgLookup, error) {
+			return &lapi.MsgLookup{
+				Message: msg.Cid(),
+				Receipt: types.MessageReceipt{ExitCode: exitcode.ErrForbidden},
+				TipSet:  types.EmptyTSK,
+			}, nil
+		})
+		require.Equal(t, ExitExecutionFailed, ExitStatus(err))
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		err := run(t, func(ctx context.Context) (*lapi.MsgLookup, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}, "--timeout", "10ms")
+		require.Equal(t, ExitTimeout, ExitStatus(err))
+	})
+}
@@ -2,9 +2,14 @@ package cli
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/urfave/cli/v2"
 	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/lib/tablewriter"
 )
 
 var LogCmd = &cli.Command{
@@ -12,6 +17,7 @@ var LogCmd = &cli.Command{
 	Usage: "Manage logging",
 	Subcommands: []*cli.Command{
 		LogList,
+		LogLevels,
 		LogSetLevel,
 	},
 }
@@ -41,6 +47,43 @@ var LogList = &cli.Command{
 	},
 }
 
+var LogLevels = &cli.Command{
+	Name:  "levels",
+	Usage: "List log systems with their current level and temporary overrides",
+	Action: func(cctx *cli.Context) error {
+		api, closer, err := GetAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := ReqContext(cctx)
+
+		systems, err := api.LogLevels(ctx)
+		if err != nil {
+			return err
+		}
+
+		tw := tablewriter.New(
+			tablewriter.Col("System"),
+			tablewriter.Col("Level"),
+			tablewriter.Col("Override"))
+
+		for _, s := range systems {
+			row := map[string]interface{}{
+				"System": s.Name,
+				"Level":  s.Level,
+			}
+			if s.Override != nil {
+				row["Override"] = fmt.Sprintf("reverts to %s in %s", s.Override.Baseline, time.Until(s.Override.Expires).Truncate(time.Second))
+			}
+			tw.Write(row)
+		}
+
+		return tw.Flush(cctx.App.Writer)
+	},
+}
+
 var LogSetLevel = &cli.Command{
 	Name:      "set-level",
 	Usage:     "Set log level",
@@ -51,6 +94,12 @@ var LogSetLevel = &cli.Command{
 
    eg) log set-level --system chain --system chainxchg debug
 
+   Systems can also be selected with a regular expression matching their
+   whole name, and the level set for a while only, reverting to the level
+   the systems had before once it elapses:
+
+   eg) log set-level --match 'chain.*' --for 15m debug
+
    Available Levels:
    debug
    info
@@ -69,6 +118,14 @@ var LogSetLevel = &cli.Command{
 			Usage: "limit to log system",
 			Value: &cli.StringSlice{},
 		},
+		&cli.StringFlag{
+			Name:  "match",
+			Usage: "limit to the log systems whose name matches this regular expression",
+		},
+		&cli.DurationFlag{
+			Name:  "for",
+			Usage: "set the level for this long only (e.g. 15m), then revert to the previous level",
+		},
 	},
 	Action: func(cctx *cli.Context) error {
 		api, closer, err := GetAPI(cctx)
@@ -82,7 +139,40 @@ var LogSetLevel = &cli.Command{
 			return fmt.Errorf("level is required")
 		}
 
+		level := cctx.Args().First()
 		systems := cctx.StringSlice("system")
+
+		if cctx.IsSet("match") || cctx.IsSet("for") {
+			if cctx.IsSet("match") && len(systems) > 0 {
+				return xerrors.Errorf("--match and --system can't be used together")
+			}
+			var matches []string
+			switch {
+			case cctx.IsSet("match"):
+				matches = []string{cctx.String("match")}
+			case len(systems) > 0:
+				for _, system := range systems {
+					matches = append(matches, regexp.QuoteMeta(system))
+				}
+			default:
+				matches = []string{".*"}
+			}
+
+			for _, match := range matches {
+				set, err := api.LogSetLevels(ctx, match, level, cctx.Duration("for"))
+				if err != nil {
+					return xerrors.Errorf("setting log level on %s: %w", match, err)
+				}
+				if cctx.IsSet("match") {
+					fmt.Fprintf(cctx.App.Writer, "Set %s on %s\n", level, strings.Join(set, ", "))
+				}
+			}
+			if cctx.IsSet("for") {
+				fmt.Fprintf(cctx.App.Writer, "Reverting in %s\n", cctx.Duration("for"))
+			}
+			return nil
+		}
+
 		if len(systems) == 0 {
 			var err error
 			systems, err = api.LogList(ctx)
@@ -92,7 +182,7 @@ var LogSetLevel = &cli.Command{
 		}
 
 		for _, system := range systems {
-			if err := api.LogSetLevel(ctx, system, cctx.Args().First()); err != nil {
+			if err := api.LogSetLevel(ctx, system, level); err != nil {
 				return xerrors.Errorf("setting log level on %s: %v", system, err)
 			}
 		}
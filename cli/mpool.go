@@ -1,11 +1,13 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	stdbig "math/big"
 	"sort"
 	"strconv"
+	"text/tabwriter"
 
 	cid "github.com/ipfs/go-cid"
 	"github.com/urfave/cli/v2"
@@ -16,7 +18,9 @@ import (
 	"github.com/filecoin-project/go-state-types/big"
 
 	lapi "github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/api/v0api"
 	"github.com/filecoin-project/lotus/build"
+	"github.com/filecoin-project/lotus/chain/actors/builtin"
 	"github.com/filecoin-project/lotus/chain/messagepool"
 	"github.com/filecoin-project/lotus/chain/types"
 	"github.com/filecoin-project/lotus/node/config"
@@ -31,12 +35,26 @@ var MpoolCmd = &cli.Command{
 		MpoolSub,
 		MpoolStat,
 		MpoolReplaceCmd,
+		MpoolCancelCmd,
+		MpoolFeeAdjustCmd,
 		MpoolFindCmd,
 		MpoolConfig,
 		MpoolGasPerfCmd,
+		MpoolFeeProjectionCmd,
+		MpoolPropagationCmd,
+		MpoolFeeHistoryCmd,
+		MpoolGasAccuracyCmd,
+		MpoolGasMarginCmd,
 	},
 }
 
+// pendingMessageJSON is the JSON of a signed message, with its local memo
+type pendingMessageJSON struct {
+	*types.RawSignedMessage
+	CID  cid.Cid
+	Memo string `json:",omitempty"`
+}
+
 var MpoolPending = &cli.Command{
 	Name:  "pending",
 	Usage: "Get pending messages",
@@ -84,9 +102,10 @@ var MpoolPending = &cli.Command{
 			froma = a
 		}
 
-		var filter map[address.Address]struct{}
-		if cctx.Bool("local") {
-			filter = map[address.Address]struct{}{}
+		// the local messages are shown with their memos
+		var local map[address.Address]struct{}
+		if cctx.Bool("local") || !cctx.Bool("cids") {
+			local = map[address.Address]struct{}{}
 
 			addrss, err := api.WalletList(ctx)
 			if err != nil {
@@ -94,7 +113,7 @@ var MpoolPending = &cli.Command{
 			}
 
 			for _, a := range addrss {
-				filter[a] = struct{}{}
+				local[a] = struct{}{}
 			}
 		}
 
@@ -104,10 +123,9 @@ var MpoolPending = &cli.Command{
 		}
 
 		for _, msg := range msgs {
-			if filter != nil {
-				if _, has := filter[msg.Message.From]; !has {
-					continue
-				}
+			_, isLocal := local[msg.Message.From]
+			if cctx.Bool("local") && !isLocal {
+				continue
 			}
 
 			if toa != address.Undef && msg.Message.To != toa {
@@ -120,7 +138,18 @@ var MpoolPending = &cli.Command{
 			if cctx.Bool("cids") {
 				fmt.Println(msg.Cid())
 			} else {
-				out, err := json.MarshalIndent(msg, "", "  ")
+				pm := pendingMessageJSON{RawSignedMessage: (*types.RawSignedMessage)(msg), CID: msg.Cid()}
+				if isLocal {
+					memo, err := api.MpoolGetMemo(ctx, msg.Cid())
+					if err != nil {
+						return xerrors.Errorf("getting memo of %s: %w", msg.Cid(), err)
+					}
+					if memo != nil {
+						pm.Memo = memo.Memo
+					}
+				}
+
+				out, err := json.MarshalIndent(pm, "", "  ")
 				if err != nil {
 					return err
 				}
@@ -213,6 +242,10 @@ var MpoolStat = &cli.Command{
 			Usage: "number of blocks to look back for minimum basefee",
 			Value: 60,
 		},
+		&cli.BoolFlag{
+			Name:  "lanes",
+			Usage: "print the occupancy of the protected lane, of local and priority addresses, and the remote lane",
+		},
 	},
 	Action: func(cctx *cli.Context) error {
 		api, closer, err := GetFullNodeAPI(cctx)
@@ -223,6 +256,20 @@ var MpoolStat = &cli.Command{
 
 		ctx := ReqContext(cctx)
 
+		if cctx.Bool("lanes") {
+			lanes, err := api.MpoolLanes(ctx)
+			if err != nil {
+				return err
+			}
+
+			tw := tabwriter.NewWriter(cctx.App.Writer, 2, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "Lane\tMessages\tSenders\tCapacity")
+			for _, l := range lanes {
+				fmt.Fprintf(tw, "%s\t%d\t%d\t%d\n", l.Name, l.Messages, l.Senders, l.Capacity)
+			}
+			return tw.Flush()
+		}
+
 		ts, err := api.ChainHead(ctx)
 		if err != nil {
 			return xerrors.Errorf("getting chain head: %w", err)
@@ -447,41 +494,21 @@ var MpoolReplaceCmd = &cli.Command{
 		}
 
 		if found == nil {
-			return fmt.Errorf("no pending message found from %s with nonce %d", from, nonce)
+			return noPendingMessage(ctx, api, from, nonce)
 		}
 
 		msg := found.Message
 
 		if cctx.Bool("auto") {
-			minRBF := messagepool.ComputeMinRBF(msg.GasPremium)
-
-			var mss *lapi.MessageSendSpec
-			if cctx.IsSet("max-fee") {
-				maxFee, err := types.BigFromString(cctx.String("max-fee"))
-				if err != nil {
-					return fmt.Errorf("parsing max-spend: %w", err)
-				}
-				mss = &lapi.MessageSendSpec{
-					MaxFee: maxFee,
-				}
-			}
-
-			// msg.GasLimit = 0 // TODO: need to fix the way we estimate gas limits to account for the messages already being in the mempool
-			msg.GasFeeCap = abi.NewTokenAmount(0)
-			msg.GasPremium = abi.NewTokenAmount(0)
-			retm, err := api.GasEstimateMessageGas(ctx, &msg, mss, types.EmptyTSK)
+			mss, err := maxFeeSendSpec(cctx)
 			if err != nil {
-				return fmt.Errorf("failed to estimate gas values: %w", err)
+				return err
 			}
 
-			msg.GasPremium = big.Max(retm.GasPremium, minRBF)
-			msg.GasFeeCap = big.Max(retm.GasFeeCap, msg.GasPremium)
-
-			mff := func() (abi.TokenAmount, error) {
-				return abi.TokenAmount(config.DefaultDefaultMaxFee), nil
+			// msg.GasLimit = 0 // TODO: need to fix the way we estimate gas limits to account for the messages already being in the mempool
+			if err := setReplacementGas(ctx, api, &msg, found.Message.GasPremium, mss); err != nil {
+				return err
 			}
-
-			messagepool.CapGasFee(mff, &msg, mss)
 		} else {
 			if cctx.IsSet("gas-limit") {
 				msg.GasLimit = cctx.Int64("gas-limit")
@@ -512,6 +539,188 @@ var MpoolReplaceCmd = &cli.Command{
 	},
 }
 
+var MpoolCancelCmd = &cli.Command{
+	Name:  "cancel",
+	Usage: "cancel a stuck message by replacing it with an empty self-send",
+	Description: `Replaces a pending message with a zero-value send from the sender to itself,
+   at the same nonce and with enough of a fee increase to replace it. Once the
+   self-send is included in a block the original message can never execute, as
+   its nonce is taken. Only the gas fees of the self-send are spent.`,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "max-fee",
+			Usage: "Spend up to X attoFIL for the cancellation message",
+		},
+		&cli.BoolFlag{
+			Name:  "really-do-it",
+			Usage: "send the cancellation message without asking for confirmation",
+		},
+	},
+	ArgsUsage: "<from nonce> | <message-cid>",
+	Action: func(cctx *cli.Context) error {
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := ReqContext(cctx)
+		afmt := NewAppFmt(cctx.App)
+
+		var from address.Address
+		var nonce uint64
+		switch cctx.Args().Len() {
+		case 1:
+			mcid, err := cid.Decode(cctx.Args().First())
+			if err != nil {
+				return err
+			}
+
+			msg, err := api.ChainGetMessage(ctx, mcid)
+			if err != nil {
+				return fmt.Errorf("could not find referenced message: %w", err)
+			}
+
+			from = msg.From
+			nonce = msg.Nonce
+		case 2:
+			from, err = address.NewFromString(cctx.Args().Get(0))
+			if err != nil {
+				return err
+			}
+
+			nonce, err = strconv.ParseUint(cctx.Args().Get(1), 10, 64)
+			if err != nil {
+				return err
+			}
+		default:
+			return cli.ShowCommandHelp(cctx, cctx.Command.Name)
+		}
+
+		pending, err := api.MpoolPending(ctx, types.EmptyTSK)
+		if err != nil {
+			return err
+		}
+
+		var found *types.SignedMessage
+		for _, p := range pending {
+			if p.Message.From == from && p.Message.Nonce == nonce {
+				found = p
+				break
+			}
+		}
+
+		if found == nil {
+			return noPendingMessage(ctx, api, from, nonce)
+		}
+
+		mss, err := maxFeeSendSpec(cctx)
+		if err != nil {
+			return err
+		}
+
+		msg := types.Message{
+			From:   found.Message.From,
+			To:     found.Message.From,
+			Nonce:  found.Message.Nonce,
+			Value:  abi.NewTokenAmount(0),
+			Method: builtin.MethodSend,
+		}
+		if err := setReplacementGas(ctx, api, &msg, found.Message.GasPremium, mss); err != nil {
+			return err
+		}
+		if msg.GasPremium.LessThan(messagepool.ComputeMinRBF(found.Message.GasPremium)) {
+			return fmt.Errorf("the max fee doesn't allow for a premium high enough to replace the message (needs at least %s attoFIL/gas)", messagepool.ComputeMinRBF(found.Message.GasPremium))
+		}
+
+		unit, err := feeUnit(cctx)
+		if err != nil {
+			return err
+		}
+
+		afmt.Printf("Cancelling message %s (from %s, nonce %d, to %s, value %s)\n",
+			found.Cid(), found.Message.From, found.Message.Nonce, found.Message.To, types.FIL(found.Message.Value))
+		afmt.Printf("by replacing it with an empty self-send at the same nonce:\n")
+		afmt.Printf("  gas limit: %d\n", msg.GasLimit)
+		afmt.Printf("  gas premium: %s/gas (was %s/gas)\n", unit.Format(msg.GasPremium), unit.Format(found.Message.GasPremium))
+		afmt.Printf("  gas fee cap: %s/gas (was %s/gas)\n", unit.Format(msg.GasFeeCap), unit.Format(found.Message.GasFeeCap))
+		afmt.Printf("  max fee: %s\n", unit.Format(msg.RequiredFunds()))
+		afmt.Println("Once the self-send is included the original message can't execute anymore.")
+
+		if !cctx.Bool("really-do-it") {
+			afmt.Print("\nSend cancellation message? (yes/no): ")
+			var yn string
+			if _, err := afmt.Scan(&yn); err != nil {
+				return err
+			}
+			if yn != "yes" {
+				return xerrors.Errorf("cancel: %w", ErrAbortedByUser)
+			}
+		}
+
+		smsg, err := api.WalletSignMessage(ctx, msg.From, &msg)
+		if err != nil {
+			return fmt.Errorf("failed to sign message: %w", err)
+		}
+
+		mcid, err := api.MpoolPush(ctx, smsg)
+		if err != nil {
+			return fmt.Errorf("failed to push cancellation message to mempool: %w", err)
+		}
+
+		afmt.Println("cancellation message cid:", mcid)
+		return nil
+	},
+}
+
+func maxFeeSendSpec(cctx *cli.Context) (*lapi.MessageSendSpec, error) {
+	if !cctx.IsSet("max-fee") {
+		return nil, nil
+	}
+
+	maxFee, err := types.BigFromString(cctx.String("max-fee"))
+	if err != nil {
+		return nil, fmt.Errorf("parsing max-spend: %w", err)
+	}
+	return &lapi.MessageSendSpec{
+		MaxFee: maxFee,
+	}, nil
+}
+
+// setReplacementGas estimates gas values for msg, raising the premium enough
+// to replace a pending message with the given premium
+func setReplacementGas(ctx context.Context, api v0api.FullNode, msg *types.Message, replacedPremium abi.TokenAmount, mss *lapi.MessageSendSpec) error {
+	minRBF := messagepool.ComputeMinRBF(replacedPremium)
+
+	msg.GasFeeCap = abi.NewTokenAmount(0)
+	msg.GasPremium = abi.NewTokenAmount(0)
+	retm, err := api.GasEstimateMessageGas(ctx, msg, mss, types.EmptyTSK)
+	if err != nil {
+		return fmt.Errorf("failed to estimate gas values: %w", err)
+	}
+
+	msg.GasLimit = retm.GasLimit
+	msg.GasPremium = big.Max(retm.GasPremium, minRBF)
+	msg.GasFeeCap = big.Max(retm.GasFeeCap, msg.GasPremium)
+
+	mff := func() (abi.TokenAmount, error) {
+		return abi.TokenAmount(config.DefaultDefaultMaxFee), nil
+	}
+
+	messagepool.CapGasFee(mff, msg, mss)
+	return nil
+}
+
+// noPendingMessage is the error when there's no pending message to replace,
+// telling apart the messages that were already mined
+func noPendingMessage(ctx context.Context, api v0api.FullNode, from address.Address, nonce uint64) error {
+	act, err := api.StateGetActor(ctx, from, types.EmptyTSK)
+	if err == nil && act.Nonce > nonce {
+		return xerrors.Errorf("message from %s with nonce %d: %w", from, nonce, ErrAlreadyMined)
+	}
+	return fmt.Errorf("no pending message found from %s with nonce %d", from, nonce)
+}
+
 var MpoolFindCmd = &cli.Command{
 	Name:  "find",
 	Usage: "find a message in the mempool",
@@ -712,11 +921,16 @@ var MpoolGasPerfCmd = &cli.Command{
 			return r
 		}
 
+		unit, err := feeUnit(cctx)
+		if err != nil {
+			return err
+		}
+
 		for _, m := range msgs {
 			gasReward := getGasReward(m)
 			gasPerf := getGasPerf(gasReward, m.Message.GasLimit)
 
-			fmt.Printf("%s\t%d\t%s\t%f\n", m.Message.From, m.Message.Nonce, gasReward, gasPerf)
+			fmt.Printf("%s\t%d\t%s\t%f\n", m.Message.From, m.Message.Nonce, unit.Format(gasReward), gasPerf)
 		}
 
 		return nil
@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	stdbig "math/big"
+	"sort"
+
+	"github.com/ipfs/go-cid"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/lotus/api/v0api"
+	"github.com/filecoin-project/lotus/chain/messagepool"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/lib/tablewriter"
+)
+
+var MpoolFeeAdjustCmd = &cli.Command{
+	Name:      "fee-adjust",
+	Usage:     "Replace the pending messages of an address at once, with higher fees",
+	ArgsUsage: "<address>",
+	Description: `Replaces the pending messages of the address, or the ones selected with
+   --nonce, either re-estimating their gas within --max-fee each, or
+   multiplying their fee cap and premium by --multiplier. Premiums are always
+   raised enough for the replacements to be accepted.
+
+   The replacements are shown with the fees they may spend in total, and
+   pushed once confirmed. All of them are signed before any is pushed, so a
+   signing failure replaces none.
+
+   Confirming needs an answer on stdin: without one, pass --really-do-it, or
+   use 'lotus mpool replace' to replace the messages one by one.`,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "max-fee",
+			Usage: "spend up to X attoFIL for each replacement, its gas being estimated again",
+		},
+		&cli.Float64Flag{
+			Name:  "multiplier",
+			Usage: "multiply the fee cap and premium of the messages by this, instead of estimating them again",
+		},
+		&cli.Int64SliceFlag{
+			Name:  "nonce",
+			Usage: "only replace the message with this nonce, can be repeated",
+		},
+		&cli.BoolFlag{
+			Name:  "really-do-it",
+			Usage: "push the replacements without asking for confirmation",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.Args().Len() != 1 {
+			return ShowHelp(cctx, fmt.Errorf("must pass the address the messages are from"))
+		}
+		from, err := address.NewFromString(cctx.Args().First())
+		if err != nil {
+			return err
+		}
+
+		mult := cctx.Float64("multiplier")
+		if cctx.IsSet("multiplier") {
+			if cctx.IsSet("max-fee") {
+				return xerrors.Errorf("--multiplier and --max-fee can't be used together")
+			}
+			if mult <= 1 {
+				return xerrors.Errorf("--multiplier must be above 1, was %f", mult)
+			}
+		}
+		mss, err := maxFeeSendSpec(cctx)
+		if err != nil {
+			return err
+		}
+
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := ReqContext(cctx)
+		afmt := NewAppFmt(cctx.App)
+
+		pending, err := pendingFrom(ctx, api, from, cctx.Int64Slice("nonce"))
+		if err != nil {
+			return err
+		}
+
+		head, err := api.ChainHead(ctx)
+		if err != nil {
+			return xerrors.Errorf("getting chain head: %w", err)
+		}
+		baseFee := head.Blocks()[0].ParentBaseFee
+
+		unit, err := feeUnit(cctx)
+		if err != nil {
+			return err
+		}
+
+		tw := tablewriter.New(
+			tablewriter.Col("Nonce"),
+			tablewriter.Col("To"),
+			tablewriter.Col("Method"),
+			tablewriter.Col("FeeCap"),
+			tablewriter.Col("NewFeeCap"),
+			tablewriter.Col("Premium"),
+			tablewriter.Col("NewPremium"),
+			tablewriter.Col("MaxFee"),
+			tablewriter.NewLineCol("Note"))
+
+		replacements := make([]*types.Message, 0, len(pending))
+		maxFees, value := big.Zero(), big.Zero()
+		for _, p := range pending {
+			msg := p.Message
+			if cctx.IsSet("multiplier") {
+				setMultipliedGas(&msg, mult)
+			} else if err := setReplacementGas(ctx, api, &msg, p.Message.GasPremium, mss); err != nil {
+				return xerrors.Errorf("nonce %d: %w", p.Message.Nonce, err)
+			}
+			if minRBF := messagepool.ComputeMinRBF(p.Message.GasPremium); msg.GasPremium.LessThan(minRBF) {
+				return xerrors.Errorf("nonce %d: the max fee doesn't allow for a premium high enough to replace the message (needs at least %s attoFIL/gas)", p.Message.Nonce, minRBF)
+			}
+
+			row := map[string]interface{}{
+				"Nonce":      msg.Nonce,
+				"To":         msg.To,
+				"Method":     msg.Method,
+				"FeeCap":     unit.Format(p.Message.GasFeeCap),
+				"NewFeeCap":  unit.Format(msg.GasFeeCap),
+				"Premium":    unit.Format(p.Message.GasPremium),
+				"NewPremium": unit.Format(msg.GasPremium),
+				"MaxFee":     unit.Format(msg.RequiredFunds()),
+			}
+			if msg.GasFeeCap.LessThan(baseFee) {
+				row["Note"] = "fee cap still below the base fee"
+			}
+			tw.Write(row)
+
+			replacements = append(replacements, &msg)
+			maxFees = big.Add(maxFees, msg.RequiredFunds())
+			value = big.Add(value, msg.Value)
+		}
+
+		afmt.Printf("Base fee: %s/gas\n\n", unit.Format(baseFee))
+		if err := tw.Flush(cctx.App.Writer); err != nil {
+			return err
+		}
+		afmt.Printf("\nThe %d replacements may spend up to %s in fees, besides the %s they send\n", len(replacements), unit.Format(maxFees), types.FIL(value))
+
+		if !cctx.Bool("really-do-it") {
+			afmt.Printf("\nReplace %d messages? (yes/no): ", len(replacements))
+			var yn string
+			if _, err := afmt.Scan(&yn); err != nil {
+				return xerrors.Errorf("reading confirmation: %w; without an interactive terminal pass --really-do-it, or use 'lotus mpool replace' to replace the messages one by one", err)
+			}
+			if yn != "yes" {
+				return xerrors.Errorf("fee-adjust: %w", ErrAbortedByUser)
+			}
+		}
+
+		signed := make([]*types.SignedMessage, 0, len(replacements))
+		for _, msg := range replacements {
+			smsg, err := api.WalletSignMessage(ctx, msg.From, msg)
+			if err != nil {
+				return xerrors.Errorf("signing the replacement of nonce %d, no message was replaced: %w", msg.Nonce, err)
+			}
+			signed = append(signed, smsg)
+		}
+
+		replaced := map[uint64]cid.Cid{}
+		var pushErr error
+		for _, smsg := range signed {
+			c, err := api.MpoolPush(ctx, smsg)
+			if err != nil {
+				pushErr = xerrors.Errorf("pushing the replacement of nonce %d: %w", smsg.Message.Nonce, err)
+				break
+			}
+			replaced[smsg.Message.Nonce] = c
+		}
+
+		for _, smsg := range signed {
+			if c, ok := replaced[smsg.Message.Nonce]; ok {
+				afmt.Printf("nonce %d: replaced by %s\n", smsg.Message.Nonce, c)
+			} else {
+				afmt.Printf("nonce %d: not replaced\n", smsg.Message.Nonce)
+			}
+		}
+		return pushErr
+	},
+}
+
+// pendingFrom returns the pending messages of the address, sorted by nonce,
+// only those with the given nonces if any
+func pendingFrom(ctx context.Context, api v0api.FullNode, from address.Address, nonces []int64) ([]*types.SignedMessage, error) {
+	senders := map[address.Address]bool{from: true}
+	if from.Protocol() == address.ID {
+		// pending messages are from the key addresses of accounts
+		if key, err := api.StateAccountKey(ctx, from, types.EmptyTSK); err == nil {
+			senders[key] = true
+		}
+	}
+	want := map[uint64]bool{}
+	for _, n := range nonces {
+		want[uint64(n)] = true
+	}
+
+	all, err := api.MpoolPending(ctx, types.EmptyTSK)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*types.SignedMessage
+	for _, p := range all {
+		if senders[p.Message.From] && (len(want) == 0 || want[p.Message.Nonce]) {
+			out = append(out, p)
+		}
+	}
+	if len(out) == 0 {
+		if len(want) > 0 {
+			return nil, xerrors.Errorf("no pending messages from %s with the given nonces", from)
+		}
+		return nil, xerrors.Errorf("no pending messages from %s", from)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Message.Nonce < out[j].Message.Nonce
+	})
+	return out, nil
+}
+
+// setMultipliedGas multiplies the fee cap and premium of msg, raising the
+// premium enough to replace it
+func setMultipliedGas(msg *types.Message, mult float64) {
+	msg.GasPremium = big.Max(mulFee(msg.GasPremium, mult), messagepool.ComputeMinRBF(msg.GasPremium))
+	msg.GasFeeCap = big.Max(mulFee(msg.GasFeeCap, mult), msg.GasPremium)
+}
+
+func mulFee(fee abi.TokenAmount, mult float64) abi.TokenAmount {
+	f := new(stdbig.Float).SetInt(fee.Int)
+	f.Mul(f, stdbig.NewFloat(mult))
+	i, _ := f.Int(nil)
+	return big.NewFromGo(i)
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/ipfs/go-cid"
+	"github.com/urfave/cli/v2"
+
+	lapi "github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/api/v0api"
+	"github.com/filecoin-project/lotus/chain/messagepool"
+)
+
+var MpoolFeeHistoryCmd = &cli.Command{
+	Name:  "fee-history",
+	Usage: "Show how fast the messages sent from this node landed, by fee level",
+	Description: `The fee level of a message is the ratio of its premium to the premium
+   estimate when it was pushed. Only messages pushed with MpoolPushMessage,
+   as 'lotus send' does, are counted, over the last week.
+
+   The history is off by default, turn it on by setting FeeHistory in
+   'lotus mpool config'.`,
+	Action: func(cctx *cli.Context) error {
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := ReqContext(cctx)
+
+		cfg, err := api.MpoolGetConfig(ctx)
+		if err != nil {
+			return err
+		}
+		if !cfg.FeeHistory {
+			fmt.Fprintln(cctx.App.ErrWriter, "The fee history is off, new sends aren't recorded. Set FeeHistory in 'lotus mpool config' to turn it on.")
+		}
+
+		levels, err := api.MpoolFeeHistory(ctx)
+		if err != nil {
+			return err
+		}
+
+		tw := tabwriter.NewWriter(cctx.App.Writer, 2, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "Fee level\tSends\tQuick\tSlow\tStalled\tLanded quickly")
+		for _, l := range levels {
+			rate := "-"
+			if l.Sends >= messagepool.FeeHistoryMinSends {
+				rate = fmt.Sprintf("%d%%", l.Quick*100/l.Sends)
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%s\n", feeLevelName(l), l.Sends, l.Quick, l.Slow, l.Stalled, rate)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		if len(levels) > 0 {
+			fmt.Fprintf(cctx.App.Writer, "Quick: landed within %d epochs. Stalled: not landed, or replaced. Levels with fewer than %d sends have no rate.\n", levels[0].QuickEpochs, messagepool.FeeHistoryMinSends)
+		}
+		return nil
+	},
+}
+
+// feeLevelName names the level by its premium ratios to the estimate
+func feeLevelName(l lapi.FeeLevelStats) string {
+	switch {
+	case l.MinRatio == 0:
+		return fmt.Sprintf("below %gx estimate", l.MaxRatio)
+	case l.MaxRatio == 0:
+		return fmt.Sprintf("%gx estimate and above", l.MinRatio)
+	default:
+		return fmt.Sprintf("%g-%gx estimate", l.MinRatio, l.MaxRatio)
+	}
+}
+
+// printFeeLevelHistory shows how fast messages at the fee level of the sent
+// message landed, if the node has enough history of the level
+func printFeeLevelHistory(ctx context.Context, cctx *cli.Context, api v0api.FullNode, msg cid.Cid) {
+	l, err := api.MpoolFeeLevel(ctx, msg)
+	if err != nil {
+		log.Warnf("getting fee history: %s", err)
+		return
+	}
+	if l == nil {
+		return
+	}
+	fmt.Fprintf(cctx.App.ErrWriter, "Fees at this level (%s) landed within %d epochs %d%% of the time recently, over %d sends\n",
+		feeLevelName(*l), l.QuickEpochs, l.Quick*100/l.Sends, l.Sends)
+}
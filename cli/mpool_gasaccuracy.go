@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/filecoin-project/go-state-types/big"
+
+	lapi "github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+var MpoolGasAccuracyCmd = &cli.Command{
+	Name:  "gas-accuracy",
+	Usage: "Compare the gas used by the messages sent from this node with their gas estimate",
+	Description: `For each method of each actor family, shows how much of the gas estimate
+   the messages sent over the last week used, as the median, 90th percentile
+   and highest ratio of the gas used to the estimate, along with the margin
+   the estimates are multiplied by. A margin below the highest ratio would
+   have run messages out of gas, a margin far above it overpays.
+
+   Only messages pushed with MpoolPushMessage, as 'lotus send' does, are
+   counted. Set the margins with 'lotus mpool gas-margin', or GasLimitMargins
+   in the Fees section of the node config.`,
+	Action: func(cctx *cli.Context) error {
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		stats, err := api.MpoolGasAccuracy(ReqContext(cctx))
+		if err != nil {
+			return err
+		}
+		if len(stats) == 0 {
+			fmt.Fprintln(cctx.App.Writer, "No messages sent from this node executed yet")
+			return nil
+		}
+
+		oog, oogFees := 0, big.Zero()
+		tw := tabwriter.NewWriter(cctx.App.Writer, 2, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "Actor\tMethod\tExecuted\tEstimated\tUsed p50\tUsed p90\tUsed max\tMargin\tOut of gas")
+		for _, s := range stats {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%s\t%s\t%s\t%.2f\t%d\n", gasAccuracyFamily(s), s.Method, s.Executed, s.Estimated,
+				gasUsedRatio(s, s.UsedP50), gasUsedRatio(s, s.UsedP90), gasUsedRatio(s, s.UsedMax), s.Margin, s.OutOfGas)
+			oog += s.OutOfGas
+			oogFees = big.Add(oogFees, s.OutOfGasFees)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+
+		if oog > 0 {
+			fmt.Fprintf(cctx.App.ErrWriter, "WARNING: %d messages ran out of gas, burning up to %s in fees\n", oog, types.FIL(oogFees))
+		}
+		return nil
+	},
+}
+
+func gasAccuracyFamily(s lapi.GasAccuracyStats) string {
+	if s.Family == "" {
+		return "<unknown>"
+	}
+	return s.Family
+}
+
+func gasUsedRatio(s lapi.GasAccuracyStats, r float64) string {
+	if s.Estimated == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", r)
+}
+
+var MpoolGasMarginCmd = &cli.Command{
+	Name:      "gas-margin",
+	Usage:     "List or set the margins the gas estimates of messages are multiplied by",
+	ArgsUsage: "[<actor family>[/<method number>] <margin>]",
+	Description: `The margin of a method, as in 'storageminer/5', goes before the margin of
+   its actor family, as in 'storageminer', and GasLimitOverestimation is used
+   for the others. The margins set here are kept in the mpool config and go
+   before the GasLimitMargins of the node config.
+
+   Use 'lotus mpool gas-accuracy' to see how much of the estimates messages use.`,
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "unset",
+			Usage: "remove the margin of the actor family or method",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := ReqContext(cctx)
+
+		cfg, err := api.MpoolGetConfig(ctx)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case cctx.Args().Len() == 0 && !cctx.Bool("unset"):
+			keys := make([]string, 0, len(cfg.GasLimitMargins))
+			for k := range cfg.GasLimitMargins {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+
+			tw := tabwriter.NewWriter(cctx.App.Writer, 2, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "Actor/method\tMargin")
+			fmt.Fprintf(tw, "default\t%.2f\n", cfg.GasLimitOverestimation)
+			for _, k := range keys {
+				fmt.Fprintf(tw, "%s\t%.2f\n", k, cfg.GasLimitMargins[k])
+			}
+			return tw.Flush()
+		case cctx.Bool("unset"):
+			if cctx.Args().Len() != 1 {
+				return ShowHelp(cctx, fmt.Errorf("expected the actor family or method to unset"))
+			}
+			delete(cfg.GasLimitMargins, cctx.Args().First())
+		default:
+			if cctx.Args().Len() != 2 {
+				return ShowHelp(cctx, fmt.Errorf("expected the actor family or method and its margin"))
+			}
+			margin, err := strconv.ParseFloat(cctx.Args().Get(1), 64)
+			if err != nil {
+				return ShowHelp(cctx, fmt.Errorf("parsing margin: %w", err))
+			}
+			if cfg.GasLimitMargins == nil {
+				cfg.GasLimitMargins = map[string]float64{}
+			}
+			cfg.GasLimitMargins[cctx.Args().First()] = margin
+		}
+
+		return api.MpoolSetConfig(ctx, cfg)
+	},
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/api/client"
+	"github.com/filecoin-project/lotus/chain/types"
+	cliutil "github.com/filecoin-project/lotus/cli/util"
+)
+
+var MpoolPropagationCmd = &cli.Command{
+	Name:      "propagation",
+	Usage:     "Diagnose whether a message published by this node propagated over the network",
+	ArgsUsage: "[message cid]",
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "probe",
+			Usage: "API info (token:multiaddr) of another node to check for the message, can be repeated",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.Args().Len() != 1 {
+			return ShowHelp(cctx, fmt.Errorf("must pass message cid"))
+		}
+
+		mc, err := cid.Decode(cctx.Args().First())
+		if err != nil {
+			return xerrors.Errorf("parsing message cid: %w", err)
+		}
+
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := ReqContext(cctx)
+		afmt := NewAppFmt(cctx.App)
+
+		prop, err := api.MpoolPropagation(ctx, mc)
+		if err != nil {
+			return err
+		}
+
+		if !prop.Tracked {
+			afmt.Println("Gossip:    not published by this node recently, no gossip information")
+		} else {
+			afmt.Printf("Published: %d times, first %s ago, last %s ago\n", prop.Publishes,
+				time.Since(prop.FirstPublished).Truncate(time.Second), time.Since(prop.LastPublished).Truncate(time.Second))
+			afmt.Printf("Sent to:   %d peers\n", prop.SentToPeers)
+			if prop.EchoPeers > 0 {
+				afmt.Printf("Re-gossip: %d peers had the message, first after %s\n", prop.EchoPeers,
+					prop.FirstEcho.Sub(prop.FirstPublished).Truncate(time.Millisecond))
+			} else {
+				afmt.Println("Re-gossip: no peer sent or advertised the message back")
+			}
+		}
+
+		seenBy := 0
+		for _, p := range cctx.StringSlice("probe") {
+			ainfo := cliutil.ParseApiInfo(p)
+			addr, err := ainfo.DialArgs("v0")
+			if err != nil {
+				return xerrors.Errorf("parsing probe api info: %w", err)
+			}
+
+			papi, pcloser, err := client.NewFullNodeRPCV0(ctx, addr, ainfo.AuthHeader())
+			if err != nil {
+				afmt.Printf("Probe %s: connecting: %s\n", addr, err)
+				continue
+			}
+
+			// messages received from the network are stored with the mempool
+			if _, err := papi.ChainGetMessage(ctx, mc); err != nil {
+				afmt.Printf("Probe %s: message not seen\n", addr)
+			} else {
+				afmt.Printf("Probe %s: message seen\n", addr)
+				seenBy++
+			}
+			pcloser()
+		}
+
+		afmt.Printf("Mempool:   ")
+		if prop.InMpool {
+			afmt.Printf("pending, premium %s ranks %d of %d pending messages\n",
+				types.FIL(prop.GasPremium), prop.PremiumRank, prop.PendingMessages)
+		} else {
+			afmt.Println("not pending")
+		}
+
+		propagated := prop.EchoPeers > 0 || seenBy > 0
+		switch {
+		case propagated && prop.InMpool && prop.GasFeeCap.LessThan(prop.BaseFee):
+			afmt.Printf("Verdict:   propagated but not selected: fee cap %s is below the base fee %s\n",
+				types.FIL(prop.GasFeeCap), types.FIL(prop.BaseFee))
+		case propagated && prop.InMpool:
+			afmt.Println("Verdict:   propagated but not selected yet: block producers pick the messages with the highest premiums first")
+		case propagated:
+			afmt.Println("Verdict:   propagated")
+		case !prop.Tracked:
+			afmt.Println("Verdict:   unknown, pass --probe to check other nodes")
+		case prop.SentToPeers == 0:
+			afmt.Println("Verdict:   not propagated: the message wasn't sent to any peer, check the node connectivity")
+		default:
+			afmt.Println("Verdict:   not propagated: no peer confirmed receiving the message")
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	ucli "github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/crypto"
+
+	"github.com/filecoin-project/lotus/api/mocks"
+	"github.com/filecoin-project/lotus/chain/actors/builtin"
+	"github.com/filecoin-project/lotus/chain/messagepool"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/chain/types/mock"
+)
+
+func TestMpoolCancel(t *testing.T) {
+	from := mustAddr(address.NewIDAddress(1000))
+	pending := &types.SignedMessage{
+		Message: types.Message{
+			From:       from,
+			To:         mustAddr(address.NewIDAddress(2000)),
+			Nonce:      5,
+			Value:      types.NewInt(1000),
+			GasLimit:   10000,
+			GasFeeCap:  abi.NewTokenAmount(200),
+			GasPremium: abi.NewTokenAmount(100),
+		},
+		Signature: crypto.Signature{Type: crypto.SigTypeSecp256k1},
+	}
+
+	run := func(t *testing.T, input string, expect func(m *mocks.MockFullNode)) (string, error) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := mocks.NewMockFullNode(ctrl)
+		m.EXPECT().MpoolPending(gomock.Any(), types.EmptyTSK).Return([]*types.SignedMessage{pending}, nil)
+		m.EXPECT().GasEstimateMessageGas(gomock.Any(), gomock.Any(), gomock.Any(), types.EmptyTSK).
+			DoAndReturn(func(_, msg, _, _ interface{}) (*types.Message, error) {
+				out := *msg.(*types.Message)
+				out.GasLimit = 500
+				out.GasPremium = abi.NewTokenAmount(110)
+				out.GasFeeCap = abi.NewTokenAmount(300)
+				return &out, nil
+			})
+		if expect != nil {
+			expect(m)
+		}
+
+		app := ucli.NewApp()
+		app.Commands = ucli.Commands{MpoolCancelCmd}
+		app.Metadata = map[string]interface{}{
+			"testnode-full": m,
+			"stdin":         strings.NewReader(input),
+		}
+		buf := &bytes.Buffer{}
+		app.Writer = buf
+
+		err := app.Run([]string{"lotus", "cancel", from.String(), "5"})
+		return buf.String(), err
+	}
+
+	t.Run("aborted", func(t *testing.T) {
+		out, err := run(t, "no\n", nil)
+		require.Error(t, err)
+		require.Contains(t, out, pending.Cid().String())
+	})
+
+	t.Run("confirmed", func(t *testing.T) {
+		signed := &types.SignedMessage{Signature: crypto.Signature{Type: crypto.SigTypeSecp256k1}}
+
+		_, err := run(t, "yes\n", func(m *mocks.MockFullNode) {
+			m.EXPECT().WalletSignMessage(gomock.Any(), from, gomock.Any()).
+				DoAndReturn(func(_, _, msg interface{}) (*types.SignedMessage, error) {
+					cancel := msg.(*types.Message)
+					require.Equal(t, from, cancel.To)
+					require.Equal(t, uint64(5), cancel.Nonce)
+					require.Equal(t, builtin.MethodSend, cancel.Method)
+					require.True(t, cancel.Value.IsZero())
+					require.EqualValues(t, 500, cancel.GasLimit)
+					// raised to the minimum replace-by-fee premium
+					require.Equal(t, messagepool.ComputeMinRBF(pending.Message.GasPremium), cancel.GasPremium)
+					require.Equal(t, abi.NewTokenAmount(300), cancel.GasFeeCap)
+
+					signed.Message = *cancel
+					return signed, nil
+				})
+			m.EXPECT().MpoolPush(gomock.Any(), signed).Return(arbtCid, nil)
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestMpoolFeeAdjust(t *testing.T) {
+	from := mustAddr(address.NewIDAddress(1000))
+	msg := func(from address.Address, nonce uint64, feeCap, premium int64) *types.SignedMessage {
+		return &types.SignedMessage{
+			Message: types.Message{
+				From:       from,
+				To:         mustAddr(address.NewIDAddress(2000)),
+				Nonce:      nonce,
+				Value:      types.NewInt(1000),
+				GasLimit:   10000,
+				GasFeeCap:  abi.NewTokenAmount(feeCap),
+				GasPremium: abi.NewTokenAmount(premium),
+			},
+			Signature: crypto.Signature{Type: crypto.SigTypeSecp256k1},
+		}
+	}
+	pending := []*types.SignedMessage{
+		msg(from, 6, 200, 100),
+		msg(mustAddr(address.NewIDAddress(1001)), 5, 200, 100),
+		msg(from, 5, 40, 20),
+	}
+	head := mock.TipSet(mock.MkBlock(nil, 1, 1))
+
+	run := func(t *testing.T, expect func(m *mocks.MockFullNode), args ...string) (string, error) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := mocks.NewMockFullNode(ctrl)
+		m.EXPECT().StateAccountKey(gomock.Any(), from, types.EmptyTSK).Return(from, nil)
+		m.EXPECT().MpoolPending(gomock.Any(), types.EmptyTSK).Return(pending, nil)
+		m.EXPECT().ChainHead(gomock.Any()).Return(head, nil)
+		if expect != nil {
+			expect(m)
+		}
+
+		app := ucli.NewApp()
+		app.Commands = ucli.Commands{MpoolFeeAdjustCmd}
+		app.Metadata = map[string]interface{}{
+			"testnode-full": m,
+			"stdin":         strings.NewReader(""),
+		}
+		buf := &bytes.Buffer{}
+		app.Writer = buf
+
+		err := app.Run(append([]string{"lotus", "fee-adjust"}, append(args, from.String())...))
+		return buf.String(), err
+	}
+
+	t.Run("no-terminal", func(t *testing.T) {
+		out, err := run(t, nil, "--multiplier", "2")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "--really-do-it")
+		require.Contains(t, out, "fee cap still below the base fee")
+	})
+
+	t.Run("signing-failed", func(t *testing.T) {
+		out, err := run(t, func(m *mocks.MockFullNode) {
+			gomock.InOrder(
+				m.EXPECT().WalletSignMessage(gomock.Any(), from, gomock.Any()).Return(&types.SignedMessage{}, nil),
+				m.EXPECT().WalletSignMessage(gomock.Any(), from, gomock.Any()).Return(nil, xerrors.Errorf("locked")),
+			)
+		}, "--multiplier", "2", "--really-do-it")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "nonce 6, no message was replaced")
+		require.NotContains(t, out, "replaced by")
+	})
+
+	t.Run("replaced", func(t *testing.T) {
+		var signed []*types.Message
+		out, err := run(t, func(m *mocks.MockFullNode) {
+			m.EXPECT().WalletSignMessage(gomock.Any(), from, gomock.Any()).
+				DoAndReturn(func(_, _, msg interface{}) (*types.SignedMessage, error) {
+					signed = append(signed, msg.(*types.Message))
+					return &types.SignedMessage{Message: *msg.(*types.Message)}, nil
+				}).Times(2)
+			m.EXPECT().MpoolPush(gomock.Any(), gomock.Any()).Return(arbtCid, nil).Times(2)
+		}, "--multiplier", "2", "--really-do-it")
+		require.NoError(t, err)
+
+		require.Len(t, signed, 2)
+		require.EqualValues(t, 5, signed[0].Nonce)
+		// raised to the minimum replace-by-fee premium if doubling isn't enough
+		require.Equal(t, big.Max(abi.NewTokenAmount(40), messagepool.ComputeMinRBF(abi.NewTokenAmount(20))), signed[0].GasPremium)
+		require.Equal(t, abi.NewTokenAmount(80), signed[0].GasFeeCap)
+		require.EqualValues(t, 6, signed[1].Nonce)
+		require.Equal(t, abi.NewTokenAmount(400), signed[1].GasFeeCap)
+		require.Contains(t, out, "nonce 5: replaced by "+arbtCid.String())
+		require.Contains(t, out, "nonce 6: replaced by "+arbtCid.String())
+	})
+}
@@ -30,7 +30,6 @@ import (
 	msig2 "github.com/filecoin-project/specs-actors/v2/actors/builtin/multisig"
 
 	"github.com/filecoin-project/lotus/blockstore"
-	"github.com/filecoin-project/lotus/build"
 	"github.com/filecoin-project/lotus/chain/actors/adt"
 	"github.com/filecoin-project/lotus/chain/actors/builtin/multisig"
 	"github.com/filecoin-project/lotus/chain/types"
@@ -42,9 +41,9 @@ var multisigCmd = &cli.Command{
 	Flags: []cli.Flag{
 		&cli.IntFlag{
 			Name:  "confidence",
-			Usage: "number of block confirmations to wait for",
-			Value: int(build.MessageConfidence),
+			Usage: "number of block confirmations to wait for (default: by value at risk, see --confidence-policy)",
 		},
+		confidencePolicyFlag,
 	},
 	Subcommands: []*cli.Command{
 		msigCreateCmd,
@@ -152,7 +151,7 @@ var msigCreateCmd = &cli.Command{
 		}
 
 		// wait for it to get mined into a block
-		wait, err := api.StateWaitMsg(ctx, msgCid, uint64(cctx.Int("confidence")))
+		wait, err := waitMsgConfidence(ctx, cctx, api, msgCid)
 		if err != nil {
 			return err
 		}
@@ -433,7 +432,7 @@ var msigProposeCmd = &cli.Command{
 
 		fmt.Println("send proposal in message: ", msgCid)
 
-		wait, err := api.StateWaitMsg(ctx, msgCid, uint64(cctx.Int("confidence")))
+		wait, err := waitMsgConfidence(ctx, cctx, api, msgCid)
 		if err != nil {
 			return err
 		}
@@ -566,7 +565,7 @@ var msigApproveCmd = &cli.Command{
 
 		fmt.Println("sent approval in message: ", msgCid)
 
-		wait, err := api.StateWaitMsg(ctx, msgCid, uint64(cctx.Int("confidence")))
+		wait, err := waitMsgConfidence(ctx, cctx, api, msgCid)
 		if err != nil {
 			return err
 		}
@@ -637,7 +636,7 @@ var msigRemoveProposeCmd = &cli.Command{
 
 		fmt.Println("sent remove proposal in message: ", msgCid)
 
-		wait, err := api.StateWaitMsg(ctx, msgCid, uint64(cctx.Int("confidence")))
+		wait, err := waitMsgConfidence(ctx, cctx, api, msgCid)
 		if err != nil {
 			return err
 		}
@@ -715,7 +714,7 @@ var msigAddProposeCmd = &cli.Command{
 
 		fmt.Fprintln(cctx.App.Writer, "sent add proposal in message: ", msgCid)
 
-		wait, err := api.StateWaitMsg(ctx, msgCid, uint64(cctx.Int("confidence")))
+		wait, err := waitMsgConfidence(ctx, cctx, api, msgCid)
 		if err != nil {
 			return err
 		}
@@ -797,7 +796,7 @@ var msigAddApproveCmd = &cli.Command{
 
 		fmt.Println("sent add approval in message: ", msgCid)
 
-		wait, err := api.StateWaitMsg(ctx, msgCid, uint64(cctx.Int("confidence")))
+		wait, err := waitMsgConfidence(ctx, cctx, api, msgCid)
 		if err != nil {
 			return err
 		}
@@ -874,7 +873,7 @@ var msigAddCancelCmd = &cli.Command{
 
 		fmt.Println("sent add cancellation in message: ", msgCid)
 
-		wait, err := api.StateWaitMsg(ctx, msgCid, uint64(cctx.Int("confidence")))
+		wait, err := waitMsgConfidence(ctx, cctx, api, msgCid)
 		if err != nil {
 			return err
 		}
@@ -946,7 +945,7 @@ var msigSwapProposeCmd = &cli.Command{
 
 		fmt.Println("sent swap proposal in message: ", msgCid)
 
-		wait, err := api.StateWaitMsg(ctx, msgCid, uint64(cctx.Int("confidence")))
+		wait, err := waitMsgConfidence(ctx, cctx, api, msgCid)
 		if err != nil {
 			return err
 		}
@@ -1028,7 +1027,7 @@ var msigSwapApproveCmd = &cli.Command{
 
 		fmt.Println("sent swap approval in message: ", msgCid)
 
-		wait, err := api.StateWaitMsg(ctx, msgCid, uint64(cctx.Int("confidence")))
+		wait, err := waitMsgConfidence(ctx, cctx, api, msgCid)
 		if err != nil {
 			return err
 		}
@@ -1105,7 +1104,7 @@ var msigSwapCancelCmd = &cli.Command{
 
 		fmt.Println("sent swap cancellation in message: ", msgCid)
 
-		wait, err := api.StateWaitMsg(ctx, msgCid, uint64(cctx.Int("confidence")))
+		wait, err := waitMsgConfidence(ctx, cctx, api, msgCid)
 		if err != nil {
 			return err
 		}
@@ -1192,7 +1191,7 @@ var msigLockProposeCmd = &cli.Command{
 
 		fmt.Println("sent lock proposal in message: ", msgCid)
 
-		wait, err := api.StateWaitMsg(ctx, msgCid, uint64(cctx.Int("confidence")))
+		wait, err := waitMsgConfidence(ctx, cctx, api, msgCid)
 		if err != nil {
 			return err
 		}
@@ -1289,7 +1288,7 @@ var msigLockApproveCmd = &cli.Command{
 
 		fmt.Println("sent lock approval in message: ", msgCid)
 
-		wait, err := api.StateWaitMsg(ctx, msgCid, uint64(cctx.Int("confidence")))
+		wait, err := waitMsgConfidence(ctx, cctx, api, msgCid)
 		if err != nil {
 			return err
 		}
@@ -1381,7 +1380,7 @@ var msigLockCancelCmd = &cli.Command{
 
 		fmt.Println("sent lock cancellation in message: ", msgCid)
 
-		wait, err := api.StateWaitMsg(ctx, msgCid, uint64(cctx.Int("confidence")))
+		wait, err := waitMsgConfidence(ctx, cctx, api, msgCid)
 		if err != nil {
 			return err
 		}
@@ -1518,7 +1517,7 @@ var msigProposeThresholdCmd = &cli.Command{
 
 		fmt.Println("sent change threshold proposal in message: ", msgCid)
 
-		wait, err := api.StateWaitMsg(ctx, msgCid, uint64(cctx.Int("confidence")))
+		wait, err := waitMsgConfidence(ctx, cctx, api, msgCid)
 		if err != nil {
 			return err
 		}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -32,6 +33,7 @@ var NetCmd = &cli.Command{
 		NetListen,
 		NetId,
 		NetFindPeer,
+		NetFindProviders,
 		NetScores,
 		NetReachability,
 		NetBandwidthCmd,
@@ -266,8 +268,20 @@ var NetId = &cli.Command{
 
 var NetFindPeer = &cli.Command{
 	Name:      "findpeer",
+	Aliases:   []string{"find-peer"},
 	Usage:     "Find the addresses of a given peerID",
 	ArgsUsage: "[peerId]",
+	Description: `Without --via-dht, peers the node is or was connected to are answered
+   from its peerstore, which may be out of date.`,
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "via-dht",
+			Usage: "look the peer up in the DHT, not in the peerstore",
+		},
+		netTimeoutFlag,
+		netDialFlag,
+		netJSONFlag,
+	},
 	Action: func(cctx *cli.Context) error {
 		if cctx.NArg() != 1 {
 			fmt.Println("Usage: findpeer [peer ID]")
@@ -287,13 +301,26 @@ var NetFindPeer = &cli.Command{
 
 		ctx := ReqContext(cctx)
 
-		addrs, err := api.NetFindPeer(ctx, pid)
-
+		qctx, cancel := context.WithTimeout(ctx, cctx.Duration(netTimeoutFlag.Name))
+		var addrs peer.AddrInfo
+		if cctx.Bool("via-dht") {
+			addrs, err = api.NetFindPeerDHT(qctx, pid)
+		} else {
+			addrs, err = api.NetFindPeer(qctx, pid)
+		}
+		cancel()
 		if err != nil {
 			return err
 		}
 
-		fmt.Println(addrs)
+		r, err := newPeerReport(ctx, cctx, api, addrs)
+		if err != nil {
+			return err
+		}
+		if cctx.Bool(netJSONFlag.Name) {
+			return printJSON(cctx, r)
+		}
+		r.print(cctx)
 		return nil
 	},
 }
@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	atypes "github.com/filecoin-project/lotus/api"
+)
+
+// The flags shared by the commands looking peers up
+var (
+	netTimeoutFlag = &cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "timeout of each query, the lookup and then the dials",
+		Value: 30 * time.Second,
+	}
+	netDialFlag = &cli.BoolFlag{
+		Name:  "dial",
+		Usage: "try to connect to each address found, and report which work",
+	}
+	netJSONFlag = &cli.BoolFlag{
+		Name:  "json",
+		Usage: "print the results as JSON",
+	}
+)
+
+var NetFindProviders = &cli.Command{
+	Name:      "find-providers",
+	Usage:     "Find the providers of a piece or payload CID in the DHT",
+	ArgsUsage: "[cid]",
+	Description: `DHT responses don't carry the age of provider records, the time into
+   the lookup each provider was found at is printed instead.`,
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:  "count",
+			Usage: "maximum number of providers to find",
+			Value: 20,
+		},
+		netTimeoutFlag,
+		netDialFlag,
+		netJSONFlag,
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() != 1 {
+			return ShowHelp(cctx, fmt.Errorf("expected a CID"))
+		}
+		c, err := cid.Parse(cctx.Args().First())
+		if err != nil {
+			return ShowHelp(cctx, fmt.Errorf("parsing CID: %w", err))
+		}
+
+		api, closer, err := GetAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := ReqContext(cctx)
+
+		qctx, cancel := context.WithTimeout(ctx, cctx.Duration(netTimeoutFlag.Name))
+		provs, err := api.NetFindProviders(qctx, c, cctx.Int("count"))
+		cancel()
+		if err != nil {
+			return xerrors.Errorf("finding providers: %w", err)
+		}
+
+		reports := make([]peerReport, 0, len(provs))
+		for _, p := range provs {
+			found := p.FoundAfter
+			r, err := newPeerReport(ctx, cctx, api, p.Peer)
+			if err != nil {
+				return err
+			}
+			r.FoundAfter = &found
+			reports = append(reports, r)
+		}
+
+		if cctx.Bool(netJSONFlag.Name) {
+			return printJSON(cctx, reports)
+		}
+		if len(reports) == 0 {
+			fmt.Fprintf(cctx.App.Writer, "No providers of %s found\n", c)
+			return nil
+		}
+		for _, r := range reports {
+			r.print(cctx)
+		}
+		return nil
+	},
+}
+
+type addrReport struct {
+	Addr  string
+	Label string             `json:",omitempty"`
+	Dial  *atypes.DialResult `json:",omitempty"`
+}
+
+type peerReport struct {
+	ID         peer.ID
+	FoundAfter *time.Duration `json:",omitempty"`
+	Addrs      []addrReport
+}
+
+// newPeerReport labels the addresses of the peer, dialing them with --dial
+func newPeerReport(ctx context.Context, cctx *cli.Context, api atypes.Common, ai peer.AddrInfo) (peerReport, error) {
+	r := peerReport{ID: ai.ID, Addrs: make([]addrReport, len(ai.Addrs))}
+	for i, a := range ai.Addrs {
+		r.Addrs[i] = addrReport{Addr: a.String(), Label: addrLabel(a)}
+	}
+
+	if !cctx.Bool(netDialFlag.Name) || len(ai.Addrs) == 0 {
+		return r, nil
+	}
+
+	dctx, cancel := context.WithTimeout(ctx, cctx.Duration(netTimeoutFlag.Name))
+	defer cancel()
+	dials, err := api.NetDialAddrs(dctx, ai)
+	if err != nil {
+		return peerReport{}, xerrors.Errorf("dialing %s: %w", ai.ID, err)
+	}
+	for i := range dials {
+		r.Addrs[i].Dial = &dials[i]
+	}
+	return r, nil
+}
+
+func (r peerReport) print(cctx *cli.Context) {
+	w := cctx.App.Writer
+	fmt.Fprintf(w, "%s", r.ID)
+	if r.FoundAfter != nil {
+		fmt.Fprintf(w, " (found after %s)", r.FoundAfter.Round(time.Millisecond))
+	}
+	fmt.Fprintln(w)
+	if len(r.Addrs) == 0 {
+		fmt.Fprintln(w, "  no known addresses")
+	}
+	for _, a := range r.Addrs {
+		fmt.Fprintf(w, "  %s", a.Addr)
+		if a.Label != "" {
+			fmt.Fprintf(w, " [%s]", a.Label)
+		}
+		if a.Dial != nil {
+			if a.Dial.Error == "" {
+				fmt.Fprintf(w, " dial OK in %s", a.Dial.Time.Round(time.Millisecond))
+			} else {
+				fmt.Fprintf(w, " dial failed: %s", a.Dial.Error)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func printJSON(cctx *cli.Context, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(cctx.App.Writer, string(b))
+	return nil
+}
+
+// addrLabel tells the addresses which can't be reached over the internet, or
+// only through a relay, empty for public and DNS addresses
+func addrLabel(a multiaddr.Multiaddr) string {
+	if _, err := a.ValueForProtocol(multiaddr.P_CIRCUIT); err == nil {
+		return "relay"
+	}
+
+	first, _ := multiaddr.SplitFirst(a)
+	if first == nil || (first.Protocol().Code != multiaddr.P_IP4 && first.Protocol().Code != multiaddr.P_IP6) {
+		return ""
+	}
+	switch {
+	case manet.IsIPLoopback(a):
+		return "loopback"
+	case manet.IsIP6LinkLocal(a):
+		return "link-local"
+	case manet.IsPrivateAddr(a):
+		return "private"
+	case !manet.IsPublicAddr(a):
+		return "unroutable"
+	}
+	return ""
+}
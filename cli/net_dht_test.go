@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddrLabel(t *testing.T) {
+	for addr, label := range map[string]string{
+		"/ip4/1.2.3.4/tcp/1234":                   "",
+		"/dns4/example.com/tcp/1234":              "",
+		"/ip4/127.0.0.1/tcp/1234":                 "loopback",
+		"/ip6/::1/tcp/1234":                       "loopback",
+		"/ip6/fe80::1/tcp/1234":                   "link-local",
+		"/ip4/192.168.1.10/tcp/1234":              "private",
+		"/ip4/10.0.0.1/udp/1234/quic":             "private",
+		"/ip4/0.0.0.0/tcp/1234":                   "unroutable",
+		"/ip4/203.0.113.5/tcp/1234":               "unroutable",
+		"/ip4/1.2.3.4/tcp/1234/p2p-circuit/tcp/1": "relay",
+	} {
+		a, err := multiaddr.NewMultiaddr(addr)
+		require.NoError(t, err, addr)
+		require.Equal(t, label, addrLabel(a), addr)
+	}
+}
@@ -1,12 +1,19 @@
 package cli
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
 	"github.com/docker/go-units"
 	paramfetch "github.com/filecoin-project/go-paramfetch"
 	"github.com/urfave/cli/v2"
 	"golang.org/x/xerrors"
 
 	"github.com/filecoin-project/lotus/build"
+	"github.com/filecoin-project/lotus/lib/proofparams"
 )
 
 var FetchParamCmd = &cli.Command{
@@ -31,3 +38,202 @@ var FetchParamCmd = &cli.Command{
 		return nil
 	},
 }
+
+var ParamsCmd = &cli.Command{
+	Name:  "params",
+	Usage: "Manage the proof parameter cache",
+	Subcommands: []*cli.Command{
+		paramsStatusCmd,
+		paramsFetchCmd,
+	},
+}
+
+var paramsSectorSizeFlag = &cli.StringFlag{
+	Name:  "sector-size",
+	Usage: "sector size to manage the sealing and proving parameters of, e.g. 32GiB; only the verifying keys are needed without",
+}
+
+func paramsManifest(cctx *cli.Context) ([]proofparams.File, error) {
+	var sectorSize uint64
+	if cctx.IsSet(paramsSectorSizeFlag.Name) {
+		ss, err := units.RAMInBytes(cctx.String(paramsSectorSizeFlag.Name))
+		if err != nil {
+			return nil, xerrors.Errorf("parsing sector size (specify as \"32GiB\", for instance): %w", err)
+		}
+		sectorSize = uint64(ss)
+	}
+
+	return proofparams.Manifest(build.ParametersJSON(), build.SrsJSON(), sectorSize)
+}
+
+var paramsStatusCmd = &cli.Command{
+	Name:  "status",
+	Usage: "List the parameter files which are present, missing or corrupt",
+	Flags: []cli.Flag{
+		paramsSectorSizeFlag,
+		&cli.BoolFlag{
+			Name:  "verify",
+			Usage: "verify the checksums of the present files, which takes a few minutes for the larger ones",
+		},
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "output the status as JSON",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		files, err := paramsManifest(cctx)
+		if err != nil {
+			return err
+		}
+
+		status := proofparams.Check(ReqContext(cctx), proofparams.Dir(), files, cctx.Bool("verify"))
+
+		if cctx.Bool("json") {
+			b, err := json.MarshalIndent(map[string]interface{}{
+				"Dir":   proofparams.Dir(),
+				"Files": status,
+			}, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cctx.App.Writer, string(b))
+		} else {
+			fmt.Fprintf(cctx.App.Writer, "Parameter cache: %s\n", proofparams.Dir())
+			tw := tabwriter.NewWriter(cctx.App.Writer, 2, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "File\tSector Size\tState\tSize")
+			for _, st := range status {
+				ss := "-"
+				if st.SectorSize != 0 {
+					ss = units.BytesSize(float64(st.SectorSize))
+				}
+				state := string(st.State)
+				if st.Error != "" {
+					state += " (" + st.Error + ")"
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", st.Name, ss, state, units.BytesSize(float64(st.Size)))
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+		}
+
+		var bad int
+		for _, st := range status {
+			if st.State == proofparams.Missing || st.State == proofparams.Corrupt {
+				bad++
+			}
+		}
+		if bad > 0 {
+			return xerrors.Errorf("%d of %d parameter files missing or corrupt", bad, len(status))
+		}
+		return nil
+	},
+}
+
+var paramsFetchCmd = &cli.Command{
+	Name:  "fetch",
+	Usage: "Fetch the missing and corrupt parameter files",
+	Flags: []cli.Flag{
+		paramsSectorSizeFlag,
+		&cli.IntFlag{
+			Name:  "parallel",
+			Usage: "number of files to download at once",
+			Value: 2,
+		},
+		&cli.StringSliceFlag{
+			Name:  "mirror",
+			Usage: "gateway URL to fall back to, e.g. https://mirror.example/ipfs/, can be repeated",
+		},
+		&cli.StringFlag{
+			Name:  "preseed-from",
+			Usage: "directory holding a parameter cache, e.g. on a network share, to link or copy files from before fetching",
+		},
+		&cli.BoolFlag{
+			Name:  "verify",
+			Usage: "verify the checksums of the present files, to fetch the corrupt ones again",
+			Value: true,
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		ctx := ReqContext(cctx)
+		afmt := NewAppFmt(cctx.App)
+
+		files, err := paramsManifest(cctx)
+		if err != nil {
+			return err
+		}
+
+		dir := proofparams.Dir()
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+
+		var need []proofparams.File
+		for i, st := range proofparams.Check(ctx, dir, files, cctx.Bool("verify")) {
+			if st.State == proofparams.Missing || st.State == proofparams.Corrupt {
+				need = append(need, files[i])
+			}
+		}
+
+		if from := cctx.String("preseed-from"); from != "" {
+			var left []proofparams.File
+			for _, f := range need {
+				if err := proofparams.Preseed(from, dir, f); err != nil {
+					afmt.Printf("Couldn't preseed %s: %s\n", f.Name, err)
+					left = append(left, f)
+					continue
+				}
+				afmt.Printf("Preseeded %s\n", f.Name)
+			}
+			need = left
+		}
+
+		if len(need) == 0 {
+			afmt.Println("All parameter files are present")
+			return nil
+		}
+
+		fetcher := &proofparams.Fetcher{
+			Dir:      dir,
+			Gateways: append(proofparams.Gateways(), cctx.StringSlice("mirror")...),
+			Parallel: cctx.Int("parallel"),
+		}
+
+		afmt.Printf("Fetching %d parameter files to %s\n", len(need), dir)
+
+		done := make(chan struct{})
+		go func() {
+			last := map[string]int64{}
+			tick := time.NewTicker(10 * time.Second)
+			defer tick.Stop()
+			for {
+				select {
+				case <-tick.C:
+				case <-done:
+					return
+				}
+
+				for _, t := range fetcher.Progress() {
+					rate := float64(t.Done-last[t.Name]) / 10
+					last[t.Name] = t.Done
+
+					total := "?"
+					if t.Total >= 0 {
+						total = units.BytesSize(float64(t.Total))
+					}
+					afmt.Printf("  %s: %s / %s (%s/s) from %s\n", t.Name, units.BytesSize(float64(t.Done)), total, units.BytesSize(rate), t.Gateway)
+				}
+			}
+		}()
+
+		start := time.Now()
+		err = fetcher.Fetch(ctx, need)
+		close(done)
+		if err != nil {
+			return xerrors.Errorf("fetching parameters: %w", err)
+		}
+
+		afmt.Printf("Fetched %d parameter files in %s\n", len(need), time.Since(start).Truncate(time.Second))
+		return nil
+	},
+}
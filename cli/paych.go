@@ -2,6 +2,7 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"fmt"
 	"io"
@@ -9,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/api/v0api"
 
 	"github.com/filecoin-project/lotus/paychmgr"
 
@@ -332,6 +334,22 @@ var paychVoucherCmd = &cli.Command{
 		paychVoucherListCmd,
 		paychVoucherBestSpendableCmd,
 		paychVoucherSubmitCmd,
+		paychVoucherExportCmd,
+	},
+}
+
+var voucherEnvelopeFlags = []cli.Flag{
+	&cli.BoolFlag{
+		Name:  "envelope",
+		Usage: "print the voucher in an envelope naming its network and channel, as JSON",
+	},
+	&cli.StringFlag{
+		Name:  "memo",
+		Usage: "note for the counterparty to add to the envelope",
+	},
+	&cli.BoolFlag{
+		Name:  "compact",
+		Usage: "print the envelope in a compact form fitting QR codes",
 	},
 }
 
@@ -339,13 +357,13 @@ var paychVoucherCreateCmd = &cli.Command{
 	Name:      "create",
 	Usage:     "Create a signed payment channel voucher",
 	ArgsUsage: "[channelAddress amount]",
-	Flags: []cli.Flag{
+	Flags: append([]cli.Flag{
 		&cli.IntFlag{
 			Name:  "lane",
 			Value: 0,
 			Usage: "specify payment channel lane to use",
 		},
-	},
+	}, voucherEnvelopeFlags...),
 	Action: func(cctx *cli.Context) error {
 		if cctx.Args().Len() != 2 {
 			return ShowHelp(cctx, fmt.Errorf("must pass two arguments: <channel> <amount>"))
@@ -380,7 +398,7 @@ var paychVoucherCreateCmd = &cli.Command{
 			return fmt.Errorf("Could not create voucher: insufficient funds in channel, shortfall: %d", v.Shortfall)
 		}
 
-		enc, err := EncodedString(v.Voucher)
+		enc, err := encodeVoucher(cctx, api, v.Voucher)
 		if err != nil {
 			return err
 		}
@@ -391,31 +409,27 @@ var paychVoucherCreateCmd = &cli.Command{
 }
 
 var paychVoucherCheckCmd = &cli.Command{
-	Name:      "check",
-	Usage:     "Check validity of payment channel voucher",
-	ArgsUsage: "[channelAddress voucher]",
+	Name:        "check",
+	Usage:       "Check validity of payment channel voucher",
+	ArgsUsage:   "[channelAddress] voucher",
+	Description: voucherArgsDescription,
 	Action: func(cctx *cli.Context) error {
-		if cctx.Args().Len() != 2 {
+		if cctx.Args().Len() != 1 && cctx.Args().Len() != 2 {
 			return ShowHelp(cctx, fmt.Errorf("must pass payment channel address and voucher to validate"))
 		}
 
-		ch, err := address.NewFromString(cctx.Args().Get(0))
+		api, closer, err := GetFullNodeAPI(cctx)
 		if err != nil {
 			return err
 		}
+		defer closer()
 
-		sv, err := paych.DecodeSignedVoucher(cctx.Args().Get(1))
-		if err != nil {
-			return err
-		}
+		ctx := ReqContext(cctx)
 
-		api, closer, err := GetFullNodeAPI(cctx)
+		ch, sv, err := voucherArgs(ctx, cctx, api)
 		if err != nil {
 			return err
 		}
-		defer closer()
-
-		ctx := ReqContext(cctx)
 
 		if err := api.PaychVoucherCheckValid(ctx, ch, sv); err != nil {
 			return err
@@ -427,31 +441,27 @@ var paychVoucherCheckCmd = &cli.Command{
 }
 
 var paychVoucherAddCmd = &cli.Command{
-	Name:      "add",
-	Usage:     "Add payment channel voucher to local datastore",
-	ArgsUsage: "[channelAddress voucher]",
+	Name:        "add",
+	Usage:       "Add payment channel voucher to local datastore",
+	ArgsUsage:   "[channelAddress] voucher",
+	Description: voucherArgsDescription,
 	Action: func(cctx *cli.Context) error {
-		if cctx.Args().Len() != 2 {
+		if cctx.Args().Len() != 1 && cctx.Args().Len() != 2 {
 			return ShowHelp(cctx, fmt.Errorf("must pass payment channel address and voucher"))
 		}
 
-		ch, err := address.NewFromString(cctx.Args().Get(0))
+		api, closer, err := GetFullNodeAPI(cctx)
 		if err != nil {
 			return err
 		}
+		defer closer()
 
-		sv, err := paych.DecodeSignedVoucher(cctx.Args().Get(1))
-		if err != nil {
-			return err
-		}
+		ctx := ReqContext(cctx)
 
-		api, closer, err := GetFullNodeAPI(cctx)
+		ch, sv, err := voucherArgs(ctx, cctx, api)
 		if err != nil {
 			return err
 		}
-		defer closer()
-
-		ctx := ReqContext(cctx)
 
 		// TODO: allow passing proof bytes
 		if _, err := api.PaychVoucherAdd(ctx, ch, sv, nil, types.NewInt(0)); err != nil {
@@ -584,13 +594,20 @@ func outputVoucher(w io.Writer, v *paych.SignedVoucher, export bool) error {
 	return nil
 }
 
-var paychVoucherSubmitCmd = &cli.Command{
-	Name:      "submit",
-	Usage:     "Submit voucher to chain to update payment channel state",
-	ArgsUsage: "[channelAddress voucher]",
+var paychVoucherExportCmd = &cli.Command{
+	Name:      "export",
+	Usage:     "Print the latest voucher of each lane of a payment channel, to send to the counterparty",
+	ArgsUsage: "[channelAddress]",
+	Flags: append([]cli.Flag{
+		&cli.IntFlag{
+			Name:  "lane",
+			Value: -1,
+			Usage: "only print the voucher of this lane",
+		},
+	}, voucherEnvelopeFlags...),
 	Action: func(cctx *cli.Context) error {
-		if cctx.Args().Len() != 2 {
-			return ShowHelp(cctx, fmt.Errorf("must pass payment channel address and voucher"))
+		if cctx.Args().Len() != 1 {
+			return ShowHelp(cctx, fmt.Errorf("must pass payment channel address"))
 		}
 
 		ch, err := address.NewFromString(cctx.Args().Get(0))
@@ -598,11 +615,118 @@ var paychVoucherSubmitCmd = &cli.Command{
 			return err
 		}
 
-		sv, err := paych.DecodeSignedVoucher(cctx.Args().Get(1))
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := ReqContext(cctx)
+
+		vouchers, err := api.PaychVoucherList(ctx, ch)
 		if err != nil {
 			return err
 		}
 
+		latest := map[uint64]*paych.SignedVoucher{}
+		for _, v := range vouchers {
+			if lane := cctx.Int("lane"); lane >= 0 && v.Lane != uint64(lane) {
+				continue
+			}
+			if l, ok := latest[v.Lane]; !ok || v.Nonce > l.Nonce {
+				latest[v.Lane] = v
+			}
+		}
+		if len(latest) == 0 {
+			return fmt.Errorf("no vouchers found for channel %s", ch)
+		}
+
+		vouchers = vouchers[:0]
+		for _, v := range latest {
+			vouchers = append(vouchers, v)
+		}
+		for _, v := range sortVouchers(vouchers) {
+			enc, err := encodeVoucher(cctx, api, v)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cctx.App.Writer, enc)
+		}
+		return nil
+	},
+}
+
+const voucherArgsDescription = `The voucher is either the voucher printed by 'voucher create', or an envelope
+   printed with --envelope, whose network must be the network of the node, and
+   channel the channel address, which can be omitted for envelopes.`
+
+// voucherArgs parses the [channelAddress] voucher args of the voucher
+// commands, checking vouchers given in an envelope are for the network of
+// the node and the channel
+func voucherArgs(ctx context.Context, cctx *cli.Context, api v0api.FullNode) (address.Address, *paych.SignedVoucher, error) {
+	ch := address.Undef
+	if cctx.Args().Len() == 2 {
+		var err error
+		if ch, err = address.NewFromString(cctx.Args().Get(0)); err != nil {
+			return address.Undef, nil, err
+		}
+	}
+
+	sv, env, err := paychmgr.ParseVoucher(cctx.Args().Get(cctx.Args().Len() - 1))
+	if err != nil {
+		return address.Undef, nil, err
+	}
+	if env == nil {
+		if ch == address.Undef {
+			return address.Undef, nil, ShowHelp(cctx, fmt.Errorf("must pass payment channel address for vouchers not in an envelope"))
+		}
+		return ch, sv, nil
+	}
+
+	network, err := api.StateNetworkName(ctx)
+	if err != nil {
+		return address.Undef, nil, err
+	}
+	if err := env.Validate(string(network), ch); err != nil {
+		return address.Undef, nil, err
+	}
+	if env.Memo != "" {
+		fmt.Fprintf(cctx.App.ErrWriter, "Memo: %s\n", env.Memo)
+	}
+	return env.Channel, sv, nil
+}
+
+// encodeVoucher encodes the voucher to send to the counterparty, in an
+// envelope when --envelope is set
+func encodeVoucher(cctx *cli.Context, api v0api.FullNode, sv *paych.SignedVoucher) (string, error) {
+	if !cctx.Bool("envelope") && !cctx.Bool("compact") {
+		return EncodedString(sv)
+	}
+
+	network, err := api.StateNetworkName(ReqContext(cctx))
+	if err != nil {
+		return "", err
+	}
+	env, err := paychmgr.NewVoucherEnvelope(string(network), sv, cctx.String("memo"))
+	if err != nil {
+		return "", err
+	}
+	if cctx.Bool("compact") {
+		return env.Compact()
+	}
+	return env.JSON()
+}
+
+var paychVoucherSubmitCmd = &cli.Command{
+	Name:        "submit",
+	Usage:       "Submit voucher to chain to update payment channel state",
+	ArgsUsage:   "[channelAddress] voucher",
+	Description: voucherArgsDescription,
+	Action: func(cctx *cli.Context) error {
+		if cctx.Args().Len() != 1 && cctx.Args().Len() != 2 {
+			return ShowHelp(cctx, fmt.Errorf("must pass payment channel address and voucher"))
+		}
+
 		api, closer, err := GetFullNodeAPI(cctx)
 		if err != nil {
 			return err
@@ -611,6 +735,11 @@ var paychVoucherSubmitCmd = &cli.Command{
 
 		ctx := ReqContext(cctx)
 
+		ch, sv, err := voucherArgs(ctx, cctx, api)
+		if err != nil {
+			return err
+		}
+
 		mcid, err := api.PaychVoucherSubmit(ctx, ch, sv, nil, nil)
 		if err != nil {
 			return err
@@ -1,17 +1,29 @@
 package cli
 
 import (
+	"bufio"
+	"context"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
 
+	"github.com/ipfs/go-cid"
 	"github.com/urfave/cli/v2"
 	"golang.org/x/xerrors"
 
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 
+	lapi "github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/api/v0api"
 	"github.com/filecoin-project/lotus/chain/actors/builtin"
+	"github.com/filecoin-project/lotus/chain/denylist"
+	"github.com/filecoin-project/lotus/chain/messagepool"
 	"github.com/filecoin-project/lotus/chain/types"
 )
 
@@ -57,16 +69,103 @@ var sendCmd = &cli.Command{
 			Name:  "params-hex",
 			Usage: "specify invocation parameters in hex",
 		},
+		&cli.StringFlag{
+			Name:  "via-msig",
+			Usage: "send from a multisig, proposing the message with the --from signer",
+		},
+		&cli.StringFlag{
+			Name:  "reference",
+			Usage: "ticket or approval reference recorded with the send, required by the node above its configured value threshold",
+		},
+		&cli.StringFlag{
+			Name:  "memo",
+			Usage: "note kept by the node with the sent message, e.g. an invoice number; it doesn't go on chain",
+		},
+		&cli.IntFlag{
+			Name:  "split",
+			Usage: "send the value in this many messages to the recipient, showing the plan and asking to confirm first",
+		},
+		&cli.StringFlag{
+			Name:  "idempotency-key",
+			Usage: "key identifying the send, retrying with the same key returns the message already pushed instead of sending again",
+		},
 		&cli.BoolFlag{
 			Name:  "force",
 			Usage: "must be specified for the action to take effect if maybe SysErrInsufficientFunds etc",
 		},
+		&cli.BoolFlag{
+			Name:  "pending",
+			Usage: "list the messages of the sender pending in the mempool, and ask before sending if there are any",
+		},
+		&cli.BoolFlag{
+			Name:  "no-retry-prompt",
+			Usage: "fail right away when pushing the message fails, instead of asking whether to retry (the default when stdin isn't a terminal)",
+		},
+		&cli.BoolFlag{
+			Name:  "wait",
+			Usage: "wait for the message to be executed",
+		},
+		&cli.Uint64Flag{
+			Name:  "confidence",
+			Usage: "number of block confirmations to wait for with --wait (default: by value at risk, see --confidence-policy)",
+		},
+		confidencePolicyFlag,
+		criticalFlag,
+		confirmWordFlag,
+		confirmAboveFlag,
+		confirmIgnoreCaseFlag,
+		twoPersonFlag,
+		twoPersonAboveFlag,
+		addOperatorFlag,
+		approvalFromFlag,
+		approverFlag,
+		approvalTimeoutFlag,
+		balanceImpactFlag,
+		retryLastFlag,
+		preSendHookFlag,
+		preSendHookTimeoutFlag,
+		sendDeadlineFlag,
+		presetFlag,
+		presetSetFlag,
+		savePresetFlag,
+		listPresetsFlag,
+		skipAPIVersionCheckFlag,
+		&cli.BoolFlag{
+			Name:  "gas-block-share",
+			Usage: "show the gas limit of the message as a share of the block gas limit, large messages being harder to include",
+		},
 	},
-	Action: func(cctx *cli.Context) error {
-		if cctx.Args().Len() != 2 {
+	Action: func(cctx *cli.Context) (err error) {
+		if cctx.Bool(listPresetsFlag.Name) {
+			return listSendPresets(cctx)
+		}
+		if cctx.IsSet(addOperatorFlag.Name) {
+			return addSendOperator(cctx)
+		}
+
+		retryLast := cctx.Bool(retryLastFlag.Name)
+		usePreset := cctx.IsSet(presetFlag.Name)
+		if cctx.IsSet(presetSetFlag.Name) && !usePreset {
+			return xerrors.Errorf("--%s only fills the placeholders of the --%s sent with", presetSetFlag.Name, presetFlag.Name)
+		}
+		switch {
+		case cctx.IsSet(savePresetFlag.Name):
+			// the arguments are checked saving the preset
+		case retryLast:
+			if cctx.Args().Len() != 0 {
+				return ShowHelp(cctx, fmt.Errorf("--retry-last takes no arguments, the send is the last one which failed"))
+			}
+		case usePreset:
+			// the arguments are checked applying the preset
+		case cctx.Args().Len() != 2:
 			return ShowHelp(cctx, fmt.Errorf("'send' expects two arguments, target and amount"))
 		}
 
+		dl, err := parseSendDeadline(cctx, time.Now())
+		if err != nil {
+			return err
+		}
+
 		srv, err := GetFullNodeServices(cctx)
 		if err != nil {
 			return err
@@ -74,86 +173,532 @@ var sendCmd = &cli.Command{
 		defer srv.Close() //nolint:errcheck
 
 		ctx := ReqContext(cctx)
-		var params SendParams
-
-		params.To, err = address.NewFromString(cctx.Args().Get(0))
-		if err != nil {
-			return ShowHelp(cctx, fmt.Errorf("failed to parse target address: %w", err))
+		if err := checkSendAPIVersion(ctx, cctx, srv.FullNodeAPI()); err != nil {
+			return err
+		}
+		if cctx.IsSet(savePresetFlag.Name) {
+			return saveSendPreset(ctx, cctx, srv)
+		}
+		if dl != nil {
+			var cancel context.CancelFunc
+			ctx, cancel = dl.bound(ctx)
+			defer cancel()
+			defer func() {
+				err = dl.failed(err)
+			}()
 		}
 
-		val, err := types.ParseFIL(cctx.Args().Get(1))
+		dl.enter("checks")
+		var params SendParams
+		memo := cctx.String("memo")
+		if retryLast {
+			var keptMemo string
+			params, keptMemo, err = loadLastFailedSend(cctx)
+			if memo == "" {
+				memo = keptMemo
+			}
+		} else {
+			to, amount := cctx.Args().Get(0), cctx.Args().Get(1)
+			if usePreset {
+				to, amount, err = applySendPreset(ctx, cctx, srv.FullNodeAPI())
+				if err != nil {
+					return err
+				}
+			}
+			params, err = sendParamsFromFlags(ctx, cctx, srv, to, amount)
+		}
 		if err != nil {
-			return ShowHelp(cctx, fmt.Errorf("failed to parse amount: %w", err))
+			return err
 		}
-		params.Val = abi.TokenAmount(val)
 
-		if from := cctx.String("from"); from != "" {
-			addr, err := address.NewFromString(from)
+		var threshold uint64
+		if params.ViaMsig != address.Undef {
+			threshold, err = srv.MsigThreshold(ctx, params.ViaMsig)
 			if err != nil {
 				return err
 			}
+		}
 
-			params.From = addr
+		split := cctx.Int("split")
+		if cctx.IsSet("split") {
+			if split < 1 {
+				return xerrors.Errorf("--split must be at least 1")
+			}
+			if params.Method != builtin.MethodSend || params.Params != nil || params.ViaMsig != address.Undef || params.Nonce != nil {
+				return xerrors.Errorf("--split only works for plain sends, without --method, --params-*, --via-msig or --nonce")
+			}
+			if types.NewInt(uint64(split)).GreaterThan(params.Val) {
+				return xerrors.Errorf("can't split %s into %d messages", types.FIL(params.Val), split)
+			}
+		}
+
+		// Keep the send if it fails before its message is pushed, for
+		// --retry-last. Split sends may fail after pushing some parts.
+		pushed := false
+		if split <= 1 {
+			intent := params
+			defer func() {
+				switch {
+				case pushed:
+					clearFailedSend(cctx)
+				case err != nil:
+					keepFailedSend(cctx, intent, memo, err)
+				}
+			}()
 		}
 
-		if cctx.IsSet("gas-premium") {
-			gp, err := types.BigFromString(cctx.String("gas-premium"))
+		var in io.Reader = NewAppFmt(cctx.App).Stdin
+		if dl != nil {
+			in = ctxReader{ctx: ctx, r: in}
+		}
+		stdin := bufio.NewReader(in)
+
+		if cctx.Bool("pending") {
+			send, err := reviewPendingMessages(ctx, cctx, srv, params.From, stdin)
 			if err != nil {
 				return err
 			}
-			params.GasPremium = &gp
+			if !send {
+				return xerrors.Errorf("send: %w", ErrAbortedByUser)
+			}
 		}
 
-		if cctx.IsSet("gas-feecap") {
-			gfc, err := types.BigFromString(cctx.String("gas-feecap"))
-			if err != nil {
+		if cctx.Bool(balanceImpactFlag.Name) {
+			if err := printBalanceImpact(ctx, cctx, srv, params); err != nil {
 				return err
 			}
-			params.GasFeeCap = &gfc
 		}
 
-		if cctx.IsSet("gas-limit") {
-			limit := cctx.Int64("gas-limit")
-			params.GasLimit = &limit
+		if params.GasPremium != nil {
+			printPremiumCompetitiveness(ctx, cctx, srv.FullNodeAPI(), *params.GasPremium)
 		}
 
-		params.Method = abi.MethodNum(cctx.Uint64("method"))
+		if params.Method != builtin.MethodSend {
+			if err := checkMethod(ctx, srv.FullNodeAPI(), params.To, params.Method); errors.Is(err, ErrSendUnknownMethod) {
+				fmt.Fprintf(cctx.App.ErrWriter, "WARNING: %s, the message will fail on chain\n", err)
+			} else if err != nil {
+				log.Warnf("checking the method of the send: %s", err)
+			}
+		}
 
-		if cctx.IsSet("params-json") {
-			decparams, err := srv.DecodeTypedParamsFromJSON(ctx, params.To, params.Method, cctx.String("params-json"))
-			if err != nil {
-				return fmt.Errorf("failed to decode json params: %w", err)
+		// approved sends show the cost of the message pinned for approval
+		if split <= 1 && params.ViaMsig == address.Undef && !cctx.IsSet(approvalFromFlag.Name) {
+			if msg, err := estimateSendMessage(ctx, srv.FullNodeAPI(), params); err == nil {
+				printSendCost(cctx, msg)
+			} else {
+				log.Warnf("estimating the message to show its cost: %s", err)
 			}
-			params.Params = decparams
 		}
-		if cctx.IsSet("params-hex") {
-			if params.Params != nil {
-				return fmt.Errorf("can only specify one of 'params-json' and 'params-hex'")
+
+		dl.enter("prompts")
+		if err := confirmCriticalSend(cctx, params, stdin); err != nil {
+			return err
+		}
+		if err := confirmTwoPersonSend(cctx, params, stdin); err != nil {
+			return err
+		}
+
+		dl.enter("pre-send hook")
+		if err := runPreSendHook(ctx, cctx, srv.FullNodeAPI(), params, memo, split); err != nil {
+			return err
+		}
+
+		if split > 1 {
+			dl.enter("split send")
+			return sendSplit(ctx, cctx, srv, params, split, stdin, dl)
+		}
+
+		// an approved message is pushed as is, without prompts changing it
+		approved := false
+		if cctx.IsSet(approvalFromFlag.Name) {
+			if params.ViaMsig != address.Undef || params.IdempotencyKey != "" {
+				return xerrors.Errorf("--approval-from can't be used with --via-msig or --idempotency-key")
 			}
-			decparams, err := hex.DecodeString(cctx.String("params-hex"))
+			msg, err := pinSendMessage(ctx, srv.FullNodeAPI(), &params)
 			if err != nil {
-				return fmt.Errorf("failed to decode hex params: %w", err)
+				return err
+			}
+			printSendCost(cctx, msg)
+			dl.enter("approval")
+			if err := awaitSendApproval(ctx, cctx, msg); err != nil {
+				return err
 			}
-			params.Params = decparams
+			approved = true
 		}
 
-		params.Force = cctx.Bool("force")
-
-		if cctx.IsSet("nonce") {
-			n := cctx.Uint64("nonce")
-			params.Nonce = &n
+		dl.enter("push")
+		msgCid, err := srv.Send(ctx, params)
+		if err != nil && !approved && params.Reference == "" && strings.Contains(err.Error(), lapi.ErrSendReferenceRequired.Error()) {
+			// the node requires a reference for this send, ask for one
+			params.Reference, err = promptSendReference(cctx, stdin)
+			if err != nil {
+				return err
+			}
+			msgCid, err = srv.Send(ctx, params)
 		}
 
-		msgCid, err := srv.Send(ctx, params)
+		for err != nil && !approved && offerSendRetry(cctx) && !isPermanentSendError(err) {
+			retry, perr := promptSendRetry(cctx, stdin, err, &params)
+			if perr != nil {
+				return perr
+			}
+			if !retry {
+				break
+			}
+			msgCid, err = srv.Send(ctx, params)
+		}
 
 		if err != nil {
 			if errors.Is(err, ErrSendBalanceTooLow) {
 				return fmt.Errorf("--force must be specified for this action to have an effect; you have been warned: %w", err)
 			}
-			return xerrors.Errorf("executing send: %w", err)
+			return WithExitStatus(xerrors.Errorf("executing send: %w", err), ExitPushFailed)
+		}
+		pushed = true
+		ctx = dl.pushed(ctx)
+
+		if memo != "" {
+			recordSendMemo(ctx, cctx, srv.FullNodeAPI(), msgCid, memo)
+		}
+
+		if msg, err := srv.FullNodeAPI().ChainGetMessage(ctx, msgCid); err == nil {
+			if cctx.Bool("gas-block-share") {
+				printGasBlockShare(ctx, cctx, srv.FullNodeAPI(), msg)
+			}
+			printFeeLevelHistory(ctx, cctx, srv.FullNodeAPI(), msgCid)
+		} else {
+			log.Warnf("getting sent message to show its fees: %s", err)
+		}
+
+		if params.ViaMsig != address.Undef {
+			printMsigSendSummary(cctx, params, threshold, msgCid)
 		}
 
 		fmt.Fprintf(cctx.App.Writer, "%s\n", msgCid)
+
+		if cctx.Bool("wait") {
+			mw, err := waitMsgConfidence(ctx, cctx, srv.FullNodeAPI(), msgCid)
+			if err != nil {
+				return WithAPIExitStatus(xerrors.Errorf("waiting for message: %w", err))
+			}
+			if mw.Receipt.ExitCode != 0 {
+				return xerrors.Errorf("exit %d: %w", mw.Receipt.ExitCode, ErrExecutionFailed)
+			}
+			fmt.Fprintf(cctx.App.Writer, "Executed at epoch %d\n", mw.Height)
+		}
 		return nil
 	},
 }
+
+// sendParamsFromFlags builds the send of amount to the target from the flags
+func sendParamsFromFlags(ctx context.Context, cctx *cli.Context, srv ServicesAPI, to, amount string) (SendParams, error) {
+	var params SendParams
+	var err error
+
+	params.To, err = address.NewFromString(to)
+	if err != nil {
+		return SendParams{}, ShowHelp(cctx, fmt.Errorf("failed to parse target address: %w", err))
+	}
+
+	val, err := types.ParseFIL(amount)
+	if err != nil {
+		return SendParams{}, ShowHelp(cctx, fmt.Errorf("failed to parse amount: %w", err))
+	}
+	params.Val = abi.TokenAmount(val)
+
+	if from := cctx.String("from"); from != "" {
+		addr, err := address.NewFromString(from)
+		if err != nil {
+			return SendParams{}, err
+		}
+
+		params.From = addr
+	}
+
+	if cctx.IsSet("gas-premium") {
+		gp, err := types.BigFromString(cctx.String("gas-premium"))
+		if err != nil {
+			return SendParams{}, err
+		}
+		params.GasPremium = &gp
+	}
+
+	if cctx.IsSet("gas-feecap") {
+		gfc, err := types.BigFromString(cctx.String("gas-feecap"))
+		if err != nil {
+			return SendParams{}, err
+		}
+		params.GasFeeCap = &gfc
+	}
+
+	if cctx.IsSet("gas-limit") {
+		limit := cctx.Int64("gas-limit")
+		params.GasLimit = &limit
+	}
+
+	params.Method = abi.MethodNum(cctx.Uint64("method"))
+
+	if cctx.IsSet("params-json") {
+		decparams, err := srv.DecodeTypedParamsFromJSON(ctx, params.To, params.Method, cctx.String("params-json"))
+		if err != nil {
+			return SendParams{}, fmt.Errorf("failed to decode json params: %w", err)
+		}
+		params.Params = decparams
+	}
+	if cctx.IsSet("params-hex") {
+		if params.Params != nil {
+			return SendParams{}, fmt.Errorf("can only specify one of 'params-json' and 'params-hex'")
+		}
+		decparams, err := hex.DecodeString(cctx.String("params-hex"))
+		if err != nil {
+			return SendParams{}, fmt.Errorf("failed to decode hex params: %w", err)
+		}
+		params.Params = decparams
+	}
+
+	params.Force = cctx.Bool("force")
+
+	if cctx.IsSet("nonce") {
+		n := cctx.Uint64("nonce")
+		params.Nonce = &n
+	}
+
+	if cctx.IsSet("via-msig") {
+		params.ViaMsig, err = address.NewFromString(cctx.String("via-msig"))
+		if err != nil {
+			return SendParams{}, ShowHelp(cctx, fmt.Errorf("failed to parse multisig address: %w", err))
+		}
+	}
+
+	if cctx.IsSet("reference") {
+		params.Reference = strings.TrimSpace(cctx.String("reference"))
+		if params.Reference == "" {
+			return SendParams{}, xerrors.Errorf("--reference can't be empty")
+		}
+	}
+
+	if cctx.IsSet("idempotency-key") {
+		params.IdempotencyKey = cctx.String("idempotency-key")
+		if params.IdempotencyKey == "" {
+			return SendParams{}, xerrors.Errorf("--idempotency-key can't be empty")
+		}
+		if params.Nonce != nil {
+			return SendParams{}, xerrors.Errorf("--idempotency-key can't be used with --nonce, the message is pushed as is")
+		}
+	}
+
+	return params, nil
+}
+
+// recordSendMemo keeps the memo of a sent message. The message is sent
+// either way, so failing to keep the memo is only a warning.
+func recordSendMemo(ctx context.Context, cctx *cli.Context, api v0api.FullNode, msg cid.Cid, memo string) {
+	if err := api.MpoolSetMemo(ctx, lapi.MessageMemo{Message: msg, Memo: memo}); err != nil {
+		fmt.Fprintf(cctx.App.ErrWriter, "WARNING: message %s was sent, but keeping its memo failed: %s\n", msg, err)
+		return
+	}
+	fmt.Fprintln(cctx.App.ErrWriter, "Memo kept by this node only, it doesn't go on chain")
+}
+
+// printSendCost shows what may leave the sender account at most: the value,
+// and the fee of the whole gas limit at the fee cap
+func printSendCost(cctx *cli.Context, msg *types.Message) {
+	maxFee := msg.RequiredFunds()
+	fmt.Fprintf(cctx.App.ErrWriter, "Max fee: %s (gas limit %d at fee cap %s)\n", types.FIL(maxFee), msg.GasLimit, types.FIL(msg.GasFeeCap))
+	fmt.Fprintf(cctx.App.ErrWriter, "Total worst-case cost: %s value + %s max fee = %s\n", types.FIL(msg.Value), types.FIL(maxFee), types.FIL(types.BigAdd(msg.Value, maxFee)))
+}
+
+// gasBlockShareWarn is the share of the block gas limit above which a message
+// is warned to be hard to include
+const gasBlockShareWarn = 0.25
+
+// printGasBlockShare shows the gas limit of the message as a share of the
+// block gas limit of the network
+func printGasBlockShare(ctx context.Context, cctx *cli.Context, api v0api.FullNode, msg *types.Message) {
+	v, err := api.Version(ctx)
+	if err != nil {
+		log.Warnf("getting block gas limit: %s", err)
+		return
+	}
+	if v.BlockGasLimit <= 0 {
+		fmt.Fprintln(cctx.App.ErrWriter, "The node doesn't tell the block gas limit")
+		return
+	}
+
+	share := float64(msg.GasLimit) / float64(v.BlockGasLimit)
+	fmt.Fprintf(cctx.App.ErrWriter, "Gas limit %d is %.2f%% of the block gas limit %d\n", msg.GasLimit, share*100, v.BlockGasLimit)
+	if share > gasBlockShareWarn {
+		fmt.Fprintf(cctx.App.ErrWriter, "WARNING: messages using over %.0f%% of a block are hard to fit in blocks with other messages, and may take long to be included\n", gasBlockShareWarn*100)
+	}
+}
+
+func printMsigSendSummary(cctx *cli.Context, params SendParams, threshold uint64, proposal cid.Cid) {
+	from := "the default wallet address"
+	if params.From != address.Undef {
+		from = params.From.String()
+	}
+
+	fmt.Fprintf(cctx.App.Writer, "Proposed through multisig %s by %s:\n", params.ViaMsig, from)
+	fmt.Fprintf(cctx.App.Writer, "  send %s to %s, method %d, %d bytes of params\n", types.FIL(params.Val), params.To, params.Method, len(params.Params))
+
+	if threshold <= 1 {
+		fmt.Fprintln(cctx.App.Writer, "The proposer's approval meets the multisig threshold, the message executes as soon as the proposal lands.")
+	} else {
+		fmt.Fprintf(cctx.App.Writer, "The proposal needs %d approvals. Once it lands, its transaction ID is in the return of 'lotus state wait-msg %s',\n", threshold, proposal)
+		fmt.Fprintf(cctx.App.Writer, "and in the pending transactions of 'lotus msig inspect %s'.\n", params.ViaMsig)
+	}
+}
+
+// reviewPendingMessages lists the messages pending from the sender, asking
+// whether to send anyway if there are any
+func reviewPendingMessages(ctx context.Context, cctx *cli.Context, srv ServicesAPI, from address.Address, stdin *bufio.Reader) (bool, error) {
+	afmt := NewAppFmt(cctx.App)
+
+	pending, err := srv.PendingMessages(ctx, from)
+	if err != nil {
+		return false, err
+	}
+
+	sender := "the default wallet address"
+	if from != address.Undef {
+		sender = from.String()
+	}
+	if len(pending) == 0 {
+		afmt.Printf("No messages pending from %s\n", sender)
+		return true, nil
+	}
+
+	afmt.Printf("%d messages pending from %s:\n", len(pending), sender)
+	tw := tabwriter.NewWriter(cctx.App.Writer, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "Nonce\tTo\tValue\tMethod\tGasFeeCap\tGasPremium\tAge")
+	for _, pm := range pending {
+		m := pm.Message.Message
+		age := "-"
+		if pm.Age > 0 {
+			age = pm.Age.Truncate(time.Second).String()
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%s\t%s\t%s\n", m.Nonce, m.To, types.FIL(m.Value), m.Method, m.GasFeeCap, m.GasPremium, age)
+	}
+	if err := tw.Flush(); err != nil {
+		return false, err
+	}
+
+	afmt.Print("Send anyway? [y/N] ")
+	answer, err := stdin.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, xerrors.Errorf("reading answer: %w", err)
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+func promptSendReference(cctx *cli.Context, stdin *bufio.Reader) (string, error) {
+	afmt := NewAppFmt(cctx.App)
+	afmt.Print("This send needs a reference (e.g. the approval ticket ID): ")
+
+	ref, err := stdin.ReadString('\n')
+	if err != nil && (err != io.EOF || ref == "") {
+		return "", xerrors.Errorf("reading reference: %w", err)
+	}
+
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", xerrors.Errorf("a non-empty reference is required for this send")
+	}
+	return ref, nil
+}
+
+// permanentSendErrors are rejections which retrying the same send can't fix.
+// Errors coming over RPC lose their type, so they are matched by message.
+var permanentSendErrors = []error{
+	ErrSendBalanceTooLow,
+	lapi.ErrSendReferenceRequired,
+	lapi.ErrIdempotencyKeyConflict,
+	lapi.ErrWatchOnlyAddress,
+	lapi.ErrWalletPolicyViolation,
+	denylist.ErrDenied,
+	messagepool.ErrMessageTooBig,
+	messagepool.ErrMessageValueTooHigh,
+	messagepool.ErrNonceTooLow,
+	messagepool.ErrNotEnoughFunds,
+	messagepool.ErrInvalidToAddr,
+}
+
+// mpoolSendErrors are transient rejections by the message pool, the message
+// was not accepted
+var mpoolSendErrors = []error{
+	messagepool.ErrGasFeeCapTooLow,
+	messagepool.ErrSoftValidationFailure,
+	messagepool.ErrRBFTooLowPremium,
+	messagepool.ErrTooManyPendingMessages,
+	messagepool.ErrNonceGap,
+}
+
+func sendErrorIn(err error, errs []error) bool {
+	for _, e := range errs {
+		if errors.Is(err, e) || strings.Contains(err.Error(), e.Error()) {
+			return true
+		}
+	}
+	return false
+}
+
+func isPermanentSendError(err error) bool {
+	return sendErrorIn(err, permanentSendErrors)
+}
+
+// offerSendRetry returns whether to ask about retrying a failed push: not with
+// --no-retry-prompt, nor when stdin isn't a terminal, as when run from a script
+func offerSendRetry(cctx *cli.Context) bool {
+	if cctx.Bool("no-retry-prompt") {
+		return false
+	}
+	if _, ok := cctx.App.Metadata["stdin"]; ok {
+		return true
+	}
+	fi, err := os.Stdin.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// promptSendRetry shows a failed push and asks whether to send again. Gas
+// values which weren't given are estimated again on every send, explicitly
+// given ones can be dropped to have them estimated too.
+func promptSendRetry(cctx *cli.Context, stdin *bufio.Reader, sendErr error, params *SendParams) (bool, error) {
+	afmt := NewAppFmt(cctx.App)
+	afmt.Printf("Pushing the message failed: %s\n", sendErr)
+
+	if !sendErrorIn(sendErr, mpoolSendErrors) {
+		// e.g. the connection dropped, the node may have taken the message
+		afmt.Println("The node may have accepted the message before the failure, check 'lotus mpool pending --local' before retrying, or it may be sent twice.")
+	}
+
+	fixedGas := params.GasPremium != nil || params.GasFeeCap != nil || params.GasLimit != nil
+	for {
+		if fixedGas {
+			afmt.Print("[r]etry, retry re-[e]stimating gas, or [a]bort? ")
+		} else {
+			afmt.Print("[r]etry (re-estimating gas) or [a]bort? ")
+		}
+
+		answer, err := stdin.ReadString('\n')
+		if err == io.EOF && answer == "" {
+			return false, nil
+		}
+		if err != nil && err != io.EOF {
+			return false, xerrors.Errorf("reading answer: %w", err)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "r", "retry":
+			return true, nil
+		case "e", "estimate":
+			if !fixedGas {
+				continue
+			}
+			params.GasPremium, params.GasFeeCap, params.GasLimit = nil, nil, nil
+			return true, nil
+		case "a", "abort":
+			return false, nil
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/crypto"
+
+	"github.com/filecoin-project/lotus/api/v0api"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/lib/sigs"
+)
+
+// The flags of sends confirmed by an approval signed out of band, instead of
+// interactively
+var (
+	approvalFromFlag = &cli.StringFlag{
+		Name:    "approval-from",
+		Usage:   "file or http(s) URL to wait for a signed approval of the message at, {cid} is replaced by the message CID",
+		EnvVars: []string{"LOTUS_SEND_APPROVAL_FROM"},
+	}
+	approverFlag = &cli.StringFlag{
+		Name:    "approver",
+		Usage:   "address whose signature approves sends with --approval-from",
+		EnvVars: []string{"LOTUS_SEND_APPROVER"},
+	}
+	approvalTimeoutFlag = &cli.DurationFlag{
+		Name:  "approval-timeout",
+		Usage: "how long to wait for the approval",
+		Value: 10 * time.Minute,
+	}
+)
+
+const approvalPollInterval = 5 * time.Second
+
+// SendApproval authorizes sending the message, until it expires
+type SendApproval struct {
+	// Message is the CID of the unsigned message
+	Message   cid.Cid
+	Expires   time.Time
+	Signature *crypto.Signature
+}
+
+// approvalSigningBytes are the bytes the approver signs
+func approvalSigningBytes(msg cid.Cid, expires time.Time) []byte {
+	return []byte(fmt.Sprintf("lotus send approval: %s until %d", msg, expires.Unix()))
+}
+
+// verify checks the approval is of the message, signed by the approver and
+// not expired
+func (a *SendApproval) verify(msg cid.Cid, approver address.Address, now time.Time) error {
+	if a.Message != msg {
+		return xerrors.Errorf("the approval is of message %s, not %s", a.Message, msg)
+	}
+	if !now.Before(a.Expires) {
+		return xerrors.Errorf("the approval expired at %s", a.Expires.Format(time.RFC3339))
+	}
+	if a.Signature == nil {
+		return xerrors.Errorf("the approval isn't signed")
+	}
+	if err := sigs.Verify(a.Signature, approver, approvalSigningBytes(a.Message, a.Expires)); err != nil {
+		return xerrors.Errorf("the approval isn't signed by %s: %w", approver, err)
+	}
+	return nil
+}
+
+// estimateSendMessage returns the message of the send as Send would push it,
+// with its gas estimated and the default sender if none is set
+func estimateSendMessage(ctx context.Context, api v0api.FullNode, params SendParams) (*types.Message, error) {
+	if params.From == address.Undef {
+		from, err := api.WalletDefaultAddress(ctx)
+		if err != nil {
+			return nil, xerrors.Errorf("getting default wallet address: %w", err)
+		}
+		params.From = from
+	}
+
+	msg := &types.Message{
+		From:       params.From,
+		To:         params.To,
+		Value:      params.Val,
+		Method:     params.Method,
+		Params:     params.Params,
+		GasPremium: types.NewInt(0),
+		GasFeeCap:  types.NewInt(0),
+	}
+	if params.GasPremium != nil {
+		msg.GasPremium = *params.GasPremium
+	}
+	if params.GasFeeCap != nil {
+		msg.GasFeeCap = *params.GasFeeCap
+	}
+	if params.GasLimit != nil {
+		msg.GasLimit = *params.GasLimit
+	}
+
+	msg, err := api.GasEstimateMessageGas(ctx, msg, nil, types.EmptyTSK)
+	if err != nil {
+		return nil, xerrors.Errorf("estimating gas: %w", err)
+	}
+	return msg, nil
+}
+
+// pinSendMessage fixes the sender, nonce and gas of the send, so the message
+// to approve is known before it is pushed. It returns the message Send will
+// push with the params.
+func pinSendMessage(ctx context.Context, api v0api.FullNode, params *SendParams) (*types.Message, error) {
+	msg, err := estimateSendMessage(ctx, api, *params)
+	if err != nil {
+		return nil, err
+	}
+	params.From = msg.From
+
+	if params.Nonce != nil {
+		msg.Nonce = *params.Nonce
+	} else {
+		msg.Nonce, err = api.MpoolGetNonce(ctx, msg.From)
+		if err != nil {
+			return nil, xerrors.Errorf("getting nonce: %w", err)
+		}
+	}
+
+	params.GasPremium = &msg.GasPremium
+	params.GasFeeCap = &msg.GasFeeCap
+	params.GasLimit = &msg.GasLimit
+	params.Nonce = &msg.Nonce
+	return msg, nil
+}
+
+// awaitSendApproval waits for the approval of the message at --approval-from,
+// failing with ErrAbortedByUser if it doesn't verify
+func awaitSendApproval(ctx context.Context, cctx *cli.Context, msg *types.Message) error {
+	approver, err := address.NewFromString(cctx.String(approverFlag.Name))
+	if err != nil {
+		return xerrors.Errorf("--approval-from needs the approver address, set with --approver or %s: %w", approverFlag.EnvVars[0], err)
+	}
+
+	mc := msg.Cid()
+	src := strings.ReplaceAll(cctx.String(approvalFromFlag.Name), "{cid}", mc.String())
+	timeout := cctx.Duration(approvalTimeoutFlag.Name)
+
+	w := cctx.App.ErrWriter
+	fmt.Fprintf(w, "Message %s: nonce %d, gas limit %d, fee cap %s, premium %s\n", mc, msg.Nonce, msg.GasLimit, msg.GasFeeCap, msg.GasPremium)
+	fmt.Fprintf(w, "Waiting up to %s for its approval by %s at %s\n", timeout, approver, src)
+	fmt.Fprintf(w, "To approve, run 'lotus wallet approve-send %s %s' and put the output there\n", approver, mc)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		b, err := fetchApproval(ctx, src)
+		if err != nil {
+			return err
+		}
+		if b != nil {
+			var a SendApproval
+			if err := json.Unmarshal(b, &a); err != nil {
+				return xerrors.Errorf("decoding approval: %w", err)
+			}
+			if err := a.verify(mc, approver, time.Now()); err != nil {
+				return xerrors.Errorf("rejecting approval, nothing was sent: %s: %w", err, ErrAbortedByUser)
+			}
+			fmt.Fprintf(w, "Approved by %s until %s\n", approver, a.Expires.Format(time.RFC3339))
+			return nil
+		}
+
+		select {
+		case <-time.After(approvalPollInterval):
+		case <-ctx.Done():
+			return xerrors.Errorf("no approval of %s within %s, nothing was sent: %w", mc, timeout, ErrWaitTimeout)
+		}
+	}
+}
+
+// fetchApproval returns the approval at the file or URL, nil if it isn't
+// there yet
+func fetchApproval(ctx context.Context, src string) ([]byte, error) {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		b, err := ioutil.ReadFile(src)
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("reading approval: %w", err)
+		}
+		return b, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("fetching approval: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, nil
+		}
+		return nil, xerrors.Errorf("fetching approval: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		b, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, xerrors.Errorf("fetching approval: %w", err)
+		}
+		return b, nil
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, xerrors.Errorf("fetching approval: %s", resp.Status)
+	}
+}
+
+var walletApproveSend = &cli.Command{
+	Name:      "approve-send",
+	Usage:     "Sign the approval of a send waiting on --approval-from",
+	ArgsUsage: "<approver address> <message cid>",
+	Flags: []cli.Flag{
+		&cli.DurationFlag{
+			Name:  "expires",
+			Usage: "how long the approval is valid for",
+			Value: time.Hour,
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.Args().Len() != 2 {
+			return ShowHelp(cctx, fmt.Errorf("expected the approver address and the message CID"))
+		}
+		approver, err := address.NewFromString(cctx.Args().First())
+		if err != nil {
+			return ShowHelp(cctx, fmt.Errorf("parsing approver address: %w", err))
+		}
+		mc, err := cid.Parse(cctx.Args().Get(1))
+		if err != nil {
+			return ShowHelp(cctx, fmt.Errorf("parsing message CID: %w", err))
+		}
+
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		a := SendApproval{
+			Message: mc,
+			Expires: time.Now().Add(cctx.Duration("expires")).Truncate(time.Second),
+		}
+		a.Signature, err = api.WalletSign(ReqContext(cctx), approver, approvalSigningBytes(a.Message, a.Expires))
+		if err != nil {
+			return xerrors.Errorf("signing approval: %w", err)
+		}
+
+		b, err := json.MarshalIndent(a, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cctx.App.Writer, string(b))
+		return nil
+	},
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/filecoin-project/go-address"
+
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+var balanceImpactFlag = &cli.BoolFlag{
+	Name:  "balance-impact",
+	Usage: "before sending, show the available and locked balance of the account the value leaves, and what stays available",
+}
+
+// printBalanceImpact shows the balance of the account the value leaves, the
+// multisig with --via-msig, split into its available and locked parts. Only
+// the available part can be spent, a send needing more is warned about.
+func printBalanceImpact(ctx context.Context, cctx *cli.Context, srv ServicesAPI, params SendParams) error {
+	account := params.From
+	cost := params.Val
+	withFee := false
+	if params.ViaMsig != address.Undef {
+		// the proposer pays the fee
+		account = params.ViaMsig
+	} else if params.GasFeeCap != nil && params.GasLimit != nil {
+		cost = types.BigAdd(cost, types.BigMul(*params.GasFeeCap, types.NewInt(uint64(*params.GasLimit))))
+		withFee = true
+	}
+
+	bal, err := srv.AccountBalance(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	w := cctx.App.ErrWriter
+	fmt.Fprintf(w, "Balance of %s (%s): %s\n", bal.Address, bal.Actor, types.FIL(bal.Total))
+	fmt.Fprintf(w, "  available: %s\n", types.FIL(bal.Available))
+	fmt.Fprintf(w, "  locked:    %s\n", types.FIL(bal.Locked))
+
+	switch {
+	case cost.GreaterThan(bal.Total):
+		fmt.Fprintf(w, "WARNING: sending %s is more than the whole balance\n", types.FIL(cost))
+	case cost.GreaterThan(bal.Available):
+		fmt.Fprintf(w, "WARNING: sending %s needs %s of the locked funds, which can't be spent; only %s is available\n",
+			types.FIL(cost), types.FIL(types.BigSub(cost, bal.Available)), types.FIL(bal.Available))
+	default:
+		left := fmt.Sprintf("Available after the send: %s", types.FIL(types.BigSub(bal.Available, cost)))
+		if !withFee && params.ViaMsig == address.Undef {
+			left += ", less the gas fee"
+		}
+		fmt.Fprintln(w, left)
+	}
+	return nil
+}
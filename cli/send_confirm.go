@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+// The flags of the confirmation word critical sends need to be typed in
+// before they are sent, instead of answering y/n
+var (
+	confirmWordFlag = &cli.StringFlag{
+		Name:    "confirm-word",
+		Usage:   "word to type to confirm critical sends, those above --confirm-above or with --critical",
+		EnvVars: []string{"LOTUS_SEND_CONFIRM_WORD"},
+	}
+	confirmAboveFlag = &cli.StringFlag{
+		Name:    "confirm-above",
+		Usage:   "value above which sends are critical and need the confirmation word",
+		EnvVars: []string{"LOTUS_SEND_CONFIRM_ABOVE"},
+	}
+	confirmIgnoreCaseFlag = &cli.BoolFlag{
+		Name:    "confirm-ignore-case",
+		Usage:   "accept the confirmation word in any case",
+		EnvVars: []string{"LOTUS_SEND_CONFIRM_IGNORE_CASE"},
+	}
+	criticalFlag = &cli.BoolFlag{
+		Name:  "critical",
+		Usage: "flag the send as critical, needing the confirmation word whatever its value",
+	}
+)
+
+// confirmCriticalSend asks for the confirmation word if the send is critical,
+// failing with ErrAbortedByUser if what's typed doesn't match
+func confirmCriticalSend(cctx *cli.Context, params SendParams, stdin *bufio.Reader) error {
+	word := cctx.String(confirmWordFlag.Name)
+	critical := cctx.Bool(criticalFlag.Name)
+	if word == "" {
+		if critical {
+			return xerrors.Errorf("--critical needs a confirmation word, set with --confirm-word or %s", confirmWordFlag.EnvVars[0])
+		}
+		return nil
+	}
+
+	if !critical && cctx.IsSet(confirmAboveFlag.Name) {
+		above, err := types.ParseFIL(cctx.String(confirmAboveFlag.Name))
+		if err != nil {
+			return xerrors.Errorf("parsing --confirm-above: %w", err)
+		}
+		critical = params.Val.GreaterThan(types.BigInt(above))
+	}
+	if !critical {
+		return nil
+	}
+
+	afmt := NewAppFmt(cctx.App)
+	afmt.Printf("Sending %s to %s is critical. Type %q to confirm: ", types.FIL(params.Val), params.To, word)
+	typed, err := stdin.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return xerrors.Errorf("reading confirmation: %w", err)
+	}
+	typed = strings.TrimRight(typed, "\r\n")
+
+	match := typed == word
+	if cctx.Bool(confirmIgnoreCaseFlag.Name) {
+		match = strings.EqualFold(typed, word)
+	}
+	if !match {
+		return xerrors.Errorf("the confirmation word doesn't match, nothing was sent: %w", ErrAbortedByUser)
+	}
+	return nil
+}
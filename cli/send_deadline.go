@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+)
+
+var sendDeadlineFlag = &cli.StringFlag{
+	Name: "deadline",
+	Usage: "abort the send unless it is pushed by then, as a duration (10m) or a time (2006-01-02T15:04:05Z07:00); " +
+		"covers the checks, prompts, pre-send hook, approval and pushing, not --wait",
+}
+
+// sendDeadline bounds a send, from its checks to its push
+type sendDeadline struct {
+	at    time.Time
+	stage string
+
+	// base is the context the send is bounded within, the one of what
+	// follows the push
+	base context.Context
+	ctx  context.Context
+}
+
+// parseSendDeadline returns the deadline of the send, nil if there is none
+func parseSendDeadline(cctx *cli.Context, now time.Time) (*sendDeadline, error) {
+	s := cctx.String(sendDeadlineFlag.Name)
+	if s == "" {
+		return nil, nil
+	}
+
+	at, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		d, derr := time.ParseDuration(s)
+		if derr != nil {
+			return nil, xerrors.Errorf("--deadline %q is neither a duration nor an RFC3339 time", s)
+		}
+		at = now.Add(d)
+	}
+	if !at.After(now) {
+		return nil, xerrors.Errorf("--deadline %s has already passed", at.Format(time.RFC3339))
+	}
+	return &sendDeadline{at: at}, nil
+}
+
+// bound returns the context of the send until it is pushed, done by the
+// deadline
+func (d *sendDeadline) bound(ctx context.Context) (context.Context, context.CancelFunc) {
+	var cancel context.CancelFunc
+	d.base = ctx
+	d.ctx, cancel = context.WithDeadline(ctx, d.at)
+	return d.ctx, cancel
+}
+
+// pushed ends the bounded part of the send, returning the context of what
+// follows the push
+func (d *sendDeadline) pushed(ctx context.Context) context.Context {
+	if d == nil {
+		return ctx
+	}
+	d.stage = ""
+	return d.base
+}
+
+// enter logs the stage of the send starting, and the time left for it
+func (d *sendDeadline) enter(stage string) {
+	if d == nil {
+		return
+	}
+	d.stage = stage
+	log.Infow("send deadline", "stage", stage, "remaining", time.Until(d.at).Truncate(time.Millisecond))
+}
+
+// failed returns the error of the send, as ErrDeadlineExceeded if it failed
+// before being pushed because the deadline passed
+func (d *sendDeadline) failed(err error) error {
+	if d == nil || err == nil || d.stage == "" || d.ctx.Err() != context.DeadlineExceeded {
+		return err
+	}
+
+	log.Warnw("send deadline exceeded", "stage", d.stage, "over", time.Since(d.at).Truncate(time.Millisecond))
+	switch d.stage {
+	case "split send":
+		return xerrors.Errorf("deadline %s passed during the split send, the messages sent are listed above: %s: %w", d.at.Format(time.RFC3339), err, ErrDeadlineExceeded)
+	case "push":
+		return xerrors.Errorf("deadline %s passed while pushing, the message may have been pushed, "+
+			"check 'lotus mpool pending --local': %s: %w", d.at.Format(time.RFC3339), err, ErrDeadlineExceeded)
+	default:
+		return xerrors.Errorf("deadline %s passed during the %s, nothing was sent: %s: %w", d.at.Format(time.RFC3339), d.stage, err, ErrDeadlineExceeded)
+	}
+}
+
+// ctxReader reads until its context is done, so prompts can't hold a send
+// past its deadline
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r ctxReader) Read(p []byte) (int, error) {
+	type read struct {
+		n   int
+		err error
+	}
+	buf := make([]byte, len(p))
+	done := make(chan read, 1)
+	go func() {
+		n, err := r.r.Read(buf)
+		done <- read{n, err}
+	}()
+
+	select {
+	case rd := <-done:
+		return copy(p, buf[:rd.n]), rd.err
+	case <-r.ctx.Done():
+		return 0, r.ctx.Err()
+	}
+}
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+
+	"github.com/filecoin-project/lotus/api/v0api"
+	"github.com/filecoin-project/lotus/chain/actors"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/multisig"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+// The flags of the command run before each send, which can veto it
+var (
+	preSendHookFlag = &cli.StringFlag{
+		Name:    "pre-send-hook",
+		Usage:   "shell command run before sending, with the message as JSON on stdin; the send is aborted if it exits non-zero",
+		EnvVars: []string{"LOTUS_SEND_PRE_HOOK"},
+	}
+	preSendHookTimeoutFlag = &cli.DurationFlag{
+		Name:    "pre-send-hook-timeout",
+		Usage:   "how long the pre-send hook may run, the send is aborted when it takes longer",
+		EnvVars: []string{"LOTUS_SEND_PRE_HOOK_TIMEOUT"},
+		Value:   30 * time.Second,
+	}
+)
+
+// PreSendHookInput is what the pre-send hook reads on stdin. The message is
+// the one pushed, the multisig proposal with --via-msig; its gas and nonce
+// are zero when they are left to the node.
+type PreSendHookInput struct {
+	Message   *types.Message
+	ViaMsig   address.Address `json:",omitempty"`
+	Reference string          `json:",omitempty"`
+	Memo      string          `json:",omitempty"`
+	// Parts is the number of messages the value is split into with --split,
+	// the message being the whole send
+	Parts int `json:",omitempty"`
+}
+
+// sendHookMessage builds the message the send pushes, the way Send does
+func sendHookMessage(ctx context.Context, api v0api.FullNode, params SendParams) (*types.Message, error) {
+	if params.From == address.Undef {
+		from, err := api.WalletDefaultAddress(ctx)
+		if err != nil {
+			return nil, xerrors.Errorf("getting default wallet address: %w", err)
+		}
+		params.From = from
+	}
+
+	msg := &types.Message{
+		From:   params.From,
+		To:     params.To,
+		Value:  params.Val,
+		Method: params.Method,
+		Params: params.Params,
+	}
+	if params.ViaMsig != address.Undef {
+		nver, err := api.StateNetworkVersion(ctx, types.EmptyTSK)
+		if err != nil {
+			return nil, err
+		}
+		msg, err = multisig.Message(actors.VersionForNetwork(nver), params.From).
+			Propose(params.ViaMsig, params.To, params.Val, params.Method, params.Params)
+		if err != nil {
+			return nil, xerrors.Errorf("creating multisig proposal: %w", err)
+		}
+	}
+
+	msg.GasPremium = types.NewInt(0)
+	msg.GasFeeCap = types.NewInt(0)
+	if params.GasPremium != nil {
+		msg.GasPremium = *params.GasPremium
+	}
+	if params.GasFeeCap != nil {
+		msg.GasFeeCap = *params.GasFeeCap
+	}
+	if params.GasLimit != nil {
+		msg.GasLimit = *params.GasLimit
+	}
+	if params.Nonce != nil {
+		msg.Nonce = *params.Nonce
+	}
+	return msg, nil
+}
+
+// runPreSendHook runs the --pre-send-hook command, if any, returning an error
+// wrapping ErrAbortedByUser when it vetoes the send and ErrWaitTimeout when
+// it runs for too long. Its output goes to stderr.
+func runPreSendHook(ctx context.Context, cctx *cli.Context, api v0api.FullNode, params SendParams, memo string, parts int) error {
+	hook := cctx.String(preSendHookFlag.Name)
+	if hook == "" {
+		return nil
+	}
+
+	msg, err := sendHookMessage(ctx, api, params)
+	if err != nil {
+		return xerrors.Errorf("building message for the pre-send hook: %w", err)
+	}
+	in, err := json.Marshal(PreSendHookInput{
+		Message:   msg,
+		ViaMsig:   params.ViaMsig,
+		Reference: params.Reference,
+		Memo:      memo,
+		Parts:     parts,
+	})
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("sh", "-c", hook)
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = cctx.App.ErrWriter
+	cmd.Stderr = cctx.App.ErrWriter
+	if err := cmd.Start(); err != nil {
+		return xerrors.Errorf("starting pre-send hook, nothing was sent: %w", err)
+	}
+
+	// Not waiting on the hook past its timeout, processes it started may
+	// hold its output open
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	timeout := cctx.Duration(preSendHookTimeoutFlag.Name)
+	select {
+	case err = <-done:
+	case <-time.After(timeout):
+		_ = cmd.Process.Kill()
+		return xerrors.Errorf("pre-send hook still running after %s, nothing was sent: %w", timeout, ErrWaitTimeout)
+	case <-ctx.Done():
+		_ = cmd.Process.Kill()
+		return xerrors.Errorf("pre-send hook: %w", ctx.Err())
+	}
+
+	var exitErr *exec.ExitError
+	if xerrors.As(err, &exitErr) {
+		return xerrors.Errorf("vetoed by the pre-send hook (exit %d), nothing was sent: %w", exitErr.ExitCode(), ErrAbortedByUser)
+	}
+	if err != nil {
+		return xerrors.Errorf("running pre-send hook, nothing was sent: %w", err)
+	}
+	fmt.Fprintln(cctx.App.ErrWriter, "Send allowed by the pre-send hook")
+	return nil
+}
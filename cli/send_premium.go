@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/lotus/api/v0api"
+	"github.com/filecoin-project/lotus/build"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+// competitivePremium returns the lowest gas premium a message needs to be
+// among the highest paying messages of the mempool filling a block, zero when
+// the mempool doesn't fill a block and any premium makes it in
+func competitivePremium(pending []*types.SignedMessage, blockGasLimit int64) abi.TokenAmount {
+	msgs := make([]*types.Message, 0, len(pending))
+	for _, sm := range pending {
+		msgs = append(msgs, &sm.Message)
+	}
+	sort.Slice(msgs, func(i, j int) bool {
+		return msgs[i].GasPremium.GreaterThan(msgs[j].GasPremium)
+	})
+
+	var gas int64
+	for _, m := range msgs {
+		gas += m.GasLimit
+		if gas >= blockGasLimit {
+			return m.GasPremium
+		}
+	}
+	return big.Zero()
+}
+
+// printPremiumCompetitiveness shows the gas premium set for the send with
+// the competitive minimum of the mempool, warning when it is below it: the
+// message is valid but miners filling their blocks with the messages paying
+// the most may leave it out for long
+func printPremiumCompetitiveness(ctx context.Context, cctx *cli.Context, api v0api.FullNode, premium abi.TokenAmount) {
+	pending, err := api.MpoolPending(ctx, types.EmptyTSK)
+	if err != nil {
+		log.Warnf("getting mempool messages to check the gas premium against: %s", err)
+		return
+	}
+
+	min := competitivePremium(pending, build.BlockGasLimit)
+	w := cctx.App.ErrWriter
+	fmt.Fprintf(w, "Gas premium: %s attoFIL (competitive minimum in the mempool: %s attoFIL)\n", premium, min)
+	if premium.LessThan(min) {
+		fmt.Fprintf(w, "WARNING: the gas premium is below what the pending messages filling the next block pay, "+
+			"the message may be left out of blocks for long; consider --gas-premium %s\n", min)
+	}
+}
@@ -0,0 +1,361 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+
+	"github.com/filecoin-project/lotus/api/v0api"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+var presetFlag = &cli.StringFlag{
+	Name:  "preset",
+	Usage: "send with the named preset, the arguments being only the amount if the preset has none; flags given override the preset",
+}
+
+var savePresetFlag = &cli.StringFlag{
+	Name:  "save-preset",
+	Usage: "save the recipient, method, params, amount if given and gas flags of the send under this name, without sending",
+}
+
+var presetSetFlag = &cli.StringSliceFlag{
+	Name:  "set",
+	Usage: "fill a placeholder of the preset sent with, as name=value; placeholders are saved as {{name}} in the target, amount or flags",
+}
+
+var listPresetsFlag = &cli.BoolFlag{
+	Name:  "list-presets",
+	Usage: "list the saved send presets",
+}
+
+const sendPresetsFile = "send-presets.json"
+
+// presetPlaceholderRe matches the placeholders of presets, filled with --set
+// when sending with them
+var presetPlaceholderRe = regexp.MustCompile(`{{([A-Za-z0-9_-]+)}}`)
+
+// presetFlags are the flags shaping the send which presets keep
+var presetFlags = []string{"from", "method", "params-json", "params-hex", "via-msig", "gas-premium", "gas-feecap", "gas-limit"}
+
+// presetExcludes are the one-time values of a send, which presets don't keep
+var presetExcludes = []string{"nonce", "idempotency-key", "reference", "memo", "split"}
+
+// sendPreset is a send saved under a name, kept in the repo
+type sendPreset struct {
+	To string
+	// ToID is the ID address the recipient resolved to when the preset was
+	// saved, if it did
+	ToID  string            `json:",omitempty"`
+	Value string            `json:",omitempty"`
+	Flags map[string]string `json:",omitempty"`
+	Saved time.Time
+}
+
+// placeholders returns the names of the placeholders of the preset, sorted
+func (p sendPreset) placeholders() []string {
+	seen := map[string]bool{}
+	var out []string
+	vals := []string{p.To, p.Value}
+	for _, v := range p.Flags {
+		vals = append(vals, v)
+	}
+	for _, v := range vals {
+		for _, m := range presetPlaceholderRe.FindAllStringSubmatch(v, -1) {
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				out = append(out, m[1])
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// fill returns the preset with its placeholders replaced by their values,
+// failing if some have none
+func (p sendPreset) fill(values map[string]string) (sendPreset, error) {
+	var missing []string
+	fill := func(v string) string {
+		return presetPlaceholderRe.ReplaceAllStringFunc(v, func(m string) string {
+			name := presetPlaceholderRe.FindStringSubmatch(m)[1]
+			val, ok := values[name]
+			if !ok {
+				missing = append(missing, name)
+				return m
+			}
+			return val
+		})
+	}
+
+	out := p
+	out.To = fill(p.To)
+	out.Value = fill(p.Value)
+	out.Flags = make(map[string]string, len(p.Flags))
+	for f, v := range p.Flags {
+		out.Flags[f] = fill(v)
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return sendPreset{}, xerrors.Errorf("no value for placeholders %s, give them with --%s name=value", strings.Join(missing, ", "), presetSetFlag.Name)
+	}
+	return out, nil
+}
+
+// parsePresetValues parses the name=value placeholder values given with
+// --set
+func parsePresetValues(cctx *cli.Context) (map[string]string, error) {
+	values := map[string]string{}
+	for _, kv := range cctx.StringSlice(presetSetFlag.Name) {
+		i := strings.Index(kv, "=")
+		if i <= 0 {
+			return nil, xerrors.Errorf("--%s %q isn't name=value", presetSetFlag.Name, kv)
+		}
+		values[kv[:i]] = kv[i+1:]
+	}
+	return values, nil
+}
+
+func loadSendPresets(path string) (map[string]sendPreset, error) {
+	presets := map[string]sendPreset{}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return presets, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("reading send presets: %w", err)
+	}
+	if err := json.Unmarshal(b, &presets); err != nil {
+		return nil, xerrors.Errorf("decoding send presets: %w", err)
+	}
+	return presets, nil
+}
+
+func sendPresetsPath(cctx *cli.Context) (string, error) {
+	path := localRepoFile(cctx, sendPresetsFile)
+	if path == "" {
+		return "", xerrors.Errorf("no repo to keep send presets in")
+	}
+	return path, nil
+}
+
+// saveSendPreset saves the send the arguments and flags describe under the
+// name given with --save-preset
+func saveSendPreset(ctx context.Context, cctx *cli.Context, srv ServicesAPI) error {
+	name := cctx.String(savePresetFlag.Name)
+	if name == "" {
+		return xerrors.Errorf("--%s can't be empty", savePresetFlag.Name)
+	}
+	if cctx.IsSet(presetFlag.Name) || cctx.Bool(retryLastFlag.Name) {
+		return xerrors.Errorf("--%s can't be used with --%s or --%s", savePresetFlag.Name, presetFlag.Name, retryLastFlag.Name)
+	}
+	for _, f := range presetExcludes {
+		if cctx.IsSet(f) {
+			return xerrors.Errorf("--%s is a one-time value, presets don't keep it", f)
+		}
+	}
+	if cctx.Args().Len() < 1 || cctx.Args().Len() > 2 {
+		return ShowHelp(cctx, fmt.Errorf("saving a preset expects the target and optionally the amount"))
+	}
+
+	preset := sendPreset{
+		To:    cctx.Args().Get(0),
+		Value: cctx.Args().Get(1),
+		Flags: map[string]string{},
+		Saved: time.Now(),
+	}
+	for _, f := range presetFlags {
+		if cctx.IsSet(f) {
+			preset.Flags[f] = fmt.Sprint(cctx.Value(f))
+		}
+	}
+
+	if ph := preset.placeholders(); len(ph) > 0 {
+		// the send can only be built once they're filled
+		fmt.Fprintf(cctx.App.ErrWriter, "Preset has placeholders %s, the send is checked when they're filled with --%s\n", strings.Join(ph, ", "), presetSetFlag.Name)
+	} else {
+		// check the send the preset makes, with a placeholder amount if it
+		// has none
+		val := preset.Value
+		if val == "" {
+			val = "0"
+		}
+		params, err := sendParamsFromFlags(ctx, cctx, srv, preset.To, val)
+		if err != nil {
+			return err
+		}
+		if id, err := srv.FullNodeAPI().StateLookupID(ctx, params.To, types.EmptyTSK); err == nil {
+			preset.ToID = id.String()
+		} else {
+			fmt.Fprintf(cctx.App.ErrWriter, "WARNING: recipient %s doesn't resolve on chain yet: %s\n", params.To, err)
+		}
+	}
+
+	path, err := sendPresetsPath(cctx)
+	if err != nil {
+		return err
+	}
+	presets, err := loadSendPresets(path)
+	if err != nil {
+		return err
+	}
+	_, replaced := presets[name]
+	presets[name] = preset
+
+	b, err := json.MarshalIndent(presets, "", "  ")
+	if err != nil {
+		return xerrors.Errorf("encoding send presets: %w", err)
+	}
+	if err := ioutil.WriteFile(path, b, 0600); err != nil {
+		return xerrors.Errorf("saving send presets: %w", err)
+	}
+
+	if replaced {
+		fmt.Fprintf(cctx.App.Writer, "Replaced preset %q, send with 'lotus send --preset %s'\n", name, name)
+	} else {
+		fmt.Fprintf(cctx.App.Writer, "Saved preset %q, send with 'lotus send --preset %s'\n", name, name)
+	}
+	return nil
+}
+
+// applySendPreset fills the flags of the send which weren't given from the
+// preset named with --preset, and returns the target and amount of the send
+func applySendPreset(ctx context.Context, cctx *cli.Context, api v0api.FullNode) (string, string, error) {
+	name := cctx.String(presetFlag.Name)
+	path, err := sendPresetsPath(cctx)
+	if err != nil {
+		return "", "", err
+	}
+	presets, err := loadSendPresets(path)
+	if err != nil {
+		return "", "", err
+	}
+	preset, ok := presets[name]
+	if !ok {
+		return "", "", xerrors.Errorf("no send preset %q", name)
+	}
+
+	values, err := parsePresetValues(cctx)
+	if err != nil {
+		return "", "", err
+	}
+	known := map[string]bool{}
+	for _, ph := range preset.placeholders() {
+		known[ph] = true
+	}
+	for v := range values {
+		if !known[v] {
+			return "", "", xerrors.Errorf("preset %q has no placeholder %q", name, v)
+		}
+	}
+	if cctx.Args().Len() == 1 {
+		// the amount given replaces the preset's, with its placeholders
+		preset.Value = ""
+	}
+	preset, err = preset.fill(values)
+	if err != nil {
+		return "", "", xerrors.Errorf("preset %q: %w", name, err)
+	}
+
+	val := preset.Value
+	switch cctx.Args().Len() {
+	case 0:
+		if val == "" {
+			return "", "", ShowHelp(cctx, fmt.Errorf("preset %q has no amount, give it as the argument", name))
+		}
+	case 1:
+		val = cctx.Args().Get(0)
+	default:
+		return "", "", ShowHelp(cctx, fmt.Errorf("sending with a preset expects only the amount, the target is the preset's"))
+	}
+
+	for f, v := range preset.Flags {
+		if cctx.IsSet(f) {
+			continue
+		}
+		if err := cctx.Set(f, v); err != nil {
+			return "", "", xerrors.Errorf("preset %q: setting --%s: %w", name, f, err)
+		}
+	}
+
+	warnPresetRecipient(ctx, cctx, api, name, preset)
+	fmt.Fprintf(cctx.App.ErrWriter, "Using preset %q, saved %s\n", name, preset.Saved.Format(time.RFC3339))
+	return preset.To, val, nil
+}
+
+// warnPresetRecipient warns when the recipient of the preset doesn't resolve
+// on chain, or resolves to another actor than when it was saved
+func warnPresetRecipient(ctx context.Context, cctx *cli.Context, api v0api.FullNode, name string, preset sendPreset) {
+	to, err := address.NewFromString(preset.To)
+	if err != nil {
+		return // failing to parse is reported building the send
+	}
+	id, err := api.StateLookupID(ctx, to, types.EmptyTSK)
+	switch {
+	case err != nil && preset.ToID != "":
+		fmt.Fprintf(cctx.App.ErrWriter, "WARNING: recipient %s of preset %q no longer resolves on chain: %s\n", preset.To, name, err)
+	case err != nil:
+		fmt.Fprintf(cctx.App.ErrWriter, "WARNING: recipient %s of preset %q doesn't resolve on chain: %s\n", preset.To, name, err)
+	case preset.ToID != "" && id.String() != preset.ToID:
+		fmt.Fprintf(cctx.App.ErrWriter, "WARNING: recipient %s of preset %q now resolves to %s, it resolved to %s when saved\n", preset.To, name, id, preset.ToID)
+	}
+}
+
+func listSendPresets(cctx *cli.Context) error {
+	path, err := sendPresetsPath(cctx)
+	if err != nil {
+		return err
+	}
+	presets, err := loadSendPresets(path)
+	if err != nil {
+		return err
+	}
+	if len(presets) == 0 {
+		fmt.Fprintln(cctx.App.Writer, "No send presets, save one with 'lotus send --save-preset <name> <target> [amount]'")
+		return nil
+	}
+
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(cctx.App.Writer, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "Name\tTo\tAmount\tFlags\tPlaceholders")
+	for _, name := range names {
+		p := presets[name]
+		val := p.Value
+		if val == "" {
+			val = "-"
+		}
+		flags := "-"
+		var set []string
+		for _, f := range presetFlags {
+			if v, ok := p.Flags[f]; ok {
+				set = append(set, fmt.Sprintf("--%s=%s", f, v))
+			}
+		}
+		if len(set) > 0 {
+			flags = strings.Join(set, " ")
+		}
+		placeholders := "-"
+		if ph := p.placeholders(); len(ph) > 0 {
+			placeholders = strings.Join(ph, ",")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", name, p.To, val, flags, placeholders)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+var retryLastFlag = &cli.BoolFlag{
+	Name:  "retry-last",
+	Usage: "send again the last send which failed, going through the checks, prompts and gas estimation again",
+}
+
+// retryLastExcludes are the flags shaping the send, which the retried send
+// already has
+var retryLastExcludes = []string{"from", "gas-premium", "gas-feecap", "gas-limit", "nonce", "method", "params-json", "params-hex",
+	"via-msig", "reference", "idempotency-key", "split", "force", "preset"}
+
+const lastFailedSendFile = "last-failed-send.json"
+
+// failedSend is the last send which failed before its message was pushed,
+// kept in the repo for --retry-last
+type failedSend struct {
+	Params SendParams
+	Memo   string `json:",omitempty"`
+	Error  string
+	Time   time.Time
+}
+
+// lastFailedSendPath returns where the last failed send is kept, empty if
+// there is no local repo to keep it in
+func lastFailedSendPath(cctx *cli.Context) string {
+	return localRepoFile(cctx, lastFailedSendFile)
+}
+
+// localRepoFile returns the path of the named file in the local repo, empty
+// if there is no local repo
+func localRepoFile(cctx *cli.Context, name string) string {
+	repoFlag := cctx.String("repo")
+	if repoFlag == "" {
+		return ""
+	}
+	repoPath, err := homedir.Expand(repoFlag)
+	if err != nil {
+		return ""
+	}
+	if fi, err := os.Stat(repoPath); err != nil || !fi.IsDir() {
+		return ""
+	}
+	return filepath.Join(repoPath, name)
+}
+
+// keepFailedSend keeps the send for --retry-last. Failing to keep it only
+// logs, the send failed either way.
+func keepFailedSend(cctx *cli.Context, params SendParams, memo string, sendErr error) {
+	path := lastFailedSendPath(cctx)
+	if path == "" {
+		return
+	}
+
+	b, err := json.MarshalIndent(failedSend{
+		Params: params,
+		Memo:   memo,
+		Error:  sendErr.Error(),
+		Time:   time.Now(),
+	}, "", "  ")
+	if err != nil {
+		log.Warnf("encoding failed send: %s", err)
+		return
+	}
+	if err := ioutil.WriteFile(path, b, 0600); err != nil {
+		log.Warnf("keeping failed send: %s", err)
+		return
+	}
+	fmt.Fprintln(cctx.App.ErrWriter, "The send can be retried with 'lotus send --retry-last'")
+}
+
+// clearFailedSend drops the kept send once a send went through, so it can't
+// be retried by mistake
+func clearFailedSend(cctx *cli.Context) {
+	path := lastFailedSendPath(cctx)
+	if path == "" {
+		return
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		log.Warnf("removing last failed send: %s", err)
+	}
+}
+
+// loadLastFailedSend returns the last failed send and its memo, showing what
+// it is and how long ago it failed
+func loadLastFailedSend(cctx *cli.Context) (SendParams, string, error) {
+	for _, name := range retryLastExcludes {
+		if cctx.IsSet(name) {
+			return SendParams{}, "", xerrors.Errorf("--%s can't be used with --retry-last, the send is retried as it was", name)
+		}
+	}
+
+	path := lastFailedSendPath(cctx)
+	if path == "" {
+		return SendParams{}, "", xerrors.Errorf("no repo to find the last failed send in")
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return SendParams{}, "", xerrors.Errorf("no failed send to retry")
+	}
+	if err != nil {
+		return SendParams{}, "", xerrors.Errorf("reading last failed send: %w", err)
+	}
+
+	var fs failedSend
+	if err := json.Unmarshal(b, &fs); err != nil {
+		return SendParams{}, "", xerrors.Errorf("decoding last failed send: %w", err)
+	}
+
+	from := "the default wallet address"
+	if !fs.Params.From.Empty() {
+		from = fs.Params.From.String()
+	}
+	if !fs.Params.ViaMsig.Empty() {
+		from = fmt.Sprintf("multisig %s", fs.Params.ViaMsig)
+	}
+
+	w := cctx.App.ErrWriter
+	fmt.Fprintf(w, "Retrying the send of %s from %s to %s, method %d, which failed %s ago (%s):\n",
+		types.FIL(fs.Params.Val), from, fs.Params.To, fs.Params.Method, time.Since(fs.Time).Truncate(time.Second), fs.Time.Format(time.RFC3339))
+	fmt.Fprintf(w, "  %s\n", fs.Error)
+	return fs.Params, fs.Memo, nil
+}
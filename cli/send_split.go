@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs/go-cid"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/chain/actors/builtin"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+// splitValue splits the value into n parts adding up to it, the first ones
+// taking the remainder
+func splitValue(val abi.TokenAmount, n int) []abi.TokenAmount {
+	part := big.Div(val, big.NewInt(int64(n)))
+	rem := big.Mod(val, big.NewInt(int64(n))).Int64()
+
+	parts := make([]abi.TokenAmount, n)
+	for i := range parts {
+		parts[i] = part
+		if int64(i) < rem {
+			parts[i] = big.Add(part, big.NewInt(1))
+		}
+	}
+	return parts
+}
+
+// sendSplit sends the value in n messages to the same recipient. The plan,
+// with the fees of every message on top of the value, is shown first, then
+// the messages are confirmed all at once or one by one.
+func sendSplit(ctx context.Context, cctx *cli.Context, srv ServicesAPI, params SendParams, n int, stdin *bufio.Reader, dl *sendDeadline) error {
+	afmt := NewAppFmt(cctx.App)
+	fapi := srv.FullNodeAPI()
+
+	if params.From == address.Undef {
+		from, err := fapi.WalletDefaultAddress(ctx)
+		if err != nil {
+			return xerrors.Errorf("getting default wallet address: %w", err)
+		}
+		params.From = from
+	}
+
+	parts := splitValue(params.Val, n)
+
+	// the parts differ by at most 1 attoFIL, one estimate fits them all
+	msg := &types.Message{
+		From:   params.From,
+		To:     params.To,
+		Value:  parts[0],
+		Method: builtin.MethodSend,
+	}
+	if params.GasPremium != nil {
+		msg.GasPremium = *params.GasPremium
+	}
+	if params.GasFeeCap != nil {
+		msg.GasFeeCap = *params.GasFeeCap
+	}
+	if params.GasLimit != nil {
+		msg.GasLimit = *params.GasLimit
+	}
+	msg, err := fapi.GasEstimateMessageGas(ctx, msg, nil, types.EmptyTSK)
+	if err != nil {
+		return xerrors.Errorf("estimating gas: %w", err)
+	}
+	maxFee := types.BigMul(msg.GasFeeCap, types.NewInt(uint64(msg.GasLimit)))
+	totalFee := types.BigMul(maxFee, types.NewInt(uint64(n)))
+	total := types.BigAdd(params.Val, totalFee)
+
+	afmt.Printf("Sending %s from %s to %s in %d messages:\n", types.FIL(params.Val), params.From, params.To, n)
+	tw := tabwriter.NewWriter(cctx.App.Writer, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tValue\tMax fee")
+	for i, v := range parts {
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", i+1, types.FIL(v), types.FIL(maxFee))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	afmt.Printf("Delivered: %s, the fees are paid on top\n", types.FIL(params.Val))
+	afmt.Printf("Total worst-case cost: %s value + %s max fees = %s\n", types.FIL(params.Val), types.FIL(totalFee), types.FIL(total))
+
+	if !params.Force {
+		balance, err := fapi.WalletBalance(ctx, params.From)
+		if err != nil {
+			return xerrors.Errorf("getting balance: %w", err)
+		}
+		if balance.LessThan(total) {
+			return xerrors.Errorf("From balance %s less than total worst-case cost %s: %w", types.FIL(balance), types.FIL(total), ErrSendBalanceTooLow)
+		}
+	}
+
+	afmt.Printf("Send the %d messages? [y]es, confirm [e]ach, or [N]o? ", n)
+	answer, err := readAnswer(stdin)
+	if err != nil {
+		return err
+	}
+	each := answer == "e" || answer == "each"
+	if !each && answer != "y" && answer != "yes" {
+		return xerrors.Errorf("split send: %w", ErrAbortedByUser)
+	}
+
+	var sent []cid.Cid
+	for i, v := range parts {
+		if each {
+			afmt.Printf("Send message %d of %d, %s? [y/N] ", i+1, n, types.FIL(v))
+			answer, err := readAnswer(stdin)
+			if err != nil {
+				return err
+			}
+			if answer != "y" && answer != "yes" {
+				afmt.Printf("Stopped after sending %d of %d messages, %s\n", len(sent), n, types.FIL(sentValue(parts, len(sent))))
+				return xerrors.Errorf("split send: %w", ErrAbortedByUser)
+			}
+		}
+
+		part := params
+		part.Val = v
+		if params.IdempotencyKey != "" {
+			part.IdempotencyKey = fmt.Sprintf("%s/%d", params.IdempotencyKey, i+1)
+		}
+
+		c, err := srv.Send(ctx, part)
+		if err != nil {
+			afmt.Printf("Stopped after sending %d of %d messages, %s\n", len(sent), n, types.FIL(sentValue(parts, len(sent))))
+			return WithExitStatus(xerrors.Errorf("executing send %d of %d: %w", i+1, n, err), ExitPushFailed)
+		}
+		sent = append(sent, c)
+		afmt.Println(c)
+
+		if memo := cctx.String("memo"); memo != "" {
+			recordSendMemo(ctx, cctx, fapi, c, memo)
+		}
+	}
+
+	ctx = dl.pushed(ctx)
+	if cctx.Bool("wait") {
+		for i, c := range sent {
+			mw, err := waitMsgConfidence(ctx, cctx, fapi, c)
+			if err != nil {
+				return WithAPIExitStatus(xerrors.Errorf("waiting for message %d of %d: %w", i+1, n, err))
+			}
+			if mw.Receipt.ExitCode != 0 {
+				return xerrors.Errorf("message %d of %d: exit %d: %w", i+1, n, mw.Receipt.ExitCode, ErrExecutionFailed)
+			}
+			afmt.Printf("Message %d executed at epoch %d\n", i+1, mw.Height)
+		}
+	}
+	return nil
+}
+
+func sentValue(parts []abi.TokenAmount, sent int) abi.TokenAmount {
+	total := big.Zero()
+	for _, v := range parts[:sent] {
+		total = big.Add(total, v)
+	}
+	return total
+}
+
+func readAnswer(stdin *bufio.Reader) (string, error) {
+	line, err := stdin.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", xerrors.Errorf("reading answer: %w", err)
+	}
+	return strings.ToLower(strings.TrimSpace(line)), nil
+}
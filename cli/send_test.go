@@ -2,16 +2,31 @@ package cli
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/crypto"
+	lapi "github.com/filecoin-project/lotus/api"
+	mocks "github.com/filecoin-project/lotus/api/v0api/v0mocks"
+	"github.com/filecoin-project/lotus/build"
+	"github.com/filecoin-project/lotus/chain/messagepool"
 	types "github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/lib/sigs"
+	builtin5 "github.com/filecoin-project/specs-actors/v5/actors/builtin"
 	gomock "github.com/golang/mock/gomock"
 	cid "github.com/ipfs/go-cid"
 	"github.com/stretchr/testify/assert"
 	ucli "github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
 )
 
 var arbtCid = (&types.Message{
@@ -28,6 +43,23 @@ func mustAddr(a address.Address, err error) address.Address {
 }
 
 func newMockApp(t *testing.T, cmd *ucli.Command) (*ucli.App, *MockServicesAPI, *bytes.Buffer, func()) {
+	app, mockSrvcs, mockApi, buf, done := newMockAppWithFullNode(t, cmd)
+	mockApi.EXPECT().ChainGetMessage(gomock.Any(), gomock.Any()).Return(nil, xerrors.Errorf("not found")).AnyTimes()
+	expectSendEstimate(mockApi)
+	return app, mockSrvcs, buf, done
+}
+
+// expectSendEstimate makes the mock node estimate the messages of sends as
+// they are, sent from the default wallet address if they have no sender
+func expectSendEstimate(mockApi *mocks.MockFullNode) {
+	mockApi.EXPECT().WalletDefaultAddress(gomock.Any()).Return(mustAddr(address.NewIDAddress(2)), nil).AnyTimes()
+	mockApi.EXPECT().GasEstimateMessageGas(gomock.Any(), gomock.Any(), gomock.Any(), types.EmptyTSK).DoAndReturn(
+		func(_ context.Context, m *types.Message, _ *lapi.MessageSendSpec, _ types.TipSetKey) (*types.Message, error) {
+			return m, nil
+		}).AnyTimes()
+}
+
+func newMockAppWithFullNode(t *testing.T, cmd *ucli.Command) (*ucli.App, *MockServicesAPI, *mocks.MockFullNode, *bytes.Buffer, func()) {
 	app := ucli.NewApp()
 	app.Commands = ucli.Commands{cmd}
 	app.Setup()
@@ -36,10 +68,14 @@ func newMockApp(t *testing.T, cmd *ucli.Command) (*ucli.App, *MockServicesAPI, *
 	mockSrvcs := NewMockServicesAPI(mockCtrl)
 	app.Metadata["test-services"] = mockSrvcs
 
+	mockApi := mocks.NewMockFullNode(mockCtrl)
+	mockSrvcs.EXPECT().FullNodeAPI().Return(mockApi).AnyTimes()
+	mockApi.EXPECT().Version(gomock.Any()).Return(lapi.APIVersion{APIVersion: lapi.FullAPIVersion0, BlockGasLimit: build.BlockGasLimit}, nil).AnyTimes()
+
 	buf := &bytes.Buffer{}
 	app.Writer = buf
 
-	return app, mockSrvcs, buf, mockCtrl.Finish
+	return app, mockSrvcs, mockApi, buf, mockCtrl.Finish
 }
 
 func TestSendCLI(t *testing.T) {
@@ -125,4 +161,795 @@ func TestSendCLI(t *testing.T) {
 		assert.EqualValues(t, arbtCid.String()+"\n", buf.String())
 	})
 
+	t.Run("via-msig", func(t *testing.T) {
+		app, mockSrvcs, buf, done := newMockApp(t, sendCmd)
+		defer done()
+
+		to := mustAddr(address.NewIDAddress(1))
+		msig := mustAddr(address.NewIDAddress(3))
+
+		gomock.InOrder(
+			mockSrvcs.EXPECT().MsigThreshold(gomock.Any(), msig).Return(uint64(2), nil),
+			mockSrvcs.EXPECT().Send(gomock.Any(), SendParams{
+				To:      to,
+				Val:     oneFil,
+				ViaMsig: msig,
+			}).Return(arbtCid, nil),
+			mockSrvcs.EXPECT().Close(),
+		)
+		err := app.Run([]string{"lotus", "send", "--via-msig=" + msig.String(), to.String(), "1"})
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), "Proposed through multisig "+msig.String())
+		assert.Contains(t, buf.String(), "needs 2 approvals")
+		assert.True(t, strings.HasSuffix(buf.String(), arbtCid.String()+"\n"))
+	})
+	t.Run("worst-case-cost", func(t *testing.T) {
+		app, mockSrvcs, mockApi, buf, done := newMockAppWithFullNode(t, sendCmd)
+		defer done()
+		errBuf := &bytes.Buffer{}
+		app.ErrWriter = errBuf
+
+		to := mustAddr(address.NewIDAddress(1))
+		from := mustAddr(address.NewIDAddress(2))
+		estimated := &types.Message{
+			From:      from,
+			To:        to,
+			Value:     oneFil,
+			GasLimit:  1000,
+			GasFeeCap: abi.NewTokenAmount(1_000_000),
+		}
+		gomock.InOrder(
+			mockApi.EXPECT().WalletDefaultAddress(gomock.Any()).Return(from, nil),
+			mockApi.EXPECT().GasEstimateMessageGas(gomock.Any(), gomock.Any(), nil, types.EmptyTSK).Return(estimated, nil),
+			mockSrvcs.EXPECT().Send(gomock.Any(), SendParams{To: to, Val: oneFil}).Return(arbtCid, nil),
+			mockApi.EXPECT().ChainGetMessage(gomock.Any(), arbtCid).Return(estimated, nil),
+			mockApi.EXPECT().MpoolFeeLevel(gomock.Any(), arbtCid).Return(&lapi.FeeLevelStats{
+				MinRatio:    0.9,
+				MaxRatio:    1.1,
+				QuickEpochs: 5,
+				Sends:       10,
+				Quick:       8,
+				Slow:        1,
+				Stalled:     1,
+			}, nil),
+			mockSrvcs.EXPECT().Close(),
+		)
+		err := app.Run([]string{"lotus", "send", to.String(), "1"})
+		assert.NoError(t, err)
+		assert.Equal(t, arbtCid.String()+"\n", buf.String())
+		assert.Contains(t, errBuf.String(), "Total worst-case cost: 1 WD value + 0.000000001 WD max fee = 1.000000001 WD")
+		assert.Contains(t, errBuf.String(), "Fees at this level (0.9-1.1x estimate) landed within 5 epochs 80% of the time recently, over 10 sends")
+	})
+
+	t.Run("balance-impact-locked", func(t *testing.T) {
+		app, mockSrvcs, buf, done := newMockApp(t, sendCmd)
+		defer done()
+		errBuf := &bytes.Buffer{}
+		app.ErrWriter = errBuf
+
+		to := mustAddr(address.NewIDAddress(1))
+		msig := mustAddr(address.NewIDAddress(3))
+		gomock.InOrder(
+			mockSrvcs.EXPECT().MsigThreshold(gomock.Any(), msig).Return(uint64(2), nil),
+			mockSrvcs.EXPECT().AccountBalance(gomock.Any(), msig).Return(AccountBalance{
+				Address:   msig,
+				Actor:     "fil/5/multisig",
+				Total:     abi.TokenAmount(types.MustParseFIL("3")),
+				Available: abi.TokenAmount(types.MustParseFIL("0.5")),
+				Locked:    abi.TokenAmount(types.MustParseFIL("2.5")),
+			}, nil),
+			mockSrvcs.EXPECT().Send(gomock.Any(), SendParams{To: to, Val: oneFil, ViaMsig: msig}).Return(arbtCid, nil),
+			mockSrvcs.EXPECT().Close(),
+		)
+		err := app.Run([]string{"lotus", "send", "--via-msig", msig.String(), "--balance-impact", to.String(), "1"})
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), arbtCid.String())
+		assert.Contains(t, errBuf.String(), "available: 0.5 WD")
+		assert.Contains(t, errBuf.String(), "locked:    2.5 WD")
+		assert.Contains(t, errBuf.String(), "WARNING: sending 1 WD needs 0.5 WD of the locked funds")
+	})
+
+	t.Run("balance-impact-available", func(t *testing.T) {
+		app, mockSrvcs, buf, done := newMockApp(t, sendCmd)
+		defer done()
+		errBuf := &bytes.Buffer{}
+		app.ErrWriter = errBuf
+
+		to := mustAddr(address.NewIDAddress(1))
+		gomock.InOrder(
+			mockSrvcs.EXPECT().AccountBalance(gomock.Any(), address.Undef).Return(AccountBalance{
+				Address:   mustAddr(address.NewIDAddress(2)),
+				Actor:     "fil/5/account",
+				Total:     abi.TokenAmount(types.MustParseFIL("3")),
+				Available: abi.TokenAmount(types.MustParseFIL("3")),
+				Locked:    abi.NewTokenAmount(0),
+			}, nil),
+			mockSrvcs.EXPECT().Send(gomock.Any(), SendParams{To: to, Val: oneFil}).Return(arbtCid, nil),
+			mockSrvcs.EXPECT().Close(),
+		)
+		err := app.Run([]string{"lotus", "send", "--balance-impact", to.String(), "1"})
+		assert.NoError(t, err)
+		assert.Equal(t, arbtCid.String()+"\n", buf.String())
+		assert.Contains(t, errBuf.String(), "Available after the send: 2 WD, less the gas fee")
+	})
+
+	t.Run("gas-block-share", func(t *testing.T) {
+		app, mockSrvcs, mockApi, buf, done := newMockAppWithFullNode(t, sendCmd)
+		defer done()
+		errBuf := &bytes.Buffer{}
+		app.ErrWriter = errBuf
+
+		to := mustAddr(address.NewIDAddress(1))
+		msg := &types.Message{
+			From:       mustAddr(address.NewIDAddress(2)),
+			To:         to,
+			Value:      oneFil,
+			GasLimit:   3_000_000_000,
+			GasFeeCap:  abi.NewTokenAmount(1),
+			GasPremium: abi.NewTokenAmount(1),
+		}
+		gomock.InOrder(
+			mockSrvcs.EXPECT().Send(gomock.Any(), SendParams{To: to, Val: oneFil}).Return(arbtCid, nil),
+			mockSrvcs.EXPECT().Close(),
+		)
+		expectSendEstimate(mockApi)
+		mockApi.EXPECT().ChainGetMessage(gomock.Any(), arbtCid).Return(msg, nil)
+		mockApi.EXPECT().MpoolFeeLevel(gomock.Any(), arbtCid).Return(nil, nil)
+
+		err := app.Run([]string{"lotus", "send", "--gas-block-share", to.String(), "1"})
+		assert.NoError(t, err)
+		assert.Equal(t, arbtCid.String()+"\n", buf.String())
+		assert.Contains(t, errBuf.String(), "Gas limit 3000000000 is 30.00% of the block gas limit 10000000000")
+		assert.Contains(t, errBuf.String(), "WARNING: messages using over 25% of a block")
+	})
+
+	t.Run("reference-prompt", func(t *testing.T) {
+		app, mockSrvcs, buf, done := newMockApp(t, sendCmd)
+		defer done()
+		app.Metadata["stdin"] = strings.NewReader("  TICKET-42 approved by ops\n")
+
+		to := mustAddr(address.NewIDAddress(1))
+		params := SendParams{
+			To:  to,
+			Val: oneFil,
+		}
+		withRef := params
+		withRef.Reference = "TICKET-42 approved by ops"
+
+		gomock.InOrder(
+			mockSrvcs.EXPECT().Send(gomock.Any(), params).Return(cid.Undef, xerrors.Errorf("mpool push: %s (sending 1 WD)", lapi.ErrSendReferenceRequired)),
+			mockSrvcs.EXPECT().Send(gomock.Any(), withRef).Return(arbtCid, nil),
+			mockSrvcs.EXPECT().Close(),
+		)
+		err := app.Run([]string{"lotus", "send", to.String(), "1"})
+		assert.NoError(t, err)
+		assert.True(t, strings.HasSuffix(buf.String(), arbtCid.String()+"\n"))
+	})
+
+	t.Run("reference-empty", func(t *testing.T) {
+		app, mockSrvcs, _, done := newMockApp(t, sendCmd)
+		defer done()
+		app.Metadata["stdin"] = strings.NewReader("\n")
+
+		gomock.InOrder(
+			mockSrvcs.EXPECT().Send(gomock.Any(), gomock.Any()).Return(cid.Undef, lapi.ErrSendReferenceRequired),
+			mockSrvcs.EXPECT().Close(),
+		)
+		err := app.Run([]string{"lotus", "send", mustAddr(address.NewIDAddress(1)).String(), "1"})
+		assert.Error(t, err)
+	})
+
+	t.Run("push-retry", func(t *testing.T) {
+		app, mockSrvcs, buf, done := newMockApp(t, sendCmd)
+		defer done()
+		app.Metadata["stdin"] = strings.NewReader("x\nr\n")
+
+		to := mustAddr(address.NewIDAddress(1))
+		gomock.InOrder(
+			mockSrvcs.EXPECT().Send(gomock.Any(), gomock.Any()).Return(cid.Undef, xerrors.Errorf("mpool push: %w", messagepool.ErrGasFeeCapTooLow)),
+			mockSrvcs.EXPECT().Send(gomock.Any(), gomock.Any()).Return(arbtCid, nil),
+			mockSrvcs.EXPECT().Close(),
+		)
+		err := app.Run([]string{"lotus", "send", to.String(), "1"})
+		assert.NoError(t, err)
+		assert.NotContains(t, buf.String(), "may have accepted")
+		assert.True(t, strings.HasSuffix(buf.String(), arbtCid.String()+"\n"))
+	})
+
+	t.Run("push-no-retry-prompt", func(t *testing.T) {
+		app, mockSrvcs, buf, done := newMockApp(t, sendCmd)
+		defer done()
+		app.Metadata["stdin"] = strings.NewReader("r\n")
+
+		to := mustAddr(address.NewIDAddress(1))
+		gomock.InOrder(
+			mockSrvcs.EXPECT().Send(gomock.Any(), gomock.Any()).Return(cid.Undef, xerrors.Errorf("mpool push: %w", messagepool.ErrGasFeeCapTooLow)),
+			mockSrvcs.EXPECT().Close(),
+		)
+		err := app.Run([]string{"lotus", "send", "--no-retry-prompt", to.String(), "1"})
+		assert.True(t, errors.Is(err, messagepool.ErrGasFeeCapTooLow))
+		assert.NotContains(t, buf.String(), "[a]bort")
+	})
+
+	t.Run("push-retry-reestimate", func(t *testing.T) {
+		app, mockSrvcs, buf, done := newMockApp(t, sendCmd)
+		defer done()
+		app.Metadata["stdin"] = strings.NewReader("e\n")
+
+		to := mustAddr(address.NewIDAddress(1))
+		feeCap := abi.NewTokenAmount(100)
+		gomock.InOrder(
+			mockSrvcs.EXPECT().Send(gomock.Any(), SendParams{To: to, Val: oneFil, GasFeeCap: &feeCap}).Return(cid.Undef, errors.New("websocket connection closed")),
+			mockSrvcs.EXPECT().Send(gomock.Any(), SendParams{To: to, Val: oneFil}).Return(arbtCid, nil),
+			mockSrvcs.EXPECT().Close(),
+		)
+		err := app.Run([]string{"lotus", "send", "--gas-feecap=100", to.String(), "1"})
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), "may have accepted")
+	})
+
+	t.Run("push-abort", func(t *testing.T) {
+		app, mockSrvcs, _, done := newMockApp(t, sendCmd)
+		defer done()
+		app.Metadata["stdin"] = strings.NewReader("a\n")
+
+		errMark := errors.New("connection refused")
+		gomock.InOrder(
+			mockSrvcs.EXPECT().Send(gomock.Any(), gomock.Any()).Return(cid.Undef, errMark),
+			mockSrvcs.EXPECT().Close(),
+		)
+		err := app.Run([]string{"lotus", "send", mustAddr(address.NewIDAddress(1)).String(), "1"})
+		assert.ErrorIs(t, err, errMark)
+	})
+
+	t.Run("push-permanent", func(t *testing.T) {
+		app, mockSrvcs, buf, done := newMockApp(t, sendCmd)
+		defer done()
+		app.Metadata["stdin"] = strings.NewReader("r\n")
+
+		gomock.InOrder(
+			// over RPC, only the message is left
+			mockSrvcs.EXPECT().Send(gomock.Any(), gomock.Any()).Return(cid.Undef, errors.New("mpool push: "+messagepool.ErrNotEnoughFunds.Error())),
+			mockSrvcs.EXPECT().Close(),
+		)
+		err := app.Run([]string{"lotus", "send", mustAddr(address.NewIDAddress(1)).String(), "1"})
+		assert.Error(t, err)
+		assert.NotContains(t, buf.String(), "[r]etry")
+	})
+
+	t.Run("split", func(t *testing.T) {
+		app, mockSrvcs, mockApi, buf, done := newMockAppWithFullNode(t, sendCmd)
+		defer done()
+		app.Metadata["stdin"] = strings.NewReader("e\ny\nn\n")
+
+		from, to := mustAddr(address.NewIDAddress(2)), mustAddr(address.NewIDAddress(1))
+		params := SendParams{From: from, To: to, IdempotencyKey: "payout"}
+		first := params
+		first.Val, first.IdempotencyKey = abi.NewTokenAmount(334), "payout/1"
+
+		gomock.InOrder(
+			mockApi.EXPECT().GasEstimateMessageGas(gomock.Any(), gomock.Any(), gomock.Any(), types.EmptyTSK).DoAndReturn(
+				func(_, m, _, _ interface{}) (*types.Message, error) {
+					msg := *m.(*types.Message)
+					msg.GasLimit, msg.GasFeeCap = 10, abi.NewTokenAmount(2)
+					return &msg, nil
+				}),
+			mockApi.EXPECT().WalletBalance(gomock.Any(), from).Return(abi.NewTokenAmount(1060), nil),
+			mockSrvcs.EXPECT().Send(gomock.Any(), first).Return(arbtCid, nil),
+			mockSrvcs.EXPECT().Close(),
+		)
+		err := app.Run([]string{"lotus", "send", "--from", from.String(), "--idempotency-key", "payout", "--split", "3", to.String(), "0.000000000000001"})
+		assert.True(t, errors.Is(err, ErrAbortedByUser))
+		assert.Contains(t, buf.String(), "Total worst-case cost: 0.000000000000001 WD value + 0.00000000000000006 WD max fees = 0.00000000000000106 WD")
+		assert.Contains(t, buf.String(), "Stopped after sending 1 of 3 messages, 0.000000000000000334 WD")
+	})
+}
+
+func TestSendRetryLast(t *testing.T) {
+	repo := t.TempDir()
+	to := mustAddr(address.NewIDAddress(1))
+	from := mustAddr(address.NewIDAddress(2))
+	params := SendParams{To: to, From: from, Val: abi.TokenAmount(types.MustParseFIL("2")), Force: true}
+
+	run := func(t *testing.T, args ...string) (*MockServicesAPI, func() error, *bytes.Buffer) {
+		app, mockSrvcs, _, done := newMockApp(t, sendCmd)
+		t.Cleanup(done)
+		app.Flags = append(app.Flags, &ucli.StringFlag{Name: "repo"})
+		errBuf := &bytes.Buffer{}
+		app.ErrWriter = errBuf
+		return mockSrvcs, func() error {
+			return app.Run(append([]string{"lotus", "--repo", repo, "send"}, args...))
+		}, errBuf
+	}
+
+	t.Run("nothing-to-retry", func(t *testing.T) {
+		mockSrvcs, send, _ := run(t, "--retry-last")
+		mockSrvcs.EXPECT().Close()
+		assert.EqualError(t, send(), "no failed send to retry")
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		mockSrvcs, send, errBuf := run(t, "--from", from.String(), "--force", "--memo", "invoice 7", to.String(), "2")
+		gomock.InOrder(
+			mockSrvcs.EXPECT().Send(gomock.Any(), params).Return(cid.Undef, messagepool.ErrNotEnoughFunds),
+			mockSrvcs.EXPECT().Close(),
+		)
+		assert.Error(t, send())
+		assert.Contains(t, errBuf.String(), "lotus send --retry-last")
+	})
+
+	t.Run("retried", func(t *testing.T) {
+		mockSrvcs, send, errBuf := run(t, "--retry-last")
+		gomock.InOrder(
+			mockSrvcs.EXPECT().Send(gomock.Any(), params).Return(arbtCid, nil),
+			mockSrvcs.EXPECT().Close(),
+		)
+		mockSrvcs.FullNodeAPI().(*mocks.MockFullNode).EXPECT().MpoolSetMemo(gomock.Any(), lapi.MessageMemo{Message: arbtCid, Memo: "invoice 7"}).Return(nil)
+		assert.NoError(t, send())
+		assert.Contains(t, errBuf.String(), "Retrying the send of 2 WD from "+from.String()+" to "+to.String()+", method 0, which failed 0s ago")
+		assert.Contains(t, errBuf.String(), "not enough funds")
+	})
+
+	t.Run("cleared", func(t *testing.T) {
+		mockSrvcs, send, _ := run(t, "--retry-last")
+		mockSrvcs.EXPECT().Close()
+		assert.EqualError(t, send(), "no failed send to retry")
+	})
+
+	t.Run("excluded-flags", func(t *testing.T) {
+		mockSrvcs, send, _ := run(t, "--retry-last", "--nonce", "3")
+		mockSrvcs.EXPECT().Close()
+		assert.EqualError(t, send(), "--nonce can't be used with --retry-last, the send is retried as it was")
+	})
+}
+
+func TestSendPreset(t *testing.T) {
+	repo := t.TempDir()
+	to := mustAddr(address.NewIDAddress(1))
+	from := mustAddr(address.NewIDAddress(2))
+	premium := abi.NewTokenAmount(100)
+
+	run := func(t *testing.T, args ...string) (*MockServicesAPI, *mocks.MockFullNode, func() error, *bytes.Buffer, *bytes.Buffer) {
+		app, mockSrvcs, mockApi, buf, done := newMockAppWithFullNode(t, sendCmd)
+		t.Cleanup(done)
+		mockApi.EXPECT().ChainGetMessage(gomock.Any(), gomock.Any()).Return(nil, xerrors.Errorf("not found")).AnyTimes()
+		expectSendEstimate(mockApi)
+		app.Flags = append(app.Flags, &ucli.StringFlag{Name: "repo"})
+		errBuf := &bytes.Buffer{}
+		app.ErrWriter = errBuf
+		return mockSrvcs, mockApi, func() error {
+			return app.Run(append([]string{"lotus", "--repo", repo, "send"}, args...))
+		}, buf, errBuf
+	}
+
+	t.Run("one-time-values", func(t *testing.T) {
+		mockSrvcs, _, send, _, _ := run(t, "--save-preset", "payouts", "--nonce", "3", to.String())
+		mockSrvcs.EXPECT().Close()
+		assert.EqualError(t, send(), "--nonce is a one-time value, presets don't keep it")
+	})
+
+	t.Run("save", func(t *testing.T) {
+		mockSrvcs, mockApi, send, buf, _ := run(t, "--save-preset", "payouts", "--from", from.String(), "--gas-premium", "100", to.String())
+		mockSrvcs.EXPECT().Close()
+		mockApi.EXPECT().StateLookupID(gomock.Any(), to, types.EmptyTSK).Return(to, nil)
+		assert.NoError(t, send())
+		assert.Contains(t, buf.String(), `Saved preset "payouts"`)
+	})
+
+	t.Run("send", func(t *testing.T) {
+		mockSrvcs, mockApi, send, _, errBuf := run(t, "--preset", "payouts", "3")
+		gomock.InOrder(
+			mockSrvcs.EXPECT().Send(gomock.Any(), SendParams{To: to, From: from, Val: abi.TokenAmount(types.MustParseFIL("3")), GasPremium: &premium}).Return(arbtCid, nil),
+			mockSrvcs.EXPECT().Close(),
+		)
+		mockApi.EXPECT().StateLookupID(gomock.Any(), to, types.EmptyTSK).Return(to, nil)
+		mockApi.EXPECT().MpoolPending(gomock.Any(), types.EmptyTSK).Return(nil, nil)
+		assert.NoError(t, send())
+		assert.Contains(t, errBuf.String(), `Using preset "payouts"`)
+		assert.NotContains(t, errBuf.String(), "WARNING: recipient")
+	})
+
+	t.Run("unresolved", func(t *testing.T) {
+		mockSrvcs, mockApi, send, _, errBuf := run(t, "--preset", "payouts", "--gas-premium", "0", "3")
+		gomock.InOrder(
+			mockSrvcs.EXPECT().Send(gomock.Any(), gomock.Any()).Return(arbtCid, nil),
+			mockSrvcs.EXPECT().Close(),
+		)
+		mockApi.EXPECT().StateLookupID(gomock.Any(), to, types.EmptyTSK).Return(address.Undef, xerrors.Errorf("actor not found"))
+		mockApi.EXPECT().MpoolPending(gomock.Any(), types.EmptyTSK).Return(nil, nil)
+		assert.NoError(t, send())
+		assert.Contains(t, errBuf.String(), `WARNING: recipient `+to.String()+` of preset "payouts" no longer resolves on chain`)
+	})
+
+	t.Run("save-placeholders", func(t *testing.T) {
+		mockSrvcs, _, send, buf, errBuf := run(t, "--save-preset", "withdraw", "--from", from.String(), "{{to}}", "{{amount}}")
+		mockSrvcs.EXPECT().Close()
+		assert.NoError(t, send())
+		assert.Contains(t, buf.String(), `Saved preset "withdraw"`)
+		assert.Contains(t, errBuf.String(), "Preset has placeholders amount, to")
+	})
+
+	t.Run("missing-placeholder", func(t *testing.T) {
+		mockSrvcs, _, send, _, _ := run(t, "--preset", "withdraw", "--set", "amount=2")
+		mockSrvcs.EXPECT().Close()
+		assert.EqualError(t, send(), `preset "withdraw": no value for placeholders to, give them with --set name=value`)
+	})
+
+	t.Run("unknown-placeholder", func(t *testing.T) {
+		mockSrvcs, _, send, _, _ := run(t, "--preset", "withdraw", "--set", "amount=2", "--set", "to="+to.String(), "--set", "memo=x")
+		mockSrvcs.EXPECT().Close()
+		assert.EqualError(t, send(), `preset "withdraw" has no placeholder "memo"`)
+	})
+
+	t.Run("send-placeholders", func(t *testing.T) {
+		mockSrvcs, mockApi, send, _, _ := run(t, "--preset", "withdraw", "--set", "amount=2", "--set", "to="+to.String())
+		gomock.InOrder(
+			mockSrvcs.EXPECT().Send(gomock.Any(), SendParams{To: to, From: from, Val: abi.TokenAmount(types.MustParseFIL("2"))}).Return(arbtCid, nil),
+			mockSrvcs.EXPECT().Close(),
+		)
+		mockApi.EXPECT().StateLookupID(gomock.Any(), to, types.EmptyTSK).Return(to, nil)
+		assert.NoError(t, send())
+	})
+
+	t.Run("list", func(t *testing.T) {
+		mockSrvcs, _, send, buf, _ := run(t, "--list-presets")
+		mockSrvcs.EXPECT().Close().AnyTimes()
+		assert.NoError(t, send())
+		assert.Contains(t, buf.String(), "--from="+from.String()+" --gas-premium=100")
+		assert.Contains(t, buf.String(), "amount,to")
+	})
+}
+
+func TestSendPreHook(t *testing.T) {
+	to := mustAddr(address.NewIDAddress(1))
+	from := mustAddr(address.NewIDAddress(2))
+	oneFil := abi.TokenAmount(types.MustParseFIL("1"))
+	params := SendParams{To: to, From: from, Val: oneFil}
+
+	run := func(t *testing.T, args ...string) (*MockServicesAPI, func() error, *bytes.Buffer) {
+		app, mockSrvcs, _, done := newMockApp(t, sendCmd)
+		t.Cleanup(done)
+		errBuf := &bytes.Buffer{}
+		app.ErrWriter = errBuf
+		return mockSrvcs, func() error {
+			return app.Run(append([]string{"lotus", "send", "--from", from.String()}, args...))
+		}, errBuf
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "msg.json")
+		mockSrvcs, send, errBuf := run(t, "--pre-send-hook", "cat > "+out, "--memo", "invoice 7", to.String(), "1")
+		gomock.InOrder(
+			mockSrvcs.EXPECT().Send(gomock.Any(), params).Return(arbtCid, nil),
+			mockSrvcs.EXPECT().Close(),
+		)
+		mockSrvcs.FullNodeAPI().(*mocks.MockFullNode).EXPECT().MpoolSetMemo(gomock.Any(), gomock.Any()).Return(nil)
+		assert.NoError(t, send())
+		assert.Contains(t, errBuf.String(), "Send allowed by the pre-send hook")
+
+		b, err := ioutil.ReadFile(out)
+		assert.NoError(t, err)
+		var in PreSendHookInput
+		assert.NoError(t, json.Unmarshal(b, &in))
+		assert.Equal(t, from, in.Message.From)
+		assert.Equal(t, to, in.Message.To)
+		assert.Equal(t, oneFil, in.Message.Value)
+		assert.Equal(t, "invoice 7", in.Memo)
+	})
+
+	t.Run("vetoed", func(t *testing.T) {
+		mockSrvcs, send, errBuf := run(t, "--pre-send-hook", "echo over the daily limit; exit 2", to.String(), "1")
+		mockSrvcs.EXPECT().Close()
+		err := send()
+		assert.True(t, errors.Is(err, ErrAbortedByUser))
+		assert.Contains(t, err.Error(), "vetoed by the pre-send hook (exit 2)")
+		assert.Contains(t, errBuf.String(), "over the daily limit")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		mockSrvcs, send, _ := run(t, "--pre-send-hook", "sleep 10", "--pre-send-hook-timeout", "100ms", to.String(), "1")
+		mockSrvcs.EXPECT().Close()
+		start := time.Now()
+		err := send()
+		assert.True(t, errors.Is(err, ErrWaitTimeout))
+		assert.Less(t, int64(time.Since(start)), int64(5*time.Second))
+	})
+}
+
+func TestSendDeadline(t *testing.T) {
+	to := mustAddr(address.NewIDAddress(1))
+	from := mustAddr(address.NewIDAddress(2))
+
+	run := func(t *testing.T, args ...string) (*MockServicesAPI, func() error) {
+		app, mockSrvcs, _, done := newMockApp(t, sendCmd)
+		t.Cleanup(done)
+		// a prompt nobody answers
+		pr, pw := io.Pipe()
+		t.Cleanup(func() { _ = pw.Close() })
+		app.Metadata["stdin"] = pr
+		app.ErrWriter = &bytes.Buffer{}
+		return mockSrvcs, func() error {
+			return app.Run(append([]string{"lotus", "send", "--from", from.String()}, args...))
+		}
+	}
+
+	t.Run("prompt", func(t *testing.T) {
+		mockSrvcs, send := run(t, "--deadline", "100ms", "--critical", "--confirm-word", "yes", to.String(), "1")
+		mockSrvcs.EXPECT().Close()
+		start := time.Now()
+		err := send()
+		assert.True(t, errors.Is(err, ErrDeadlineExceeded))
+		assert.Equal(t, ExitDeadline, ExitStatus(err))
+		assert.Contains(t, err.Error(), "during the prompts, nothing was sent")
+		assert.Less(t, int64(time.Since(start)), int64(5*time.Second))
+	})
+
+	t.Run("hook", func(t *testing.T) {
+		mockSrvcs, send := run(t, "--deadline", "100ms", "--pre-send-hook", "sleep 10", to.String(), "1")
+		mockSrvcs.EXPECT().Close()
+		err := send()
+		assert.True(t, errors.Is(err, ErrDeadlineExceeded))
+		assert.Contains(t, err.Error(), "during the pre-send hook")
+	})
+
+	t.Run("in-time", func(t *testing.T) {
+		mockSrvcs, send := run(t, "--deadline", "1m", to.String(), "1")
+		gomock.InOrder(
+			mockSrvcs.EXPECT().Send(gomock.Any(), gomock.Any()).Return(arbtCid, nil),
+			mockSrvcs.EXPECT().Close(),
+		)
+		assert.NoError(t, send())
+	})
+
+	t.Run("passed", func(t *testing.T) {
+		_, send := run(t, "--deadline", time.Now().Add(-time.Minute).Format(time.RFC3339), to.String(), "1")
+		err := send()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "has already passed")
+	})
+}
+
+func TestSplitValue(t *testing.T) {
+	parts := splitValue(abi.NewTokenAmount(11), 3)
+	assert.Equal(t, []abi.TokenAmount{abi.NewTokenAmount(4), abi.NewTokenAmount(4), abi.NewTokenAmount(3)}, parts)
+	assert.Equal(t, abi.NewTokenAmount(11), sentValue(parts, 3))
+	assert.Equal(t, abi.NewTokenAmount(4), sentValue(parts, 1))
+}
+
+func TestCompetitivePremium(t *testing.T) {
+	pending := func(premiums ...int64) []*types.SignedMessage {
+		var out []*types.SignedMessage
+		for _, p := range premiums {
+			out = append(out, &types.SignedMessage{Message: types.Message{GasLimit: 40, GasPremium: abi.NewTokenAmount(p)}})
+		}
+		return out
+	}
+
+	// the three paying the most fill the block
+	assert.Equal(t, abi.NewTokenAmount(30), competitivePremium(pending(10, 50, 30, 20, 40), 100))
+	// the mempool doesn't fill a block, any premium makes it in
+	assert.Equal(t, abi.NewTokenAmount(0), competitivePremium(pending(10, 50), 100))
+	assert.Equal(t, abi.NewTokenAmount(0), competitivePremium(nil, 100))
+}
+
+func TestSendUnknownMethod(t *testing.T) {
+	to := mustAddr(address.NewIDAddress(1))
+	params := SendParams{To: to, Val: abi.NewTokenAmount(0), Method: 99}
+
+	app, mockSrvcs, mockApi, _, done := newMockAppWithFullNode(t, sendCmd)
+	defer done()
+	errBuf := &bytes.Buffer{}
+	app.ErrWriter = errBuf
+
+	mockApi.EXPECT().ChainGetMessage(gomock.Any(), gomock.Any()).Return(nil, xerrors.Errorf("not found")).AnyTimes()
+	expectSendEstimate(mockApi)
+	mockApi.EXPECT().StateGetActor(gomock.Any(), to, types.EmptyTSK).Return(&types.Actor{Code: builtin5.AccountActorCodeID}, nil)
+	gomock.InOrder(
+		mockSrvcs.EXPECT().Send(gomock.Any(), params).Return(arbtCid, nil),
+		mockSrvcs.EXPECT().Close(),
+	)
+
+	// the send goes on, warned about
+	assert.NoError(t, app.Run([]string{"lotus", "send", "--method", "99", to.String(), "0"}))
+	assert.Contains(t, errBuf.String(), "WARNING: unknown method: method 99 doesn't exist on account actor")
+}
+
+func TestCompatibleAPIVersion(t *testing.T) {
+	send := lapi.FullAPIVersion0 // 1.4.0
+
+	assert.True(t, compatibleAPIVersion(send, send))
+	assert.True(t, compatibleAPIVersion(send+1, send))     // newer patch
+	assert.True(t, compatibleAPIVersion(send+1<<8, send))  // newer minor
+	assert.False(t, compatibleAPIVersion(send-1<<8, send)) // older minor
+	assert.False(t, compatibleAPIVersion(lapi.FullAPIVersion1, send))
+}
+
+func TestSendConfirmWord(t *testing.T) {
+	to := mustAddr(address.NewIDAddress(1))
+	params := SendParams{To: to, Val: abi.TokenAmount(types.MustParseFIL("100"))}
+
+	run := func(t *testing.T, stdin string, sends bool, args ...string) error {
+		app, mockSrvcs, _, done := newMockApp(t, sendCmd)
+		defer done()
+		app.Metadata["stdin"] = strings.NewReader(stdin)
+
+		if sends {
+			mockSrvcs.EXPECT().Send(gomock.Any(), params).Return(arbtCid, nil)
+		}
+		mockSrvcs.EXPECT().Close()
+		return app.Run(append(append([]string{"lotus", "send"}, args...), to.String(), "100"))
+	}
+
+	t.Run("below-threshold", func(t *testing.T) {
+		assert.NoError(t, run(t, "", true, "--confirm-word", "CONFIRM", "--confirm-above", "1000"))
+	})
+	t.Run("above-threshold", func(t *testing.T) {
+		assert.NoError(t, run(t, "CONFIRM\n", true, "--confirm-word", "CONFIRM", "--confirm-above", "10"))
+	})
+	t.Run("mismatch", func(t *testing.T) {
+		err := run(t, "confirm\n", false, "--confirm-word", "CONFIRM", "--confirm-above", "10")
+		assert.True(t, errors.Is(err, ErrAbortedByUser))
+		assert.Contains(t, err.Error(), "doesn't match")
+
+		err = run(t, "\n", false, "--confirm-word", "CONFIRM", "--critical")
+		assert.True(t, errors.Is(err, ErrAbortedByUser))
+	})
+	t.Run("ignore-case", func(t *testing.T) {
+		assert.NoError(t, run(t, "confirm\n", true, "--confirm-word", "CONFIRM", "--confirm-ignore-case", "--critical"))
+	})
+	t.Run("critical-without-word", func(t *testing.T) {
+		assert.Error(t, run(t, "", false, "--critical"))
+	})
+}
+
+func TestSendTwoPerson(t *testing.T) {
+	repo := t.TempDir()
+	to := mustAddr(address.NewIDAddress(1))
+	params := SendParams{To: to, Val: abi.TokenAmount(types.MustParseFIL("100"))}
+
+	var passphrases []string
+	defer func(read func(*ucli.Context, string) ([]byte, error)) { readOperatorPassphrase = read }(readOperatorPassphrase)
+	readOperatorPassphrase = func(*ucli.Context, string) ([]byte, error) {
+		if len(passphrases) == 0 {
+			return nil, io.EOF
+		}
+		p := passphrases[0]
+		passphrases = passphrases[1:]
+		return []byte(p), nil
+	}
+
+	run := func(t *testing.T, stdin string, sends bool, args ...string) error {
+		app, mockSrvcs, _, done := newMockApp(t, sendCmd)
+		defer done()
+		app.Flags = append(app.Flags, &ucli.StringFlag{Name: "repo"})
+		app.ErrWriter = &bytes.Buffer{}
+		app.Metadata["stdin"] = strings.NewReader(stdin)
+
+		if sends {
+			mockSrvcs.EXPECT().Send(gomock.Any(), params).Return(arbtCid, nil)
+		}
+		mockSrvcs.EXPECT().Close().AnyTimes()
+		return app.Run(append(append([]string{"lotus", "--repo", repo, "send"}, args...), to.String(), "100"))
+	}
+
+	t.Run("no-operators", func(t *testing.T) {
+		assert.Error(t, run(t, "", false, "--two-person"))
+	})
+
+	t.Run("add-operators", func(t *testing.T) {
+		passphrases = []string{"alice pass", "alice typo"}
+		assert.EqualError(t, run(t, "", false, "--add-operator", "alice"), "passphrases don't match")
+
+		passphrases = []string{"alice pass", "alice pass", "bob pass", "bob pass"}
+		assert.NoError(t, run(t, "", false, "--add-operator", "alice"))
+		assert.NoError(t, run(t, "", false, "--add-operator", "bob"))
+		assert.Error(t, run(t, "", false, "--add-operator", "bob"))
+	})
+
+	t.Run("below-threshold", func(t *testing.T) {
+		assert.NoError(t, run(t, "", true, "--two-person-above", "1000"))
+	})
+	t.Run("approved", func(t *testing.T) {
+		passphrases = []string{"alice pass", "bob pass"}
+		assert.NoError(t, run(t, "alice\ny\nbob\nyes\n", true, "--two-person-above", "10"))
+	})
+	t.Run("same-operator", func(t *testing.T) {
+		passphrases = []string{"alice pass"}
+		err := run(t, "alice\ny\nalice\n", false, "--two-person")
+		assert.True(t, errors.Is(err, ErrAbortedByUser))
+		assert.Contains(t, err.Error(), "already approved")
+	})
+	t.Run("wrong-passphrase", func(t *testing.T) {
+		passphrases = []string{"alice pass", "alice pass"}
+		err := run(t, "alice\ny\nbob\n", false, "--two-person")
+		assert.True(t, errors.Is(err, ErrAbortedByUser))
+		assert.Contains(t, err.Error(), "wrong passphrase")
+	})
+	t.Run("declined", func(t *testing.T) {
+		passphrases = []string{"alice pass", "bob pass"}
+		err := run(t, "alice\ny\nbob\nn\n", false, "--two-person")
+		assert.True(t, errors.Is(err, ErrAbortedByUser))
+		assert.Contains(t, err.Error(), `declined by operator "bob"`)
+	})
+}
+
+func TestSendApproval(t *testing.T) {
+	priv, err := sigs.Generate(crypto.SigTypeSecp256k1)
+	assert.NoError(t, err)
+	pub, err := sigs.ToPublic(crypto.SigTypeSecp256k1, priv)
+	assert.NoError(t, err)
+	approver := mustAddr(address.NewSecp256k1Address(pub))
+
+	approve := func(msg cid.Cid, expires time.Time) []byte {
+		sig, err := sigs.Sign(crypto.SigTypeSecp256k1, priv, approvalSigningBytes(msg, expires))
+		assert.NoError(t, err)
+		b, err := json.Marshal(SendApproval{Message: msg, Expires: expires, Signature: sig})
+		assert.NoError(t, err)
+		return b
+	}
+
+	from, to := mustAddr(address.NewIDAddress(2)), mustAddr(address.NewIDAddress(1))
+	oneFil := abi.TokenAmount(types.MustParseFIL("1"))
+	pinned := &types.Message{
+		From:       from,
+		To:         to,
+		Value:      oneFil,
+		Nonce:      7,
+		GasLimit:   5000,
+		GasFeeCap:  abi.NewTokenAmount(1000),
+		GasPremium: abi.NewTokenAmount(100),
+	}
+
+	run := func(t *testing.T, approval []byte, sends bool) error {
+		app, mockSrvcs, mockApi, _, done := newMockAppWithFullNode(t, sendCmd)
+		defer done()
+		app.ErrWriter = &bytes.Buffer{}
+
+		path := filepath.Join(t.TempDir(), "approval.json")
+		assert.NoError(t, ioutil.WriteFile(path, approval, 0600))
+
+		mockApi.EXPECT().GasEstimateMessageGas(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, m *types.Message, _ *lapi.MessageSendSpec, _ types.TipSetKey) (*types.Message, error) {
+				est := *m
+				est.GasLimit, est.GasFeeCap, est.GasPremium = pinned.GasLimit, pinned.GasFeeCap, pinned.GasPremium
+				return &est, nil
+			})
+		mockApi.EXPECT().MpoolGetNonce(gomock.Any(), from).Return(pinned.Nonce, nil)
+		if sends {
+			mockSrvcs.EXPECT().Send(gomock.Any(), SendParams{
+				From:       from,
+				To:         to,
+				Val:        oneFil,
+				GasPremium: &pinned.GasPremium,
+				GasFeeCap:  &pinned.GasFeeCap,
+				GasLimit:   &pinned.GasLimit,
+				Nonce:      &pinned.Nonce,
+			}).Return(arbtCid, nil)
+			mockApi.EXPECT().ChainGetMessage(gomock.Any(), arbtCid).Return(nil, xerrors.Errorf("not found"))
+		}
+		mockSrvcs.EXPECT().Close()
+		return app.Run([]string{"lotus", "send", "--from", from.String(), "--approval-from", path, "--approver", approver.String(), to.String(), "1"})
+	}
+
+	t.Run("approved", func(t *testing.T) {
+		assert.NoError(t, run(t, approve(pinned.Cid(), time.Now().Add(time.Hour)), true))
+	})
+	t.Run("mismatched", func(t *testing.T) {
+		err := run(t, approve(arbtCid, time.Now().Add(time.Hour)), false)
+		assert.True(t, errors.Is(err, ErrAbortedByUser))
+		assert.Contains(t, err.Error(), "the approval is of message")
+	})
+	t.Run("verify", func(t *testing.T) {
+		expires := time.Now().Add(time.Hour).Truncate(time.Second)
+		var a SendApproval
+		assert.NoError(t, json.Unmarshal(approve(pinned.Cid(), expires), &a))
+		assert.NoError(t, a.verify(pinned.Cid(), approver, time.Now()))
+
+		err := a.verify(pinned.Cid(), approver, expires)
+		assert.Contains(t, err.Error(), "expired")
+
+		a.Expires = expires.Add(time.Hour)
+		err = a.verify(pinned.Cid(), approver, time.Now())
+		assert.Contains(t, err.Error(), "isn't signed by")
+	})
 }
@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/crypto/ssh/terminal"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+// The flags of sends which two operators sharing the terminal need to
+// approve, each with their own passphrase
+var (
+	twoPersonFlag = &cli.BoolFlag{
+		Name:  "two-person",
+		Usage: "require the approval of two distinct operators, see --add-operator",
+	}
+	twoPersonAboveFlag = &cli.StringFlag{
+		Name:    "two-person-above",
+		Usage:   "value above which sends need the approval of two distinct operators",
+		EnvVars: []string{"LOTUS_SEND_TWO_PERSON_ABOVE"},
+	}
+	addOperatorFlag = &cli.StringFlag{
+		Name:  "add-operator",
+		Usage: "register an operator who can approve two-person sends under this name, asking for their passphrase, without sending",
+	}
+)
+
+const sendOperatorsFile = "send-operators.json"
+
+// sendOperator is an operator who can approve two-person sends, known by
+// the scrypt hash of their passphrase
+type sendOperator struct {
+	Salt  []byte
+	Hash  []byte
+	Added time.Time
+}
+
+func operatorHash(passphrase, salt []byte) ([]byte, error) {
+	return scrypt.Key(passphrase, salt, 1<<15, 8, 1, 32)
+}
+
+// readOperatorPassphrase reads a passphrase without echoing it, replaced in
+// tests
+var readOperatorPassphrase = func(cctx *cli.Context, prompt string) ([]byte, error) {
+	fmt.Fprint(cctx.App.ErrWriter, prompt)
+	passphrase, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(cctx.App.ErrWriter)
+	if err != nil {
+		return nil, xerrors.Errorf("reading passphrase: %w", err)
+	}
+	return passphrase, nil
+}
+
+// sendOperatorsPath returns where the operators are kept, empty if there is no
+// local repo to keep them in
+func sendOperatorsPath(cctx *cli.Context) string {
+	return localRepoFile(cctx, sendOperatorsFile)
+}
+
+func loadSendOperators(cctx *cli.Context) (string, map[string]sendOperator, error) {
+	path := sendOperatorsPath(cctx)
+	if path == "" {
+		return "", nil, xerrors.Errorf("no repo to keep send operators in")
+	}
+
+	operators := map[string]sendOperator{}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return path, operators, nil
+	}
+	if err != nil {
+		return "", nil, xerrors.Errorf("reading send operators: %w", err)
+	}
+	if err := json.Unmarshal(b, &operators); err != nil {
+		return "", nil, xerrors.Errorf("decoding send operators: %w", err)
+	}
+	return path, operators, nil
+}
+
+// addSendOperator registers the operator named with --add-operator
+func addSendOperator(cctx *cli.Context) error {
+	name := strings.TrimSpace(cctx.String(addOperatorFlag.Name))
+	if name == "" {
+		return xerrors.Errorf("--%s can't be empty", addOperatorFlag.Name)
+	}
+
+	path, operators, err := loadSendOperators(cctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := operators[name]; ok {
+		return xerrors.Errorf("operator %q is already registered", name)
+	}
+
+	passphrase, err := readOperatorPassphrase(cctx, fmt.Sprintf("Passphrase of %s: ", name))
+	if err != nil {
+		return err
+	}
+	if len(passphrase) == 0 {
+		return xerrors.Errorf("the passphrase can't be empty")
+	}
+	again, err := readOperatorPassphrase(cctx, "Repeat passphrase: ")
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(passphrase, again) {
+		return xerrors.Errorf("passphrases don't match")
+	}
+
+	op := sendOperator{Salt: make([]byte, 16), Added: time.Now()}
+	if _, err := rand.Read(op.Salt); err != nil {
+		return err
+	}
+	if op.Hash, err = operatorHash(passphrase, op.Salt); err != nil {
+		return err
+	}
+	operators[name] = op
+
+	b, err := json.MarshalIndent(operators, "", "  ")
+	if err != nil {
+		return xerrors.Errorf("encoding send operators: %w", err)
+	}
+	if err := ioutil.WriteFile(path, b, 0600); err != nil {
+		return xerrors.Errorf("saving send operators: %w", err)
+	}
+	fmt.Fprintf(cctx.App.Writer, "Registered operator %q, %d operators can approve two-person sends\n", name, len(operators))
+	return nil
+}
+
+// confirmTwoPersonSend asks two distinct operators to approve the send if it
+// needs it, failing with ErrAbortedByUser if either declines
+func confirmTwoPersonSend(cctx *cli.Context, params SendParams, stdin *bufio.Reader) error {
+	required := cctx.Bool(twoPersonFlag.Name)
+	if !required && cctx.IsSet(twoPersonAboveFlag.Name) {
+		above, err := types.ParseFIL(cctx.String(twoPersonAboveFlag.Name))
+		if err != nil {
+			return xerrors.Errorf("parsing --%s: %w", twoPersonAboveFlag.Name, err)
+		}
+		required = params.Val.GreaterThan(types.BigInt(above))
+	}
+	if !required {
+		return nil
+	}
+
+	_, operators, err := loadSendOperators(cctx)
+	if err != nil {
+		return err
+	}
+	if len(operators) < 2 {
+		return xerrors.Errorf("two-person approval needs two registered operators, add them with --%s", addOperatorFlag.Name)
+	}
+
+	from := "the default wallet address"
+	if !params.From.Empty() {
+		from = params.From.String()
+	}
+	summary := fmt.Sprintf("Send %s from %s to %s, method %d, %d bytes of params", types.FIL(params.Val), from, params.To, params.Method, len(params.Params))
+
+	afmt := NewAppFmt(cctx.App)
+	readLine := func() (string, error) {
+		s, err := stdin.ReadString('\n')
+		if err != nil && (err != io.EOF || s == "") {
+			return "", xerrors.Errorf("reading answer: %w", err)
+		}
+		return strings.TrimSpace(s), nil
+	}
+
+	var approvers []string
+	for len(approvers) < 2 {
+		afmt.Printf("\nTwo-person approval, approver %d of 2:\n  %s\n", len(approvers)+1, summary)
+		afmt.Print("Operator: ")
+		name, err := readLine()
+		if err != nil {
+			return err
+		}
+		op, ok := operators[name]
+		if !ok {
+			return xerrors.Errorf("unknown operator %q, nothing was sent: %w", name, ErrAbortedByUser)
+		}
+		if len(approvers) == 1 && approvers[0] == name {
+			return xerrors.Errorf("operator %q already approved, the second approval must be another operator's, nothing was sent: %w", name, ErrAbortedByUser)
+		}
+
+		passphrase, err := readOperatorPassphrase(cctx, "Passphrase: ")
+		if err != nil {
+			return err
+		}
+		hash, err := operatorHash(passphrase, op.Salt)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(hash, op.Hash) {
+			return xerrors.Errorf("wrong passphrase for operator %q, nothing was sent: %w", name, ErrAbortedByUser)
+		}
+
+		afmt.Print("Approve this send? [y/N] ")
+		answer, err := readLine()
+		if err != nil {
+			return err
+		}
+		if a := strings.ToLower(answer); a != "y" && a != "yes" {
+			return xerrors.Errorf("declined by operator %q, nothing was sent: %w", name, ErrAbortedByUser)
+		}
+		approvers = append(approvers, name)
+	}
+
+	log.Infow("send approved by two operators", "first", approvers[0], "second", approvers[1], "from", from, "to", params.To, "value", types.FIL(params.Val))
+	fmt.Fprintf(cctx.App.ErrWriter, "Send approved by %s and %s\n", approvers[0], approvers[1])
+	return nil
+}
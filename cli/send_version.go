@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	lapi "github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/api/v0api"
+	"github.com/filecoin-project/lotus/build"
+)
+
+var skipAPIVersionCheckFlag = &cli.BoolFlag{
+	Name:  "skip-api-version-check",
+	Usage: "send even if the API version of the node is incompatible with this lotus",
+}
+
+// sendAPIVersion is the version of the full node API the send is written
+// against, the v0 API the CLI talks to. Its minor version 4 added the methods
+// the send uses besides the baseline ones, MpoolSetMemo, MpoolPropagation and
+// MpoolFeeLevel among them, so older nodes are turned away before sending.
+var sendAPIVersion = lapi.FullAPIVersion0
+
+// ErrIncompatibleAPIVersion is returned sending through a node which API
+// version is incompatible with the send
+var ErrIncompatibleAPIVersion = xerrors.New("incompatible node API version")
+
+// compatibleAPIVersion tells whether the send can go through a node with the
+// API version: the major versions must match, as they change when methods
+// break, and the node's minor version must be at least the send's, as minor
+// versions add the methods the send may use. Patch versions don't matter.
+func compatibleAPIVersion(node, send lapi.Version) bool {
+	nmj, nmi, _ := node.Ints()
+	smj, smi, _ := send.Ints()
+	return nmj == smj && nmi >= smi
+}
+
+// checkSendAPIVersion fails the send before any check or prompt if the node's
+// API version is incompatible, showing both versions
+func checkSendAPIVersion(ctx context.Context, cctx *cli.Context, api v0api.FullNode) error {
+	if cctx.Bool(skipAPIVersionCheckFlag.Name) {
+		return nil
+	}
+
+	v, err := api.Version(ctx)
+	if err != nil {
+		return xerrors.Errorf("getting node API version: %w", err)
+	}
+	if compatibleAPIVersion(v.APIVersion, sendAPIVersion) {
+		return nil
+	}
+
+	smj, smi, _ := sendAPIVersion.Ints()
+	return xerrors.Errorf("node %s has API version %s, this lotus (%s) sends through API version %s and needs %d.x with x >= %d; "+
+		"use a lotus matching the node, or --%s: %w",
+		v.Version, v.APIVersion, build.UserVersion(), sendAPIVersion, smj, smi, skipAPIVersionCheckFlag.Name, ErrIncompatibleAPIVersion)
+}
@@ -7,14 +7,24 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
+	"time"
 
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-jsonrpc"
 	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/api/errcode"
 	"github.com/filecoin-project/lotus/api/v0api"
+	"github.com/filecoin-project/lotus/blockstore"
+	"github.com/filecoin-project/lotus/chain/actors"
+	"github.com/filecoin-project/lotus/chain/actors/adt"
+	"github.com/filecoin-project/lotus/chain/actors/builtin"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/multisig"
 	"github.com/filecoin-project/lotus/chain/stmgr"
 	types "github.com/filecoin-project/lotus/chain/types"
 	cid "github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
 	cbg "github.com/whyrusleeping/cbor-gen"
 	"golang.org/x/xerrors"
 )
@@ -27,6 +37,17 @@ type ServicesAPI interface {
 	// DecodeTypedParamsFromJSON takes in information needed to identify a method and converts JSON
 	// parameters to bytes of their CBOR encoding
 	DecodeTypedParamsFromJSON(ctx context.Context, to address.Address, method abi.MethodNum, paramstr string) ([]byte, error)
+	// MsigThreshold returns the number of approvals a multisig transaction
+	// needs before it is executed
+	MsigThreshold(ctx context.Context, msig address.Address) (uint64, error)
+	// PendingMessages returns the messages from the address waiting in the
+	// mempool, ordered by nonce. Undef is the default wallet address.
+	PendingMessages(ctx context.Context, from address.Address) ([]PendingMessage, error)
+	// AccountBalance returns the balance of the account, split into its
+	// available and locked parts. Undef is the default wallet address.
+	AccountBalance(ctx context.Context, addr address.Address) (AccountBalance, error)
+	// FullNodeAPI returns the full node API the services use
+	FullNodeAPI() v0api.FullNode
 
 	// Close ends the session of services and disconnects from RPC, using Services after Close is called
 	// most likely will result in an error
@@ -39,6 +60,10 @@ type ServicesImpl struct {
 	closer jsonrpc.ClientCloser
 }
 
+func (s *ServicesImpl) FullNodeAPI() v0api.FullNode {
+	return s.api
+}
+
 func (s *ServicesImpl) Close() error {
 	if s.closer == nil {
 		return xerrors.Errorf("Services already closed")
@@ -72,6 +97,119 @@ func (s *ServicesImpl) DecodeTypedParamsFromJSON(ctx context.Context, to address
 	return buf.Bytes(), nil
 }
 
+func (s *ServicesImpl) MsigThreshold(ctx context.Context, msig address.Address) (uint64, error) {
+	act, err := s.api.StateGetActor(ctx, msig, types.EmptyTSK)
+	if err != nil {
+		return 0, xerrors.Errorf("getting multisig actor: %w", err)
+	}
+
+	store := adt.WrapStore(ctx, cbor.NewCborStore(blockstore.NewAPIBlockstore(s.api)))
+	mstate, err := multisig.Load(store, act)
+	if err != nil {
+		return 0, xerrors.Errorf("loading multisig state: %w", err)
+	}
+
+	return mstate.Threshold()
+}
+
+// AccountBalance is the balance of an account. Multisig and miner actors
+// lock part of theirs, as vesting funds, pledge and deposits; the rest is
+// available to send.
+type AccountBalance struct {
+	Address   address.Address
+	Actor     string
+	Total     abi.TokenAmount
+	Available abi.TokenAmount
+	Locked    abi.TokenAmount
+}
+
+func (s *ServicesImpl) AccountBalance(ctx context.Context, addr address.Address) (AccountBalance, error) {
+	if addr == address.Undef {
+		defaddr, err := s.api.WalletDefaultAddress(ctx)
+		if err != nil {
+			return AccountBalance{}, err
+		}
+		addr = defaddr
+	}
+
+	act, err := s.api.StateGetActor(ctx, addr, types.EmptyTSK)
+	if err != nil {
+		return AccountBalance{}, xerrors.Errorf("getting actor: %w", err)
+	}
+
+	avail := act.Balance
+	switch {
+	case builtin.IsMultisigActor(act.Code):
+		avail, err = s.api.MsigGetAvailableBalance(ctx, addr, types.EmptyTSK)
+		if err != nil {
+			return AccountBalance{}, xerrors.Errorf("getting multisig available balance: %w", err)
+		}
+	case builtin.IsStorageMinerActor(act.Code):
+		avail, err = s.api.StateMinerAvailableBalance(ctx, addr, types.EmptyTSK)
+		if err != nil {
+			return AccountBalance{}, xerrors.Errorf("getting miner available balance: %w", err)
+		}
+	}
+
+	return AccountBalance{
+		Address:   addr,
+		Actor:     builtin.ActorNameByCode(act.Code),
+		Total:     act.Balance,
+		Available: avail,
+		Locked:    types.BigSub(act.Balance, avail),
+	}, nil
+}
+
+// PendingMessage is a message waiting in the mempool
+type PendingMessage struct {
+	Message *types.SignedMessage
+	// Age is the time since the node first published the message, zero when
+	// the node didn't publish it recently
+	Age time.Duration
+}
+
+func (s *ServicesImpl) PendingMessages(ctx context.Context, from address.Address) ([]PendingMessage, error) {
+	if from == address.Undef {
+		defaddr, err := s.api.WalletDefaultAddress(ctx)
+		if err != nil {
+			return nil, err
+		}
+		from = defaddr
+	}
+
+	key := from
+	if from.Protocol() == address.ID {
+		k, err := s.api.StateAccountKey(ctx, from, types.EmptyTSK)
+		if err != nil {
+			return nil, xerrors.Errorf("resolving sender key address: %w", err)
+		}
+		key = k
+	}
+
+	pending, err := s.api.MpoolPending(ctx, types.EmptyTSK)
+	if err != nil {
+		return nil, xerrors.Errorf("getting pending messages: %w", err)
+	}
+
+	var out []PendingMessage
+	for _, sm := range pending {
+		if sm.Message.From != from && sm.Message.From != key {
+			continue
+		}
+
+		pm := PendingMessage{Message: sm}
+		if prop, err := s.api.MpoolPropagation(ctx, sm.Cid()); err == nil && prop.Tracked {
+			pm.Age = time.Since(prop.FirstPublished)
+		}
+		out = append(out, pm)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Message.Message.Nonce < out[j].Message.Message.Nonce
+	})
+	return out, nil
+}
+
 type SendParams struct {
 	To   address.Address
 	From address.Address
@@ -86,6 +224,18 @@ type SendParams struct {
 	Params []byte
 
 	Force bool
+
+	// ViaMsig makes the send a proposal of the multisig, with From as the
+	// proposing signer
+	ViaMsig address.Address
+
+	// Reference is the ticket / approval reference recorded with the send,
+	// required by the node for sends above its configured threshold
+	Reference string
+
+	// IdempotencyKey identifies the send to the node, which returns the
+	// message already pushed with the key instead of sending again
+	IdempotencyKey string
 }
 
 // This is specialised Send for Send command
@@ -94,6 +244,32 @@ type SendParams struct {
 
 var ErrSendBalanceTooLow = errors.New("balance too low")
 
+// ErrSendUnknownMethod is the result of checkMethod when the method doesn't
+// exist on the actor the message is sent to, so the message would fail on
+// chain
+var ErrSendUnknownMethod = errors.New("unknown method")
+
+// checkMethod checks that the method exists on the actor receiving the
+// message. Actors which don't exist yet or have an unknown code are skipped.
+func checkMethod(ctx context.Context, fapi v0api.FullNode, to address.Address, method abi.MethodNum) error {
+	act, err := fapi.StateGetActor(ctx, to, types.EmptyTSK)
+	if err != nil {
+		if api.ErrorCode(err) == errcode.ActorNotFound {
+			return nil
+		}
+		return xerrors.Errorf("getting target actor: %w", err)
+	}
+
+	methods, known := stmgr.MethodsMap[act.Code]
+	if !known {
+		return nil
+	}
+	if _, ok := methods[method]; !ok {
+		return xerrors.Errorf("%w: method %d doesn't exist on %s actor %s", ErrSendUnknownMethod, method, builtin.ActorNameByCode(act.Code), to)
+	}
+	return nil
+}
+
 func (s *ServicesImpl) Send(ctx context.Context, params SendParams) (cid.Cid, error) {
 	if params.From == address.Undef {
 		defaddr, err := s.api.WalletDefaultAddress(ctx)
@@ -112,6 +288,29 @@ func (s *ServicesImpl) Send(ctx context.Context, params SendParams) (cid.Cid, er
 		Params: params.Params,
 	}
 
+	if params.ViaMsig != address.Undef {
+		if !params.Force {
+			avail, err := s.api.MsigGetAvailableBalance(ctx, params.ViaMsig, types.EmptyTSK)
+			if err != nil {
+				return cid.Undef, xerrors.Errorf("getting multisig available balance: %w", err)
+			}
+			if avail.LessThan(params.Val) {
+				return cid.Undef, xerrors.Errorf("multisig available balance %s less than sent value %s: %w", types.FIL(avail), types.FIL(params.Val), ErrSendBalanceTooLow)
+			}
+		}
+
+		nver, err := s.api.StateNetworkVersion(ctx, types.EmptyTSK)
+		if err != nil {
+			return cid.Undef, err
+		}
+
+		msg, err = multisig.Message(actors.VersionForNetwork(nver), params.From).
+			Propose(params.ViaMsig, params.To, params.Val, params.Method, params.Params)
+		if err != nil {
+			return cid.Undef, xerrors.Errorf("creating multisig proposal: %w", err)
+		}
+	}
+
 	if params.GasPremium != nil {
 		msg.GasPremium = *params.GasPremium
 	} else {
@@ -157,7 +356,12 @@ func (s *ServicesImpl) Send(ctx context.Context, params SendParams) (cid.Cid, er
 		return sm.Cid(), nil
 	}
 
-	sm, err := s.api.MpoolPushMessage(ctx, msg, nil)
+	var spec *api.MessageSendSpec
+	if params.Reference != "" || params.IdempotencyKey != "" {
+		spec = &api.MessageSendSpec{Reference: params.Reference, IdempotencyKey: params.IdempotencyKey}
+	}
+
+	sm, err := s.api.MpoolPushMessage(ctx, msg, spec)
 	if err != nil {
 		return cid.Undef, err
 	}
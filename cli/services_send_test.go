@@ -4,16 +4,21 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/go-state-types/crypto"
+	"github.com/filecoin-project/go-state-types/network"
 	"github.com/filecoin-project/lotus/api"
 	mocks "github.com/filecoin-project/lotus/api/v0api/v0mocks"
 	types "github.com/filecoin-project/lotus/chain/types"
+	builtin5 "github.com/filecoin-project/specs-actors/v5/actors/builtin"
 	gomock "github.com/golang/mock/gomock"
 	cid "github.com/ipfs/go-cid"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
 )
 
 type markerKeyType struct{}
@@ -263,4 +268,114 @@ func TestSendService(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, *msgCid, c)
 	})
+	t.Run("via-msig", func(t *testing.T) {
+		params := params
+		params.ViaMsig = addrGen()
+		mm := MessageMatcher{From: a1, To: params.ViaMsig, Val: big.Zero()}
+
+		srvcs, mockApi := setupMockSrvcs(t)
+		defer srvcs.Close() //nolint:errcheck
+		msgCid, sign := makeMessageSigner()
+		gomock.InOrder(
+			mockApi.EXPECT().MsigGetAvailableBalance(ctxM, params.ViaMsig, types.EmptyTSK).Return(types.NewInt(balance), nil),
+			mockApi.EXPECT().StateNetworkVersion(ctxM, types.EmptyTSK).Return(network.Version13, nil),
+			mockApi.EXPECT().WalletBalance(ctxM, a1).Return(types.NewInt(1), nil),
+			mockApi.EXPECT().MpoolPushMessage(ctxM, mm, nil).DoAndReturn(sign),
+		)
+
+		c, err := srvcs.Send(ctx, params)
+		assert.NoError(t, err)
+		assert.Equal(t, *msgCid, c)
+	})
+
+	t.Run("via-msig-balance-too-low", func(t *testing.T) {
+		params := params
+		params.ViaMsig = addrGen()
+
+		srvcs, mockApi := setupMockSrvcs(t)
+		defer srvcs.Close() //nolint:errcheck
+		gomock.InOrder(
+			mockApi.EXPECT().MsigGetAvailableBalance(ctxM, params.ViaMsig, types.EmptyTSK).Return(types.NewInt(balance-200), nil),
+		)
+
+		_, err := srvcs.Send(ctx, params)
+		assert.ErrorIs(t, err, ErrSendBalanceTooLow)
+	})
+
+	t.Run("unknown-method", func(t *testing.T) {
+		params := params
+		params.Method = 99
+
+		srvcs, mockApi := setupMockSrvcs(t)
+		defer srvcs.Close() //nolint:errcheck
+		msgCid, sign := makeMessageSigner()
+		gomock.InOrder(
+			// the method is only warned about by the send command
+			mockApi.EXPECT().WalletBalance(ctxM, a1).Return(types.NewInt(balance), nil),
+			mockApi.EXPECT().MpoolPushMessage(ctxM, MessageMatcher(params), nil).DoAndReturn(sign),
+		)
+
+		c, err := srvcs.Send(ctx, params)
+		assert.NoError(t, err)
+		assert.Equal(t, *msgCid, c)
+	})
+}
+
+func TestCheckMethod(t *testing.T) {
+	ctx, ctxM := ContextWithMarker(context.Background())
+	to := mustAddr(address.NewIDAddress(1000))
+
+	check := func(t *testing.T, method abi.MethodNum, act *types.Actor, actErr error) error {
+		mockApi := mocks.NewMockFullNode(gomock.NewController(t))
+		mockApi.EXPECT().StateGetActor(ctxM, to, types.EmptyTSK).Return(act, actErr)
+		return checkMethod(ctx, mockApi, to, method)
+	}
+
+	t.Run("unknown-method", func(t *testing.T) {
+		err := check(t, 99, &types.Actor{Code: builtin5.AccountActorCodeID}, nil)
+		assert.ErrorIs(t, err, ErrSendUnknownMethod)
+	})
+	t.Run("known-method", func(t *testing.T) {
+		assert.NoError(t, check(t, builtin5.MethodsAccount.PubkeyAddress, &types.Actor{Code: builtin5.AccountActorCodeID}, nil))
+	})
+	t.Run("method-on-missing-actor", func(t *testing.T) {
+		assert.NoError(t, check(t, 99, nil, xerrors.Errorf("load state tree: actor not found")))
+	})
+}
+
+func TestPendingMessages(t *testing.T) {
+	ctx, ctxM := ContextWithMarker(context.Background())
+
+	id := mustAddr(address.NewIDAddress(1000))
+	key := mustAddr(address.NewSecp256k1Address([]byte("sender")))
+	other := mustAddr(address.NewSecp256k1Address([]byte("other")))
+
+	mk := func(from address.Address, nonce uint64) *types.SignedMessage {
+		return fakeSign(&types.Message{
+			From:       from,
+			To:         other,
+			Nonce:      nonce,
+			Value:      big.Zero(),
+			GasFeeCap:  big.Zero(),
+			GasPremium: big.Zero(),
+		})
+	}
+	m2, m1, mOther := mk(key, 2), mk(key, 1), mk(other, 1)
+
+	srvcs, mockApi := setupMockSrvcs(t)
+	defer srvcs.Close() //nolint:errcheck
+	gomock.InOrder(
+		mockApi.EXPECT().StateAccountKey(ctxM, id, types.EmptyTSK).Return(key, nil),
+		mockApi.EXPECT().MpoolPending(ctxM, types.EmptyTSK).Return([]*types.SignedMessage{m2, mOther, m1}, nil),
+	)
+	mockApi.EXPECT().MpoolPropagation(ctxM, m2.Cid()).Return(&api.MsgPropagation{Tracked: true, FirstPublished: time.Now().Add(-time.Minute)}, nil)
+	mockApi.EXPECT().MpoolPropagation(ctxM, m1.Cid()).Return(&api.MsgPropagation{}, nil)
+
+	pending, err := srvcs.PendingMessages(ctx, id)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 2)
+	assert.Equal(t, m1, pending[0].Message)
+	assert.Zero(t, pending[0].Age)
+	assert.Equal(t, m2, pending[1].Message)
+	assert.True(t, pending[1].Age >= time.Minute)
 }
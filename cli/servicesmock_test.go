@@ -8,6 +8,7 @@ import (
 	context "context"
 	go_address "github.com/filecoin-project/go-address"
 	abi "github.com/filecoin-project/go-state-types/abi"
+	v0api "github.com/filecoin-project/lotus/api/v0api"
 	gomock "github.com/golang/mock/gomock"
 	go_cid "github.com/ipfs/go-cid"
 	reflect "reflect"
@@ -36,6 +37,21 @@ func (m *MockServicesAPI) EXPECT() *MockServicesAPIMockRecorder {
 	return m.recorder
 }
 
+// AccountBalance mocks base method
+func (m *MockServicesAPI) AccountBalance(arg0 context.Context, arg1 go_address.Address) (AccountBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountBalance", arg0, arg1)
+	ret0, _ := ret[0].(AccountBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountBalance indicates an expected call of AccountBalance
+func (mr *MockServicesAPIMockRecorder) AccountBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountBalance", reflect.TypeOf((*MockServicesAPI)(nil).AccountBalance), arg0, arg1)
+}
+
 // Close mocks base method
 func (m *MockServicesAPI) Close() error {
 	m.ctrl.T.Helper()
@@ -65,6 +81,50 @@ func (mr *MockServicesAPIMockRecorder) DecodeTypedParamsFromJSON(arg0, arg1, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecodeTypedParamsFromJSON", reflect.TypeOf((*MockServicesAPI)(nil).DecodeTypedParamsFromJSON), arg0, arg1, arg2, arg3)
 }
 
+// FullNodeAPI mocks base method
+func (m *MockServicesAPI) FullNodeAPI() v0api.FullNode {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FullNodeAPI")
+	ret0, _ := ret[0].(v0api.FullNode)
+	return ret0
+}
+
+// FullNodeAPI indicates an expected call of FullNodeAPI
+func (mr *MockServicesAPIMockRecorder) FullNodeAPI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FullNodeAPI", reflect.TypeOf((*MockServicesAPI)(nil).FullNodeAPI))
+}
+
+// MsigThreshold mocks base method
+func (m *MockServicesAPI) MsigThreshold(arg0 context.Context, arg1 go_address.Address) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MsigThreshold", arg0, arg1)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MsigThreshold indicates an expected call of MsigThreshold
+func (mr *MockServicesAPIMockRecorder) MsigThreshold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MsigThreshold", reflect.TypeOf((*MockServicesAPI)(nil).MsigThreshold), arg0, arg1)
+}
+
+// PendingMessages mocks base method
+func (m *MockServicesAPI) PendingMessages(arg0 context.Context, arg1 go_address.Address) ([]PendingMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PendingMessages", arg0, arg1)
+	ret0, _ := ret[0].([]PendingMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PendingMessages indicates an expected call of PendingMessages
+func (mr *MockServicesAPIMockRecorder) PendingMessages(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingMessages", reflect.TypeOf((*MockServicesAPI)(nil).PendingMessages), arg0, arg1)
+}
+
 // Send mocks base method
 func (m *MockServicesAPI) Send(arg0 context.Context, arg1 SendParams) (go_cid.Cid, error) {
 	m.ctrl.T.Helper()
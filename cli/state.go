@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -13,8 +14,10 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/filecoin-project/lotus/api/errcode"
 	"github.com/filecoin-project/lotus/api/v0api"
 
 	"github.com/fatih/color"
@@ -37,7 +40,6 @@ import (
 	"github.com/filecoin-project/lotus/api"
 	lapi "github.com/filecoin-project/lotus/api"
 	"github.com/filecoin-project/lotus/blockstore"
-	"github.com/filecoin-project/lotus/build"
 	"github.com/filecoin-project/lotus/chain/state"
 	"github.com/filecoin-project/lotus/chain/stmgr"
 	"github.com/filecoin-project/lotus/chain/types"
@@ -51,6 +53,7 @@ var StateCmd = &cli.Command{
 			Name:  "tipset",
 			Usage: "specify tipset to call method on (pass comma separated array of cids)",
 		},
+		nullRoundFlag,
 	},
 	Subcommands: []*cli.Command{
 		StatePowerCmd,
@@ -74,7 +77,9 @@ var StateCmd = &cli.Command{
 		StateMinerInfo,
 		StateMarketCmd,
 		StateExecTraceCmd,
+		StateBalanceDeltaCmd,
 		StateNtwkVersionCmd,
+		StateWatchCmd,
 	},
 }
 
@@ -180,16 +185,30 @@ func ParseTipSetString(ts string) ([]cid.Cid, error) {
 	return cids, nil
 }
 
+// nullRoundFlag sets how commands resolve heights without blocks, as in
+// --tipset @<height>
+var nullRoundFlag = &cli.StringFlag{
+	Name:  "null-round",
+	Usage: "resolve heights without blocks to the previous tipset, the next one, or fail: previous, next or error",
+	Value: string(lapi.NullRoundPrevious),
+}
+
 func LoadTipSet(ctx context.Context, cctx *cli.Context, api v0api.FullNode) (*types.TipSet, error) {
 	tss := cctx.String("tipset")
 	if tss == "" {
 		return nil, nil
 	}
 
-	return ParseTipSetRef(ctx, api, tss)
+	return ParseTipSetRefPolicy(ctx, api, tss, lapi.NullRoundPolicy(cctx.String("null-round")))
 }
 
 func ParseTipSetRef(ctx context.Context, api v0api.FullNode, tss string) (*types.TipSet, error) {
+	return ParseTipSetRefPolicy(ctx, api, tss, lapi.NullRoundPrevious)
+}
+
+// ParseTipSetRefPolicy parses a tipset ref, resolving @<height> refs to null
+// rounds by the policy
+func ParseTipSetRefPolicy(ctx context.Context, api v0api.FullNode, tss string, policy lapi.NullRoundPolicy) (*types.TipSet, error) {
 	if tss[0] == '@' {
 		if tss == "@head" {
 			return api.ChainHead(ctx)
@@ -200,7 +219,7 @@ func ParseTipSetRef(ctx context.Context, api v0api.FullNode, tss string) (*types
 			return nil, xerrors.Errorf("parsing height tipset ref: %w", err)
 		}
 
-		return api.ChainGetTipSetByHeight(ctx, abi.ChainEpoch(h), types.EmptyTSK)
+		return getTipSetByHeight(ctx, api, abi.ChainEpoch(h), policy)
 	}
 
 	cids, err := ParseTipSetString(tss)
@@ -221,6 +240,20 @@ func ParseTipSetRef(ctx context.Context, api v0api.FullNode, tss string) (*types
 	return ts, nil
 }
 
+// getTipSetByHeight returns the tipset at the height, resolving null rounds
+// by the policy. ChainGetTipSetByHeight is kept for the default policy, so
+// that nodes without ChainGetTipSetByHeightPolicy can still be used.
+func getTipSetByHeight(ctx context.Context, api v0api.FullNode, h abi.ChainEpoch, policy lapi.NullRoundPolicy) (*types.TipSet, error) {
+	if policy == "" || policy == lapi.NullRoundPrevious {
+		return api.ChainGetTipSetByHeight(ctx, h, types.EmptyTSK)
+	}
+	ts, err := api.ChainGetTipSetByHeightPolicy(ctx, h, policy, types.EmptyTSK)
+	if err != nil {
+		return nil, WithAPIExitStatus(err)
+	}
+	return ts, nil
+}
+
 var StatePowerCmd = &cli.Command{
 	Name:      "power",
 	Usage:     "Query network or miner power",
@@ -451,19 +484,24 @@ var StateReplayCmd = &cli.Command{
 			return xerrors.Errorf("replay call failed: %w", err)
 		}
 
+		unit, err := feeUnit(cctx)
+		if err != nil {
+			return err
+		}
+
 		fmt.Println("Replay receipt:")
 		fmt.Printf("Exit code: %d\n", res.MsgRct.ExitCode)
 		fmt.Printf("Return: %x\n", res.MsgRct.Return)
 		fmt.Printf("Gas Used: %d\n", res.MsgRct.GasUsed)
 
 		if cctx.Bool("detailed-gas") {
-			fmt.Printf("Base Fee Burn: %d\n", res.GasCost.BaseFeeBurn)
-			fmt.Printf("Overestimaton Burn: %d\n", res.GasCost.OverEstimationBurn)
-			fmt.Printf("Miner Penalty: %d\n", res.GasCost.MinerPenalty)
-			fmt.Printf("Miner Tip: %d\n", res.GasCost.MinerTip)
-			fmt.Printf("Refund: %d\n", res.GasCost.Refund)
+			fmt.Printf("Base Fee Burn: %s\n", unit.Format(res.GasCost.BaseFeeBurn))
+			fmt.Printf("Overestimaton Burn: %s\n", unit.Format(res.GasCost.OverEstimationBurn))
+			fmt.Printf("Miner Penalty: %s\n", unit.Format(res.GasCost.MinerPenalty))
+			fmt.Printf("Miner Tip: %s\n", unit.Format(res.GasCost.MinerTip))
+			fmt.Printf("Refund: %s\n", unit.Format(res.GasCost.Refund))
 		}
-		fmt.Printf("Total Message Cost: %d\n", res.GasCost.TotalCost)
+		fmt.Printf("Total Message Cost: %s\n", unit.Format(res.GasCost.TotalCost))
 
 		if res.MsgRct.ExitCode != 0 {
 			fmt.Printf("Error message: %q\n", res.Error)
@@ -676,6 +714,19 @@ var StateLookupIDCmd = &cli.Command{
 			Aliases: []string{"r"},
 			Usage:   "Perform reverse lookup",
 		},
+		&cli.BoolFlag{
+			Name:  "batch",
+			Usage: "resolve addresses read one per line from stdin (or --file) to all their known forms, printing ndjson",
+		},
+		&cli.StringFlag{
+			Name:  "file",
+			Usage: "with --batch, read addresses from this file instead of stdin",
+		},
+		&cli.IntFlag{
+			Name:  "parallel",
+			Usage: "with --batch, number of addresses resolved concurrently",
+			Value: 8,
+		},
 	},
 	Action: func(cctx *cli.Context) error {
 		api, closer, err := GetFullNodeAPI(cctx)
@@ -686,6 +737,15 @@ var StateLookupIDCmd = &cli.Command{
 
 		ctx := ReqContext(cctx)
 
+		ts, err := LoadTipSet(ctx, cctx, api)
+		if err != nil {
+			return err
+		}
+
+		if cctx.Bool("batch") {
+			return stateLookupBatch(ctx, cctx, api, ts.Key())
+		}
+
 		if !cctx.Args().Present() {
 			return fmt.Errorf("must pass address of actor to get")
 		}
@@ -695,16 +755,11 @@ var StateLookupIDCmd = &cli.Command{
 			return err
 		}
 
-		ts, err := LoadTipSet(ctx, cctx, api)
-		if err != nil {
-			return err
-		}
-
 		var a address.Address
 		if !cctx.Bool("reverse") {
 			a, err = api.StateLookupID(ctx, addr, ts.Key())
 		} else {
-			a, err = api.StateAccountKey(ctx, addr, ts.Key())
+			a, err = lookupRobustAddress(ctx, api, addr, ts.Key())
 		}
 
 		if err != nil {
@@ -717,6 +772,118 @@ var StateLookupIDCmd = &cli.Command{
 	},
 }
 
+// lookupRobustAddress returns the key address of accounts, and the robust
+// address other actors were created with
+func lookupRobustAddress(ctx context.Context, api v0api.FullNode, addr address.Address, tsk types.TipSetKey) (address.Address, error) {
+	act, err := api.StateGetActor(ctx, addr, tsk)
+	if err != nil {
+		return address.Undef, err
+	}
+	if builtin.IsAccountActor(act.Code) {
+		return api.StateAccountKey(ctx, addr, tsk)
+	}
+	return api.StateLookupRobustAddress(ctx, addr, tsk)
+}
+
+// AddressLookup is a line of 'state lookup --batch' output
+type AddressLookup struct {
+	Input string
+
+	// Found is false for invalid addresses and addresses without an actor in
+	// the state, with the reason in Error
+	Found     bool
+	ID        string `json:",omitempty"`
+	Robust    string `json:",omitempty"`
+	ActorType string `json:",omitempty"`
+	Error     string `json:",omitempty"`
+}
+
+func lookupAddressForms(ctx context.Context, api v0api.FullNode, input string, tsk types.TipSetKey) AddressLookup {
+	out := AddressLookup{Input: input}
+
+	addr, err := address.NewFromString(input)
+	if err != nil {
+		out.Error = err.Error()
+		return out
+	}
+
+	act, err := api.StateGetActor(ctx, addr, tsk)
+	if err != nil {
+		out.Error = err.Error()
+		return out
+	}
+	out.Found = true
+	out.ActorType = builtin.ActorNameByCode(act.Code)
+
+	if addr.Protocol() == address.ID {
+		out.ID = addr.String()
+		// actors created by ID (e.g. builtin actors) have no robust address
+		if robust, err := lookupRobustAddress(ctx, api, addr, tsk); err == nil {
+			out.Robust = robust.String()
+		}
+		return out
+	}
+
+	out.Robust = addr.String()
+	id, err := api.StateLookupID(ctx, addr, tsk)
+	if err != nil {
+		out.Error = err.Error()
+		return out
+	}
+	out.ID = id.String()
+	return out
+}
+
+func stateLookupBatch(ctx context.Context, cctx *cli.Context, api v0api.FullNode, tsk types.TipSetKey) error {
+	in := NewAppFmt(cctx.App).Stdin
+	if cctx.IsSet("file") {
+		f, err := os.Open(cctx.String("file"))
+		if err != nil {
+			return err
+		}
+		defer f.Close() //nolint:errcheck
+		in = f
+	}
+
+	var inputs []string
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			inputs = append(inputs, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return xerrors.Errorf("reading addresses: %w", err)
+	}
+
+	parallel := cctx.Int("parallel")
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	results := make([]AddressLookup, len(inputs))
+	throttle := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, input := range inputs {
+		wg.Add(1)
+		throttle <- struct{}{}
+		go func(i int, input string) {
+			defer wg.Done()
+			defer func() { <-throttle }()
+			results[i] = lookupAddressForms(ctx, api, input, tsk)
+		}(i, input)
+	}
+	wg.Wait()
+
+	enc := json.NewEncoder(cctx.App.Writer)
+	for _, r := range results {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 var StateSectorSizeCmd = &cli.Command{
 	Name:      "sector-size",
 	Usage:     "Look up miners sector size",
@@ -1374,8 +1541,18 @@ var StateWaitMsgCmd = &cli.Command{
 	Flags: []cli.Flag{
 		&cli.StringFlag{
 			Name:  "timeout",
+			Usage: "give up if the message isn't executed after this long",
 			Value: "10m",
 		},
+		&cli.BoolFlag{
+			Name:  "follow-replacements",
+			Usage: "if the message was replaced in the mempool (e.g. by a fee bump), wait for the replacing message instead",
+		},
+		&cli.Uint64Flag{
+			Name:  "confidence",
+			Usage: "number of block confirmations to wait for (default: by value at risk, see --confidence-policy)",
+		},
+		confidencePolicyFlag,
 	},
 	Action: func(cctx *cli.Context) error {
 		if !cctx.Args().Present() {
@@ -1395,9 +1572,39 @@ var StateWaitMsgCmd = &cli.Command{
 			return err
 		}
 
-		mw, err := api.StateWaitMsg(ctx, msg, build.MessageConfidence)
+		if cctx.Bool("follow-replacements") {
+			seen := map[cid.Cid]struct{}{msg: {}}
+			for {
+				r, err := api.MpoolGetReplacement(ctx, msg)
+				if err != nil {
+					return xerrors.Errorf("getting replacement of %s: %w", msg, err)
+				}
+				if r == nil {
+					break
+				}
+				if _, ok := seen[r.Replacement]; ok {
+					return xerrors.Errorf("replacement loop at %s", r.Replacement)
+				}
+				seen[r.Replacement] = struct{}{}
+
+				fmt.Printf("message %s was replaced by %s\n", msg, r.Replacement)
+				msg = r.Replacement
+			}
+		}
+
+		timeout, err := time.ParseDuration(cctx.String("timeout"))
 		if err != nil {
-			return err
+			return xerrors.Errorf("parsing timeout: %w", err)
+		}
+		wctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		mw, err := waitMsgConfidence(wctx, cctx, api, msg)
+		if err != nil {
+			if ctx.Err() == nil && wctx.Err() == context.DeadlineExceeded {
+				return xerrors.Errorf("message %s not executed after %s: %w", msg, timeout, ErrWaitTimeout)
+			}
+			return WithAPIExitStatus(err)
 		}
 
 		m, err := api.ChainGetMessage(ctx, msg)
@@ -1405,7 +1612,20 @@ var StateWaitMsgCmd = &cli.Command{
 			return err
 		}
 
-		return printMsg(ctx, api, msg, mw, m)
+		if err := printMsg(ctx, api, msg, mw, m); err != nil {
+			return err
+		}
+
+		if mw.Receipt.ExitCode == exitcode.SysErrOutOfGas {
+			return &ExitStatusErr{
+				Err:    xerrors.Errorf("message ran out of gas (limit %d)", m.GasLimit),
+				Status: apiErrorExitStatus[errcode.OutOfGas],
+			}
+		}
+		if mw.Receipt.ExitCode != exitcode.Ok {
+			return xerrors.Errorf("exit %d: %w", mw.Receipt.ExitCode, ErrExecutionFailed)
+		}
+		return nil
 	},
 }
 
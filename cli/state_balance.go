@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+
+	lapi "github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/lib/tablewriter"
+)
+
+var StateBalanceDeltaCmd = &cli.Command{
+	Name:      "balance-delta",
+	Usage:     "Print a ledger of the changes of the balance of an actor over an epoch range",
+	ArgsUsage: "<address>",
+	Description: `Replays the execution of the epochs in the range to attribute the change of the
+   balance of the actor to the transfers, gas, rewards, burns and market
+   deposits and withdrawals which made it. The range ends at the tipset
+   selected with --tipset, the head by default.
+
+   Replaying is expensive, so long ranges take a while; they are fetched in
+   pages of a few epochs.`,
+	Flags: []cli.Flag{
+		&cli.Int64Flag{
+			Name:     "from",
+			Usage:    "first epoch of the range",
+			Required: true,
+		},
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "print the deltas of the pages as JSON",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.Args().Len() != 1 {
+			return ShowHelp(cctx, fmt.Errorf("must pass the address of the actor"))
+		}
+
+		addr, err := address.NewFromString(cctx.Args().First())
+		if err != nil {
+			return err
+		}
+
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+		ctx := ReqContext(cctx)
+
+		to, err := LoadTipSet(ctx, cctx, api)
+		if err != nil {
+			return err
+		}
+		if to == nil {
+			if to, err = api.ChainHead(ctx); err != nil {
+				return err
+			}
+		}
+		fromHeight := abi.ChainEpoch(cctx.Int64("from"))
+		if fromHeight > to.Height() {
+			return fmt.Errorf("--from %d is after the end of the range at %d", fromHeight, to.Height())
+		}
+		from, err := api.ChainGetTipSetByHeight(ctx, fromHeight, to.Key())
+		if err != nil {
+			return err
+		}
+
+		var deltas []*lapi.BalanceDelta
+		fromKey := from.Key()
+		for {
+			d, err := api.StateAccountBalanceDelta(ctx, addr, fromKey, to.Key())
+			if err != nil {
+				return err
+			}
+			deltas = append(deltas, d)
+			if d.Complete {
+				break
+			}
+			fromKey = d.To
+			if !cctx.Bool("json") {
+				_, _ = fmt.Fprintf(cctx.App.ErrWriter, "replayed epochs %d to %d of %d\n", from.Height(), d.ToHeight, to.Height())
+			}
+		}
+
+		if cctx.Bool("json") {
+			out, err := json.MarshalIndent(deltas, "", "  ")
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(cctx.App.Writer, string(out))
+			return nil
+		}
+
+		first, last := deltas[0], deltas[len(deltas)-1]
+		_, _ = fmt.Fprintf(cctx.App.Writer, "Balance at %d: %s\n\n", first.FromHeight, types.FIL(first.FromBalance))
+
+		tw := tablewriter.New(
+			tablewriter.Col("Height"),
+			tablewriter.Col("Kind"),
+			tablewriter.Col("Amount"),
+			tablewriter.Col("Balance"),
+			tablewriter.Col("Counterparty"),
+			tablewriter.Col("Method"),
+			tablewriter.Col("Message"))
+
+		balance := first.FromBalance
+		unattributed := big.Zero()
+		for _, d := range deltas {
+			for _, it := range d.Items {
+				balance = big.Add(balance, it.Amount)
+				tw.Write(map[string]interface{}{
+					"Height":       it.Height,
+					"Kind":         it.Kind,
+					"Amount":       types.FIL(it.Amount).Short(),
+					"Balance":      types.FIL(balance).Short(),
+					"Counterparty": it.Counterparty,
+					"Method":       it.Method,
+					"Message":      it.Message,
+				})
+			}
+			unattributed = big.Add(unattributed, d.Unattributed)
+		}
+		if err := tw.Flush(cctx.App.Writer); err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(cctx.App.Writer, "\nBalance at %d: %s\n", last.ToHeight, types.FIL(last.ToBalance))
+		_, _ = fmt.Fprintf(cctx.App.Writer, "Change: %s\n", types.FIL(big.Sub(last.ToBalance, first.FromBalance)))
+		if !unattributed.IsZero() {
+			_, _ = fmt.Fprintf(cctx.App.Writer, "Unattributed: %s\n", types.FIL(unattributed))
+		}
+
+		// miners only have locked funds on the pages ending after they were created
+		var locked *lapi.BalanceDeltaLocked
+		for _, d := range deltas {
+			if d.Locked == nil {
+				continue
+			}
+			if locked == nil {
+				locked = &lapi.BalanceDeltaLocked{
+					VestingFunds:      big.Zero(),
+					InitialPledge:     big.Zero(),
+					PreCommitDeposits: big.Zero(),
+				}
+			}
+			locked.VestingFunds = big.Add(locked.VestingFunds, d.Locked.VestingFunds)
+			locked.InitialPledge = big.Add(locked.InitialPledge, d.Locked.InitialPledge)
+			locked.PreCommitDeposits = big.Add(locked.PreCommitDeposits, d.Locked.PreCommitDeposits)
+		}
+		if locked != nil {
+			_, _ = fmt.Fprintf(cctx.App.Writer, "\nLocked funds changes (part of the balance):\n")
+			_, _ = fmt.Fprintf(cctx.App.Writer, "  Vesting:             %s\n", types.FIL(locked.VestingFunds))
+			_, _ = fmt.Fprintf(cctx.App.Writer, "  Initial pledge:      %s\n", types.FIL(locked.InitialPledge))
+			_, _ = fmt.Fprintf(cctx.App.Writer, "  Pre-commit deposits: %s\n", types.FIL(locked.PreCommitDeposits))
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	builtin5 "github.com/filecoin-project/specs-actors/v5/actors/builtin"
+
+	"github.com/filecoin-project/lotus/api/v0api/v0mocks"
+	"github.com/filecoin-project/lotus/chain/actors/builtin"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+func TestLookupAddressForms(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	account := mustAddr(address.NewIDAddress(1000))
+	key := mustAddr(address.NewSecp256k1Address([]byte("key")))
+	msig := mustAddr(address.NewIDAddress(1001))
+	msigRobust := mustAddr(address.NewActorAddress([]byte("msig")))
+	unknown := mustAddr(address.NewSecp256k1Address([]byte("unknown")))
+
+	m := v0mocks.NewMockFullNode(ctrl)
+	m.EXPECT().StateGetActor(gomock.Any(), gomock.Any(), types.EmptyTSK).DoAndReturn(func(_ context.Context, a address.Address, _ types.TipSetKey) (*types.Actor, error) {
+		switch a {
+		case account, key:
+			return &types.Actor{Code: builtin5.AccountActorCodeID}, nil
+		case msig:
+			return &types.Actor{Code: builtin5.MultisigActorCodeID}, nil
+		}
+		return nil, xerrors.New("actor not found")
+	}).AnyTimes()
+	m.EXPECT().StateAccountKey(gomock.Any(), account, types.EmptyTSK).Return(key, nil)
+	m.EXPECT().StateLookupRobustAddress(gomock.Any(), msig, types.EmptyTSK).Return(msigRobust, nil)
+	m.EXPECT().StateLookupID(gomock.Any(), key, types.EmptyTSK).Return(account, nil)
+
+	require.Equal(t, AddressLookup{
+		Input:     account.String(),
+		Found:     true,
+		ID:        account.String(),
+		Robust:    key.String(),
+		ActorType: builtin.ActorNameByCode(builtin5.AccountActorCodeID),
+	}, lookupAddressForms(ctx, m, account.String(), types.EmptyTSK))
+
+	require.Equal(t, AddressLookup{
+		Input:     msig.String(),
+		Found:     true,
+		ID:        msig.String(),
+		Robust:    msigRobust.String(),
+		ActorType: builtin.ActorNameByCode(builtin5.MultisigActorCodeID),
+	}, lookupAddressForms(ctx, m, msig.String(), types.EmptyTSK))
+
+	require.Equal(t, account.String(), lookupAddressForms(ctx, m, key.String(), types.EmptyTSK).ID)
+
+	// addresses without an actor are reported, not dropped
+	res := lookupAddressForms(ctx, m, unknown.String(), types.EmptyTSK)
+	require.False(t, res.Found)
+	require.Equal(t, "actor not found", res.Error)
+
+	res = lookupAddressForms(ctx, m, "not an address", types.EmptyTSK)
+	require.False(t, res.Found)
+	require.NotEmpty(t, res.Error)
+}
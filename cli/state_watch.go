@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/filecoin-project/go-address"
+
+	"github.com/filecoin-project/lotus/lib/tablewriter"
+)
+
+var StateWatchCmd = &cli.Command{
+	Name:  "watch",
+	Usage: "Manage actors whose state is kept in memory at the chain head",
+	Description: `Watched actors have their state (and miner info for miners) resolved on every
+   head change, so that StateGetActor and StateMinerInfo calls at head don't
+   need to walk the state tree. Actors added here are forgotten on restart,
+   use the WatchedActors.Addresses config to watch actors permanently.`,
+	Subcommands: []*cli.Command{
+		stateWatchListCmd,
+		stateWatchAddCmd,
+		stateWatchRemoveCmd,
+	},
+}
+
+var stateWatchListCmd = &cli.Command{
+	Name:  "list",
+	Usage: "List watched actors",
+	Action: func(cctx *cli.Context) error {
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+		ctx := ReqContext(cctx)
+
+		watched, err := api.StateWatchedActors(ctx)
+		if err != nil {
+			return err
+		}
+
+		tw := tablewriter.New(
+			tablewriter.Col("Address"),
+			tablewriter.Col("ID"),
+			tablewriter.Col("Miner"),
+			tablewriter.Col("Height"),
+			tablewriter.Col("Hits"),
+			tablewriter.NewLineCol("Error"))
+
+		for _, w := range watched {
+			row := map[string]interface{}{
+				"Address": w.Address,
+				"Miner":   w.Miner,
+				"Hits":    w.Hits,
+			}
+			if w.ID != address.Undef {
+				row["ID"] = w.ID
+				row["Height"] = w.Height
+			}
+			if w.Error != "" {
+				row["Error"] = w.Error
+			}
+			tw.Write(row)
+		}
+
+		return tw.Flush(os.Stdout)
+	},
+}
+
+var stateWatchAddCmd = &cli.Command{
+	Name:      "add",
+	Usage:     "Add actors to the watched actors",
+	ArgsUsage: "[address ...]",
+	Action: func(cctx *cli.Context) error {
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+		ctx := ReqContext(cctx)
+
+		if !cctx.Args().Present() {
+			return fmt.Errorf("must pass address of actor to watch")
+		}
+
+		for _, s := range cctx.Args().Slice() {
+			addr, err := address.NewFromString(s)
+			if err != nil {
+				return err
+			}
+			if err := api.StateWatchActor(ctx, addr); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var stateWatchRemoveCmd = &cli.Command{
+	Name:      "remove",
+	Usage:     "Remove actors from the watched actors",
+	ArgsUsage: "[address ...]",
+	Action: func(cctx *cli.Context) error {
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+		ctx := ReqContext(cctx)
+
+		if !cctx.Args().Present() {
+			return fmt.Errorf("must pass address of actor to stop watching")
+		}
+
+		for _, s := range cctx.Args().Slice() {
+			addr, err := address.NewFromString(s)
+			if err != nil {
+				return err
+			}
+			if err := api.StateUnwatchActor(ctx, addr); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/filecoin-project/go-state-types/abi"
 	cid "github.com/ipfs/go-cid"
 	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
 
 	"github.com/filecoin-project/lotus/api"
 	"github.com/filecoin-project/lotus/api/v0api"
@@ -90,6 +91,10 @@ var SyncWaitCmd = &cli.Command{
 			Name:  "watch",
 			Usage: "don't exit after node is synced",
 		},
+		&cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "give up if the node isn't synced after this long, e.g. 30m",
+		},
 	},
 	Action: func(cctx *cli.Context) error {
 		napi, closer, err := GetFullNodeAPI(cctx)
@@ -99,7 +104,19 @@ var SyncWaitCmd = &cli.Command{
 		defer closer()
 		ctx := ReqContext(cctx)
 
-		return SyncWait(ctx, napi, cctx.Bool("watch"))
+		timeout := cctx.Duration("timeout")
+		if timeout <= 0 {
+			return SyncWait(ctx, napi, cctx.Bool("watch"))
+		}
+
+		tctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		err = SyncWait(tctx, napi, cctx.Bool("watch"))
+		if ctx.Err() == nil && tctx.Err() == context.DeadlineExceeded {
+			return xerrors.Errorf("node not synced after %s: %w", timeout, ErrWaitTimeout)
+		}
+		return err
 	},
 }
 
@@ -36,6 +36,12 @@ var walletCmd = &cli.Command{
 		walletVerify,
 		walletDelete,
 		walletMarket,
+		walletKeystore,
+		walletMemo,
+		walletApproveSend,
+		walletRefunds,
+		walletWatchOnly,
+		walletPolicy,
 	},
 }
 
@@ -100,6 +106,20 @@ var walletList = &cli.Command{
 			return err
 		}
 
+		// watch-only addresses are listed after the wallet ones, not with
+		// --addr-only which lists the addresses the node can sign with
+		watched, err := api.WalletListWatch(ctx)
+		if err != nil {
+			return err
+		}
+		labels := map[address.Address]string{}
+		if !cctx.Bool("addr-only") {
+			for _, w := range watched {
+				addrs = append(addrs, w.Address)
+				labels[w.Address] = w.Label
+			}
+		}
+
 		// Assume an error means no default key is set
 		def, _ := api.WalletDefaultAddress(ctx)
 
@@ -111,6 +131,8 @@ var walletList = &cli.Command{
 			tablewriter.Col("Market(Locked)"),
 			tablewriter.Col("Nonce"),
 			tablewriter.Col("Default"),
+			tablewriter.Col("Watch-only"),
+			tablewriter.Col("Label"),
 			tablewriter.NewLineCol("Error"))
 
 		for _, addr := range addrs {
@@ -140,6 +162,10 @@ var walletList = &cli.Command{
 				if addr == def {
 					row["Default"] = "X"
 				}
+				if label, ok := labels[addr]; ok {
+					row["Watch-only"] = "X"
+					row["Label"] = label
+				}
 
 				if cctx.Bool("id") {
 					id, err := api.StateLookupID(ctx, addr, types.EmptyTSK)
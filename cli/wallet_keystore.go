@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/crypto/ssh/terminal"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/chain/wallet"
+	"github.com/filecoin-project/lotus/node/repo"
+)
+
+var walletKeystore = &cli.Command{
+	Name:  "keystore",
+	Usage: "Manage keystore encryption",
+	Subcommands: []*cli.Command{
+		walletKeystoreEncrypt,
+		walletKeystoreConfirm,
+		walletKeystoreRestore,
+		walletKeystoreUnlock,
+		walletKeystoreLock,
+	},
+}
+
+var passphraseFileFlag = &cli.StringFlag{
+	Name:  "passphrase-file",
+	Usage: "read the keystore passphrase from a file instead of the " + wallet.EnvKeystorePassphrase + " env var or a prompt",
+}
+
+func readPassphrase(cctx *cli.Context, confirm bool) ([]byte, error) {
+	if file := cctx.String(passphraseFileFlag.Name); file != "" {
+		passphrase, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, xerrors.Errorf("reading passphrase: %w", err)
+		}
+		return bytes.TrimRight(passphrase, "\r\n"), nil
+	}
+
+	if passphrase, ok := os.LookupEnv(wallet.EnvKeystorePassphrase); ok {
+		return []byte(passphrase), nil
+	}
+
+	fmt.Fprint(cctx.App.ErrWriter, "Passphrase: ")
+	passphrase, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(cctx.App.ErrWriter)
+	if err != nil {
+		return nil, xerrors.Errorf("reading passphrase: %w", err)
+	}
+
+	if confirm {
+		fmt.Fprint(cctx.App.ErrWriter, "Repeat passphrase: ")
+		again, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(cctx.App.ErrWriter)
+		if err != nil {
+			return nil, xerrors.Errorf("reading passphrase: %w", err)
+		}
+		if !bytes.Equal(passphrase, again) {
+			return nil, xerrors.Errorf("passphrases don't match")
+		}
+	}
+
+	return passphrase, nil
+}
+
+func withKeyStoreRewriter(cctx *cli.Context, cb func(kr repo.KeyStoreRewriter) error) error {
+	r, err := repo.NewFS(cctx.String("repo"))
+	if err != nil {
+		return err
+	}
+
+	lr, err := r.Lock(repo.FullNode)
+	if err != nil {
+		if xerrors.Is(err, repo.ErrRepoAlreadyLocked) {
+			return xerrors.Errorf("%w; the daemon must be stopped to modify the keystore", err)
+		}
+		return err
+	}
+	defer lr.Close() //nolint:errcheck
+
+	kr, ok := lr.(repo.KeyStoreRewriter)
+	if !ok {
+		return xerrors.Errorf("repo doesn't support replacing the keystore")
+	}
+
+	return cb(kr)
+}
+
+var walletKeystoreEncrypt = &cli.Command{
+	Name:  "encrypt",
+	Usage: "Encrypt the wallet keys in the keystore with a passphrase",
+	Description: `Encrypts all wallet keys in the node keystore with a key derived from the
+   passphrase (argon2id). The daemon must be stopped.
+
+   The original keystore is kept as a backup until 'lotus wallet keystore confirm'
+   is called, and can be put back with 'lotus wallet keystore restore'.
+
+   Once encrypted, the daemon starts with signing disabled until the keystore is
+   unlocked with 'lotus wallet keystore unlock', or the passphrase is configured
+   with Wallet.KeystorePassphraseFile or the ` + wallet.EnvKeystorePassphrase + ` env var.`,
+	Flags: []cli.Flag{
+		passphraseFileFlag,
+	},
+	Action: func(cctx *cli.Context) error {
+		return withKeyStoreRewriter(cctx, func(kr repo.KeyStoreRewriter) error {
+			passphrase, err := readPassphrase(cctx, true)
+			if err != nil {
+				return err
+			}
+			if len(passphrase) == 0 {
+				return xerrors.Errorf("empty passphrase")
+			}
+
+			err = kr.RewriteKeyStore(func(from, to types.KeyStore) error {
+				return wallet.EncryptKeyStore(from, to, passphrase)
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cctx.App.Writer, "Keystore encrypted, the unencrypted keys were kept as a backup.")
+			fmt.Fprintln(cctx.App.Writer, "Once the node works with the encrypted keystore, remove the backup with 'lotus wallet keystore confirm'.")
+			return nil
+		})
+	},
+}
+
+var walletKeystoreConfirm = &cli.Command{
+	Name:  "confirm",
+	Usage: "Remove the keystore backup left by 'encrypt'",
+	Action: func(cctx *cli.Context) error {
+		return withKeyStoreRewriter(cctx, func(kr repo.KeyStoreRewriter) error {
+			has, err := kr.KeyStoreBackup()
+			if err != nil {
+				return err
+			}
+			if !has {
+				return xerrors.Errorf("no keystore backup")
+			}
+
+			if err := kr.DropKeyStoreBackup(); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cctx.App.Writer, "Keystore backup removed")
+			return nil
+		})
+	},
+}
+
+var walletKeystoreRestore = &cli.Command{
+	Name:  "restore",
+	Usage: "Replace the keystore with the backup left by 'encrypt'",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "force",
+			Usage: "restore even if keys were added to the keystore since it was backed up, losing them",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		return withKeyStoreRewriter(cctx, func(kr repo.KeyStoreRewriter) error {
+			missing, err := kr.MissingFromKeyStoreBackup()
+			if err != nil {
+				return err
+			}
+			var lost []string
+			for _, name := range missing {
+				// the encryption params only exist in the encrypted keystore
+				if name != wallet.KEncryption {
+					lost = append(lost, name)
+				}
+			}
+			if len(lost) > 0 && !cctx.Bool("force") {
+				return xerrors.Errorf("the keystore has keys the backup doesn't, which restoring it would lose: %s; export them first, or pass --force", strings.Join(lost, ", "))
+			}
+
+			if err := kr.RestoreKeyStoreBackup(); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cctx.App.Writer, "Keystore backup restored")
+			return nil
+		})
+	},
+}
+
+var walletKeystoreUnlock = &cli.Command{
+	Name:  "unlock",
+	Usage: "Unlock the encrypted keystore of the running node, enabling signing",
+	Flags: []cli.Flag{
+		passphraseFileFlag,
+	},
+	Action: func(cctx *cli.Context) error {
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+		ctx := ReqContext(cctx)
+
+		passphrase, err := readPassphrase(cctx, false)
+		if err != nil {
+			return err
+		}
+
+		return api.WalletUnlock(ctx, string(passphrase))
+	},
+}
+
+var walletKeystoreLock = &cli.Command{
+	Name:  "lock",
+	Usage: "Lock the encrypted keystore of the running node, disabling signing",
+	Action: func(cctx *cli.Context) error {
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+		ctx := ReqContext(cctx)
+
+		return api.WalletLock(ctx)
+	},
+}
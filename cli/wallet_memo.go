@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"text/tabwriter"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/ipfs/go-cid"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	lapi "github.com/filecoin-project/lotus/api"
+)
+
+const memoLocalNote = "Memos are kept by this node only, they are not part of the message and never go on chain."
+
+var walletMemo = &cli.Command{
+	Name:  "memo",
+	Usage: "Manage the local memos of sent messages",
+	Description: memoLocalNote + `
+   A message replacing another in the mempool inherits its memo. Use export
+   and import to move the memos to another node.`,
+	Subcommands: []*cli.Command{
+		walletMemoGet,
+		walletMemoSet,
+		walletMemoList,
+		walletMemoExport,
+		walletMemoImport,
+	},
+}
+
+var walletMemoGet = &cli.Command{
+	Name:      "get",
+	Usage:     "Print the memo of a message",
+	ArgsUsage: "<message cid>",
+	Action: func(cctx *cli.Context) error {
+		if cctx.Args().Len() != 1 {
+			return ShowHelp(cctx, fmt.Errorf("expected the message CID"))
+		}
+		mc, err := cid.Parse(cctx.Args().First())
+		if err != nil {
+			return ShowHelp(cctx, fmt.Errorf("parsing message CID: %w", err))
+		}
+
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		m, err := api.MpoolGetMemo(ReqContext(cctx), mc)
+		if err != nil {
+			return err
+		}
+		if m == nil {
+			return xerrors.Errorf("no memo for message %s", mc)
+		}
+		fmt.Fprintln(cctx.App.Writer, m.Memo)
+		return nil
+	},
+}
+
+var walletMemoSet = &cli.Command{
+	Name:      "set",
+	Usage:     "Set the memo of a message, an empty memo deletes it",
+	ArgsUsage: "<message cid> <memo>",
+	Action: func(cctx *cli.Context) error {
+		if cctx.Args().Len() != 2 {
+			return ShowHelp(cctx, fmt.Errorf("expected the message CID and the memo"))
+		}
+		mc, err := cid.Parse(cctx.Args().First())
+		if err != nil {
+			return ShowHelp(cctx, fmt.Errorf("parsing message CID: %w", err))
+		}
+
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		if err := api.MpoolSetMemo(ReqContext(cctx), lapi.MessageMemo{Message: mc, Memo: cctx.Args().Get(1)}); err != nil {
+			return err
+		}
+		fmt.Fprintln(cctx.App.ErrWriter, memoLocalNote)
+		return nil
+	},
+}
+
+var walletMemoList = &cli.Command{
+	Name:      "list",
+	Usage:     "List the memos of the messages from or to an address, or all of them",
+	ArgsUsage: "[address]",
+	Action: func(cctx *cli.Context) error {
+		var addr address.Address
+		if cctx.Args().Present() {
+			a, err := address.NewFromString(cctx.Args().First())
+			if err != nil {
+				return ShowHelp(cctx, fmt.Errorf("parsing address: %w", err))
+			}
+			addr = a
+		}
+
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		memos, err := api.MpoolListMemos(ReqContext(cctx), addr)
+		if err != nil {
+			return err
+		}
+
+		tw := tabwriter.NewWriter(cctx.App.Writer, 2, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "Time\tMessage\tFrom\tTo\tMemo")
+		for _, m := range memos {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", m.Timestamp.Format("2006-01-02 15:04:05"), m.Message, m.From, m.To, m.Memo)
+		}
+		return tw.Flush()
+	},
+}
+
+var walletMemoExport = &cli.Command{
+	Name:      "export",
+	Usage:     "Export all the memos as JSON",
+	ArgsUsage: "[file (default stdout)]",
+	Action: func(cctx *cli.Context) error {
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		memos, err := api.MpoolListMemos(ReqContext(cctx), address.Undef)
+		if err != nil {
+			return err
+		}
+
+		b, err := json.MarshalIndent(memos, "", "  ")
+		if err != nil {
+			return err
+		}
+		if !cctx.Args().Present() {
+			fmt.Fprintln(cctx.App.Writer, string(b))
+			return nil
+		}
+		return ioutil.WriteFile(cctx.Args().First(), append(b, '\n'), 0600)
+	},
+}
+
+var walletMemoImport = &cli.Command{
+	Name:      "import",
+	Usage:     "Import memos exported as JSON, replacing the memos of the same messages",
+	ArgsUsage: "[file (default stdin)]",
+	Action: func(cctx *cli.Context) error {
+		var b []byte
+		var err error
+		if cctx.Args().Present() {
+			b, err = ioutil.ReadFile(cctx.Args().First())
+		} else {
+			b, err = ioutil.ReadAll(os.Stdin)
+		}
+		if err != nil {
+			return xerrors.Errorf("reading memos: %w", err)
+		}
+
+		var memos []lapi.MessageMemo
+		if err := json.Unmarshal(b, &memos); err != nil {
+			return xerrors.Errorf("decoding memos: %w", err)
+		}
+
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := ReqContext(cctx)
+		for _, m := range memos {
+			if err := api.MpoolSetMemo(ctx, m); err != nil {
+				return xerrors.Errorf("importing memo of %s: %w", m.Message, err)
+			}
+		}
+		fmt.Fprintf(cctx.App.Writer, "Imported %d memos\n", len(memos))
+		return nil
+	},
+}
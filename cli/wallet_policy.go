@@ -0,0 +1,216 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+
+	lapi "github.com/filecoin-project/lotus/api"
+)
+
+var walletPolicy = &cli.Command{
+	Name:  "policy",
+	Usage: "Manage the usage policies restricting what addresses may sign",
+	Description: `An address with a usage policy may only sign the chain messages and data its
+   policy allows, so a leaked API token or a mistake can't, say, withdraw funds
+   with a PoSt address. Roles allow the usual uses of miner addresses:
+
+   owner   manage the miner: change its owner and worker, withdraw and repay
+   worker  send any message to the miner, power and market actors, sign blocks
+   post    submit WindowPoSt and declare faults and recoveries
+   deals   publish deals and manage the market balance, sign deal proposals
+   sender  send funds to accounts, multisigs and payment channels
+
+   Other uses are allowed with --allow and --category. Addresses without a
+   policy may sign anything.`,
+	Subcommands: []*cli.Command{
+		walletPolicySet,
+		walletPolicyGet,
+		walletPolicyList,
+		walletPolicyRemove,
+	},
+}
+
+var walletPolicySet = &cli.Command{
+	Name:      "set",
+	Usage:     "Set the usage policy of an address, replacing its current one",
+	ArgsUsage: "<address>",
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "role",
+			Usage: "allow the uses of a role: owner, worker, post, deals or sender",
+		},
+		&cli.StringSliceFlag{
+			Name:  "allow",
+			Usage: "allow messages to an actor family, to some methods only as 'storageminer:16,3'; '*' matches any actor",
+		},
+		&cli.StringSliceFlag{
+			Name:  "category",
+			Usage: "allow signing data other than messages: block, dealproposal or unknown",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.Args().Len() != 1 {
+			return ShowHelp(cctx, fmt.Errorf("expected the address to set the policy of"))
+		}
+		addr, err := address.NewFromString(cctx.Args().First())
+		if err != nil {
+			return ShowHelp(cctx, fmt.Errorf("parsing address: %w", err))
+		}
+
+		pol := lapi.WalletUsagePolicy{
+			Address: addr,
+			Roles:   cctx.StringSlice("role"),
+		}
+		for _, a := range cctx.StringSlice("allow") {
+			rule, err := parseMessageRule(a)
+			if err != nil {
+				return ShowHelp(cctx, err)
+			}
+			pol.Messages = append(pol.Messages, rule)
+		}
+		for _, c := range cctx.StringSlice("category") {
+			pol.Categories = append(pol.Categories, lapi.MsgType(c))
+		}
+		if len(pol.Roles) == 0 && len(pol.Messages) == 0 && len(pol.Categories) == 0 {
+			return ShowHelp(cctx, fmt.Errorf("a policy allowing nothing would lock the address, pass --role, --allow or --category"))
+		}
+
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		return api.WalletPolicySet(ReqContext(cctx), pol)
+	},
+}
+
+var walletPolicyGet = &cli.Command{
+	Name:      "get",
+	Usage:     "Print the usage policy of an address",
+	ArgsUsage: "<address>",
+	Action: func(cctx *cli.Context) error {
+		if cctx.Args().Len() != 1 {
+			return ShowHelp(cctx, fmt.Errorf("expected the address to get the policy of"))
+		}
+		addr, err := address.NewFromString(cctx.Args().First())
+		if err != nil {
+			return ShowHelp(cctx, fmt.Errorf("parsing address: %w", err))
+		}
+
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		pol, err := api.WalletPolicyGet(ReqContext(cctx), addr)
+		if err != nil {
+			return err
+		}
+		if pol == nil {
+			fmt.Fprintf(cctx.App.Writer, "%s has no usage policy, it may sign anything\n", addr)
+			return nil
+		}
+
+		fmt.Fprintf(cctx.App.Writer, "Address:    %s\n", pol.Address)
+		fmt.Fprintf(cctx.App.Writer, "Roles:      %s\n", strings.Join(pol.Roles, ", "))
+		fmt.Fprintf(cctx.App.Writer, "Messages:   %s\n", formatMessageRules(pol.Messages))
+		fmt.Fprintf(cctx.App.Writer, "Categories: %s\n", formatCategories(pol.Categories))
+		return nil
+	},
+}
+
+var walletPolicyList = &cli.Command{
+	Name:  "list",
+	Usage: "List the addresses with a usage policy",
+	Action: func(cctx *cli.Context) error {
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		pols, err := api.WalletPolicyList(ReqContext(cctx))
+		if err != nil {
+			return err
+		}
+
+		tw := tabwriter.NewWriter(cctx.App.Writer, 2, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "Address\tRoles\tMessages\tCategories")
+		for _, p := range pols {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", p.Address, strings.Join(p.Roles, ","), formatMessageRules(p.Messages), formatCategories(p.Categories))
+		}
+		return tw.Flush()
+	},
+}
+
+var walletPolicyRemove = &cli.Command{
+	Name:      "remove",
+	Usage:     "Remove the usage policy of an address, allowing it to sign anything",
+	ArgsUsage: "<address>",
+	Action: func(cctx *cli.Context) error {
+		if cctx.Args().Len() != 1 {
+			return ShowHelp(cctx, fmt.Errorf("expected the address to remove the policy of"))
+		}
+		addr, err := address.NewFromString(cctx.Args().First())
+		if err != nil {
+			return ShowHelp(cctx, fmt.Errorf("parsing address: %w", err))
+		}
+
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		return api.WalletPolicyRemove(ReqContext(cctx), addr)
+	},
+}
+
+// parseMessageRule parses a message rule as 'family' or 'family:m1,m2'
+func parseMessageRule(s string) (lapi.WalletMessageRule, error) {
+	parts := strings.SplitN(s, ":", 2)
+	rule := lapi.WalletMessageRule{Actor: parts[0]}
+	if len(parts) == 1 {
+		return rule, nil
+	}
+	for _, m := range strings.Split(parts[1], ",") {
+		n, err := strconv.ParseUint(strings.TrimSpace(m), 10, 64)
+		if err != nil {
+			return lapi.WalletMessageRule{}, fmt.Errorf("parsing method %q of %q: %w", m, s, err)
+		}
+		rule.Methods = append(rule.Methods, abi.MethodNum(n))
+	}
+	return rule, nil
+}
+
+func formatMessageRules(rules []lapi.WalletMessageRule) string {
+	out := make([]string, len(rules))
+	for i, r := range rules {
+		out[i] = r.Actor
+		if len(r.Methods) > 0 {
+			ms := make([]string, len(r.Methods))
+			for j, m := range r.Methods {
+				ms[j] = strconv.FormatUint(uint64(m), 10)
+			}
+			out[i] += ":" + strings.Join(ms, ",")
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+func formatCategories(cats []lapi.MsgType) string {
+	out := make([]string, len(cats))
+	for i, c := range cats {
+		out[i] = string(c)
+	}
+	return strings.Join(out, ",")
+}
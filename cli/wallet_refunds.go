@@ -0,0 +1,359 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+
+	lapi "github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/api/v0api"
+	"github.com/filecoin-project/lotus/chain/actors/builtin"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+const refundsFile = "refunds.json"
+
+var walletRefunds = &cli.Command{
+	Name:  "refunds",
+	Usage: "Find and refund the overpaid transfers to an address",
+	Description: `Transfers to the address which executed and paid more than the expected
+   amount are refunded the excess, less the fee allowance, to the robust address
+   of their sender. Transfers from an ID address, as exchanges send from, are
+   listed for manual review and never refunded.
+
+   The refunded messages are kept in the local repo, a transfer is refunded
+   once however many times refunds send runs.`,
+	Subcommands: []*cli.Command{
+		walletRefundsScan,
+		walletRefundsSend,
+	},
+}
+
+var walletRefundsFlags = []cli.Flag{
+	&cli.Int64Flag{
+		Name:     "since-epoch",
+		Usage:    "look at the transfers from this epoch on",
+		Required: true,
+	},
+	&cli.StringFlag{
+		Name:     "expected",
+		Usage:    "amount each transfer is expected to pay",
+		Required: true,
+	},
+	&cli.StringFlag{
+		Name:  "fee-allowance",
+		Usage: "amount kept from each refund to pay for its fees",
+		Value: "0.001",
+	},
+}
+
+var walletRefundsScan = &cli.Command{
+	Name:      "scan",
+	Usage:     "List the transfers to an address deviating from the expected amount",
+	ArgsUsage: "<address>",
+	Flags:     walletRefundsFlags,
+	Action: func(cctx *cli.Context) error {
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		_, refunds, err := scanRefunds(ReqContext(cctx), cctx, api)
+		if err != nil {
+			return err
+		}
+		if len(refunds) == 0 {
+			fmt.Fprintln(cctx.App.Writer, "No transfers deviating from the expected amount")
+			return nil
+		}
+		return printRefunds(cctx, refunds)
+	},
+}
+
+var walletRefundsSend = &cli.Command{
+	Name:      "send",
+	Usage:     "Refund the overpaid transfers to an address",
+	ArgsUsage: "<address>",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "from",
+			Usage: "address to send the refunds from, the scanned address by default",
+		},
+	}, walletRefundsFlags...),
+	Action: func(cctx *cli.Context) error {
+		srv, err := GetFullNodeServices(cctx)
+		if err != nil {
+			return err
+		}
+		defer srv.Close() //nolint:errcheck
+
+		ctx := ReqContext(cctx)
+		afmt := NewAppFmt(cctx.App)
+
+		path := localRepoFile(cctx, refundsFile)
+		if path == "" {
+			return xerrors.Errorf("no local repo to keep the refunded messages in")
+		}
+
+		addr, refunds, err := scanRefunds(ctx, cctx, srv.FullNodeAPI())
+		if err != nil {
+			return err
+		}
+		from := addr
+		if cctx.IsSet("from") {
+			if from, err = address.NewFromString(cctx.String("from")); err != nil {
+				return ShowHelp(cctx, fmt.Errorf("parsing from address: %w", err))
+			}
+		}
+
+		var todo, review []refundCandidate
+		total := big.Zero()
+		for _, r := range refunds {
+			switch r.Status {
+			case refundPending:
+				todo = append(todo, r)
+				total = big.Add(total, r.Refund)
+			case refundReview:
+				review = append(review, r)
+			}
+		}
+		if len(review) > 0 {
+			afmt.Printf("%d transfers from ID addresses to review manually:\n", len(review))
+			if err := printRefunds(cctx, review); err != nil {
+				return err
+			}
+		}
+		if len(todo) == 0 {
+			afmt.Println("No transfers to refund")
+			return nil
+		}
+
+		afmt.Printf("Refunding %d transfers from %s:\n", len(todo), from)
+		if err := printRefunds(cctx, todo); err != nil {
+			return err
+		}
+		afmt.Printf("Send the %d refunds, %s in total? [y/N] ", len(todo), types.FIL(total))
+		answer, err := readAnswer(bufio.NewReader(afmt.Stdin))
+		if err != nil {
+			return err
+		}
+		if answer != "y" && answer != "yes" {
+			return xerrors.Errorf("refunds: %w", ErrAbortedByUser)
+		}
+
+		for i, r := range todo {
+			c, err := srv.Send(ctx, SendParams{
+				From: from,
+				To:   r.From,
+				Val:  r.Refund,
+				// the node sends a refund once even when it isn't recorded
+				IdempotencyKey: "refund/" + r.Message.String(),
+			})
+			if err != nil {
+				afmt.Printf("Stopped after sending %d of %d refunds\n", i, len(todo))
+				return WithExitStatus(xerrors.Errorf("refunding %s: %w", r.Message, err), ExitPushFailed)
+			}
+			if err := recordRefund(path, r, c); err != nil {
+				return xerrors.Errorf("recording refund %s of %s, not sending the others: %w", c, r.Message, err)
+			}
+			afmt.Printf("Refunded %s to %s: %s\n", types.FIL(r.Refund), r.From, c)
+		}
+		return nil
+	},
+}
+
+type refundStatus string
+
+const (
+	refundPending    refundStatus = "to refund"
+	refundReview     refundStatus = "manual review, ID address sender"
+	refundUnderpaid  refundStatus = "underpaid"
+	refundBelowFee   refundStatus = "excess below fee allowance"
+	refundDone       refundStatus = "refunded"
+	refundFailedExec refundStatus = "failed"
+)
+
+// refundCandidate is a transfer deviating from the expected amount
+type refundCandidate struct {
+	Message cid.Cid
+	From    address.Address
+	Value   abi.TokenAmount
+	Excess  abi.TokenAmount
+	Refund  abi.TokenAmount
+	Status  refundStatus
+	// Refunded is the refund message, once sent
+	Refunded cid.Cid
+}
+
+// refundRecord is a refund sent, kept in the repo by the transfer it refunds
+type refundRecord struct {
+	Refund cid.Cid
+	To     address.Address
+	Value  abi.TokenAmount
+	Time   time.Time
+}
+
+// classifyRefund returns how a transfer is refunded, the excess over the
+// expected amount less the fee allowance, going to the sender
+func classifyRefund(mc cid.Cid, msg *types.Message, expected, allowance abi.TokenAmount) refundCandidate {
+	r := refundCandidate{
+		Message: mc,
+		From:    msg.From,
+		Value:   msg.Value,
+		Excess:  big.Sub(msg.Value, expected),
+		Refund:  big.Zero(),
+	}
+	switch {
+	case r.Excess.LessThan(big.Zero()):
+		r.Status = refundUnderpaid
+	case msg.From.Protocol() == address.ID:
+		r.Status = refundReview
+	case r.Excess.LessThanEqual(allowance):
+		r.Status = refundBelowFee
+	default:
+		r.Refund = big.Sub(r.Excess, allowance)
+		r.Status = refundPending
+	}
+	return r
+}
+
+// scanRefunds returns the scanned address and its transfers deviating from
+// the expected amount, with the ones already refunded marked
+func scanRefunds(ctx context.Context, cctx *cli.Context, api v0api.FullNode) (address.Address, []refundCandidate, error) {
+	if cctx.Args().Len() != 1 {
+		return address.Undef, nil, ShowHelp(cctx, fmt.Errorf("expected the address receiving the transfers"))
+	}
+	addr, err := address.NewFromString(cctx.Args().First())
+	if err != nil {
+		return address.Undef, nil, ShowHelp(cctx, fmt.Errorf("parsing address: %w", err))
+	}
+	expected, err := types.ParseFIL(cctx.String("expected"))
+	if err != nil {
+		return address.Undef, nil, ShowHelp(cctx, fmt.Errorf("parsing expected amount: %w", err))
+	}
+	allowance, err := types.ParseFIL(cctx.String("fee-allowance"))
+	if err != nil {
+		return address.Undef, nil, ShowHelp(cctx, fmt.Errorf("parsing fee allowance: %w", err))
+	}
+
+	done, err := loadRefunds(localRepoFile(cctx, refundsFile))
+	if err != nil {
+		return address.Undef, nil, err
+	}
+
+	msgs, err := api.StateListMessages(ctx, &lapi.MessageMatch{To: addr}, types.EmptyTSK, abi.ChainEpoch(cctx.Int64("since-epoch")))
+	if err != nil {
+		return address.Undef, nil, xerrors.Errorf("listing messages to %s: %w", addr, err)
+	}
+
+	var refunds []refundCandidate
+	for _, mc := range msgs {
+		msg, err := api.ChainGetMessage(ctx, mc)
+		if err != nil {
+			return address.Undef, nil, xerrors.Errorf("getting message %s: %w", mc, err)
+		}
+		if msg.Method != builtin.MethodSend || msg.Value.IsZero() || msg.Value.Equals(abi.TokenAmount(expected)) {
+			continue
+		}
+
+		r := classifyRefund(mc, msg, abi.TokenAmount(expected), abi.TokenAmount(allowance))
+
+		// only the transfers which executed paid anything
+		lookup, err := api.StateSearchMsg(ctx, mc)
+		if err != nil {
+			return address.Undef, nil, xerrors.Errorf("searching receipt of %s: %w", mc, err)
+		}
+		if lookup == nil {
+			continue
+		}
+		if lookup.Receipt.ExitCode != 0 {
+			r.Status, r.Refund = refundFailedExec, big.Zero()
+		}
+
+		if rec, ok := done[mc]; ok {
+			r.Status, r.Refund, r.Refunded = refundDone, rec.Value, rec.Refund
+		}
+		refunds = append(refunds, r)
+	}
+	return addr, refunds, nil
+}
+
+func printRefunds(cctx *cli.Context, refunds []refundCandidate) error {
+	tw := tabwriter.NewWriter(cctx.App.Writer, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "Message\tFrom\tValue\tDeviation\tRefund\tStatus")
+	for _, r := range refunds {
+		status := string(r.Status)
+		if r.Refunded.Defined() {
+			status += " in " + r.Refunded.String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", r.Message, r.From, types.FIL(r.Value), types.FIL(r.Excess), types.FIL(r.Refund), status)
+	}
+	return tw.Flush()
+}
+
+// loadRefunds returns the refunds kept in the repo, by the message refunded
+func loadRefunds(path string) (map[cid.Cid]refundRecord, error) {
+	done := map[cid.Cid]refundRecord{}
+	if path == "" {
+		return done, nil
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("reading refunds: %w", err)
+	}
+
+	var recs map[string]refundRecord
+	if err := json.Unmarshal(b, &recs); err != nil {
+		return nil, xerrors.Errorf("decoding refunds: %w", err)
+	}
+	for k, rec := range recs {
+		mc, err := cid.Parse(k)
+		if err != nil {
+			return nil, xerrors.Errorf("decoding refunds: %w", err)
+		}
+		done[mc] = rec
+	}
+	return done, nil
+}
+
+// recordRefund keeps the refund of the transfer in the repo, written after
+// each refund so none is sent twice when sending stops half way
+func recordRefund(path string, r refundCandidate, refund cid.Cid) error {
+	done, err := loadRefunds(path)
+	if err != nil {
+		return err
+	}
+	done[r.Message] = refundRecord{
+		Refund: refund,
+		To:     r.From,
+		Value:  r.Refund,
+		Time:   time.Now(),
+	}
+
+	recs := make(map[string]refundRecord, len(done))
+	for mc, rec := range done {
+		recs[mc.String()] = rec
+	}
+	b, err := json.MarshalIndent(recs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0600)
+}
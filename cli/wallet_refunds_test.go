@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+func TestClassifyRefund(t *testing.T) {
+	fil := func(s string) abi.TokenAmount {
+		return abi.TokenAmount(types.MustParseFIL(s))
+	}
+	expected, allowance := fil("10"), fil("0.1")
+
+	robust, err := address.NewSecp256k1Address([]byte("sender"))
+	require.NoError(t, err)
+	id := mustAddr(address.NewIDAddress(1234))
+
+	for _, tc := range []struct {
+		name   string
+		from   address.Address
+		value  abi.TokenAmount
+		status refundStatus
+		refund abi.TokenAmount
+	}{
+		{"overpaid", robust, fil("12"), refundPending, fil("1.9")},
+		{"underpaid", robust, fil("9"), refundUnderpaid, fil("0")},
+		{"below-fee", robust, fil("10.05"), refundBelowFee, fil("0")},
+		{"id-sender", id, fil("12"), refundReview, fil("0")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := classifyRefund(arbtCid, &types.Message{From: tc.from, Value: tc.value}, expected, allowance)
+			require.Equal(t, tc.status, r.Status)
+			require.Equal(t, tc.refund.String(), r.Refund.String())
+			require.Equal(t, tc.from, r.From)
+		})
+	}
+}
+
+func TestRecordRefund(t *testing.T) {
+	dir, err := ioutil.TempDir("", "refunds")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) //nolint:errcheck
+	path := filepath.Join(dir, refundsFile)
+
+	done, err := loadRefunds(path)
+	require.NoError(t, err)
+	require.Empty(t, done)
+
+	r := refundCandidate{
+		Message: arbtCid,
+		From:    mustAddr(address.NewIDAddress(1)),
+		Refund:  abi.TokenAmount(types.MustParseFIL("1")),
+	}
+	require.NoError(t, recordRefund(path, r, arbtCid))
+
+	done, err = loadRefunds(path)
+	require.NoError(t, err)
+	require.Contains(t, done, arbtCid)
+	require.Equal(t, r.From, done[arbtCid].To)
+	require.Equal(t, r.Refund.String(), done[arbtCid].Value.String())
+}
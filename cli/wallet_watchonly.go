@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/filecoin-project/go-address"
+)
+
+var walletWatchOnly = &cli.Command{
+	Name:  "watch-only",
+	Usage: "Manage the addresses tracked without their keys",
+	Description: `Watch-only addresses, as of cold storage, are listed by 'lotus wallet list'
+   along with the wallet addresses, but the node has no key for them: signing
+   and sending from them fail. They are kept in the node metadata, not the
+   keystore.`,
+	Subcommands: []*cli.Command{
+		walletWatchOnlyAdd,
+		walletWatchOnlyRemove,
+		walletWatchOnlyList,
+	},
+}
+
+var walletWatchOnlyAdd = &cli.Command{
+	Name:      "add",
+	Usage:     "Add a watch-only address, or change its label",
+	ArgsUsage: "<address>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "label",
+			Usage: "label of the address, as 'treasury'",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.Args().Len() != 1 {
+			return ShowHelp(cctx, fmt.Errorf("expected the address to watch"))
+		}
+		addr, err := address.NewFromString(cctx.Args().First())
+		if err != nil {
+			return ShowHelp(cctx, fmt.Errorf("parsing address: %w", err))
+		}
+
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		return api.WalletAddWatch(ReqContext(cctx), addr, cctx.String("label"))
+	},
+}
+
+var walletWatchOnlyRemove = &cli.Command{
+	Name:      "remove",
+	Usage:     "Remove a watch-only address",
+	ArgsUsage: "<address>",
+	Action: func(cctx *cli.Context) error {
+		if cctx.Args().Len() != 1 {
+			return ShowHelp(cctx, fmt.Errorf("expected the address to remove"))
+		}
+		addr, err := address.NewFromString(cctx.Args().First())
+		if err != nil {
+			return ShowHelp(cctx, fmt.Errorf("parsing address: %w", err))
+		}
+
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		return api.WalletRemoveWatch(ReqContext(cctx), addr)
+	},
+}
+
+var walletWatchOnlyList = &cli.Command{
+	Name:  "list",
+	Usage: "List the watch-only addresses",
+	Action: func(cctx *cli.Context) error {
+		api, closer, err := GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		watched, err := api.WalletListWatch(ReqContext(cctx))
+		if err != nil {
+			return err
+		}
+
+		tw := tabwriter.NewWriter(cctx.App.Writer, 2, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "Address\tLabel")
+		for _, w := range watched {
+			fmt.Fprintf(tw, "%s\t%s\n", w.Address, w.Label)
+		}
+		return tw.Flush()
+	},
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/filecoin-project/go-state-types/dline"
 	"github.com/filecoin-project/go-state-types/network"
 	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/api/errcode"
 	"github.com/filecoin-project/lotus/build"
 	"github.com/filecoin-project/lotus/chain/actors/builtin/miner"
 	"github.com/filecoin-project/lotus/chain/types"
@@ -28,7 +30,7 @@ const (
 )
 
 var (
-	ErrLookbackTooLong = fmt.Errorf("lookbacks of more than %s are disallowed", LookbackCap)
+	ErrLookbackTooLong = api.WrapError(errcode.LookbackExceeded, fmt.Errorf("lookbacks of more than %s are disallowed", LookbackCap))
 )
 
 // gatewayDepsAPI defines the API methods that the GatewayAPI depends on
@@ -419,6 +421,12 @@ func (a *GatewayAPI) WalletBalance(ctx context.Context, k address.Address) (type
 	return a.api.WalletBalance(ctx, k)
 }
 
+// ProjectedCall projects the results of the gateway methods themselves, so
+// their lookback checks apply and only the fields are sent to the client
+func (a *GatewayAPI) ProjectedCall(ctx context.Context, method string, params []json.RawMessage, fields []string) (json.RawMessage, error) {
+	return api.CallProjected(ctx, (*api.Gateway)(nil), a, method, params, fields)
+}
+
 var _ api.Gateway = (*GatewayAPI)(nil)
 var _ full.ChainModuleAPI = (*GatewayAPI)(nil)
 var _ full.GasModuleAPI = (*GatewayAPI)(nil)
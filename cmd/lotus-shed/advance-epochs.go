@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/lotus/build"
+	lcli "github.com/filecoin-project/lotus/cli"
+	"github.com/filecoin-project/lotus/miner"
+)
+
+var advanceEpochsCmd = &cli.Command{
+	Name:      "advance-epochs",
+	Usage:     "Mine the next epochs of a local devnet as fast as possible",
+	ArgsUsage: "<epochs>",
+	Description: `Has the devnet miner mine the next epochs without waiting on the clock, and
+   prints the epoch of the head once there. Each epoch is mined, or null when
+   the miner doesn't win it, so proving deadlines pass the way they would in
+   time; with --nulls the epochs before the last are null rounds, which is
+   faster but skips the proofs due in them.
+
+   The chain can't advance past the clock, the daemon rejects blocks from the
+   future. To have epochs to advance, start the devnet from a genesis dated in
+   the past (the Timestamp of the genesis template), and run the miner with
+   ` + miner.EnvDevnetTimeTravel + `=1 so it keeps the gap to the clock instead of catching up.
+
+   Only 2k and debug builds advance epochs, and never on a public network.`,
+	Flags: []cli.Flag{
+		&cli.DurationFlag{
+			Name:  "by",
+			Usage: "advance by the epochs in this duration, as in 720h, instead of a number of epochs",
+		},
+		&cli.BoolFlag{
+			Name:  "nulls",
+			Usage: "skip the epochs before the last as null rounds",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		var epochs abi.ChainEpoch
+		switch {
+		case cctx.IsSet("by") && !cctx.Args().Present():
+			epochs = abi.ChainEpoch(cctx.Duration("by") / (time.Duration(build.BlockDelaySecs) * time.Second))
+		case !cctx.IsSet("by") && cctx.Args().Len() == 1:
+			n, err := strconv.ParseInt(cctx.Args().First(), 10, 64)
+			if err != nil {
+				return xerrors.Errorf("parsing epochs: %w", err)
+			}
+			epochs = abi.ChainEpoch(n)
+		default:
+			return xerrors.Errorf("expected the number of epochs, or --by")
+		}
+		if epochs < 1 {
+			return xerrors.Errorf("the epochs to advance by must be positive")
+		}
+
+		api, closer, err := lcli.GetStorageMinerAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		head, err := api.MiningAdvanceEpochs(lcli.ReqContext(cctx), epochs, cctx.Bool("nulls"))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Advanced %d epochs, head at epoch %d\n", epochs, head)
+		return nil
+	},
+}
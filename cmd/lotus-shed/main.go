@@ -54,6 +54,9 @@ func main() {
 		cidCmd,
 		blockmsgidCmd,
 		signaturesCmd,
+		migrateStateCmd,
+		snapshotCmd,
+		advanceEpochsCmd,
 	}
 
 	app := &cli.App{
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/specs-actors/v3/actors/migration/nv10"
+
+	"github.com/filecoin-project/lotus/blockstore"
+	"github.com/filecoin-project/lotus/chain/stmgr"
+	"github.com/filecoin-project/lotus/chain/store"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/chain/vm"
+	lcli "github.com/filecoin-project/lotus/cli"
+	"github.com/filecoin-project/lotus/extern/sector-storage/ffiwrapper"
+	"github.com/filecoin-project/lotus/node/repo"
+)
+
+var migrateStateCmd = &cli.Command{
+	Name:  "migrate-state",
+	Usage: "Run a network upgrade state migration against the head state, discarding the result",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "next",
+			Usage: "run the migration of the next network upgrade registered in this binary",
+		},
+		&cli.BoolFlag{
+			Name:  "benchmark",
+			Usage: "report wall times, peak memory and migration cache effectiveness",
+		},
+		&cli.BoolFlag{
+			Name:  "skip-pre-migration",
+			Usage: "don't run the pre-migration before the migration",
+		},
+		&cli.BoolFlag{
+			Name:  "from-api",
+			Usage: "read the state through the API of the running node instead of opening its repo",
+		},
+		&cli.BoolFlag{
+			Name:  "persist-cache",
+			Usage: "save the pre-migration results to the repo, for the node to use at the upgrade epoch",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if !cctx.Bool("next") {
+			return xerrors.Errorf("only the migration of the next upgrade (--next) is supported")
+		}
+		if cctx.Bool("persist-cache") && cctx.Bool("skip-pre-migration") {
+			return xerrors.Errorf("--persist-cache requires running the pre-migration")
+		}
+
+		ctx := lcli.ReqContext(cctx)
+
+		var (
+			base blockstore.Blockstore
+			mds  datastore.Batching
+			head *types.TipSet
+		)
+		if cctx.Bool("from-api") {
+			if cctx.Bool("persist-cache") {
+				return xerrors.Errorf("--persist-cache writes to the repo, stop the node and run without --from-api")
+			}
+
+			api, closer, err := lcli.GetFullNodeAPI(cctx)
+			if err != nil {
+				return err
+			}
+			defer closer()
+
+			// reads only, the migration writes go to the throwaway store
+			base = blockstore.NewAPIBlockstore(api)
+			mds = dssync.MutexWrap(datastore.NewMapDatastore())
+			if head, err = api.ChainHead(ctx); err != nil {
+				return xerrors.Errorf("getting chain head: %w", err)
+			}
+		} else {
+			fsrepo, err := repo.NewFS(cctx.String("repo"))
+			if err != nil {
+				return err
+			}
+
+			lkrepo, err := fsrepo.Lock(repo.FullNode)
+			if err != nil {
+				return xerrors.Errorf("opening repo (use --from-api while the node is running): %w", err)
+			}
+			defer lkrepo.Close() //nolint:errcheck
+
+			if base, err = lkrepo.Blockstore(ctx, repo.UniversalBlockstore); err != nil {
+				return xerrors.Errorf("failed to open blockstore: %w", err)
+			}
+			defer func() {
+				if c, ok := base.(io.Closer); ok {
+					if err := c.Close(); err != nil {
+						log.Warnf("failed to close blockstore: %s", err)
+					}
+				}
+			}()
+
+			if mds, err = lkrepo.Datastore(ctx, "/metadata"); err != nil {
+				return err
+			}
+		}
+
+		// migration writes stay in memory
+		written := blockstore.NewMemorySync()
+		bs := blockstore.NewTieredBstore(base, written)
+
+		cs := store.NewChainStore(bs, bs, mds, vm.Syscalls(ffiwrapper.ProofVerifier), nil)
+		defer cs.Close() //nolint:errcheck
+
+		if head == nil {
+			if err := cs.Load(); err != nil {
+				return xerrors.Errorf("loading chain: %w", err)
+			}
+			head = cs.GetHeaviestTipSet()
+		}
+
+		var next *stmgr.Upgrade
+		for _, u := range stmgr.DefaultUpgradeSchedule() {
+			if u.Height > head.Height() && u.Migration != nil {
+				u := u
+				next = &u
+				break
+			}
+		}
+		if next == nil {
+			return xerrors.Errorf("no migration for an upgrade after epoch %d is registered in this binary", head.Height())
+		}
+
+		sm := stmgr.NewStateManager(cs)
+		cache := &countingMigrationCache{MemMigrationCache: nv10.NewMemMigrationCache()}
+		root := head.ParentState()
+
+		fmt.Printf("Migrating to network version %d (upgrade epoch %d) from the state at epoch %d\n", next.Network, next.Height, head.Height())
+
+		peakMemory := trackPeakMemory(ctx)
+
+		if !cctx.Bool("skip-pre-migration") && len(next.PreMigrations) > 0 {
+			start := time.Now()
+			if err := next.PreMigrations[0].PreMigration(ctx, sm, cache, root, head.Height(), head); err != nil {
+				return xerrors.Errorf("running pre-migration: %w", err)
+			}
+			fmt.Printf("Pre-migration took %s\n", time.Since(start).Truncate(time.Millisecond))
+
+			if cctx.Bool("persist-cache") {
+				if err := persistPreMigration(ctx, written, base, mds, next.Height, cache.MemMigrationCache); err != nil {
+					return err
+				}
+			}
+		}
+
+		cache.resetCounts()
+
+		start := time.Now()
+		newRoot, err := next.Migration(ctx, sm, cache, nil, root, head.Height(), head)
+		if err != nil {
+			return xerrors.Errorf("running migration: %w", err)
+		}
+		took := time.Since(start)
+
+		fmt.Printf("Migration took %s\n", took.Truncate(time.Millisecond))
+		fmt.Printf("New state root: %s\n", newRoot)
+
+		if cctx.Bool("benchmark") {
+			hits, misses := cache.counts()
+			hitRate := 0.0
+			if hits+misses > 0 {
+				hitRate = 100 * float64(hits) / float64(hits+misses)
+			}
+
+			fmt.Printf("Peak heap in use: %s\n", units.BytesSize(float64(peakMemory())))
+			fmt.Printf("Migration cache: %d hits, %d misses (%.1f%% hit rate)\n", hits, misses, hitRate)
+		}
+
+		return nil
+	},
+}
+
+// persistPreMigration copies the state written by the pre-migration to the
+// repo blockstore and saves the cache referencing it
+func persistPreMigration(ctx context.Context, written, base blockstore.Blockstore, mds datastore.Datastore, height abi.ChainEpoch, cache *nv10.MemMigrationCache) error {
+	keys, err := written.AllKeysChan(ctx)
+	if err != nil {
+		return xerrors.Errorf("listing pre-migration blocks: %w", err)
+	}
+
+	var n int
+	for c := range keys {
+		blk, err := written.Get(c)
+		if err != nil {
+			return xerrors.Errorf("reading pre-migration block %s: %w", c, err)
+		}
+		if err := base.Put(blk); err != nil {
+			return xerrors.Errorf("writing pre-migration block %s: %w", c, err)
+		}
+		n++
+	}
+
+	entries, err := stmgr.SaveMigrationCache(mds, height, cache)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Persisted %d pre-migration blocks and %d cache entries\n", n, entries)
+	return nil
+}
+
+// countingMigrationCache counts the lookups answered from the cache
+type countingMigrationCache struct {
+	*nv10.MemMigrationCache
+
+	hits, misses int64
+}
+
+func (c *countingMigrationCache) Read(key string) (bool, cid.Cid, error) {
+	ok, v, err := c.MemMigrationCache.Read(key)
+	c.count(ok)
+	return ok, v, err
+}
+
+func (c *countingMigrationCache) Load(key string, loadFunc func() (cid.Cid, error)) (cid.Cid, error) {
+	ok, v, err := c.MemMigrationCache.Read(key)
+	if err != nil {
+		return cid.Undef, err
+	}
+	c.count(ok)
+	if ok {
+		return v, nil
+	}
+	return c.MemMigrationCache.Load(key, loadFunc)
+}
+
+func (c *countingMigrationCache) count(hit bool) {
+	if hit {
+		atomic.AddInt64(&c.hits, 1)
+	} else {
+		atomic.AddInt64(&c.misses, 1)
+	}
+}
+
+func (c *countingMigrationCache) counts() (hits, misses int64) {
+	return atomic.LoadInt64(&c.hits), atomic.LoadInt64(&c.misses)
+}
+
+func (c *countingMigrationCache) resetCounts() {
+	atomic.StoreInt64(&c.hits, 0)
+	atomic.StoreInt64(&c.misses, 0)
+}
+
+// trackPeakMemory samples the heap in use until the context is done or the
+// returned function is called, which returns the peak
+func trackPeakMemory(ctx context.Context) func() uint64 {
+	var (
+		lk   sync.Mutex
+		peak uint64
+	)
+	sample := func() {
+		var ms runtime.MemStats
+		runtime.ReadMemStats(&ms)
+
+		lk.Lock()
+		defer lk.Unlock()
+		if ms.HeapInuse > peak {
+			peak = ms.HeapInuse
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		tick := time.NewTicker(500 * time.Millisecond)
+		defer tick.Stop()
+		for {
+			select {
+			case <-tick.C:
+				sample()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return func() uint64 {
+		cancel()
+		sample()
+
+		lk.Lock()
+		defer lk.Unlock()
+		return peak
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/fatih/color"
 
@@ -38,15 +39,57 @@ var msgCmd = &cli.Command{
 
 		switch msg := msg.(type) {
 		case *types.SignedMessage:
-			return printSignedMessage(cctx, msg)
+			err = printSignedMessage(cctx, msg)
 		case *types.Message:
-			return printMessage(cctx, msg)
+			err = printMessage(cctx, msg)
 		default:
 			return xerrors.Errorf("this error message can't be printed")
 		}
+		if err != nil {
+			return err
+		}
+
+		return printReplacements(cctx, msg.Cid())
 	},
 }
 
+func printReplacements(cctx *cli.Context, c cid.Cid) error {
+	api, closer, err := lcli.GetFullNodeAPI(cctx)
+	if err != nil {
+		return err
+	}
+
+	defer closer()
+	ctx := lcli.ReqContext(cctx)
+
+	fmt.Println("---")
+	color.Green("Replacements:")
+
+	seen := map[cid.Cid]struct{}{}
+	for {
+		if _, ok := seen[c]; ok {
+			return xerrors.Errorf("replacement loop at %s", c)
+		}
+		seen[c] = struct{}{}
+
+		r, err := api.MpoolGetReplacement(ctx, c)
+		if err != nil {
+			return err
+		}
+		if r == nil {
+			break
+		}
+
+		fmt.Printf("%s replaced by %s at %s\n", r.Original, r.Replacement, r.Timestamp.Format(time.RFC3339))
+		c = r.Replacement
+	}
+
+	if len(seen) == 1 {
+		fmt.Println("none seen by the node")
+	}
+	return nil
+}
+
 func printSignedMessage(cctx *cli.Context, smsg *types.SignedMessage) error {
 	color.Green("Signed:")
 	color.Blue("CID: %s\n", smsg.Cid())
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/ipfs/go-cid"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/chain/types"
+	lcli "github.com/filecoin-project/lotus/cli"
+	cliutil "github.com/filecoin-project/lotus/cli/util"
+	"github.com/filecoin-project/lotus/lib/rpcrecord"
+	"github.com/filecoin-project/lotus/node/repo"
+)
+
+var rpcRecordCmd = &cli.Command{
+	Name:  "record",
+	Usage: "Record the API calls served by the node, to be replayed",
+	Subcommands: []*cli.Command{
+		rpcRecordStartCmd,
+		rpcRecordStopCmd,
+		rpcRecordStatusCmd,
+	},
+}
+
+var rpcRecordStartCmd = &cli.Command{
+	Name:      "start",
+	Usage:     "Start recording the API calls to a file on the node",
+	ArgsUsage: "<path>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "token",
+			Usage: "only record the calls made with this token",
+		},
+		&cli.StringFlag{
+			Name:  "token-fingerprint",
+			Usage: "only record the calls made with the token of this fingerprint, as logged by the node",
+		},
+		&cli.StringSliceFlag{
+			Name:  "method",
+			Usage: "only record the calls to these methods",
+		},
+		&cli.StringFlag{
+			Name:  "max-size",
+			Usage: "stop recording once the file grew by this much",
+			Value: "64MiB",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.Args().Len() != 1 {
+			return lcli.ShowHelp(cctx, fmt.Errorf("expected the path to record to"))
+		}
+		if cctx.IsSet("token") && cctx.IsSet("token-fingerprint") {
+			return xerrors.Errorf("--token and --token-fingerprint are mutually exclusive")
+		}
+
+		path, err := filepath.Abs(cctx.Args().First())
+		if err != nil {
+			return err
+		}
+		maxBytes, err := units.RAMInBytes(cctx.String("max-size"))
+		if err != nil {
+			return xerrors.Errorf("parsing --max-size: %w", err)
+		}
+
+		fp := cctx.String("token-fingerprint")
+		if cctx.IsSet("token") {
+			fp = api.TokenFingerprint(cctx.String("token"))
+		}
+
+		napi, closer, err := lcli.GetFullNodeAPIV1(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		err = napi.RPCRecordStart(lcli.ReqContext(cctx), api.RPCRecordConfig{
+			Path:             path,
+			TokenFingerprint: fp,
+			Methods:          cctx.StringSlice("method"),
+			MaxBytes:         maxBytes,
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("recording API calls to %s on the node\n", path)
+		return nil
+	},
+}
+
+var rpcRecordStopCmd = &cli.Command{
+	Name:  "stop",
+	Usage: "Stop recording the API calls",
+	Action: func(cctx *cli.Context) error {
+		napi, closer, err := lcli.GetFullNodeAPIV1(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		st, err := napi.RPCRecordStop(lcli.ReqContext(cctx))
+		if err != nil {
+			return err
+		}
+		printRecordStatus(st)
+		return nil
+	},
+}
+
+var rpcRecordStatusCmd = &cli.Command{
+	Name:  "status",
+	Usage: "Print the status of the recording",
+	Action: func(cctx *cli.Context) error {
+		napi, closer, err := lcli.GetFullNodeAPIV1(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		st, err := napi.RPCRecordStatus(lcli.ReqContext(cctx))
+		if err != nil {
+			return err
+		}
+		if st.Path == "" {
+			fmt.Println("not recording")
+			return nil
+		}
+		printRecordStatus(st)
+		return nil
+	},
+}
+
+func printRecordStatus(st api.RPCRecordStatus) {
+	if st.Active {
+		fmt.Println("Status:  recording")
+	} else {
+		fmt.Printf("Status:  stopped (%s)\n", st.StopReason)
+	}
+	fmt.Printf("Path:    %s\n", st.Path)
+	if st.TokenFingerprint != "" {
+		fmt.Printf("Token:   %s\n", st.TokenFingerprint)
+	}
+	if len(st.Methods) > 0 {
+		fmt.Printf("Methods: %v\n", st.Methods)
+	}
+	fmt.Printf("Started: %s\n", st.Started.Format(time.RFC3339))
+	fmt.Printf("Calls:   %d\n", st.Calls)
+	fmt.Printf("Size:    %s / %s\n", units.BytesSize(float64(st.Bytes)), units.BytesSize(float64(st.MaxBytes)))
+}
+
+var rpcReplayCmd = &cli.Command{
+	Name:      "replay",
+	Usage:     "Replay recorded API calls against a node, comparing the responses",
+	ArgsUsage: "<recording>",
+	Description: `Replays the calls of a recording made with 'rpc record' in order, against the
+   node of --target, or the default node, and reports the calls which
+   responses diverge from the recorded ones.
+
+   Epochs and tipset keys listed as Relative in the recorded calls are shifted
+   by how far the head of the node is from the head the call was recorded at,
+   unless --no-shift is set. Remove a param from Relative in the recording to
+   replay it as recorded.
+
+   Calls with redacted secrets are skipped, as are the calls to methods
+   requiring more than the read permission unless --allow-writes is set.`,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "target",
+			Usage: "API info of the node to replay against, as in FULLNODE_API_INFO",
+		},
+		&cli.BoolFlag{
+			Name:  "no-shift",
+			Usage: "replay the epochs and tipsets as recorded",
+		},
+		&cli.BoolFlag{
+			Name:  "allow-writes",
+			Usage: "also replay the calls to methods which aren't read only, such as MpoolPush",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.Args().Len() != 1 {
+			return lcli.ShowHelp(cctx, fmt.Errorf("expected the recording to replay"))
+		}
+
+		f, err := os.Open(cctx.Args().First())
+		if err != nil {
+			return err
+		}
+		calls, err := rpcrecord.ReadCalls(f)
+		_ = f.Close() // nolint:errcheck
+		if err != nil {
+			return err
+		}
+
+		var addr string
+		var headers http.Header
+		if cctx.IsSet("target") {
+			ainfo := cliutil.ParseApiInfo(cctx.String("target"))
+			if addr, err = ainfo.DialArgs("v1"); err != nil {
+				return xerrors.Errorf("parsing --target: %w", err)
+			}
+			headers = ainfo.AuthHeader()
+		} else if addr, headers, err = lcli.GetRawAPI(cctx, repo.FullNode, "v1"); err != nil {
+			return err
+		}
+		if addr, err = httpAddr(addr); err != nil {
+			return err
+		}
+
+		ctx := lcli.ReqContext(cctx)
+		call := func(method string, params []json.RawMessage, out interface{}) (json.RawMessage, error) {
+			res, rerr, err := rawCall(ctx, addr, headers, method, params)
+			if err != nil {
+				return nil, err
+			}
+			if rerr != "" {
+				return nil, xerrors.New(rerr)
+			}
+			if out != nil {
+				return res, json.Unmarshal(res, out)
+			}
+			return res, nil
+		}
+
+		var head struct{ Height abi.ChainEpoch }
+		if !cctx.Bool("no-shift") {
+			if _, err := call("ChainHead", nil, &head); err != nil {
+				return xerrors.Errorf("getting the head of the target: %w", err)
+			}
+		}
+		resolve := func(h abi.ChainEpoch) (types.TipSetKey, error) {
+			epoch, _ := json.Marshal(h)
+			empty, _ := json.Marshal(types.EmptyTSK)
+			var ts struct{ Cids []cid.Cid }
+			if _, err := call("ChainGetTipSetByHeight", []json.RawMessage{epoch, empty}, &ts); err != nil {
+				return types.EmptyTSK, err
+			}
+			return types.NewTipSetKey(ts.Cids...), nil
+		}
+
+		var replayed, skipped, diverged int
+		var recordedTime, replayTime time.Duration
+		for i, c := range calls {
+			if c.Redacted || (c.Perm != string(api.PermRead) && !cctx.Bool("allow-writes")) {
+				skipped++
+				continue
+			}
+
+			var delta abi.ChainEpoch
+			if !cctx.Bool("no-shift") && len(c.Relative) > 0 {
+				delta = head.Height - c.Head
+			}
+			params, err := c.ShiftParams(delta, resolve)
+			if err != nil {
+				fmt.Printf("#%d %s: skipped, %s\n", i, c.Method, err)
+				skipped++
+				continue
+			}
+
+			start := time.Now()
+			res, rerr, err := rawCall(ctx, addr, headers, c.Method, params)
+			took := time.Since(start)
+			if err != nil {
+				return xerrors.Errorf("replaying call #%d: %w", i, err)
+			}
+			replayed++
+			recordedTime += c.Duration
+			replayTime += took
+
+			var divergence string
+			switch {
+			case c.Error != "" && rerr == "":
+				divergence = fmt.Sprintf("recorded error %q, replay succeeded", c.Error)
+			case c.Error == "" && rerr != "":
+				divergence = fmt.Sprintf("replay failed: %s", rerr)
+			case c.Error == "" && !rpcrecord.SameResult(c.Result, res):
+				divergence = "results differ"
+			}
+			if divergence == "" {
+				continue
+			}
+			diverged++
+			shifted := ""
+			if delta != 0 {
+				shifted = fmt.Sprintf(" (shifted by %d epochs)", delta)
+			}
+			fmt.Printf("#%d %s%s: %s, took %s, recorded %s\n", i, c.Method, shifted, divergence, took.Round(time.Millisecond), c.Duration.Round(time.Millisecond))
+		}
+
+		fmt.Printf("replayed %d calls, skipped %d, %d diverged; took %s, recorded %s\n", replayed, skipped, diverged, replayTime.Round(time.Millisecond), recordedTime.Round(time.Millisecond))
+		if diverged > 0 {
+			return cli.Exit("", 1)
+		}
+		return nil
+	},
+}
+
+// httpAddr returns the HTTP address of the JSON-RPC endpoint of a websocket
+// API address
+func httpAddr(addr string) (string, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return "", xerrors.Errorf("parsing api URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	}
+	return u.String(), nil
+}
+
+// rawCall calls the method over JSON-RPC, returning its result or the
+// message of the error it returned
+func rawCall(ctx context.Context, addr string, headers http.Header, method string, params []json.RawMessage) (json.RawMessage, string, error) {
+	if params == nil {
+		params = []json.RawMessage{}
+	}
+	jreq, err := json.Marshal(struct {
+		Jsonrpc string            `json:"jsonrpc"`
+		ID      int               `json:"id"`
+		Method  string            `json:"method"`
+		Params  []json.RawMessage `json:"params"`
+	}{
+		Jsonrpc: "2.0",
+		Method:  "Filecoin." + method,
+		Params:  params,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", addr, bytes.NewReader(jreq))
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header = headers
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close() // nolint:errcheck
+
+	rb, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var jresp struct {
+		Result json.RawMessage
+		Error  *struct {
+			Message string
+		}
+	}
+	if err := json.Unmarshal(rb, &jresp); err != nil {
+		return nil, "", xerrors.Errorf("decoding response (status %d): %w", resp.StatusCode, err)
+	}
+	if jresp.Error != nil {
+		return nil, jresp.Error.Message, nil
+	}
+	return jresp.Result, "", nil
+}
@@ -8,7 +8,6 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
-	"net/url"
 	"os"
 	"strings"
 	"text/scanner"
@@ -33,6 +32,10 @@ var rpcCmd = &cli.Command{
 			Value: "v0",
 		},
 	},
+	Subcommands: []*cli.Command{
+		rpcRecordCmd,
+		rpcReplayCmd,
+	},
 	Action: func(cctx *cli.Context) error {
 		rt := repo.FullNode
 		if cctx.Bool("miner") {
@@ -44,20 +47,11 @@ var rpcCmd = &cli.Command{
 			return err
 		}
 
-		u, err := url.Parse(addr)
+		addr, err = httpAddr(addr)
 		if err != nil {
-			return xerrors.Errorf("parsing api URL: %w", err)
-		}
-
-		switch u.Scheme {
-		case "ws":
-			u.Scheme = "http"
-		case "wss":
-			u.Scheme = "https"
+			return err
 		}
 
-		addr = u.String()
-
 		ctx := lcli.ReqContext(cctx)
 		ctx, cancel := context.WithCancel(ctx)
 		defer cancel()
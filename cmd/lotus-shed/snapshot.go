@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+	"go.uber.org/multierr"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/lotus/chain/types"
+	lcli "github.com/filecoin-project/lotus/cli"
+)
+
+var snapshotCmd = &cli.Command{
+	Name:  "snapshot",
+	Usage: "Verify and compare chain snapshots",
+	Subcommands: []*cli.Command{
+		snapshotVerifyCmd,
+		snapshotDiffCmd,
+	},
+}
+
+var snapshotTmpdirFlag = &cli.StringFlag{
+	Name:  "tmpdir",
+	Usage: "directory for the temporary block index, which takes a few percent of the snapshot size",
+	Value: os.TempDir(),
+}
+
+var snapshotVerifyCmd = &cli.Command{
+	Name:      "verify",
+	Usage:     "Check a chain snapshot for integrity and completeness",
+	ArgsUsage: "<file.car>",
+	Description: `Checks that every block in the CAR file matches its CID, that the header
+   chain goes back the expected number of epochs, and that the state of the
+   head is complete. With --recent-stateroots, the state and messages of the
+   tipsets within that many epochs of the head are checked too, matching what
+   'lotus chain export' includes. Messages of older tipsets are checked unless
+   --skip-old-msgs is set.
+
+   The file is streamed through once to index it; the index is kept on disk.
+   Exits with an error listing the findings if any defect is found.`,
+	Flags: []cli.Flag{
+		&cli.Int64Flag{
+			Name:  "epochs",
+			Usage: "number of epochs the header chain must go back, 0 for down to genesis",
+		},
+		&cli.Int64Flag{
+			Name:  "recent-stateroots",
+			Usage: "number of epochs below the head the snapshot has the state of",
+		},
+		&cli.BoolFlag{
+			Name:  "skip-old-msgs",
+			Usage: "the snapshot leaves out the messages older than --recent-stateroots",
+		},
+		snapshotTmpdirFlag,
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.Args().Len() != 1 {
+			return lcli.ShowHelp(cctx, fmt.Errorf("expected the snapshot file"))
+		}
+		recent := abi.ChainEpoch(cctx.Int64("recent-stateroots"))
+		skipOld := cctx.Bool("skip-old-msgs")
+
+		si, err := indexSnapshot(cctx.Args().First(), cctx.String("tmpdir"))
+		if err != nil {
+			return err
+		}
+		defer si.Close() //nolint:errcheck
+
+		head, lowest, tipsets := abi.ChainEpoch(-1), abi.ChainEpoch(-1), 0
+		err = si.walkHeaders(abi.ChainEpoch(cctx.Int64("epochs")), func(ts *types.TipSet) error {
+			h := ts.Height()
+			if head < 0 {
+				head = h
+				if err := si.checkDAG(ts.ParentState(), classState, fmt.Sprintf("state root of the head at height %d", h)); err != nil {
+					return err
+				}
+			}
+			tipsets++
+			lowest = h
+
+			inRecent := h > head-recent || h == 0
+			if inRecent || !skipOld {
+				for _, b := range ts.Blocks() {
+					if err := si.checkDAG(b.Messages, classMessages, fmt.Sprintf("messages of block %s at height %d", b.Cid(), h)); err != nil {
+						return err
+					}
+				}
+			}
+			if inRecent && h != head {
+				if err := si.checkDAG(ts.ParentState(), classState, fmt.Sprintf("state root at height %d", h)); err != nil {
+					return err
+				}
+			}
+			if h == 0 {
+				for _, p := range ts.Parents().Cids() {
+					if err := si.checkDAG(p, classState, "parent of genesis"); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Snapshot: %s\n", si.path)
+		if head >= 0 {
+			fmt.Printf("Head: height %d %s\n", head, si.roots)
+			fmt.Printf("Header chain: %d tipsets, from height %d to %d\n", tipsets, head, lowest)
+		}
+		fmt.Printf("Blocks: %d (%s), %d duplicated\n", si.blocks, types.SizeStr(types.NewInt(si.size)), si.duplicates)
+
+		var reached uint64
+		for _, bc := range blockClasses {
+			fmt.Printf("  %-10s %d\n", bc.name, si.reached[bc.class])
+			reached += si.reached[bc.class]
+		}
+		fmt.Printf("  %-10s %d\n", "not walked", si.indexed-reached)
+
+		return si.checkFindings()
+	},
+}
+
+var snapshotDiffCmd = &cli.Command{
+	Name:      "diff",
+	Usage:     "Compare the blocks and epochs of two chain snapshots",
+	ArgsUsage: "<old.car> <new.car>",
+	Description: `Reports the blocks added and removed between the snapshots, and the epoch
+   ranges each covers. Both files are streamed through once to index them;
+   the indexes are kept on disk. Exits with an error listing the findings if
+   either snapshot has a defect.`,
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "list",
+			Usage: "list the CIDs of the added (+) and removed (-) blocks",
+		},
+		snapshotTmpdirFlag,
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.Args().Len() != 2 {
+			return lcli.ShowHelp(cctx, fmt.Errorf("expected the old and the new snapshot files"))
+		}
+
+		var snaps []*snapshotIndex
+		defer func() {
+			for _, si := range snaps {
+				_ = si.Close()
+			}
+		}()
+		for _, path := range cctx.Args().Slice() {
+			si, err := indexSnapshot(path, cctx.String("tmpdir"))
+			if err != nil {
+				return err
+			}
+			snaps = append(snaps, si)
+
+			r, err := chainRange(si)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s: %d blocks (%s)\n", path, si.indexed, types.SizeStr(types.NewInt(si.size)))
+			if r.head < 0 {
+				fmt.Println("  no header chain")
+				continue
+			}
+			fmt.Printf("  headers from height %d to %d\n", r.head, r.lowest)
+			if r.stateFrom < 0 {
+				fmt.Println("  no state")
+			} else {
+				fmt.Printf("  state from height %d to %d\n", r.head, r.stateFrom)
+			}
+		}
+
+		var added, removed, common, addedSize, removedSize uint64
+		err := diffSnapshots(snaps[0], snaps[1], func(bi *blockIterator, inOld, inNew bool) {
+			switch {
+			case inOld && inNew:
+				common++
+			case inNew:
+				added++
+				addedSize += bi.size()
+				if cctx.Bool("list") {
+					fmt.Printf("+ %s\n", bi.cid())
+				}
+			default:
+				removed++
+				removedSize += bi.size()
+				if cctx.Bool("list") {
+					fmt.Printf("- %s\n", bi.cid())
+				}
+			}
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Added: %d blocks (%s)\n", added, types.SizeStr(types.NewInt(addedSize)))
+		fmt.Printf("Removed: %d blocks (%s)\n", removed, types.SizeStr(types.NewInt(removedSize)))
+		fmt.Printf("Unchanged: %d blocks\n", common)
+
+		var errs error
+		for _, si := range snaps {
+			errs = multierr.Append(errs, si.checkFindings())
+		}
+		return errs
+	},
+}
+
+type snapshotRange struct {
+	head, lowest abi.ChainEpoch
+	// stateFrom is the lowest height the parent state is in the snapshot
+	// for, going down from the head
+	stateFrom abi.ChainEpoch
+}
+
+func chainRange(si *snapshotIndex) (snapshotRange, error) {
+	r := snapshotRange{head: -1, lowest: -1, stateFrom: -1}
+	gap := false
+	err := si.walkHeaders(0, func(ts *types.TipSet) error {
+		if r.head < 0 {
+			r.head = ts.Height()
+		}
+		r.lowest = ts.Height()
+
+		has, err := si.has(ts.ParentState())
+		if err != nil {
+			return err
+		}
+		// only the state right below the head counts, the genesis state is
+		// always there
+		if !has {
+			gap = true
+		} else if !gap {
+			r.stateFrom = ts.Height()
+		}
+		return nil
+	})
+	return r, err
+}
+
+// diffSnapshots merges the block indexes of the snapshots, calling cb with
+// the iterator at each block and the snapshots it is in
+func diffSnapshots(older, newer *snapshotIndex, cb func(bi *blockIterator, inOld, inNew bool)) error {
+	oi, ni := older.iterator(), newer.iterator()
+	oi.next()
+	ni.next()
+	for oi.ok || ni.ok {
+		var cmp int
+		switch {
+		case !oi.ok:
+			cmp = 1
+		case !ni.ok:
+			cmp = -1
+		default:
+			cmp = bytes.Compare(oi.key(), ni.key())
+		}
+
+		switch {
+		case cmp < 0:
+			cb(oi, true, false)
+			oi.next()
+		case cmp > 0:
+			cb(ni, false, true)
+			ni.next()
+		default:
+			cb(oi, true, true)
+			oi.next()
+			ni.next()
+		}
+	}
+	return multierr.Combine(oi.release(), ni.release())
+}
+
+// checkFindings prints the findings, failing if there are any
+func (si *snapshotIndex) checkFindings() error {
+	if si.defects == 0 {
+		fmt.Printf("%s: no defects found\n", si.path)
+		return nil
+	}
+
+	fmt.Printf("%s: %d defects found:\n", si.path, si.defects)
+	for _, f := range si.findings {
+		fmt.Printf("  - %s\n", f)
+	}
+	if more := si.defects - len(si.findings); more > 0 {
+		fmt.Printf("  ... and %d more\n", more)
+	}
+	return xerrors.Errorf("%s has %d defects", si.path, si.defects)
+}
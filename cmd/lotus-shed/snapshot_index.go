@@ -0,0 +1,351 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/ipfs/go-cid"
+	"github.com/ipld/go-car"
+	carutil "github.com/ipld/go-car/util"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	ldbopt "github.com/syndtr/goleveldb/leveldb/opt"
+	ldbutil "github.com/syndtr/goleveldb/leveldb/util"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+// maxFindings is how many findings are listed, the others are only counted
+const maxFindings = 50
+
+const (
+	indexPrefix   = 'i'
+	visitedPrefix = 'v'
+)
+
+type blockClass byte
+
+const (
+	classHeader blockClass = iota + 1
+	classMessages
+	classState
+)
+
+var blockClasses = []struct {
+	class blockClass
+	name  string
+}{
+	{classHeader, "headers"},
+	{classMessages, "messages"},
+	{classState, "state"},
+}
+
+var errNotInSnapshot = xerrors.New("block not in snapshot")
+
+// snapshotIndex indexes the blocks of a snapshot CAR file in a temporary
+// leveldb, so the blocks can be read from the file as the chain is walked
+// without holding the file, or the set of its blocks, in memory
+type snapshotIndex struct {
+	path string
+	f    *os.File
+	dir  string
+	db   *leveldb.DB
+
+	roots      []cid.Cid
+	blocks     uint64
+	size       uint64
+	indexed    uint64
+	duplicates uint64
+	reached    map[blockClass]uint64
+
+	findings []string
+	defects  int
+}
+
+// indexSnapshot streams the CAR file once, checking every block against its
+// CID. Defects are recorded as findings; only an unreadable header is an
+// error.
+func indexSnapshot(path, tmpdir string) (si *snapshotIndex, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, xerrors.Errorf("opening snapshot: %w", err)
+	}
+
+	dir, err := ioutil.TempDir(tmpdir, "snapshot-index")
+	if err != nil {
+		_ = f.Close()
+		return nil, xerrors.Errorf("creating index dir: %w", err)
+	}
+
+	// the index is thrown away, don't bother syncing it
+	db, err := leveldb.OpenFile(dir, &ldbopt.Options{NoSync: true})
+	if err != nil {
+		_ = f.Close()
+		_ = os.RemoveAll(dir)
+		return nil, xerrors.Errorf("opening index: %w", err)
+	}
+
+	si = &snapshotIndex{
+		path:    path,
+		f:       f,
+		dir:     dir,
+		db:      db,
+		reached: map[blockClass]uint64{},
+	}
+	defer func() {
+		if err != nil {
+			_ = si.Close()
+		}
+	}()
+
+	br := bufio.NewReaderSize(f, 1<<20)
+	h, offset, err := car.ReadHeader(br)
+	if err != nil {
+		return nil, xerrors.Errorf("reading CAR header of %s: %w", path, err)
+	}
+	if h.Version != 1 {
+		return nil, xerrors.Errorf("unsupported CAR version %d in %s", h.Version, path)
+	}
+	if len(h.Roots) == 0 {
+		si.addFinding("the CAR header has no roots, there's no head to verify from")
+	}
+	si.roots = h.Roots
+
+	val := make([]byte, 2*binary.MaxVarintLen64)
+	for {
+		c, l, data, err := carutil.ReadNode(br)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			si.addFinding("malformed or truncated section at offset %d: %s", offset, err)
+			break
+		}
+
+		dataOffset := offset + l - uint64(len(data))
+		offset += l
+		si.blocks++
+		si.size += uint64(len(data))
+
+		hashed, err := c.Prefix().Sum(data)
+		if err != nil || !hashed.Equals(c) {
+			si.addFinding("block %s at offset %d doesn't match its CID", c, dataOffset)
+			continue
+		}
+
+		key := indexKey(c)
+		has, err := db.Has(key, nil)
+		if err != nil {
+			return nil, xerrors.Errorf("reading index: %w", err)
+		}
+		if has {
+			si.duplicates++
+			continue
+		}
+
+		n := binary.PutUvarint(val, dataOffset)
+		n += binary.PutUvarint(val[n:], uint64(len(data)))
+		if err := db.Put(key, val[:n], nil); err != nil {
+			return nil, xerrors.Errorf("writing index: %w", err)
+		}
+		si.indexed++
+	}
+
+	return si, nil
+}
+
+func indexKey(c cid.Cid) []byte {
+	return append([]byte{indexPrefix}, c.Bytes()...)
+}
+
+func (si *snapshotIndex) addFinding(format string, args ...interface{}) {
+	si.defects++
+	if len(si.findings) < maxFindings {
+		si.findings = append(si.findings, fmt.Sprintf(format, args...))
+	}
+}
+
+func (si *snapshotIndex) has(c cid.Cid) (bool, error) {
+	return si.db.Has(indexKey(c), nil)
+}
+
+func (si *snapshotIndex) get(c cid.Cid) ([]byte, error) {
+	v, err := si.db.Get(indexKey(c), nil)
+	if err == leveldb.ErrNotFound {
+		return nil, errNotInSnapshot
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("reading index: %w", err)
+	}
+
+	offset, n := binary.Uvarint(v)
+	size, _ := binary.Uvarint(v[n:])
+
+	buf := make([]byte, size)
+	if _, err := si.f.ReadAt(buf, int64(offset)); err != nil {
+		return nil, xerrors.Errorf("reading block %s: %w", c, err)
+	}
+	return buf, nil
+}
+
+// visit marks the block as walked, returning false if it already was
+func (si *snapshotIndex) visit(c cid.Cid) (bool, error) {
+	key := append([]byte{visitedPrefix}, c.Bytes()...)
+	has, err := si.db.Has(key, nil)
+	if err != nil || has {
+		return false, err
+	}
+	return true, si.db.Put(key, nil, nil)
+}
+
+// checkDAG checks that all the blocks reachable from the root are in the
+// snapshot. Like the chain export, only DAG-CBOR blocks are followed.
+func (si *snapshotIndex) checkDAG(root cid.Cid, class blockClass, what string) error {
+	stack := []cid.Cid{root}
+	for len(stack) > 0 {
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if c.Prefix().Codec != cid.DagCBOR {
+			continue
+		}
+		first, err := si.visit(c)
+		if err != nil {
+			return xerrors.Errorf("writing index: %w", err)
+		}
+		if !first {
+			continue
+		}
+
+		data, err := si.get(c)
+		if err == errNotInSnapshot {
+			si.addFinding("%s: block %s is missing", what, c)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		si.reached[class]++
+
+		err = cbg.ScanForLinks(bytes.NewReader(data), func(l cid.Cid) {
+			stack = append(stack, l)
+		})
+		if err != nil {
+			si.addFinding("%s: block %s isn't valid CBOR: %s", what, c, err)
+		}
+	}
+	return nil
+}
+
+// walkHeaders walks the header chain from the roots, one tipset at a time,
+// down to genesis, or to depth epochs below the head when depth is positive.
+// A break in the chain is a finding, and ends the walk.
+func (si *snapshotIndex) walkHeaders(depth abi.ChainEpoch, cb func(ts *types.TipSet) error) error {
+	cur := si.roots
+	head := abi.ChainEpoch(-1)
+	at := "the head tipset"
+	for len(cur) > 0 {
+		blks := make([]*types.BlockHeader, 0, len(cur))
+		for _, c := range cur {
+			data, err := si.get(c)
+			if err == errNotInSnapshot {
+				si.addFinding("header %s of %s is missing", c, at)
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			var b types.BlockHeader
+			if err := b.UnmarshalCBOR(bytes.NewReader(data)); err != nil {
+				si.addFinding("block %s of %s isn't a block header: %s", c, at, err)
+				return nil
+			}
+			if _, err := si.visit(c); err != nil {
+				return xerrors.Errorf("writing index: %w", err)
+			}
+			si.reached[classHeader]++
+			blks = append(blks, &b)
+		}
+
+		ts, err := types.NewTipSet(blks)
+		if err != nil {
+			si.addFinding("the blocks of %s aren't a tipset: %s", at, err)
+			return nil
+		}
+		if head < 0 {
+			head = ts.Height()
+		}
+
+		if err := cb(ts); err != nil {
+			return err
+		}
+
+		if ts.Height() == 0 || (depth > 0 && head-ts.Height() >= depth) {
+			return nil
+		}
+		cur = ts.Parents().Cids()
+		at = fmt.Sprintf("the parent tipset of height %d", ts.Height())
+	}
+	return nil
+}
+
+// iterator iterates over the blocks in the index, in CID byte order
+func (si *snapshotIndex) iterator() *blockIterator {
+	return &blockIterator{it: si.db.NewIterator(ldbutil.BytesPrefix([]byte{indexPrefix}), nil)}
+}
+
+func (si *snapshotIndex) Close() error {
+	ferr := si.f.Close()
+	if err := si.db.Close(); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(si.dir); err != nil {
+		return err
+	}
+	return ferr
+}
+
+type blockIterator struct {
+	it iterator.Iterator
+	ok bool
+}
+
+func (bi *blockIterator) next() bool {
+	bi.ok = bi.it.Next()
+	return bi.ok
+}
+
+func (bi *blockIterator) key() []byte {
+	return bi.it.Key()[1:]
+}
+
+func (bi *blockIterator) cid() cid.Cid {
+	c, err := cid.Cast(bi.key())
+	if err != nil {
+		// the keys are written from CIDs
+		panic(err)
+	}
+	return c
+}
+
+func (bi *blockIterator) size() uint64 {
+	v := bi.it.Value()
+	_, n := binary.Uvarint(v)
+	size, _ := binary.Uvarint(v[n:])
+	return size
+}
+
+func (bi *blockIterator) release() error {
+	bi.it.Release()
+	return bi.it.Error()
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	block "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	cbornode "github.com/ipfs/go-ipld-cbor"
+	"github.com/ipld/go-car"
+	carutil "github.com/ipld/go-car/util"
+	mh "github.com/multiformats/go-multihash"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/chain/types/mock"
+)
+
+type testSnapshot struct {
+	head   *types.TipSet
+	blocks []block.Block
+}
+
+// mkSnapshot makes a chain of the given length, each tipset with its own
+// messages, all sharing one state tree of two blocks
+func mkSnapshot(t *testing.T, length int) *testSnapshot {
+	wrap := func(v interface{}) block.Block {
+		nd, err := cbornode.WrapObject(v, mh.SHA2_256, -1)
+		require.NoError(t, err)
+		return nd
+	}
+
+	leaf := wrap("leaf")
+	state := wrap(map[string]interface{}{"child": leaf.Cid()})
+	s := &testSnapshot{blocks: []block.Block{state, leaf}}
+
+	for i := 0; i < length; i++ {
+		b := mock.MkBlock(s.head, 1, uint64(i))
+		msgs := wrap(map[string]interface{}{"height": i})
+		b.ParentStateRoot = state.Cid()
+		b.Messages = msgs.Cid()
+
+		hb, err := b.ToStorageBlock()
+		require.NoError(t, err)
+		s.blocks = append(s.blocks, hb, msgs)
+
+		s.head, err = types.NewTipSet([]*types.BlockHeader{b})
+		require.NoError(t, err)
+	}
+	return s
+}
+
+func (s *testSnapshot) write(t *testing.T, skip cid.Cid, corrupt cid.Cid) string {
+	path := filepath.Join(t.TempDir(), "snapshot.car")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close() //nolint:errcheck
+
+	require.NoError(t, car.WriteHeader(&car.CarHeader{Roots: s.head.Cids(), Version: 1}, f))
+	for _, b := range s.blocks {
+		data := b.RawData()
+		switch b.Cid() {
+		case skip:
+			continue
+		case corrupt:
+			data = append([]byte{}, data...)
+			data[len(data)-1]++
+		}
+		require.NoError(t, carutil.LdWrite(f, b.Cid().Bytes(), data))
+	}
+	return path
+}
+
+func runSnapshotCmd(args ...string) error {
+	app := cli.NewApp()
+	app.Commands = []*cli.Command{snapshotCmd}
+	return app.Run(append([]string{"lotus-shed", "snapshot"}, args...))
+}
+
+func TestSnapshotVerify(t *testing.T) {
+	s := mkSnapshot(t, 5)
+	leaf := s.blocks[1].Cid()
+
+	require.NoError(t, runSnapshotCmd("verify", "--recent-stateroots", "3", s.write(t, cid.Undef, cid.Undef)))
+
+	err := runSnapshotCmd("verify", s.write(t, leaf, cid.Undef))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "1 defects")
+
+	si, err := indexSnapshot(s.write(t, cid.Undef, leaf), t.TempDir())
+	require.NoError(t, err)
+	defer si.Close() //nolint:errcheck
+	require.Equal(t, 1, si.defects)
+	require.Contains(t, si.findings[0], "doesn't match its CID")
+
+	// the header chain breaks below height 2, with only 2 epochs expected
+	// that's fine
+	header1 := s.blocks[2+2*1].Cid()
+	require.NoError(t, runSnapshotCmd("verify", "--epochs", "2", s.write(t, header1, cid.Undef)))
+	require.Error(t, runSnapshotCmd("verify", s.write(t, header1, cid.Undef)))
+}
+
+func TestSnapshotDiff(t *testing.T) {
+	older := mkSnapshot(t, 3)
+	newer := mkSnapshot(t, 5)
+
+	a, err := indexSnapshot(older.write(t, cid.Undef, cid.Undef), t.TempDir())
+	require.NoError(t, err)
+	defer a.Close() //nolint:errcheck
+	b, err := indexSnapshot(newer.write(t, cid.Undef, cid.Undef), t.TempDir())
+	require.NoError(t, err)
+	defer b.Close() //nolint:errcheck
+
+	var added, removed, common int
+	require.NoError(t, diffSnapshots(a, b, func(_ *blockIterator, inOld, inNew bool) {
+		switch {
+		case inOld && inNew:
+			common++
+		case inNew:
+			added++
+		default:
+			removed++
+		}
+	}))
+	// the first three tipsets are the same, with the state
+	require.Equal(t, 2+3*2, common)
+	require.Equal(t, 2*2, added)
+	require.Equal(t, 0, removed)
+
+	r, err := chainRange(b)
+	require.NoError(t, err)
+	require.Equal(t, snapshotRange{head: 4, lowest: 0, stateFrom: 0}, r)
+}
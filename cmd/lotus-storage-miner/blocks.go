@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/api"
+	lcli "github.com/filecoin-project/lotus/cli"
+)
+
+var blocksCmd = &cli.Command{
+	Name:  "blocks",
+	Usage: "Check block production",
+	Subcommands: []*cli.Command{
+		blocksSimulateCmd,
+	},
+}
+
+var blocksSimulateCmd = &cli.Command{
+	Name:  "simulate",
+	Usage: "Run a mining round on the head as if the miner won it, without broadcasting the block",
+	Description: `Checks the worker key can sign the ticket and election proof, computes a
+   WinningPoSt for a random challenge, assembles a block with the messages the
+   mpool selects and validates it, showing how long each stage took against the
+   time a block has to propagate. The miner runs a simulation on startup,
+   --last shows the latest one without running another.`,
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "last",
+			Usage: "show the latest simulation instead of running one",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		nodeApi, closer, err := lcli.GetStorageMinerAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := lcli.ReqContext(cctx)
+
+		var sim *api.BlockSimulation
+		if cctx.Bool("last") {
+			sim, err = nodeApi.MiningLastSimulation(ctx)
+		} else {
+			sim, err = nodeApi.MiningSimulate(ctx)
+		}
+		if err != nil {
+			return err
+		}
+		if sim == nil {
+			fmt.Println("No block production simulated since the miner started")
+			return nil
+		}
+
+		fmt.Printf("Simulated block at epoch %d on %s, %d messages (%s)\n", sim.Epoch, sim.Base, sim.Messages, sim.Start.Format(time.RFC3339))
+		tw := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "Stage\tTook\tError")
+		for _, st := range sim.Stages {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", st.Name, st.Took.Truncate(time.Millisecond), st.Error)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+
+		took := sim.Took.Truncate(time.Millisecond).String()
+		if sim.Took > sim.Budget {
+			took = color.RedString(took)
+		}
+		fmt.Printf("Took %s of the %s a block has to propagate\n", took, sim.Budget)
+		if sim.Error != "" {
+			return xerrors.Errorf("block production failed: %s", sim.Error)
+		}
+		if sim.Took > sim.Budget {
+			fmt.Println(color.YellowString("WARNING: blocks take too long to produce, they would propagate late and may be orphaned"))
+		}
+		return nil
+	},
+}
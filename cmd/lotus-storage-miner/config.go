@@ -5,12 +5,18 @@ import (
 
 	"github.com/urfave/cli/v2"
 
+	lcli "github.com/filecoin-project/lotus/cli"
 	"github.com/filecoin-project/lotus/node/config"
+	"github.com/filecoin-project/lotus/node/repo"
 )
 
 var configCmd = &cli.Command{
 	Name:  "config",
 	Usage: "Output default configuration",
+	Subcommands: []*cli.Command{
+		lcli.ConfigValidateCmd(FlagMinerRepo, repo.StorageMiner),
+		lcli.ConfigHistoryCmd(),
+	},
 	Action: func(cctx *cli.Context) error {
 		comm, err := config.ConfigComment(config.DefaultStorageMiner())
 		if err != nil {
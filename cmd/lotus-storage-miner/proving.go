@@ -29,6 +29,8 @@ var provingCmd = &cli.Command{
 		provingDeadlineInfoCmd,
 		provingFaultsCmd,
 		provingCheckProvableCmd,
+		provingSafeWindowCmd,
+		provingComputeCmd,
 	},
 }
 
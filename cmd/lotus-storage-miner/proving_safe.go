@@ -0,0 +1,300 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/dline"
+	"github.com/filecoin-project/go-state-types/exitcode"
+
+	lapi "github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/api/v0api"
+	"github.com/filecoin-project/lotus/build"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/miner"
+	"github.com/filecoin-project/lotus/chain/types"
+	lcli "github.com/filecoin-project/lotus/cli"
+)
+
+// safeWindowHorizon is how far ahead safe windows are looked for
+const safeWindowHorizon = 24 * time.Hour
+
+var provingSafeWindowCmd = &cli.Command{
+	Name:  "safe-window",
+	Usage: "List the windows in the next 24 hours when the miner can be stopped without missing a WindowPoSt",
+	Description: `A deadline with live sectors is busy from its challenge epoch until its
+   proofs land on chain. How long that takes is estimated from the proofs the
+   miner submitted over the last proving period; without any, the deadline is
+   taken to be busy until it closes. The current deadline is busy until all
+   its partitions with live sectors are proven, however long that takes.
+
+   Prints the windows of at least --need which don't overlap a busy deadline.
+   With --block-until-safe, waits instead until such a window begins, which
+   suits a pre-stop hook.`,
+	Flags: []cli.Flag{
+		&cli.DurationFlag{
+			Name:  "need",
+			Usage: "minimum length of the windows",
+			Value: 30 * time.Minute,
+		},
+		&cli.BoolFlag{
+			Name:  "block-until-safe",
+			Usage: "wait until the next safe window begins instead of listing the windows",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		api, acloser, err := lcli.GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer acloser()
+
+		ctx := lcli.ReqContext(cctx)
+
+		maddr, err := getActorAddress(ctx, cctx)
+		if err != nil {
+			return err
+		}
+
+		need := durationEpochs(cctx.Duration("need"))
+
+		for {
+			sw, err := findSafeWindows(ctx, api, maddr, need)
+			if err != nil {
+				return err
+			}
+
+			if !cctx.Bool("block-until-safe") {
+				return sw.print(need)
+			}
+
+			if len(sw.windows) == 0 {
+				return xerrors.Errorf("no safe window of %s in the next %s", cctx.Duration("need"), safeWindowHorizon)
+			}
+			start := sw.windows[0].from
+			if start <= sw.head.Height() {
+				fmt.Printf("Safe until %s\n", sw.epochTime(sw.windows[0].to).Format(safeWindowTimeFormat))
+				return nil
+			}
+
+			// the start is estimated from the epoch, check again once there
+			fmt.Printf("Waiting until %s for the next safe window\n", sw.epochTime(start).Format(safeWindowTimeFormat))
+			select {
+			case <-time.After(time.Until(sw.epochTime(start))):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	},
+}
+
+const safeWindowTimeFormat = "2006-01-02 15:04:05 MST"
+
+// epochSpan is the span of epochs [from, to)
+type epochSpan struct {
+	from, to abi.ChainEpoch
+}
+
+type safeWindows struct {
+	head *types.TipSet
+	// proofTime is the estimated time from the challenge of a deadline to
+	// its proofs landing, zero if unknown
+	proofTime abi.ChainEpoch
+	proofs    int
+	busy      []epochSpan
+	windows   []epochSpan
+}
+
+func durationEpochs(d time.Duration) abi.ChainEpoch {
+	blockDelay := time.Duration(build.BlockDelaySecs) * time.Second
+	return abi.ChainEpoch((d + blockDelay - 1) / blockDelay)
+}
+
+func (sw *safeWindows) epochTime(e abi.ChainEpoch) time.Time {
+	return time.Unix(int64(sw.head.MinTimestamp()), 0).Add(time.Duration(e-sw.head.Height()) * time.Duration(build.BlockDelaySecs) * time.Second)
+}
+
+func findSafeWindows(ctx context.Context, api v0api.FullNode, maddr address.Address, need abi.ChainEpoch) (*safeWindows, error) {
+	head, err := api.ChainHead(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("getting chain head: %w", err)
+	}
+
+	cd, err := api.StateMinerProvingDeadline(ctx, maddr, head.Key())
+	if err != nil {
+		return nil, xerrors.Errorf("getting proving deadline: %w", err)
+	}
+
+	deadlines, err := api.StateMinerDeadlines(ctx, maddr, head.Key())
+	if err != nil {
+		return nil, xerrors.Errorf("getting deadlines: %w", err)
+	}
+
+	sw := &safeWindows{head: head}
+	sw.proofTime, sw.proofs, err = provingTime(ctx, api, maddr, head, cd)
+	if err != nil {
+		return nil, err
+	}
+
+	until := head.Height() + durationEpochs(safeWindowHorizon)
+	for dlIdx := range deadlines {
+		partitions, err := api.StateMinerPartitions(ctx, maddr, uint64(dlIdx), head.Key())
+		if err != nil {
+			return nil, xerrors.Errorf("getting partitions for deadline %d: %w", dlIdx, err)
+		}
+
+		// the partitions with live sectors which are still to be proven in
+		// the current deadline
+		var live, unproven int
+		for pIdx, part := range partitions {
+			n, err := part.LiveSectors.Count()
+			if err != nil {
+				return nil, err
+			}
+			if n == 0 {
+				continue
+			}
+			live++
+
+			proven, err := deadlines[dlIdx].PostSubmissions.IsSet(uint64(pIdx))
+			if err != nil {
+				return nil, err
+			}
+			if !proven {
+				unproven++
+			}
+		}
+		if live == 0 {
+			continue
+		}
+
+		// from the previous period, as its challenge can be before the
+		// current period starts
+		for ps := cd.PeriodStart - cd.WPoStProvingPeriod; ps < until; ps += cd.WPoStProvingPeriod {
+			di := dline.NewInfo(ps, uint64(dlIdx), head.Height(), cd.WPoStPeriodDeadlines, cd.WPoStProvingPeriod, cd.WPoStChallengeWindow, cd.WPoStChallengeLookback, cd.FaultDeclarationCutoff)
+			if di.Close <= head.Height() {
+				continue
+			}
+
+			end := di.Close
+			if sw.proofTime > 0 && di.Challenge+sw.proofTime < end {
+				end = di.Challenge + sw.proofTime
+			}
+			if ps == cd.PeriodStart && uint64(dlIdx) == cd.Index && di.Challenge <= head.Height() {
+				// in flight, done when all the partitions are proven
+				if unproven == 0 {
+					end = head.Height()
+				} else {
+					end = di.Close
+				}
+			}
+			sw.busy = append(sw.busy, epochSpan{from: di.Challenge, to: end})
+		}
+	}
+
+	sw.windows = freeSpans(sw.busy, head.Height(), until, need)
+	return sw, nil
+}
+
+// provingTime returns the longest time from the challenge of a deadline to
+// its proofs landing, over the proofs of the last proving period
+func provingTime(ctx context.Context, api v0api.FullNode, maddr address.Address, head *types.TipSet, cd *dline.Info) (abi.ChainEpoch, int, error) {
+	msgs, err := api.StateListMessages(ctx, &lapi.MessageMatch{To: maddr}, head.Key(), head.Height()-cd.WPoStProvingPeriod)
+	if err != nil {
+		return 0, 0, xerrors.Errorf("listing messages to the miner: %w", err)
+	}
+
+	var longest abi.ChainEpoch
+	var proofs int
+	for _, mc := range msgs {
+		m, err := api.ChainGetMessage(ctx, mc)
+		if err != nil {
+			return 0, 0, xerrors.Errorf("getting message %s: %w", mc, err)
+		}
+		if m.Method != miner.Methods.SubmitWindowedPoSt {
+			continue
+		}
+
+		ml, err := api.StateSearchMsg(ctx, mc)
+		if err != nil {
+			return 0, 0, xerrors.Errorf("searching message %s: %w", mc, err)
+		}
+		if ml == nil || ml.Receipt.ExitCode != exitcode.Ok {
+			continue
+		}
+
+		// the message landed while its deadline was open, in the epoch
+		// before its receipt
+		landed := ml.Height - 1
+		open := landed - mod(landed-cd.PeriodStart, cd.WPoStChallengeWindow)
+		if t := landed - (open - cd.WPoStChallengeLookback); t > longest {
+			longest = t
+		}
+		proofs++
+	}
+	return longest, proofs, nil
+}
+
+func mod(a, b abi.ChainEpoch) abi.ChainEpoch {
+	return ((a % b) + b) % b
+}
+
+// freeSpans returns the spans of at least need epochs in [from, to) not
+// overlapping any of the busy spans
+func freeSpans(busy []epochSpan, from, to, need abi.ChainEpoch) []epochSpan {
+	busy = append([]epochSpan{}, busy...)
+	sort.Slice(busy, func(i, j int) bool {
+		return busy[i].from < busy[j].from
+	})
+
+	var free []epochSpan
+	cur := from
+	for _, b := range busy {
+		if b.from >= to {
+			break
+		}
+		if b.from > cur && b.from-cur >= need {
+			free = append(free, epochSpan{from: cur, to: b.from})
+		}
+		if b.to > cur {
+			cur = b.to
+		}
+	}
+	if cur < to && to-cur >= need {
+		free = append(free, epochSpan{from: cur, to: to})
+	}
+	return free
+}
+
+func (sw *safeWindows) print(need abi.ChainEpoch) error {
+	if sw.proofs > 0 {
+		fmt.Printf("Proving time: up to %d epochs from the challenge, over %d proofs\n", sw.proofTime, sw.proofs)
+	} else {
+		fmt.Println("Proving time: unknown, no proofs in the last proving period; busy deadlines are taken to last until they close")
+	}
+
+	if len(sw.windows) == 0 {
+		fmt.Printf("No safe window of %d epochs in the next %s\n", need, safeWindowHorizon)
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "Start\tEnd\tLength")
+	for _, w := range sw.windows {
+		start := sw.epochTime(w.from).Format(safeWindowTimeFormat)
+		if w.from <= sw.head.Height() {
+			start = "now"
+		}
+		length := time.Duration(w.to-w.from) * time.Duration(build.BlockDelaySecs) * time.Second
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", start, sw.epochTime(w.to).Format(safeWindowTimeFormat), length)
+	}
+	return tw.Flush()
+}
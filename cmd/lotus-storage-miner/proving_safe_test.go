@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreeSpans(t *testing.T) {
+	busy := []epochSpan{
+		{from: 160, to: 200},
+		{from: 40, to: 90},
+		// overlapping the previous one
+		{from: 80, to: 100},
+		{from: 110, to: 120},
+	}
+
+	require.Equal(t, []epochSpan{
+		{from: 0, to: 40},
+		{from: 100, to: 110},
+		{from: 120, to: 160},
+		{from: 200, to: 300},
+	}, freeSpans(busy, 0, 300, 10))
+
+	require.Equal(t, []epochSpan{
+		{from: 120, to: 160},
+		{from: 200, to: 300},
+	}, freeSpans(busy, 50, 300, 20))
+
+	// in the middle of a busy span, until the end of the horizon
+	require.Empty(t, freeSpans(busy, 170, 210, 20))
+	require.Equal(t, []epochSpan{{from: 200, to: 210}}, freeSpans(busy, 170, 210, 10))
+}
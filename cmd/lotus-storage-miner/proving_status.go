@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/api"
+	lcli "github.com/filecoin-project/lotus/cli"
+)
+
+var provingComputeCmd = &cli.Command{
+	Name:  "compute",
+	Usage: "View WindowPoSt proof computation",
+	Subcommands: []*cli.Command{
+		provingComputeStatusCmd,
+		provingComputeParallelBatchesCmd,
+	},
+}
+
+var provingComputeStatusCmd = &cli.Command{
+	Name:  "status",
+	Usage: "Show the prover, its recent requests and the progress of the proofs of the deadline being proven",
+	Description: `Partitions are proven in batches, one proof per message; the partitions
+   of a batch are proven, or fail, together. Sectors failing to be proven are
+   skipped, see the "wdpost" "sector_skipped" journal events for why.`,
+	Action: func(cctx *cli.Context) error {
+		nodeApi, closer, err := lcli.GetStorageMinerAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := lcli.ReqContext(cctx)
+
+		ps, err := nodeApi.WdPoStProverStatus(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Prover: %s\n", ps.Prover)
+		fmt.Printf("Parallel batches: %d\n", ps.ParallelBatches)
+		if len(ps.Requests) > 0 {
+			fmt.Println("Recent requests:")
+			tw := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+			_, _ = fmt.Fprintln(tw, "  Prover\tStarted\tTook\tSectors\tError")
+			for _, r := range ps.Requests {
+				_, _ = fmt.Fprintf(tw, "  %s\t%s\t%s\t%d\t%s\n", r.Prover, r.Start.Format(time.Stamp), r.Took.Truncate(time.Millisecond), r.Sectors, r.Error)
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+		}
+		fmt.Println()
+
+		st, err := nodeApi.WdPoStStatus(ctx)
+		if err != nil {
+			return err
+		}
+		if st == nil {
+			fmt.Println("No deadline proven since the miner started")
+			return nil
+		}
+
+		fmt.Printf("Deadline %d (epochs %d to %d)\n", st.Deadline, st.Open, st.Close)
+		if len(st.Partitions) == 0 {
+			fmt.Println("No partitions to prove")
+			return nil
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "Partition\tBatch\tState\tSkipped\tError")
+		for _, p := range st.Partitions {
+			state := string(p.State)
+			switch p.State {
+			case api.WdPoStProven:
+				state = color.GreenString(state)
+			case api.WdPoStFailed:
+				state = color.RedString(state)
+			}
+			_, _ = fmt.Fprintf(tw, "%d\t%d\t%s\t%d\t%s\n", p.Index, p.Batch, state, p.Skipped, p.Error)
+		}
+		return tw.Flush()
+	},
+}
+
+var provingComputeParallelBatchesCmd = &cli.Command{
+	Name:      "parallel-batches",
+	Usage:     "Set how many batches of partitions of a deadline are proven at once",
+	ArgsUsage: "<count>",
+	Description: `Takes effect from the next deadline on, and is saved in the config.
+   Proving in parallel contends with WinningPoSt for the GPU.`,
+	Action: func(cctx *cli.Context) error {
+		if cctx.Args().Len() != 1 {
+			return xerrors.Errorf("expected 1 argument: the number of batches")
+		}
+		n, err := strconv.Atoi(cctx.Args().First())
+		if err != nil {
+			return xerrors.Errorf("parsing the number of batches: %w", err)
+		}
+
+		nodeApi, closer, err := lcli.GetStorageMinerAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		return nodeApi.WdPoStSetParallelBatches(lcli.ReqContext(cctx), n)
+	},
+}
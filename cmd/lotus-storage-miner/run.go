@@ -185,7 +185,7 @@ var runCmd = &cli.Command{
 		}
 
 		srv := &http.Server{
-			Handler: ah,
+			Handler: api.CallerHandler(ah),
 			BaseContext: func(listener net.Listener) context.Context {
 				ctx, _ := tag.New(context.Background(), tag.Upsert(metrics.APIInterface, "lotus-miner"))
 				return ctx
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -39,8 +40,11 @@ var sectorsCmd = &cli.Command{
 		sectorsUpdateCmd,
 		sectorsPledgeCmd,
 		sectorsExtendCmd,
+		sectorsExpirationsCmd,
 		sectorsTerminateCmd,
 		sectorsRemoveCmd,
+		sectorsExportCmd,
+		sectorsImportCmd,
 		sectorsMarkForUpgradeCmd,
 		sectorsStartSealCmd,
 		sectorsSealDelayCmd,
@@ -638,6 +642,95 @@ var sectorsExtendCmd = &cli.Command{
 	},
 }
 
+var sectorsExpirationsCmd = &cli.Command{
+	Name:  "expirations",
+	Usage: "Show live sectors by expiration month, and suggest extensions spreading out concentrated months",
+	Flags: []cli.Flag{
+		&cli.Float64Flag{
+			Name:  "max-share",
+			Usage: "flag months in which more than this share (0-1) of live sectors expire",
+			Value: 0.2,
+		},
+		&cli.BoolFlag{
+			Name:  "export",
+			Usage: "only print the suggested extensions, as 'sectors extend' commands",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		api, nCloser, err := lcli.GetFullNodeAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer nCloser()
+
+		ctx := lcli.ReqContext(cctx)
+
+		maddr, err := getActorAddress(ctx, cctx)
+		if err != nil {
+			return err
+		}
+
+		ladder, err := api.StateMinerExpirationLadder(ctx, maddr, cctx.Float64("max-share"), types.EmptyTSK)
+		if err != nil {
+			return xerrors.Errorf("getting expiration ladder: %w", err)
+		}
+
+		extendCmds := make([]string, 0, len(ladder.Suggestions))
+		for _, ext := range ladder.Suggestions {
+			sns := make([]string, len(ext.Sectors))
+			for i, sn := range ext.Sectors {
+				sns[i] = strconv.FormatUint(uint64(sn), 10)
+			}
+			extendCmds = append(extendCmds, fmt.Sprintf("lotus-miner sectors extend --new-expiration=%d %s", ext.NewExpiration, strings.Join(sns, " ")))
+		}
+
+		if cctx.Bool("export") {
+			for _, c := range extendCmds {
+				fmt.Println(c)
+			}
+			return nil
+		}
+
+		tw := tablewriter.New(
+			tablewriter.Col("Month"),
+			tablewriter.Col("From"),
+			tablewriter.Col("Sectors"),
+			tablewriter.Col("Raw"),
+			tablewriter.Col("QAP"),
+			tablewriter.Col("Pledge"),
+			tablewriter.Col("Concentrated"))
+
+		for i, b := range ladder.Buckets {
+			m := map[string]interface{}{
+				"Month":   i,
+				"From":    lcli.EpochTime(ladder.Height, b.Start),
+				"Sectors": b.Sectors,
+				"Raw":     types.SizeStr(b.RawBytes),
+				"QAP":     types.SizeStr(b.QAPower),
+				"Pledge":  types.FIL(b.Pledge).Short(),
+			}
+			if b.Concentrated {
+				m["Concentrated"] = color.RedString("yes")
+			}
+			tw.Write(m)
+		}
+
+		if err := tw.Flush(os.Stdout); err != nil {
+			return err
+		}
+
+		if len(extendCmds) == 0 {
+			return nil
+		}
+
+		fmt.Printf("\nSuggested extensions (%d batches, export with --export):\n", len(extendCmds))
+		for _, c := range extendCmds {
+			fmt.Println(c)
+		}
+		return nil
+	},
+}
+
 var sectorsTerminateCmd = &cli.Command{
 	Name:      "terminate",
 	Usage:     "Terminate sector on-chain then remove (WARNING: This means losing power and collateral for the removed sector)",
@@ -785,6 +878,99 @@ var sectorsRemoveCmd = &cli.Command{
 	},
 }
 
+var sectorsExportCmd = &cli.Command{
+	Name:      "export",
+	Usage:     "Export the files of a proving sector, with a manifest of its seal and deal info",
+	ArgsUsage: "<sectorNum>",
+	Description: `Copies the sealed and cache files of the sector to the output directory on the
+   miner, with a manifest.json holding its ticket, seed, commitments and deals,
+   to be imported with 'sectors import' into another instance of the same miner
+   actor. The sector keeps being proven by this miner until it's removed.`,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "output",
+			Usage:    "directory to export the sector to",
+			Required: true,
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.Args().Len() != 1 {
+			return lcli.ShowHelp(cctx, xerrors.Errorf("must pass sector number"))
+		}
+
+		nodeApi, closer, err := lcli.GetStorageMinerAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+		ctx := lcli.ReqContext(cctx)
+
+		id, err := strconv.ParseUint(cctx.Args().Get(0), 10, 64)
+		if err != nil {
+			return xerrors.Errorf("could not parse sector number: %w", err)
+		}
+		out, err := filepath.Abs(cctx.String("output"))
+		if err != nil {
+			return err
+		}
+
+		mf, err := nodeApi.SectorExport(ctx, abi.SectorNumber(id), out)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("exported sector %d of %s to %s\n", mf.SectorNumber, mf.Miner, out)
+		fmt.Printf("CommR: %s\nCommD: %s\nDeals: %d\n", mf.CommR, mf.CommD, len(mf.Pieces))
+		return nil
+	},
+}
+
+var sectorsImportCmd = &cli.Command{
+	Name:      "import",
+	Usage:     "Import a sector exported from another instance of this miner actor",
+	ArgsUsage: "<manifest>",
+	Description: `Verifies the manifest and the files of a sector exported with 'sectors export'
+   against the on-chain commitments of the sector, then copies its files to a
+   storage path of this miner and adds it to the sealing store as proving.
+   Imports are rejected naming the field which disagrees with the chain.
+
+   Sectors which aren't proven on chain are refused, unless --allow-unproven
+   is set to recover them, their files being verified against the manifest.`,
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "allow-unproven",
+			Usage: "import sectors which aren't proven on chain",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.Args().Len() != 1 {
+			return lcli.ShowHelp(cctx, xerrors.Errorf("must pass the manifest of the sector"))
+		}
+
+		nodeApi, closer, err := lcli.GetStorageMinerAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+		ctx := lcli.ReqContext(cctx)
+
+		manifest, err := filepath.Abs(cctx.Args().First())
+		if err != nil {
+			return err
+		}
+		if fi, err := os.Stat(manifest); err == nil && fi.IsDir() {
+			manifest = filepath.Join(manifest, api.SectorManifestName)
+		}
+
+		if err := nodeApi.SectorImport(ctx, manifest, cctx.Bool("allow-unproven")); err != nil {
+			return err
+		}
+
+		fmt.Println("sector imported")
+		return nil
+	},
+}
+
 var sectorsMarkForUpgradeCmd = &cli.Command{
 	Name:      "mark-for-upgrade",
 	Usage:     "Mark a committed capacity sector for replacement by a sector with deals",
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	lcli "github.com/filecoin-project/lotus/cli"
+	"github.com/filecoin-project/lotus/node/config"
+	"github.com/filecoin-project/lotus/node/repo"
+)
+
+var configCmd = &cli.Command{
+	Name:  "config",
+	Usage: "Output default configuration",
+	Subcommands: []*cli.Command{
+		lcli.ConfigValidateCmd("repo", repo.FullNode),
+		lcli.ConfigHistoryCmd(),
+	},
+	Action: func(cctx *cli.Context) error {
+		comm, err := config.ConfigComment(config.DefaultFullNode())
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(comm))
+		return nil
+	},
+}
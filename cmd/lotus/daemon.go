@@ -75,7 +75,7 @@ var daemonStopCmd = &cli.Command{
 var DaemonCmd = &cli.Command{
 	Name:  "daemon",
 	Usage: "Start a lotus daemon process",
-	Flags: []cli.Flag{
+	Flags: append([]cli.Flag{
 		&cli.StringFlag{
 			Name:  "api",
 			Value: "1234",
@@ -144,6 +144,10 @@ var DaemonCmd = &cli.Command{
 			Name:  "api-max-req-size",
 			Usage: "maximum API request size accepted by the JSON RPC server",
 		},
+		&cli.BoolFlag{
+			Name:  "api-deprecation-warnings",
+			Usage: "add a warning field to HTTP JSON RPC responses of deprecated methods",
+		},
 		&cli.PathFlag{
 			Name:  "restore",
 			Usage: "restore from backup file",
@@ -152,7 +156,7 @@ var DaemonCmd = &cli.Command{
 			Name:  "restore-config",
 			Usage: "config file to use when restoring from backup",
 		},
-	},
+	}, devnetFlags...),
 	Action: func(cctx *cli.Context) error {
 		isLite := cctx.Bool("lite")
 
@@ -287,6 +291,18 @@ var DaemonCmd = &cli.Command{
 			genesis = node.Override(new(modules.Genesis), testing.MakeGenesis(cctx.String(makeGenFlag), cctx.String(preTemplateFlag)))
 		}
 
+		var dn *devnet
+		if cctx.Bool("devnet") {
+			repoPath, err := homedir.Expand(cctx.String("repo"))
+			if err != nil {
+				return err
+			}
+			dn, genesis, err = setupDevnet(ctx, cctx, repoPath, freshRepo)
+			if err != nil {
+				return xerrors.Errorf("setting up devnet: %w", err)
+			}
+		}
+
 		shutdownChan := make(chan struct{})
 
 		// If the daemon is started in "lite mode", provide a  Gateway
@@ -331,7 +347,7 @@ var DaemonCmd = &cli.Command{
 					}
 					return lr.SetAPIEndpoint(apima)
 				})),
-			node.ApplyIf(func(s *node.Settings) bool { return !cctx.Bool("bootstrap") },
+			node.ApplyIf(func(s *node.Settings) bool { return !cctx.Bool("bootstrap") || dn != nil },
 				node.Unset(node.RunPeerMgrKey),
 				node.Unset(new(*peermgr.PeerMgr)),
 			),
@@ -346,13 +362,19 @@ var DaemonCmd = &cli.Command{
 			}
 		}
 
+		if dn != nil {
+			if err := dn.started(ctx, api); err != nil {
+				return xerrors.Errorf("starting devnet: %w", err)
+			}
+		}
+
 		endpoint, err := r.APIEndpoint()
 		if err != nil {
 			return xerrors.Errorf("getting api endpoint: %w", err)
 		}
 
 		// TODO: properly parse api endpoint (or make it a URL)
-		return serveRPC(api, stop, endpoint, shutdownChan, int64(cctx.Int("api-max-req-size")))
+		return serveRPC(api, stop, endpoint, shutdownChan, int64(cctx.Int("api-max-req-size")), cctx.Bool("api-deprecation-warnings"))
 	},
 	Subcommands: []*cli.Command{
 		daemonStopCmd,
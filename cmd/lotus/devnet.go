@@ -0,0 +1,309 @@
+// +build !nodaemon
+
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	paramfetch "github.com/filecoin-project/go-paramfetch"
+	"github.com/google/uuid"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/build"
+	"github.com/filecoin-project/lotus/chain/gen"
+	genesis2 "github.com/filecoin-project/lotus/chain/gen/genesis"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/chain/wallet"
+	"github.com/filecoin-project/lotus/cmd/lotus-seed/seed"
+	"github.com/filecoin-project/lotus/genesis"
+	"github.com/filecoin-project/lotus/node"
+	"github.com/filecoin-project/lotus/node/modules"
+	"github.com/filecoin-project/lotus/node/modules/testing"
+)
+
+const (
+	devnetDir          = "devnet"
+	devnetInfoFile     = "devnet.json"
+	devnetAccountsFile = "accounts.json"
+	devnetGenesisFile  = "devnet.car"
+	devnetTemplateFile = "genesis-template.json"
+	devnetPresealDir   = "presealed"
+	devnetMinerRepo    = "miner"
+
+	devnetSealProof = abi.RegisteredSealProof_StackedDrg2KiBV1
+)
+
+var devnetFlags = []cli.Flag{
+	&cli.BoolFlag{
+		Name: "devnet",
+		Usage: "run a local devnet: on first run, generate its genesis with funded accounts and a pre-sealed miner in the repo, " +
+			"resume it on the next runs. The block time is set with LOTUS_2K_BLOCK_DELAY, needs a 2k build",
+	},
+	&cli.IntFlag{
+		Name:  "devnet-accounts",
+		Usage: "number of funded accounts in a new devnet",
+		Value: 3,
+	},
+	&cli.StringFlag{
+		Name:  "devnet-balance",
+		Usage: "balance of each account of a new devnet",
+		Value: "100000",
+	},
+	&cli.IntFlag{
+		Name:  "devnet-sectors",
+		Usage: "number of 2KiB sectors pre-sealed for the miner of a new devnet",
+		Value: 2,
+	},
+}
+
+// devnetInfo describes a devnet, kept in its dir once its genesis is made
+type devnetInfo struct {
+	NetworkName    string
+	BlockDelaySecs uint64
+	Miner          address.Address
+	SectorSize     abi.SectorSize
+}
+
+// devnetAccount is a funded account of a devnet, in accounts.json for tests
+// to use. The key is hex encoded, as `lotus wallet export` prints it.
+type devnetAccount struct {
+	Address address.Address
+	Balance types.FIL
+	Key     string
+}
+
+// devnet is a local network, kept in the devnet dir of the repo
+type devnet struct {
+	repo string
+	dir  string
+	info devnetInfo
+
+	// fresh is set when the genesis is made on this run, the keys are then
+	// imported once the node is up
+	fresh bool
+	keys  []types.KeyInfo
+}
+
+// setupDevnet returns the devnet of the repo, making it on first run, and the
+// genesis of the node
+func setupDevnet(ctx context.Context, cctx *cli.Context, repoPath string, freshRepo bool) (*devnet, node.Option, error) {
+	if build.BuildType&build.Build2k == 0 {
+		return nil, nil, xerrors.Errorf("--devnet needs a 2k build of lotus (make 2k)")
+	}
+	for _, f := range []string{"genesis", makeGenFlag, preTemplateFlag, "import-chain", "import-snapshot", "lite", "restore"} {
+		if cctx.IsSet(f) {
+			return nil, nil, xerrors.Errorf("--%s can't be used with --devnet", f)
+		}
+	}
+
+	dn := &devnet{repo: repoPath, dir: filepath.Join(repoPath, devnetDir)}
+
+	ib, err := ioutil.ReadFile(filepath.Join(dn.dir, devnetInfoFile))
+	switch {
+	case err == nil:
+		if err := dn.resume(ib); err != nil {
+			return nil, nil, err
+		}
+		genesis, err := dn.genesis()
+		if err != nil {
+			return nil, nil, err
+		}
+		return dn, genesis, nil
+	case !os.IsNotExist(err):
+		return nil, nil, xerrors.Errorf("reading devnet info: %w", err)
+	}
+
+	if _, err := os.Stat(dn.dir); os.IsNotExist(err) && !freshRepo {
+		return nil, nil, xerrors.Errorf("repo %s holds a chain which isn't a devnet, use a new repo for --devnet", repoPath)
+	}
+	// a devnet dir without its info is from a first run which didn't make
+	// the genesis
+	if err := os.RemoveAll(dn.dir); err != nil {
+		return nil, nil, xerrors.Errorf("removing unfinished devnet: %w", err)
+	}
+
+	genesis, err := dn.generate(ctx, cctx)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("generating devnet: %w", err)
+	}
+	return dn, genesis, nil
+}
+
+func (dn *devnet) resume(ib []byte) error {
+	if err := json.Unmarshal(ib, &dn.info); err != nil {
+		return xerrors.Errorf("decoding devnet info: %w", err)
+	}
+	if dn.info.BlockDelaySecs != build.BlockDelaySecs {
+		return xerrors.Errorf("the devnet has a block time of %ds, not %ds, set LOTUS_2K_BLOCK_DELAY=%d",
+			dn.info.BlockDelaySecs, build.BlockDelaySecs, dn.info.BlockDelaySecs)
+	}
+
+	log.Infof("resuming devnet %s", dn.info.NetworkName)
+	return nil
+}
+
+// genesis returns the genesis of a resumed devnet
+func (dn *devnet) genesis() (node.Option, error) {
+	genBytes, err := ioutil.ReadFile(filepath.Join(dn.dir, devnetGenesisFile))
+	if err != nil {
+		return nil, xerrors.Errorf("reading devnet genesis: %w", err)
+	}
+	return node.Override(new(modules.Genesis), modules.LoadGenesis(genBytes)), nil
+}
+
+// generate pre-seals the miner and makes the accounts of a new devnet, and
+// returns the genesis made from them
+func (dn *devnet) generate(ctx context.Context, cctx *cli.Context) (node.Option, error) {
+	balance, err := types.ParseFIL(cctx.String("devnet-balance"))
+	if err != nil {
+		return nil, xerrors.Errorf("parsing --devnet-balance: %w", err)
+	}
+	if cctx.Int("devnet-accounts") < 1 || cctx.Int("devnet-sectors") < 1 {
+		return nil, xerrors.Errorf("a devnet needs at least one account and one sector")
+	}
+
+	if err := os.MkdirAll(dn.dir, 0755); err != nil {
+		return nil, err
+	}
+
+	ssize, err := devnetSealProof.SectorSize()
+	if err != nil {
+		return nil, err
+	}
+	if err := paramfetch.GetParams(ctx, build.ParametersJSON(), build.SrsJSON(), uint64(ssize)); err != nil {
+		return nil, xerrors.Errorf("fetching proof parameters: %w", err)
+	}
+
+	maddr, err := address.NewIDAddress(genesis2.MinerStart)
+	if err != nil {
+		return nil, err
+	}
+
+	sbroot := filepath.Join(dn.dir, devnetPresealDir)
+	genm, minerKey, err := seed.PreSeal(maddr, devnetSealProof, 0, cctx.Int("devnet-sectors"), sbroot, []byte("lotus devnet"), nil, false)
+	if err != nil {
+		return nil, xerrors.Errorf("pre-sealing: %w", err)
+	}
+	if err := seed.WriteGenesisMiner(maddr, sbroot, genm, minerKey); err != nil {
+		return nil, xerrors.Errorf("writing genesis miner: %w", err)
+	}
+
+	template := genesis.Template{
+		Miners: []genesis.Miner{*genm},
+		Accounts: []genesis.Actor{{
+			Type:    genesis.TAccount,
+			Balance: types.FromFil(5000000),
+			Meta:    (&genesis.AccountMeta{Owner: genm.Owner}).ActorMeta(),
+		}},
+		NetworkName:      "devnet-" + uuid.New().String(),
+		VerifregRootKey:  gen.DefaultVerifregRootkeyActor,
+		RemainderAccount: gen.DefaultRemainderAccountActor,
+	}
+
+	var accounts []devnetAccount
+	for i := 0; i < cctx.Int("devnet-accounts"); i++ {
+		k, err := wallet.GenerateKey(types.KTSecp256k1)
+		if err != nil {
+			return nil, err
+		}
+		kb, err := json.Marshal(k.KeyInfo)
+		if err != nil {
+			return nil, err
+		}
+
+		template.Accounts = append(template.Accounts, genesis.Actor{
+			Type:    genesis.TAccount,
+			Balance: abi.TokenAmount(balance),
+			Meta:    (&genesis.AccountMeta{Owner: k.Address}).ActorMeta(),
+		})
+		accounts = append(accounts, devnetAccount{
+			Address: k.Address,
+			Balance: balance,
+			Key:     hex.EncodeToString(kb),
+		})
+		dn.keys = append(dn.keys, k.KeyInfo)
+	}
+	// the miner key goes last, the first account being the default
+	dn.keys = append(dn.keys, *minerKey)
+
+	tb, err := json.MarshalIndent(&template, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	templatePath := filepath.Join(dn.dir, devnetTemplateFile)
+	if err := ioutil.WriteFile(templatePath, tb, 0644); err != nil {
+		return nil, xerrors.Errorf("writing genesis template: %w", err)
+	}
+
+	ab, err := json.MarshalIndent(accounts, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dn.dir, devnetAccountsFile), ab, 0600); err != nil {
+		return nil, xerrors.Errorf("writing accounts: %w", err)
+	}
+
+	dn.fresh = true
+	dn.info = devnetInfo{
+		NetworkName:    template.NetworkName,
+		BlockDelaySecs: build.BlockDelaySecs,
+		Miner:          maddr,
+		SectorSize:     ssize,
+	}
+	return node.Override(new(modules.Genesis), testing.MakeGenesis(filepath.Join(dn.dir, devnetGenesisFile), templatePath)), nil
+}
+
+// started finishes a new devnet once the node made its genesis, importing
+// the keys, and shows how to run the miner
+func (dn *devnet) started(ctx context.Context, full api.FullNode) error {
+	if dn.fresh {
+		for i, ki := range dn.keys {
+			addr, err := full.WalletImport(ctx, &ki)
+			if err != nil {
+				return xerrors.Errorf("importing devnet key: %w", err)
+			}
+			if i == 0 {
+				if err := full.WalletSetDefault(ctx, addr); err != nil {
+					return err
+				}
+			}
+		}
+
+		ib, err := json.MarshalIndent(dn.info, "", "  ")
+		if err != nil {
+			return err
+		}
+		// written last, a devnet without it is made again
+		if err := ioutil.WriteFile(filepath.Join(dn.dir, devnetInfoFile), ib, 0644); err != nil {
+			return xerrors.Errorf("writing devnet info: %w", err)
+		}
+	}
+
+	env := fmt.Sprintf("LOTUS_PATH=%s LOTUS_MINER_PATH=%s", dn.repo, filepath.Join(dn.dir, devnetMinerRepo))
+	if dn.info.BlockDelaySecs != 4 {
+		env = fmt.Sprintf("LOTUS_2K_BLOCK_DELAY=%d %s", dn.info.BlockDelaySecs, env)
+	}
+	sbroot := filepath.Join(dn.dir, devnetPresealDir)
+
+	fmt.Printf("Devnet %s, with a block time of %ds\n", dn.info.NetworkName, dn.info.BlockDelaySecs)
+	fmt.Printf("Funded accounts and their keys: %s\n", filepath.Join(dn.dir, devnetAccountsFile))
+	fmt.Printf("Run its miner %s with:\n", dn.info.Miner)
+	if _, err := os.Stat(filepath.Join(dn.dir, devnetMinerRepo)); os.IsNotExist(err) {
+		fmt.Printf("  %s lotus-miner init --genesis-miner --actor=%s --sector-size=%d --pre-sealed-sectors=%s --pre-sealed-metadata=%s --nosync\n",
+			env, dn.info.Miner, dn.info.SectorSize, sbroot,
+			filepath.Join(sbroot, "pre-seal-"+dn.info.Miner.String()+".json"))
+	}
+	fmt.Printf("  %s lotus-miner run --nosync\n", env)
+	return nil
+}
@@ -24,6 +24,7 @@ func main() {
 	local := []*cli.Command{
 		DaemonCmd,
 		backupCmd,
+		configCmd,
 	}
 	if AdvanceBlockCmd != nil {
 		local = append(local, AdvanceBlockCmd)
@@ -64,6 +65,7 @@ func main() {
 				Hidden:  true,
 				Value:   "~/.lotus", // TODO: Consider XDG_DATA_HOME
 			},
+			lcli.FeeUnitFlag,
 		},
 
 		Commands: append(local, lcli.Commands...),
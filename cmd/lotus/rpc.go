@@ -1,14 +1,19 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"net"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
 	"os/signal"
 	"runtime"
+	"strings"
 	"syscall"
 
 	"github.com/ipfs/go-cid"
@@ -24,6 +29,8 @@ import (
 	"github.com/filecoin-project/lotus/api"
 	"github.com/filecoin-project/lotus/api/v0api"
 	"github.com/filecoin-project/lotus/api/v1api"
+	"github.com/filecoin-project/lotus/lib/headpin"
+	"github.com/filecoin-project/lotus/lib/rpcrecord"
 	"github.com/filecoin-project/lotus/metrics"
 	"github.com/filecoin-project/lotus/node"
 	"github.com/filecoin-project/lotus/node/impl"
@@ -31,27 +38,33 @@ import (
 
 var log = logging.Logger("main")
 
-func serveRPC(a v1api.FullNode, stop node.StopFunc, addr multiaddr.Multiaddr, shutdownCh <-chan struct{}, maxRequestSize int64) error {
+func serveRPC(a v1api.FullNode, stop node.StopFunc, addr multiaddr.Multiaddr, shutdownCh <-chan struct{}, maxRequestSize int64, deprecationWarnings bool) error {
 	serverOptions := make([]jsonrpc.ServerOption, 0)
 	if maxRequestSize != 0 { // config set
 		serverOptions = append(serverOptions, jsonrpc.WithMaxRequestSize(maxRequestSize))
 	}
-	serveRpc := func(path string, hnd interface{}) {
+	serveRpc := func(path string, hnd interface{}, methods map[string]api.MethodStability) {
 		rpcServer := jsonrpc.NewServer(serverOptions...)
 		rpcServer.Register("Filecoin", hnd)
 
+		var next http.Handler = rpcServer
+		if deprecationWarnings {
+			next = &deprecationWarningHandler{methods: methods, maxRequestSize: maxRequestSize, next: rpcServer}
+		}
+
 		ah := &auth.Handler{
 			Verify: a.AuthVerify,
-			Next:   rpcServer.ServeHTTP,
+			Next:   next.ServeHTTP,
 		}
 
-		http.Handle(path, ah)
+		http.Handle(path, headpin.Handler(api.CallerHandler(ah)))
 	}
 
-	pma := api.PermissionedFullAPI(metrics.MetricedFullAPI(a))
+	fa := a.(*impl.FullNodeAPI)
+	pma := api.PermissionedFullAPI(rpcrecord.RecordedFullAPI(headpin.PinnedFullAPI(metrics.MetricedFullAPI(a), fa.HeadPins), fa.Recorder))
 
-	serveRpc("/rpc/v1", pma)
-	serveRpc("/rpc/v0", &v0api.WrapperV1Full{FullNode: pma})
+	serveRpc("/rpc/v1", pma, api.GetMethodStability(new(api.FullNodeStruct)))
+	serveRpc("/rpc/v0", &v0api.WrapperV1Full{FullNode: pma}, api.GetMethodStability(new(v0api.FullNodeStruct)))
 
 	importAH := &auth.Handler{
 		Verify: a.AuthVerify,
@@ -136,3 +149,98 @@ func handleImport(a *impl.FullNodeAPI) func(w http.ResponseWriter, r *http.Reque
 		}
 	}
 }
+
+// deprecationWarningHandler adds a warning field to HTTP JSON RPC responses of
+// deprecated methods. Websocket connections are passed through as-is.
+type deprecationWarningHandler struct {
+	methods        map[string]api.MethodStability
+	maxRequestSize int64
+	next           http.Handler
+}
+
+func (h *deprecationWarningHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	maxSize := h.maxRequestSize
+	if maxSize == 0 {
+		maxSize = jsonrpc.DEFAULT_MAX_REQUEST_SIZE
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxSize+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// oversized requests are rejected by the rpc server
+	r.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+
+	var req struct {
+		Method string `json:"method"`
+	}
+	if int64(len(body)) > maxSize || json.Unmarshal(body, &req) != nil {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	warning := deprecationWarning(h.methods, req.Method)
+	if warning == "" {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	rec := &bufferedResponse{header: http.Header{}, status: http.StatusOK}
+	h.next.ServeHTTP(rec, r)
+
+	resp := rec.body.Bytes()
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(resp, &fields); err == nil {
+		fields["warning"], _ = json.Marshal(warning)
+		if out, err := json.Marshal(fields); err == nil {
+			resp = out
+		}
+	}
+
+	for k, v := range rec.header {
+		w.Header()[k] = v
+	}
+	w.Header().Del("Content-Length")
+	w.WriteHeader(rec.status)
+	_, _ = w.Write(resp)
+}
+
+func deprecationWarning(methods map[string]api.MethodStability, method string) string {
+	m, ok := methods[strings.TrimPrefix(method, "Filecoin.")]
+	if !ok || m.Stability != api.StabilityDeprecated {
+		return ""
+	}
+
+	warning := fmt.Sprintf("%s is deprecated", method)
+	if m.Removal != "" {
+		warning += fmt.Sprintf(" and will be removed in %s", m.Removal)
+	}
+	if m.Replacement != "" {
+		warning += fmt.Sprintf(", use Filecoin.%s instead", m.Replacement)
+	}
+	return warning
+}
+
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	b.status = status
+}
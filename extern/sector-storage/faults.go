@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/ipfs/go-cid"
 	"golang.org/x/xerrors"
 
 	ffi "github.com/filecoin-project/filecoin-ffi"
@@ -139,6 +140,64 @@ func (m *Manager) CheckProvable(ctx context.Context, pp abi.RegisteredPoStProof,
 	return bad, nil
 }
 
+// VerifySectorFiles checks the sealed and cache files of a sector at the paths
+// are complete and match the commR, by generating a vanilla proof for a random
+// challenge as CheckProvable does
+func VerifySectorFiles(sector storage.SectorRef, sealed, cache string, commr cid.Cid) error {
+	ssize, err := sector.ProofType.SectorSize()
+	if err != nil {
+		return err
+	}
+
+	toCheck := map[string]int64{
+		sealed:                        1,
+		filepath.Join(cache, "t_aux"): 0,
+		filepath.Join(cache, "p_aux"): 0,
+	}
+	addCachePathsForSectorSize(toCheck, cache, ssize)
+
+	for p, sz := range toCheck {
+		st, err := os.Stat(p)
+		if err != nil {
+			return err
+		}
+		if sz != 0 && st.Size() != int64(ssize)*sz {
+			return xerrors.Errorf("%s is wrong size (got %d, expect %d)", p, st.Size(), int64(ssize)*sz)
+		}
+	}
+
+	wpp, err := sector.ProofType.RegisteredWindowPoStProof()
+	if err != nil {
+		return err
+	}
+
+	var pr abi.PoStRandomness = make([]byte, abi.RandomnessLength)
+	_, _ = rand.Read(pr)
+	pr[31] &= 0x3f
+
+	ch, err := ffi.GeneratePoStFallbackSectorChallenges(wpp, sector.ID.Miner, pr, []abi.SectorNumber{
+		sector.ID.Number,
+	})
+	if err != nil {
+		return xerrors.Errorf("generating fallback challenges: %w", err)
+	}
+
+	_, err = ffi.GenerateSingleVanillaProof(ffi.PrivateSectorInfo{
+		SectorInfo: proof.SectorInfo{
+			SealProof:    sector.ProofType,
+			SectorNumber: sector.ID.Number,
+			SealedCID:    commr,
+		},
+		CacheDirPath:     cache,
+		PoStProofType:    wpp,
+		SealedSectorPath: sealed,
+	}, ch.Challenges[sector.ID.Number])
+	if err != nil {
+		return xerrors.Errorf("generating vanilla proof: %w", err)
+	}
+	return nil
+}
+
 func addCachePathsForSectorSize(chk map[string]int64, cacheDir string, ssize abi.SectorSize) {
 	switch ssize {
 	case 2 << 10:
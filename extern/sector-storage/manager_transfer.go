@@ -0,0 +1,125 @@
+package sectorstorage
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-storage/storage"
+
+	"github.com/filecoin-project/lotus/extern/sector-storage/stores"
+	"github.com/filecoin-project/lotus/extern/sector-storage/storiface"
+)
+
+// ExportSector copies the sealed and cache files of a sector, which must be in
+// a local storage path, to the sealed and cache paths
+func (m *Manager) ExportSector(ctx context.Context, sector storage.SectorRef, sealed, cache string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if err := m.index.StorageLock(ctx, sector.ID, storiface.FTSealed|storiface.FTCache, storiface.FTNone); err != nil {
+		return xerrors.Errorf("acquiring sector lock: %w", err)
+	}
+
+	lp, _, err := m.localStore.AcquireSector(ctx, sector, storiface.FTSealed|storiface.FTCache, storiface.FTNone, storiface.PathStorage, storiface.AcquireCopy)
+	if err != nil {
+		return xerrors.Errorf("finding sector files: %w", err)
+	}
+	if lp.Sealed == "" || lp.Cache == "" {
+		return xerrors.Errorf("the sealed and cache files of sector %d aren't in a local storage path (sealed %q, cache %q)", sector.ID.Number, lp.Sealed, lp.Cache)
+	}
+
+	if err := copyPath(lp.Sealed, sealed); err != nil {
+		return xerrors.Errorf("copying sealed file: %w", err)
+	}
+	if err := copyPath(lp.Cache, cache); err != nil {
+		return xerrors.Errorf("copying cache: %w", err)
+	}
+	return nil
+}
+
+// ImportSector copies the sealed and cache files of a sector to a local
+// storage path, and declares them in the index. The sector must have no files
+// in the storage yet. On failure, the files copied so far are removed and
+// undeclared.
+func (m *Manager) ImportSector(ctx context.Context, sector storage.SectorRef, sealed, cache string) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if err := m.index.StorageLock(ctx, sector.ID, storiface.FTNone, storiface.FTSealed|storiface.FTCache); err != nil {
+		return xerrors.Errorf("acquiring sector lock: %w", err)
+	}
+
+	ssize, err := sector.ProofType.SectorSize()
+	if err != nil {
+		return err
+	}
+	existing, err := m.index.StorageFindSector(ctx, sector.ID, storiface.FTSealed|storiface.FTCache, ssize, false)
+	if err != nil {
+		return xerrors.Errorf("finding existing sector files: %w", err)
+	}
+	if len(existing) > 0 {
+		return xerrors.Errorf("sector %d already has files in storage %s", sector.ID.Number, existing[0].ID)
+	}
+
+	dest, destIds, err := m.localStore.AcquireSector(ctx, sector, storiface.FTNone, storiface.FTSealed|storiface.FTCache, storiface.PathStorage, storiface.AcquireMove)
+	if err != nil {
+		return xerrors.Errorf("allocating sector storage: %w", err)
+	}
+
+	var copied, declared []storiface.SectorFileType
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, ft := range declared {
+			if derr := m.index.StorageDropSector(ctx, stores.ID(storiface.PathByType(destIds, ft)), sector.ID, ft); derr != nil {
+				log.Errorw("undeclaring the files of a sector which failed to be imported", "sector", sector.ID, "type", ft, "error", derr)
+			}
+		}
+		for _, ft := range copied {
+			if rerr := os.RemoveAll(storiface.PathByType(dest, ft)); rerr != nil {
+				log.Errorw("removing the files of a sector which failed to be imported", "sector", sector.ID, "type", ft, "error", rerr)
+			}
+		}
+	}()
+
+	for _, ft := range []storiface.SectorFileType{storiface.FTSealed, storiface.FTCache} {
+		from := sealed
+		if ft == storiface.FTCache {
+			from = cache
+		}
+		to := storiface.PathByType(dest, ft)
+		if _, err := os.Stat(to); err == nil {
+			return xerrors.Errorf("%s already exists", to)
+		}
+		// a failed copy may leave a partial copy behind
+		copied = append(copied, ft)
+		if err := copyPath(from, to); err != nil {
+			return xerrors.Errorf("copying %s: %w", ft, err)
+		}
+		if err := m.index.StorageDeclareSector(ctx, stores.ID(storiface.PathByType(destIds, ft)), sector.ID, ft, true); err != nil {
+			return xerrors.Errorf("declaring %s: %w", ft, err)
+		}
+		declared = append(declared, ft)
+	}
+	return nil
+}
+
+// copyPath copies a file or directory, which mustn't exist at to yet
+func copyPath(from, to string) error {
+	if _, err := os.Stat(to); err == nil {
+		return xerrors.Errorf("%s already exists", to)
+	}
+
+	var errOut bytes.Buffer
+	cmd := exec.Command("/usr/bin/env", "cp", "-R", from, to) // nolint
+	cmd.Stderr = &errOut
+	if err := cmd.Run(); err != nil {
+		return xerrors.Errorf("exec cp (stderr: %s): %w", errOut.String(), err)
+	}
+	return nil
+}
@@ -39,6 +39,7 @@ var fsmPlanners = map[SectorState]func(events []statemachine.Event, state *Secto
 	UndefinedSectorState: planOne(
 		on(SectorStart{}, WaitDeals),
 		on(SectorStartCC{}, Packing),
+		on(SectorImported{}, Proving),
 	),
 	Empty: planOne( // deprecated
 		on(SectorAddPiece{}, AddPiece),
@@ -77,6 +77,15 @@ func (evt SectorStartCC) apply(state *SectorInfo) {
 	state.SectorType = evt.SectorType
 }
 
+// SectorImported adds a sector sealed by another instance of the miner
+type SectorImported struct {
+	Info SectorInfo
+}
+
+func (evt SectorImported) apply(state *SectorInfo) {
+	*state = evt.Info
+}
+
 type SectorAddPiece struct{}
 
 func (evt SectorAddPiece) apply(state *SectorInfo) {
@@ -228,6 +228,26 @@ func TestPlanCommittingHandlesSectorCommitFailed(t *testing.T) {
 	require.Equal(t, CommitFailed, m.state.State)
 }
 
+func TestImportedSector(t *testing.T) {
+	ma, _ := address.NewIDAddress(55151)
+	m := test{
+		s: &Sealing{
+			maddr: ma,
+			stats: SectorStats{
+				bySector: map[abi.SectorID]statSectorState{},
+			},
+			notifee: func(before, after SectorInfo) {},
+		},
+		t:     t,
+		state: &SectorInfo{},
+	}
+
+	m.planSingle(SectorImported{Info: SectorInfo{SectorNumber: 7, TicketEpoch: 10, SeedEpoch: 20}})
+	require.Equal(m.t, Proving, m.state.State)
+	require.Equal(m.t, abi.SectorNumber(7), m.state.SectorNumber)
+	require.Equal(m.t, abi.ChainEpoch(20), m.state.SeedEpoch)
+}
+
 func TestPlannerList(t *testing.T) {
 	for state := range ExistSectorStateList {
 		_, ok := fsmPlanners[state]
@@ -197,6 +197,36 @@ func (m *Sealing) Remove(ctx context.Context, sid abi.SectorNumber) error {
 	return m.sectors.Send(uint64(sid), SectorRemove{})
 }
 
+// ImportSector adds a sector exported from another instance of the miner,
+// whose files were already imported into the storage, as proving
+func (m *Sealing) ImportSector(ctx context.Context, si SectorInfo) error {
+	m.startupWait.Wait()
+
+	has, err := m.HasSector(si.SectorNumber)
+	if err != nil {
+		return err
+	}
+	if has {
+		return xerrors.Errorf("sector %d is already in the sealing store", si.SectorNumber)
+	}
+
+	// advance the counter past the imported number, so that it isn't handed
+	// out to a new sector
+	for {
+		n, err := m.sc.Next()
+		if err != nil {
+			return xerrors.Errorf("advancing the sector number counter: %w", err)
+		}
+		if n >= si.SectorNumber {
+			break
+		}
+	}
+
+	si.State = UndefinedSectorState
+	si.Log = nil
+	return m.sectors.Send(uint64(si.SectorNumber), SectorImported{Info: si})
+}
+
 func (m *Sealing) Terminate(ctx context.Context, sid abi.SectorNumber) error {
 	m.startupWait.Wait()
 
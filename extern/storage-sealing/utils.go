@@ -55,3 +55,7 @@ func (m *Sealing) GetSectorInfo(sid abi.SectorNumber) (SectorInfo, error) {
 	err := m.sectors.Get(uint64(sid)).Get(&out)
 	return out, err
 }
+
+func (m *Sealing) HasSector(sid abi.SectorNumber) (bool, error) {
+	return m.sectors.Has(uint64(sid))
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 	"unicode"
@@ -23,21 +24,45 @@ type methodMeta struct {
 type Visitor struct {
 	Methods map[string]map[string]*methodMeta
 	Include map[string][]string
+
+	// Stability is the default stability of methods in an interface, set
+	// with a //stability: directive in the interface doc comment
+	Stability map[string]string
 }
 
 func (v *Visitor) Visit(node ast.Node) ast.Visitor {
-	st, ok := node.(*ast.TypeSpec)
-	if !ok {
+	gd, ok := node.(*ast.GenDecl)
+	if !ok || gd.Tok != token.TYPE {
 		return v
 	}
 
+	for _, spec := range gd.Specs {
+		st := spec.(*ast.TypeSpec)
+		doc := st.Doc
+		if doc == nil {
+			doc = gd.Doc
+		}
+		v.visitType(st, doc)
+	}
+
+	return nil
+}
+
+func (v *Visitor) visitType(st *ast.TypeSpec, doc *ast.CommentGroup) {
 	iface, ok := st.Type.(*ast.InterfaceType)
 	if !ok {
-		return v
+		return
 	}
 	if v.Methods[st.Name.Name] == nil {
 		v.Methods[st.Name.Name] = map[string]*methodMeta{}
 	}
+	if doc != nil {
+		for _, c := range doc.List {
+			if strings.HasPrefix(c.Text, "//stability:") {
+				v.Stability[st.Name.Name] = strings.TrimPrefix(c.Text, "//stability:")
+			}
+		}
+	}
 	for _, m := range iface.Methods.List {
 		switch ft := m.Type.(type) {
 		case *ast.Ident:
@@ -49,22 +74,60 @@ func (v *Visitor) Visit(node ast.Node) ast.Visitor {
 			}
 		}
 	}
-
-	return v
 }
 
 func main() {
 	// latest (v1)
 	if err := generate("./api", "api", "api", "./api/proxy_gen.go"); err != nil {
 		fmt.Println("error: ", err)
+		os.Exit(1)
 	}
 
 	// v0
 	if err := generate("./api/v0api", "v0api", "v0api", "./api/v0api/proxy_gen.go"); err != nil {
 		fmt.Println("error: ", err)
+		os.Exit(1)
 	}
 }
 
+// method annotations which become struct tags on the proxy structs
+var methodTags = map[string]bool{
+	"perm":        true,
+	"stability":   true,
+	"removal":     true,
+	"replacement": true,
+}
+
+var stabilityLevels = map[string]bool{
+	"stable":       true,
+	"experimental": true,
+	"deprecated":   true,
+}
+
+// checkStability makes sure that every method is classified, falling back to
+// the default of the interface declaring it
+func checkStability(mi map[string][]string, def string) error {
+	if _, ok := mi["stability"]; !ok {
+		if def == "" {
+			return xerrors.Errorf("no stability classification; annotate the method with stability:<level> or the interface with //stability:<level>")
+		}
+		mi["stability"] = []string{"stability", def}
+	}
+
+	level := mi["stability"][1]
+	if !stabilityLevels[level] {
+		return xerrors.Errorf("unknown stability level '%s'", level)
+	}
+	if level != "deprecated" {
+		for _, tag := range []string{"removal", "replacement"} {
+			if _, ok := mi[tag]; ok {
+				return xerrors.Errorf("%s annotation on a method which isn't deprecated", tag)
+			}
+		}
+	}
+	return nil
+}
+
 func typeName(e ast.Expr, pkg string) (string, error) {
 	switch t := e.(type) {
 	case *ast.SelectorExpr:
@@ -133,20 +196,25 @@ func generate(path, pkg, outpkg, outfile string) error {
 	if err != nil {
 		return err
 	}
-	pkgs, err := parser.ParseDir(fset, apiDir, nil, parser.AllErrors|parser.ParseComments)
+	// interfaces declared by tests don't get proxies
+	notTest := func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}
+	pkgs, err := parser.ParseDir(fset, apiDir, notTest, parser.AllErrors|parser.ParseComments)
 	if err != nil {
 		return err
 	}
 
 	ap := pkgs[pkg]
 
-	v := &Visitor{make(map[string]map[string]*methodMeta), map[string][]string{}}
+	v := &Visitor{make(map[string]map[string]*methodMeta), map[string][]string{}, map[string]string{}}
 	ast.Walk(v, ap)
 
 	type methodInfo struct {
 		Name                                     string
 		node                                     ast.Node
 		Tags                                     map[string][]string
+		StructTags                               string
 		NamedParams, ParamNames, Results, DefRes string
 	}
 
@@ -263,7 +331,7 @@ func generate(path, pkg, outpkg, outfile string) error {
 						if len(tf) != 2 {
 							continue
 						}
-						if tf[0] != "perm" { // todo: allow more tag types
+						if !methodTags[tf[0]] {
 							continue
 						}
 						info.Methods[mname].Tags[tf[0]] = tf
@@ -273,6 +341,24 @@ func generate(path, pkg, outpkg, outfile string) error {
 		}
 	}
 
+	for ifname, info := range m.Infos {
+		for mname, mi := range info.Methods {
+			if err := checkStability(mi.Tags, v.Stability[ifname]); err != nil {
+				return xerrors.Errorf("method %s.%s: %w", ifname, mname, err)
+			}
+
+			tags := make([]string, 0, len(mi.Tags))
+			for tag := range mi.Tags {
+				tags = append(tags, tag)
+			}
+			sort.Strings(tags)
+			for i, tag := range tags {
+				tags[i] = fmt.Sprintf("%s:%q", tag, mi.Tags[tag][1])
+			}
+			mi.StructTags = strings.Join(tags, " ")
+		}
+	}
+
 	/*jb, err := json.MarshalIndent(Infos, "", "  ")
 	if err != nil {
 		return err
@@ -305,7 +391,7 @@ type {{.Name}}Struct struct {
 {{end}}
 	Internal struct {
 {{range .Methods}}
-		{{.Name}} func({{.NamedParams}}) ({{.Results}}) `+"`"+`{{range .Tags}}{{index . 0}}:"{{index . 1}}"{{end}}`+"`"+`
+		{{.Name}} func({{.NamedParams}}) ({{.Results}}) `+"`"+`{{.StructTags}}`+"`"+`
 {{end}}
 	}
 }
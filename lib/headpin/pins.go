@@ -0,0 +1,197 @@
+package headpin
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	logging "github.com/ipfs/go-log/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+var log = logging.Logger("headpin")
+
+const (
+	// MaxPins bounds the number of sessions pinning a head at once
+	MaxPins = 64
+	// MaxTTL bounds the lifetime of sessions
+	MaxTTL = 10 * time.Minute
+	// DefaultTTL is the lifetime of sessions pinned without one
+	DefaultTTL = time.Minute
+)
+
+// ErrSessionEnded is returned by the calls made in a session which expired or
+// was unpinned, including the calls in progress when it ended
+var ErrSessionEnded = xerrors.New("pinned head session ended")
+
+type pin struct {
+	ts      *types.TipSet
+	expires time.Time
+	timer   *time.Timer
+	// ended is closed when the session expires or is unpinned
+	ended chan struct{}
+}
+
+// Pins keeps the heads pinned by the sessions of ChainPinHead
+type Pins struct {
+	lk   sync.Mutex
+	pins map[string]*pin
+}
+
+func NewPins() *Pins {
+	return &Pins{pins: map[string]*pin{}}
+}
+
+// Pin pins the tipset for a new session lasting ttl, DefaultTTL if it's 0
+func (p *Pins) Pin(ts *types.TipSet, ttl time.Duration) (api.PinnedHead, error) {
+	switch {
+	case ttl < 0:
+		return api.PinnedHead{}, xerrors.Errorf("negative ttl %s", ttl)
+	case ttl == 0:
+		ttl = DefaultTTL
+	case ttl > MaxTTL:
+		return api.PinnedHead{}, xerrors.Errorf("ttl %s exceeds the maximum of %s", ttl, MaxTTL)
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return api.PinnedHead{}, err
+	}
+	id := hex.EncodeToString(b)
+
+	p.lk.Lock()
+	defer p.lk.Unlock()
+
+	if len(p.pins) >= MaxPins {
+		return api.PinnedHead{}, xerrors.Errorf("too many pinned heads (%d), unpin some or wait for them to expire", len(p.pins))
+	}
+
+	pn := &pin{
+		ts:      ts,
+		expires: time.Now().Add(ttl),
+		ended:   make(chan struct{}),
+	}
+	pn.timer = time.AfterFunc(ttl, func() {
+		if p.remove(id) {
+			log.Debugw("pinned head session expired", "session", id, "tipset", ts.Key())
+		}
+	})
+	p.pins[id] = pn
+
+	return api.PinnedHead{
+		Session: id,
+		TipSet:  ts.Key(),
+		Height:  ts.Height(),
+		Expires: pn.expires,
+	}, nil
+}
+
+// Unpin ends the session
+func (p *Pins) Unpin(id string) error {
+	if !p.remove(id) {
+		return xerrors.Errorf("%w: session %s expired or was unpinned", ErrSessionEnded, id)
+	}
+	return nil
+}
+
+func (p *Pins) remove(id string) bool {
+	p.lk.Lock()
+	defer p.lk.Unlock()
+
+	pn, ok := p.pins[id]
+	if !ok {
+		return false
+	}
+	delete(p.pins, id)
+	pn.timer.Stop()
+	close(pn.ended)
+	return true
+}
+
+// Use returns the tipset pinned by the session, with a context of ctx which
+// is canceled when the session ends. The call made with the context must be
+// followed by done, which returns an ErrSessionEnded error if the session
+// ended during the call.
+func (p *Pins) Use(ctx context.Context, id string) (ts *types.TipSet, sctx context.Context, done func() error, err error) {
+	p.lk.Lock()
+	pn, ok := p.pins[id]
+	p.lk.Unlock()
+	if !ok {
+		return nil, nil, nil, xerrors.Errorf("%w: session %s expired or was unpinned", ErrSessionEnded, id)
+	}
+
+	sctx, cancel := context.WithCancel(ctx)
+	stop := make(chan struct{})
+	exited := make(chan bool, 1)
+	go func() {
+		select {
+		case <-pn.ended:
+			cancel()
+			exited <- true
+		case <-stop:
+			exited <- false
+		}
+	}()
+
+	return pn.ts, sctx, func() error {
+		close(stop)
+		ended := <-exited
+		cancel()
+		if ended {
+			return xerrors.Errorf("%w: session %s expired or was unpinned during the call", ErrSessionEnded, id)
+		}
+		return nil
+	}, nil
+}
+
+// TipSets returns the pinned tipsets, for the splitstore to keep
+func (p *Pins) TipSets() []*types.TipSet {
+	p.lk.Lock()
+	defer p.lk.Unlock()
+
+	out := make([]*types.TipSet, 0, len(p.pins))
+	for _, pn := range p.pins {
+		out = append(out, pn.ts)
+	}
+	return out
+}
+
+// Close ends all sessions
+func (p *Pins) Close() error {
+	p.lk.Lock()
+	ids := make([]string, 0, len(p.pins))
+	for id := range p.pins {
+		ids = append(ids, id)
+	}
+	p.lk.Unlock()
+
+	for _, id := range ids {
+		p.remove(id)
+	}
+	return nil
+}
+
+type sessionKey struct{}
+
+// Handler passes the session of the PinnedHeadHeader of requests on in their
+// context, for PinnedFullAPI
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s := r.Header.Get(api.PinnedHeadHeader); s != "" {
+			r = r.WithContext(context.WithValue(r.Context(), sessionKey{}, s))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Session returns the pinned head session of the call, if it was made in one
+func Session(ctx context.Context) (string, bool) {
+	s, ok := ctx.Value(sessionKey{}).(string)
+	return s, ok
+}
@@ -0,0 +1,122 @@
+package headpin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/chain/types/mock"
+)
+
+type fakeNode struct {
+	api.FullNodeStub
+	head  *types.TipSet
+	block bool
+}
+
+func (f *fakeNode) ChainHead(ctx context.Context) (*types.TipSet, error) {
+	return f.head, nil
+}
+
+func (f *fakeNode) StateLookupID(ctx context.Context, addr address.Address, tsk types.TipSetKey) (address.Address, error) {
+	if f.block {
+		// wait for the session to end
+		<-ctx.Done()
+		return address.Undef, ctx.Err()
+	}
+	if tsk.IsEmpty() {
+		tsk = f.head.Key()
+	}
+	return address.NewIDAddress(uint64(len(tsk.Cids())))
+}
+
+// sessionCtx returns the context of a call made in the session
+func sessionCtx(session string) context.Context {
+	var ctx context.Context
+	h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	}))
+	req := httptest.NewRequest("POST", "/rpc/v1", nil)
+	req.Header.Set(api.PinnedHeadHeader, session)
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	return ctx
+}
+
+func TestPinnedHead(t *testing.T) {
+	pinned := mock.TipSet(mock.MkBlock(nil, 1, 1))
+	b1, b2 := mock.MkBlock(pinned, 1, 2), mock.MkBlock(pinned, 1, 3)
+	b2.Miner, _ = address.NewIDAddress(1001)
+	next, err := types.NewTipSet([]*types.BlockHeader{b1, b2})
+	require.NoError(t, err)
+
+	node := &fakeNode{head: pinned}
+	p := NewPins()
+	a := PinnedFullAPI(node, p)
+
+	_, err = p.Pin(pinned, MaxTTL+time.Second)
+	require.Error(t, err)
+	ph, err := p.Pin(pinned, 0)
+	require.NoError(t, err)
+	require.Equal(t, pinned.Key(), ph.TipSet)
+	require.Equal(t, []*types.TipSet{pinned}, p.TipSets())
+
+	// the head changes, reads in the session still see the pinned one
+	node.head = next
+	ctx := sessionCtx(ph.Session)
+
+	head, err := a.ChainHead(ctx)
+	require.NoError(t, err)
+	require.Equal(t, pinned, head)
+	head, err = a.ChainHead(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, next, head)
+
+	id1, err := address.NewIDAddress(1)
+	require.NoError(t, err)
+	id2, err := address.NewIDAddress(2)
+	require.NoError(t, err)
+
+	id, err := a.StateLookupID(ctx, address.Undef, types.EmptyTSK)
+	require.NoError(t, err)
+	require.Equal(t, id1, id)
+	id, err = a.StateLookupID(context.Background(), address.Undef, types.EmptyTSK)
+	require.NoError(t, err)
+	require.Equal(t, id2, id)
+	id, err = a.StateLookupID(ctx, address.Undef, next.Key())
+	require.NoError(t, err)
+	require.Equal(t, id2, id)
+
+	// calls in ended sessions fail
+	require.NoError(t, p.Unpin(ph.Session))
+	_, err = a.ChainHead(ctx)
+	require.True(t, xerrors.Is(err, ErrSessionEnded))
+	require.True(t, xerrors.Is(p.Unpin(ph.Session), ErrSessionEnded))
+	require.Empty(t, p.TipSets())
+
+	// and so do the calls in progress as the session expires
+	ph, err = p.Pin(pinned, 50*time.Millisecond)
+	require.NoError(t, err)
+	node.block = true
+	_, err = a.StateLookupID(sessionCtx(ph.Session), address.Undef, types.EmptyTSK)
+	require.True(t, xerrors.Is(err, ErrSessionEnded))
+	require.Contains(t, err.Error(), "expired or was unpinned during the call")
+
+	// the number of sessions is bounded
+	for i := 0; i < MaxPins; i++ {
+		_, err = p.Pin(pinned, time.Minute)
+		require.NoError(t, err)
+	}
+	_, err = p.Pin(pinned, time.Minute)
+	require.Error(t, err)
+	require.NoError(t, p.Close())
+	require.Empty(t, p.TipSets())
+}
@@ -0,0 +1,81 @@
+package headpin
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+var tskType = reflect.TypeOf(types.TipSetKey{})
+
+// PinnedFullAPI resolves the current head of the calls made in a session of
+// the pins to the pinned tipset: ChainHead returns it, and empty tipset keys
+// are replaced by its key. Calls in sessions which ended fail with
+// ErrSessionEnded.
+func PinnedFullAPI(a api.FullNode, p *Pins) api.FullNode {
+	var out api.FullNodeStruct
+	proxy(a, p, &out.Internal)
+	proxy(a, p, &out.CommonStruct.Internal)
+	return &out
+}
+
+func proxy(a api.FullNode, p *Pins, out interface{}) {
+	rint := reflect.ValueOf(out).Elem()
+	ra := reflect.ValueOf(a)
+
+	for f := 0; f < rint.NumField(); f++ {
+		field := rint.Type().Field(f)
+		fn := ra.MethodByName(field.Name)
+		ft := field.Type
+		if ft.NumOut() == 2 && ft.Out(0).Kind() == reflect.Chan {
+			// subscriptions outlive the calls, and follow the chain anyway
+			rint.Field(f).Set(fn)
+			continue
+		}
+
+		rint.Field(f).Set(reflect.MakeFunc(ft, func(args []reflect.Value) (results []reflect.Value) {
+			ctx := args[0].Interface().(context.Context)
+			id, ok := Session(ctx)
+			if !ok {
+				return fn.Call(args)
+			}
+
+			ts, ctx, done, err := p.Use(ctx, id)
+			if err != nil {
+				return errResults(ft, err)
+			}
+			if field.Name == "ChainHead" {
+				if err := done(); err != nil {
+					return errResults(ft, err)
+				}
+				return []reflect.Value{reflect.ValueOf(ts), reflect.Zero(ft.Out(1))}
+			}
+
+			args[0] = reflect.ValueOf(ctx)
+			for i := 1; i < len(args); i++ {
+				if args[i].Type() == tskType && args[i].Interface().(types.TipSetKey).IsEmpty() {
+					args[i] = reflect.ValueOf(ts.Key())
+				}
+			}
+
+			results = fn.Call(args)
+			if err := done(); err != nil {
+				return errResults(ft, err)
+			}
+			return results
+		}))
+	}
+}
+
+// errResults returns the results of a call of a function of type ft failing
+// with err
+func errResults(ft reflect.Type, err error) []reflect.Value {
+	out := make([]reflect.Value, ft.NumOut())
+	for i := range out {
+		out[i] = reflect.Zero(ft.Out(i))
+	}
+	out[len(out)-1] = reflect.ValueOf(&err).Elem()
+	return out
+}
@@ -0,0 +1,153 @@
+// Package loglevels sets the levels of the logging subsystems at runtime,
+// keeping temporary overrides which revert on their own.
+package loglevels
+
+import (
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	logging "github.com/ipfs/go-log/v2"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/api"
+)
+
+// MaxOverride bounds the duration of temporary overrides
+const MaxOverride = 24 * time.Hour
+
+type override struct {
+	baseline string
+	expires  time.Time
+	timer    *time.Timer
+}
+
+// Levels sets the levels of the logging subsystems, reverting the temporary
+// overrides as they expire
+type Levels struct {
+	lk        sync.Mutex
+	overrides map[string]*override
+}
+
+func New() *Levels {
+	return &Levels{overrides: map[string]*override{}}
+}
+
+// Level returns the current level of the subsystem, the lowest its logger is
+// enabled at
+func Level(system string) string {
+	core := logging.Logger(system).Desugar().Core()
+	for l := zapcore.DebugLevel; l <= zapcore.FatalLevel; l++ {
+		if core.Enabled(l) {
+			return l.String()
+		}
+	}
+	return zapcore.FatalLevel.String()
+}
+
+// List returns the subsystems, sorted by name, with their level and override
+func (l *Levels) List() []api.LogSystem {
+	l.lk.Lock()
+	defer l.lk.Unlock()
+
+	systems := logging.GetSubsystems()
+	sort.Strings(systems)
+
+	out := make([]api.LogSystem, 0, len(systems))
+	for _, s := range systems {
+		ls := api.LogSystem{Name: s, Level: Level(s)}
+		if o, ok := l.overrides[s]; ok {
+			ls.Override = &api.LogOverride{Baseline: o.baseline, Expires: o.expires}
+		}
+		out = append(out, ls)
+	}
+	return out
+}
+
+// Set sets the level of the subsystems whose name matches the expression in
+// full, returning their names, sorted. The level is a temporary override when
+// d isn't 0.
+func (l *Levels) Set(match, level string, d time.Duration) ([]string, error) {
+	if d < 0 || d > MaxOverride {
+		return nil, xerrors.Errorf("override duration %s must be between 0 and %s", d, MaxOverride)
+	}
+	if _, err := logging.LevelFromString(level); err != nil {
+		return nil, xerrors.Errorf("invalid level %q: %w", level, err)
+	}
+	re, err := regexp.Compile("^(?:" + match + ")$")
+	if err != nil {
+		return nil, xerrors.Errorf("invalid subsystem expression: %w", err)
+	}
+
+	var systems []string
+	for _, s := range logging.GetSubsystems() {
+		if re.MatchString(s) {
+			systems = append(systems, s)
+		}
+	}
+	if len(systems) == 0 {
+		return nil, xerrors.Errorf("no logging subsystem matches %q", match)
+	}
+	sort.Strings(systems)
+
+	l.lk.Lock()
+	defer l.lk.Unlock()
+
+	for _, s := range systems {
+		if err := l.set(s, level, d); err != nil {
+			return nil, err
+		}
+	}
+	return systems, nil
+}
+
+func (l *Levels) set(system, level string, d time.Duration) error {
+	o, overridden := l.overrides[system]
+	if d == 0 {
+		if overridden {
+			o.timer.Stop()
+			delete(l.overrides, system)
+		}
+		return logging.SetLogLevel(system, level)
+	}
+
+	// overriding again keeps the level from before the first override
+	if !overridden {
+		o = &override{baseline: Level(system)}
+		l.overrides[system] = o
+	} else {
+		o.timer.Stop()
+	}
+	o.expires = time.Now().Add(d)
+	o.timer = time.AfterFunc(d, func() {
+		l.revert(system, o)
+	})
+	return logging.SetLogLevel(system, level)
+}
+
+// revert reverts the override of the subsystem, unless it was replaced
+func (l *Levels) revert(system string, o *override) {
+	l.lk.Lock()
+	defer l.lk.Unlock()
+
+	if l.overrides[system] != o || time.Now().Before(o.expires) {
+		return
+	}
+	delete(l.overrides, system)
+	_ = logging.SetLogLevel(system, o.baseline)
+}
+
+// Close reverts the overrides in effect
+func (l *Levels) Close() error {
+	l.lk.Lock()
+	defer l.lk.Unlock()
+
+	for s, o := range l.overrides {
+		o.timer.Stop()
+		delete(l.overrides, s)
+		_ = logging.SetLogLevel(s, o.baseline)
+	}
+	return nil
+}
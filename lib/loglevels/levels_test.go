@@ -0,0 +1,65 @@
+package loglevels
+
+import (
+	"testing"
+	"time"
+
+	logging "github.com/ipfs/go-log/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverrides(t *testing.T) {
+	logging.Logger("lltest-a")
+	logging.Logger("lltest-b")
+	require.NoError(t, logging.SetLogLevel("lltest-a", "info"))
+	require.NoError(t, logging.SetLogLevel("lltest-b", "error"))
+
+	l := New()
+	override := func(name string) string {
+		for _, s := range l.List() {
+			if s.Name == name && s.Override != nil {
+				return s.Override.Baseline
+			}
+		}
+		return ""
+	}
+
+	_, err := l.Set("lltest-.*", "loud", time.Minute)
+	require.Error(t, err)
+	_, err = l.Set("nothing-matches", "debug", 0)
+	require.Error(t, err)
+
+	set, err := l.Set("lltest-.*", "debug", 50*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, []string{"lltest-a", "lltest-b"}, set)
+	require.Equal(t, "debug", Level("lltest-a"))
+	require.Equal(t, "info", override("lltest-a"))
+	require.Equal(t, "error", override("lltest-b"))
+
+	// the last override wins, and reverts to the level from before the first
+	_, err = l.Set("lltest-a", "warn", 200*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, "warn", Level("lltest-a"))
+	require.Equal(t, "info", override("lltest-a"))
+
+	// setting a level for good ends the override
+	_, err = l.Set("lltest-b", "warn", 0)
+	require.NoError(t, err)
+	require.Equal(t, "", override("lltest-b"))
+
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, "warn", Level("lltest-a"))
+	require.Equal(t, "warn", Level("lltest-b"))
+
+	require.Eventually(t, func() bool {
+		return Level("lltest-a") == "info"
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, "", override("lltest-a"))
+	require.Equal(t, "warn", Level("lltest-b"))
+
+	// closing reverts the overrides in effect
+	_, err = l.Set("lltest-b", "debug", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, l.Close())
+	require.Equal(t, "warn", Level("lltest-b"))
+}
@@ -0,0 +1,246 @@
+package proofparams
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	fslock "github.com/ipfs/go-fs-lock"
+	logging "github.com/ipfs/go-log/v2"
+	"go.uber.org/multierr"
+	"golang.org/x/xerrors"
+)
+
+var log = logging.Logger("proofparams")
+
+const lockRetry = 10 * time.Second
+
+// Transfer is the progress of the download of a file
+type Transfer struct {
+	Name    string
+	Gateway string
+	// Done counts the bytes in the file, including the ones downloaded
+	// before a resumed transfer
+	Done int64
+	// Total is -1 until the gateway answers
+	Total int64
+}
+
+// Fetcher downloads parameter files, several at a time. Transfers resume from
+// partial files, and move on to the next gateway when one fails.
+type Fetcher struct {
+	Dir      string
+	Gateways []string
+	Parallel int
+
+	lk        sync.Mutex
+	transfers map[string]*transfer
+}
+
+type transfer struct {
+	gateway     string
+	done, total int64
+}
+
+// Progress returns the transfers in progress
+func (f *Fetcher) Progress() []Transfer {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+
+	var out []Transfer
+	for name, t := range f.transfers {
+		out = append(out, Transfer{
+			Name:    name,
+			Gateway: t.gateway,
+			Done:    atomic.LoadInt64(&t.done),
+			Total:   atomic.LoadInt64(&t.total),
+		})
+	}
+	return out
+}
+
+// Fetch downloads the files, verifying them. Files already in the directory
+// are expected to have been checked, partial files are resumed.
+func (f *Fetcher) Fetch(ctx context.Context, files []File) error {
+	if len(f.Gateways) == 0 {
+		return xerrors.Errorf("no gateways to fetch from")
+	}
+	if err := os.MkdirAll(f.Dir, 0755); err != nil {
+		return err
+	}
+
+	unlock, err := f.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock.Close() //nolint:errcheck
+
+	parallel := f.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	var (
+		wg       sync.WaitGroup
+		errLk    sync.Mutex
+		errs     error
+		throttle = make(chan struct{}, parallel)
+	)
+	for _, file := range files {
+		select {
+		case throttle <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		}
+
+		wg.Add(1)
+		go func(file File) {
+			defer wg.Done()
+			defer func() { <-throttle }()
+
+			if err := f.fetchFile(ctx, file); err != nil {
+				errLk.Lock()
+				errs = multierr.Append(errs, xerrors.Errorf("fetching %s: %w", file.Name, err))
+				errLk.Unlock()
+			}
+		}(file)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+func (f *Fetcher) lock(ctx context.Context) (io.Closer, error) {
+	for {
+		unlock, err := fslock.Lock(f.Dir, lockFile)
+		if err == nil {
+			return unlock, nil
+		}
+
+		le := fslock.LockedError("")
+		if !xerrors.As(err, &le) {
+			return nil, xerrors.Errorf("acquiring fetch lock: %w", err)
+		}
+
+		log.Warnf("acquiring fetch lock: %s; will retry in %s", err, lockRetry)
+		select {
+		case <-time.After(lockRetry):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (f *Fetcher) fetchFile(ctx context.Context, file File) error {
+	path := filepath.Join(f.Dir, file.Name)
+
+	t := &transfer{total: -1}
+	f.lk.Lock()
+	if f.transfers == nil {
+		f.transfers = map[string]*transfer{}
+	}
+	f.transfers[file.Name] = t
+	f.lk.Unlock()
+
+	defer func() {
+		f.lk.Lock()
+		delete(f.transfers, file.Name)
+		f.lk.Unlock()
+	}()
+
+	var errs error
+	for _, gw := range f.Gateways {
+		f.lk.Lock()
+		t.gateway = gw
+		f.lk.Unlock()
+
+		err := download(ctx, gw+file.Cid, path, t)
+		if err == nil {
+			err = verifyFile(path, file)
+			if err != nil {
+				// don't resume from corrupt data
+				_ = os.Remove(path)
+			}
+		}
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		log.Warnw("fetching parameter file failed, trying the next gateway", "file", file.Name, "gateway", gw, "error", err)
+		errs = multierr.Append(errs, xerrors.Errorf("gateway %s: %w", gw, err))
+	}
+	return errs
+}
+
+// download appends to the partial file from its current size
+func download(ctx context.Context, url, path string, t *transfer) error {
+	out, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close() //nolint:errcheck
+
+	st, err := out.Stat()
+	if err != nil {
+		return err
+	}
+	offset := st.Size()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		// the gateway ignored the range, start over
+		if err := out.Truncate(0); err != nil {
+			return err
+		}
+		offset = 0
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the partial file is complete
+		atomic.StoreInt64(&t.done, offset)
+		atomic.StoreInt64(&t.total, offset)
+		return nil
+	default:
+		return xerrors.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	atomic.StoreInt64(&t.done, offset)
+	if resp.ContentLength >= 0 {
+		atomic.StoreInt64(&t.total, offset+resp.ContentLength)
+	}
+
+	_, err = io.Copy(out, &countingReader{r: resp.Body, n: &t.done})
+	return err
+}
+
+type countingReader struct {
+	r io.Reader
+	n *int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	atomic.AddInt64(c.n, int64(n))
+	return n, err
+}
@@ -0,0 +1,214 @@
+// Package proofparams manages the proof parameter cache: it checks the files
+// against the manifest embedded in the binary, fetches the missing ones and
+// seeds them from local copies.
+package proofparams
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/minio/blake2b-simd"
+	"golang.org/x/xerrors"
+)
+
+// DefaultGateway is the gateway paramfetch downloads parameters from
+const DefaultGateway = "https://proofs.filecoin.io/ipfs/"
+
+const (
+	defaultDir = "/var/tmp/filecoin-proof-parameters"
+	dirEnv     = "FIL_PROOFS_PARAMETER_CACHE"
+	gatewayEnv = "IPFS_GATEWAY"
+
+	// lockFile is shared with paramfetch, so fetches from nodes starting at
+	// the same time don't step on each other
+	lockFile = "fetch.lock"
+)
+
+// Dir is the parameter cache directory
+func Dir() string {
+	if d := os.Getenv(dirEnv); d != "" {
+		return d
+	}
+	return defaultDir
+}
+
+// Gateways returns the gateway set in the environment, or the default one
+func Gateways() []string {
+	if gw := os.Getenv(gatewayEnv); gw != "" {
+		return []string{gw}
+	}
+	return []string{DefaultGateway}
+}
+
+type File struct {
+	Name       string
+	Cid        string `json:"cid"`
+	Digest     string `json:"digest"`
+	SectorSize uint64 `json:"sector_size"`
+}
+
+// Manifest lists the files needed for the sector size, like
+// paramfetch.GetParams does: the .params files of the sector size, and all
+// the verifying keys and SRS files. A zero sector size skips all the .params
+// files, which only sealing and proving need.
+func Manifest(paramsJSON, srsJSON []byte, sectorSize uint64) ([]File, error) {
+	var out []File
+	for _, b := range [][]byte{paramsJSON, srsJSON} {
+		var files map[string]File
+		if err := json.Unmarshal(b, &files); err != nil {
+			return nil, xerrors.Errorf("parsing manifest: %w", err)
+		}
+
+		for name, f := range files {
+			if strings.HasSuffix(name, ".params") && f.SectorSize != sectorSize {
+				continue
+			}
+			f.Name = name
+			out = append(out, f)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+	return out, nil
+}
+
+type State string
+
+const (
+	Missing State = "missing"
+	// Present files exist but weren't verified
+	Present  State = "present"
+	Verified State = "verified"
+	Corrupt  State = "corrupt"
+)
+
+type FileStatus struct {
+	Name       string
+	SectorSize uint64
+	State      State
+	Size       int64
+	Error      string `json:",omitempty"`
+}
+
+// Check returns the state of the files in the directory. Verifying hashes
+// every file, which takes minutes for the larger ones.
+func Check(ctx context.Context, dir string, files []File, verify bool) []FileStatus {
+	out := make([]FileStatus, len(files))
+
+	throttle := make(chan struct{}, 4)
+	var wg sync.WaitGroup
+	for i, f := range files {
+		out[i] = FileStatus{Name: f.Name, SectorSize: f.SectorSize, State: Missing}
+
+		st, err := os.Stat(filepath.Join(dir, f.Name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			out[i].State = Corrupt
+			out[i].Error = err.Error()
+			continue
+		}
+		out[i].Size = st.Size()
+		out[i].State = Present
+
+		if !verify {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, f File) {
+			defer wg.Done()
+
+			select {
+			case throttle <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-throttle }()
+
+			if err := verifyFile(filepath.Join(dir, f.Name), f); err != nil {
+				out[i].State = Corrupt
+				out[i].Error = err.Error()
+				return
+			}
+			out[i].State = Verified
+		}(i, f)
+	}
+	wg.Wait()
+
+	return out
+}
+
+func verifyFile(path string, f File) error {
+	fh, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer fh.Close() //nolint:errcheck
+
+	h := blake2b.New512()
+	if _, err := io.Copy(h, fh); err != nil {
+		return xerrors.Errorf("reading %s: %w", path, err)
+	}
+
+	// the manifest digests are truncated to 16 bytes
+	sum := hex.EncodeToString(h.Sum(nil)[:16])
+	if sum != f.Digest {
+		return xerrors.Errorf("checksum mismatch in %s, %s != %s", path, sum, f.Digest)
+	}
+	return nil
+}
+
+// Preseed puts the file in the directory from a copy in another directory,
+// e.g. a cache on a network share. The copy is verified first, then
+// hardlinked, or copied when linking isn't possible.
+func Preseed(from, dir string, f File) error {
+	src := filepath.Join(from, f.Name)
+	if err := verifyFile(src, f); err != nil {
+		return xerrors.Errorf("verifying seed: %w", err)
+	}
+
+	dst := filepath.Join(dir, f.Name)
+	if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return xerrors.Errorf("removing %s: %w", dst, err)
+	}
+
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+
+	tmp := dst + ".preseed"
+	if err := copyFile(src, tmp); err != nil {
+		_ = os.Remove(tmp)
+		return xerrors.Errorf("copying %s: %w", src, err)
+	}
+	return os.Rename(tmp, dst)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close() //nolint:errcheck
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
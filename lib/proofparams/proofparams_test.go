@@ -0,0 +1,126 @@
+package proofparams
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/minio/blake2b-simd"
+	"github.com/stretchr/testify/require"
+)
+
+func digest(b []byte) string {
+	sum := blake2b.Sum512(b)
+	return hex.EncodeToString(sum[:16])
+}
+
+func TestManifest(t *testing.T) {
+	params := []byte(`{
+		"a-2k.params": {"cid": "Qa", "digest": "da", "sector_size": 2048},
+		"a-2k.vk": {"cid": "Qb", "digest": "db", "sector_size": 2048},
+		"a-8m.params": {"cid": "Qc", "digest": "dc", "sector_size": 8388608}
+	}`)
+	srs := []byte(`{"srs.bin": {"cid": "Qd", "digest": "dd", "sector_size": 0}}`)
+
+	files, err := Manifest(params, srs, 2048)
+	require.NoError(t, err)
+	require.Equal(t, []File{
+		{Name: "a-2k.params", Cid: "Qa", Digest: "da", SectorSize: 2048},
+		{Name: "a-2k.vk", Cid: "Qb", Digest: "db", SectorSize: 2048},
+		{Name: "srs.bin", Cid: "Qd", Digest: "dd"},
+	}, files)
+
+	files, err = Manifest(params, srs, 0)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+}
+
+func TestCheckAndPreseed(t *testing.T) {
+	ctx := context.Background()
+	dir, seed := t.TempDir(), t.TempDir()
+
+	content := []byte("parameters")
+	files := []File{
+		{Name: "good", Digest: digest(content)},
+		{Name: "bad", Digest: digest(content)},
+		{Name: "missing", Digest: digest(content)},
+	}
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "good"), content, 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "bad"), []byte("param"), 0644))
+
+	states := func(verify bool) []State {
+		var out []State
+		for _, st := range Check(ctx, dir, files, verify) {
+			out = append(out, st.State)
+		}
+		return out
+	}
+	require.Equal(t, []State{Present, Present, Missing}, states(false))
+	require.Equal(t, []State{Verified, Corrupt, Missing}, states(true))
+
+	// corrupt seeds are refused
+	require.NoError(t, ioutil.WriteFile(filepath.Join(seed, "bad"), []byte("other"), 0644))
+	require.Error(t, Preseed(seed, dir, files[1]))
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(seed, "bad"), content, 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(seed, "missing"), content, 0644))
+	require.NoError(t, Preseed(seed, dir, files[1]))
+	require.NoError(t, Preseed(seed, dir, files[2]))
+	require.Equal(t, []State{Verified, Verified, Verified}, states(true))
+}
+
+func TestFetch(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	files := []File{
+		{Name: "a", Cid: "Qa", Digest: digest(content)},
+		{Name: "b", Cid: "Qb", Digest: digest(content)},
+	}
+
+	var ranged int64
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/ipfs/Q") {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Range") != "bytes=0-" {
+			atomic.AddInt64(&ranged, 1)
+		}
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+	}))
+	defer mirror.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	defer down.Close()
+
+	// partial download of a to resume
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a"), content[:4000], 0644))
+
+	f := &Fetcher{
+		Dir:      dir,
+		Gateways: []string{down.URL + "/ipfs/", mirror.URL + "/ipfs/"},
+		Parallel: 2,
+	}
+	require.NoError(t, f.Fetch(ctx, files))
+	require.Equal(t, int64(1), atomic.LoadInt64(&ranged))
+	require.Empty(t, f.Progress())
+
+	for _, st := range Check(ctx, dir, files, true) {
+		require.Equal(t, Verified, st.State, st.Name)
+	}
+
+	// all gateways fail
+	f.Gateways = []string{down.URL + "/ipfs/"}
+	require.NoError(t, os.Remove(filepath.Join(dir, "b")))
+	require.Error(t, f.Fetch(ctx, files[1:]))
+}
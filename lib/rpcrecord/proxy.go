@@ -0,0 +1,112 @@
+package rpcrecord
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+var (
+	epochType = reflect.TypeOf(abi.ChainEpoch(0))
+	tskType   = reflect.TypeOf(types.TipSetKey{})
+)
+
+// RecordedFullAPI records the calls made to a while r is recording
+func RecordedFullAPI(a api.FullNode, r *Recorder) api.FullNode {
+	var out api.FullNodeStruct
+	proxy(a, r, &out.Internal)
+	proxy(a, r, &out.CommonStruct.Internal)
+	return &out
+}
+
+func proxy(a api.FullNode, r *Recorder, out interface{}) {
+	rint := reflect.ValueOf(out).Elem()
+	ra := reflect.ValueOf(a)
+
+	for f := 0; f < rint.NumField(); f++ {
+		field := rint.Type().Field(f)
+		fn := ra.MethodByName(field.Name)
+
+		ft := field.Type
+		if ft.NumOut() == 2 && ft.Out(0).Kind() == reflect.Chan {
+			// subscriptions can't be replayed
+			rint.Field(f).Set(fn)
+			continue
+		}
+
+		perm := field.Tag.Get("perm")
+		rint.Field(f).Set(reflect.MakeFunc(ft, func(args []reflect.Value) (results []reflect.Value) {
+			ctx := args[0].Interface().(context.Context)
+			if !r.recording(ctx, field.Name) {
+				return fn.Call(args)
+			}
+
+			c := newCall(ctx, a, field.Name, perm, args[1:])
+			start := time.Now()
+			results = fn.Call(args)
+			c.Duration = time.Since(start)
+
+			if err, _ := results[len(results)-1].Interface().(error); err != nil {
+				c.Error = err.Error()
+			} else if len(results) == 2 {
+				res, err := json.Marshal(results[0].Interface())
+				if err != nil {
+					log.Warnw("encoding result of recorded call", "method", field.Name, "error", err)
+				}
+				c.Result = res
+			}
+
+			r.write(c)
+			return results
+		}))
+	}
+}
+
+// newCall records the params of a call, annotating the ones relative to the
+// chain head
+func newCall(ctx context.Context, a api.FullNode, method, perm string, params []reflect.Value) *Call {
+	c := &Call{
+		Method: method,
+		Perm:   perm,
+		Params: make([]json.RawMessage, len(params)),
+		At:     time.Now(),
+	}
+	if head, err := a.ChainHead(ctx); err == nil {
+		c.Head = head.Height()
+	}
+
+	for i, p := range params {
+		b, err := json.Marshal(p.Interface())
+		if err != nil {
+			log.Warnw("encoding param of recorded call", "method", method, "param", i, "error", err)
+		}
+		c.Params[i] = b
+
+		switch p.Type() {
+		case epochType:
+			c.Relative = append(c.Relative, i)
+		case tskType:
+			tsk := p.Interface().(types.TipSetKey)
+			if tsk.IsEmpty() {
+				// already the head of whichever node it's replayed against
+				continue
+			}
+			ts, err := a.ChainGetTipSet(ctx, tsk)
+			if err != nil {
+				continue
+			}
+			if c.Heights == nil {
+				c.Heights = map[int]abi.ChainEpoch{}
+			}
+			c.Relative = append(c.Relative, i)
+			c.Heights[i] = ts.Height()
+		}
+	}
+	return c
+}
@@ -0,0 +1,271 @@
+// Package rpcrecord records the API calls served by a node to a file, to be
+// replayed against another node with `lotus-shed rpc replay`, comparing the
+// responses of both nodes.
+package rpcrecord
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	logging "github.com/ipfs/go-log/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/lotus/api"
+)
+
+var log = logging.Logger("rpcrecord")
+
+// DefaultMaxBytes bounds the size of a recording when no bound is given
+const DefaultMaxBytes = 64 << 20
+
+// reminderInterval is how often an active recording is logged
+var reminderInterval = 5 * time.Minute
+
+// Redacted replaces the secrets in recorded calls
+const Redacted = "<redacted>"
+
+// Call is a recorded API call, written as a line of JSON
+type Call struct {
+	Method string
+	Perm   string
+	Params []json.RawMessage
+	// Head is the height of the chain head when the call was made
+	Head abi.ChainEpoch
+	// Relative lists the params which are relative to the chain head: epochs
+	// and tipset keys, shifted by the replay as far as the head of the target
+	// node is from Head. Params can be removed from the list by hand to be
+	// replayed as recorded.
+	Relative []int `json:",omitempty"`
+	// Heights holds the heights of the tipset keys in Relative
+	Heights map[int]abi.ChainEpoch `json:",omitempty"`
+
+	At       time.Time
+	Duration time.Duration
+	Result   json.RawMessage `json:",omitempty"`
+	Error    string          `json:",omitempty"`
+	// Redacted is set when secrets were removed from the params or the
+	// result, such calls aren't replayed
+	Redacted bool `json:",omitempty"`
+}
+
+// secrets lists the methods with secret params, by index, or a secret result
+var secrets = map[string]struct {
+	params []int
+	result bool
+}{
+	"AuthNew":      {result: true},
+	"AuthVerify":   {params: []int{0}},
+	"WalletExport": {result: true},
+	"WalletImport": {params: []int{0}},
+	"WalletUnlock": {params: []int{0}},
+}
+
+// unrecorded lists the methods never recorded: the ones controlling the
+// recording
+var unrecorded = map[string]struct{}{
+	"RPCRecordStart":  {},
+	"RPCRecordStop":   {},
+	"RPCRecordStatus": {},
+}
+
+func redact(c *Call) {
+	s, ok := secrets[c.Method]
+	if !ok {
+		return
+	}
+	red, _ := json.Marshal(Redacted)
+	for _, i := range s.params {
+		if i < len(c.Params) {
+			c.Params[i] = red
+			c.Redacted = true
+		}
+	}
+	if s.result && c.Result != nil {
+		c.Result = red
+		c.Redacted = true
+	}
+}
+
+// Recorder writes the calls made to the API to a file while a recording is
+// active. A recording stops when it reaches its size bound.
+type Recorder struct {
+	lk     sync.Mutex
+	cfg    api.RPCRecordConfig
+	f      *os.File
+	status api.RPCRecordStatus
+	stop   chan struct{}
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Start starts recording the calls to cfg.Path, which is appended to
+func (r *Recorder) Start(cfg api.RPCRecordConfig) error {
+	if !filepath.IsAbs(cfg.Path) {
+		return xerrors.Errorf("recording path %q must be absolute", cfg.Path)
+	}
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = DefaultMaxBytes
+	}
+
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	if r.f != nil {
+		return xerrors.Errorf("already recording to %s", r.status.Path)
+	}
+
+	// the calls can reveal what the node is used for, keep them private
+	f, err := os.OpenFile(cfg.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return xerrors.Errorf("opening recording file: %w", err)
+	}
+
+	r.cfg = cfg
+	r.f = f
+	r.stop = make(chan struct{})
+	r.status = api.RPCRecordStatus{
+		Active:           true,
+		Path:             cfg.Path,
+		TokenFingerprint: cfg.TokenFingerprint,
+		Methods:          cfg.Methods,
+		MaxBytes:         cfg.MaxBytes,
+		Started:          time.Now(),
+	}
+
+	log.Warnw("RPC recording started, API calls are written to file", "path", cfg.Path, "token", cfg.TokenFingerprint, "methods", cfg.Methods, "maxBytes", cfg.MaxBytes)
+	go r.remind(r.stop)
+	return nil
+}
+
+// remind logs the recording periodically until it stops
+func (r *Recorder) remind(stop chan struct{}) {
+	t := time.NewTicker(reminderInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			st := r.Status()
+			log.Warnw("RPC recording active", "path", st.Path, "calls", st.Calls, "bytes", st.Bytes, "since", st.Started)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Stop stops the recording, returning its final status
+func (r *Recorder) Stop() (api.RPCRecordStatus, error) {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	if r.f == nil {
+		return r.status, xerrors.Errorf("not recording")
+	}
+	err := r.stopLocked("stopped")
+	return r.status, err
+}
+
+func (r *Recorder) stopLocked(reason string) error {
+	err := r.f.Close()
+	r.f = nil
+	close(r.stop)
+	r.status.Active = false
+	r.status.StopReason = reason
+
+	log.Warnw("RPC recording stopped", "path", r.status.Path, "reason", reason, "calls", r.status.Calls, "bytes", r.status.Bytes)
+	if err != nil {
+		return xerrors.Errorf("closing recording file: %w", err)
+	}
+	return nil
+}
+
+// Status returns the status of the current recording, or of the last one
+// when no recording is active
+func (r *Recorder) Status() api.RPCRecordStatus {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	return r.status
+}
+
+// Close stops the recording, if any, as the node shuts down
+func (r *Recorder) Close() error {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	if r.f == nil {
+		return nil
+	}
+	return r.stopLocked("node shutting down")
+}
+
+// recording returns whether a call to the method by the caller of ctx is to
+// be recorded
+func (r *Recorder) recording(ctx context.Context, method string) bool {
+	if _, ok := unrecorded[method]; ok {
+		return false
+	}
+
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	if r.f == nil {
+		return false
+	}
+	if r.cfg.TokenFingerprint != "" {
+		if fp, ok := api.CallerFingerprint(ctx); !ok || fp != r.cfg.TokenFingerprint {
+			return false
+		}
+	}
+	if len(r.cfg.Methods) == 0 {
+		return true
+	}
+	for _, m := range r.cfg.Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// write appends the call to the recording, stopping it when the call would
+// take it past its size bound
+func (r *Recorder) write(c *Call) {
+	redact(c)
+	b, err := json.Marshal(c)
+	if err != nil {
+		log.Errorw("encoding recorded call", "method", c.Method, "error", err)
+		return
+	}
+	b = append(b, '\n')
+
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	if r.f == nil {
+		// stopped while the call was served
+		return
+	}
+	if r.status.Bytes+int64(len(b)) > r.cfg.MaxBytes {
+		if err := r.stopLocked("size limit reached"); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+	if _, err := r.f.Write(b); err != nil {
+		log.Errorw("writing recorded call", "error", err)
+		if err := r.stopLocked("write failed: " + err.Error()); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+	r.status.Calls++
+	r.status.Bytes += int64(len(b))
+}
@@ -0,0 +1,150 @@
+package rpcrecord
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-jsonrpc/auth"
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/chain/types/mock"
+)
+
+type fakeNode struct {
+	api.FullNodeStub
+	chain []*types.TipSet
+}
+
+func (f *fakeNode) ChainHead(ctx context.Context) (*types.TipSet, error) {
+	return f.chain[len(f.chain)-1], nil
+}
+
+func (f *fakeNode) ChainGetTipSet(ctx context.Context, tsk types.TipSetKey) (*types.TipSet, error) {
+	for _, ts := range f.chain {
+		if ts.Key() == tsk {
+			return ts, nil
+		}
+	}
+	return nil, xerrors.Errorf("tipset %s not found", tsk)
+}
+
+func (f *fakeNode) ChainGetTipSetByHeight(ctx context.Context, h abi.ChainEpoch, tsk types.TipSetKey) (*types.TipSet, error) {
+	return f.chain[h], nil
+}
+
+func (f *fakeNode) AuthNew(ctx context.Context, perms []auth.Permission) ([]byte, error) {
+	return []byte("secret"), nil
+}
+
+func (f *fakeNode) ChainNotify(ctx context.Context) (<-chan []*api.HeadChange, error) {
+	return make(chan []*api.HeadChange), nil
+}
+
+// callerCtx returns the context of a call made with the token
+func callerCtx(token string) context.Context {
+	var ctx context.Context
+	h := api.CallerHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	}))
+	req := httptest.NewRequest("POST", "/rpc/v1", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	return ctx
+}
+
+func TestRecord(t *testing.T) {
+	node := &fakeNode{}
+	var parent *types.TipSet
+	for i := 0; i < 5; i++ {
+		ts := mock.TipSet(mock.MkBlock(parent, 1, uint64(i)))
+		node.chain = append(node.chain, ts)
+		parent = ts
+	}
+
+	r := NewRecorder()
+	a := RecordedFullAPI(node, r)
+	alice, bob := callerCtx("alice"), callerCtx("bob")
+
+	// nothing is recorded until the recording starts
+	_, err := a.ChainHead(alice)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "calls.ndjson")
+	require.Error(t, r.Start(api.RPCRecordConfig{Path: "calls.ndjson"}))
+	require.NoError(t, r.Start(api.RPCRecordConfig{Path: path, TokenFingerprint: api.TokenFingerprint("alice")}))
+	require.Error(t, r.Start(api.RPCRecordConfig{Path: path}))
+
+	_, err = a.ChainGetTipSetByHeight(bob, 1, types.EmptyTSK)
+	require.NoError(t, err)
+	_, err = a.ChainGetTipSetByHeight(alice, 2, node.chain[3].Key())
+	require.NoError(t, err)
+	_, err = a.AuthNew(alice, api.AllPermissions)
+	require.NoError(t, err)
+	_, err = a.ChainNotify(alice)
+	require.NoError(t, err)
+
+	st, err := r.Stop()
+	require.NoError(t, err)
+	require.False(t, st.Active)
+	require.Equal(t, int64(2), st.Calls)
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	calls, err := ReadCalls(f)
+	require.NoError(t, f.Close())
+	require.NoError(t, err)
+	require.Len(t, calls, 2)
+
+	c := calls[0]
+	require.Equal(t, "ChainGetTipSetByHeight", c.Method)
+	require.Equal(t, "read", c.Perm)
+	require.Equal(t, abi.ChainEpoch(4), c.Head)
+	require.Equal(t, []int{0, 1}, c.Relative)
+	require.Equal(t, map[int]abi.ChainEpoch{1: 3}, c.Heights)
+	require.False(t, c.Redacted)
+	res, err := json.Marshal(node.chain[2])
+	require.NoError(t, err)
+	require.True(t, SameResult(res, c.Result))
+
+	// secrets aren't recorded
+	require.Equal(t, "AuthNew", calls[1].Method)
+	require.True(t, calls[1].Redacted)
+	var redacted string
+	require.NoError(t, json.Unmarshal(calls[1].Result, &redacted))
+	require.Equal(t, Redacted, redacted)
+
+	// epochs are shifted, tipsets resolved at their shifted height
+	params, err := c.ShiftParams(10, func(h abi.ChainEpoch) (types.TipSetKey, error) {
+		require.Equal(t, abi.ChainEpoch(13), h)
+		return node.chain[0].Key(), nil
+	})
+	require.NoError(t, err)
+	tsk, err := json.Marshal(node.chain[0].Key())
+	require.NoError(t, err)
+	require.Equal(t, []json.RawMessage{json.RawMessage("12"), tsk}, params)
+
+	// the recording stops at its size bound
+	require.NoError(t, r.Start(api.RPCRecordConfig{Path: path, MaxBytes: 10}))
+	_, err = a.ChainHead(bob)
+	require.NoError(t, err)
+	st = r.Status()
+	require.False(t, st.Active)
+	require.Equal(t, "size limit reached", st.StopReason)
+	require.Equal(t, int64(0), st.Calls)
+}
+
+func TestSameResult(t *testing.T) {
+	require.True(t, SameResult(json.RawMessage(`{"a":1,"b":[2]}`), json.RawMessage(`{ "b": [2], "a": 1 }`)))
+	require.False(t, SameResult(json.RawMessage(`{"a":1}`), json.RawMessage(`{"a":2}`)))
+	require.False(t, SameResult(json.RawMessage(`null`), nil))
+}
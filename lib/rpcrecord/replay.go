@@ -0,0 +1,89 @@
+package rpcrecord
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"reflect"
+
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+// ReadCalls reads the calls of a recording
+func ReadCalls(r io.Reader) ([]*Call, error) {
+	var calls []*Call
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, DefaultMaxBytes)
+	for line := 1; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var c Call
+		if err := json.Unmarshal(sc.Bytes(), &c); err != nil {
+			return nil, xerrors.Errorf("decoding call on line %d: %w", line, err)
+		}
+		calls = append(calls, &c)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, xerrors.Errorf("reading recording: %w", err)
+	}
+	return calls, nil
+}
+
+// ShiftParams returns the params of the call with the params in Relative
+// moved by delta epochs: epochs are shifted, and tipset keys replaced by the
+// key resolve returns for the shifted height of their tipset.
+func (c *Call) ShiftParams(delta abi.ChainEpoch, resolve func(abi.ChainEpoch) (types.TipSetKey, error)) ([]json.RawMessage, error) {
+	params := append([]json.RawMessage{}, c.Params...)
+	if delta == 0 {
+		return params, nil
+	}
+
+	for _, i := range c.Relative {
+		if i < 0 || i >= len(params) {
+			return nil, xerrors.Errorf("relative param %d out of range", i)
+		}
+
+		var shifted interface{}
+		if h, ok := c.Heights[i]; ok {
+			tsk, err := resolve(h + delta)
+			if err != nil {
+				return nil, xerrors.Errorf("resolving tipset of param %d at height %d: %w", i, h+delta, err)
+			}
+			shifted = tsk
+		} else {
+			var epoch abi.ChainEpoch
+			if err := json.Unmarshal(params[i], &epoch); err != nil {
+				return nil, xerrors.Errorf("param %d isn't an epoch: %w", i, err)
+			}
+			shifted = epoch + delta
+		}
+
+		b, err := json.Marshal(shifted)
+		if err != nil {
+			return nil, err
+		}
+		params[i] = b
+	}
+	return params, nil
+}
+
+// SameResult returns whether two JSON results are equal, regardless of their
+// formatting and the order of their object keys
+func SameResult(a, b json.RawMessage) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return len(a) == len(b)
+	}
+	var av, bv interface{}
+	if err := json.Unmarshal(a, &av); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(b, &bv); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(av, bv)
+}
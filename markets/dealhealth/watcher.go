@@ -0,0 +1,377 @@
+package dealhealth
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/ipfs/go-cid"
+	logging "github.com/ipfs/go-log/v2"
+	lps "github.com/whyrusleeping/pubsub"
+	"go.uber.org/fx"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-fil-markets/storagemarket"
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/miner"
+	"github.com/filecoin-project/lotus/chain/store"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/node/impl/full"
+	"github.com/filecoin-project/lotus/node/modules/helpers"
+)
+
+var log = logging.Logger("dealhealth")
+
+const healthUpdates = "health"
+
+// watcherAPI is the node API used by the Watcher (an interface for the tests)
+type watcherAPI interface {
+	ChainHead(context.Context) (*types.TipSet, error)
+	ChainNotify(context.Context) (<-chan []*api.HeadChange, error)
+	StateGetActor(ctx context.Context, actor address.Address, tsk types.TipSetKey) (*types.Actor, error)
+	StateMarketStorageDeal(ctx context.Context, dealID abi.DealID, tsk types.TipSetKey) (*api.MarketDeal, error)
+	StateMinerSectors(ctx context.Context, addr address.Address, sectorNos *bitfield.BitField, tsk types.TipSetKey) ([]*miner.SectorOnChainInfo, error)
+	StateSectorExpiration(ctx context.Context, addr address.Address, sector abi.SectorNumber, tsk types.TipSetKey) (*miner.SectorExpiration, error)
+}
+
+type WatcherAPI struct {
+	fx.In
+
+	full.ChainAPI
+	full.StateAPI
+}
+
+// provider caches what we know about the sectors of a storage provider
+type provider struct {
+	// miner actor state the deals of this provider were last checked at,
+	// deals are only checked again once it changes
+	head cid.Cid
+
+	// deal -> sector index, built from the full sector list of the provider
+	// when we can't find a deal in it, at most once per provider state
+	sectors   map[abi.DealID]abi.SectorNumber
+	indexedAt cid.Cid
+}
+
+// Watcher monitors the sectors holding the active deals of the local client,
+// reporting deals whose sector was terminated, is faulty, or expires before
+// the deal ends
+type Watcher struct {
+	api   watcherAPI
+	deals func(context.Context) ([]storagemarket.ClientDeal, error)
+
+	closing chan struct{}
+	changes *lps.PubSub
+
+	// held while checking deals, also protects providers
+	checkLk   sync.Mutex
+	providers map[address.Address]*provider
+
+	lk     sync.Mutex
+	health map[abi.DealID]api.DealHealth
+}
+
+func NewWatcher(mctx helpers.MetricsCtx, lc fx.Lifecycle, wapi WatcherAPI, client storagemarket.StorageClient) *Watcher {
+	w := newWatcher(&wapi, client.ListLocalDeals)
+
+	ctx := helpers.LifecycleCtx(mctx, lc)
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go w.run(ctx)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			close(w.closing)
+			w.changes.Shutdown()
+			return nil
+		},
+	})
+
+	return w
+}
+
+func newWatcher(wapi watcherAPI, deals func(context.Context) ([]storagemarket.ClientDeal, error)) *Watcher {
+	return &Watcher{
+		api:       wapi,
+		deals:     deals,
+		closing:   make(chan struct{}),
+		changes:   lps.New(50),
+		providers: map[address.Address]*provider{},
+		health:    map[abi.DealID]api.DealHealth{},
+	}
+}
+
+func (w *Watcher) run(ctx context.Context) {
+	notifs, err := w.api.ChainNotify(ctx)
+	if err != nil {
+		log.Errorf("deal health watcher: subscribing to head changes: %s", err)
+		return
+	}
+
+	for {
+		select {
+		case changes, ok := <-notifs:
+			if !ok {
+				log.Warn("deal health watcher: head change channel closed")
+				return
+			}
+
+			var head *types.TipSet
+			for _, change := range changes {
+				if change.Type == store.HCApply || change.Type == store.HCCurrent {
+					head = change.Val
+				}
+			}
+			if head == nil {
+				continue
+			}
+
+			if err := w.check(ctx, head); err != nil {
+				log.Errorf("deal health watcher: checking deals at %d: %s", head.Height(), err)
+			}
+		case <-w.closing:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// check evaluates the health of active client deals at the tipset, only
+// looking at deals of providers whose state changed since the last check
+func (w *Watcher) check(ctx context.Context, ts *types.TipSet) error {
+	w.checkLk.Lock()
+	defer w.checkLk.Unlock()
+
+	deals, err := w.deals(ctx)
+	if err != nil {
+		return xerrors.Errorf("listing client deals: %w", err)
+	}
+
+	byProvider := map[address.Address][]storagemarket.ClientDeal{}
+	active := map[abi.DealID]struct{}{}
+	for _, d := range deals {
+		if d.State != storagemarket.StorageDealActive || d.DealID == 0 {
+			continue
+		}
+		byProvider[d.Proposal.Provider] = append(byProvider[d.Proposal.Provider], d)
+		active[d.DealID] = struct{}{}
+	}
+
+	var updates []api.DealHealth
+	for maddr, pdeals := range byProvider {
+		p, ok := w.providers[maddr]
+		if !ok {
+			p = &provider{}
+			w.providers[maddr] = p
+		}
+
+		act, err := w.api.StateGetActor(ctx, maddr, ts.Key())
+		if err != nil {
+			return xerrors.Errorf("getting provider actor %s: %w", maddr, err)
+		}
+
+		if act.Head == p.head && w.allKnown(pdeals) {
+			continue
+		}
+
+		for _, d := range pdeals {
+			h, err := w.dealHealth(ctx, ts, p, act.Head, d)
+			if err != nil {
+				return xerrors.Errorf("checking deal %d: %w", d.DealID, err)
+			}
+
+			w.lk.Lock()
+			prev, known := w.health[d.DealID]
+			w.health[d.DealID] = h
+			w.lk.Unlock()
+
+			if !known || prev.Status != h.Status || prev.Sector != h.Sector || prev.SectorExpiration != h.SectorExpiration {
+				updates = append(updates, h)
+			}
+		}
+
+		p.head = act.Head
+	}
+
+	w.lk.Lock()
+	for id := range w.health {
+		if _, ok := active[id]; !ok {
+			delete(w.health, id)
+		}
+	}
+	w.lk.Unlock()
+
+	for maddr := range w.providers {
+		if _, ok := byProvider[maddr]; !ok {
+			delete(w.providers, maddr)
+		}
+	}
+
+	for _, u := range updates {
+		w.changes.Pub(u, healthUpdates)
+	}
+
+	return nil
+}
+
+func (w *Watcher) allKnown(deals []storagemarket.ClientDeal) bool {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+
+	for _, d := range deals {
+		if _, ok := w.health[d.DealID]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *Watcher) dealHealth(ctx context.Context, ts *types.TipSet, p *provider, head cid.Cid, d storagemarket.ClientDeal) (api.DealHealth, error) {
+	h := api.DealHealth{
+		ProposalCid: d.ProposalCid,
+		DealID:      d.DealID,
+		Provider:    d.Proposal.Provider,
+		DealEnd:     d.Proposal.EndEpoch,
+		Height:      ts.Height(),
+	}
+
+	md, err := w.api.StateMarketStorageDeal(ctx, d.DealID, ts.Key())
+	if err != nil {
+		// deals are removed from the market actor once they expire or
+		// their sector was terminated
+		h.Status = missingDealStatus(ts.Height(), d.Proposal.EndEpoch)
+		h.Message = "deal not found in market state"
+		return h, nil
+	}
+	if md.State.SlashEpoch != -1 {
+		h.Status = api.DealSectorTerminated
+		h.Message = "deal slashed"
+		return h, nil
+	}
+
+	sector, ok := p.sectors[d.DealID]
+	if !ok && p.indexedAt != head {
+		if err := w.indexSectors(ctx, ts, p, d.Proposal.Provider, head); err != nil {
+			return api.DealHealth{}, err
+		}
+		sector, ok = p.sectors[d.DealID]
+	}
+	if !ok {
+		h.Status = api.DealSectorUnknown
+		h.Message = "no provider sector holds the deal"
+		return h, nil
+	}
+	h.Sector = sector
+
+	exp, err := w.api.StateSectorExpiration(ctx, d.Proposal.Provider, sector, ts.Key())
+	if err != nil {
+		// terminated sectors are removed from the miner state
+		delete(p.sectors, d.DealID)
+		h.Status = api.DealSectorTerminated
+		h.Message = "sector not found in provider state"
+		return h, nil
+	}
+
+	h.SectorExpiration = exp.OnTime
+	switch {
+	case exp.Early != 0:
+		h.Status = api.DealSectorFaulty
+		h.FaultExpiration = exp.Early
+		h.Message = "sector faulty, terminated if not recovered"
+	case exp.OnTime < d.Proposal.EndEpoch:
+		h.Status = api.DealSectorExpiringEarly
+		h.Message = "sector expires before the deal ends"
+	default:
+		h.Status = api.DealSectorOK
+	}
+
+	return h, nil
+}
+
+// missingDealStatus is the status of a deal missing from the market state,
+// which is expected once the deal ended, and a termination before that
+func missingDealStatus(height, dealEnd abi.ChainEpoch) api.DealSectorStatus {
+	if height >= dealEnd {
+		return api.DealSectorUnknown
+	}
+	return api.DealSectorTerminated
+}
+
+func (w *Watcher) indexSectors(ctx context.Context, ts *types.TipSet, p *provider, maddr address.Address, head cid.Cid) error {
+	sectors, err := w.api.StateMinerSectors(ctx, maddr, nil, ts.Key())
+	if err != nil {
+		return xerrors.Errorf("loading sectors of %s: %w", maddr, err)
+	}
+
+	p.sectors = map[abi.DealID]abi.SectorNumber{}
+	for _, s := range sectors {
+		for _, id := range s.DealIDs {
+			p.sectors[id] = s.SectorNumber
+		}
+	}
+	p.indexedAt = head
+
+	return nil
+}
+
+// Health returns the health of all active client deals at the chain head
+func (w *Watcher) Health(ctx context.Context) ([]api.DealHealth, error) {
+	head, err := w.api.ChainHead(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("getting chain head: %w", err)
+	}
+
+	if err := w.check(ctx, head); err != nil {
+		return nil, err
+	}
+
+	w.lk.Lock()
+	defer w.lk.Unlock()
+
+	out := make([]api.DealHealth, 0, len(w.health))
+	for _, h := range w.health {
+		out = append(out, h)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].DealID < out[j].DealID
+	})
+	return out, nil
+}
+
+// Updates returns deal health whenever it changes
+func (w *Watcher) Updates(ctx context.Context) (<-chan api.DealHealth, error) {
+	out := make(chan api.DealHealth, 20)
+	sub := w.changes.Sub(healthUpdates)
+
+	go func() {
+		defer w.changes.Unsub(sub, healthUpdates)
+		defer close(out)
+
+		for {
+			select {
+			case u, ok := <-sub:
+				if !ok {
+					return
+				}
+				select {
+				case out <- u.(api.DealHealth):
+				case <-ctx.Done():
+					return
+				case <-w.closing:
+					return
+				}
+			case <-ctx.Done():
+				return
+			case <-w.closing:
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,176 @@
+package dealhealth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-fil-markets/storagemarket"
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/market"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/miner"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/chain/types/mock"
+)
+
+type fakeAPI struct {
+	head  *types.TipSet
+	state cid.Cid
+
+	deals       map[abi.DealID]*api.MarketDeal
+	sectors     map[abi.SectorNumber]*miner.SectorOnChainInfo
+	expirations map[abi.SectorNumber]*miner.SectorExpiration
+
+	sectorLoads int
+	dealLoads   int
+}
+
+func (f *fakeAPI) ChainHead(context.Context) (*types.TipSet, error) {
+	return f.head, nil
+}
+
+func (f *fakeAPI) ChainNotify(context.Context) (<-chan []*api.HeadChange, error) {
+	return make(chan []*api.HeadChange), nil
+}
+
+func (f *fakeAPI) StateGetActor(ctx context.Context, actor address.Address, tsk types.TipSetKey) (*types.Actor, error) {
+	return &types.Actor{Head: f.state}, nil
+}
+
+func (f *fakeAPI) StateMarketStorageDeal(ctx context.Context, dealID abi.DealID, tsk types.TipSetKey) (*api.MarketDeal, error) {
+	f.dealLoads++
+	d, ok := f.deals[dealID]
+	if !ok {
+		return nil, xerrors.Errorf("deal %d not found", dealID)
+	}
+	return d, nil
+}
+
+func (f *fakeAPI) StateMinerSectors(ctx context.Context, addr address.Address, sectorNos *bitfield.BitField, tsk types.TipSetKey) ([]*miner.SectorOnChainInfo, error) {
+	f.sectorLoads++
+	var out []*miner.SectorOnChainInfo
+	for _, s := range f.sectors {
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func (f *fakeAPI) StateSectorExpiration(ctx context.Context, addr address.Address, sector abi.SectorNumber, tsk types.TipSetKey) (*miner.SectorExpiration, error) {
+	e, ok := f.expirations[sector]
+	if !ok {
+		return nil, xerrors.Errorf("sector %d not found", sector)
+	}
+	return e, nil
+}
+
+func TestWatcher(t *testing.T) {
+	ctx := context.Background()
+
+	maddr, err := address.NewIDAddress(1000)
+	require.NoError(t, err)
+
+	fapi := &fakeAPI{
+		head:  mock.TipSet(mock.MkBlock(nil, 1, 1)),
+		state: testCid(1),
+		deals: map[abi.DealID]*api.MarketDeal{},
+		sectors: map[abi.SectorNumber]*miner.SectorOnChainInfo{
+			1: {SectorNumber: 1, DealIDs: []abi.DealID{10}},
+			2: {SectorNumber: 2, DealIDs: []abi.DealID{11}},
+		},
+		expirations: map[abi.SectorNumber]*miner.SectorExpiration{
+			1: {OnTime: 2000},
+			2: {OnTime: 800},
+		},
+	}
+
+	var deals []storagemarket.ClientDeal
+	for i, id := range []abi.DealID{10, 11} {
+		fapi.deals[id] = &api.MarketDeal{State: market.DealState{SlashEpoch: -1}}
+
+		d := storagemarket.ClientDeal{
+			ProposalCid: testCid(100 + i),
+			DealID:      id,
+			State:       storagemarket.StorageDealActive,
+		}
+		d.Proposal.Provider = maddr
+		d.Proposal.EndEpoch = 1000
+		deals = append(deals, d)
+	}
+	// not on chain yet, ignored
+	deals = append(deals, storagemarket.ClientDeal{
+		ProposalCid: testCid(200),
+		State:       storagemarket.StorageDealSealing,
+	})
+
+	w := newWatcher(fapi, func(context.Context) ([]storagemarket.ClientDeal, error) {
+		return deals, nil
+	})
+
+	updates, err := w.Updates(ctx)
+	require.NoError(t, err)
+
+	statuses := func() map[abi.DealID]api.DealSectorStatus {
+		health, err := w.Health(ctx)
+		require.NoError(t, err)
+
+		out := map[abi.DealID]api.DealSectorStatus{}
+		for _, h := range health {
+			out[h.DealID] = h.Status
+		}
+		return out
+	}
+
+	require.Equal(t, map[abi.DealID]api.DealSectorStatus{
+		10: api.DealSectorOK,
+		11: api.DealSectorExpiringEarly,
+	}, statuses())
+	require.Equal(t, 1, fapi.sectorLoads)
+
+	for i := 0; i < 2; i++ {
+		u := <-updates
+		require.Contains(t, []abi.DealID{10, 11}, u.DealID)
+	}
+
+	// provider state unchanged, nothing is loaded again
+	dealLoads := fapi.dealLoads
+	statuses()
+	require.Equal(t, dealLoads, fapi.dealLoads)
+
+	// sector 1 becomes faulty
+	fapi.state = testCid(2)
+	fapi.expirations[1] = &miner.SectorExpiration{OnTime: 2000, Early: 1500}
+	require.Equal(t, api.DealSectorFaulty, statuses()[10])
+	require.Equal(t, 1, fapi.sectorLoads, "sector index is only rebuilt for unknown deals")
+
+	u := <-updates
+	require.Equal(t, abi.DealID(10), u.DealID)
+	require.Equal(t, api.DealSectorFaulty, u.Status)
+	require.Equal(t, abi.ChainEpoch(1500), u.FaultExpiration)
+
+	// and gets terminated
+	fapi.state = testCid(3)
+	delete(fapi.expirations, 1)
+	delete(fapi.deals, 10)
+	require.Equal(t, api.DealSectorTerminated, statuses()[10])
+
+	u = <-updates
+	require.Equal(t, abi.DealID(10), u.DealID)
+	require.Equal(t, api.DealSectorTerminated, u.Status)
+
+	select {
+	case u := <-updates:
+		t.Fatalf("unexpected update %+v", u)
+	default:
+	}
+}
+
+func testCid(i int) cid.Cid {
+	return mock.MkBlock(nil, uint64(i), uint64(i)).Cid()
+}
@@ -0,0 +1,321 @@
+// Package retrievalstats records the outcome and performance of the
+// retrievals made by the client, to pick providers by how they served past
+// retrievals.
+package retrievalstats
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/namespace"
+	"github.com/ipfs/go-datastore/query"
+	logging "github.com/ipfs/go-log/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/lotus/api"
+)
+
+var log = logging.Logger("retrievalstats")
+
+var storePrefix = datastore.NewKey("/retrievalstats")
+
+// pruneInterval is how often records past the retention are removed
+const pruneInterval = time.Hour
+
+// Store keeps a record per retrieval under /<provider>/<start>, the start
+// time being in zero-padded unix nanoseconds so that keys sort by time
+type Store struct {
+	ds        datastore.Batching
+	retention time.Duration
+
+	lk        sync.Mutex
+	lastPrune time.Time
+}
+
+// NewStore returns a store keeping records for retention, or forever when
+// it's 0
+func NewStore(ds datastore.Batching, retention time.Duration) *Store {
+	return &Store{
+		ds:        namespace.Wrap(ds, storePrefix),
+		retention: retention,
+	}
+}
+
+func recordKey(r *api.RetrievalRecord) datastore.Key {
+	return datastore.KeyWithNamespaces([]string{r.Provider.String(), fmt.Sprintf("%020d", r.Started.UnixNano())})
+}
+
+func (s *Store) expired(r *api.RetrievalRecord, now time.Time) bool {
+	return s.retention > 0 && r.Started.Before(now.Add(-s.retention))
+}
+
+// Put records the retrieval
+func (s *Store) Put(r api.RetrievalRecord) error {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	if err := s.put(&r); err != nil {
+		return err
+	}
+
+	if time.Since(s.lastPrune) > pruneInterval {
+		if err := s.prune(time.Now()); err != nil {
+			log.Warnw("pruning retrieval records", "error", err)
+		}
+	}
+	return nil
+}
+
+func (s *Store) put(r *api.RetrievalRecord) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if err := s.ds.Put(recordKey(r), b); err != nil {
+		return xerrors.Errorf("storing retrieval record: %w", err)
+	}
+	return nil
+}
+
+// prune removes the records past the retention
+func (s *Store) prune(now time.Time) error {
+	s.lastPrune = now
+	if s.retention == 0 {
+		return nil
+	}
+
+	recs, err := s.load(address.Undef)
+	if err != nil {
+		return err
+	}
+	batch, err := s.ds.Batch()
+	if err != nil {
+		return err
+	}
+	for i := range recs {
+		if s.expired(&recs[i], now) {
+			if err := batch.Delete(recordKey(&recs[i])); err != nil {
+				return err
+			}
+		}
+	}
+	return batch.Commit()
+}
+
+// load returns the records of the provider, or of all providers when it's
+// undefined, in no particular order
+func (s *Store) load(provider address.Address) ([]api.RetrievalRecord, error) {
+	q := query.Query{}
+	if provider != address.Undef {
+		q.Prefix = datastore.NewKey(provider.String()).String()
+	}
+	res, err := s.ds.Query(q)
+	if err != nil {
+		return nil, xerrors.Errorf("listing retrieval records: %w", err)
+	}
+	entries, err := res.Rest()
+	if err != nil {
+		return nil, xerrors.Errorf("listing retrieval records: %w", err)
+	}
+
+	out := make([]api.RetrievalRecord, 0, len(entries))
+	for _, e := range entries {
+		var r api.RetrievalRecord
+		if err := json.Unmarshal(e.Value, &r); err != nil {
+			return nil, xerrors.Errorf("decoding retrieval record %s: %w", e.Key, err)
+		}
+		if provider != address.Undef && r.Provider != provider {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// Records returns the retained records of the provider, or of all providers
+// when it's undefined, newest first, at most limit of them when it's positive
+func (s *Store) Records(provider address.Address, limit int) ([]api.RetrievalRecord, error) {
+	recs, err := s.load(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	out := recs[:0]
+	for i := range recs {
+		if !s.expired(&recs[i], now) {
+			out = append(out, recs[i])
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Started.After(out[j].Started)
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// Stats aggregates the retained records of the provider, or of each provider
+// when it's undefined
+func (s *Store) Stats(provider address.Address) ([]api.RetrievalProviderStats, error) {
+	recs, err := s.Records(provider, 0)
+	if err != nil {
+		return nil, err
+	}
+	return aggregate(recs), nil
+}
+
+// aggregate aggregates the records per provider, sorting the providers by
+// address. The last error is the one of the newest failed retrieval.
+func aggregate(recs []api.RetrievalRecord) []api.RetrievalProviderStats {
+	type totals struct {
+		stats      api.RetrievalProviderStats
+		ttfb       time.Duration
+		throughput uint64
+		received   int
+	}
+
+	byProvider := map[address.Address]*totals{}
+	for _, r := range recs {
+		t, ok := byProvider[r.Provider]
+		if !ok {
+			t = &totals{stats: api.RetrievalProviderStats{Provider: r.Provider, TotalCost: big.Zero()}}
+			byProvider[r.Provider] = t
+		}
+
+		st := &t.stats
+		st.Retrievals++
+		st.BytesReceived += r.BytesReceived
+		if !r.Cost.Nil() {
+			st.TotalCost = big.Add(st.TotalCost, r.Cost)
+		}
+		newest := r.Started.After(st.LastRetrieval)
+		if newest {
+			st.LastRetrieval = r.Started
+		}
+		if r.Error != "" {
+			st.Failures++
+			if newest || st.LastError == "" {
+				st.LastError = r.Error
+			}
+		}
+		if r.BytesReceived > 0 {
+			t.ttfb += r.TimeToFirstByte
+			t.throughput += r.Throughput
+			t.received++
+		}
+	}
+
+	out := make([]api.RetrievalProviderStats, 0, len(byProvider))
+	for _, t := range byProvider {
+		if t.received > 0 {
+			t.stats.AvgTimeToFirstByte = t.ttfb / time.Duration(t.received)
+			t.stats.AvgThroughput = t.throughput / uint64(t.received)
+		}
+		out = append(out, t.stats)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Provider.String() < out[j].Provider.String()
+	})
+	return out
+}
+
+// Import adds the records not recorded yet and not past the retention,
+// returning the number added
+func (s *Store) Import(recs []api.RetrievalRecord) (int, error) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	now := time.Now()
+	var added int
+	for i := range recs {
+		r := &recs[i]
+		if r.Provider == address.Undef || r.Started.IsZero() {
+			return added, xerrors.Errorf("record %d: missing provider or start time", i)
+		}
+		if s.expired(r, now) {
+			continue
+		}
+		if r.Cost.Nil() {
+			r.Cost = big.Zero()
+		}
+		has, err := s.ds.Has(recordKey(r))
+		if err != nil {
+			return added, err
+		}
+		if has {
+			continue
+		}
+		if err := s.put(r); err != nil {
+			return added, err
+		}
+		added++
+	}
+	return added, nil
+}
+
+// Attempt tracks a retrieval in progress, to record it when it's done
+type Attempt struct {
+	s   *Store
+	rec api.RetrievalRecord
+	// firstByte is when data was first received
+	firstByte time.Time
+}
+
+// Begin starts tracking a retrieval of the size bytes of root from the
+// provider
+func (s *Store) Begin(provider address.Address, root cid.Cid, size uint64) *Attempt {
+	return &Attempt{
+		s: s,
+		rec: api.RetrievalRecord{
+			Provider: provider,
+			Root:     root,
+			Size:     size,
+			Started:  time.Now(),
+			Cost:     big.Zero(),
+		},
+	}
+}
+
+// Progress updates the bytes received and the funds spent so far
+func (a *Attempt) Progress(received uint64, spent abi.TokenAmount) {
+	if received > 0 && a.firstByte.IsZero() {
+		a.firstByte = time.Now()
+	}
+	a.rec.BytesReceived = received
+	if !spent.Nil() {
+		a.rec.Cost = spent
+	}
+}
+
+// Done records the retrieval, failed when err isn't nil
+func (a *Attempt) Done(err error) {
+	end := time.Now()
+	r := a.rec
+	r.Duration = end.Sub(r.Started)
+	if !a.firstByte.IsZero() {
+		r.TimeToFirstByte = a.firstByte.Sub(r.Started)
+		if d := end.Sub(a.firstByte); d > 0 {
+			r.Throughput = uint64(float64(r.BytesReceived) / d.Seconds())
+		} else {
+			r.Throughput = r.BytesReceived
+		}
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+
+	if err := a.s.Put(r); err != nil {
+		log.Warnw("recording retrieval", "provider", r.Provider, "root", r.Root, "error", err)
+	}
+}
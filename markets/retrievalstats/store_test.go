@@ -0,0 +1,94 @@
+package retrievalstats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/lotus/api"
+)
+
+func TestStore(t *testing.T) {
+	s := NewStore(dssync.MutexWrap(datastore.NewMapDatastore()), 24*time.Hour)
+	p1, err := address.NewIDAddress(1000)
+	require.NoError(t, err)
+	p2, err := address.NewIDAddress(10001)
+	require.NoError(t, err)
+
+	now := time.Now()
+	record := func(p address.Address, age time.Duration, received, throughput uint64, ttfb time.Duration, cost int64, failure string) api.RetrievalRecord {
+		return api.RetrievalRecord{
+			Provider:        p,
+			Started:         now.Add(-age),
+			TimeToFirstByte: ttfb,
+			BytesReceived:   received,
+			Throughput:      throughput,
+			Cost:            big.NewInt(cost),
+			Error:           failure,
+		}
+	}
+
+	require.NoError(t, s.Put(record(p1, 3*time.Hour, 1000, 100, time.Second, 10, "")))
+	require.NoError(t, s.Put(record(p1, 2*time.Hour, 0, 0, 0, 0, "rejected")))
+	require.NoError(t, s.Put(record(p1, time.Hour, 3000, 300, 3*time.Second, 30, "")))
+	require.NoError(t, s.Put(record(p2, time.Minute, 500, 50, time.Second, 5, "timed out")))
+	// past the retention
+	require.NoError(t, s.Put(record(p1, 48*time.Hour, 1, 1, time.Second, 1, "")))
+
+	recs, err := s.Records(address.Undef, 0)
+	require.NoError(t, err)
+	require.Len(t, recs, 4)
+	require.Equal(t, p2, recs[0].Provider, "newest first")
+
+	// the keys of p1's records are a prefix of the keys of p2's (t01000 and t010001)
+	recs, err = s.Records(p1, 2)
+	require.NoError(t, err)
+	require.Len(t, recs, 2)
+	require.Equal(t, uint64(3000), recs[0].BytesReceived)
+
+	stats, err := s.Stats(address.Undef)
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	st := stats[0]
+	require.Equal(t, p1, st.Provider)
+	require.Equal(t, 3, st.Retrievals)
+	require.Equal(t, 1, st.Failures)
+	require.Equal(t, uint64(4000), st.BytesReceived)
+	require.Equal(t, big.NewInt(40), st.TotalCost)
+	require.Equal(t, 2*time.Second, st.AvgTimeToFirstByte)
+	require.Equal(t, uint64(200), st.AvgThroughput)
+	require.Equal(t, "rejected", st.LastError)
+	require.Equal(t, "timed out", stats[1].LastError)
+
+	// export from one node, import into another
+	exported, err := s.Records(address.Undef, 0)
+	require.NoError(t, err)
+	other := NewStore(dssync.MutexWrap(datastore.NewMapDatastore()), 90*time.Minute)
+	n, err := other.Import(exported)
+	require.NoError(t, err)
+	require.Equal(t, 2, n, "records past the retention are skipped")
+	n, err = other.Import(exported)
+	require.NoError(t, err)
+	require.Equal(t, 0, n, "records are imported once")
+
+	_, err = other.Import([]api.RetrievalRecord{{Started: now}})
+	require.Error(t, err)
+
+	// attempts are recorded when done
+	a := other.Begin(p1, recs[0].Root, 100)
+	a.Progress(0, big.Zero())
+	a.Progress(100, big.NewInt(7))
+	a.Done(xerrors.New("boom"))
+	recs, err = other.Records(p1, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), recs[0].BytesReceived)
+	require.Equal(t, big.NewInt(7), recs[0].Cost)
+	require.Equal(t, "boom", recs[0].Error)
+}
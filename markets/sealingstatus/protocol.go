@@ -0,0 +1,99 @@
+// Package sealingstatus implements a protocol through which storage clients
+// ask providers where their deals are in the sealing pipeline, and when they
+// are expected to activate.
+package sealingstatus
+
+import (
+	"context"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"golang.org/x/xerrors"
+
+	cborutil "github.com/filecoin-project/go-cbor-util"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/crypto"
+
+	"github.com/filecoin-project/lotus/api"
+)
+
+const ProtocolID = "/wd/storage/sealingstatus/1.0.0"
+
+// Request asks for the sealing status of a deal. It is signed by the deal
+// client over the proposal cid bytes, so only the client learns the status.
+type Request struct {
+	Proposal  cid.Cid
+	Signature crypto.Signature
+}
+
+type Response struct {
+	// Error is set when the provider couldn't answer
+	Error string
+
+	Disclosed     bool
+	Sector        abi.SectorNumber
+	SectorState   string
+	QueuePosition uint64
+	QueueLength   uint64
+	// ETA is in unix seconds, 0 when unknown
+	ETA int64
+}
+
+func newResponse(st *api.DealSealingStatus) *Response {
+	out := &Response{
+		Disclosed:     st.Disclosed,
+		Sector:        st.Sector,
+		SectorState:   st.SectorState,
+		QueuePosition: uint64(st.QueuePosition),
+		QueueLength:   uint64(st.QueueLength),
+	}
+	if !st.ETA.IsZero() {
+		out.ETA = st.ETA.Unix()
+	}
+	return out
+}
+
+func (r *Response) status() *api.DealSealingStatus {
+	out := &api.DealSealingStatus{
+		Disclosed:     r.Disclosed,
+		Sector:        r.Sector,
+		SectorState:   r.SectorState,
+		QueuePosition: int(r.QueuePosition),
+		QueueLength:   int(r.QueueLength),
+	}
+	if r.ETA != 0 {
+		out.ETA = time.Unix(r.ETA, 0)
+	}
+	return out
+}
+
+// Query asks the provider for the sealing status of the deal
+func Query(ctx context.Context, h host.Host, provider peer.ID, proposal cid.Cid, sig *crypto.Signature) (*api.DealSealingStatus, error) {
+	s, err := h.NewStream(ctx, provider, ProtocolID)
+	if err != nil {
+		return nil, xerrors.Errorf("opening stream: %w", err)
+	}
+	defer s.Close() //nolint:errcheck
+
+	if dl, ok := ctx.Deadline(); ok {
+		if err := s.SetDeadline(dl); err != nil {
+			return nil, xerrors.Errorf("setting stream deadline: %w", err)
+		}
+	}
+
+	if err := cborutil.WriteCborRPC(s, &Request{Proposal: proposal, Signature: *sig}); err != nil {
+		return nil, xerrors.Errorf("sending request: %w", err)
+	}
+
+	var resp Response
+	if err := cborutil.ReadCborRPC(s, &resp); err != nil {
+		return nil, xerrors.Errorf("reading response: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, xerrors.Errorf("provider error: %s", resp.Error)
+	}
+
+	return resp.status(), nil
+}
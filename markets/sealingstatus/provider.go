@@ -0,0 +1,277 @@
+package sealingstatus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	logging "github.com/ipfs/go-log/v2"
+	inet "github.com/libp2p/go-libp2p-core/network"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	cborutil "github.com/filecoin-project/go-cbor-util"
+	"github.com/filecoin-project/go-fil-markets/storagemarket"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/build"
+	"github.com/filecoin-project/lotus/chain/types"
+	sealing "github.com/filecoin-project/lotus/extern/storage-sealing"
+	"github.com/filecoin-project/lotus/lib/sigs"
+	"github.com/filecoin-project/lotus/node/modules/dtypes"
+)
+
+var log = logging.Logger("sealingstatus")
+
+// throughputWindow is how far back deal activations are counted to estimate
+// the throughput of the sealing pipeline
+const throughputWindow = 7 * 24 * time.Hour
+
+const requestTimeout = time.Minute
+
+// activationEvent is the kind of the sector log entry recorded when the
+// sector proof lands on chain, activating its deals
+var activationEvent = fmt.Sprintf("event;%T", sealing.SectorProving{})
+
+// activationPending are the sector states before the sector proof lands on
+// chain, including the failed states sealing is retried from
+var activationPending = map[sealing.SectorState]bool{
+	sealing.UndefinedSectorState:  true,
+	sealing.Empty:                 true,
+	sealing.WaitDeals:             true,
+	sealing.AddPiece:              true,
+	sealing.AddPieceFailed:        true,
+	sealing.Packing:               true,
+	sealing.PackingFailed:         true,
+	sealing.GetTicket:             true,
+	sealing.PreCommit1:            true,
+	sealing.PreCommit2:            true,
+	sealing.SealPreCommit1Failed:  true,
+	sealing.SealPreCommit2Failed:  true,
+	sealing.PreCommitting:         true,
+	sealing.PreCommitWait:         true,
+	sealing.PreCommitFailed:       true,
+	sealing.SubmitPreCommitBatch:  true,
+	sealing.PreCommitBatchWait:    true,
+	sealing.WaitSeed:              true,
+	sealing.Committing:            true,
+	sealing.ComputeProofFailed:    true,
+	sealing.CommitFinalize:        true,
+	sealing.CommitFinalizeFailed:  true,
+	sealing.SubmitCommit:          true,
+	sealing.CommitWait:            true,
+	sealing.CommitFailed:          true,
+	sealing.SubmitCommitAggregate: true,
+	sealing.CommitAggregateWait:   true,
+}
+
+// ProviderAPI is the node API used by the Provider
+type ProviderAPI interface {
+	StateAccountKey(context.Context, address.Address, types.TipSetKey) (address.Address, error)
+}
+
+type DealLister interface {
+	ListLocalDeals() ([]storagemarket.MinerDeal, error)
+}
+
+type SectorLister interface {
+	ListSectors() ([]sealing.SectorInfo, error)
+}
+
+// Provider answers sealing status requests from the clients of the deals
+type Provider struct {
+	api      ProviderAPI
+	deals    DealLister
+	sectors  SectorLister
+	disclose dtypes.DiscloseSealingStatusFunc
+}
+
+func NewProvider(api ProviderAPI, deals DealLister, sectors SectorLister, disclose dtypes.DiscloseSealingStatusFunc) *Provider {
+	return &Provider{
+		api:      api,
+		deals:    deals,
+		sectors:  sectors,
+		disclose: disclose,
+	}
+}
+
+// Status returns the sealing status of the deal, whether it is disclosed to
+// the client or not
+func (p *Provider) Status(ctx context.Context, proposal cid.Cid) (*api.DealSealingStatus, error) {
+	deal, err := p.findDeal(proposal)
+	if err != nil {
+		return nil, err
+	}
+	return p.status(deal)
+}
+
+func (p *Provider) findDeal(proposal cid.Cid) (storagemarket.MinerDeal, error) {
+	deals, err := p.deals.ListLocalDeals()
+	if err != nil {
+		return storagemarket.MinerDeal{}, xerrors.Errorf("listing deals: %w", err)
+	}
+	for _, d := range deals {
+		if d.ProposalCid == proposal {
+			return d, nil
+		}
+	}
+	return storagemarket.MinerDeal{}, xerrors.Errorf("deal %s not found", proposal)
+}
+
+func (p *Provider) status(deal storagemarket.MinerDeal) (*api.DealSealingStatus, error) {
+	sectors, err := p.sectors.ListSectors()
+	if err != nil {
+		return nil, xerrors.Errorf("listing sectors: %w", err)
+	}
+
+	st := sealingStatus(deal, sectors, build.Clock.Now())
+	return &st, nil
+}
+
+func (p *Provider) HandleStream(s inet.Stream) {
+	defer s.Close() //nolint:errcheck
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	var req Request
+	if err := cborutil.ReadCborRPC(s, &req); err != nil {
+		log.Debugw("failed to read sealing status request", "peer", s.Conn().RemotePeer(), "error", err)
+		return
+	}
+
+	resp, err := p.respond(ctx, &req)
+	if err != nil {
+		log.Debugw("sealing status request failed", "peer", s.Conn().RemotePeer(), "proposal", req.Proposal, "error", err)
+		resp = &Response{Error: err.Error()}
+	}
+
+	if err := cborutil.WriteCborRPC(s, resp); err != nil {
+		log.Debugw("failed to write sealing status response", "peer", s.Conn().RemotePeer(), "error", err)
+	}
+}
+
+func (p *Provider) respond(ctx context.Context, req *Request) (*Response, error) {
+	disclose, err := p.disclose()
+	if err != nil {
+		log.Errorf("reading sealing status disclosure config: %s", err)
+		return nil, xerrors.Errorf("internal error")
+	}
+	if !disclose {
+		return &Response{Disclosed: false}, nil
+	}
+
+	deal, err := p.findDeal(req.Proposal)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := p.api.StateAccountKey(ctx, deal.Proposal.Client, types.EmptyTSK)
+	if err != nil {
+		return nil, xerrors.Errorf("resolving client address: %w", err)
+	}
+	if err := sigs.Verify(&req.Signature, client, req.Proposal.Bytes()); err != nil {
+		return nil, xerrors.Errorf("request not signed by the deal client")
+	}
+
+	st, err := p.status(deal)
+	if err != nil {
+		log.Errorf("getting sealing status of deal %s: %s", req.Proposal, err)
+		return nil, xerrors.Errorf("internal error")
+	}
+	return newResponse(st), nil
+}
+
+// sealingStatus locates the sector of the deal in the sealing pipeline. The
+// ETA assumes sectors ahead in the pipeline activate at the rate they did
+// over the throughput window, and that the sector of the deal takes at
+// least the average time sectors took to activate.
+func sealingStatus(deal storagemarket.MinerDeal, sectors []sealing.SectorInfo, now time.Time) api.DealSealingStatus {
+	out := api.DealSealingStatus{Disclosed: true}
+
+	var (
+		dealSector    *sealing.SectorInfo
+		queue         []*sealing.SectorInfo
+		activations   int
+		activationDur time.Duration
+	)
+	for i := range sectors {
+		si := &sectors[i]
+		if deal.DealID != 0 && si.SectorNumber == deal.SectorNumber && hasDeal(si, deal) {
+			dealSector = si
+		}
+
+		if activationPending[si.State] {
+			queue = append(queue, si)
+			continue
+		}
+
+		at, ok := lastLog(si, activationEvent)
+		if !ok || now.Sub(at) > throughputWindow {
+			continue
+		}
+		activations++
+		activationDur += at.Sub(sectorStart(si))
+	}
+
+	out.QueueLength = len(queue)
+	out.QueuePosition = len(queue)
+	if dealSector != nil {
+		out.Sector = dealSector.SectorNumber
+		out.SectorState = string(dealSector.State)
+		if !activationPending[dealSector.State] {
+			// already active, or sealing failed for good
+			out.QueuePosition = 0
+			return out
+		}
+
+		out.QueuePosition = 0
+		for _, si := range queue {
+			if si.SectorNumber < dealSector.SectorNumber {
+				out.QueuePosition++
+			}
+		}
+	}
+
+	if activations == 0 {
+		return out
+	}
+
+	wait := throughputWindow / time.Duration(activations) * time.Duration(out.QueuePosition+1)
+	remaining := activationDur / time.Duration(activations)
+	if dealSector != nil {
+		remaining -= now.Sub(sectorStart(dealSector))
+	}
+	if remaining > wait {
+		wait = remaining
+	}
+	out.ETA = now.Add(wait)
+
+	return out
+}
+
+func hasDeal(si *sealing.SectorInfo, deal storagemarket.MinerDeal) bool {
+	for _, p := range si.Pieces {
+		if p.DealInfo != nil && p.DealInfo.DealID == deal.DealID {
+			return true
+		}
+	}
+	return false
+}
+
+func sectorStart(si *sealing.SectorInfo) time.Time {
+	if len(si.Log) == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(si.Log[0].Timestamp), 0)
+}
+
+func lastLog(si *sealing.SectorInfo, kind string) (time.Time, bool) {
+	for i := len(si.Log) - 1; i >= 0; i-- {
+		if si.Log[i].Kind == kind {
+			return time.Unix(int64(si.Log[i].Timestamp), 0), true
+		}
+	}
+	return time.Time{}, false
+}
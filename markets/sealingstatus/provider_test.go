@@ -0,0 +1,96 @@
+package sealingstatus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/go-fil-markets/storagemarket"
+	"github.com/filecoin-project/go-state-types/abi"
+
+	sealing "github.com/filecoin-project/lotus/extern/storage-sealing"
+)
+
+func TestSealingStatus(t *testing.T) {
+	now := time.Unix(1_600_000_000, 0)
+	at := func(ago time.Duration) uint64 { return uint64(now.Add(-ago).Unix()) }
+
+	withDeal := func(si sealing.SectorInfo, id abi.DealID) sealing.SectorInfo {
+		si.Pieces = append(si.Pieces, sealing.Piece{DealInfo: &sealing.DealInfo{DealID: id}})
+		return si
+	}
+
+	// one sector activated every hour over the throughput window, each
+	// taking 8h from start to activation
+	var sectors []sealing.SectorInfo
+	for i := 0; i < int(throughputWindow/time.Hour); i++ {
+		activated := time.Duration(i)*time.Hour + time.Minute
+		sectors = append(sectors, sealing.SectorInfo{
+			SectorNumber: abi.SectorNumber(i + 1),
+			State:        sealing.Proving,
+			Log: []sealing.Log{
+				{Timestamp: at(activated + 8*time.Hour), Kind: "event;sealing.SectorStart"},
+				{Timestamp: at(activated), Kind: activationEvent},
+			},
+		})
+	}
+	// activated before the window, not counted
+	sectors = append(sectors, sealing.SectorInfo{
+		SectorNumber: 500,
+		State:        sealing.Proving,
+		Log: []sealing.Log{
+			{Timestamp: at(throughputWindow + 2*time.Hour)},
+			{Timestamp: at(throughputWindow + time.Hour), Kind: activationEvent},
+		},
+	})
+	// 20 sectors in the pipeline, started an hour ago
+	for i := 0; i < 20; i++ {
+		sectors = append(sectors, sealing.SectorInfo{
+			SectorNumber: abi.SectorNumber(200 + i),
+			State:        sealing.PreCommit1,
+			Log:          []sealing.Log{{Timestamp: at(time.Hour)}},
+		})
+	}
+	sectors[len(sectors)-1] = withDeal(sectors[len(sectors)-1], 42)
+	sectors[len(sectors)-18] = withDeal(sectors[len(sectors)-18], 43)
+	sectors[0] = withDeal(sectors[0], 44)
+
+	t.Run("end-of-queue", func(t *testing.T) {
+		st := sealingStatus(storagemarket.MinerDeal{DealID: 42, SectorNumber: 219}, sectors, now)
+		require.True(t, st.Disclosed)
+		require.Equal(t, abi.SectorNumber(219), st.Sector)
+		require.Equal(t, string(sealing.PreCommit1), st.SectorState)
+		require.Equal(t, 19, st.QueuePosition)
+		require.Equal(t, 20, st.QueueLength)
+		// bound by the throughput, one sector per hour
+		require.Equal(t, now.Add(20*time.Hour), st.ETA)
+	})
+
+	t.Run("front-of-queue", func(t *testing.T) {
+		st := sealingStatus(storagemarket.MinerDeal{DealID: 43, SectorNumber: 202}, sectors, now)
+		require.Equal(t, 2, st.QueuePosition)
+		// bound by the 8h the sector takes to seal, 1h of which elapsed
+		require.Equal(t, now.Add(7*time.Hour), st.ETA)
+	})
+
+	t.Run("not-in-sector", func(t *testing.T) {
+		st := sealingStatus(storagemarket.MinerDeal{}, sectors, now)
+		require.Empty(t, st.SectorState)
+		require.Equal(t, 20, st.QueuePosition)
+		require.Equal(t, now.Add(21*time.Hour), st.ETA)
+	})
+
+	t.Run("active", func(t *testing.T) {
+		st := sealingStatus(storagemarket.MinerDeal{DealID: 44, SectorNumber: 1}, sectors, now)
+		require.Equal(t, string(sealing.Proving), st.SectorState)
+		require.Equal(t, 0, st.QueuePosition)
+		require.True(t, st.ETA.IsZero())
+	})
+
+	t.Run("no-history", func(t *testing.T) {
+		st := sealingStatus(storagemarket.MinerDeal{DealID: 42, SectorNumber: 219}, sectors[len(sectors)-20:], now)
+		require.Equal(t, 19, st.QueuePosition)
+		require.True(t, st.ETA.IsZero())
+	})
+}
@@ -41,6 +41,7 @@ var (
 	MsgValid, _     = tag.NewKey("message_valid")
 	Endpoint, _     = tag.NewKey("endpoint")
 	APIInterface, _ = tag.NewKey("api") // to distinguish between gateway api and full node api endpoint calls
+	PushOutcome, _  = tag.NewKey("push_outcome")
 
 	// miner
 	TaskType, _       = tag.NewKey("task_type")
@@ -68,6 +69,8 @@ var (
 	MpoolAddTsDuration                  = stats.Float64("mpool/addts_ms", "Duration of addTs in mpool", stats.UnitMilliseconds)
 	MpoolAddDuration                    = stats.Float64("mpool/add_ms", "Duration of Add in mpool", stats.UnitMilliseconds)
 	MpoolPushDuration                   = stats.Float64("mpool/push_ms", "Duration of Push in mpool", stats.UnitMilliseconds)
+	MpoolPushMessage                    = stats.Int64("mpool/push_message", "Counter for MpoolPushMessage calls, by outcome", stats.UnitDimensionless)
+	MpoolPushMessageEstimateToPush      = stats.Float64("mpool/push_message_estimate_to_push_ms", "Duration from gas estimation to the push of messages sent with MpoolPushMessage", stats.UnitMilliseconds)
 	BlockPublished                      = stats.Int64("block/published", "Counter for total locally published blocks", stats.UnitDimensionless)
 	BlockReceived                       = stats.Int64("block/received", "Counter for total received blocks", stats.UnitDimensionless)
 	BlockValidationFailure              = stats.Int64("block/failure", "Counter for block validation failures", stats.UnitDimensionless)
@@ -103,6 +106,12 @@ var (
 	SplitstoreCompactionHot         = stats.Int64("splitstore/hot", "Number of hot blocks in last compaction", stats.UnitDimensionless)
 	SplitstoreCompactionCold        = stats.Int64("splitstore/cold", "Number of cold blocks in last compaction", stats.UnitDimensionless)
 	SplitstoreCompactionDead        = stats.Int64("splitstore/dead", "Number of dead blocks in last compaction", stats.UnitDimensionless)
+
+	// watched actors
+	WatchedActorsHit             = stats.Int64("watched_actors/hit", "Counter for state lookups served from the watched actor cache", stats.UnitDimensionless)
+	WatchedActorsMiss            = stats.Int64("watched_actors/miss", "Counter for state lookups of watched actors not served from the cache", stats.UnitDimensionless)
+	WatchedActorsCount           = stats.Int64("watched_actors/count", "Number of watched actors", stats.UnitDimensionless)
+	WatchedActorsRefreshDuration = stats.Float64("watched_actors/refresh_ms", "Duration of refreshing watched actors on head change", stats.UnitMilliseconds)
 )
 
 var (
@@ -202,6 +211,15 @@ var (
 		Measure:     MpoolPushDuration,
 		Aggregation: defaultMillisecondsDistribution,
 	}
+	MpoolPushMessageView = &view.View{
+		Measure:     MpoolPushMessage,
+		Aggregation: view.Count(),
+		TagKeys:     []tag.Key{PushOutcome},
+	}
+	MpoolPushMessageEstimateToPushView = &view.View{
+		Measure:     MpoolPushMessageEstimateToPush,
+		Aggregation: defaultMillisecondsDistribution,
+	}
 	PeerCountView = &view.View{
 		Measure:     PeerCount,
 		Aggregation: view.LastValue(),
@@ -318,6 +336,24 @@ var (
 		Measure:     SplitstoreCompactionDead,
 		Aggregation: view.Sum(),
 	}
+
+	// watched actors
+	WatchedActorsHitView = &view.View{
+		Measure:     WatchedActorsHit,
+		Aggregation: view.Count(),
+	}
+	WatchedActorsMissView = &view.View{
+		Measure:     WatchedActorsMiss,
+		Aggregation: view.Count(),
+	}
+	WatchedActorsCountView = &view.View{
+		Measure:     WatchedActorsCount,
+		Aggregation: view.LastValue(),
+	}
+	WatchedActorsRefreshDurationView = &view.View{
+		Measure:     WatchedActorsRefreshDuration,
+		Aggregation: defaultMillisecondsDistribution,
+	}
 )
 
 // DefaultViews is an array of OpenCensus views for metric gathering purposes
@@ -351,6 +387,8 @@ var ChainNodeViews = append([]*view.View{
 	MpoolAddTsDurationView,
 	MpoolAddDurationView,
 	MpoolPushDurationView,
+	MpoolPushMessageView,
+	MpoolPushMessageEstimateToPushView,
 	PubsubPublishMessageView,
 	PubsubDeliverMessageView,
 	PubsubRejectMessageView,
@@ -365,6 +403,10 @@ var ChainNodeViews = append([]*view.View{
 	SplitstoreCompactionHotView,
 	SplitstoreCompactionColdView,
 	SplitstoreCompactionDeadView,
+	WatchedActorsHitView,
+	WatchedActorsMissView,
+	WatchedActorsCountView,
+	WatchedActorsRefreshDurationView,
 	VMApplyBlocksTotalView,
 	VMApplyMessagesView,
 	VMApplyEarlyView,
@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+func TestViewsRegisteredOnce(t *testing.T) {
+	for name, views := range map[string][]*view.View{
+		"chain": ChainNodeViews,
+		"miner": MinerNodeViews,
+	} {
+		seen := map[string]bool{}
+		for _, v := range views {
+			n := v.Name
+			if n == "" {
+				n = v.Measure.Name()
+			}
+			require.False(t, seen[n], "%s view %s listed twice", name, n)
+			seen[n] = true
+		}
+	}
+
+	require.NoError(t, view.Register(ChainNodeViews...))
+	defer view.Unregister(ChainNodeViews...)
+
+	ctx, err := tag.New(context.Background(), tag.Upsert(PushOutcome, "denied"))
+	require.NoError(t, err)
+	stats.Record(ctx, MpoolPushMessage.M(1))
+	stats.Record(ctx, MpoolPushMessage.M(1))
+
+	rows, err := view.RetrieveData(MpoolPushMessageView.Measure.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, []tag.Tag{{Key: PushOutcome, Value: "denied"}}, rows[0].Tags)
+	require.Equal(t, int64(2), rows[0].Data.(*view.CountData).Value)
+}
@@ -0,0 +1,205 @@
+package miner
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/lotus/build"
+	"github.com/filecoin-project/lotus/node/modules/dtypes"
+)
+
+// EnvDevnetTimeTravel makes a devnet miner keep the gap between its chain and
+// the clock when it starts, instead of catching up, for AdvanceEpochs to
+// advance through. Start the devnet from a genesis dated in the past to have
+// a gap to keep.
+const EnvDevnetTimeTravel = "LOTUS_DEVNET_TIME_TRAVEL"
+
+// publicNetworks are the names of the recognized public networks, which
+// epochs are never advanced on whatever the build
+var publicNetworks = map[dtypes.NetworkName]bool{
+	"testnetnet":     true, // mainnet
+	"calibrationnet": true,
+	"butterflynet":   true,
+	"nerpanet":       true,
+	"interopnet":     true,
+}
+
+// CheckDevnet returns an error unless the node is a 2k or debug build running
+// a local network
+func CheckDevnet(netName dtypes.NetworkName) error {
+	if build.BuildType != build.Build2k && build.BuildType != build.BuildDebug {
+		return xerrors.Errorf("epochs can only be advanced by 2k or debug builds, this is a %q build", build.UserVersion())
+	}
+	return checkLocalNetwork(netName)
+}
+
+func checkLocalNetwork(netName dtypes.NetworkName) error {
+	if publicNetworks[netName] {
+		return xerrors.Errorf("epochs can't be advanced on the public network %s", netName)
+	}
+	return nil
+}
+
+// checkDevnet returns an error unless the miner mines a local devnet. Test
+// miners only ever mine the local networks of tests, whatever the build.
+func (m *Miner) checkDevnet(ctx context.Context) error {
+	netName, err := m.api.StateNetworkName(ctx)
+	if err != nil {
+		return xerrors.Errorf("getting network name: %w", err)
+	}
+	if m.testMiner {
+		return checkLocalNetwork(netName)
+	}
+	return CheckDevnet(netName)
+}
+
+// advanceBudget returns the most epochs the chain can advance by from the
+// head at headTime: blocks past the clock would be rejected as coming from
+// the future
+func advanceBudget(headTime uint64, now time.Time) abi.ChainEpoch {
+	if uint64(now.Unix()) <= headTime {
+		return 0
+	}
+	return abi.ChainEpoch((uint64(now.Unix()) - headTime) / build.BlockDelaySecs)
+}
+
+// EnableTimeTravel makes the miner keep the gap between its chain and the
+// clock when it starts, see EnvDevnetTimeTravel. It must be called before
+// Start.
+func (m *Miner) EnableTimeTravel() {
+	m.advanceLk.Lock()
+	defer m.advanceLk.Unlock()
+	m.timeTravel = true
+}
+
+// startClock sets the lag of the clock the miner paces blocks by, the gap
+// between the head and the clock with time travel, none otherwise
+func (m *Miner) startClock(ctx context.Context) {
+	m.advanceLk.Lock()
+	defer m.advanceLk.Unlock()
+	if !m.timeTravel {
+		return
+	}
+
+	if err := m.checkDevnet(ctx); err != nil {
+		log.Errorf("not keeping the gap to the clock for advancing epochs: %s", err)
+		m.timeTravel = false
+		return
+	}
+
+	head, err := m.api.ChainHead(ctx)
+	if err != nil {
+		log.Errorf("not keeping the gap to the clock for advancing epochs: getting chain head: %s", err)
+		return
+	}
+	if lag := build.Clock.Since(time.Unix(int64(head.MinTimestamp()), 0)); lag > 0 {
+		m.lag = lag
+	}
+	log.Infow("keeping the gap to the clock for advancing epochs", "lag", m.lag, "epochs", advanceBudget(head.MinTimestamp(), build.Clock.Now()))
+}
+
+// now returns the time the miner paces blocks by: the clock, less the gap to
+// it kept for advancing epochs
+func (m *Miner) now() time.Time {
+	m.advanceLk.Lock()
+	defer m.advanceLk.Unlock()
+	return build.Clock.Now().Add(-m.lag)
+}
+
+func (m *Miner) until(t time.Time) time.Duration {
+	return t.Sub(m.now())
+}
+
+// advancing returns whether the miner is advancing epochs past the base,
+// mining without waiting
+func (m *Miner) advancing(base *MiningBase) bool {
+	m.advanceLk.Lock()
+	defer m.advanceLk.Unlock()
+	return base.TipSet.Height()+base.NullRounds < m.advanceTo
+}
+
+// takeAdvanceNulls returns the null rounds to inject advancing epochs, once
+func (m *Miner) takeAdvanceNulls() abi.ChainEpoch {
+	m.advanceLk.Lock()
+	defer m.advanceLk.Unlock()
+	nulls := m.advanceNulls
+	m.advanceNulls = 0
+	return nulls
+}
+
+// AdvanceEpochs mines the next epochs as fast as the miner can, on devnets
+// only, and returns the epoch of the head once there. Each epoch is mined,
+// or null when the miner doesn't win it, so proving deadlines pass the way
+// they would in time. With nulls, the epochs before the last are skipped as
+// null rounds, which is faster but skips the proofs due in them.
+//
+// The chain can't advance past the clock, the daemon rejects blocks from the
+// future: devnets have epochs to advance when their genesis is dated in the
+// past and their miner keeps the gap, see EnvDevnetTimeTravel.
+func (m *Miner) AdvanceEpochs(ctx context.Context, epochs abi.ChainEpoch, nulls bool) (abi.ChainEpoch, error) {
+	if epochs < 1 {
+		return 0, xerrors.Errorf("the epochs to advance by must be positive")
+	}
+	if err := m.checkDevnet(ctx); err != nil {
+		return 0, err
+	}
+
+	head, err := m.api.ChainHead(ctx)
+	if err != nil {
+		return 0, xerrors.Errorf("getting chain head: %w", err)
+	}
+	if budget := advanceBudget(head.MinTimestamp(), build.Clock.Now()); epochs > budget {
+		return 0, xerrors.Errorf("can advance by %d epochs at most before the chain reaches the clock; "+
+			"start the devnet from a genesis dated in the past, with %s=1 for the miner", budget, EnvDevnetTimeTravel)
+	}
+	target := head.Height() + epochs
+
+	m.advanceLk.Lock()
+	if m.advanceTo > head.Height() {
+		m.advanceLk.Unlock()
+		return 0, xerrors.Errorf("already advancing to epoch %d", m.advanceTo)
+	}
+	m.advanceTo = target
+	if nulls {
+		m.advanceNulls = epochs - 1
+	}
+	// the clock the miner paces by jumps to the last epoch advanced, so it
+	// carries on from there
+	if m.timeTravel {
+		m.lag = build.Clock.Since(time.Unix(int64(head.MinTimestamp()+uint64(epochs)*build.BlockDelaySecs), 0))
+	}
+	m.advanceLk.Unlock()
+
+	// the mining loop may be waiting on the clock, or on a test's MineOne
+	select {
+	case m.advanceWake <- struct{}{}:
+	default:
+	}
+
+	defer func() {
+		m.advanceLk.Lock()
+		m.advanceTo, m.advanceNulls = 0, 0
+		m.advanceLk.Unlock()
+	}()
+
+	log.Infow("advancing epochs", "from", head.Height(), "to", target, "nulls", nulls)
+	for {
+		head, err := m.api.ChainHead(ctx)
+		if err != nil {
+			return 0, xerrors.Errorf("getting chain head: %w", err)
+		}
+		if head.Height() >= target {
+			return head.Height(), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, xerrors.Errorf("advancing to epoch %d, at %d: %w", target, head.Height(), ctx.Err())
+		case <-build.Clock.After(100 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package miner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/lotus/build"
+	"github.com/filecoin-project/lotus/node/modules/dtypes"
+)
+
+func TestCheckDevnet(t *testing.T) {
+	defer func(bt int) { build.BuildType = bt }(build.BuildType)
+
+	build.BuildType = build.BuildMainnet
+	require.Error(t, CheckDevnet("localnet"))
+
+	build.BuildType = build.Build2k
+	require.NoError(t, CheckDevnet("localnet"))
+	for _, netName := range []string{"testnetnet", "calibrationnet", "butterflynet"} {
+		require.Error(t, CheckDevnet(dtypes.NetworkName(netName)), netName)
+	}
+}
+
+func TestAdvanceBudget(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+	head := uint64(now.Unix()) - 10*build.BlockDelaySecs - 1
+
+	require.Equal(t, abi.ChainEpoch(10), advanceBudget(head, now))
+	require.Equal(t, abi.ChainEpoch(0), advanceBudget(uint64(now.Unix()), now))
+	require.Equal(t, abi.ChainEpoch(0), advanceBudget(uint64(now.Unix())+60, now))
+}
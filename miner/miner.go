@@ -67,38 +67,43 @@ func NewMiner(api v1api.FullNode, epp gen.WinningPoStProver, addr address.Addres
 		panic(err)
 	}
 
-	return &Miner{
+	m := &Miner{
 		api:     api,
 		epp:     epp,
 		address: addr,
-		waitFunc: func(ctx context.Context, baseTime uint64) (func(bool, abi.ChainEpoch, error), abi.ChainEpoch, error) {
-			// wait around for half the block time in case other parents come in
-			//
-			// if we're mining a block in the past via catch-up/rush mining,
-			// such as when recovering from a network halt, this sleep will be
-			// for a negative duration, and therefore **will return
-			// immediately**.
-			//
-			// the result is that we WILL NOT wait, therefore fast-forwarding
-			// and thus healing the chain by backfilling it with null rounds
-			// rapidly.
-			deadline := baseTime + build.PropagationDelaySecs
-			baseT := time.Unix(int64(deadline), 0)
-
-			baseT = baseT.Add(randTimeOffset(time.Second))
-
-			build.Clock.Sleep(build.Clock.Until(baseT))
-
-			return func(bool, abi.ChainEpoch, error) {}, 0, nil
-		},
 
 		sf:                sf,
 		minedBlockHeights: arc,
 		evtTypes: [...]journal.EventType{
 			evtTypeBlockMined: j.RegisterEventType("miner", "block_mined"),
 		},
-		journal: j,
+		journal:     j,
+		advanceWake: make(chan struct{}, 1),
+	}
+	m.waitFunc = func(ctx context.Context, baseTime uint64) (func(bool, abi.ChainEpoch, error), abi.ChainEpoch, error) {
+		// wait around for half the block time in case other parents come in
+		//
+		// if we're mining a block in the past via catch-up/rush mining,
+		// such as when recovering from a network halt, this sleep will be
+		// for a negative duration, and therefore **will return
+		// immediately**.
+		//
+		// the result is that we WILL NOT wait, therefore fast-forwarding
+		// and thus healing the chain by backfilling it with null rounds
+		// rapidly.
+		deadline := baseTime + build.PropagationDelaySecs
+		baseT := time.Unix(int64(deadline), 0)
+
+		baseT = baseT.Add(randTimeOffset(time.Second))
+
+		select {
+		case <-build.Clock.After(m.until(baseT)):
+		case <-m.advanceWake:
+		}
+
+		return func(bool, abi.ChainEpoch, error) {}, 0, nil
 	}
+	return m
 }
 
 // Miner encapsulates the mining processes of the system.
@@ -127,6 +132,22 @@ type Miner struct {
 
 	evtTypes [1]journal.EventType
 	journal  journal.Journal
+
+	// advanceLk guards the state of advancing epochs: the lag of the clock
+	// blocks are paced by when time travel is enabled, and the epoch mined
+	// up to with the null rounds to inject on the way
+	advanceLk    sync.Mutex
+	testMiner    bool
+	timeTravel   bool
+	lag          time.Duration
+	advanceTo    abi.ChainEpoch
+	advanceNulls abi.ChainEpoch
+	// advanceWake wakes the mining loop from waiting to mine the next round
+	// once advancing epochs
+	advanceWake chan struct{}
+
+	simLk   sync.Mutex
+	lastSim *api.BlockSimulation
 }
 
 // Address returns the address of the miner.
@@ -139,12 +160,13 @@ func (m *Miner) Address() address.Address {
 
 // Start starts the mining operation. It spawns a goroutine and returns
 // immediately. Start is not idempotent.
-func (m *Miner) Start(_ context.Context) error {
+func (m *Miner) Start(ctx context.Context) error {
 	m.lk.Lock()
 	defer m.lk.Unlock()
 	if m.stop != nil {
 		return fmt.Errorf("miner already started")
 	}
+	m.startClock(ctx)
 	m.stop = make(chan struct{})
 	go m.mine(context.TODO())
 	return nil
@@ -181,26 +203,26 @@ func (m *Miner) niceSleep(d time.Duration) bool {
 
 // mine runs the mining loop. It performs the following:
 //
-//  1.  Queries our current best currently-known mining candidate (tipset to
-//      build upon).
-//  2.  Waits until the propagation delay of the network has elapsed (currently
-//      6 seconds). The waiting is done relative to the timestamp of the best
-//      candidate, which means that if it's way in the past, we won't wait at
-//      all (e.g. in catch-up or rush mining).
-//  3.  After the wait, we query our best mining candidate. This will be the one
-//      we'll work with.
-//  4.  Sanity check that we _actually_ have a new mining base to mine on. If
-//      not, wait one epoch + propagation delay, and go back to the top.
-//  5.  We attempt to mine a block, by calling mineOne (refer to godocs). This
-//      method will either return a block if we were eligible to mine, or nil
-//      if we weren't.
-//  6a. If we mined a block, we update our state and push it out to the network
-//      via gossipsub.
-//  6b. If we didn't mine a block, we consider this to be a nil round on top of
-//      the mining base we selected. If other miner or miners on the network
-//      were eligible to mine, we will receive their blocks via gossipsub and
-//      we will select that tipset on the next iteration of the loop, thus
-//      discarding our null round.
+//  1. Queries our current best currently-known mining candidate (tipset to
+//     build upon).
+//  2. Waits until the propagation delay of the network has elapsed (currently
+//     6 seconds). The waiting is done relative to the timestamp of the best
+//     candidate, which means that if it's way in the past, we won't wait at
+//     all (e.g. in catch-up or rush mining).
+//  3. After the wait, we query our best mining candidate. This will be the one
+//     we'll work with.
+//  4. Sanity check that we _actually_ have a new mining base to mine on. If
+//     not, wait one epoch + propagation delay, and go back to the top.
+//  5. We attempt to mine a block, by calling mineOne (refer to godocs). This
+//     method will either return a block if we were eligible to mine, or nil
+//     if we weren't.
+//     6a. If we mined a block, we update our state and push it out to the network
+//     via gossipsub.
+//     6b. If we didn't mine a block, we consider this to be a nil round on top of
+//     the mining base we selected. If other miner or miners on the network
+//     were eligible to mine, we will receive their blocks via gossipsub and
+//     we will select that tipset on the next iteration of the loop, thus
+//     discarding our null round.
 func (m *Miner) mine(ctx context.Context) {
 	ctx, span := trace.StartSpan(ctx, "/mine")
 	defer span.End()
@@ -250,11 +272,16 @@ minerLoop:
 			// that when the test 'MineOne' function is triggered, we pull our
 			// best mining candidate at that time.
 
-			// Wait until propagation delay period after block we plan to mine on
-			onDone, injectNulls, err = m.waitFunc(ctx, prebase.TipSet.MinTimestamp())
-			if err != nil {
-				log.Error(err)
-				continue
+			// Wait until propagation delay period after block we plan to mine on,
+			// unless advancing epochs
+			if m.advancing(prebase) {
+				onDone, injectNulls = func(bool, abi.ChainEpoch, error) {}, 0
+			} else {
+				onDone, injectNulls, err = m.waitFunc(ctx, prebase.TipSet.MinTimestamp())
+				if err != nil {
+					log.Error(err)
+					continue
+				}
 			}
 
 			// just wait for the beacon entry to become available before we select our final mining base
@@ -271,10 +298,16 @@ minerLoop:
 		}
 
 		base.NullRounds += injectNulls // testing
+		base.NullRounds += m.takeAdvanceNulls()
 
 		if base.TipSet.Equals(lastBase.TipSet) && lastBase.NullRounds == base.NullRounds {
 			log.Warnf("BestMiningCandidate from the previous round: %s (nulls:%d)", lastBase.TipSet.Cids(), lastBase.NullRounds)
-			if !m.niceSleep(time.Duration(build.BlockDelaySecs) * time.Second) {
+			wait := time.Duration(build.BlockDelaySecs) * time.Second
+			if m.advancing(base) {
+				// the block just mined is yet to become the head
+				wait = 100 * time.Millisecond
+			}
+			if !m.niceSleep(wait) {
 				continue minerLoop
 			}
 			continue
@@ -309,14 +342,14 @@ minerLoop:
 			})
 
 			btime := time.Unix(int64(b.Header.Timestamp), 0)
-			now := build.Clock.Now()
+			now := m.now()
 			switch {
 			case btime == now:
 				// block timestamp is perfectly aligned with time.
 			case btime.After(now):
-				if !m.niceSleep(build.Clock.Until(btime)) {
+				if !m.niceSleep(m.until(btime)) {
 					log.Warnf("received interrupt while waiting to broadcast block, will shutdown after block is sent out")
-					build.Clock.Sleep(build.Clock.Until(btime))
+					build.Clock.Sleep(m.until(btime))
 				}
 			default:
 				log.Warnw("mined block in the past",
@@ -349,7 +382,7 @@ minerLoop:
 			nextRound := time.Unix(int64(base.TipSet.MinTimestamp()+build.BlockDelaySecs*uint64(base.NullRounds))+int64(build.PropagationDelaySecs), 0)
 
 			select {
-			case <-build.Clock.After(build.Clock.Until(nextRound)):
+			case <-build.Clock.After(m.until(nextRound)):
 			case <-m.stop:
 				stopping := m.stopping
 				m.stop = nil
@@ -416,7 +449,7 @@ func (m *Miner) GetBestMiningCandidate(ctx context.Context) (*MiningBase, error)
 //
 // This method does the following:
 //
-//  1.
+//	1.
 func (m *Miner) mineOne(ctx context.Context, base *MiningBase) (minedBlock *types.BlockMsg, err error) {
 	log.Debugw("attempting to mine a block", "tipset", types.LogCids(base.TipSet.Cids()))
 	start := build.Clock.Now()
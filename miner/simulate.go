@@ -0,0 +1,234 @@
+package miner
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/crypto"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/build"
+	"github.com/filecoin-project/lotus/chain/actors/builtin"
+	"github.com/filecoin-project/lotus/chain/gen"
+	"github.com/filecoin-project/lotus/chain/store"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/lib/sigs"
+)
+
+// winningPoStVerifier is implemented by the WinningPoSt provers which can
+// verify their proofs
+type winningPoStVerifier interface {
+	VerifyProof(ctx context.Context, ssi []builtin.SectorInfo, rand abi.PoStRandomness, proofs []builtin.PoStProof) (bool, error)
+}
+
+// blockSimulation times the stages of a simulated mining round
+type blockSimulation struct {
+	res *api.BlockSimulation
+}
+
+// stage runs a stage of the round, recording how long it took and its
+// error, if any
+func (s *blockSimulation) stage(name string, run func() error) error {
+	start := build.Clock.Now()
+	err := run()
+	st := api.BlockSimulationStage{Name: name, Took: build.Clock.Since(start)}
+	if err != nil {
+		st.Error = err.Error()
+		s.res.Error = xerrors.Errorf("%s: %w", name, err).Error()
+	}
+	s.res.Stages = append(s.res.Stages, st)
+	return err
+}
+
+// Simulate runs a mining round on the head as if the miner won it, without
+// broadcasting the block: it signs the ticket and election proof with the
+// worker key, computes a WinningPoSt for a random challenge, assembles a block
+// with the messages the mpool selects, and validates it. The result is kept
+// for LastSimulation.
+func (m *Miner) Simulate(ctx context.Context) *api.BlockSimulation {
+	s := &blockSimulation{
+		res: &api.BlockSimulation{
+			Start:  build.Clock.Now(),
+			Budget: time.Duration(build.BlockDelaySecs-build.PropagationDelaySecs) * time.Second,
+		},
+	}
+	m.simulate(ctx, s)
+	s.res.Took = build.Clock.Since(s.res.Start)
+
+	if s.res.Error != "" {
+		log.Errorw("block production simulation failed", "epoch", s.res.Epoch, "took", s.res.Took, "error", s.res.Error)
+	} else if s.res.Took > s.res.Budget {
+		log.Warnw("block production simulation took longer than the time blocks have to propagate", "epoch", s.res.Epoch, "took", s.res.Took, "budget", s.res.Budget)
+	} else {
+		log.Infow("block production simulation succeeded", "epoch", s.res.Epoch, "took", s.res.Took, "budget", s.res.Budget)
+	}
+
+	m.simLk.Lock()
+	m.lastSim = s.res
+	m.simLk.Unlock()
+	return s.res
+}
+
+// LastSimulation returns the result of the last simulated mining round, nil
+// if none ran
+func (m *Miner) LastSimulation() *api.BlockSimulation {
+	m.simLk.Lock()
+	defer m.simLk.Unlock()
+	return m.lastSim
+}
+
+func (m *Miner) simulate(ctx context.Context, s *blockSimulation) {
+	var (
+		base  *MiningBase
+		mbi   *api.MiningBaseInfo
+		rbase types.BeaconEntry
+		round abi.ChainEpoch
+	)
+	if s.stage("base info", func() error {
+		head, err := m.api.ChainHead(ctx)
+		if err != nil {
+			return xerrors.Errorf("getting chain head: %w", err)
+		}
+		base = &MiningBase{TipSet: head}
+		round = head.Height() + 1
+		s.res.Base = head.Key()
+		s.res.Epoch = round
+
+		mbi, err = m.api.MinerGetBaseInfo(ctx, m.address, round, head.Key())
+		if err != nil {
+			return xerrors.Errorf("getting mining base info: %w", err)
+		}
+		if mbi == nil || !mbi.EligibleForMining {
+			return xerrors.Errorf("miner %s isn't eligible to mine at epoch %d, it has no power or too little", m.address, round)
+		}
+		if len(mbi.Sectors) == 0 {
+			return xerrors.Errorf("no sectors to prove")
+		}
+
+		rbase = mbi.PrevBeaconEntry
+		if len(mbi.BeaconEntries) > 0 {
+			rbase = mbi.BeaconEntries[len(mbi.BeaconEntries)-1]
+		}
+		return nil
+	}) != nil {
+		return
+	}
+
+	var ticket *types.Ticket
+	if s.stage("ticket", func() (err error) {
+		ticket, err = m.computeTicket(ctx, &rbase, base, mbi)
+		if err != nil {
+			return xerrors.Errorf("signing with worker key %s: %w", mbi.WorkerKey, err)
+		}
+		return nil
+	}) != nil {
+		return
+	}
+
+	var eproof *types.ElectionProof
+	if s.stage("election proof", func() error {
+		buf := new(bytes.Buffer)
+		if err := m.address.MarshalCBOR(buf); err != nil {
+			return err
+		}
+		electionRand, err := store.DrawRandomness(rbase.Data, crypto.DomainSeparationTag_ElectionProofProduction, round, buf.Bytes())
+		if err != nil {
+			return xerrors.Errorf("drawing randomness: %w", err)
+		}
+		vrfout, err := gen.ComputeVRF(ctx, m.api.WalletSign, mbi.WorkerKey, electionRand)
+		if err != nil {
+			return xerrors.Errorf("signing with worker key %s: %w", mbi.WorkerKey, err)
+		}
+		// the round is simulated as won
+		eproof = &types.ElectionProof{VRFProof: vrfout, WinCount: 1}
+		return nil
+	}) != nil {
+		return
+	}
+
+	var (
+		prand abi.PoStRandomness = make([]byte, 32)
+		proof []builtin.PoStProof
+	)
+	if s.stage("winning post", func() (err error) {
+		if _, err := rand.Read(prand); err != nil {
+			return xerrors.Errorf("drawing the challenge: %w", err)
+		}
+		proof, err = m.epp.ComputeProof(ctx, mbi.Sectors, prand)
+		return err
+	}) != nil {
+		return
+	}
+
+	if v, ok := m.epp.(winningPoStVerifier); ok {
+		if s.stage("verify winning post", func() error {
+			ok, err := v.VerifyProof(ctx, mbi.Sectors, prand, proof)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return xerrors.Errorf("the winning post proof is invalid")
+			}
+			return nil
+		}) != nil {
+			return
+		}
+	}
+
+	var msgs []*types.SignedMessage
+	if s.stage("select messages", func() (err error) {
+		msgs, err = m.api.MpoolSelect(ctx, base.TipSet.Key(), ticket.Quality())
+		return err
+	}) != nil {
+		return
+	}
+	s.res.Messages = len(msgs)
+
+	var blk *types.BlockMsg
+	if s.stage("create block", func() (err error) {
+		blk, err = m.createBlock(base, m.address, ticket, eproof, mbi.BeaconEntries, proof, msgs)
+		return err
+	}) != nil {
+		return
+	}
+
+	_ = s.stage("validate block", func() error {
+		return validateSimulatedBlock(ctx, blk, msgs, base, mbi.WorkerKey)
+	})
+}
+
+// validateSimulatedBlock checks the block as the network would, as far as the
+// miner can without the parent state
+func validateSimulatedBlock(ctx context.Context, blk *types.BlockMsg, msgs []*types.SignedMessage, base *MiningBase, worker address.Address) error {
+	h := blk.Header
+	if h.Height != base.TipSet.Height()+1 {
+		return xerrors.Errorf("block is at epoch %d, expected %d", h.Height, base.TipSet.Height()+1)
+	}
+	if types.NewTipSetKey(h.Parents...) != base.TipSet.Key() {
+		return xerrors.Errorf("block parents %s aren't the base %s", types.NewTipSetKey(h.Parents...), base.TipSet.Key())
+	}
+	if err := sigs.CheckBlockSignature(ctx, h, worker); err != nil {
+		return xerrors.Errorf("block signature: %w", err)
+	}
+
+	if n := len(blk.BlsMessages) + len(blk.SecpkMessages); n != len(msgs) {
+		return xerrors.Errorf("block has %d messages, %d were selected", n, len(msgs))
+	}
+	if len(msgs) > build.BlockMessageLimit {
+		return xerrors.Errorf("block has %d messages, over the limit of %d", len(msgs), build.BlockMessageLimit)
+	}
+	var gas int64
+	for _, msg := range msgs {
+		gas += msg.Message.GasLimit
+	}
+	if gas > build.BlockGasLimit {
+		return xerrors.Errorf("messages of the block use %d gas, over the block gas limit %d", gas, build.BlockGasLimit)
+	}
+	return nil
+}
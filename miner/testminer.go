@@ -29,13 +29,15 @@ func NewTestMiner(nextCh <-chan MineReq, addr address.Address) func(v1api.FullNo
 
 		m := &Miner{
 			api:               api,
-			waitFunc:          chanWaiter(nextCh),
 			epp:               epp,
 			minedBlockHeights: arc,
 			address:           addr,
 			sf:                slashfilter.New(ds.NewMapDatastore()),
 			journal:           journal.NilJournal(),
+			testMiner:         true,
+			advanceWake:       make(chan struct{}, 1),
 		}
+		m.waitFunc = chanWaiter(nextCh, m.advanceWake)
 
 		if err := m.Start(context.TODO()); err != nil {
 			panic(err)
@@ -44,13 +46,15 @@ func NewTestMiner(nextCh <-chan MineReq, addr address.Address) func(v1api.FullNo
 	}
 }
 
-func chanWaiter(next <-chan MineReq) func(ctx context.Context, _ uint64) (func(bool, abi.ChainEpoch, error), abi.ChainEpoch, error) {
+func chanWaiter(next <-chan MineReq, advance <-chan struct{}) func(ctx context.Context, _ uint64) (func(bool, abi.ChainEpoch, error), abi.ChainEpoch, error) {
 	return func(ctx context.Context, _ uint64) (func(bool, abi.ChainEpoch, error), abi.ChainEpoch, error) {
 		select {
 		case <-ctx.Done():
 			return nil, 0, ctx.Err()
 		case req := <-next:
 			return req.Done, req.InjectNulls, nil
+		case <-advance:
+			return func(bool, abi.ChainEpoch, error) {}, 0, nil
 		}
 	}
 }
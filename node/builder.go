@@ -10,7 +10,10 @@ import (
 
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/lotus/chain"
+	"github.com/filecoin-project/lotus/chain/denylist"
 	"github.com/filecoin-project/lotus/chain/exchange"
+	"github.com/filecoin-project/lotus/chain/follow"
+	"github.com/filecoin-project/lotus/chain/history"
 	rpcstmgr "github.com/filecoin-project/lotus/chain/stmgr/rpc"
 	"github.com/filecoin-project/lotus/chain/store"
 	"github.com/filecoin-project/lotus/chain/vm"
@@ -42,6 +45,7 @@ import (
 	storage2 "github.com/filecoin-project/specs-storage/storage"
 
 	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/chain/actorcache"
 	"github.com/filecoin-project/lotus/chain/beacon"
 	"github.com/filecoin-project/lotus/chain/gen"
 	"github.com/filecoin-project/lotus/chain/gen/slashfilter"
@@ -49,6 +53,7 @@ import (
 	"github.com/filecoin-project/lotus/chain/messagepool"
 	"github.com/filecoin-project/lotus/chain/messagesigner"
 	"github.com/filecoin-project/lotus/chain/metrics"
+	"github.com/filecoin-project/lotus/chain/msgprop"
 	"github.com/filecoin-project/lotus/chain/stmgr"
 	"github.com/filecoin-project/lotus/chain/types"
 	ledgerwallet "github.com/filecoin-project/lotus/chain/wallet/ledger"
@@ -59,10 +64,16 @@ import (
 	"github.com/filecoin-project/lotus/extern/sector-storage/storiface"
 	sealing "github.com/filecoin-project/lotus/extern/storage-sealing"
 	"github.com/filecoin-project/lotus/journal"
+	"github.com/filecoin-project/lotus/lib/headpin"
+	"github.com/filecoin-project/lotus/lib/loglevels"
 	"github.com/filecoin-project/lotus/lib/peermgr"
+	"github.com/filecoin-project/lotus/lib/rpcrecord"
 	_ "github.com/filecoin-project/lotus/lib/sigs/bls"
 	_ "github.com/filecoin-project/lotus/lib/sigs/secp"
 	"github.com/filecoin-project/lotus/markets/dealfilter"
+	"github.com/filecoin-project/lotus/markets/dealhealth"
+	"github.com/filecoin-project/lotus/markets/retrievalstats"
+	"github.com/filecoin-project/lotus/markets/sealingstatus"
 	"github.com/filecoin-project/lotus/markets/storageadapter"
 	"github.com/filecoin-project/lotus/miner"
 	"github.com/filecoin-project/lotus/node/config"
@@ -139,6 +150,7 @@ const (
 	HandleMigrateProviderFundsKey
 	HandleDealsKey
 	HandleRetrievalKey
+	HandleSealingStatusKey
 	RunSectorServiceKey
 
 	// daemon
@@ -149,6 +161,7 @@ const (
 	SetupFallbackBlockstoresKey
 
 	SetApiEndpointKey
+	RecordConfigKey
 
 	_nInvokes // keep this last
 )
@@ -187,6 +200,7 @@ func defaults() []Option {
 		}),
 
 		Override(new(dtypes.ShutdownChan), make(chan struct{})),
+		Override(new(*loglevels.Levels), modules.LogLevels),
 	}
 }
 
@@ -295,8 +309,12 @@ var ChainNode = Options(
 
 	// Service: Message Pool
 	Override(new(dtypes.DefaultMaxFeeFunc), modules.NewDefaultMaxFeeFunc),
+	Override(new(dtypes.MinGasPremiumFunc), modules.NewMinGasPremiumFunc),
+	Override(new(dtypes.GasLimitMarginsFunc), modules.NewGasLimitMarginsFunc),
+	Override(new(dtypes.SendReferenceThresholdFunc), modules.NewSendReferenceThresholdFunc),
 	Override(new(*messagepool.MessagePool), modules.MessagePool),
 	Override(new(*dtypes.MpoolLocker), new(dtypes.MpoolLocker)),
+	Override(new(*msgprop.Tracker), msgprop.NewTracker),
 
 	// Shared graphsync (markets, serving chain)
 	Override(new(dtypes.Graphsync), modules.Graphsync(config.DefaultFullNode().Client.SimultaneousTransfers)),
@@ -306,6 +324,8 @@ var ChainNode = Options(
 	Override(new(*wallet.LocalWallet), wallet.NewWallet),
 	Override(new(wallet.Default), From(new(*wallet.LocalWallet))),
 	Override(new(api.Wallet), From(new(wallet.MultiWallet))),
+	Override(new(*wallet.WatchOnly), wallet.NewWatchOnly),
+	Override(new(*wallet.UsagePolicies), modules.WalletUsagePolicies),
 
 	// Service: Payment channels
 	Override(new(paychmgr.PaychAPI), From(new(modules.PaychAPI))),
@@ -321,12 +341,14 @@ var ChainNode = Options(
 	Override(new(discovery.PeerResolver), modules.RetrievalResolver),
 	Override(new(retrievalmarket.RetrievalClient), modules.RetrievalClient),
 	Override(new(dtypes.ClientDataTransfer), modules.NewClientGraphsyncDataTransfer),
+	Override(new(*retrievalstats.Store), modules.RetrievalStats(config.DefaultFullNode().Client.RetrievalStatsRetention)),
 
 	// Markets (storage)
 	Override(new(*market.FundManager), market.NewFundManager),
 	Override(new(dtypes.ClientDatastore), modules.NewClientDatastore),
 	Override(new(storagemarket.StorageClient), modules.StorageClient),
 	Override(new(storagemarket.StorageClientNode), storageadapter.NewClientNodeAdapter),
+	Override(new(*dealhealth.Watcher), dealhealth.NewWatcher),
 	Override(HandleMigrateClientFundsKey, modules.HandleMigrateClientFunds),
 
 	Override(new(*full.GasPriceCache), full.NewGasPriceCache),
@@ -388,7 +410,7 @@ var MinerNode = Options(
 
 	// Mining / proving
 	Override(new(*slashfilter.SlashFilter), modules.NewSlashFilter),
-	Override(new(*storage.Miner), modules.StorageMiner(config.DefaultStorageMiner().Fees)),
+	Override(new(*storage.Miner), modules.StorageMiner(config.DefaultStorageMiner().Fees, config.DefaultStorageMiner().Proving)),
 	Override(new(*miner.Miner), modules.SetupBlockProducer),
 	Override(new(gen.WinningPoStProver), storage.NewWinningPoStProver),
 
@@ -416,6 +438,8 @@ var MinerNode = Options(
 	Override(new(storagemarket.StorageProviderNode), storageadapter.NewProviderNodeAdapter(nil, nil)),
 	Override(HandleMigrateProviderFundsKey, modules.HandleMigrateProviderFunds),
 	Override(HandleDealsKey, modules.HandleDeals),
+	Override(new(*sealingstatus.Provider), modules.SealingStatusProvider),
+	Override(HandleSealingStatusKey, modules.HandleSealingStatus),
 
 	// Config (todo: get a real property system)
 	Override(new(dtypes.ConsiderOnlineStorageDealsConfigFunc), modules.NewConsiderOnlineStorageDealsConfigFunc),
@@ -438,6 +462,8 @@ var MinerNode = Options(
 	Override(new(dtypes.GetExpectedSealDurationFunc), modules.NewGetExpectedSealDurationFunc),
 	Override(new(dtypes.SetMaxDealStartDelayFunc), modules.NewSetMaxDealStartDelayFunc),
 	Override(new(dtypes.GetMaxDealStartDelayFunc), modules.NewGetMaxDealStartDelayFunc),
+	Override(new(dtypes.DiscloseSealingStatusFunc), modules.NewDiscloseSealingStatusFunc),
+	Override(new(dtypes.SetWdPoStParallelBatchesFunc), modules.NewSetWdPoStParallelBatchesFunc),
 )
 
 // Online sets up basic libp2p node
@@ -536,11 +562,25 @@ func ConfigFullNode(c interface{}) Option {
 			),
 		),
 		Override(new(dtypes.Graphsync), modules.Graphsync(cfg.Client.SimultaneousTransfers)),
+		Override(new(*retrievalstats.Store), modules.RetrievalStats(cfg.Client.RetrievalStatsRetention)),
 
 		If(cfg.Metrics.HeadNotifs,
 			Override(HeadMetricsKey, metrics.SendHeadNotifs(cfg.Metrics.Nickname)),
 		),
 
+		Override(new(*actorcache.WatchedActors), modules.WatchedActors(cfg.WatchedActors)),
+		Override(new(*denylist.Denylist), modules.SendDenylist(cfg.SendDenylist)),
+		Override(new(*follow.Follower), modules.ChainFollower(cfg.ChainFollow)),
+		Override(new(*rpcrecord.Recorder), modules.RPCRecorder),
+		Override(new(*headpin.Pins), modules.HeadPins),
+		If(cfg.Metrics.History.Enable,
+			Override(new(*history.Recorder), modules.MetricsHistory(cfg.Metrics.History)),
+		),
+
+		Override(new(*config.History), config.NewHistory),
+		Override(RecordConfigKey, modules.RecordFullNodeConfig),
+
+		Override(new(*wallet.LocalWallet), modules.LocalWallet(cfg.Wallet)),
 		If(cfg.Wallet.RemoteBackend != "",
 			Override(new(*remotewallet.RemoteWallet), remotewallet.SetupRemoteWallet(cfg.Wallet.RemoteBackend)),
 		),
@@ -579,7 +619,10 @@ func ConfigStorageMiner(c interface{}) Option {
 
 		Override(new(sectorstorage.SealerConfig), cfg.Storage),
 		Override(new(*storage.AddressSelector), modules.AddressSelector(&cfg.Addresses)),
-		Override(new(*storage.Miner), modules.StorageMiner(cfg.Fees)),
+		Override(new(*storage.Miner), modules.StorageMiner(cfg.Fees, cfg.Proving)),
+
+		Override(new(*config.History), config.NewHistory),
+		Override(RecordConfigKey, modules.RecordMinerConfig),
 	)
 }
 
@@ -608,6 +651,7 @@ func Repo(r repo.Repo) Option {
 
 		return Options(
 			Override(new(repo.LockedRepo), modules.LockedRepo(lr)), // module handles closing
+			Override(new(dtypes.LoadedConfig), c),
 
 			Override(new(dtypes.UniversalBlockstore), modules.UniversalBlockstore),
 
@@ -30,6 +30,11 @@ type FullNode struct {
 	Wallet     Wallet
 	Fees       FeeConfig
 	Chainstore Chainstore
+
+	WatchedActors WatchedActors
+	SendDenylist  SendDenylist
+	ChainFollow   ChainFollow
+	SendAudit     SendAudit
 }
 
 // // Common
@@ -47,6 +52,31 @@ type StorageMiner struct {
 	Storage    sectorstorage.SealerConfig
 	Fees       MinerFeeConfig
 	Addresses  MinerAddressConfig
+	Proving    ProvingConfig
+}
+
+// ProvingConfig configures where the WindowPoSt proofs are computed
+type ProvingConfig struct {
+	// RemoteProvers are the URLs of HTTP services computing the WindowPoSt
+	// proofs instead of the miner, tried in order. They read the challenged
+	// sectors from storage they share with the miner. The proofs are computed
+	// locally when empty.
+	RemoteProvers []string
+	// RemoteProverToken is sent to the remote provers as a bearer token
+	RemoteProverToken string `secret:"true"`
+	// RemoteProverTimeout bounds a request to a remote prover, which is also
+	// bounded by the close of the proving window
+	RemoteProverTimeout Duration
+	// LocalFallbackTime is how long computing the proofs locally takes. When
+	// the remote provers fail with at least this much time left before the
+	// proving window closes, the proofs are computed locally.
+	LocalFallbackTime Duration
+	// ParallelBatches is how many batches of partitions of a deadline are
+	// proven at once. Proving in parallel contends with WinningPoSt for the
+	// GPU, so by default the batches are proven one after another. It can be
+	// changed while the miner runs with 'lotus-miner proving compute
+	// parallel-batches'.
+	ParallelBatches int
 }
 
 type DealmakingConfig struct {
@@ -69,9 +99,13 @@ type DealmakingConfig struct {
 	// The maximum collateral that the provider will put up against a deal,
 	// as a multiplier of the minimum collateral bound
 	MaxProviderCollateralMultiplier uint64
+	// Whether deal clients can ask where their deals are in the sealing
+	// pipeline, and when they are expected to activate
+	DiscloseSealingStatus bool
 
-	Filter          string
-	RetrievalFilter string
+	// Filter and RetrievalFilter are commands, which may carry credentials
+	Filter          string `secret:"true"`
+	RetrievalFilter string `secret:"true"`
 }
 
 type SealingConfig struct {
@@ -145,6 +179,35 @@ type MinerFeeConfig struct {
 	MaxWindowPoStGasFee    types.FIL
 	MaxPublishDealsFee     types.FIL
 	MaxMarketBalanceAddFee types.FIL
+
+	// WindowPoStEscalation picks the gas premium of the WindowPoSt messages
+	// by how close the epoch they must land by is
+	WindowPoStEscalation FeeEscalationConfig
+}
+
+// FeeEscalationConfig picks the gas premium of a message from the one the
+// node estimates, by the epochs left until the message must land on chain
+type FeeEscalationConfig struct {
+	// Enable replaces WindowPoSt submissions which haven't landed with ones
+	// paying the premium of the Curve as the proving window close approaches,
+	// and has fault recoveries declared well before their cutoff pay an
+	// economical premium
+	Enable bool
+	// Curve is the estimated premium multiplier of messages with at most
+	// RemainingEpochs left, the step with the fewest epochs applying. With
+	// more epochs left than in any step the estimated premium is paid.
+	Curve []FeeEscalationStep
+	// Fault recoveries declared with more than EconomicalEpochs left before
+	// their cutoff pay EconomicalMultiplier of the estimated premium
+	EconomicalEpochs     int64
+	EconomicalMultiplier float64
+	// MaxGasPremium caps the premium, whatever the Curve
+	MaxGasPremium types.FIL
+}
+
+type FeeEscalationStep struct {
+	RemainingEpochs int64
+	Multiplier      float64
 }
 
 type MinerAddressConfig struct {
@@ -205,9 +268,58 @@ type Splitstore struct {
 
 // // Full Node
 
+type WatchedActors struct {
+	// Addresses of actors whose state is kept in memory at the chain head,
+	// speeding up StateGetActor and StateMinerInfo calls for them
+	Addresses []string
+}
+
+type SendDenylist struct {
+	// Addresses the node refuses to send messages to, also when they are
+	// the target of a multisig proposal
+	Addresses []string
+	// File with more denied addresses, one per line. The file is read again
+	// when it changes, no restart is needed.
+	File string
+}
+
+type SendAudit struct {
+	// ReferenceRequiredAbove makes MpoolPushMessage require a reference
+	// (e.g. a ticket ID) for messages sending more than this value. The
+	// reference is recorded in the journal with the message. 0 disables it.
+	ReferenceRequiredAbove types.FIL
+}
+
+type ChainFollow struct {
+	// NdjsonDir enables the built-in chain follow consumer, appending tipset,
+	// message and receipt rows to ndjson files in this directory
+	NdjsonDir string
+}
+
 type Metrics struct {
 	Nickname   string
 	HeadNotifs bool
+
+	History MetricsHistory
+}
+
+type MetricsHistory struct {
+	// Enable records the Series into the node's metadata datastore, kept for
+	// far longer than Prometheus usually keeps them
+	Enable bool
+	// Series are the recorded series: basefee, networkpower, and
+	// power:<miner>, pledge:<miner> or balance:<address>
+	Series []string
+	// Tiers are the downsampling tiers, each keeping points of Resolution
+	// epochs for Retention epochs before they are rolled up into the next
+	// tier, or dropped from the last one. Samples are taken once per
+	// Resolution epochs of the first tier.
+	Tiers []MetricsHistoryTier
+}
+
+type MetricsHistoryTier struct {
+	Resolution abi.ChainEpoch
+	Retention  abi.ChainEpoch
 }
 
 type Client struct {
@@ -216,16 +328,36 @@ type Client struct {
 	IpfsMAddr             string
 	IpfsUseForRetrieval   bool
 	SimultaneousTransfers uint64
+	// RetrievalStatsRetention is how long the records of the retrievals made
+	// by the client are kept, 0 keeping them forever
+	RetrievalStatsRetention Duration
 }
 
 type Wallet struct {
-	RemoteBackend string
+	RemoteBackend string `secret:"true"` // holds the API token of the remote wallet
 	EnableLedger  bool
 	DisableLocal  bool
+
+	// KeystorePassphraseFile is a file containing the passphrase of an
+	// encrypted keystore, which is then unlocked on startup. The passphrase
+	// can also be set with the LOTUS_KEYSTORE_PASSPHRASE env var.
+	KeystorePassphraseFile string
+	// KeystoreRelockAfter locks an encrypted keystore after its keys weren't
+	// used for this long; 0 keeps it unlocked until WalletLock is called
+	KeystoreRelockAfter Duration
 }
 
 type FeeConfig struct {
 	DefaultMaxFee types.FIL
+	// MinGasPremium is the lowest gas premium messages pushed by the node are
+	// sent with, estimated or given premiums below it are raised to it
+	// (e.g. "100000 aWD"); 0 disables the floor
+	MinGasPremium types.FIL
+	// GasLimitMargins are the margins the gas estimates of messages pushed
+	// by the node are multiplied by, by the family of the destination actor
+	// ("multisig") or its method ("storageminer/5"). The margins set in the
+	// mpool config go first, the mpool GasLimitOverestimation is the default.
+	GasLimitMargins map[string]float64
 }
 
 func defCommon() Common {
@@ -264,9 +396,23 @@ func DefaultFullNode() *FullNode {
 		Common: defCommon(),
 		Fees: FeeConfig{
 			DefaultMaxFee: DefaultDefaultMaxFee,
+			MinGasPremium: types.FIL(types.NewInt(0)),
+		},
+		SendAudit: SendAudit{
+			ReferenceRequiredAbove: types.FIL(types.NewInt(0)),
 		},
 		Client: Client{
-			SimultaneousTransfers: DefaultSimultaneousTransfers,
+			SimultaneousTransfers:   DefaultSimultaneousTransfers,
+			RetrievalStatsRetention: Duration(90 * 24 * time.Hour),
+		},
+		Metrics: Metrics{
+			History: MetricsHistory{
+				Series: []string{"basefee", "networkpower"},
+				Tiers: []MetricsHistoryTier{
+					{Resolution: 120, Retention: 30 * 2880},        // hourly for 30 days
+					{Resolution: 2880, Retention: 10 * 365 * 2880}, // daily for 10 years
+				},
+			},
 		},
 		Chainstore: Chainstore{
 			EnableSplitstore: false,
@@ -333,6 +479,7 @@ func DefaultStorageMiner() *StorageMiner {
 			PublishMsgPeriod:                Duration(time.Hour),
 			MaxDealsPerPublishMsg:           8,
 			MaxProviderCollateralMultiplier: 2,
+			DiscloseSealingStatus:           true,
 		},
 
 		Fees: MinerFeeConfig{
@@ -352,12 +499,31 @@ func DefaultStorageMiner() *StorageMiner {
 			MaxWindowPoStGasFee:    types.MustParseFIL("5"),
 			MaxPublishDealsFee:     types.MustParseFIL("0.05"),
 			MaxMarketBalanceAddFee: types.MustParseFIL("0.007"),
+
+			WindowPoStEscalation: FeeEscalationConfig{
+				Enable: true,
+				Curve: []FeeEscalationStep{
+					{RemainingEpochs: 30, Multiplier: 1.5},
+					{RemainingEpochs: 15, Multiplier: 3},
+					{RemainingEpochs: 5, Multiplier: 6},
+				},
+				EconomicalEpochs:     30,
+				EconomicalMultiplier: 0.5,
+				MaxGasPremium:        types.FIL(types.BigMul(types.PicoFil, types.NewInt(1000))), // 1 nFIL
+			},
 		},
 
 		Addresses: MinerAddressConfig{
 			PreCommitControl: []string{},
 			CommitControl:    []string{},
 		},
+
+		Proving: ProvingConfig{
+			RemoteProvers:       []string{},
+			RemoteProverTimeout: Duration(20 * time.Minute),
+			LocalFallbackTime:   Duration(10 * time.Minute),
+			ParallelBatches:     1,
+		},
 	}
 	cfg.Common.API.ListenAddress = "/ip4/127.0.0.1/tcp/2345/http"
 	cfg.Common.API.RemoteListenAddress = "127.0.0.1:2345"
@@ -0,0 +1,376 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/namespace"
+	"github.com/ipfs/go-datastore/query"
+	"github.com/multiformats/go-multihash"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/node/modules/dtypes"
+)
+
+const historyDs = "/config/history"
+
+// HistoryRetention is the number of config snapshots kept
+var HistoryRetention = 100
+
+// History keeps the snapshots of the effective config of the node. The
+// sections of the snapshots are kept by their Cid, once for identical
+// configs.
+type History struct {
+	lk sync.Mutex
+	ds datastore.Datastore
+}
+
+// historyEntry is a snapshot, without its sections
+type historyEntry struct {
+	Seq    uint64
+	Cid    cid.Cid
+	Time   time.Time
+	Reason string
+	Caller string
+}
+
+func NewHistory(ds dtypes.MetadataDS) *History {
+	return &History{ds: namespace.Wrap(ds, datastore.NewKey(historyDs))}
+}
+
+func entryKey(seq uint64) datastore.Key {
+	return datastore.NewKey(fmt.Sprintf("/entries/%020d", seq))
+}
+
+func sectionsKey(c cid.Cid) datastore.Key {
+	return datastore.NewKey("/sections/" + c.String())
+}
+
+// Record takes a snapshot of the config, the latest one with the changed
+// sections replaced
+func (h *History) Record(reason, caller string, changed map[string]string) error {
+	h.lk.Lock()
+	defer h.lk.Unlock()
+
+	entries, err := h.entries()
+	if err != nil {
+		return err
+	}
+
+	sections := map[string]string{}
+	var seq uint64
+	if len(entries) > 0 {
+		last := entries[len(entries)-1]
+		if sections, err = h.sections(last.Cid); err != nil {
+			return err
+		}
+		seq = last.Seq + 1
+	}
+	for name, s := range changed {
+		sections[name] = s
+	}
+
+	b, err := json.Marshal(sections)
+	if err != nil {
+		return xerrors.Errorf("encoding config sections: %w", err)
+	}
+	c, err := cid.Prefix{Version: 1, Codec: cid.Raw, MhType: multihash.SHA2_256, MhLength: -1}.Sum(b)
+	if err != nil {
+		return err
+	}
+	if err := h.ds.Put(sectionsKey(c), b); err != nil {
+		return xerrors.Errorf("persisting config sections: %w", err)
+	}
+
+	eb, err := json.Marshal(historyEntry{Seq: seq, Cid: c, Time: time.Now(), Reason: reason, Caller: caller})
+	if err != nil {
+		return err
+	}
+	if err := h.ds.Put(entryKey(seq), eb); err != nil {
+		return xerrors.Errorf("persisting config snapshot: %w", err)
+	}
+
+	return h.prune()
+}
+
+// RecordChange takes a snapshot of the config once the API method changed
+// the section to v at runtime, for the caller of the method
+func (h *History) RecordChange(ctx context.Context, method, section string, v interface{}) error {
+	if h == nil {
+		return nil
+	}
+	s, err := FlattenJSON(v)
+	if err != nil {
+		return xerrors.Errorf("flattening %s config: %w", section, err)
+	}
+	return h.Record(method, api.Caller(ctx), map[string]string{section: s})
+}
+
+// Snapshots returns the snapshots kept, oldest first
+func (h *History) Snapshots() ([]api.ConfigSnapshot, error) {
+	h.lk.Lock()
+	defer h.lk.Unlock()
+
+	entries, err := h.entries()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]api.ConfigSnapshot, 0, len(entries))
+	for _, e := range entries {
+		sections, err := h.sections(e.Cid)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, api.ConfigSnapshot{
+			Cid:      e.Cid,
+			Time:     e.Time,
+			Reason:   e.Reason,
+			Caller:   e.Caller,
+			Sections: sections,
+		})
+	}
+	return out, nil
+}
+
+// entries returns the snapshots kept, oldest first
+func (h *History) entries() ([]historyEntry, error) {
+	res, err := h.ds.Query(query.Query{Prefix: "/entries"})
+	if err != nil {
+		return nil, xerrors.Errorf("listing config snapshots: %w", err)
+	}
+	rs, err := res.Rest()
+	if err != nil {
+		return nil, xerrors.Errorf("listing config snapshots: %w", err)
+	}
+
+	out := make([]historyEntry, 0, len(rs))
+	for _, r := range rs {
+		var e historyEntry
+		if err := json.Unmarshal(r.Value, &e); err != nil {
+			return nil, xerrors.Errorf("decoding config snapshot %s: %w", r.Key, err)
+		}
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Seq < out[j].Seq
+	})
+	return out, nil
+}
+
+func (h *History) sections(c cid.Cid) (map[string]string, error) {
+	b, err := h.ds.Get(sectionsKey(c))
+	if err != nil {
+		return nil, xerrors.Errorf("getting config sections %s: %w", c, err)
+	}
+	var sections map[string]string
+	if err := json.Unmarshal(b, &sections); err != nil {
+		return nil, xerrors.Errorf("decoding config sections %s: %w", c, err)
+	}
+	return sections, nil
+}
+
+// prune drops the oldest snapshots past HistoryRetention, and the sections
+// no snapshot kept refers to
+func (h *History) prune() error {
+	entries, err := h.entries()
+	if err != nil {
+		return err
+	}
+
+	for len(entries) > HistoryRetention {
+		if err := h.ds.Delete(entryKey(entries[0].Seq)); err != nil {
+			return xerrors.Errorf("deleting config snapshot: %w", err)
+		}
+		entries = entries[1:]
+	}
+
+	kept := map[string]bool{}
+	for _, e := range entries {
+		kept[sectionsKey(e.Cid).String()] = true
+	}
+	res, err := h.ds.Query(query.Query{Prefix: "/sections", KeysOnly: true})
+	if err != nil {
+		return xerrors.Errorf("listing config sections: %w", err)
+	}
+	rs, err := res.Rest()
+	if err != nil {
+		return xerrors.Errorf("listing config sections: %w", err)
+	}
+	for _, r := range rs {
+		if kept[r.Key] {
+			continue
+		}
+		if err := h.ds.Delete(datastore.NewKey(r.Key)); err != nil {
+			return xerrors.Errorf("deleting config sections: %w", err)
+		}
+	}
+	return nil
+}
+
+// FlattenConfig returns the node config as a config section, with the
+// settings tagged `secret:"true"` redacted
+func FlattenConfig(cfg interface{}) (string, error) {
+	buf := new(bytes.Buffer)
+	if err := toml.NewEncoder(buf).Encode(cfg); err != nil {
+		return "", xerrors.Errorf("encoding config: %w", err)
+	}
+	var m map[string]interface{}
+	if _, err := toml.Decode(buf.String(), &m); err != nil {
+		return "", xerrors.Errorf("decoding config: %w", err)
+	}
+
+	// the decoded map is a copy, the config is the one the node runs with
+	for _, path := range secretPaths(reflect.Indirect(reflect.ValueOf(cfg)).Type(), nil) {
+		redactPath(m, path)
+	}
+	return flatten(m), nil
+}
+
+// FlattenJSON returns a setting changed at runtime as a config section
+func FlattenJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var m interface{}
+	if err := d.Decode(&m); err != nil {
+		return "", err
+	}
+	return flatten(m), nil
+}
+
+// secretPaths returns the paths of the string settings tagged
+// `secret:"true"`, fields of embedded structs being settings of the struct
+// embedding them
+func secretPaths(t reflect.Type, prefix []string) [][]string {
+	var out [][]string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue // unexported
+		}
+
+		path := prefix
+		if !f.Anonymous {
+			path = append(append([]string{}, prefix...), f.Name)
+		}
+		switch {
+		case f.Tag.Get("secret") == "true" && f.Type.Kind() == reflect.String:
+			out = append(out, path)
+		case f.Type.Kind() == reflect.Struct:
+			out = append(out, secretPaths(f.Type, path)...)
+		}
+	}
+	return out
+}
+
+func redactPath(m map[string]interface{}, path []string) {
+	for _, k := range path[:len(path)-1] {
+		sub, ok := m[k].(map[string]interface{})
+		if !ok {
+			return
+		}
+		m = sub
+	}
+	if s, ok := m[path[len(path)-1]].(string); ok && s != "" {
+		m[path[len(path)-1]] = redacted(s)
+	}
+}
+
+// redacted replaces a secret, with a fingerprint telling when it changed
+func redacted(secret string) string {
+	h := sha256.Sum256([]byte(secret))
+	return "<redacted " + hex.EncodeToString(h[:4]) + ">"
+}
+
+// flatten returns the values as sorted "path = value" lines
+func flatten(v interface{}) string {
+	var lines []string
+	flattenValue("", v, &lines)
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+func flattenValue(path string, v interface{}, out *[]string) {
+	if m, ok := v.(map[string]interface{}); ok {
+		for k, e := range m {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			flattenValue(p, e, out)
+		}
+		return
+	}
+
+	b := new(bytes.Buffer)
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		*out = append(*out, path+" = "+fmt.Sprint(v))
+		return
+	}
+	*out = append(*out, path+" = "+strings.TrimSuffix(b.String(), "\n"))
+}
+
+// DiffSnapshots returns the settings which differ between the sections of
+// two snapshots, as "+" added, "-" removed and "~" changed lines
+func DiffSnapshots(a, b map[string]string) []string {
+	var out []string
+	for _, name := range unionKeys(a, b) {
+		av, bv := parseSection(a[name]), parseSection(b[name])
+		for _, path := range unionKeys(av, bv) {
+			old, hadOld := av[path]
+			nw, hasNew := bv[path]
+			switch {
+			case !hadOld:
+				out = append(out, fmt.Sprintf("+ %s %s = %s", name, path, nw))
+			case !hasNew:
+				out = append(out, fmt.Sprintf("- %s %s = %s", name, path, old))
+			case old != nw:
+				out = append(out, fmt.Sprintf("~ %s %s: %s -> %s", name, path, old, nw))
+			}
+		}
+	}
+	return out
+}
+
+func parseSection(s string) map[string]string {
+	out := map[string]string{}
+	for _, l := range strings.Split(s, "\n") {
+		if i := strings.Index(l, " = "); i >= 0 {
+			out[l[:i]] = l[i+3:]
+		}
+	}
+	return out
+}
+
+func unionKeys(a, b map[string]string) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory(t *testing.T) {
+	defer func(r int) { HistoryRetention = r }(HistoryRetention)
+	HistoryRetention = 3
+
+	h := NewHistory(dssync.MutexWrap(datastore.NewMapDatastore()))
+
+	require.NoError(t, h.Record("startup", "local", map[string]string{"config": "A = 1", "mpool": "B = 2"}))
+	require.NoError(t, h.Record("MpoolSetConfig", "token 1a2b3c4d (admin)", map[string]string{"mpool": "B = 3"}))
+	require.NoError(t, h.Record("startup", "local", map[string]string{"config": "A = 1", "mpool": "B = 2"}))
+
+	snaps, err := h.Snapshots()
+	require.NoError(t, err)
+	require.Len(t, snaps, 3)
+
+	// a runtime change keeps the other sections
+	require.Equal(t, map[string]string{"config": "A = 1", "mpool": "B = 3"}, snaps[1].Sections)
+	require.Equal(t, "token 1a2b3c4d (admin)", snaps[1].Caller)
+	// identical configs have the same Cid
+	require.Equal(t, snaps[0].Cid, snaps[2].Cid)
+	require.NotEqual(t, snaps[0].Cid, snaps[1].Cid)
+
+	require.NoError(t, h.Record("startup", "local", map[string]string{"mpool": "B = 4"}))
+	snaps, err = h.Snapshots()
+	require.NoError(t, err)
+	require.Len(t, snaps, 3)
+	require.Equal(t, "MpoolSetConfig", snaps[0].Reason)
+	require.Equal(t, "B = 4", snaps[2].Sections["mpool"])
+}
+
+func TestFlattenConfigRedacts(t *testing.T) {
+	cfg := DefaultFullNode()
+	cfg.Wallet.RemoteBackend = "secrettoken:/ip4/127.0.0.1/tcp/1777/http"
+
+	s, err := FlattenConfig(cfg)
+	require.NoError(t, err)
+	require.NotContains(t, s, "secrettoken")
+	require.Contains(t, s, `Wallet.RemoteBackend = "<redacted `)
+	require.Contains(t, s, `API.ListenAddress = "/ip4/127.0.0.1/tcp/1234/http"`)
+	// the config the node runs with is left as is
+	require.Equal(t, "secrettoken:/ip4/127.0.0.1/tcp/1777/http", cfg.Wallet.RemoteBackend)
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	a := map[string]string{
+		"config": strings.Join([]string{`A = 1`, `B = "x"`}, "\n"),
+		"mpool":  `C = 3`,
+	}
+	b := map[string]string{
+		"config":  strings.Join([]string{`A = 2`, `D = true`}, "\n"),
+		"mpool":   `C = 3`,
+		"storage": `id = "/data"`,
+	}
+
+	require.Equal(t, []string{
+		`~ config A: 1 -> 2`,
+		`- config B = "x"`,
+		`+ config D = true`,
+		`+ storage id = "/data"`,
+	}, DiffSnapshots(a, b))
+}
@@ -0,0 +1,526 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	miner5 "github.com/filecoin-project/specs-actors/v5/actors/builtin/miner"
+
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+// Severity is the severity of a config Violation
+type Severity int
+
+const (
+	// SeverityWarning marks settings which are valid, but very likely not
+	// what the operator wants
+	SeverityWarning Severity = iota
+	// SeverityError marks settings which will make the node misbehave or
+	// fail to start
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// Violation is a single problem found in a config
+type Violation struct {
+	// Rule is the name of the rule which produced this violation
+	Rule string
+	// Path is the dotted path of the offending field, as it appears in
+	// config.toml, e.g. `Fees.DefaultMaxFee`
+	Path     string
+	Severity Severity
+	Message  string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s: %s (%s)", v.Severity, v.Path, v.Message, v.Rule)
+}
+
+// Rule is a single semantic config check. Check is called with the parsed
+// config (*FullNode or *StorageMiner), and must ignore config types it
+// doesn't apply to.
+type Rule struct {
+	Name  string
+	Check func(cfg interface{}) []Violation
+}
+
+// ValidationRules is the set of rules applied by Validate. New rules should
+// be added here, using the commonRule / fullNodeRule / minerRule helpers.
+var ValidationRules = []Rule{
+	commonRule("api-listen-address", checkAPIListenAddress),
+	commonRule("listen-address-overlap", checkListenOverlap),
+	commonRule("connmgr-watermarks", checkConnMgr),
+
+	fullNodeRule("default-max-fee", checkDefaultMaxFee),
+	fullNodeRule("splitstore-types", checkSplitstoreTypes),
+	fullNodeRule("splitstore-compaction", checkSplitstoreCompaction),
+	fullNodeRule("client-ipfs", checkClientIpfs),
+	fullNodeRule("wallet-backend", checkWalletBackend),
+	fullNodeRule("metrics-history", checkMetricsHistory),
+
+	minerRule("sealing-batch-sizes", checkSealingBatchSizes),
+	minerRule("sealing-batch-wait", checkSealingBatchWait),
+	minerRule("miner-max-fees", checkMinerMaxFees),
+	minerRule("fee-escalation", checkFeeEscalation),
+	minerRule("dealmaking-publish", checkDealPublish),
+	minerRule("control-addresses", checkControlAddresses),
+}
+
+// Validate applies all ValidationRules to the passed config. Violations are
+// sorted by path.
+func Validate(cfg interface{}) []Violation {
+	var out []Violation
+	for _, r := range ValidationRules {
+		for _, v := range r.Check(cfg) {
+			v.Rule = r.Name
+			out = append(out, v)
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Path < out[j].Path
+	})
+	return out
+}
+
+// UnknownKeys decodes config from the reader, and returns a warning for each
+// key which doesn't correspond to any config field (usually a typo, or a
+// setting which was renamed or removed).
+func UnknownKeys(reader io.Reader, def interface{}) ([]Violation, error) {
+	md, err := toml.DecodeReader(reader, def)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Violation
+	for _, key := range md.Undecoded() {
+		out = append(out, Violation{
+			Rule:     "unknown-key",
+			Path:     key.String(),
+			Severity: SeverityWarning,
+			Message:  "unknown config key, setting is ignored",
+		})
+	}
+	return out, nil
+}
+
+// Diff returns the paths of all config fields which have different values in
+// a and b. Both configs must be of the same type.
+func Diff(a, b interface{}) ([]string, error) {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Type() != vb.Type() {
+		return nil, xerrors.Errorf("can't diff configs of different types (%T and %T)", a, b)
+	}
+
+	var out []string
+	diffValue("", reflect.Indirect(va), reflect.Indirect(vb), &out)
+	return out, nil
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+func diffValue(path string, a, b reflect.Value, out *[]string) {
+	if a.Kind() != reflect.Struct || a.Type().Implements(textMarshalerType) {
+		// leaf value, compare printed representation so that things like nil
+		// and empty slices, or differently normalized big ints are equal
+		if fmt.Sprint(a.Interface()) != fmt.Sprint(b.Interface()) {
+			*out = append(*out, path)
+		}
+		return
+	}
+
+	for i := 0; i < a.NumField(); i++ {
+		f := a.Type().Field(i)
+		if f.PkgPath != "" {
+			continue // unexported
+		}
+
+		fpath := path
+		if !f.Anonymous { // embedded structs are flattened in toml
+			if fpath != "" {
+				fpath += "."
+			}
+			fpath += f.Name
+		}
+
+		diffValue(fpath, a.Field(i), b.Field(i), out)
+	}
+}
+
+func commonRule(name string, check func(*Common) []Violation) Rule {
+	return Rule{
+		Name: name,
+		Check: func(cfg interface{}) []Violation {
+			switch c := cfg.(type) {
+			case *FullNode:
+				return check(&c.Common)
+			case *StorageMiner:
+				return check(&c.Common)
+			default:
+				return nil
+			}
+		},
+	}
+}
+
+func fullNodeRule(name string, check func(*FullNode) []Violation) Rule {
+	return Rule{
+		Name: name,
+		Check: func(cfg interface{}) []Violation {
+			c, ok := cfg.(*FullNode)
+			if !ok {
+				return nil
+			}
+			return check(c)
+		},
+	}
+}
+
+func minerRule(name string, check func(*StorageMiner) []Violation) Rule {
+	return Rule{
+		Name: name,
+		Check: func(cfg interface{}) []Violation {
+			c, ok := cfg.(*StorageMiner)
+			if !ok {
+				return nil
+			}
+			return check(c)
+		},
+	}
+}
+
+func errorf(path string, format string, args ...interface{}) Violation {
+	return Violation{Path: path, Severity: SeverityError, Message: fmt.Sprintf(format, args...)}
+}
+
+func warnf(path string, format string, args ...interface{}) Violation {
+	return Violation{Path: path, Severity: SeverityWarning, Message: fmt.Sprintf(format, args...)}
+}
+
+// // Common
+
+func checkAPIListenAddress(c *Common) []Violation {
+	if _, err := multiaddr.NewMultiaddr(c.API.ListenAddress); err != nil {
+		return []Violation{errorf("API.ListenAddress", "invalid multiaddr: %s", err)}
+	}
+	return nil
+}
+
+type listenAddr struct {
+	path string
+	ip   net.IP
+	net  string
+	port int
+}
+
+func (l listenAddr) overlaps(o listenAddr) bool {
+	if l.net != o.net || l.port == 0 || l.port != o.port {
+		return false
+	}
+	if (l.ip.To4() == nil) != (o.ip.To4() == nil) {
+		return false // ip4 and ip6 listeners don't conflict
+	}
+	return l.ip.IsUnspecified() || o.ip.IsUnspecified() || l.ip.Equal(o.ip)
+}
+
+func checkListenOverlap(c *Common) []Violation {
+	var out []Violation
+	var addrs []listenAddr
+
+	parse := func(path, s string) {
+		ma, err := multiaddr.NewMultiaddr(s)
+		if err != nil {
+			out = append(out, errorf(path, "invalid multiaddr: %s", err))
+			return
+		}
+		na, err := manet.ToNetAddr(ma)
+		if err != nil {
+			return // not an ip listen address
+		}
+		switch a := na.(type) {
+		case *net.TCPAddr:
+			addrs = append(addrs, listenAddr{path: path, ip: a.IP, net: "tcp", port: a.Port})
+		case *net.UDPAddr:
+			addrs = append(addrs, listenAddr{path: path, ip: a.IP, net: "udp", port: a.Port})
+		}
+	}
+
+	if ma, err := multiaddr.NewMultiaddr(c.API.ListenAddress); err == nil {
+		// the API address usually has a /http suffix which manet doesn't understand
+		parse("API.ListenAddress", ma.Decapsulate(multiaddr.StringCast("/http")).String())
+	}
+	for i, a := range c.Libp2p.ListenAddresses {
+		parse(fmt.Sprintf("Libp2p.ListenAddresses[%d]", i), a)
+	}
+
+	for i := range addrs {
+		for j := i + 1; j < len(addrs); j++ {
+			if addrs[i].overlaps(addrs[j]) {
+				out = append(out, errorf(addrs[j].path, "listens on %s port %d, which is already used by %s", addrs[j].net, addrs[j].port, addrs[i].path))
+			}
+		}
+	}
+
+	return out
+}
+
+func checkConnMgr(c *Common) []Violation {
+	if c.Libp2p.ConnMgrLow > c.Libp2p.ConnMgrHigh {
+		return []Violation{errorf("Libp2p.ConnMgrLow", "low watermark (%d) is above high watermark (%d)", c.Libp2p.ConnMgrLow, c.Libp2p.ConnMgrHigh)}
+	}
+	return nil
+}
+
+// // Full Node
+
+func checkDefaultMaxFee(c *FullNode) []Violation {
+	if c.Fees.DefaultMaxFee.Int == nil || c.Fees.DefaultMaxFee.Sign() <= 0 {
+		return []Violation{errorf("Fees.DefaultMaxFee", "must be above zero, otherwise messages without an explicit max fee can't pay for gas")}
+	}
+	return nil
+}
+
+func checkSplitstoreTypes(c *FullNode) []Violation {
+	if !c.Chainstore.EnableSplitstore {
+		return nil
+	}
+
+	var out []Violation
+	if c.Chainstore.Splitstore.HotStoreType != "badger" {
+		out = append(out, errorf("Chainstore.Splitstore.HotStoreType", "unsupported hotstore type %q, only \"badger\" is supported", c.Chainstore.Splitstore.HotStoreType))
+	}
+
+	switch c.Chainstore.Splitstore.TrackingStoreType {
+	case "", "bolt":
+	case "mem":
+		out = append(out, warnf("Chainstore.Splitstore.TrackingStoreType", "\"mem\" tracking store is meant for tests and readonly access, tracking state will be lost on restart"))
+	default:
+		out = append(out, errorf("Chainstore.Splitstore.TrackingStoreType", "unsupported tracking store type %q", c.Chainstore.Splitstore.TrackingStoreType))
+	}
+
+	switch c.Chainstore.Splitstore.MarkSetType {
+	case "", "bloom", "bolt":
+	default:
+		out = append(out, errorf("Chainstore.Splitstore.MarkSetType", "unsupported mark set type %q", c.Chainstore.Splitstore.MarkSetType))
+	}
+
+	return out
+}
+
+func checkSplitstoreCompaction(c *FullNode) []Violation {
+	if !c.Chainstore.EnableSplitstore {
+		return nil
+	}
+
+	var out []Violation
+	if !c.Chainstore.Splitstore.EnableFullCompaction {
+		if c.Chainstore.Splitstore.EnableGC {
+			out = append(out, warnf("Chainstore.Splitstore.EnableGC", "has no effect without EnableFullCompaction"))
+		}
+		if c.Chainstore.Splitstore.Archival {
+			out = append(out, warnf("Chainstore.Splitstore.Archival", "has no effect without EnableFullCompaction"))
+		}
+	} else if c.Chainstore.Splitstore.EnableGC {
+		out = append(out, warnf("Chainstore.Splitstore.EnableGC", "EXPERIMENTAL: unreachable objects will be deleted, don't enable on nodes backing miners"))
+	}
+
+	return out
+}
+
+func checkClientIpfs(c *FullNode) []Violation {
+	if c.Client.UseIpfs {
+		if _, err := multiaddr.NewMultiaddr(c.Client.IpfsMAddr); c.Client.IpfsMAddr != "" && err != nil {
+			return []Violation{errorf("Client.IpfsMAddr", "invalid multiaddr: %s", err)}
+		}
+		return nil
+	}
+
+	var out []Violation
+	if c.Client.IpfsUseForRetrieval {
+		out = append(out, warnf("Client.IpfsUseForRetrieval", "has no effect without Client.UseIpfs"))
+	}
+	if c.Client.IpfsOnlineMode {
+		out = append(out, warnf("Client.IpfsOnlineMode", "has no effect without Client.UseIpfs"))
+	}
+	return out
+}
+
+func checkWalletBackend(c *FullNode) []Violation {
+	if c.Wallet.DisableLocal && c.Wallet.RemoteBackend == "" && !c.Wallet.EnableLedger {
+		return []Violation{errorf("Wallet.DisableLocal", "local wallet is disabled, but no remote backend or ledger is enabled; the node won't be able to sign anything")}
+	}
+	return nil
+}
+
+func checkMetricsHistory(c *FullNode) []Violation {
+	h := c.Metrics.History
+	if !h.Enable {
+		return nil
+	}
+
+	var out []Violation
+	if len(h.Series) == 0 {
+		out = append(out, warnf("Metrics.History.Series", "no series to record"))
+	}
+	if len(h.Tiers) == 0 {
+		return append(out, errorf("Metrics.History.Tiers", "at least one tier is needed"))
+	}
+	for i, t := range h.Tiers {
+		path := fmt.Sprintf("Metrics.History.Tiers[%d]", i)
+		switch {
+		case t.Resolution <= 0:
+			out = append(out, errorf(path+".Resolution", "must be positive"))
+		case t.Retention < t.Resolution:
+			out = append(out, errorf(path+".Retention", "must be at least the resolution"))
+		case i > 0 && h.Tiers[i-1].Resolution > 0 && (t.Resolution <= h.Tiers[i-1].Resolution || t.Resolution%h.Tiers[i-1].Resolution != 0):
+			out = append(out, errorf(path+".Resolution", "must be a multiple of the resolution of the previous tier"))
+		case i > 0 && t.Retention <= h.Tiers[i-1].Retention:
+			out = append(out, errorf(path+".Retention", "must be longer than the retention of the previous tier"))
+		}
+	}
+	return out
+}
+
+// // Storage Miner
+
+func checkSealingBatchSizes(c *StorageMiner) []Violation {
+	var out []Violation
+	s := c.Sealing
+
+	if s.BatchPreCommits && (s.MaxPreCommitBatch < 1 || s.MaxPreCommitBatch > miner5.PreCommitSectorBatchMaxSize) {
+		out = append(out, errorf("Sealing.MaxPreCommitBatch", "must be between 1 and %d", miner5.PreCommitSectorBatchMaxSize))
+	}
+
+	if s.AggregateCommits {
+		if s.MinCommitBatch < miner5.MinAggregatedSectors {
+			out = append(out, errorf("Sealing.MinCommitBatch", "must be at least %d", miner5.MinAggregatedSectors))
+		}
+		if s.MaxCommitBatch > miner5.MaxAggregatedSectors {
+			out = append(out, errorf("Sealing.MaxCommitBatch", "must be at most %d", miner5.MaxAggregatedSectors))
+		}
+		if s.MinCommitBatch > s.MaxCommitBatch {
+			out = append(out, errorf("Sealing.MinCommitBatch", "is above Sealing.MaxCommitBatch (%d > %d)", s.MinCommitBatch, s.MaxCommitBatch))
+		}
+	}
+
+	if s.TerminateBatchMin > s.TerminateBatchMax {
+		out = append(out, errorf("Sealing.TerminateBatchMin", "is above Sealing.TerminateBatchMax (%d > %d)", s.TerminateBatchMin, s.TerminateBatchMax))
+	}
+
+	return out
+}
+
+// precommit tickets expire after 31.5 hours
+var maxPreCommitBatchWait = 31*time.Hour + 30*time.Minute
+
+func checkSealingBatchWait(c *StorageMiner) []Violation {
+	var out []Violation
+	s := c.Sealing
+
+	if s.BatchPreCommits && time.Duration(s.PreCommitBatchWait) >= maxPreCommitBatchWait {
+		out = append(out, warnf("Sealing.PreCommitBatchWait", "should be below %s, otherwise precommit tickets may expire before the batch is sent", maxPreCommitBatchWait))
+	}
+	if s.BatchPreCommits && s.PreCommitBatchSlack > s.PreCommitBatchWait {
+		out = append(out, warnf("Sealing.PreCommitBatchSlack", "is above Sealing.PreCommitBatchWait, batches will always be sent early"))
+	}
+	if s.AggregateCommits && s.CommitBatchSlack > s.CommitBatchWait {
+		out = append(out, warnf("Sealing.CommitBatchSlack", "is above Sealing.CommitBatchWait, batches will always be sent early"))
+	}
+
+	return out
+}
+
+func checkMinerMaxFees(c *StorageMiner) []Violation {
+	var out []Violation
+	fees := map[string]types.FIL{
+		"Fees.MaxPreCommitGasFee":     c.Fees.MaxPreCommitGasFee,
+		"Fees.MaxCommitGasFee":        c.Fees.MaxCommitGasFee,
+		"Fees.MaxTerminateGasFee":     c.Fees.MaxTerminateGasFee,
+		"Fees.MaxWindowPoStGasFee":    c.Fees.MaxWindowPoStGasFee,
+		"Fees.MaxPublishDealsFee":     c.Fees.MaxPublishDealsFee,
+		"Fees.MaxMarketBalanceAddFee": c.Fees.MaxMarketBalanceAddFee,
+	}
+	if c.Sealing.BatchPreCommits {
+		fees["Fees.MaxPreCommitBatchGasFee.PerSector"] = c.Fees.MaxPreCommitBatchGasFee.PerSector
+	}
+	if c.Sealing.AggregateCommits {
+		fees["Fees.MaxCommitBatchGasFee.PerSector"] = c.Fees.MaxCommitBatchGasFee.PerSector
+	}
+
+	for path, fee := range fees {
+		if fee.Int == nil || fee.Sign() <= 0 {
+			out = append(out, errorf(path, "must be above zero, otherwise messages can't pay for gas and will never land on chain"))
+		}
+	}
+
+	return out
+}
+
+func checkFeeEscalation(c *StorageMiner) []Violation {
+	esc := c.Fees.WindowPoStEscalation
+	if !esc.Enable {
+		return nil
+	}
+
+	var out []Violation
+	for i, st := range esc.Curve {
+		if st.Multiplier <= 0 {
+			out = append(out, errorf(fmt.Sprintf("Fees.WindowPoStEscalation.Curve[%d].Multiplier", i), "must be above zero"))
+		} else if st.Multiplier < 1 {
+			out = append(out, warnf(fmt.Sprintf("Fees.WindowPoStEscalation.Curve[%d].Multiplier", i), "is below 1, messages pay less than the estimated premium close to their deadline"))
+		}
+	}
+	if esc.EconomicalMultiplier <= 0 {
+		out = append(out, errorf("Fees.WindowPoStEscalation.EconomicalMultiplier", "must be above zero"))
+	}
+	if esc.MaxGasPremium.Int == nil || esc.MaxGasPremium.Sign() <= 0 {
+		out = append(out, errorf("Fees.WindowPoStEscalation.MaxGasPremium", "must be above zero, otherwise messages can't pay a premium to be included"))
+	}
+	return out
+}
+
+func checkDealPublish(c *StorageMiner) []Violation {
+	if c.Dealmaking.MaxDealsPerPublishMsg == 0 {
+		return []Violation{errorf("Dealmaking.MaxDealsPerPublishMsg", "must be above zero")}
+	}
+	return nil
+}
+
+func checkControlAddresses(c *StorageMiner) []Violation {
+	var out []Violation
+	check := func(path string, addrs []string) {
+		for i, a := range addrs {
+			if _, err := address.NewFromString(a); err != nil {
+				out = append(out, errorf(fmt.Sprintf("%s[%d]", path, i), "invalid address %q: %s", a, err))
+			}
+		}
+	}
+
+	check("Addresses.PreCommitControl", c.Addresses.PreCommitControl)
+	check("Addresses.CommitControl", c.Addresses.CommitControl)
+	check("Addresses.TerminateControl", c.Addresses.TerminateControl)
+
+	return out
+}
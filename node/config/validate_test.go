@@ -0,0 +1,219 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+func TestValidateDefaults(t *testing.T) {
+	require.Empty(t, Validate(DefaultFullNode()))
+	require.Empty(t, Validate(DefaultStorageMiner()))
+}
+
+func TestValidateRules(t *testing.T) {
+	type expect struct {
+		path string
+		sev  Severity
+	}
+
+	testCases := []struct {
+		name   string
+		cfg    func() interface{}
+		expect []expect
+	}{
+		{
+			name: "zero default max fee",
+			cfg: func() interface{} {
+				c := DefaultFullNode()
+				c.Fees.DefaultMaxFee = types.MustParseFIL("0")
+				return c
+			},
+			expect: []expect{{"Fees.DefaultMaxFee", SeverityError}},
+		},
+		{
+			name: "overlapping libp2p listen addresses",
+			cfg: func() interface{} {
+				c := DefaultFullNode()
+				c.Libp2p.ListenAddresses = []string{"/ip4/0.0.0.0/tcp/4001", "/ip4/127.0.0.1/tcp/4001", "/ip6/::/tcp/4001"}
+				return c
+			},
+			expect: []expect{{"Libp2p.ListenAddresses[1]", SeverityError}},
+		},
+		{
+			name: "api and libp2p on the same port",
+			cfg: func() interface{} {
+				c := DefaultStorageMiner()
+				c.Libp2p.ListenAddresses = []string{"/ip4/0.0.0.0/tcp/2345"}
+				return c
+			},
+			expect: []expect{{"Libp2p.ListenAddresses[0]", SeverityError}},
+		},
+		{
+			name: "invalid api address",
+			cfg: func() interface{} {
+				c := DefaultFullNode()
+				c.API.ListenAddress = "127.0.0.1:1234"
+				return c
+			},
+			expect: []expect{{"API.ListenAddress", SeverityError}},
+		},
+		{
+			name: "connmgr watermarks",
+			cfg: func() interface{} {
+				c := DefaultFullNode()
+				c.Libp2p.ConnMgrLow = 200
+				return c
+			},
+			expect: []expect{{"Libp2p.ConnMgrLow", SeverityError}},
+		},
+		{
+			name: "splitstore unsupported hotstore",
+			cfg: func() interface{} {
+				c := DefaultFullNode()
+				c.Chainstore.EnableSplitstore = true
+				c.Chainstore.Splitstore.HotStoreType = "leveldb"
+				return c
+			},
+			expect: []expect{{"Chainstore.Splitstore.HotStoreType", SeverityError}},
+		},
+		{
+			name: "splitstore settings ignored when disabled",
+			cfg: func() interface{} {
+				c := DefaultFullNode()
+				c.Chainstore.Splitstore.HotStoreType = "leveldb"
+				c.Chainstore.Splitstore.EnableGC = true
+				return c
+			},
+		},
+		{
+			name: "splitstore gc",
+			cfg: func() interface{} {
+				c := DefaultFullNode()
+				c.Chainstore.EnableSplitstore = true
+				c.Chainstore.Splitstore.EnableGC = true
+				return c
+			},
+			expect: []expect{{"Chainstore.Splitstore.EnableGC", SeverityWarning}},
+		},
+		{
+			name: "no wallet backend",
+			cfg: func() interface{} {
+				c := DefaultFullNode()
+				c.Wallet.DisableLocal = true
+				return c
+			},
+			expect: []expect{{"Wallet.DisableLocal", SeverityError}},
+		},
+		{
+			name: "metrics history tier finer than the previous one",
+			cfg: func() interface{} {
+				c := DefaultFullNode()
+				c.Metrics.History.Enable = true
+				c.Metrics.History.Tiers = append(c.Metrics.History.Tiers, MetricsHistoryTier{Resolution: 1000, Retention: 20 * 365 * 2880})
+				return c
+			},
+			expect: []expect{{"Metrics.History.Tiers[2].Resolution", SeverityError}},
+		},
+		{
+			name: "commit batch bounds",
+			cfg: func() interface{} {
+				c := DefaultStorageMiner()
+				c.Sealing.MinCommitBatch = 100
+				c.Sealing.MaxCommitBatch = 10
+				return c
+			},
+			expect: []expect{{"Sealing.MinCommitBatch", SeverityError}},
+		},
+		{
+			name: "precommit batch wait",
+			cfg: func() interface{} {
+				c := DefaultStorageMiner()
+				c.Sealing.PreCommitBatchWait = Duration(48 * time.Hour)
+				return c
+			},
+			expect: []expect{{"Sealing.PreCommitBatchWait", SeverityWarning}},
+		},
+		{
+			name: "zero miner fee",
+			cfg: func() interface{} {
+				c := DefaultStorageMiner()
+				c.Fees.MaxWindowPoStGasFee = types.MustParseFIL("0")
+				return c
+			},
+			expect: []expect{{"Fees.MaxWindowPoStGasFee", SeverityError}},
+		},
+		{
+			name: "fee escalation curve",
+			cfg: func() interface{} {
+				c := DefaultStorageMiner()
+				c.Fees.WindowPoStEscalation.Curve = []FeeEscalationStep{
+					{RemainingEpochs: 30, Multiplier: 0.5},
+					{RemainingEpochs: 10, Multiplier: 0},
+				}
+				return c
+			},
+			expect: []expect{
+				{"Fees.WindowPoStEscalation.Curve[0].Multiplier", SeverityWarning},
+				{"Fees.WindowPoStEscalation.Curve[1].Multiplier", SeverityError},
+			},
+		},
+		{
+			name: "bad control address",
+			cfg: func() interface{} {
+				c := DefaultStorageMiner()
+				c.Addresses.CommitControl = []string{"not-an-address"}
+				return c
+			},
+			expect: []expect{{"Addresses.CommitControl[0]", SeverityError}},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got []expect
+			for _, v := range Validate(tc.cfg()) {
+				got = append(got, expect{v.Path, v.Severity})
+			}
+			require.Equal(t, tc.expect, got)
+		})
+	}
+}
+
+func TestUnknownKeys(t *testing.T) {
+	vs, err := UnknownKeys(strings.NewReader(`
+		[API]
+		ListenAddres = "/ip4/127.0.0.1/tcp/1234/http"
+		[Fees]
+		DefaultMaxFee = "0.1"
+		`), DefaultFullNode())
+	require.NoError(t, err)
+	require.Len(t, vs, 1)
+	require.Equal(t, "API.ListenAddres", vs[0].Path)
+	require.Equal(t, SeverityWarning, vs[0].Severity)
+}
+
+func TestDiff(t *testing.T) {
+	a := DefaultStorageMiner()
+	b := DefaultStorageMiner()
+
+	d, err := Diff(a, b)
+	require.NoError(t, err)
+	require.Empty(t, d)
+
+	b.API.Timeout = Duration(time.Minute)
+	b.Fees.MaxCommitGasFee = types.MustParseFIL("1")
+	b.Addresses.PreCommitControl = nil // nil and empty are the same in toml
+
+	d, err = Diff(a, b)
+	require.NoError(t, err)
+	require.Equal(t, []string{"API.Timeout", "Fees.MaxCommitGasFee"}, d)
+
+	_, err = Diff(a, DefaultFullNode())
+	require.Error(t, err)
+}
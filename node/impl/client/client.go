@@ -46,7 +46,10 @@ import (
 	"github.com/filecoin-project/go-multistore"
 	"github.com/filecoin-project/go-state-types/abi"
 
+	"github.com/filecoin-project/lotus/markets/dealhealth"
 	marketevents "github.com/filecoin-project/lotus/markets/loggers"
+	"github.com/filecoin-project/lotus/markets/retrievalstats"
+	"github.com/filecoin-project/lotus/markets/sealingstatus"
 
 	"github.com/filecoin-project/lotus/api"
 	"github.com/filecoin-project/lotus/build"
@@ -74,6 +77,8 @@ type API struct {
 	full.StateAPI
 
 	SMDealClient storagemarket.StorageClient
+	DealHealth   *dealhealth.Watcher
+	RetStats     *retrievalstats.Store
 	RetDiscovery discovery.PeerResolver
 	Retrieval    rm.RetrievalClient
 	Chain        *store.ChainStore
@@ -260,6 +265,42 @@ func (a *API) ClientGetDealUpdates(ctx context.Context) (<-chan api.DealInfo, er
 	return updates, nil
 }
 
+func (a *API) ClientDealHealth(ctx context.Context) ([]api.DealHealth, error) {
+	return a.DealHealth.Health(ctx)
+}
+
+func (a *API) ClientDealHealthUpdates(ctx context.Context) (<-chan api.DealHealth, error) {
+	return a.DealHealth.Updates(ctx)
+}
+
+func (a *API) ClientDealSealingStatus(ctx context.Context, proposal cid.Cid) (*api.DealSealingStatus, error) {
+	deal, err := a.SMDealClient.GetLocalDeal(ctx, proposal)
+	if err != nil {
+		return nil, err
+	}
+
+	mi, err := a.StateMinerInfo(ctx, deal.Proposal.Provider, types.EmptyTSK)
+	if err != nil {
+		return nil, xerrors.Errorf("getting provider info: %w", err)
+	}
+	if mi.PeerId == nil {
+		return nil, xerrors.Errorf("provider %s has no peer ID set", deal.Proposal.Provider)
+	}
+
+	pi := utils.NewStorageProviderInfo(deal.Proposal.Provider, mi.Worker, mi.SectorSize, *mi.PeerId, mi.Multiaddrs)
+	if err := a.Host.Connect(ctx, peer.AddrInfo{ID: pi.PeerID, Addrs: pi.Addrs}); err != nil {
+		return nil, xerrors.Errorf("connecting to provider: %w", err)
+	}
+
+	// the provider only discloses the status to the client of the deal
+	sig, err := a.WalletSign(ctx, deal.Proposal.Client, proposal.Bytes())
+	if err != nil {
+		return nil, xerrors.Errorf("signing request: %w", err)
+	}
+
+	return sealingstatus.Query(ctx, a.Host, pi.PeerID, proposal, sig)
+}
+
 func (a *API) newDealInfo(ctx context.Context, v storagemarket.ClientDeal) api.DealInfo {
 	// Find the data transfer associated with this deal
 	var transferCh *api.DataTransferChannel
@@ -536,7 +577,7 @@ type retrievalSubscribeEvent struct {
 	state rm.ClientDealState
 }
 
-func readSubscribeEvents(ctx context.Context, dealID retrievalmarket.DealID, subscribeEvents chan retrievalSubscribeEvent, events chan marketevents.RetrievalEvent) error {
+func readSubscribeEvents(ctx context.Context, dealID retrievalmarket.DealID, subscribeEvents chan retrievalSubscribeEvent, events chan marketevents.RetrievalEvent, attempt *retrievalstats.Attempt) error {
 	for {
 		var subscribeEvent retrievalSubscribeEvent
 		select {
@@ -563,6 +604,7 @@ func readSubscribeEvents(ctx context.Context, dealID retrievalmarket.DealID, sub
 		}
 
 		state := subscribeEvent.state
+		attempt.Progress(state.TotalReceived, state.FundsSpent)
 		switch state.Status {
 		case rm.DealStatusCompleted:
 			return nil
@@ -651,6 +693,8 @@ func (a *API) clientRetrieve(ctx context.Context, order api.RetrievalOrder, ref
 			}
 		})
 
+		attempt := a.RetStats.Begin(order.Miner, order.Root, order.Size)
+
 		dealID, err := a.Retrieval.Retrieve(
 			ctx,
 			order.Root,
@@ -663,13 +707,15 @@ func (a *API) clientRetrieve(ctx context.Context, order api.RetrievalOrder, ref
 
 		if err != nil {
 			unsubscribe()
+			attempt.Done(err)
 			finish(xerrors.Errorf("Retrieve failed: %w", err))
 			return
 		}
 
-		err = readSubscribeEvents(ctx, dealID, subscribeEvents, events)
+		err = readSubscribeEvents(ctx, dealID, subscribeEvents, events, attempt)
 
 		unsubscribe()
+		attempt.Done(err)
 		if err != nil {
 			finish(xerrors.Errorf("Retrieve: %w", err))
 			return
@@ -725,6 +771,18 @@ func (a *API) clientRetrieve(ctx context.Context, order api.RetrievalOrder, ref
 	return
 }
 
+func (a *API) ClientRetrievalStats(ctx context.Context, provider address.Address) ([]api.RetrievalProviderStats, error) {
+	return a.RetStats.Stats(provider)
+}
+
+func (a *API) ClientRetrievalHistory(ctx context.Context, provider address.Address, limit int) ([]api.RetrievalRecord, error) {
+	return a.RetStats.Records(provider, limit)
+}
+
+func (a *API) ClientRetrievalStatsImport(ctx context.Context, records []api.RetrievalRecord) (int, error) {
+	return a.RetStats.Import(records)
+}
+
 type multiStoreRetrievalStore struct {
 	storeID multistore.StoreID
 	store   *multistore.Store
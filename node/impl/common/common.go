@@ -1,21 +1,28 @@
 package common
 
 import (
+	"bytes"
 	"context"
+	"regexp"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/gbrlsnchs/jwt/v3"
 	"github.com/google/uuid"
 	"go.uber.org/fx"
 	"golang.org/x/xerrors"
 
+	"github.com/ipfs/go-cid"
 	logging "github.com/ipfs/go-log/v2"
 	"github.com/libp2p/go-libp2p-core/host"
 	metrics "github.com/libp2p/go-libp2p-core/metrics"
 	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/peer"
 	protocol "github.com/libp2p/go-libp2p-core/protocol"
+	"github.com/libp2p/go-libp2p-core/routing"
+	dht "github.com/libp2p/go-libp2p-kad-dht"
 	swarm "github.com/libp2p/go-libp2p-swarm"
 	basichost "github.com/libp2p/go-libp2p/p2p/host/basic"
 	"github.com/libp2p/go-libp2p/p2p/net/conngater"
@@ -26,6 +33,8 @@ import (
 	"github.com/filecoin-project/lotus/api"
 	apitypes "github.com/filecoin-project/lotus/api/types"
 	"github.com/filecoin-project/lotus/build"
+	"github.com/filecoin-project/lotus/lib/loglevels"
+	"github.com/filecoin-project/lotus/node/config"
 	"github.com/filecoin-project/lotus/node/modules/dtypes"
 	"github.com/filecoin-project/lotus/node/modules/lp2p"
 )
@@ -43,6 +52,9 @@ type CommonAPI struct {
 	Reporter     metrics.Reporter
 	Sk           *dtypes.ScoreKeeper
 	ShutdownChan dtypes.ShutdownChan
+	LoadedConfig dtypes.LoadedConfig
+	History      *config.History `optional:"true"`
+	Logging      *loglevels.Levels
 }
 
 type jwtPayload struct {
@@ -155,6 +167,69 @@ func (a *CommonAPI) NetFindPeer(ctx context.Context, p peer.ID) (peer.AddrInfo,
 	return a.Router.FindPeer(ctx, p)
 }
 
+func (a *CommonAPI) NetFindPeerDHT(ctx context.Context, p peer.ID) (peer.AddrInfo, error) {
+	d, ok := a.Router.(*dht.IpfsDHT)
+	if !ok {
+		return peer.AddrInfo{}, xerrors.Errorf("the DHT is disabled on this node")
+	}
+
+	// the lookup adds the addresses it learns to the peerstore
+	closest, err := d.GetClosestPeers(ctx, string(p))
+	if err != nil {
+		return peer.AddrInfo{}, xerrors.Errorf("DHT lookup: %w", err)
+	}
+	found := false
+	for cp := range closest {
+		found = found || cp == p
+	}
+	if err := ctx.Err(); err != nil {
+		return peer.AddrInfo{}, err
+	}
+	if !found {
+		return peer.AddrInfo{}, routing.ErrNotFound
+	}
+	return a.Host.Peerstore().PeerInfo(p), nil
+}
+
+func (a *CommonAPI) NetFindProviders(ctx context.Context, c cid.Cid, count int) ([]api.ProviderRecord, error) {
+	if count <= 0 {
+		return nil, xerrors.Errorf("count must be positive")
+	}
+
+	start := time.Now()
+	out := []api.ProviderRecord{}
+	for p := range a.Router.FindProvidersAsync(ctx, c, count) {
+		out = append(out, api.ProviderRecord{Peer: p, FoundAfter: time.Since(start)})
+	}
+	if err := ctx.Err(); err != nil && len(out) == 0 {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (a *CommonAPI) NetDialAddrs(ctx context.Context, p peer.AddrInfo) ([]api.DialResult, error) {
+	sw, ok := a.RawHost.Network().(*swarm.Swarm)
+	if !ok {
+		return nil, xerrors.Errorf("unexpected network type %T", a.RawHost.Network())
+	}
+
+	out := make([]api.DialResult, 0, len(p.Addrs))
+	for _, addr := range p.Addrs {
+		res := api.DialResult{Addr: addr.String()}
+		start := time.Now()
+		if tpt := sw.TransportForDialing(addr); tpt == nil {
+			res.Error = "no transport for the address"
+		} else if conn, err := tpt.Dial(ctx, addr, p.ID); err != nil {
+			res.Error = err.Error()
+		} else {
+			_ = conn.Close()
+		}
+		res.Time = time.Since(start)
+		out = append(out, res)
+	}
+	return out, nil
+}
+
 func (a *CommonAPI) NetAutoNatStatus(ctx context.Context) (i api.NatInfo, err error) {
 	autonat := a.RawHost.(*basichost.BasicHost).AutoNat
 
@@ -226,7 +301,8 @@ func (a *CommonAPI) Version(context.Context) (api.APIVersion, error) {
 		Version:    build.UserVersion(),
 		APIVersion: v,
 
-		BlockDelay: build.BlockDelaySecs,
+		BlockDelay:    build.BlockDelaySecs,
+		BlockGasLimit: build.BlockGasLimit,
 	}, nil
 }
 
@@ -235,7 +311,20 @@ func (a *CommonAPI) LogList(context.Context) ([]string, error) {
 }
 
 func (a *CommonAPI) LogSetLevel(ctx context.Context, subsystem, level string) error {
-	return logging.SetLogLevel(subsystem, level)
+	match := regexp.QuoteMeta(subsystem)
+	if subsystem == "*" {
+		match = ".*"
+	}
+	_, err := a.Logging.Set(match, level, 0)
+	return err
+}
+
+func (a *CommonAPI) LogLevels(context.Context) ([]api.LogSystem, error) {
+	return a.Logging.List(), nil
+}
+
+func (a *CommonAPI) LogSetLevels(ctx context.Context, match, level string, d time.Duration) ([]string, error) {
+	return a.Logging.Set(match, level, d)
 }
 
 func (a *CommonAPI) Shutdown(ctx context.Context) error {
@@ -251,4 +340,19 @@ func (a *CommonAPI) Closing(ctx context.Context) (<-chan struct{}, error) {
 	return make(chan struct{}), nil // relies on jsonrpc closing
 }
 
+func (a *CommonAPI) ConfigLoaded(context.Context) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := toml.NewEncoder(buf).Encode(a.LoadedConfig); err != nil {
+		return nil, xerrors.Errorf("encoding config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (a *CommonAPI) ConfigHistory(context.Context) ([]api.ConfigSnapshot, error) {
+	if a.History == nil {
+		return nil, xerrors.Errorf("the node keeps no config history")
+	}
+	return a.History.Snapshots()
+}
+
 var _ api.Common = &CommonAPI{}
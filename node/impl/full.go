@@ -2,10 +2,14 @@ package impl
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	logging "github.com/ipfs/go-log/v2"
 
 	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/lib/headpin"
+	"github.com/filecoin-project/lotus/lib/rpcrecord"
 	"github.com/filecoin-project/lotus/node/impl/client"
 	"github.com/filecoin-project/lotus/node/impl/common"
 	"github.com/filecoin-project/lotus/node/impl/full"
@@ -30,11 +34,41 @@ type FullNodeAPI struct {
 	full.SyncAPI
 	full.BeaconAPI
 
-	DS dtypes.MetadataDS
+	DS       dtypes.MetadataDS
+	Recorder *rpcrecord.Recorder
+	HeadPins *headpin.Pins
 }
 
 func (n *FullNodeAPI) CreateBackup(ctx context.Context, fpath string) error {
 	return backup(n.DS, fpath)
 }
 
+func (n *FullNodeAPI) Methods(ctx context.Context) (map[string]api.MethodStability, error) {
+	return api.GetMethodStability(new(api.FullNodeStruct)), nil
+}
+
+func (n *FullNodeAPI) ProjectedCall(ctx context.Context, method string, params []json.RawMessage, fields []string) (json.RawMessage, error) {
+	return api.CallProjected(ctx, (*api.FullNode)(nil), n, method, params, fields)
+}
+
+func (n *FullNodeAPI) RPCRecordStart(ctx context.Context, cfg api.RPCRecordConfig) error {
+	return n.Recorder.Start(cfg)
+}
+
+func (n *FullNodeAPI) RPCRecordStop(ctx context.Context) (api.RPCRecordStatus, error) {
+	return n.Recorder.Stop()
+}
+
+func (n *FullNodeAPI) RPCRecordStatus(ctx context.Context) (api.RPCRecordStatus, error) {
+	return n.Recorder.Status(), nil
+}
+
+func (n *FullNodeAPI) ChainPinHead(ctx context.Context, ttl time.Duration) (api.PinnedHead, error) {
+	return n.HeadPins.Pin(n.ChainAPI.Chain.GetHeaviestTipSet(), ttl)
+}
+
+func (n *FullNodeAPI) ChainUnpinHead(ctx context.Context, session string) error {
+	return n.HeadPins.Unpin(session)
+}
+
 var _ api.FullNode = &FullNodeAPI{}
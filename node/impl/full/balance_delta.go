@@ -0,0 +1,225 @@
+package full
+
+import (
+	"context"
+
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/chain/actors/builtin"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/market"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/miner"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/reward"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+func (a *StateAPI) StateAccountBalanceDelta(ctx context.Context, addr address.Address, fromKey, toKey types.TipSetKey) (*api.BalanceDelta, error) {
+	from, err := a.Chain.GetTipSetFromKey(fromKey)
+	if err != nil {
+		return nil, xerrors.Errorf("loading tipset %s: %w", fromKey, err)
+	}
+	to, err := a.Chain.GetTipSetFromKey(toKey)
+	if err != nil {
+		return nil, xerrors.Errorf("loading tipset %s: %w", toKey, err)
+	}
+	if from.Height() > to.Height() {
+		return nil, xerrors.Errorf("from tipset at %d is after to tipset at %d", from.Height(), to.Height())
+	}
+
+	anc, err := a.Chain.GetTipsetByHeight(ctx, from.Height(), to, true)
+	if err != nil {
+		return nil, xerrors.Errorf("walking back to %d: %w", from.Height(), err)
+	}
+	if anc.Key() != from.Key() {
+		return nil, xerrors.Errorf("from tipset %s is not an ancestor of to tipset %s", from.Key(), to.Key())
+	}
+
+	complete := true
+	if to.Height()-from.Height() > api.BalanceDeltaMaxEpochs {
+		to, err = a.Chain.GetTipsetByHeight(ctx, from.Height()+api.BalanceDeltaMaxEpochs, to, true)
+		if err != nil {
+			return nil, xerrors.Errorf("loading end of range: %w", err)
+		}
+		complete = false
+	}
+
+	// messages may refer to the actor by any of its addresses
+	self := map[address.Address]bool{addr: true}
+	if id, err := a.StateManager.LookupID(ctx, addr, to); err == nil {
+		self[id] = true
+	}
+	if key, err := a.StateManager.ResolveToKeyAddress(ctx, addr, to); err == nil {
+		self[key] = true
+	}
+
+	// the tipsets whose execution leads from the state of from to the one of to
+	var tss []*types.TipSet
+	for ts := to; ts.Height() > from.Height(); {
+		ts, err = a.Chain.LoadTipSet(ts.Parents())
+		if err != nil {
+			return nil, xerrors.Errorf("loading parent tipset: %w", err)
+		}
+		tss = append(tss, ts)
+	}
+
+	out := &api.BalanceDelta{
+		Address:    addr,
+		From:       from.Key(),
+		To:         to.Key(),
+		FromHeight: from.Height(),
+		ToHeight:   to.Height(),
+		Items:      []api.BalanceDeltaItem{},
+		Complete:   complete,
+	}
+
+	for i := len(tss) - 1; i >= 0; i-- {
+		_, trace, err := a.StateManager.ExecutionTrace(ctx, tss[i])
+		if err != nil {
+			return nil, xerrors.Errorf("replaying tipset at %d: %w", tss[i].Height(), err)
+		}
+		for _, ir := range trace {
+			out.Items = append(out.Items, attributeInvocation(self, tss[i].Height(), ir)...)
+		}
+	}
+
+	fromAct, err := a.loadActorOrNil(ctx, addr, from)
+	if err != nil {
+		return nil, err
+	}
+	toAct, err := a.loadActorOrNil(ctx, addr, to)
+	if err != nil {
+		return nil, err
+	}
+
+	out.FromBalance, out.ToBalance = big.Zero(), big.Zero()
+	if fromAct != nil {
+		out.FromBalance = fromAct.Balance
+	}
+	if toAct != nil {
+		out.ToBalance = toAct.Balance
+	}
+	out.Delta = big.Sub(out.ToBalance, out.FromBalance)
+
+	attributed := big.Zero()
+	for _, it := range out.Items {
+		attributed = big.Add(attributed, it.Amount)
+	}
+	out.Unattributed = big.Sub(out.Delta, attributed)
+
+	if toAct != nil && builtin.IsStorageMinerActor(toAct.Code) {
+		fromLocked, err := a.lockedFunds(ctx, fromAct)
+		if err != nil {
+			return nil, xerrors.Errorf("loading locked funds at %d: %w", from.Height(), err)
+		}
+		toLocked, err := a.lockedFunds(ctx, toAct)
+		if err != nil {
+			return nil, xerrors.Errorf("loading locked funds at %d: %w", to.Height(), err)
+		}
+		out.Locked = &api.BalanceDeltaLocked{
+			VestingFunds:      big.Sub(toLocked.VestingFunds, fromLocked.VestingFunds),
+			InitialPledge:     big.Sub(toLocked.InitialPledgeRequirement, fromLocked.InitialPledgeRequirement),
+			PreCommitDeposits: big.Sub(toLocked.PreCommitDeposits, fromLocked.PreCommitDeposits),
+		}
+	}
+
+	return out, nil
+}
+
+// loadActorOrNil loads the actor from the parent state of ts, returning nil
+// if it doesn't exist yet
+func (a *StateAPI) loadActorOrNil(ctx context.Context, addr address.Address, ts *types.TipSet) (*types.Actor, error) {
+	act, err := a.StateManager.LoadActor(ctx, addr, ts)
+	if xerrors.Is(err, types.ErrActorNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("loading actor at %d: %w", ts.Height(), err)
+	}
+	return act, nil
+}
+
+// lockedFunds returns the locked funds of the miner, all zero if it's nil
+func (a *StateAPI) lockedFunds(ctx context.Context, act *types.Actor) (miner.LockedFunds, error) {
+	if act == nil || !builtin.IsStorageMinerActor(act.Code) {
+		return miner.LockedFunds{
+			VestingFunds:             big.Zero(),
+			InitialPledgeRequirement: big.Zero(),
+			PreCommitDeposits:        big.Zero(),
+		}, nil
+	}
+	mas, err := miner.Load(a.StateManager.ChainStore().ActorStore(ctx), act)
+	if err != nil {
+		return miner.LockedFunds{}, xerrors.Errorf("failed to load miner actor state: %w", err)
+	}
+	return mas.LockedFunds()
+}
+
+// attributeInvocation returns the changes of the balance of the actor known by
+// the self addresses made by the invocation executed at height: the gas it
+// paid for the message if it sent it, and the value of the successful sends
+// to or from it anywhere in the execution trace
+func attributeInvocation(self map[address.Address]bool, height abi.ChainEpoch, ir *api.InvocResult) []api.BalanceDeltaItem {
+	var items []api.BalanceDeltaItem
+
+	if ir.Msg != nil && self[ir.Msg.From] && !ir.GasCost.TotalCost.Nil() && !ir.GasCost.TotalCost.IsZero() {
+		items = append(items, api.BalanceDeltaItem{
+			Height:       height,
+			Kind:         api.BalanceGas,
+			Amount:       ir.GasCost.TotalCost.Neg(),
+			Message:      ir.MsgCid,
+			Counterparty: ir.Msg.To,
+			Method:       ir.Msg.Method,
+		})
+	}
+
+	var walk func(et *types.ExecutionTrace)
+	walk = func(et *types.ExecutionTrace) {
+		// reverted calls moved no funds, and neither did their subcalls
+		if et.Msg == nil || et.MsgRct == nil || et.MsgRct.ExitCode != exitcode.Ok {
+			return
+		}
+
+		in, out := self[et.Msg.To], self[et.Msg.From]
+		if in != out && !et.Msg.Value.Nil() && !et.Msg.Value.IsZero() {
+			it := api.BalanceDeltaItem{
+				Height:  height,
+				Message: ir.MsgCid,
+				Method:  et.Msg.Method,
+			}
+			if in {
+				it.Amount = et.Msg.Value
+				it.Counterparty = et.Msg.From
+				it.Kind = api.BalanceTransferIn
+				switch it.Counterparty {
+				case reward.Address:
+					it.Kind = api.BalanceReward
+				case market.Address:
+					it.Kind = api.BalanceMarketWithdrawal
+				}
+			} else {
+				it.Amount = et.Msg.Value.Neg()
+				it.Counterparty = et.Msg.To
+				it.Kind = api.BalanceTransferOut
+				switch it.Counterparty {
+				case builtin.BurntFundsActorAddr:
+					it.Kind = api.BalanceBurn
+				case market.Address:
+					it.Kind = api.BalanceMarketDeposit
+				}
+			}
+			items = append(items, it)
+		}
+
+		for i := range et.Subcalls {
+			walk(&et.Subcalls[i])
+		}
+	}
+	walk(&ir.ExecutionTrace)
+
+	return items
+}
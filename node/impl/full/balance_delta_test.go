@@ -0,0 +1,89 @@
+package full
+
+import (
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/chain/actors/builtin"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/market"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/reward"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+func TestAttributeInvocation(t *testing.T) {
+	id, err := address.NewIDAddress(1000)
+	require.NoError(t, err)
+	key, err := address.NewSecp256k1Address([]byte("balance delta test key"))
+	require.NoError(t, err)
+	other, err := address.NewIDAddress(1001)
+	require.NoError(t, err)
+	self := map[address.Address]bool{id: true, key: true}
+
+	mcid, err := cid.Parse("bafy2bzacea3wsdh6y3a36tb3skempjoxqpuyompjbmfeyf34fi3uy6uue42v4")
+	require.NoError(t, err)
+
+	trace := func(from, to address.Address, value int64, code exitcode.ExitCode, sub ...types.ExecutionTrace) types.ExecutionTrace {
+		return types.ExecutionTrace{
+			Msg:      &types.Message{From: from, To: to, Value: big.NewInt(value), Method: 2},
+			MsgRct:   &types.MessageReceipt{ExitCode: code},
+			Subcalls: sub,
+		}
+	}
+
+	// a message sent by the actor, with the key address, funding the market
+	// and paying another actor, whose reverted send back is ignored
+	msg := &types.Message{From: key, To: other, Method: 2}
+	items := attributeInvocation(self, 10, &api.InvocResult{
+		MsgCid:  mcid,
+		Msg:     msg,
+		GasCost: api.MsgGasCost{TotalCost: big.NewInt(7)},
+		ExecutionTrace: trace(key, other, 100, exitcode.Ok,
+			trace(other, market.Address, 0, exitcode.Ok),
+			trace(id, market.Address, 20, exitcode.Ok),
+			trace(other, id, 5, exitcode.ErrForbidden),
+			trace(other, other, 5, exitcode.Ok),
+		),
+	})
+	require.Equal(t, []api.BalanceDeltaItem{
+		{Height: 10, Kind: api.BalanceGas, Amount: big.NewInt(-7), Message: mcid, Counterparty: other, Method: 2},
+		{Height: 10, Kind: api.BalanceTransferOut, Amount: big.NewInt(-100), Message: mcid, Counterparty: other, Method: 2},
+		{Height: 10, Kind: api.BalanceMarketDeposit, Amount: big.NewInt(-20), Message: mcid, Counterparty: market.Address, Method: 2},
+	}, items)
+
+	// an implicit message rewarding the actor, which burns part of it
+	items = attributeInvocation(self, 11, &api.InvocResult{
+		MsgCid:  mcid,
+		Msg:     &types.Message{From: builtin.SystemActorAddr, To: reward.Address},
+		GasCost: api.MsgGasCost{TotalCost: abi.TokenAmount{}},
+		ExecutionTrace: trace(builtin.SystemActorAddr, reward.Address, 0, exitcode.Ok,
+			trace(reward.Address, id, 50, exitcode.Ok,
+				trace(id, builtin.BurntFundsActorAddr, 3, exitcode.Ok),
+			),
+			trace(market.Address, id, 4, exitcode.Ok),
+		),
+	})
+	require.Equal(t, []api.BalanceDeltaItem{
+		{Height: 11, Kind: api.BalanceReward, Amount: big.NewInt(50), Message: mcid, Counterparty: reward.Address, Method: 2},
+		{Height: 11, Kind: api.BalanceBurn, Amount: big.NewInt(-3), Message: mcid, Counterparty: builtin.BurntFundsActorAddr, Method: 2},
+		{Height: 11, Kind: api.BalanceMarketWithdrawal, Amount: big.NewInt(4), Message: mcid, Counterparty: market.Address, Method: 2},
+	}, items)
+
+	// failed messages only cost gas
+	items = attributeInvocation(self, 12, &api.InvocResult{
+		MsgCid:         mcid,
+		Msg:            msg,
+		GasCost:        api.MsgGasCost{TotalCost: big.NewInt(9)},
+		ExecutionTrace: trace(key, other, 100, exitcode.SysErrInsufficientFunds),
+	})
+	require.Equal(t, []api.BalanceDeltaItem{
+		{Height: 12, Kind: api.BalanceGas, Amount: big.NewInt(-9), Message: mcid, Counterparty: other, Method: 2},
+	}, items)
+}
@@ -34,6 +34,8 @@ import (
 
 	"github.com/filecoin-project/lotus/api"
 	"github.com/filecoin-project/lotus/blockstore"
+	"github.com/filecoin-project/lotus/chain/follow"
+	"github.com/filecoin-project/lotus/chain/history"
 	"github.com/filecoin-project/lotus/chain/store"
 	"github.com/filecoin-project/lotus/chain/types"
 	"github.com/filecoin-project/lotus/chain/vm"
@@ -77,7 +79,9 @@ type ChainAPI struct {
 	WalletAPI
 	ChainModuleAPI
 
-	Chain *store.ChainStore
+	Chain    *store.ChainStore
+	Follower *follow.Follower  `optional:"true"`
+	Metrics  *history.Recorder `optional:"true"`
 
 	// ExposedBlockstore is the global monolith blockstore that is safe to
 	// expose externally. In the future, this will be segregated into two
@@ -617,3 +621,34 @@ func (a *ChainAPI) ChainExport(ctx context.Context, nroots abi.ChainEpoch, skipo
 
 	return out, nil
 }
+
+func (a *ChainAPI) ChainFollowConsumers(context.Context) ([]api.ChainFollowConsumer, error) {
+	if a.Follower == nil {
+		return []api.ChainFollowConsumer{}, nil
+	}
+	return a.Follower.Consumers(), nil
+}
+
+func (a *ChainAPI) ChainGetTipSetByHeightPolicy(ctx context.Context, h abi.ChainEpoch, policy api.NullRoundPolicy, tsk types.TipSetKey) (*types.TipSet, error) {
+	ts, err := a.Chain.GetTipSetFromKey(tsk)
+	if err != nil {
+		return nil, xerrors.Errorf("loading tipset %s: %w", tsk, err)
+	}
+	return a.Chain.GetTipsetByHeightPolicy(ctx, h, ts, policy)
+}
+
+var errMetricsHistoryDisabled = xerrors.New("metrics history isn't enabled, set Metrics.History.Enable in the config")
+
+func (a *ChainAPI) ChainMetricsSeries(context.Context) ([]api.MetricsSeries, error) {
+	if a.Metrics == nil {
+		return nil, errMetricsHistoryDisabled
+	}
+	return a.Metrics.Series()
+}
+
+func (a *ChainAPI) ChainMetricsQuery(ctx context.Context, series string, from, to, resolution abi.ChainEpoch) ([]api.MetricsPoint, error) {
+	if a.Metrics == nil {
+		return nil, errMetricsHistoryDisabled
+	}
+	return a.Metrics.Query(series, from, to, resolution)
+}
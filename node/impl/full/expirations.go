@@ -0,0 +1,179 @@
+package full
+
+import (
+	"context"
+	"sort"
+
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/chain/actors/builtin"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/miner"
+	"github.com/filecoin-project/lotus/chain/actors/policy"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+// expirationBucketSize groups sector expirations by (30 day) month
+const expirationBucketSize = 30 * builtin.EpochsInDay
+
+func (a *StateAPI) StateMinerExpirationLadder(ctx context.Context, maddr address.Address, maxShare float64, tsk types.TipSetKey) (*api.ExpirationLadder, error) {
+	if maxShare <= 0 || maxShare > 1 {
+		return nil, xerrors.Errorf("max share must be in (0, 1], was %f", maxShare)
+	}
+
+	ts, err := a.Chain.GetTipSetFromKey(tsk)
+	if err != nil {
+		return nil, xerrors.Errorf("loading tipset %s: %w", tsk, err)
+	}
+
+	act, err := a.StateManager.LoadActor(ctx, maddr, ts)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load miner actor: %w", err)
+	}
+
+	mas, err := miner.Load(a.StateManager.ChainStore().ActorStore(ctx), act)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load miner actor state: %w", err)
+	}
+
+	info, err := mas.Info()
+	if err != nil {
+		return nil, xerrors.Errorf("loading miner info: %w", err)
+	}
+
+	live, err := miner.AllPartSectors(mas, miner.Partition.LiveSectors)
+	if err != nil {
+		return nil, xerrors.Errorf("merge partition live sets: %w", err)
+	}
+
+	sectors, err := mas.LoadSectors(&live)
+	if err != nil {
+		return nil, xerrors.Errorf("loading live sectors: %w", err)
+	}
+
+	nv := a.StateManager.GetNtwkVersion(ctx, ts.Height())
+	maxExtension := ts.Height() + policy.GetMaxSectorExpirationExtension()
+	maxExpiration := func(si *miner.SectorOnChainInfo) abi.ChainEpoch {
+		exp := si.Activation + policy.GetSectorMaxLifetime(si.SealProof, nv)
+		if exp > maxExtension {
+			exp = maxExtension
+		}
+		return exp
+	}
+
+	return expirationLadder(sectors, ts.Height(), info.SectorSize, maxShare, maxExpiration, policy.GetAddressedSectorsMax(nv)), nil
+}
+
+// expirationLadder buckets the sectors by expiration. Sectors in buckets over
+// maxShare are moved to the earliest later bucket with room which they can
+// still be extended to, one suggested extension per target bucket, split in
+// batches of at most maxBatch sectors.
+func expirationLadder(sectors []*miner.SectorOnChainInfo, height abi.ChainEpoch, ssize abi.SectorSize, maxShare float64, maxExpiration func(*miner.SectorOnChainInfo) abi.ChainEpoch, maxBatch int) *api.ExpirationLadder {
+	out := &api.ExpirationLadder{
+		Height:      height,
+		BucketSize:  expirationBucketSize,
+		Buckets:     []api.ExpirationBucket{},
+		Suggestions: []api.ExpirationExtension{},
+	}
+
+	bucketOf := func(exp abi.ChainEpoch) int {
+		if exp < height {
+			return 0
+		}
+		return int((exp - height) / expirationBucketSize)
+	}
+	bucketStart := func(i int) abi.ChainEpoch {
+		return height + abi.ChainEpoch(i)*expirationBucketSize
+	}
+
+	sort.Slice(sectors, func(i, j int) bool {
+		return sectors[i].SectorNumber < sectors[j].SectorNumber
+	})
+
+	var inBucket [][]*miner.SectorOnChainInfo
+	for _, si := range sectors {
+		b := bucketOf(si.Expiration)
+		for len(out.Buckets) <= b {
+			out.Buckets = append(out.Buckets, api.ExpirationBucket{
+				Start:    bucketStart(len(out.Buckets)),
+				RawBytes: big.Zero(),
+				QAPower:  big.Zero(),
+				Pledge:   big.Zero(),
+			})
+			inBucket = append(inBucket, nil)
+		}
+
+		bk := &out.Buckets[b]
+		bk.Sectors++
+		bk.RawBytes = big.Add(bk.RawBytes, big.NewIntUnsigned(uint64(ssize)))
+		bk.QAPower = big.Add(bk.QAPower, builtin.QAPowerForWeight(ssize, si.Expiration-si.Activation, si.DealWeight, si.VerifiedDealWeight))
+		bk.Pledge = big.Add(bk.Pledge, si.InitialPledge)
+		inBucket[b] = append(inBucket[b], si)
+	}
+
+	limit := uint64(maxShare * float64(len(sectors)))
+	if limit == 0 {
+		limit = 1
+	}
+
+	counts := make([]uint64, len(out.Buckets))
+	for i := range out.Buckets {
+		counts[i] = out.Buckets[i].Sectors
+		out.Buckets[i].Concentrated = counts[i] > limit
+	}
+
+	moves := map[abi.ChainEpoch][]abi.SectorNumber{}
+	for i := range inBucket {
+		for _, si := range inBucket[i] {
+			if counts[i] <= limit {
+				break
+			}
+
+			maxExp := maxExpiration(si)
+			for j := i + 1; bucketStart(j) <= maxExp; j++ {
+				for len(counts) <= j {
+					counts = append(counts, 0)
+				}
+				if counts[j] >= limit {
+					continue
+				}
+
+				newExp := bucketStart(j) + expirationBucketSize/2
+				if newExp > maxExp {
+					newExp = maxExp
+				}
+
+				counts[i]--
+				counts[j]++
+				moves[newExp] = append(moves[newExp], si.SectorNumber)
+				break
+			}
+		}
+	}
+
+	for exp, sns := range moves {
+		for len(sns) > 0 {
+			n := len(sns)
+			if maxBatch > 0 && n > maxBatch {
+				n = maxBatch
+			}
+			out.Suggestions = append(out.Suggestions, api.ExpirationExtension{
+				NewExpiration: exp,
+				Sectors:       sns[:n],
+			})
+			sns = sns[n:]
+		}
+	}
+	sort.Slice(out.Suggestions, func(i, j int) bool {
+		if out.Suggestions[i].NewExpiration != out.Suggestions[j].NewExpiration {
+			return out.Suggestions[i].NewExpiration < out.Suggestions[j].NewExpiration
+		}
+		return out.Suggestions[i].Sectors[0] < out.Suggestions[j].Sectors[0]
+	})
+
+	return out
+}
@@ -0,0 +1,66 @@
+package full
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/lotus/chain/actors/builtin/miner"
+)
+
+func TestExpirationLadder(t *testing.T) {
+	const height = abi.ChainEpoch(1000)
+
+	sector := func(n abi.SectorNumber, exp abi.ChainEpoch) *miner.SectorOnChainInfo {
+		return &miner.SectorOnChainInfo{
+			SectorNumber:       n,
+			Activation:         0,
+			Expiration:         exp,
+			DealWeight:         big.Zero(),
+			VerifiedDealWeight: big.Zero(),
+			InitialPledge:      big.NewInt(10),
+		}
+	}
+
+	// 6 sectors in the first month, one each in the second and third
+	var sectors []*miner.SectorOnChainInfo
+	for i := 0; i < 6; i++ {
+		sectors = append(sectors, sector(abi.SectorNumber(i), height+100))
+	}
+	sectors = append(sectors,
+		sector(6, height+expirationBucketSize+100),
+		sector(7, height+2*expirationBucketSize+100),
+	)
+
+	noLimit := func(*miner.SectorOnChainInfo) abi.ChainEpoch { return height + 10*expirationBucketSize }
+
+	ladder := expirationLadder(sectors, height, 2048, 0.25, noLimit, 2)
+	require.Len(t, ladder.Buckets, 3)
+	require.Equal(t, uint64(6), ladder.Buckets[0].Sectors)
+	require.True(t, ladder.Buckets[0].Concentrated)
+	require.False(t, ladder.Buckets[1].Concentrated)
+	require.Equal(t, big.NewInt(6*2048), ladder.Buckets[0].RawBytes)
+	require.Equal(t, big.NewInt(60), ladder.Buckets[0].Pledge)
+
+	// limit is 2 sectors per month: bucket 1 and 2 take one sector each, the
+	// last two go to the fourth month, in batches of 2
+	mid := func(i int) abi.ChainEpoch {
+		return height + abi.ChainEpoch(i)*expirationBucketSize + expirationBucketSize/2
+	}
+	require.Equal(t, []abi.SectorNumber{0}, ladder.Suggestions[0].Sectors)
+	require.Equal(t, mid(1), ladder.Suggestions[0].NewExpiration)
+	require.Equal(t, []abi.SectorNumber{1}, ladder.Suggestions[1].Sectors)
+	require.Equal(t, mid(2), ladder.Suggestions[1].NewExpiration)
+	require.Equal(t, []abi.SectorNumber{2, 3}, ladder.Suggestions[2].Sectors)
+	require.Equal(t, mid(3), ladder.Suggestions[2].NewExpiration)
+	require.Len(t, ladder.Suggestions, 3)
+
+	// sectors which can't be extended past their month are not suggested
+	noExtension := func(si *miner.SectorOnChainInfo) abi.ChainEpoch { return si.Expiration }
+	ladder = expirationLadder(sectors, height, 2048, 0.25, noExtension, 2)
+	require.True(t, ladder.Buckets[0].Concentrated)
+	require.Empty(t, ladder.Suggestions)
+}
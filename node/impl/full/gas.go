@@ -4,6 +4,7 @@ import (
 	"context"
 	"math"
 	"math/rand"
+	"path"
 	"sort"
 
 	"github.com/filecoin-project/lotus/chain/actors/builtin"
@@ -19,6 +20,7 @@ import (
 	"github.com/filecoin-project/go-state-types/exitcode"
 
 	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/api/errcode"
 	"github.com/filecoin-project/lotus/build"
 	"github.com/filecoin-project/lotus/chain/messagepool"
 	"github.com/filecoin-project/lotus/chain/stmgr"
@@ -43,6 +45,9 @@ type GasModule struct {
 	Mpool     *messagepool.MessagePool
 	GetMaxFee dtypes.DefaultMaxFeeFunc
 
+	GetMinPremium      dtypes.MinGasPremiumFunc   `optional:"true"`
+	GetGasLimitMargins dtypes.GasLimitMarginsFunc `optional:"true"`
+
 	PriceCache *GasPriceCache
 }
 
@@ -291,6 +296,9 @@ func gasEstimateGasLimit(
 	if err != nil {
 		return -1, xerrors.Errorf("CallWithGas failed: %w", err)
 	}
+	if res.MsgRct.ExitCode == exitcode.SysErrOutOfGas {
+		return -1, api.WrapError(errcode.OutOfGas, xerrors.Errorf("message execution failed: exit %s, reason: %s", res.MsgRct.ExitCode, res.Error))
+	}
 	if res.MsgRct.ExitCode != exitcode.Ok {
 		return -1, xerrors.Errorf("message execution failed: exit %s, reason: %s", res.MsgRct.ExitCode, res.Error)
 	}
@@ -324,11 +332,11 @@ func gasEstimateGasLimit(
 
 func (m *GasModule) GasEstimateMessageGas(ctx context.Context, msg *types.Message, spec *api.MessageSendSpec, _ types.TipSetKey) (*types.Message, error) {
 	if msg.GasLimit == 0 {
-		gasLimit, err := m.GasEstimateGasLimit(ctx, msg, types.TipSetKey{})
+		gasLimit, _, margin, err := gasEstimateGasLimitWithMargin(ctx, m.Stmgr, m.Chain, m.Mpool, m.GetGasLimitMargins, msg)
 		if err != nil {
-			return nil, xerrors.Errorf("estimating gas used: %w", err)
+			return nil, err
 		}
-		msg.GasLimit = int64(float64(gasLimit) * m.Mpool.GetConfig().GasLimitOverestimation)
+		msg.GasLimit = int64(float64(gasLimit) * margin)
 	}
 
 	if msg.GasPremium == types.EmptyInt || types.BigCmp(msg.GasPremium, types.NewInt(0)) == 0 {
@@ -349,5 +357,83 @@ func (m *GasModule) GasEstimateMessageGas(ctx context.Context, msg *types.Messag
 
 	messagepool.CapGasFee(m.GetMaxFee, msg, spec)
 
+	if m.GetMinPremium != nil {
+		floor, err := m.GetMinPremium()
+		if err != nil {
+			return nil, xerrors.Errorf("getting gas premium floor: %w", err)
+		}
+		if err := applyGasPremiumFloor(msg, floor); err != nil {
+			return nil, err
+		}
+	}
+
 	return msg, nil
 }
+
+// gasEstimateGasLimitWithMargin estimates the gas used by msg at the head, and
+// returns it with the family of the destination actor and the margin the gas
+// limit is the estimate times
+func gasEstimateGasLimitWithMargin(ctx context.Context, smgr *stmgr.StateManager, cstore *store.ChainStore, mpool *messagepool.MessagePool,
+	getNodeMargins dtypes.GasLimitMarginsFunc, msg *types.Message) (int64, string, float64, error) {
+	ts := cstore.GetHeaviestTipSet()
+	gasLimit, err := gasEstimateGasLimit(ctx, cstore, smgr, mpool, msg, ts)
+	if err != nil {
+		return 0, "", 0, xerrors.Errorf("estimating gas used: %w", err)
+	}
+
+	family, err := actorFamily(ctx, smgr, ts, msg.To)
+	if err != nil {
+		return 0, "", 0, err
+	}
+
+	var nodeMargins map[string]float64
+	if getNodeMargins != nil {
+		if nodeMargins, err = getNodeMargins(); err != nil {
+			return 0, "", 0, xerrors.Errorf("getting gas limit margins: %w", err)
+		}
+		if err := messagepool.ValidateGasLimitMargins(nodeMargins); err != nil {
+			return 0, "", 0, xerrors.Errorf("node config Fees.GasLimitMargins: %w", err)
+		}
+	}
+
+	cfg := mpool.GetConfig()
+	return gasLimit, family, messagepool.GasLimitMargin(cfg.GasLimitOverestimation, cfg.GasLimitMargins, nodeMargins, family, msg.Method), nil
+}
+
+// actorFamily returns the family of the actor at the address in the tipset,
+// as in the gas limit margins, empty if it isn't a builtin actor
+func actorFamily(ctx context.Context, smgr *stmgr.StateManager, ts *types.TipSet, to address.Address) (string, error) {
+	act, err := smgr.LoadActor(ctx, to, ts)
+	switch {
+	case err == nil:
+		if builtin.IsBuiltinActor(act.Code) {
+			return path.Base(builtin.ActorNameByCode(act.Code)), nil
+		}
+		return "", nil
+	case xerrors.Is(err, types.ErrActorNotFound):
+		// the send creates an account for a key address
+		if to.Protocol() == address.SECP256K1 || to.Protocol() == address.BLS {
+			return "account", nil
+		}
+		return "", nil
+	default:
+		return "", xerrors.Errorf("loading destination actor: %w", err)
+	}
+}
+
+// applyGasPremiumFloor raises the gas premium of msg to the floor. The fee cap
+// is left alone, as it is either given or limited by the max fee, so a fee cap
+// below the floor is an error.
+func applyGasPremiumFloor(msg *types.Message, floor abi.TokenAmount) error {
+	if floor.Sign() <= 0 || !msg.GasPremium.LessThan(floor) {
+		return nil
+	}
+
+	if msg.GasFeeCap.LessThan(floor) {
+		return xerrors.Errorf("gas premium floor %s is above the gas fee cap %s (limited by the max fee or given explicitly)", types.FIL(floor), types.FIL(msg.GasFeeCap))
+	}
+
+	log.Infow("raising gas premium to the configured floor", "from", msg.From, "premium", msg.GasPremium, "floor", floor)
+	msg.GasPremium = floor
+	return nil
+}
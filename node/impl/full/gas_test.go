@@ -38,3 +38,20 @@ func TestMedian(t *testing.T) {
 		{big.NewInt(30), build.BlockGasTarget / 2},
 	}, 2))
 }
+
+func TestGasPremiumFloor(t *testing.T) {
+	msg := &types.Message{GasPremium: big.NewInt(100), GasFeeCap: big.NewInt(1000)}
+
+	require.NoError(t, applyGasPremiumFloor(msg, big.Zero()))
+	require.Equal(t, big.NewInt(100), msg.GasPremium)
+
+	require.NoError(t, applyGasPremiumFloor(msg, big.NewInt(500)))
+	require.Equal(t, big.NewInt(500), msg.GasPremium)
+	require.Equal(t, big.NewInt(1000), msg.GasFeeCap)
+
+	// premiums above the floor are kept
+	require.NoError(t, applyGasPremiumFloor(msg, big.NewInt(200)))
+	require.Equal(t, big.NewInt(500), msg.GasPremium)
+
+	require.Error(t, applyGasPremiumFloor(msg, big.NewInt(2000)))
+}
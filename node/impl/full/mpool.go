@@ -3,16 +3,27 @@ package full
 import (
 	"context"
 	"encoding/json"
+	stdbig "math/big"
+	"strings"
+	"time"
 
 	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/ipfs/go-cid"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
 	"go.uber.org/fx"
 	"golang.org/x/xerrors"
 
 	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/api/errcode"
+	"github.com/filecoin-project/lotus/chain/denylist"
 	"github.com/filecoin-project/lotus/chain/messagepool"
 	"github.com/filecoin-project/lotus/chain/messagesigner"
+	"github.com/filecoin-project/lotus/chain/msgprop"
 	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/metrics"
+	"github.com/filecoin-project/lotus/node/config"
 	"github.com/filecoin-project/lotus/node/modules/dtypes"
 )
 
@@ -42,8 +53,14 @@ type MpoolAPI struct {
 	GasAPI
 
 	MessageSigner *messagesigner.MessageSigner
+	Denylist      *denylist.Denylist `optional:"true"`
 
 	PushLocks *dtypes.MpoolLocker
+
+	ReferenceThreshold dtypes.SendReferenceThresholdFunc `optional:"true"`
+	GetGasLimitMargins dtypes.GasLimitMarginsFunc        `optional:"true"`
+	Propagation        *msgprop.Tracker                  `optional:"true"`
+	History            *config.History                   `optional:"true"`
 }
 
 func (a *MpoolAPI) MpoolGetConfig(context.Context) (*types.MpoolConfig, error) {
@@ -51,7 +68,13 @@ func (a *MpoolAPI) MpoolGetConfig(context.Context) (*types.MpoolConfig, error) {
 }
 
 func (a *MpoolAPI) MpoolSetConfig(ctx context.Context, cfg *types.MpoolConfig) error {
-	return a.Mpool.SetConfig(cfg)
+	if err := a.Mpool.SetConfig(cfg); err != nil {
+		return err
+	}
+	if err := a.History.RecordChange(ctx, "MpoolSetConfig", "mpool", a.Mpool.GetConfig()); err != nil {
+		log.Errorf("recording config snapshot: %s", err)
+	}
+	return nil
 }
 
 func (a *MpoolAPI) MpoolSelect(ctx context.Context, tsk types.TipSetKey, ticketQuality float64) ([]*types.SignedMessage, error) {
@@ -126,6 +149,219 @@ func (a *MpoolAPI) MpoolClear(ctx context.Context, local bool) error {
 	return nil
 }
 
+func (a *MpoolAPI) MpoolGetReplacement(ctx context.Context, c cid.Cid) (*api.MsgReplacement, error) {
+	return a.Mpool.GetReplacement(c)
+}
+
+func (a *MpoolAPI) MpoolGetMemo(ctx context.Context, c cid.Cid) (*api.MessageMemo, error) {
+	return a.Mpool.GetMemo(c)
+}
+
+func (a *MpoolAPI) MpoolSetMemo(ctx context.Context, m api.MessageMemo) error {
+	if !m.Message.Defined() {
+		return xerrors.Errorf("memo has no message")
+	}
+	if m.Memo != "" && (m.From == address.Undef || m.To == address.Undef) {
+		cm, err := a.Chain.GetCMessage(m.Message)
+		if err != nil {
+			return xerrors.Errorf("loading message %s: %w", m.Message, err)
+		}
+		m.From, m.To = cm.VMMessage().From, cm.VMMessage().To
+	}
+	return a.Mpool.SetMemo(m)
+}
+
+func (a *MpoolAPI) MpoolListMemos(ctx context.Context, addr address.Address) ([]api.MessageMemo, error) {
+	return a.Mpool.ListMemos(addr)
+}
+
+func (a *MpoolAPI) MpoolFeeHistory(ctx context.Context) ([]api.FeeLevelStats, error) {
+	records, head, err := a.resolveFeeRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return messagepool.FeeLevelStats(records, head), nil
+}
+
+func (a *MpoolAPI) MpoolFeeLevel(ctx context.Context, c cid.Cid) (*api.FeeLevelStats, error) {
+	r, err := a.Mpool.GetFeeRecord(c)
+	if err != nil || r == nil {
+		return nil, err
+	}
+
+	records, head, err := a.resolveFeeRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s := messagepool.FeeLevelStats(records, head)[messagepool.FeeLevel(r.Ratio)]
+	if s.Sends < messagepool.FeeHistoryMinSends {
+		return nil, nil
+	}
+	return &s, nil
+}
+
+// resolveFeeRecords searches the chain for the recorded messages which
+// haven't landed yet, from the height it was last searched up to
+func (a *MpoolAPI) resolveFeeRecords(ctx context.Context) ([]messagepool.FeeRecord, abi.ChainEpoch, error) {
+	records, err := a.Mpool.FeeRecords()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	head := a.Chain.GetHeaviestTipSet()
+	for i, r := range records {
+		if r.Landed != 0 || head.Height()-r.Pushed <= messagepool.FeeHistoryQuickEpochs {
+			continue
+		}
+
+		from := r.Pushed
+		if r.Checked > from {
+			from = r.Checked
+		}
+		ts, _, found, err := a.Stmgr.SearchForMessage(ctx, head, r.Message, head.Height()-from, true)
+		if err != nil {
+			return nil, 0, xerrors.Errorf("searching message %s: %w", r.Message, err)
+		}
+		r.Checked = head.Height()
+		if ts != nil {
+			// the receipt is in the tipset after the one the message landed in
+			pts, err := a.Chain.LoadTipSet(ts.Parents())
+			if err != nil {
+				return nil, 0, xerrors.Errorf("loading tipset: %w", err)
+			}
+			r.Landed = pts.Height()
+			r.Replaced = found != r.Message
+		}
+		if err := a.Mpool.PutFeeRecord(r); err != nil {
+			return nil, 0, err
+		}
+		records[i] = r
+	}
+	return records, head.Height(), nil
+}
+
+func (a *MpoolAPI) MpoolLanes(ctx context.Context) ([]api.MpoolLane, error) {
+	return a.Mpool.Lanes(ctx), nil
+}
+
+func (a *MpoolAPI) MpoolGasAccuracy(ctx context.Context) ([]api.GasAccuracyStats, error) {
+	records, err := a.resolveGasRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodeMargins map[string]float64
+	if a.GetGasLimitMargins != nil {
+		if nodeMargins, err = a.GetGasLimitMargins(); err != nil {
+			return nil, xerrors.Errorf("getting gas limit margins: %w", err)
+		}
+	}
+	cfg := a.Mpool.GetConfig()
+	return messagepool.GasAccuracyStats(records, func(family string, method abi.MethodNum) float64 {
+		return messagepool.GasLimitMargin(cfg.GasLimitOverestimation, cfg.GasLimitMargins, nodeMargins, family, method)
+	}), nil
+}
+
+// resolveGasRecords searches the chain for the receipts of the recorded
+// messages which haven't executed yet, from the height it was last searched
+// up to, and sets the family of the executed ones pushed with their gas limit
+func (a *MpoolAPI) resolveGasRecords(ctx context.Context) ([]messagepool.GasRecord, error) {
+	records, err := a.Mpool.GasRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	head := a.Chain.GetHeaviestTipSet()
+	for i, r := range records {
+		if r.Executed || r.Replaced {
+			continue
+		}
+
+		from := r.Pushed
+		if r.Checked > from {
+			from = r.Checked
+		}
+		ts, rct, found, err := a.Stmgr.SearchForMessage(ctx, head, r.Message, head.Height()-from, true)
+		if err != nil {
+			return nil, xerrors.Errorf("searching message %s: %w", r.Message, err)
+		}
+		r.Checked = head.Height()
+		if ts != nil {
+			if found != r.Message {
+				r.Replaced = true
+			} else {
+				r.Executed = true
+				r.GasUsed = rct.GasUsed
+				r.ExitCode = rct.ExitCode
+				if r.Estimate == 0 {
+					// the destination of a message pushed with its gas limit
+					// isn't looked up on push
+					msg, err := a.Chain.GetMessage(r.Message)
+					if err != nil {
+						return nil, xerrors.Errorf("loading message %s: %w", r.Message, err)
+					}
+					if r.Family, err = actorFamily(ctx, a.Stmgr, ts, msg.To); err != nil {
+						return nil, xerrors.Errorf("message %s: %w", r.Message, err)
+					}
+				}
+			}
+		}
+		if err := a.Mpool.PutGasRecord(r); err != nil {
+			return nil, err
+		}
+		records[i] = r
+	}
+	return records, nil
+}
+
+func (a *MpoolAPI) MpoolGetIdempotencyKey(ctx context.Context, key string) (*api.IdempotencyRecord, error) {
+	return a.Mpool.GetIdempotencyKey(key)
+}
+
+func (a *MpoolAPI) MpoolPropagation(ctx context.Context, c cid.Cid) (*api.MsgPropagation, error) {
+	pending, ts := a.Mpool.Pending(ctx)
+
+	var smsg *types.SignedMessage
+	for _, m := range pending {
+		if m.Cid() == c {
+			smsg = m
+			break
+		}
+	}
+	inMpool := smsg != nil
+	if !inMpool {
+		var err error
+		if smsg, err = a.Chain.GetSignedMessage(c); err != nil {
+			return nil, xerrors.Errorf("loading message: %w", err)
+		}
+	}
+
+	out := api.MsgPropagation{Message: c}
+	if a.Propagation != nil {
+		var err error
+		if out, err = a.Propagation.Propagation(smsg); err != nil {
+			return nil, err
+		}
+	}
+
+	out.InMpool = inMpool
+	out.PendingMessages = len(pending)
+	out.GasPremium = smsg.Message.GasPremium
+	out.GasFeeCap = smsg.Message.GasFeeCap
+	out.BaseFee = ts.Blocks()[0].ParentBaseFee
+
+	if inMpool {
+		out.PremiumRank = 1
+		for _, m := range pending {
+			if m.Message.GasPremium.GreaterThan(smsg.Message.GasPremium) {
+				out.PremiumRank++
+			}
+		}
+	}
+
+	return &out, nil
+}
+
 func (m *MpoolModule) MpoolPush(ctx context.Context, smsg *types.SignedMessage) (cid.Cid, error) {
 	return m.Mpool.Push(ctx, smsg)
 }
@@ -135,6 +371,12 @@ func (a *MpoolAPI) MpoolPushUntrusted(ctx context.Context, smsg *types.SignedMes
 }
 
 func (a *MpoolAPI) MpoolPushMessage(ctx context.Context, msg *types.Message, spec *api.MessageSendSpec) (*types.SignedMessage, error) {
+	outcome := "error"
+	defer func() {
+		ctx, _ := tag.New(ctx, tag.Upsert(metrics.PushOutcome, outcome))
+		stats.Record(ctx, metrics.MpoolPushMessage.M(1))
+	}()
+
 	cp := *msg
 	msg = &cp
 	inMsg := *msg
@@ -154,12 +396,79 @@ func (a *MpoolAPI) MpoolPushMessage(ctx context.Context, msg *types.Message, spe
 		return nil, xerrors.Errorf("MpoolPushMessage expects message nonce to be 0, was %d", msg.Nonce)
 	}
 
+	// the push lock of the sender is held, so a retry waits for the first
+	// push to be recorded
+	var idempotencyKey string
+	if spec != nil {
+		idempotencyKey = spec.IdempotencyKey
+	}
+	if idempotencyKey != "" {
+		r, err := a.Mpool.GetIdempotencyKey(idempotencyKey)
+		if err != nil {
+			return nil, xerrors.Errorf("mpool push: %w", err)
+		}
+		if r != nil {
+			if !r.Matches(fromA, msg) {
+				outcome = "idempotency_conflict"
+				return nil, api.WrapError(errcode.IdempotencyKeyConflict,
+					xerrors.Errorf("mpool push: %w: key %q was used for message %s", api.ErrIdempotencyKeyConflict, idempotencyKey, r.Message))
+			}
+
+			smsg, err := a.Chain.GetSignedMessage(r.Message)
+			if err != nil {
+				return nil, xerrors.Errorf("mpool push: loading message %s pushed with idempotency key %q: %w", r.Message, idempotencyKey, err)
+			}
+			outcome = "idempotent_replay"
+			return smsg, nil
+		}
+	}
+
+	if err := a.WatchOnly.Check(fromA); err != nil {
+		outcome = "watch_only"
+		return nil, xerrors.Errorf("mpool push: %w", err)
+	}
+
+	if err := a.Denylist.Check(ctx, a.Stmgr, a.Chain.GetHeaviestTipSet(), msg); err != nil {
+		outcome = "denied"
+		return nil, xerrors.Errorf("mpool push: %w", err)
+	}
+
+	var reference string
+	if spec != nil {
+		reference = strings.TrimSpace(spec.Reference)
+	}
+	if a.ReferenceThreshold != nil && reference == "" {
+		threshold, err := a.ReferenceThreshold()
+		if err != nil {
+			return nil, xerrors.Errorf("getting send reference threshold: %w", err)
+		}
+		if threshold.Sign() > 0 && msg.Value.GreaterThan(threshold) {
+			outcome = "reference_required"
+			return nil, xerrors.Errorf("mpool push: %w (sending %s, above %s)", api.ErrSendReferenceRequired, types.FIL(msg.Value), types.FIL(threshold))
+		}
+	}
+
+	// the gas limit is estimated here rather than by GasEstimateMessageGas,
+	// for the gas records to compare the estimate with the gas used
+	gasRec := messagepool.GasRecord{Method: msg.Method}
+	if msg.GasLimit == 0 {
+		gasRec.Estimate, gasRec.Family, gasRec.Margin, err = gasEstimateGasLimitWithMargin(ctx, a.Stmgr, a.Chain, a.Mpool, a.GetGasLimitMargins, msg)
+		if err != nil {
+			outcome = "estimation_failed"
+			return nil, xerrors.Errorf("mpool push: %w", err)
+		}
+		msg.GasLimit = int64(float64(gasRec.Estimate) * gasRec.Margin)
+	}
+
+	estimated := time.Now()
 	msg, err = a.GasAPI.GasEstimateMessageGas(ctx, msg, spec, types.EmptyTSK)
 	if err != nil {
-		return nil, xerrors.Errorf("GasEstimateMessageGas error: %w", err)
+		outcome = "estimation_failed"
+		return nil, xerrors.Errorf("mpool push: estimating message gas: %w", err)
 	}
 
 	if msg.GasPremium.GreaterThan(msg.GasFeeCap) {
+		outcome = "estimation_failed"
 		inJson, _ := json.Marshal(inMsg)
 		outJson, _ := json.Marshal(msg)
 		return nil, xerrors.Errorf("After estimation, GasPremium is greater than GasFeeCap, inmsg: %s, outmsg: %s",
@@ -167,7 +476,8 @@ func (a *MpoolAPI) MpoolPushMessage(ctx context.Context, msg *types.Message, spe
 	}
 
 	if msg.From.Protocol() == address.ID {
-		log.Warnf("Push from ID address (%s), adjusting to %s", msg.From, fromA)
+		r := a.Mpool.Redactor()
+		log.Warnf("Push from ID address (%s), adjusting to %s", r.From(msg.From), r.From(fromA))
 		msg.From = fromA
 	}
 
@@ -176,17 +486,70 @@ func (a *MpoolAPI) MpoolPushMessage(ctx context.Context, msg *types.Message, spe
 		return nil, xerrors.Errorf("mpool push: getting origin balance: %w", err)
 	}
 
+	feeRatio := -1.0
+	if a.Mpool.FeeHistoryEnabled() {
+		feeRatio, err = a.premiumRatio(ctx, msg)
+		if err != nil {
+			log.Warnf("mpool push: estimating premium for the fee history: %s", err)
+		}
+	}
+
 	if b.LessThan(msg.Value) {
+		outcome = "insufficient_funds"
 		return nil, xerrors.Errorf("mpool push: not enough funds: %s < %s", b, msg.Value)
 	}
 
 	// Sign and push the message
-	return a.MessageSigner.SignMessage(ctx, msg, func(smsg *types.SignedMessage) error {
+	smsg, err := a.MessageSigner.SignMessage(ctx, msg, func(smsg *types.SignedMessage) error {
 		if _, err := a.MpoolModuleAPI.MpoolPush(ctx, smsg); err != nil {
 			return xerrors.Errorf("mpool push: failed to push message: %w", err)
 		}
+		stats.Record(ctx, metrics.MpoolPushMessageEstimateToPush.M(metrics.SinceInMilliseconds(estimated)))
+		if reference != "" {
+			a.Mpool.RecordSendReference(smsg, reference)
+		}
+		if idempotencyKey != "" {
+			a.Mpool.RecordIdempotencyKey(api.IdempotencyRecord{
+				Key:     idempotencyKey,
+				Message: smsg.Cid(),
+				From:    fromA,
+				To:      inMsg.To,
+				Value:   inMsg.Value,
+				Method:  inMsg.Method,
+				Params:  inMsg.Params,
+			})
+		}
+		if feeRatio >= 0 {
+			a.Mpool.RecordFeeLevel(smsg.Cid(), feeRatio, a.Chain.GetHeaviestTipSet().Height())
+		}
+		gasRec.Message = smsg.Cid()
+		gasRec.Limit = smsg.Message.GasLimit
+		gasRec.FeeCap = smsg.Message.GasFeeCap
+		gasRec.Pushed = a.Chain.GetHeaviestTipSet().Height()
+		a.Mpool.RecordGasLimit(gasRec)
 		return nil
 	})
+	if err != nil {
+		outcome = "push_failed"
+		return nil, err
+	}
+
+	outcome = "ok"
+	return smsg, nil
+}
+
+// premiumRatio returns the ratio of the premium of the message to the
+// premium estimate
+func (a *MpoolAPI) premiumRatio(ctx context.Context, msg *types.Message) (float64, error) {
+	est, err := a.GasEstimateGasPremium(ctx, 10, msg.From, msg.GasLimit, types.EmptyTSK)
+	if err != nil {
+		return -1, err
+	}
+	if est.Sign() <= 0 {
+		return -1, xerrors.Errorf("premium estimate is %s", est)
+	}
+	ratio, _ := new(stdbig.Float).Quo(new(stdbig.Float).SetInt(msg.GasPremium.Int), new(stdbig.Float).SetInt(est.Int)).Float64()
+	return ratio, nil
 }
 
 func (a *MpoolAPI) MpoolBatchPush(ctx context.Context, smsgs []*types.SignedMessage) ([]cid.Cid, error) {
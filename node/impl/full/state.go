@@ -18,7 +18,9 @@ import (
 	"github.com/filecoin-project/lotus/extern/sector-storage/ffiwrapper"
 
 	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/chain/actorcache"
 	"github.com/filecoin-project/lotus/chain/actors/builtin"
+	_init "github.com/filecoin-project/lotus/chain/actors/builtin/init"
 	"github.com/filecoin-project/lotus/chain/actors/builtin/market"
 	"github.com/filecoin-project/lotus/chain/actors/builtin/miner"
 	"github.com/filecoin-project/lotus/chain/actors/builtin/multisig"
@@ -66,8 +68,9 @@ var _ StateModuleAPI = *new(api.FullNode)
 type StateModule struct {
 	fx.In
 
-	StateManager *stmgr.StateManager
-	Chain        *store.ChainStore
+	StateManager  *stmgr.StateManager
+	Chain         *store.ChainStore
+	WatchedActors *actorcache.WatchedActors `optional:"true"`
 }
 
 var _ StateModuleAPI = (*StateModule)(nil)
@@ -86,6 +89,7 @@ type StateAPI struct {
 	StateManager  *stmgr.StateManager
 	Chain         *store.ChainStore
 	Beacon        beacon.Schedule
+	WatchedActors *actorcache.WatchedActors `optional:"true"`
 }
 
 func (a *StateAPI) StateNetworkName(ctx context.Context) (dtypes.NetworkName, error) {
@@ -131,6 +135,12 @@ func (m *StateModule) StateMinerInfo(ctx context.Context, actor address.Address,
 		return miner.MinerInfo{}, xerrors.Errorf("failed to load tipset: %w", err)
 	}
 
+	if m.WatchedActors != nil {
+		if info, ok := m.WatchedActors.MinerInfo(ctx, actor, ts); ok {
+			return info, nil
+		}
+	}
+
 	act, err := m.StateManager.LoadActor(ctx, actor, ts)
 	if err != nil {
 		return miner.MinerInfo{}, xerrors.Errorf("failed to load miner actor: %w", err)
@@ -431,9 +441,38 @@ func (m *StateModule) StateGetActor(ctx context.Context, actor address.Address,
 	if err != nil {
 		return nil, xerrors.Errorf("loading tipset %s: %w", tsk, err)
 	}
+
+	if m.WatchedActors != nil {
+		if act, ok := m.WatchedActors.Actor(ctx, actor, ts); ok {
+			return act, nil
+		}
+	}
+
 	return m.StateManager.LoadActor(ctx, actor, ts)
 }
 
+func (a *StateAPI) StateWatchActor(ctx context.Context, actor address.Address) error {
+	if a.WatchedActors == nil {
+		return xerrors.Errorf("watched actor cache not available")
+	}
+	a.WatchedActors.Watch(actor)
+	return nil
+}
+
+func (a *StateAPI) StateUnwatchActor(ctx context.Context, actor address.Address) error {
+	if a.WatchedActors == nil {
+		return xerrors.Errorf("watched actor cache not available")
+	}
+	return a.WatchedActors.Unwatch(actor)
+}
+
+func (a *StateAPI) StateWatchedActors(ctx context.Context) ([]api.WatchedActor, error) {
+	if a.WatchedActors == nil {
+		return nil, xerrors.Errorf("watched actor cache not available")
+	}
+	return a.WatchedActors.List(), nil
+}
+
 func (m *StateModule) StateLookupID(ctx context.Context, addr address.Address, tsk types.TipSetKey) (address.Address, error) {
 	ts, err := m.Chain.GetTipSetFromKey(tsk)
 	if err != nil {
@@ -452,6 +491,49 @@ func (m *StateModule) StateAccountKey(ctx context.Context, addr address.Address,
 	return m.StateManager.ResolveToKeyAddress(ctx, addr, ts)
 }
 
+func (a *StateAPI) StateLookupRobustAddress(ctx context.Context, addr address.Address, tsk types.TipSetKey) (address.Address, error) {
+	if addr.Protocol() != address.ID {
+		return addr, nil
+	}
+
+	ts, err := a.Chain.GetTipSetFromKey(tsk)
+	if err != nil {
+		return address.Undef, xerrors.Errorf("loading tipset %s: %w", tsk, err)
+	}
+
+	act, err := a.StateManager.LoadActor(ctx, _init.Address, ts)
+	if err != nil {
+		return address.Undef, xerrors.Errorf("loading init actor: %w", err)
+	}
+
+	ias, err := _init.Load(a.StateManager.ChainStore().ActorStore(ctx), act)
+	if err != nil {
+		return address.Undef, xerrors.Errorf("loading init actor state: %w", err)
+	}
+
+	id, err := address.IDFromAddress(addr)
+	if err != nil {
+		return address.Undef, err
+	}
+
+	robust := address.Undef
+	errFound := xerrors.New("found")
+	err = ias.ForEachActor(func(aid abi.ActorID, ra address.Address) error {
+		if uint64(aid) == id {
+			robust = ra
+			return errFound
+		}
+		return nil
+	})
+	if err != nil && err != errFound {
+		return address.Undef, xerrors.Errorf("scanning init actor address map: %w", err)
+	}
+	if robust == address.Undef {
+		return address.Undef, xerrors.Errorf("no robust address for %s", addr)
+	}
+	return robust, nil
+}
+
 func (a *StateAPI) StateReadState(ctx context.Context, actor address.Address, tsk types.TipSetKey) (*api.ActorState, error) {
 	ts, err := a.Chain.GetTipSetFromKey(tsk)
 	if err != nil {
@@ -23,6 +23,10 @@ type WalletAPI struct {
 	StateManagerAPI stmgr.StateManagerAPI
 	Default         wallet.Default
 	api.Wallet
+
+	LocalWallet *wallet.LocalWallet   `optional:"true"`
+	WatchOnly   *wallet.WatchOnly     `optional:"true"`
+	Policies    *wallet.UsagePolicies `optional:"true"`
 }
 
 func (a *WalletAPI) WalletBalance(ctx context.Context, addr address.Address) (types.BigInt, error) {
@@ -40,6 +44,9 @@ func (a *WalletAPI) WalletSign(ctx context.Context, k address.Address, msg []byt
 	if err != nil {
 		return nil, xerrors.Errorf("failed to resolve ID address: %w", keyAddr)
 	}
+	if err := a.WatchOnly.Check(keyAddr); err != nil {
+		return nil, err
+	}
 	return a.Wallet.WalletSign(ctx, keyAddr, msg, api.MsgMeta{
 		Type: api.MTUnknown,
 	})
@@ -50,6 +57,9 @@ func (a *WalletAPI) WalletSignMessage(ctx context.Context, k address.Address, ms
 	if err != nil {
 		return nil, xerrors.Errorf("failed to resolve ID address: %w", keyAddr)
 	}
+	if err := a.WatchOnly.Check(keyAddr); err != nil {
+		return nil, err
+	}
 
 	mb, err := msg.ToStorageBlock()
 	if err != nil {
@@ -85,3 +95,91 @@ func (a *WalletAPI) WalletSetDefault(ctx context.Context, addr address.Address)
 func (a *WalletAPI) WalletValidateAddress(ctx context.Context, str string) (address.Address, error) {
 	return address.NewFromString(str)
 }
+
+func (a *WalletAPI) WalletUnlock(ctx context.Context, passphrase string) error {
+	if a.LocalWallet == nil {
+		return xerrors.Errorf("local wallet disabled")
+	}
+	return a.LocalWallet.Unlock([]byte(passphrase))
+}
+
+func (a *WalletAPI) WalletLock(ctx context.Context) error {
+	if a.LocalWallet == nil {
+		return xerrors.Errorf("local wallet disabled")
+	}
+	return a.LocalWallet.Lock()
+}
+
+func (a *WalletAPI) WalletAddWatch(ctx context.Context, addr address.Address, label string) error {
+	if a.WatchOnly == nil {
+		return xerrors.Errorf("watch-only addresses aren't supported by this node")
+	}
+	has, err := a.Wallet.WalletHas(ctx, addr)
+	if err != nil {
+		return err
+	}
+	if has {
+		return xerrors.Errorf("the wallet has the key of %s, it can't be watch-only", addr)
+	}
+	return a.WatchOnly.Add(addr, label)
+}
+
+func (a *WalletAPI) WalletRemoveWatch(ctx context.Context, addr address.Address) error {
+	if a.WatchOnly == nil {
+		return xerrors.Errorf("watch-only addresses aren't supported by this node")
+	}
+	return a.WatchOnly.Remove(addr)
+}
+
+func (a *WalletAPI) WalletListWatch(ctx context.Context) ([]api.WatchOnlyAddress, error) {
+	if a.WatchOnly == nil {
+		return nil, nil
+	}
+	return a.WatchOnly.List()
+}
+
+func (a *WalletAPI) WalletPolicySet(ctx context.Context, pol api.WalletUsagePolicy) error {
+	if a.Policies == nil {
+		return xerrors.Errorf("wallet usage policies aren't supported by this node")
+	}
+	keyAddr, err := a.StateManagerAPI.ResolveToKeyAddress(ctx, pol.Address, nil)
+	if err != nil {
+		return xerrors.Errorf("resolving %s to a key address: %w", pol.Address, err)
+	}
+	pol.Address = keyAddr
+	return a.Policies.Set(pol)
+}
+
+func (a *WalletAPI) WalletPolicyGet(ctx context.Context, addr address.Address) (*api.WalletUsagePolicy, error) {
+	if a.Policies == nil {
+		return nil, nil
+	}
+	keyAddr, err := a.StateManagerAPI.ResolveToKeyAddress(ctx, addr, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("resolving %s to a key address: %w", addr, err)
+	}
+	return a.Policies.Get(keyAddr)
+}
+
+func (a *WalletAPI) WalletPolicyList(ctx context.Context) ([]api.WalletUsagePolicy, error) {
+	if a.Policies == nil {
+		return nil, nil
+	}
+	return a.Policies.List()
+}
+
+func (a *WalletAPI) WalletPolicyRemove(ctx context.Context, addr address.Address) error {
+	if a.Policies == nil {
+		return xerrors.Errorf("wallet usage policies aren't supported by this node")
+	}
+	keyAddr, err := a.StateManagerAPI.ResolveToKeyAddress(ctx, addr, nil)
+	if err != nil {
+		return xerrors.Errorf("resolving %s to a key address: %w", addr, err)
+	}
+	return a.Policies.Remove(keyAddr)
+}
+
+func (a *WalletAPI) WalletSignMessageOverridePolicy(ctx context.Context, k address.Address, msg *types.Message) (*types.SignedMessage, error) {
+	log.Warnw("signing message overriding the wallet usage policy", "from", k, "to", msg.To, "method", msg.Method)
+	return a.WalletSignMessage(wallet.WithPolicyOverride(ctx), k, msg)
+}
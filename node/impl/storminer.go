@@ -37,6 +37,7 @@ import (
 	"github.com/filecoin-project/lotus/api"
 	apitypes "github.com/filecoin-project/lotus/api/types"
 	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/markets/sealingstatus"
 	"github.com/filecoin-project/lotus/markets/storageadapter"
 	"github.com/filecoin-project/lotus/miner"
 	"github.com/filecoin-project/lotus/node/impl/common"
@@ -65,6 +66,7 @@ type StorageMinerAPI struct {
 	Host          host.Host
 	AddrSel       *storage.AddressSelector
 	DealPublisher *storageadapter.DealPublisher
+	SealingStatus *sealingstatus.Provider
 
 	Epp gen.WinningPoStProver
 	DS  dtypes.MetadataDS
@@ -87,6 +89,7 @@ type StorageMinerAPI struct {
 	GetSealingConfigFunc                        dtypes.GetSealingConfigFunc
 	GetExpectedSealDurationFunc                 dtypes.GetExpectedSealDurationFunc
 	SetExpectedSealDurationFunc                 dtypes.SetExpectedSealDurationFunc
+	SetWdPoStParallelBatchesFunc                dtypes.SetWdPoStParallelBatchesFunc
 }
 
 func (sm *StorageMinerAPI) ServeRemote(w http.ResponseWriter, r *http.Request) {
@@ -119,6 +122,18 @@ func (sm *StorageMinerAPI) MiningBase(ctx context.Context) (*types.TipSet, error
 	return mb.TipSet, nil
 }
 
+func (sm *StorageMinerAPI) MiningAdvanceEpochs(ctx context.Context, epochs abi.ChainEpoch, nulls bool) (abi.ChainEpoch, error) {
+	return sm.BlockMiner.AdvanceEpochs(ctx, epochs, nulls)
+}
+
+func (sm *StorageMinerAPI) MiningSimulate(ctx context.Context) (*api.BlockSimulation, error) {
+	return sm.BlockMiner.Simulate(ctx), nil
+}
+
+func (sm *StorageMinerAPI) MiningLastSimulation(ctx context.Context) (*api.BlockSimulation, error) {
+	return sm.BlockMiner.LastSimulation(), nil
+}
+
 func (sm *StorageMinerAPI) ActorSectorSize(ctx context.Context, addr address.Address) (abi.SectorSize, error) {
 	mi, err := sm.Full.StateMinerInfo(ctx, addr, types.EmptyTSK)
 	if err != nil {
@@ -335,7 +350,13 @@ func (sm *StorageMinerAPI) SectorSetSealDelay(ctx context.Context, delay time.Du
 
 	cfg.WaitDealsDelay = delay
 
-	return sm.SetSealingConfigFunc(cfg)
+	if err := sm.SetSealingConfigFunc(cfg); err != nil {
+		return err
+	}
+	if err := sm.History.RecordChange(ctx, "SectorSetSealDelay", "sealing", cfg); err != nil {
+		log.Errorf("recording config snapshot: %s", err)
+	}
+	return nil
 }
 
 func (sm *StorageMinerAPI) SectorGetSealDelay(ctx context.Context) (time.Duration, error) {
@@ -480,6 +501,10 @@ func (sm *StorageMinerAPI) MarketListIncompleteDeals(ctx context.Context) ([]sto
 	return sm.StorageProvider.ListLocalDeals()
 }
 
+func (sm *StorageMinerAPI) MarketDealSealingStatus(ctx context.Context, proposal cid.Cid) (*api.DealSealingStatus, error) {
+	return sm.SealingStatus.Status(ctx, proposal)
+}
+
 func (sm *StorageMinerAPI) MarketSetAsk(ctx context.Context, price types.BigInt, verifiedPrice types.BigInt, duration abi.ChainEpoch, minPieceSize abi.PaddedPieceSize, maxPieceSize abi.PaddedPieceSize) error {
 	options := []storagemarket.StorageAskOption{
 		storagemarket.MinPieceSize(minPieceSize),
@@ -647,7 +672,18 @@ func (sm *StorageMinerAPI) StorageAddLocal(ctx context.Context, path string) err
 		return xerrors.Errorf("no storage manager")
 	}
 
-	return sm.StorageMgr.AddLocalStorage(ctx, path)
+	if err := sm.StorageMgr.AddLocalStorage(ctx, path); err != nil {
+		return err
+	}
+
+	paths, err := sm.StorageMgr.StorageLocal(ctx)
+	if err == nil {
+		err = sm.History.RecordChange(ctx, "StorageAddLocal", "storage", paths)
+	}
+	if err != nil {
+		log.Errorf("recording config snapshot: %s", err)
+	}
+	return nil
 }
 
 func (sm *StorageMinerAPI) PiecesListPieces(ctx context.Context) ([]cid.Cid, error) {
@@ -720,4 +756,26 @@ func (sm *StorageMinerAPI) ComputeProof(ctx context.Context, ssi []builtin.Secto
 	return sm.Epp.ComputeProof(ctx, ssi, rand)
 }
 
+func (sm *StorageMinerAPI) WdPoStStatus(ctx context.Context) (*api.WdPoStStatus, error) {
+	return sm.Miner.WdPoStStatus(), nil
+}
+
+func (sm *StorageMinerAPI) WdPoStProverStatus(ctx context.Context) (api.WdPoStProverStatus, error) {
+	return sm.Miner.WdPoStProverStatus(), nil
+}
+
+func (sm *StorageMinerAPI) WdPoStSetParallelBatches(ctx context.Context, n int) error {
+	if n < 1 {
+		return xerrors.Errorf("parallel batches must be at least 1, got %d", n)
+	}
+	if err := sm.SetWdPoStParallelBatchesFunc(n); err != nil {
+		return xerrors.Errorf("saving config: %w", err)
+	}
+	return sm.Miner.SetWdPoStParallelBatches(n)
+}
+
+func (sm *StorageMinerAPI) Methods(ctx context.Context) (map[string]api.MethodStability, error) {
+	return api.GetMethodStability(new(api.StorageMinerStruct)), nil
+}
+
 var _ api.StorageMiner = &StorageMinerAPI{}
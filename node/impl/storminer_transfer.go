@@ -0,0 +1,279 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/crypto"
+	sto "github.com/filecoin-project/specs-storage/storage"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/chain/types"
+	sectorstorage "github.com/filecoin-project/lotus/extern/sector-storage"
+	"github.com/filecoin-project/lotus/extern/sector-storage/ffiwrapper"
+	sealing "github.com/filecoin-project/lotus/extern/storage-sealing"
+)
+
+func (sm *StorageMinerAPI) sectorRef(id abi.SectorNumber, spt abi.RegisteredSealProof) (sto.SectorRef, error) {
+	mid, err := address.IDFromAddress(sm.Miner.Address())
+	if err != nil {
+		return sto.SectorRef{}, err
+	}
+	return sto.SectorRef{
+		ID:        abi.SectorID{Miner: abi.ActorID(mid), Number: id},
+		ProofType: spt,
+	}, nil
+}
+
+func (sm *StorageMinerAPI) SectorExport(ctx context.Context, id abi.SectorNumber, dir string) (api.SectorManifest, error) {
+	if !filepath.IsAbs(dir) {
+		return api.SectorManifest{}, xerrors.Errorf("export directory %q must be absolute", dir)
+	}
+
+	info, err := sm.Miner.GetSectorInfo(id)
+	if err != nil {
+		return api.SectorManifest{}, err
+	}
+	if info.State != sealing.Proving {
+		return api.SectorManifest{}, xerrors.Errorf("sector %d is %s, only proving sectors can be exported", id, info.State)
+	}
+	ref, err := sm.sectorRef(id, info.SectorType)
+	if err != nil {
+		return api.SectorManifest{}, err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return api.SectorManifest{}, err
+	}
+	mpath := filepath.Join(dir, api.SectorManifestName)
+	if _, err := os.Stat(mpath); err == nil {
+		return api.SectorManifest{}, xerrors.Errorf("%s already holds an exported sector", dir)
+	}
+
+	if err := sm.StorageMgr.ExportSector(ctx, ref, filepath.Join(dir, api.SectorManifestSealed), filepath.Join(dir, api.SectorManifestCache)); err != nil {
+		return api.SectorManifest{}, err
+	}
+
+	mf := api.SectorManifest{
+		Miner:        sm.Miner.Address(),
+		SectorNumber: id,
+		SectorType:   info.SectorType,
+		Ticket: api.SealTicket{
+			Value: info.TicketValue,
+			Epoch: info.TicketEpoch,
+		},
+		Seed: api.SealSeed{
+			Value: info.SeedValue,
+			Epoch: info.SeedEpoch,
+		},
+		CommD:            info.CommD,
+		CommR:            info.CommR,
+		PreCommitMessage: info.PreCommitMessage,
+		CommitMessage:    info.CommitMessage,
+	}
+	for _, p := range info.Pieces {
+		mp := api.SectorManifestPiece{Piece: p.Piece}
+		if p.DealInfo != nil {
+			mp.Deal = &api.SectorManifestDeal{
+				PublishCid:   p.DealInfo.PublishCid,
+				DealID:       p.DealInfo.DealID,
+				DealProposal: p.DealInfo.DealProposal,
+				StartEpoch:   p.DealInfo.DealSchedule.StartEpoch,
+				EndEpoch:     p.DealInfo.DealSchedule.EndEpoch,
+				KeepUnsealed: p.DealInfo.KeepUnsealed,
+			}
+		}
+		mf.Pieces = append(mf.Pieces, mp)
+	}
+
+	b, err := json.MarshalIndent(mf, "", "  ")
+	if err != nil {
+		return api.SectorManifest{}, err
+	}
+	if err := ioutil.WriteFile(mpath, b, 0644); err != nil {
+		return api.SectorManifest{}, xerrors.Errorf("writing manifest: %w", err)
+	}
+	return mf, nil
+}
+
+func (sm *StorageMinerAPI) SectorImport(ctx context.Context, manifest string, allowUnproven bool) error {
+	b, err := ioutil.ReadFile(manifest)
+	if err != nil {
+		return xerrors.Errorf("reading manifest: %w", err)
+	}
+	var mf api.SectorManifest
+	if err := json.Unmarshal(b, &mf); err != nil {
+		return xerrors.Errorf("decoding manifest: %w", err)
+	}
+
+	// check before copying anything, the sealing store checks again when
+	// importing
+	has, err := sm.Miner.HasSector(mf.SectorNumber)
+	if err != nil {
+		return xerrors.Errorf("checking the sealing store: %w", err)
+	}
+	if has {
+		return xerrors.Errorf("sector %d is already in the sealing store", mf.SectorNumber)
+	}
+
+	dir := filepath.Dir(manifest)
+	sealed, cache := filepath.Join(dir, api.SectorManifestSealed), filepath.Join(dir, api.SectorManifestCache)
+	ref, err := sm.sectorRef(mf.SectorNumber, mf.SectorType)
+	if err != nil {
+		return err
+	}
+
+	if err := sm.verifySectorImport(ctx, &mf, ref, sealed, cache, allowUnproven); err != nil {
+		return xerrors.Errorf("verifying sector %d: %w", mf.SectorNumber, err)
+	}
+
+	if err := sm.StorageMgr.ImportSector(ctx, ref, sealed, cache); err != nil {
+		return xerrors.Errorf("importing sector files: %w", err)
+	}
+
+	si := sealing.SectorInfo{
+		SectorNumber:     mf.SectorNumber,
+		SectorType:       mf.SectorType,
+		CreationTime:     time.Now().Unix(),
+		TicketValue:      mf.Ticket.Value,
+		TicketEpoch:      mf.Ticket.Epoch,
+		CommD:            mf.CommD,
+		CommR:            mf.CommR,
+		PreCommitDeposit: big.Zero(),
+		PreCommitMessage: mf.PreCommitMessage,
+		SeedValue:        mf.Seed.Value,
+		SeedEpoch:        mf.Seed.Epoch,
+		CommitMessage:    mf.CommitMessage,
+	}
+	for _, p := range mf.Pieces {
+		sp := sealing.Piece{Piece: p.Piece}
+		if p.Deal != nil {
+			sp.DealInfo = &sealing.DealInfo{
+				PublishCid:   p.Deal.PublishCid,
+				DealID:       p.Deal.DealID,
+				DealProposal: p.Deal.DealProposal,
+				DealSchedule: sealing.DealSchedule{
+					StartEpoch: p.Deal.StartEpoch,
+					EndEpoch:   p.Deal.EndEpoch,
+				},
+				KeepUnsealed: p.Deal.KeepUnsealed,
+			}
+		}
+		si.Pieces = append(si.Pieces, sp)
+	}
+
+	if err := sm.Miner.ImportSector(ctx, si); err != nil {
+		// don't leave the files of a sector the miner doesn't know about
+		if rerr := sm.StorageMgr.Remove(ctx, ref); rerr != nil {
+			log.Errorw("removing the files of a sector which failed to be imported", "sector", mf.SectorNumber, "error", rerr)
+		}
+		return xerrors.Errorf("importing sector %d: %w", mf.SectorNumber, err)
+	}
+	return nil
+}
+
+func mismatch(field string, manifest, expected interface{}) error {
+	return xerrors.Errorf("%s mismatch: manifest has %v, expected %v", field, manifest, expected)
+}
+
+// verifySectorImport checks the manifest and the files of a sector to import
+// against its on-chain commitments and the chain randomness, naming the field
+// which disagrees
+func (sm *StorageMinerAPI) verifySectorImport(ctx context.Context, mf *api.SectorManifest, ref sto.SectorRef, sealed, cache string, allowUnproven bool) error {
+	maddr := sm.Miner.Address()
+	if mf.Miner != maddr {
+		return mismatch("Miner", mf.Miner, maddr)
+	}
+	if mf.CommD == nil || mf.CommR == nil {
+		return xerrors.Errorf("manifest is missing CommD or CommR")
+	}
+
+	pieces := make([]abi.PieceInfo, len(mf.Pieces))
+	var deals []abi.DealID
+	for i, p := range mf.Pieces {
+		pieces[i] = p.Piece
+		if p.Deal != nil {
+			deals = append(deals, p.Deal.DealID)
+		}
+	}
+	commD, err := ffiwrapper.GenerateUnsealedCID(mf.SectorType, pieces)
+	if err != nil {
+		return xerrors.Errorf("computing CommD of the pieces: %w", err)
+	}
+	if commD != *mf.CommD {
+		return mismatch("CommD", *mf.CommD, commD)
+	}
+
+	onChain, err := sm.Full.StateSectorGetInfo(ctx, maddr, mf.SectorNumber, types.EmptyTSK)
+	if err != nil {
+		return xerrors.Errorf("getting on-chain sector info: %w", err)
+	}
+	if onChain == nil {
+		if !allowUnproven {
+			return xerrors.Errorf("sector isn't proven on chain, set --allow-unproven to import it anyway")
+		}
+		log.Warnw("importing sector not proven on chain", "sector", mf.SectorNumber)
+	} else {
+		if onChain.SealProof != mf.SectorType {
+			return mismatch("SectorType", mf.SectorType, onChain.SealProof)
+		}
+		if onChain.SealedCID != *mf.CommR {
+			return mismatch("CommR", *mf.CommR, onChain.SealedCID)
+		}
+		if fmt.Sprint(deals) != fmt.Sprint(onChain.DealIDs) {
+			return mismatch("Deals", deals, onChain.DealIDs)
+		}
+	}
+
+	for i, p := range mf.Pieces {
+		if p.Deal == nil {
+			continue
+		}
+		deal, err := sm.Full.StateMarketStorageDeal(ctx, p.Deal.DealID, types.EmptyTSK)
+		if err != nil {
+			// deals are removed from the market state once they expire
+			log.Warnw("not checking the piece of a deal not found on chain", "sector", mf.SectorNumber, "deal", p.Deal.DealID, "error", err)
+			continue
+		}
+		if deal.Proposal.PieceCID != p.Piece.PieceCID {
+			return mismatch(fmt.Sprintf("Pieces[%d].Piece.PieceCID", i), p.Piece.PieceCID, deal.Proposal.PieceCID)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := maddr.MarshalCBOR(buf); err != nil {
+		return err
+	}
+	ticket, err := sm.Full.ChainGetRandomnessFromTickets(ctx, types.EmptyTSK, crypto.DomainSeparationTag_SealRandomness, mf.Ticket.Epoch, buf.Bytes())
+	if err != nil {
+		return xerrors.Errorf("getting ticket randomness: %w", err)
+	}
+	if !bytes.Equal(ticket, mf.Ticket.Value) {
+		return mismatch("Ticket.Value", fmt.Sprintf("%x", mf.Ticket.Value), fmt.Sprintf("%x", ticket))
+	}
+	if onChain != nil || mf.Seed.Value != nil {
+		seed, err := sm.Full.ChainGetRandomnessFromBeacon(ctx, types.EmptyTSK, crypto.DomainSeparationTag_InteractiveSealChallengeSeed, mf.Seed.Epoch, buf.Bytes())
+		if err != nil {
+			return xerrors.Errorf("getting seed randomness: %w", err)
+		}
+		if !bytes.Equal(seed, mf.Seed.Value) {
+			return mismatch("Seed.Value", fmt.Sprintf("%x", mf.Seed.Value), fmt.Sprintf("%x", seed))
+		}
+	}
+
+	if err := sectorstorage.VerifySectorFiles(ref, sealed, cache, *mf.CommR); err != nil {
+		return xerrors.Errorf("sector files don't match CommR: %w", err)
+	}
+	return nil
+}
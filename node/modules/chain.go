@@ -26,6 +26,7 @@ import (
 	"github.com/filecoin-project/lotus/chain/vm"
 	"github.com/filecoin-project/lotus/extern/sector-storage/ffiwrapper"
 	"github.com/filecoin-project/lotus/journal"
+	"github.com/filecoin-project/lotus/lib/headpin"
 	"github.com/filecoin-project/lotus/node/modules/dtypes"
 	"github.com/filecoin-project/lotus/node/modules/helpers"
 )
@@ -101,6 +102,21 @@ func ChainStore(lc fx.Lifecycle, cbs dtypes.ChainBlockstore, sbs dtypes.StateBlo
 	return chain
 }
 
+// HeadPins sets up the heads pinned by ChainPinHead, whose state the
+// splitstore keeps, ending the sessions as the node shuts down
+func HeadPins(lc fx.Lifecycle, basebs dtypes.BaseBlockstore) *headpin.Pins {
+	p := headpin.NewPins()
+	if ss, ok := basebs.(*splitstore.SplitStore); ok {
+		ss.AddProtector(p.TipSets)
+	}
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			return p.Close()
+		},
+	})
+	return p
+}
+
 func NetworkName(mctx helpers.MetricsCtx, lc fx.Lifecycle, cs *store.ChainStore, us stmgr.UpgradeSchedule, _ dtypes.AfterGenesisSet) (dtypes.NetworkName, error) {
 	if !build.Devnet {
 		return "testnetnet", nil
@@ -35,6 +35,8 @@ import (
 	"github.com/filecoin-project/lotus/markets"
 	marketevents "github.com/filecoin-project/lotus/markets/loggers"
 	"github.com/filecoin-project/lotus/markets/retrievaladapter"
+	"github.com/filecoin-project/lotus/markets/retrievalstats"
+	"github.com/filecoin-project/lotus/node/config"
 	"github.com/filecoin-project/lotus/node/impl/full"
 	payapi "github.com/filecoin-project/lotus/node/impl/paych"
 	"github.com/filecoin-project/lotus/node/modules/dtypes"
@@ -174,6 +176,14 @@ func NewClientGraphsyncDataTransfer(lc fx.Lifecycle, h host.Host, gs dtypes.Grap
 }
 
 // NewClientDatastore creates a datastore for the client to store its deals
+// RetrievalStats sets up the records of the retrievals made by the client,
+// kept for retention
+func RetrievalStats(retention config.Duration) func(ds dtypes.MetadataDS) *retrievalstats.Store {
+	return func(ds dtypes.MetadataDS) *retrievalstats.Store {
+		return retrievalstats.NewStore(ds, time.Duration(retention))
+	}
+}
+
 func NewClientDatastore(ds dtypes.MetadataDS) dtypes.ClientDatastore {
 	return namespace.Wrap(ds, datastore.NewKey("/deals/client"))
 }
@@ -0,0 +1,59 @@
+package modules
+
+import (
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/chain/messagepool"
+	sectorstorage "github.com/filecoin-project/lotus/extern/sector-storage"
+	"github.com/filecoin-project/lotus/node/config"
+	"github.com/filecoin-project/lotus/node/modules/dtypes"
+	"github.com/filecoin-project/lotus/node/modules/helpers"
+)
+
+// RecordFullNodeConfig takes a snapshot of the config of the full node on
+// startup: its config file and mpool config
+func RecordFullNodeConfig(h *config.History, cfg dtypes.LoadedConfig, mp *messagepool.MessagePool) error {
+	mpool, err := config.FlattenJSON(mp.GetConfig())
+	if err != nil {
+		return xerrors.Errorf("flattening mpool config: %w", err)
+	}
+	return recordStartupConfig(h, cfg, map[string]string{"mpool": mpool})
+}
+
+// RecordMinerConfig takes a snapshot of the config of the miner on startup:
+// its config file, sealing config and storage paths
+func RecordMinerConfig(mctx helpers.MetricsCtx, h *config.History, cfg dtypes.LoadedConfig, getSealing dtypes.GetSealingConfigFunc, sm *sectorstorage.Manager) error {
+	sc, err := getSealing()
+	if err != nil {
+		return xerrors.Errorf("getting sealing config: %w", err)
+	}
+	sealing, err := config.FlattenJSON(sc)
+	if err != nil {
+		return xerrors.Errorf("flattening sealing config: %w", err)
+	}
+
+	paths, err := sm.StorageLocal(mctx)
+	if err != nil {
+		return xerrors.Errorf("listing storage paths: %w", err)
+	}
+	storage, err := config.FlattenJSON(paths)
+	if err != nil {
+		return xerrors.Errorf("flattening storage paths: %w", err)
+	}
+
+	return recordStartupConfig(h, cfg, map[string]string{"sealing": sealing, "storage": storage})
+}
+
+func recordStartupConfig(h *config.History, cfg dtypes.LoadedConfig, sections map[string]string) error {
+	file, err := config.FlattenConfig(cfg)
+	if err != nil {
+		return xerrors.Errorf("flattening config: %w", err)
+	}
+	sections["config"] = file
+
+	if err := h.Record("startup", "local", sections); err != nil {
+		// the history is for troubleshooting, the node runs without it
+		log.Errorf("recording config snapshot: %s", err)
+	}
+	return nil
+}
@@ -26,6 +26,8 @@ import (
 	"github.com/filecoin-project/lotus/build"
 	"github.com/filecoin-project/lotus/chain/types"
 	"github.com/filecoin-project/lotus/lib/addrutil"
+	"github.com/filecoin-project/lotus/lib/loglevels"
+	"github.com/filecoin-project/lotus/lib/rpcrecord"
 	"github.com/filecoin-project/lotus/node/config"
 	"github.com/filecoin-project/lotus/node/modules/dtypes"
 	"github.com/filecoin-project/lotus/node/repo"
@@ -181,6 +183,30 @@ func APISecret(keystore types.KeyStore, lr repo.LockedRepo) (*dtypes.APIAlg, err
 	return (*dtypes.APIAlg)(jwt.NewHS256(key.PrivateKey)), nil
 }
 
+// RPCRecorder sets up the recorder of the API calls, stopping any active
+// recording as the node shuts down
+func RPCRecorder(lc fx.Lifecycle) *rpcrecord.Recorder {
+	r := rpcrecord.NewRecorder()
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			return r.Close()
+		},
+	})
+	return r
+}
+
+// LogLevels sets up the runtime log levels, reverting the temporary overrides
+// as the node shuts down
+func LogLevels(lc fx.Lifecycle) *loglevels.Levels {
+	l := loglevels.New()
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			return l.Close()
+		},
+	})
+	return l
+}
+
 func ConfigBootstrap(peers []string) func() (dtypes.BootstrapPeers, error) {
 	return func() (dtypes.BootstrapPeers, error) {
 		return addrutil.ParseAddresses(context.TODO(), peers)
@@ -214,6 +240,33 @@ func NewDefaultMaxFeeFunc(r repo.LockedRepo) dtypes.DefaultMaxFeeFunc {
 	}
 }
 
+func NewMinGasPremiumFunc(r repo.LockedRepo) dtypes.MinGasPremiumFunc {
+	return func() (out abi.TokenAmount, err error) {
+		err = readNodeCfg(r, func(cfg *config.FullNode) {
+			out = abi.TokenAmount(cfg.Fees.MinGasPremium)
+		})
+		return
+	}
+}
+
+func NewGasLimitMarginsFunc(r repo.LockedRepo) dtypes.GasLimitMarginsFunc {
+	return func() (out map[string]float64, err error) {
+		err = readNodeCfg(r, func(cfg *config.FullNode) {
+			out = cfg.Fees.GasLimitMargins
+		})
+		return
+	}
+}
+
+func NewSendReferenceThresholdFunc(r repo.LockedRepo) dtypes.SendReferenceThresholdFunc {
+	return func() (out abi.TokenAmount, err error) {
+		err = readNodeCfg(r, func(cfg *config.FullNode) {
+			out = abi.TokenAmount(cfg.SendAudit.ReferenceRequiredAbove)
+		})
+		return
+	}
+}
+
 func readNodeCfg(r repo.LockedRepo, accessor func(node *config.FullNode)) error {
 	raw, err := r.Config()
 	if err != nil {
@@ -0,0 +1,5 @@
+package dtypes
+
+// LoadedConfig is the node config (*config.FullNode or *config.StorageMiner)
+// as loaded from the repo when the node was started.
+type LoadedConfig interface{}
@@ -88,6 +88,14 @@ type SetExpectedSealDurationFunc func(time.Duration) error
 // too determine how long sealing is expected to take
 type GetExpectedSealDurationFunc func() (time.Duration, error)
 
+// DiscloseSealingStatusFunc returns whether deal clients are told where their
+// deals are in the sealing pipeline
+type DiscloseSealingStatusFunc func() (bool, error)
+
+// SetWdPoStParallelBatchesFunc is a function which is used to set how many
+// batches of partitions of a deadline are proven at once.
+type SetWdPoStParallelBatchesFunc func(int) error
+
 type SetMaxDealStartDelayFunc func(time.Duration) error
 type GetMaxDealStartDelayFunc func() (time.Duration, error)
 
@@ -36,3 +36,13 @@ func (ml *MpoolLocker) TakeLock(ctx context.Context, a address.Address) (func(),
 }
 
 type DefaultMaxFeeFunc func() (abi.TokenAmount, error)
+
+// MinGasPremiumFunc returns the gas premium floor, zero if there is none
+type MinGasPremiumFunc func() (abi.TokenAmount, error)
+
+// GasLimitMarginsFunc returns the gas limit margins of the node config
+type GasLimitMarginsFunc func() (map[string]float64, error)
+
+// SendReferenceThresholdFunc returns the value above which sends need a
+// reference, zero if references aren't required
+type SendReferenceThresholdFunc func() (abi.TokenAmount, error)
@@ -17,6 +17,7 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/filecoin-project/lotus/build"
+	"github.com/filecoin-project/lotus/chain/msgprop"
 	"github.com/filecoin-project/lotus/metrics"
 	"github.com/filecoin-project/lotus/node/config"
 	"github.com/filecoin-project/lotus/node/modules/dtypes"
@@ -51,6 +52,7 @@ type GossipIn struct {
 	Cfg  *config.Pubsub
 	Sk   *dtypes.ScoreKeeper
 	Dr   dtypes.DrandSchedule
+	Prop *msgprop.Tracker `optional:"true"`
 }
 
 func getDrandTopic(chainInfoJSON string) (string, error) {
@@ -349,11 +351,11 @@ func GossipSub(in GossipIn) (service *pubsub.PubSub, err error) {
 			return nil, err
 		}
 
-		trw := newTracerWrapper(tr, build.BlocksTopic(in.Nn))
+		trw := newTracerWrapper(tr, in.Prop, build.BlocksTopic(in.Nn))
 		options = append(options, pubsub.WithEventTracer(trw))
 	} else {
 		// still instantiate a tracer for collecting metrics
-		trw := newTracerWrapper(nil)
+		trw := newTracerWrapper(nil, in.Prop)
 		options = append(options, pubsub.WithEventTracer(trw))
 	}
 
@@ -365,7 +367,7 @@ func HashMsgId(m *pubsub_pb.Message) string {
 	return string(hash[:])
 }
 
-func newTracerWrapper(tr pubsub.EventTracer, topics ...string) pubsub.EventTracer {
+func newTracerWrapper(tr pubsub.EventTracer, prop *msgprop.Tracker, topics ...string) pubsub.EventTracer {
 	var topicsMap map[string]struct{}
 	if len(topics) > 0 {
 		topicsMap = make(map[string]struct{})
@@ -374,11 +376,12 @@ func newTracerWrapper(tr pubsub.EventTracer, topics ...string) pubsub.EventTrace
 		}
 	}
 
-	return &tracerWrapper{tr: tr, topics: topicsMap}
+	return &tracerWrapper{tr: tr, prop: prop, topics: topicsMap}
 }
 
 type tracerWrapper struct {
 	tr     pubsub.EventTracer
+	prop   *msgprop.Tracker
 	topics map[string]struct{}
 }
 
@@ -388,6 +391,11 @@ func (trw *tracerWrapper) traceMessage(topic string) bool {
 }
 
 func (trw *tracerWrapper) Trace(evt *pubsub_pb.TraceEvent) {
+	// the propagation of published messages is tracked locally from all events
+	if trw.prop != nil {
+		trw.prop.Trace(evt)
+	}
+
 	// this filters the trace events reported to the remote tracer to include only
 	// JOIN/LEAVE/GRAFT/PRUNE/PUBLISH/DELIVER. This significantly reduces bandwidth usage and still
 	// collects enough data to recover the state of the mesh and compute message delivery latency
@@ -1,20 +1,139 @@
 package modules
 
 import (
+	"context"
+
 	"go.uber.org/fx"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
 
+	"github.com/filecoin-project/lotus/chain/actorcache"
+	"github.com/filecoin-project/lotus/chain/denylist"
+	"github.com/filecoin-project/lotus/chain/follow"
+	"github.com/filecoin-project/lotus/chain/history"
 	"github.com/filecoin-project/lotus/chain/stmgr"
 	"github.com/filecoin-project/lotus/chain/store"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/node/config"
+	"github.com/filecoin-project/lotus/node/modules/dtypes"
 )
 
-func StateManager(lc fx.Lifecycle, cs *store.ChainStore, us stmgr.UpgradeSchedule) (*stmgr.StateManager, error) {
+func StateManager(lc fx.Lifecycle, cs *store.ChainStore, us stmgr.UpgradeSchedule, ds dtypes.MetadataDS) (*stmgr.StateManager, error) {
 	sm, err := stmgr.NewStateManagerWithUpgradeSchedule(cs, us)
 	if err != nil {
 		return nil, err
 	}
+	// the caches only speed up the migrations, don't fail on them
+	if err := sm.LoadMigrationCaches(ds); err != nil {
+		log.Warnf("loading persisted migration caches: %s", err)
+	}
 	lc.Append(fx.Hook{
 		OnStart: sm.Start,
 		OnStop:  sm.Stop,
 	})
 	return sm, nil
 }
+
+// WatchedActors sets up the watched actor cache with the configured actors,
+// refreshing it on head changes
+func WatchedActors(cfg config.WatchedActors) func(lc fx.Lifecycle, sm *stmgr.StateManager) (*actorcache.WatchedActors, error) {
+	return func(lc fx.Lifecycle, sm *stmgr.StateManager) (*actorcache.WatchedActors, error) {
+		addrs := make([]address.Address, 0, len(cfg.Addresses))
+		for _, s := range cfg.Addresses {
+			addr, err := address.NewFromString(s)
+			if err != nil {
+				return nil, xerrors.Errorf("parsing watched actor address '%s': %w", s, err)
+			}
+			addrs = append(addrs, addr)
+		}
+
+		wa := actorcache.NewWatchedActors(sm, addrs)
+		lc.Append(fx.Hook{
+			OnStart: func(context.Context) error {
+				wa.Start()
+				sm.ChainStore().SubscribeHeadChanges(wa.HeadChange)
+				return wa.HeadChange(nil, []*types.TipSet{sm.ChainStore().GetHeaviestTipSet()})
+			},
+			OnStop: func(context.Context) error {
+				wa.Stop()
+				return nil
+			},
+		})
+		return wa, nil
+	}
+}
+
+// SendDenylist sets up the denylist MpoolPushMessage checks messages against
+func SendDenylist(cfg config.SendDenylist) func() (*denylist.Denylist, error) {
+	return func() (*denylist.Denylist, error) {
+		addrs := make([]address.Address, 0, len(cfg.Addresses))
+		for _, s := range cfg.Addresses {
+			addr, err := address.NewFromString(s)
+			if err != nil {
+				return nil, xerrors.Errorf("parsing denied address '%s': %w", s, err)
+			}
+			addrs = append(addrs, addr)
+		}
+
+		dl := denylist.New(addrs, cfg.File)
+		if cfg.File != "" {
+			// fail on startup rather than on the first send
+			if err := dl.Load(); err != nil {
+				return nil, err
+			}
+		}
+		return dl, nil
+	}
+}
+
+// ChainFollower sets up chain follow deliveries, registering the built-in
+// ndjson consumer when it's configured
+func ChainFollower(cfg config.ChainFollow) func(lc fx.Lifecycle, cs *store.ChainStore, ds dtypes.MetadataDS) (*follow.Follower, error) {
+	return func(lc fx.Lifecycle, cs *store.ChainStore, ds dtypes.MetadataDS) (*follow.Follower, error) {
+		f := follow.NewFollower(cs, ds)
+
+		if cfg.NdjsonDir != "" {
+			nc, err := follow.NewNdjsonConsumer(cs, cfg.NdjsonDir)
+			if err != nil {
+				return nil, err
+			}
+			if err := f.Register(follow.NdjsonConsumerName, nc); err != nil {
+				return nil, err
+			}
+		}
+
+		lc.Append(fx.Hook{
+			OnStart: func(context.Context) error {
+				f.Start()
+				cs.SubscribeHeadChanges(f.HeadChange)
+				return nil
+			},
+			OnStop: func(context.Context) error {
+				f.Stop()
+				return nil
+			},
+		})
+		return f, nil
+	}
+}
+
+// MetricsHistory sets up the metrics history recorder, sampling the
+// configured series as tipsets are delivered by the chain follower
+func MetricsHistory(cfg config.MetricsHistory) func(sm *stmgr.StateManager, ds dtypes.MetadataDS, f *follow.Follower) (*history.Recorder, error) {
+	return func(sm *stmgr.StateManager, ds dtypes.MetadataDS, f *follow.Follower) (*history.Recorder, error) {
+		tiers := make([]history.Tier, len(cfg.Tiers))
+		for i, t := range cfg.Tiers {
+			tiers[i] = history.Tier{Resolution: t.Resolution, Retention: t.Retention}
+		}
+
+		r, err := history.NewRecorder(sm, ds, cfg.Series, tiers)
+		if err != nil {
+			return nil, xerrors.Errorf("setting up metrics history: %w", err)
+		}
+		if err := f.Register(history.ConsumerName, r); err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+}
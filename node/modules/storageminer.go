@@ -67,6 +67,7 @@ import (
 	"github.com/filecoin-project/lotus/markets"
 	marketevents "github.com/filecoin-project/lotus/markets/loggers"
 	"github.com/filecoin-project/lotus/markets/retrievaladapter"
+	"github.com/filecoin-project/lotus/markets/sealingstatus"
 	lotusminer "github.com/filecoin-project/lotus/miner"
 	"github.com/filecoin-project/lotus/node/config"
 	"github.com/filecoin-project/lotus/node/modules/dtypes"
@@ -208,7 +209,7 @@ type StorageMinerParams struct {
 	AddrSel            *storage.AddressSelector
 }
 
-func StorageMiner(fc config.MinerFeeConfig) func(params StorageMinerParams) (*storage.Miner, error) {
+func StorageMiner(fc config.MinerFeeConfig, pc config.ProvingConfig) func(params StorageMinerParams) (*storage.Miner, error) {
 	return func(params StorageMinerParams) (*storage.Miner, error) {
 		var (
 			ds     = params.MetadataDS
@@ -232,12 +233,12 @@ func StorageMiner(fc config.MinerFeeConfig) func(params StorageMinerParams) (*st
 
 		ctx := helpers.LifecycleCtx(mctx, lc)
 
-		fps, err := storage.NewWindowedPoStScheduler(api, fc, as, sealer, verif, sealer, j, maddr)
+		fps, err := storage.NewWindowedPoStScheduler(api, fc, pc, as, storage.NewWindowPoStProver(pc, sealer), verif, sealer, j, maddr)
 		if err != nil {
 			return nil, err
 		}
 
-		sm, err := storage.NewMiner(api, maddr, h, ds, sealer, sc, verif, prover, gsd, fc, j, as)
+		sm, err := storage.NewMiner(api, maddr, h, ds, sealer, sc, verif, prover, gsd, fc, j, as, fps)
 		if err != nil {
 			return nil, err
 		}
@@ -446,12 +447,17 @@ func SetupBlockProducer(lc fx.Lifecycle, ds dtypes.MetadataDS, api v1api.FullNod
 	}
 
 	m := lotusminer.NewMiner(api, epp, minerAddr, sf, j)
+	if os.Getenv(lotusminer.EnvDevnetTimeTravel) == "1" {
+		m.EnableTimeTravel()
+	}
 
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			if err := m.Start(ctx); err != nil {
 				return err
 			}
+			// find out if block production is broken before winning a block
+			go m.Simulate(context.Background())
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
@@ -910,6 +916,33 @@ func NewGetExpectedSealDurationFunc(r repo.LockedRepo) (dtypes.GetExpectedSealDu
 	}, nil
 }
 
+func NewSetWdPoStParallelBatchesFunc(r repo.LockedRepo) (dtypes.SetWdPoStParallelBatchesFunc, error) {
+	return func(n int) (err error) {
+		err = mutateCfg(r, func(cfg *config.StorageMiner) {
+			cfg.Proving.ParallelBatches = n
+		})
+		return
+	}, nil
+}
+
+func NewDiscloseSealingStatusFunc(r repo.LockedRepo) (dtypes.DiscloseSealingStatusFunc, error) {
+	return func() (out bool, err error) {
+		err = readCfg(r, func(cfg *config.StorageMiner) {
+			out = cfg.Dealmaking.DiscloseSealingStatus
+		})
+		return
+	}, nil
+}
+
+// SealingStatusProvider answers the sealing status requests of deal clients
+func SealingStatusProvider(full v1api.FullNode, sp storagemarket.StorageProvider, m *storage.Miner, disclose dtypes.DiscloseSealingStatusFunc) *sealingstatus.Provider {
+	return sealingstatus.NewProvider(full, sp, m, disclose)
+}
+
+func HandleSealingStatus(h host.Host, p *sealingstatus.Provider) {
+	h.SetStreamHandler(sealingstatus.ProtocolID, p.HandleStream)
+}
+
 func NewSetMaxDealStartDelayFunc(r repo.LockedRepo) (dtypes.SetMaxDealStartDelayFunc, error) {
 	return func(delay time.Duration) (err error) {
 		err = mutateCfg(r, func(cfg *config.StorageMiner) {
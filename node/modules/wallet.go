@@ -0,0 +1,97 @@
+package modules
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	"github.com/mitchellh/go-homedir"
+	"go.uber.org/fx"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+
+	"github.com/filecoin-project/lotus/chain/stmgr"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/chain/wallet"
+	"github.com/filecoin-project/lotus/node/config"
+	"github.com/filecoin-project/lotus/node/modules/dtypes"
+)
+
+// LocalWallet sets up the local wallet, which starts with signing disabled if
+// the keystore is encrypted, unless the passphrase is configured
+func LocalWallet(cfg config.Wallet) func(lc fx.Lifecycle, ks types.KeyStore) (*wallet.LocalWallet, error) {
+	return func(lc fx.Lifecycle, ks types.KeyStore) (*wallet.LocalWallet, error) {
+		eks := wallet.NewEncryptedKeyStore(ks, time.Duration(cfg.KeystoreRelockAfter))
+		encrypted, err := eks.Encrypted()
+		if err != nil {
+			return nil, xerrors.Errorf("checking keystore encryption: %w", err)
+		}
+		if !encrypted {
+			return wallet.NewWallet(ks)
+		}
+
+		w, err := wallet.NewWallet(eks)
+		if err != nil {
+			return nil, err
+		}
+
+		passphrase, err := keystorePassphrase(cfg.KeystorePassphraseFile)
+		if err != nil {
+			return nil, err
+		}
+		if passphrase != nil {
+			if err := eks.Unlock(passphrase); err != nil {
+				return nil, xerrors.Errorf("unlocking keystore: %w", err)
+			}
+		} else {
+			log.Warn("keystore is encrypted, signing is disabled until it's unlocked with 'lotus wallet keystore unlock'")
+		}
+
+		lc.Append(fx.Hook{
+			OnStop: func(context.Context) error {
+				eks.Lock()
+				return nil
+			},
+		})
+
+		return w, nil
+	}
+}
+
+// WalletUsagePolicies sets up the wallet usage policies, which look up the
+// actors messages are sent to in the head state
+func WalletUsagePolicies(ds dtypes.MetadataDS, sm *stmgr.StateManager) *wallet.UsagePolicies {
+	return wallet.NewUsagePolicies(ds, func(ctx context.Context, addr address.Address) (cid.Cid, error) {
+		act, err := sm.LoadActorTsk(ctx, addr, types.EmptyTSK)
+		if xerrors.Is(err, types.ErrActorNotFound) {
+			return cid.Undef, nil
+		} else if err != nil {
+			return cid.Undef, err
+		}
+		return act.Code, nil
+	})
+}
+
+func keystorePassphrase(file string) ([]byte, error) {
+	if file != "" {
+		file, err := homedir.Expand(file)
+		if err != nil {
+			return nil, err
+		}
+
+		passphrase, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, xerrors.Errorf("reading keystore passphrase: %w", err)
+		}
+		return bytes.TrimRight(passphrase, "\r\n"), nil
+	}
+
+	if passphrase, ok := os.LookupEnv(wallet.EnvKeystorePassphrase); ok {
+		return []byte(passphrase), nil
+	}
+	return nil, nil
+}
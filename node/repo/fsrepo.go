@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
@@ -39,6 +40,8 @@ const (
 	fsDatastore     = "datastore"
 	fsLock          = "repo.lock"
 	fsKeystore      = "keystore"
+	fsKeystoreNew   = "keystore.new"
+	fsKeystoreBak   = "keystore.bak"
 )
 
 type RepoType int
@@ -469,19 +472,169 @@ func (fsr *fsLockedRepo) KeyStore() (types.KeyStore, error) {
 	if err := fsr.stillValid(); err != nil {
 		return nil, err
 	}
-	return fsr, nil
+	if err := fsr.finishKeyStoreRewrite(); err != nil {
+		return nil, err
+	}
+	return fsr.keyStoreAt(fsKeystore), nil
+}
+
+func (fsr *fsLockedRepo) keyStoreAt(dir string) *fsKeyStore {
+	return &fsKeyStore{
+		path:       fsr.join(dir),
+		stillValid: fsr.stillValid,
+	}
+}
+
+// finishKeyStoreRewrite completes a keystore rewrite interrupted between
+// moving the old keystore to the backup and moving the new one in place
+func (fsr *fsLockedRepo) finishKeyStoreRewrite() error {
+	if _, err := os.Stat(fsr.join(fsKeystore)); !os.IsNotExist(err) {
+		return err
+	}
+	if _, err := os.Stat(fsr.join(fsKeystoreBak)); err != nil {
+		return nil
+	}
+	if _, err := os.Stat(fsr.join(fsKeystoreNew)); err != nil {
+		return nil
+	}
+
+	log.Warnf("finishing interrupted keystore rewrite")
+	if err := os.Rename(fsr.join(fsKeystoreNew), fsr.join(fsKeystore)); err != nil {
+		return xerrors.Errorf("moving new keystore in place: %w", err)
+	}
+	return nil
+}
+
+func (fsr *fsLockedRepo) RewriteKeyStore(rewrite func(from, to types.KeyStore) error) error {
+	if err := fsr.stillValid(); err != nil {
+		return err
+	}
+	if err := fsr.finishKeyStoreRewrite(); err != nil {
+		return err
+	}
+
+	if has, err := fsr.KeyStoreBackup(); err != nil {
+		return err
+	} else if has {
+		return xerrors.Errorf("keystore backup %s exists, restore or drop it first", fsr.join(fsKeystoreBak))
+	}
+
+	// clean up after a rewrite which failed before the new keystore was complete
+	if err := os.RemoveAll(fsr.join(fsKeystoreNew)); err != nil {
+		return xerrors.Errorf("removing stale staging keystore: %w", err)
+	}
+	if err := os.Mkdir(fsr.join(fsKeystoreNew), 0700); err != nil {
+		return xerrors.Errorf("creating staging keystore: %w", err)
+	}
+
+	if err := rewrite(fsr.keyStoreAt(fsKeystore), fsr.keyStoreAt(fsKeystoreNew)); err != nil {
+		if rerr := os.RemoveAll(fsr.join(fsKeystoreNew)); rerr != nil {
+			log.Errorf("removing staging keystore: %s", rerr)
+		}
+		return xerrors.Errorf("rewriting keystore: %w", err)
+	}
+
+	if err := os.Rename(fsr.join(fsKeystore), fsr.join(fsKeystoreBak)); err != nil {
+		return xerrors.Errorf("moving keystore to backup: %w", err)
+	}
+	if err := os.Rename(fsr.join(fsKeystoreNew), fsr.join(fsKeystore)); err != nil {
+		return xerrors.Errorf("moving new keystore in place: %w", err)
+	}
+	return nil
+}
+
+func (fsr *fsLockedRepo) KeyStoreBackup() (bool, error) {
+	_, err := os.Stat(fsr.join(fsKeystoreBak))
+	switch {
+	case err == nil:
+		return true, nil
+	case os.IsNotExist(err):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+func (fsr *fsLockedRepo) MissingFromKeyStoreBackup() ([]string, error) {
+	if has, err := fsr.KeyStoreBackup(); err != nil {
+		return nil, err
+	} else if !has {
+		return nil, xerrors.Errorf("no keystore backup")
+	}
+
+	current, err := fsr.keyStoreAt(fsKeystore).List()
+	if err != nil {
+		return nil, xerrors.Errorf("listing keystore: %w", err)
+	}
+	backup, err := fsr.keyStoreAt(fsKeystoreBak).List()
+	if err != nil {
+		return nil, xerrors.Errorf("listing keystore backup: %w", err)
+	}
+
+	backedUp := make(map[string]struct{}, len(backup))
+	for _, name := range backup {
+		backedUp[name] = struct{}{}
+	}
+	var missing []string
+	for _, name := range current {
+		if _, ok := backedUp[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing, nil
+}
+
+func (fsr *fsLockedRepo) RestoreKeyStoreBackup() error {
+	if err := fsr.stillValid(); err != nil {
+		return err
+	}
+
+	if has, err := fsr.KeyStoreBackup(); err != nil {
+		return err
+	} else if !has {
+		return xerrors.Errorf("no keystore backup")
+	}
+
+	// the current keystore becomes the staging keystore, which is cleaned
+	// up on the next rewrite, so the swap can't lose keys if interrupted
+	if err := os.RemoveAll(fsr.join(fsKeystoreNew)); err != nil {
+		return xerrors.Errorf("removing stale staging keystore: %w", err)
+	}
+	if err := os.Rename(fsr.join(fsKeystore), fsr.join(fsKeystoreNew)); err != nil {
+		return xerrors.Errorf("moving keystore away: %w", err)
+	}
+	if err := os.Rename(fsr.join(fsKeystoreBak), fsr.join(fsKeystore)); err != nil {
+		return xerrors.Errorf("restoring keystore backup: %w", err)
+	}
+	return os.RemoveAll(fsr.join(fsKeystoreNew))
+}
+
+func (fsr *fsLockedRepo) DropKeyStoreBackup() error {
+	if err := fsr.stillValid(); err != nil {
+		return err
+	}
+	return os.RemoveAll(fsr.join(fsKeystoreBak))
+}
+
+var _ KeyStoreRewriter = &fsLockedRepo{}
+
+// fsKeyStore is a KeyStore keeping each key in a separate file in a directory
+type fsKeyStore struct {
+	path       string
+	stillValid func() error
 }
 
 var kstrPermissionMsg = "permissions of key: '%s' are too relaxed, " +
 	"required: 0600, got: %#o"
 
 // List lists all the keys stored in the KeyStore
-func (fsr *fsLockedRepo) List() ([]string, error) {
-	if err := fsr.stillValid(); err != nil {
+func (fks *fsKeyStore) List() ([]string, error) {
+	if err := fks.stillValid(); err != nil {
 		return nil, err
 	}
 
-	kstorePath := fsr.join(fsKeystore)
+	kstorePath := fks.path
 	dir, err := os.Open(kstorePath)
 	if err != nil {
 		return nil, xerrors.Errorf("opening dir to list keystore: %w", err)
@@ -506,13 +659,13 @@ func (fsr *fsLockedRepo) List() ([]string, error) {
 }
 
 // Get gets a key out of keystore and returns types.KeyInfo coresponding to named key
-func (fsr *fsLockedRepo) Get(name string) (types.KeyInfo, error) {
-	if err := fsr.stillValid(); err != nil {
+func (fks *fsKeyStore) Get(name string) (types.KeyInfo, error) {
+	if err := fks.stillValid(); err != nil {
 		return types.KeyInfo{}, err
 	}
 
 	encName := base32.RawStdEncoding.EncodeToString([]byte(name))
-	keyPath := fsr.join(fsKeystore, encName)
+	keyPath := filepath.Join(fks.path, encName)
 
 	fstat, err := os.Stat(keyPath)
 	if os.IsNotExist(err) {
@@ -548,12 +701,12 @@ func (fsr *fsLockedRepo) Get(name string) (types.KeyInfo, error) {
 const KTrashPrefix = "trash-"
 
 // Put saves key info under given name
-func (fsr *fsLockedRepo) Put(name string, info types.KeyInfo) error {
-	return fsr.put(name, info, 0)
+func (fks *fsKeyStore) Put(name string, info types.KeyInfo) error {
+	return fks.put(name, info, 0)
 }
 
-func (fsr *fsLockedRepo) put(rawName string, info types.KeyInfo, retries int) error {
-	if err := fsr.stillValid(); err != nil {
+func (fks *fsKeyStore) put(rawName string, info types.KeyInfo, retries int) error {
+	if err := fks.stillValid(); err != nil {
 		return err
 	}
 
@@ -563,12 +716,12 @@ func (fsr *fsLockedRepo) put(rawName string, info types.KeyInfo, retries int) er
 	}
 
 	encName := base32.RawStdEncoding.EncodeToString([]byte(name))
-	keyPath := fsr.join(fsKeystore, encName)
+	keyPath := filepath.Join(fks.path, encName)
 
 	_, err := os.Stat(keyPath)
 	if err == nil && strings.HasPrefix(name, KTrashPrefix) {
 		// retry writing the trash-prefixed file with a number suffix
-		return fsr.put(rawName, info, retries+1)
+		return fks.put(rawName, info, retries+1)
 	} else if err == nil {
 		return xerrors.Errorf("checking key before put '%s': %w", name, types.ErrKeyExists)
 	} else if !os.IsNotExist(err) {
@@ -587,13 +740,13 @@ func (fsr *fsLockedRepo) put(rawName string, info types.KeyInfo, retries int) er
 	return nil
 }
 
-func (fsr *fsLockedRepo) Delete(name string) error {
-	if err := fsr.stillValid(); err != nil {
+func (fks *fsKeyStore) Delete(name string) error {
+	if err := fks.stillValid(); err != nil {
 		return err
 	}
 
 	encName := base32.RawStdEncoding.EncodeToString([]byte(name))
-	keyPath := fsr.join(fsKeystore, encName)
+	keyPath := filepath.Join(fks.path, encName)
 
 	_, err := os.Stat(keyPath)
 	if os.IsNotExist(err) {
@@ -4,6 +4,11 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/lotus/chain/types"
 )
 
 func genFsRepo(t *testing.T) (*FsRepo, func()) {
@@ -31,3 +36,72 @@ func TestFsBasic(t *testing.T) {
 	defer closer()
 	basicTest(t, repo)
 }
+
+func TestFsRewriteKeyStore(t *testing.T) {
+	repo, closer := genFsRepo(t)
+	defer closer()
+
+	lr, err := repo.Lock(FullNode)
+	require.NoError(t, err)
+	defer lr.Close() //nolint:errcheck
+
+	ks, err := lr.KeyStore()
+	require.NoError(t, err)
+	k1 := types.KeyInfo{Type: "foo", PrivateKey: []byte("k1")}
+	require.NoError(t, ks.Put("k1", k1))
+
+	kr := lr.(KeyStoreRewriter)
+
+	// failed rewrites leave the keystore as it was
+	err = kr.RewriteKeyStore(func(from, to types.KeyStore) error {
+		require.NoError(t, to.Put("k2", k1))
+		return xerrors.New("fail")
+	})
+	require.Error(t, err)
+	list, err := ks.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"k1"}, list)
+
+	rewrite := func(from, to types.KeyStore) error {
+		ki, err := from.Get("k1")
+		if err != nil {
+			return err
+		}
+		ki.PrivateKey = []byte("rewritten")
+		return to.Put("k1", ki)
+	}
+	require.NoError(t, kr.RewriteKeyStore(rewrite))
+
+	ki, err := ks.Get("k1")
+	require.NoError(t, err)
+	require.Equal(t, []byte("rewritten"), ki.PrivateKey)
+
+	has, err := kr.KeyStoreBackup()
+	require.NoError(t, err)
+	require.True(t, has)
+	require.Error(t, kr.RewriteKeyStore(rewrite), "backup must be dropped first")
+
+	// keys added after the rewrite are lost by restoring the backup
+	missing, err := kr.MissingFromKeyStoreBackup()
+	require.NoError(t, err)
+	require.Empty(t, missing)
+	require.NoError(t, ks.Put("k3", k1))
+	missing, err = kr.MissingFromKeyStoreBackup()
+	require.NoError(t, err)
+	require.Equal(t, []string{"k3"}, missing)
+	require.NoError(t, ks.Delete("k3"))
+
+	require.NoError(t, kr.RestoreKeyStoreBackup())
+	ki, err = ks.Get("k1")
+	require.NoError(t, err)
+	require.Equal(t, k1, ki)
+
+	require.NoError(t, kr.RewriteKeyStore(rewrite))
+	require.NoError(t, kr.DropKeyStoreBackup())
+	has, err = kr.KeyStoreBackup()
+	require.NoError(t, err)
+	require.False(t, has)
+	require.Error(t, kr.RestoreKeyStoreBackup())
+	_, err = kr.MissingFromKeyStoreBackup()
+	require.Error(t, err)
+}
@@ -92,3 +92,21 @@ type LockedRepo interface {
 	// Readonly returns true if the repo is readonly
 	Readonly() bool
 }
+
+// KeyStoreRewriter is implemented by repos which can atomically replace their
+// keystore, keeping the previous one as a backup
+type KeyStoreRewriter interface {
+	// RewriteKeyStore calls rewrite with the current keystore and an empty
+	// staging keystore, which replaces the current one if rewrite succeeds.
+	RewriteKeyStore(rewrite func(from, to types.KeyStore) error) error
+
+	// KeyStoreBackup returns whether a backup of a replaced keystore exists
+	KeyStoreBackup() (bool, error)
+	// MissingFromKeyStoreBackup lists the keys of the current keystore which
+	// the backup doesn't have, and restoring it would lose
+	MissingFromKeyStoreBackup() ([]string, error)
+	// RestoreKeyStoreBackup puts the backed up keystore back in place
+	RestoreKeyStoreBackup() error
+	// DropKeyStoreBackup removes the backed up keystore
+	DropKeyStoreBackup() error
+}
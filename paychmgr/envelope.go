@@ -0,0 +1,149 @@
+package paychmgr
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+
+	"github.com/filecoin-project/lotus/chain/actors/builtin/paych"
+	"github.com/filecoin-project/lotus/chain/types"
+)
+
+// VoucherEnvelopeVersion is the version of the envelope format
+const VoucherEnvelopeVersion = 1
+
+// compactPrefix starts the compact encoding of envelopes, which only uses
+// characters of the QR code alphanumeric mode
+const compactPrefix = "FILVOUCHER1:"
+
+var compactEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+var (
+	ErrEnvelopeNetwork = xerrors.New("voucher envelope is for another network")
+	ErrEnvelopeChannel = xerrors.New("voucher envelope is for another channel")
+	ErrEnvelopeInvalid = xerrors.New("invalid voucher envelope")
+)
+
+// VoucherEnvelope wraps a voucher exchanged with a counterparty with the
+// context needed to tell which channel it's for. Lane, Nonce and Amount
+// repeat the fields of the voucher for readers, and must match them.
+type VoucherEnvelope struct {
+	Version int
+	Network string
+	Channel address.Address
+	Lane    uint64
+	Nonce   uint64
+	Amount  types.BigInt
+	Memo    string `json:",omitempty"`
+	// Voucher is the voucher encoded as by `paych voucher create`
+	Voucher string
+}
+
+// NewVoucherEnvelope wraps the voucher for the network
+func NewVoucherEnvelope(network string, sv *paych.SignedVoucher, memo string) (*VoucherEnvelope, error) {
+	buf := new(bytes.Buffer)
+	if err := sv.MarshalCBOR(buf); err != nil {
+		return nil, err
+	}
+
+	return &VoucherEnvelope{
+		Version: VoucherEnvelopeVersion,
+		Network: network,
+		Channel: sv.ChannelAddr,
+		Lane:    sv.Lane,
+		Nonce:   sv.Nonce,
+		Amount:  sv.Amount,
+		Memo:    memo,
+		Voucher: base64.RawURLEncoding.EncodeToString(buf.Bytes()),
+	}, nil
+}
+
+// JSON returns the indented JSON encoding of the envelope
+func (e *VoucherEnvelope) JSON() (string, error) {
+	b, err := json.MarshalIndent(e, "", "  ")
+	return string(b), err
+}
+
+// Compact returns an encoding of the envelope fitting QR codes in
+// alphanumeric mode
+func (e *VoucherEnvelope) Compact() (string, error) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	return compactPrefix + compactEncoding.EncodeToString(b), nil
+}
+
+// SignedVoucher decodes the voucher of the envelope, checking it matches the
+// fields of the envelope
+func (e *VoucherEnvelope) SignedVoucher() (*paych.SignedVoucher, error) {
+	if e.Version != VoucherEnvelopeVersion {
+		return nil, xerrors.Errorf("%w: unsupported version %d", ErrEnvelopeInvalid, e.Version)
+	}
+
+	sv, err := paych.DecodeSignedVoucher(e.Voucher)
+	if err != nil {
+		return nil, xerrors.Errorf("%w: decoding voucher: %s", ErrEnvelopeInvalid, err)
+	}
+
+	switch {
+	case sv.ChannelAddr != e.Channel:
+		return nil, xerrors.Errorf("%w: envelope channel %s doesn't match voucher channel %s", ErrEnvelopeInvalid, e.Channel, sv.ChannelAddr)
+	case sv.Lane != e.Lane:
+		return nil, xerrors.Errorf("%w: envelope lane %d doesn't match voucher lane %d", ErrEnvelopeInvalid, e.Lane, sv.Lane)
+	case sv.Nonce != e.Nonce:
+		return nil, xerrors.Errorf("%w: envelope nonce %d doesn't match voucher nonce %d", ErrEnvelopeInvalid, e.Nonce, sv.Nonce)
+	case e.Amount.Nil() || !e.Amount.Equals(sv.Amount):
+		return nil, xerrors.Errorf("%w: envelope amount %s doesn't match voucher amount %s", ErrEnvelopeInvalid, e.Amount, sv.Amount)
+	}
+	return sv, nil
+}
+
+// Validate checks the envelope is for the network and, unless ch is
+// undefined, for the channel
+func (e *VoucherEnvelope) Validate(network string, ch address.Address) error {
+	if e.Network != network {
+		return xerrors.Errorf("%w: envelope is for network %q, this node is on %q", ErrEnvelopeNetwork, e.Network, network)
+	}
+	if ch != address.Undef && e.Channel != ch {
+		return xerrors.Errorf("%w: envelope is for channel %s, not %s", ErrEnvelopeChannel, e.Channel, ch)
+	}
+	return nil
+}
+
+// ParseVoucher decodes a voucher given either in the raw form, as printed by
+// `paych voucher create`, or in an envelope, as JSON or in the compact form.
+// The envelope is nil for raw vouchers.
+func ParseVoucher(s string) (*paych.SignedVoucher, *VoucherEnvelope, error) {
+	s = strings.TrimSpace(s)
+
+	var b []byte
+	switch {
+	case strings.HasPrefix(s, "{"):
+		b = []byte(s)
+	case strings.HasPrefix(strings.ToUpper(s), compactPrefix):
+		var err error
+		if b, err = compactEncoding.DecodeString(strings.ToUpper(s[len(compactPrefix):])); err != nil {
+			return nil, nil, xerrors.Errorf("%w: decoding compact envelope: %s", ErrEnvelopeInvalid, err)
+		}
+	default:
+		sv, err := paych.DecodeSignedVoucher(s)
+		return sv, nil, err
+	}
+
+	var e VoucherEnvelope
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, nil, xerrors.Errorf("%w: %s", ErrEnvelopeInvalid, err)
+	}
+	sv, err := e.SignedVoucher()
+	if err != nil {
+		return nil, nil, err
+	}
+	return sv, &e, nil
+}
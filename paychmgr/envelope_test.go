@@ -0,0 +1,90 @@
+package paychmgr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/crypto"
+	tutils "github.com/filecoin-project/specs-actors/v2/support/testing"
+
+	"github.com/filecoin-project/lotus/chain/actors/builtin/paych"
+)
+
+func TestVoucherEnvelope(t *testing.T) {
+	ch := tutils.NewIDAddr(t, 100)
+	other := tutils.NewIDAddr(t, 101)
+	sv := &paych.SignedVoucher{
+		ChannelAddr: ch,
+		Lane:        2,
+		Nonce:       5,
+		Amount:      big.NewInt(1000),
+		Signature:   &crypto.Signature{Type: crypto.SigTypeSecp256k1, Data: []byte("sig")},
+	}
+
+	env, err := NewVoucherEnvelope("testnetnet", sv, "invoice 42")
+	require.NoError(t, err)
+
+	js, err := env.JSON()
+	require.NoError(t, err)
+	compact, err := env.Compact()
+	require.NoError(t, err)
+	require.Equal(t, strings.ToUpper(compact), compact)
+
+	// the raw form, the JSON envelope and the compact one all decode
+	for _, s := range []string{env.Voucher, js, compact, strings.ToLower(compact)} {
+		got, genv, err := ParseVoucher(s)
+		require.NoError(t, err)
+		require.Equal(t, sv.Amount, got.Amount)
+		require.Equal(t, sv.Lane, got.Lane)
+		if s == env.Voucher {
+			require.Nil(t, genv)
+			continue
+		}
+		require.Equal(t, "invoice 42", genv.Memo)
+		require.NoError(t, genv.Validate("testnetnet", ch))
+		require.NoError(t, genv.Validate("testnetnet", address.Undef))
+	}
+
+	// wrong network
+	err = env.Validate("mainnet", ch)
+	require.True(t, xerrors.Is(err, ErrEnvelopeNetwork))
+	require.Contains(t, err.Error(), `envelope is for network "testnetnet", this node is on "mainnet"`)
+
+	// channel mismatch with the channel given
+	err = env.Validate("testnetnet", other)
+	require.True(t, xerrors.Is(err, ErrEnvelopeChannel))
+	require.Contains(t, err.Error(), "envelope is for channel "+ch.String()+", not "+other.String())
+
+	// envelope fields disagreeing with the voucher
+	bad := *env
+	bad.Channel = other
+	_, err = bad.SignedVoucher()
+	require.True(t, xerrors.Is(err, ErrEnvelopeInvalid))
+	require.Contains(t, err.Error(), "envelope channel "+other.String()+" doesn't match voucher channel "+ch.String())
+
+	bad = *env
+	bad.Amount = big.NewInt(2000)
+	_, err = bad.SignedVoucher()
+	require.Contains(t, err.Error(), "envelope amount 2000 doesn't match voucher amount 1000")
+
+	bad = *env
+	bad.Lane = 3
+	js, err = bad.JSON()
+	require.NoError(t, err)
+	_, _, err = ParseVoucher(js)
+	require.True(t, xerrors.Is(err, ErrEnvelopeInvalid))
+	require.Contains(t, err.Error(), "envelope lane 3 doesn't match voucher lane 2")
+
+	bad = *env
+	bad.Version = 2
+	_, err = bad.SignedVoucher()
+	require.Contains(t, err.Error(), "unsupported version 2")
+
+	_, _, err = ParseVoucher("FILVOUCHER1:not base32!")
+	require.True(t, xerrors.Is(err, ErrEnvelopeInvalid))
+}
@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"sort"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/lotus/chain/messagepool"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/node/config"
+)
+
+// multiplierPrecision is the precision premium multipliers are applied with
+const multiplierPrecision = 1000
+
+// FeeEscalator picks the gas premium of a message by how many epochs are left
+// until it must land on chain: the premium the node estimates, an economical
+// share of it well before the message is due, and a growing multiple of it as
+// the epoch approaches.
+type FeeEscalator struct {
+	cfg config.FeeEscalationConfig
+}
+
+func NewFeeEscalator(cfg config.FeeEscalationConfig) *FeeEscalator {
+	curve := append([]config.FeeEscalationStep{}, cfg.Curve...)
+	sort.Slice(curve, func(i, j int) bool {
+		return curve[i].RemainingEpochs < curve[j].RemainingEpochs
+	})
+	cfg.Curve = curve
+
+	return &FeeEscalator{cfg: cfg}
+}
+
+// Enabled returns whether messages are escalated at all
+func (fe *FeeEscalator) Enabled() bool {
+	return fe != nil && fe.cfg.Enable
+}
+
+// Multiplier returns the multiplier of the estimated premium for a message
+// with the epochs left
+func (fe *FeeEscalator) Multiplier(remaining abi.ChainEpoch) float64 {
+	for _, st := range fe.cfg.Curve {
+		if int64(remaining) <= st.RemainingEpochs {
+			return st.Multiplier
+		}
+	}
+	return 1
+}
+
+// Economical returns the premium of a message which isn't urgent yet, with
+// the epochs left, and whether it's economical
+func (fe *FeeEscalator) Economical(estimated abi.TokenAmount, remaining abi.ChainEpoch) (abi.TokenAmount, bool) {
+	if !fe.Enabled() || int64(remaining) <= fe.cfg.EconomicalEpochs || fe.cfg.EconomicalMultiplier <= 0 {
+		return estimated, false
+	}
+	return fe.capPremium(multiplyPremium(estimated, fe.cfg.EconomicalMultiplier)), true
+}
+
+// Escalate returns the gas premium and fee cap to replace a message which
+// hasn't landed with, remaining epochs before it must, given the premium
+// estimated when it was sent and the current base fee. The premium follows
+// the curve, and the fee cap is raised when the base fee spikes above what
+// the message can pay, within maxFee for the whole message. It returns false
+// if the message shouldn't, or can't within the caps, be replaced.
+func (fe *FeeEscalator) Escalate(msg *types.Message, estimated, baseFee, maxFee abi.TokenAmount, remaining abi.ChainEpoch) (abi.TokenAmount, abi.TokenAmount, bool) {
+	if !fe.Enabled() || remaining <= 0 {
+		return msg.GasPremium, msg.GasFeeCap, false
+	}
+
+	premium := msg.GasPremium
+	if target := fe.capPremium(multiplyPremium(estimated, fe.Multiplier(remaining))); target.GreaterThan(premium) {
+		premium = target
+	}
+
+	feeCap := msg.GasFeeCap
+	if big.Add(baseFee, premium).GreaterThan(feeCap) {
+		// leave room for the base fee to keep rising until the message lands
+		feeCap = big.Add(big.Mul(baseFee, big.NewInt(2)), premium)
+	}
+	if maxFee.Sign() > 0 && msg.GasLimit > 0 {
+		if maxFeeCap := big.Div(maxFee, big.NewInt(msg.GasLimit)); feeCap.GreaterThan(maxFeeCap) {
+			feeCap = big.Max(maxFeeCap, msg.GasFeeCap)
+		}
+	}
+
+	if premium.Equals(msg.GasPremium) && feeCap.Equals(msg.GasFeeCap) {
+		return msg.GasPremium, msg.GasFeeCap, false
+	}
+
+	// the mpool only replaces messages paying enough more premium
+	minPremium := messagepool.ComputeMinRBF(msg.GasPremium)
+	if premium.LessThan(minPremium) {
+		premium = minPremium
+	}
+	if premium.GreaterThan(fe.maxPremium()) || premium.GreaterThan(feeCap) {
+		return msg.GasPremium, msg.GasFeeCap, false
+	}
+	return premium, feeCap, true
+}
+
+func (fe *FeeEscalator) maxPremium() abi.TokenAmount {
+	if fe.cfg.MaxGasPremium.Int == nil {
+		return big.Zero()
+	}
+	return abi.TokenAmount(fe.cfg.MaxGasPremium)
+}
+
+func (fe *FeeEscalator) capPremium(premium abi.TokenAmount) abi.TokenAmount {
+	return big.Min(premium, fe.maxPremium())
+}
+
+func multiplyPremium(premium abi.TokenAmount, multiplier float64) abi.TokenAmount {
+	return big.Div(big.Mul(premium, big.NewInt(int64(multiplier*multiplierPrecision))), big.NewInt(multiplierPrecision))
+}
@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/lotus/chain/messagepool"
+	"github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/node/config"
+)
+
+func testFeeEscalator(maxPremium int64) *FeeEscalator {
+	return NewFeeEscalator(config.FeeEscalationConfig{
+		Enable: true,
+		// out of order, the escalator sorts the steps
+		Curve: []config.FeeEscalationStep{
+			{RemainingEpochs: 15, Multiplier: 3},
+			{RemainingEpochs: 30, Multiplier: 1.5},
+			{RemainingEpochs: 5, Multiplier: 6},
+		},
+		EconomicalEpochs:     30,
+		EconomicalMultiplier: 0.5,
+		MaxGasPremium:        types.FIL(big.NewInt(maxPremium)),
+	})
+}
+
+func testEscalatedMessage() *types.Message {
+	return &types.Message{
+		GasLimit:   10_000_000,
+		GasPremium: big.NewInt(100_000),
+		GasFeeCap:  big.NewInt(200_100_000),
+	}
+}
+
+func TestFeeEscalatorCurve(t *testing.T) {
+	fe := testFeeEscalator(1_000_000)
+	estimated := big.NewInt(100_000)
+	baseFee := big.NewInt(100_000_000)
+	maxFee := abi.TokenAmount(types.MustParseFIL("5"))
+
+	for _, tc := range []struct {
+		remaining abi.ChainEpoch
+		premium   int64
+		escalated bool
+	}{
+		{remaining: 60, premium: 100_000},
+		{remaining: 31, premium: 100_000},
+		{remaining: 30, premium: 150_000, escalated: true},
+		{remaining: 15, premium: 300_000, escalated: true},
+		{remaining: 1, premium: 600_000, escalated: true},
+		{remaining: 0, premium: 100_000},
+	} {
+		premium, feeCap, ok := fe.Escalate(testEscalatedMessage(), estimated, baseFee, maxFee, tc.remaining)
+		require.Equal(t, tc.escalated, ok, "remaining %d", tc.remaining)
+		require.Equal(t, big.NewInt(tc.premium), premium, "remaining %d", tc.remaining)
+		require.True(t, feeCap.GreaterThanEqual(big.Add(baseFee, premium)), "remaining %d", tc.remaining)
+	}
+}
+
+func TestFeeEscalatorBaseFeeSpike(t *testing.T) {
+	fe := testFeeEscalator(1_000_000)
+	estimated := big.NewInt(100_000)
+	maxFee := abi.TokenAmount(types.MustParseFIL("5"))
+
+	// the base fee spikes tenfold well before the curve escalates, the fee
+	// cap is raised with the smallest premium the mpool replaces with
+	msg := testEscalatedMessage()
+	premium, feeCap, ok := fe.Escalate(msg, estimated, big.NewInt(1_000_000_000), maxFee, 50)
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(125_001), premium)
+	require.Equal(t, big.NewInt(2_000_100_000), feeCap)
+
+	// a fee cap covering the base fee isn't replaced
+	msg.GasPremium, msg.GasFeeCap = premium, feeCap
+	_, _, ok = fe.Escalate(msg, estimated, big.NewInt(900_000_000), maxFee, 49)
+	require.False(t, ok)
+
+	// the fee cap is bounded by the max fee for the whole message
+	msg = testEscalatedMessage()
+	smallMaxFee := big.Mul(big.NewInt(300_000_000), big.NewInt(msg.GasLimit))
+	premium, feeCap, ok = fe.Escalate(msg, estimated, big.NewInt(1_000_000_000), smallMaxFee, 50)
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(125_001), premium)
+	require.Equal(t, big.NewInt(300_000_000), feeCap)
+}
+
+func TestFeeEscalatorSimulatedEpochs(t *testing.T) {
+	maxPremium := big.NewInt(400_000)
+	fe := testFeeEscalator(400_000)
+	estimated := big.NewInt(100_000)
+	maxFee := abi.TokenAmount(types.MustParseFIL("5"))
+
+	// base fee spiking from 0.1 nFIL to 2 nFIL and back down while the
+	// proving window closes
+	baseFee := func(remaining abi.ChainEpoch) abi.TokenAmount {
+		switch {
+		case remaining <= 40 && remaining > 20:
+			return big.NewInt(2_000_000_000)
+		default:
+			return big.NewInt(100_000_000)
+		}
+	}
+
+	msg := testEscalatedMessage()
+	var replaced int
+	for remaining := abi.ChainEpoch(60); remaining > 0; remaining-- {
+		bf := baseFee(remaining)
+		premium, feeCap, ok := fe.Escalate(msg, estimated, bf, maxFee, remaining)
+		if !ok {
+			continue
+		}
+		replaced++
+
+		require.True(t, premium.GreaterThanEqual(messagepool.ComputeMinRBF(msg.GasPremium)), "remaining %d", remaining)
+		require.True(t, premium.LessThanEqual(maxPremium), "remaining %d", remaining)
+		require.True(t, feeCap.GreaterThanEqual(big.Add(bf, premium)), "remaining %d", remaining)
+		require.True(t, feeCap.GreaterThanEqual(msg.GasFeeCap), "remaining %d", remaining)
+
+		msg.GasPremium, msg.GasFeeCap = premium, feeCap
+	}
+
+	// the spike, the 1.5x and 3x steps, then the 6x step capped
+	require.Equal(t, 4, replaced)
+	require.Equal(t, maxPremium, msg.GasPremium)
+}
+
+func TestFeeEscalatorEconomical(t *testing.T) {
+	fe := testFeeEscalator(1_000_000)
+	estimated := big.NewInt(100_000)
+
+	premium, ok := fe.Economical(estimated, 50)
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(50_000), premium)
+
+	premium, ok = fe.Economical(estimated, 30)
+	require.False(t, ok)
+	require.Equal(t, estimated, premium)
+
+	var disabled *FeeEscalator
+	_, ok = disabled.Economical(estimated, 50)
+	require.False(t, ok)
+	_, _, ok = disabled.Escalate(testEscalatedMessage(), estimated, big.NewInt(1_000_000_000), big.Zero(), 1)
+	require.False(t, ok)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/filecoin-project/go-state-types/crypto"
 	sectorstorage "github.com/filecoin-project/lotus/extern/sector-storage"
 	"github.com/filecoin-project/lotus/extern/sector-storage/ffiwrapper"
+	proof5 "github.com/filecoin-project/specs-actors/v5/actors/runtime/proof"
 	"github.com/filecoin-project/specs-storage/storage"
 
 	"github.com/filecoin-project/lotus/api"
@@ -51,6 +52,7 @@ type Miner struct {
 	verif   ffiwrapper.Verifier
 	prover  ffiwrapper.Prover
 	addrSel *AddressSelector
+	wdpost  *WindowPoStScheduler
 
 	maddr address.Address
 
@@ -96,6 +98,7 @@ type storageMinerApi interface {
 	StateLookupID(context.Context, address.Address, types.TipSetKey) (address.Address, error)
 
 	MpoolPushMessage(context.Context, *types.Message, *api.MessageSendSpec) (*types.SignedMessage, error)
+	MpoolPush(context.Context, *types.SignedMessage) (cid.Cid, error)
 
 	GasEstimateMessageGas(context.Context, *types.Message, *api.MessageSendSpec, types.TipSetKey) (*types.Message, error)
 	GasEstimateFeeCap(context.Context, *types.Message, int64, types.TipSetKey) (types.BigInt, error)
@@ -113,11 +116,12 @@ type storageMinerApi interface {
 	ChainGetTipSet(ctx context.Context, key types.TipSetKey) (*types.TipSet, error)
 
 	WalletSign(context.Context, address.Address, []byte) (*crypto.Signature, error)
+	WalletSignMessage(context.Context, address.Address, *types.Message) (*types.SignedMessage, error)
 	WalletBalance(context.Context, address.Address) (types.BigInt, error)
 	WalletHas(context.Context, address.Address) (bool, error)
 }
 
-func NewMiner(api storageMinerApi, maddr address.Address, h host.Host, ds datastore.Batching, sealer sectorstorage.SectorManager, sc sealing.SectorIDCounter, verif ffiwrapper.Verifier, prover ffiwrapper.Prover, gsd dtypes.GetSealingConfigFunc, feeCfg config.MinerFeeConfig, journal journal.Journal, as *AddressSelector) (*Miner, error) {
+func NewMiner(api storageMinerApi, maddr address.Address, h host.Host, ds datastore.Batching, sealer sectorstorage.SectorManager, sc sealing.SectorIDCounter, verif ffiwrapper.Verifier, prover ffiwrapper.Prover, gsd dtypes.GetSealingConfigFunc, feeCfg config.MinerFeeConfig, journal journal.Journal, as *AddressSelector, wdpost *WindowPoStScheduler) (*Miner, error) {
 	m := &Miner{
 		api:     api,
 		feeCfg:  feeCfg,
@@ -128,6 +132,7 @@ func NewMiner(api storageMinerApi, maddr address.Address, h host.Host, ds datast
 		verif:   verif,
 		prover:  prover,
 		addrSel: as,
+		wdpost:  wdpost,
 
 		maddr:          maddr,
 		getSealConfig:  gsd,
@@ -138,6 +143,34 @@ func NewMiner(api storageMinerApi, maddr address.Address, h host.Host, ds datast
 	return m, nil
 }
 
+// WdPoStStatus returns the progress of proving the current deadline, nil
+// before the first proof
+func (m *Miner) WdPoStStatus() *api.WdPoStStatus {
+	if m.wdpost == nil {
+		return nil
+	}
+	return m.wdpost.WdPoStStatus()
+}
+
+// WdPoStProverStatus returns the prover computing the WindowPoSt proofs, and
+// its recent requests
+func (m *Miner) WdPoStProverStatus() api.WdPoStProverStatus {
+	if m.wdpost == nil {
+		return api.WdPoStProverStatus{}
+	}
+	return m.wdpost.ProverStatus()
+}
+
+// SetWdPoStParallelBatches changes how many batches of partitions of a
+// deadline are proven at once, from the next deadline on
+func (m *Miner) SetWdPoStParallelBatches(n int) error {
+	if m.wdpost == nil {
+		return xerrors.Errorf("WindowPoSt isn't running")
+	}
+	m.wdpost.SetParallelBatches(n)
+	return nil
+}
+
 func (m *Miner) Run(ctx context.Context) error {
 	if err := m.runPreflightChecks(ctx); err != nil {
 		return xerrors.Errorf("miner preflight checks failed: %w", err)
@@ -259,3 +292,17 @@ func (wpp *StorageWpp) ComputeProof(ctx context.Context, ssi []builtin.SectorInf
 	log.Infof("GenerateWinningPoSt took %s", time.Since(start))
 	return proof, nil
 }
+
+// VerifyProof verifies a WinningPoSt computed with ComputeProof
+func (wpp *StorageWpp) VerifyProof(ctx context.Context, ssi []builtin.SectorInfo, rand abi.PoStRandomness, proofs []builtin.PoStProof) (bool, error) {
+	if build.InsecurePoStValidation {
+		return true, nil
+	}
+
+	return wpp.verifier.VerifyWinningPoSt(ctx, proof5.WinningPoStVerifyInfo{
+		Randomness:        rand,
+		Proofs:            proofs,
+		ChallengedSectors: ssi,
+		Prover:            wpp.miner,
+	})
+}
@@ -36,6 +36,10 @@ func (m *Miner) GetSectorInfo(sid abi.SectorNumber) (sealing.SectorInfo, error)
 	return m.sealing.GetSectorInfo(sid)
 }
 
+func (m *Miner) HasSector(sid abi.SectorNumber) (bool, error) {
+	return m.sealing.HasSector(sid)
+}
+
 func (m *Miner) PledgeSector(ctx context.Context) (storage.SectorRef, error) {
 	return m.sealing.PledgeSector(ctx)
 }
@@ -48,6 +52,10 @@ func (m *Miner) RemoveSector(ctx context.Context, id abi.SectorNumber) error {
 	return m.sealing.Remove(ctx, id)
 }
 
+func (m *Miner) ImportSector(ctx context.Context, si sealing.SectorInfo) error {
+	return m.sealing.ImportSector(ctx, si)
+}
+
 func (m *Miner) TerminateSector(ctx context.Context, id abi.SectorNumber) error {
 	return m.sealing.Terminate(ctx, id)
 }
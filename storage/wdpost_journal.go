@@ -33,6 +33,8 @@ const (
 	evtTypeWdPoStProofs
 	evtTypeWdPoStRecoveries
 	evtTypeWdPoStFaults
+	evtTypeWdPoStSkipped
+	evtTypeWdPoStFeeEscalated
 )
 
 // evtCommon is a common set of attributes for Windowed PoSt journal events.
@@ -73,3 +75,28 @@ type WdPoStFaultsProcessedEvt struct {
 	Declarations []miner.FaultDeclaration
 	MessageCID   cid.Cid `json:",omitempty"`
 }
+
+// WdPoStSectorSkippedEvt is the journal event that gets recorded when a
+// sector is left out of a Windowed PoSt proof.
+type WdPoStSectorSkippedEvt struct {
+	evtCommon
+	Partition uint64
+	Sector    abi.SectorNumber
+	Reason    string
+}
+
+// WdPoStFeeEscalatedEvt is the journal event that gets recorded when a
+// message which hasn't landed is replaced with one paying more, as the epoch
+// it must land by approaches.
+type WdPoStFeeEscalatedEvt struct {
+	evtCommon
+	Method          abi.MethodNum
+	OldMessageCID   cid.Cid
+	NewMessageCID   cid.Cid
+	OldGasPremium   abi.TokenAmount
+	NewGasPremium   abi.TokenAmount
+	OldGasFeeCap    abi.TokenAmount
+	NewGasFeeCap    abi.TokenAmount
+	BaseFee         abi.TokenAmount
+	RemainingEpochs abi.ChainEpoch
+}
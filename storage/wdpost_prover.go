@@ -0,0 +1,277 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	proof2 "github.com/filecoin-project/specs-actors/v2/actors/runtime/proof"
+
+	"github.com/filecoin-project/lotus/api"
+	"github.com/filecoin-project/lotus/build"
+	"github.com/filecoin-project/lotus/node/config"
+)
+
+// WindowPoStProver computes the WindowPoSt proofs of the challenged sectors.
+// Sectors which can't be proven are returned with an error, for the proofs
+// to be computed again without them.
+type WindowPoStProver interface {
+	GenerateWindowPoSt(ctx context.Context, minerID abi.ActorID, sectorInfo []proof2.SectorInfo, randomness abi.PoStRandomness) ([]proof2.PoStProof, []abi.SectorID, error)
+}
+
+// proverRequestsKept is the number of recent prover requests kept for the
+// prover status
+const proverRequestsKept = 32
+
+// proverRequests are the recent requests of a prover
+type proverRequests struct {
+	lk   sync.Mutex
+	reqs []api.WdPoStProverRequest
+}
+
+func (r *proverRequests) record(prover string, start time.Time, sectors int, err error) {
+	req := api.WdPoStProverRequest{
+		Prover:  prover,
+		Start:   start,
+		Took:    build.Clock.Since(start),
+		Sectors: sectors,
+	}
+	if err != nil {
+		req.Error = err.Error()
+	}
+
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	r.reqs = append(r.reqs, req)
+	if len(r.reqs) > proverRequestsKept {
+		r.reqs = r.reqs[len(r.reqs)-proverRequestsKept:]
+	}
+}
+
+func (r *proverRequests) list() []api.WdPoStProverRequest {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	return append([]api.WdPoStProverRequest{}, r.reqs...)
+}
+
+// LocalWindowPoStProver computes the proofs with the workers of the miner
+type LocalWindowPoStProver struct {
+	prover WindowPoStProver
+	reqs   proverRequests
+}
+
+func NewLocalWindowPoStProver(prover WindowPoStProver) *LocalWindowPoStProver {
+	return &LocalWindowPoStProver{prover: prover}
+}
+
+func (p *LocalWindowPoStProver) GenerateWindowPoSt(ctx context.Context, minerID abi.ActorID, sectorInfo []proof2.SectorInfo, randomness abi.PoStRandomness) ([]proof2.PoStProof, []abi.SectorID, error) {
+	start := build.Clock.Now()
+	proofs, skipped, err := p.prover.GenerateWindowPoSt(ctx, minerID, sectorInfo, randomness)
+	p.reqs.record("local", start, len(sectorInfo), err)
+	return proofs, skipped, err
+}
+
+func (p *LocalWindowPoStProver) ProverStatus() api.WdPoStProverStatus {
+	return api.WdPoStProverStatus{Prover: "local", Requests: p.reqs.list()}
+}
+
+// RemoteWindowPoStRequest is the body of the requests to remote provers. The
+// sectors are streamed, a remote prover reads them from the storage it shares
+// with the miner.
+type RemoteWindowPoStRequest struct {
+	Miner      abi.ActorID
+	Randomness abi.PoStRandomness
+	Sectors    []proof2.SectorInfo
+}
+
+// RemoteWindowPoStResponse is the body of the responses of remote provers.
+// Sectors which couldn't be proven are listed in Skipped, with the Error.
+type RemoteWindowPoStResponse struct {
+	Proofs  []proof2.PoStProof
+	Skipped []abi.SectorID `json:",omitempty"`
+	Error   string         `json:",omitempty"`
+}
+
+// RemoteWindowPoStProver computes the proofs with the remote provers, and
+// locally when they all fail with enough time left in the proving window
+type RemoteWindowPoStProver struct {
+	endpoints    []string
+	token        string
+	timeout      time.Duration
+	fallbackTime time.Duration
+	local        WindowPoStProver
+	client       *http.Client
+
+	reqs proverRequests
+}
+
+func NewRemoteWindowPoStProver(cfg config.ProvingConfig, local WindowPoStProver) *RemoteWindowPoStProver {
+	return &RemoteWindowPoStProver{
+		endpoints:    cfg.RemoteProvers,
+		token:        cfg.RemoteProverToken,
+		timeout:      time.Duration(cfg.RemoteProverTimeout),
+		fallbackTime: time.Duration(cfg.LocalFallbackTime),
+		local:        local,
+		client:       &http.Client{},
+	}
+}
+
+// NewWindowPoStProver returns the prover the config sets, computing the
+// proofs with local if no remote prover is configured
+func NewWindowPoStProver(cfg config.ProvingConfig, local WindowPoStProver) WindowPoStProver {
+	if len(cfg.RemoteProvers) == 0 {
+		return NewLocalWindowPoStProver(local)
+	}
+	return NewRemoteWindowPoStProver(cfg, local)
+}
+
+func (p *RemoteWindowPoStProver) GenerateWindowPoSt(ctx context.Context, minerID abi.ActorID, sectorInfo []proof2.SectorInfo, randomness abi.PoStRandomness) ([]proof2.PoStProof, []abi.SectorID, error) {
+	var remoteErr error
+	for _, endpoint := range p.endpoints {
+		start := build.Clock.Now()
+		resp, err := p.request(ctx, endpoint, RemoteWindowPoStRequest{Miner: minerID, Randomness: randomness, Sectors: sectorInfo})
+		if err == nil && resp.Error != "" {
+			err = xerrors.New(resp.Error)
+		}
+		p.reqs.record(endpoint, start, len(sectorInfo), err)
+
+		switch {
+		case err == nil:
+			return resp.Proofs, nil, nil
+		case resp != nil && len(resp.Skipped) > 0:
+			// the prover worked, the sectors didn't
+			return nil, resp.Skipped, err
+		}
+
+		log.Warnw("remote window post prover failed", "prover", endpoint, "error", err)
+		remoteErr = xerrors.Errorf("remote prover %s: %w", endpoint, err)
+		if ctx.Err() != nil {
+			return nil, nil, remoteErr
+		}
+	}
+
+	if closes, ok := provingDeadline(ctx); ok {
+		if left := build.Clock.Until(closes); left < p.fallbackTime {
+			return nil, nil, xerrors.Errorf("not computing the proofs locally, %s left before the proving window closes is less than the %s it takes: %w",
+				left.Truncate(time.Second), p.fallbackTime, remoteErr)
+		}
+	}
+
+	log.Warnw("computing window post locally, the remote provers failed", "error", remoteErr)
+	start := build.Clock.Now()
+	proofs, skipped, err := p.local.GenerateWindowPoSt(ctx, minerID, sectorInfo, randomness)
+	p.reqs.record("local", start, len(sectorInfo), err)
+	return proofs, skipped, err
+}
+
+// request sends the request to the remote prover, bounded by the timeout and
+// the close of the proving window. The body is streamed as it is encoded, the
+// challenged sectors of a batch of partitions adding up.
+func (p *RemoteWindowPoStProver) request(ctx context.Context, endpoint string, req RemoteWindowPoStRequest) (*RemoteWindowPoStResponse, error) {
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+	if closes, ok := provingDeadline(ctx); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, closes)
+		defer cancel()
+	}
+
+	body, pw := io.Pipe()
+	go func() {
+		w := bufio.NewWriter(pw)
+		err := encodeRemoteWindowPoStRequest(w, req)
+		if err == nil {
+			err = w.Flush()
+		}
+		_ = pw.CloseWithError(err)
+	}()
+	defer body.Close() //nolint:errcheck
+
+	hreq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return nil, xerrors.Errorf("creating request: %w", err)
+	}
+	hreq.Header.Set("Content-Type", "application/json")
+	if p.token != "" {
+		hreq.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	hresp, err := p.client.Do(hreq)
+	if err != nil {
+		return nil, err
+	}
+	defer hresp.Body.Close() //nolint:errcheck
+
+	if hresp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(hresp.Body, 1024))
+		return nil, xerrors.Errorf("status %s: %s", hresp.Status, bytes.TrimSpace(msg))
+	}
+
+	var resp RemoteWindowPoStResponse
+	if err := json.NewDecoder(hresp.Body).Decode(&resp); err != nil {
+		return nil, xerrors.Errorf("decoding response: %w", err)
+	}
+	return &resp, nil
+}
+
+// encodeRemoteWindowPoStRequest writes the request as JSON, a sector at a
+// time
+func encodeRemoteWindowPoStRequest(w io.Writer, req RemoteWindowPoStRequest) error {
+	head, err := json.Marshal(struct {
+		Miner      abi.ActorID
+		Randomness abi.PoStRandomness
+	}{req.Miner, req.Randomness})
+	if err != nil {
+		return err
+	}
+	// reopen the object to add the sectors
+	if _, err := w.Write(append(head[:len(head)-1], []byte(`,"Sectors":[`)...)); err != nil {
+		return err
+	}
+	for i, si := range req.Sectors {
+		if i > 0 {
+			if _, err := w.Write([]byte(",")); err != nil {
+				return err
+			}
+		}
+		b, err := json.Marshal(si)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	_, err = w.Write([]byte("]}"))
+	return err
+}
+
+func (p *RemoteWindowPoStProver) ProverStatus() api.WdPoStProverStatus {
+	return api.WdPoStProverStatus{Prover: "remote " + strings.Join(p.endpoints, ", ") + ", falling back to local", Requests: p.reqs.list()}
+}
+
+type provingDeadlineKey struct{}
+
+// withProvingDeadline tells the prover when the proving window closes, the
+// proofs being useless past it
+func withProvingDeadline(ctx context.Context, closes time.Time) context.Context {
+	return context.WithValue(ctx, provingDeadlineKey{}, closes)
+}
+
+func provingDeadline(ctx context.Context) (time.Time, bool) {
+	closes, ok := ctx.Value(provingDeadlineKey{}).(time.Time)
+	return closes, ok
+}
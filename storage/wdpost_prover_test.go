@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	proof2 "github.com/filecoin-project/specs-actors/v2/actors/runtime/proof"
+
+	"github.com/filecoin-project/lotus/node/config"
+)
+
+type countingProver struct {
+	mockProver
+	calls int
+}
+
+func (p *countingProver) GenerateWindowPoSt(ctx context.Context, aid abi.ActorID, sis []proof2.SectorInfo, pr abi.PoStRandomness) ([]proof2.PoStProof, []abi.SectorID, error) {
+	p.calls++
+	return p.mockProver.GenerateWindowPoSt(ctx, aid, sis, pr)
+}
+
+func TestRemoteWindowPoStProver(t *testing.T) {
+	sectors := []proof2.SectorInfo{
+		{SealProof: abi.RegisteredSealProof_StackedDrg2KiBV1, SectorNumber: 1},
+		{SealProof: abi.RegisteredSealProof_StackedDrg2KiBV1, SectorNumber: 2},
+	}
+	remoteProof := proof2.PoStProof{PoStProof: abi.RegisteredPoStProof_StackedDrgWindow2KiBV1, ProofBytes: []byte("remote")}
+
+	var resp RemoteWindowPoStResponse
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var req RemoteWindowPoStRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, abi.ActorID(1000), req.Miner)
+		require.Equal(t, sectors, req.Sectors)
+
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	local := &countingProver{}
+	p := NewRemoteWindowPoStProver(config.ProvingConfig{
+		RemoteProvers:       []string{srv.URL},
+		RemoteProverToken:   "secret",
+		RemoteProverTimeout: config.Duration(time.Minute),
+		LocalFallbackTime:   config.Duration(10 * time.Minute),
+	}, local)
+	ctx := withProvingDeadline(context.Background(), time.Now().Add(30*time.Minute))
+
+	// proven remotely
+	resp = RemoteWindowPoStResponse{Proofs: []proof2.PoStProof{remoteProof}}
+	proofs, skipped, err := p.GenerateWindowPoSt(ctx, 1000, sectors, abi.PoStRandomness{1})
+	require.NoError(t, err)
+	require.Nil(t, skipped)
+	require.Equal(t, []proof2.PoStProof{remoteProof}, proofs)
+	require.Equal(t, 0, local.calls)
+
+	// sectors skipped remotely are returned, not proven locally
+	resp = RemoteWindowPoStResponse{Skipped: []abi.SectorID{{Miner: 1000, Number: 2}}, Error: "sector 2 unreadable"}
+	_, skipped, err = p.GenerateWindowPoSt(ctx, 1000, sectors, abi.PoStRandomness{1})
+	require.EqualError(t, err, "sector 2 unreadable")
+	require.Equal(t, []abi.SectorID{{Miner: 1000, Number: 2}}, skipped)
+	require.Equal(t, 0, local.calls)
+
+	// the remote failing with enough time left falls back to local
+	status, resp = http.StatusInternalServerError, RemoteWindowPoStResponse{}
+	proofs, _, err = p.GenerateWindowPoSt(ctx, 1000, sectors, abi.PoStRandomness{1})
+	require.NoError(t, err)
+	require.Equal(t, []byte("post-proof"), proofs[0].ProofBytes)
+	require.Equal(t, 1, local.calls)
+
+	// but not too close to the end of the proving window
+	late := withProvingDeadline(context.Background(), time.Now().Add(5*time.Minute))
+	_, _, err = p.GenerateWindowPoSt(late, 1000, sectors, abi.PoStRandomness{1})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not computing the proofs locally")
+	require.Equal(t, 1, local.calls)
+
+	st := p.ProverStatus()
+	require.Len(t, st.Requests, 5)
+	require.Equal(t, srv.URL, st.Requests[0].Prover)
+	require.Equal(t, 2, st.Requests[0].Sectors)
+	require.Equal(t, "local", st.Requests[3].Prover)
+	require.Contains(t, st.Requests[4].Error, "500")
+}
@@ -3,6 +3,8 @@ package storage
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/filecoin-project/go-bitfield"
@@ -14,6 +16,7 @@ import (
 	"github.com/filecoin-project/go-state-types/crypto"
 	"github.com/filecoin-project/go-state-types/dline"
 	"github.com/filecoin-project/go-state-types/network"
+	"github.com/hashicorp/go-multierror"
 	"github.com/ipfs/go-cid"
 
 	"go.opencensus.io/trace"
@@ -180,7 +183,7 @@ func (s *WindowPoStScheduler) runSubmitPoST(
 		post.ChainCommitRand = commRand
 
 		// Submit PoST
-		sm, submitErr := s.submitPost(ctx, post)
+		sm, submitErr := s.submitPost(ctx, post, deadline.Close)
 		if submitErr != nil {
 			log.Errorf("submit window post failed: %+v", submitErr)
 		} else {
@@ -192,14 +195,21 @@ func (s *WindowPoStScheduler) runSubmitPoST(
 }
 
 func (s *WindowPoStScheduler) checkSectors(ctx context.Context, check bitfield.BitField, tsk types.TipSetKey) (bitfield.BitField, error) {
+	good, _, err := s.provableSectors(ctx, check, tsk)
+	return good, err
+}
+
+// provableSectors returns the sectors which can be proven, and why the others
+// can't
+func (s *WindowPoStScheduler) provableSectors(ctx context.Context, check bitfield.BitField, tsk types.TipSetKey) (bitfield.BitField, map[abi.SectorNumber]string, error) {
 	mid, err := address.IDFromAddress(s.actor)
 	if err != nil {
-		return bitfield.BitField{}, err
+		return bitfield.BitField{}, nil, err
 	}
 
 	sectorInfos, err := s.api.StateMinerSectors(ctx, s.actor, &check, tsk)
 	if err != nil {
-		return bitfield.BitField{}, err
+		return bitfield.BitField{}, nil, err
 	}
 
 	sectors := make(map[abi.SectorNumber]struct{})
@@ -217,10 +227,12 @@ func (s *WindowPoStScheduler) checkSectors(ctx context.Context, check bitfield.B
 
 	bad, err := s.faultTracker.CheckProvable(ctx, s.proofType, tocheck, nil)
 	if err != nil {
-		return bitfield.BitField{}, xerrors.Errorf("checking provable sectors: %w", err)
+		return bitfield.BitField{}, nil, xerrors.Errorf("checking provable sectors: %w", err)
 	}
-	for id := range bad {
+	reasons := make(map[abi.SectorNumber]string, len(bad))
+	for id, reason := range bad {
 		delete(sectors, id.Number)
+		reasons[id.Number] = reason
 	}
 
 	log.Warnw("Checked sectors", "checked", len(tocheck), "good", len(sectors))
@@ -230,10 +242,10 @@ func (s *WindowPoStScheduler) checkSectors(ctx context.Context, check bitfield.B
 		sbf.Set(uint64(s))
 	}
 
-	return sbf, nil
+	return sbf, reasons, nil
 }
 
-func (s *WindowPoStScheduler) checkNextRecoveries(ctx context.Context, dlIdx uint64, partitions []api.Partition, tsk types.TipSetKey) ([]miner.RecoveryDeclaration, *types.SignedMessage, error) {
+func (s *WindowPoStScheduler) checkNextRecoveries(ctx context.Context, dlIdx uint64, partitions []api.Partition, tsk types.TipSetKey, remaining abi.ChainEpoch) ([]miner.RecoveryDeclaration, *types.SignedMessage, error) {
 	ctx, span := trace.StartSpan(ctx, "storage.checkNextRecoveries")
 	defer span.End()
 
@@ -306,6 +318,11 @@ func (s *WindowPoStScheduler) checkNextRecoveries(ctx context.Context, dlIdx uin
 		return recoveries, nil, err
 	}
 
+	if premium, ok := s.feeEsc.Economical(msg.GasPremium, remaining); ok {
+		log.Infow("declaring recoveries well before the cutoff, paying an economical gas premium", "deadline", dlIdx, "remaining", remaining, "estimated", msg.GasPremium, "premium", premium)
+		msg.GasPremium = premium
+	}
+
 	sm, err := s.api.MpoolPushMessage(ctx, msg, &api.MessageSendSpec{MaxFee: abi.TokenAmount(s.feeCfg.MaxWindowPoStGasFee)})
 	if err != nil {
 		return recoveries, sm, xerrors.Errorf("pushing message to mpool: %w", err)
@@ -420,6 +437,8 @@ func (s *WindowPoStScheduler) runPost(ctx context.Context, di dline.Info, ts *ty
 		// check faults / recoveries for the *next* deadline. It's already too
 		// late to declare them for this deadline
 		declDeadline := (di.Index + 2) % di.WPoStPeriodDeadlines
+		// recoveries must land before the fault cutoff of that deadline
+		declCutoff := di.Open + 2*di.WPoStChallengeWindow - di.FaultDeclarationCutoff
 
 		partitions, err := s.api.StateMinerPartitions(context.TODO(), s.actor, declDeadline, ts.Key())
 		if err != nil {
@@ -443,7 +462,7 @@ func (s *WindowPoStScheduler) runPost(ctx context.Context, di dline.Info, ts *ty
 			}
 		)
 
-		if recoveries, sigmsg, err = s.checkNextRecoveries(context.TODO(), declDeadline, partitions, ts.Key()); err != nil {
+		if recoveries, sigmsg, err = s.checkNextRecoveries(context.TODO(), declDeadline, partitions, ts.Key(), declCutoff-ts.Height()); err != nil {
 			// TODO: This is potentially quite bad, but not even trying to post when this fails is objectively worse
 			log.Errorf("checking sector recoveries: %v", err)
 		}
@@ -509,179 +528,279 @@ func (s *WindowPoStScheduler) runPost(ctx context.Context, di dline.Info, ts *ty
 		return nil, err
 	}
 
-	// Generate proofs in batches
-	posts := make([]miner.SubmitWindowedPoStParams, 0, len(partitionBatches))
+	s.resetStatus(di, partitionBatches)
+
+	// Generate the proofs of the batches, up to parallelBatches at once. A
+	// batch failing doesn't keep the proofs of the others from being
+	// submitted.
+	throttle := make(chan struct{}, s.ParallelBatches())
+	batchPosts := make([]*miner.SubmitWindowedPoStParams, len(partitionBatches))
+	batchErrs := make([]error, len(partitionBatches))
+	var wg sync.WaitGroup
+	batchPartitionStartIdx := 0
 	for batchIdx, batch := range partitionBatches {
-		batchPartitionStartIdx := 0
-		for _, batch := range partitionBatches[:batchIdx] {
-			batchPartitionStartIdx += len(batch)
+		select {
+		case throttle <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			// don't start proving more batches for an aborted PoSt
+			break
 		}
 
-		params := miner.SubmitWindowedPoStParams{
-			Deadline:   di.Index,
-			Partitions: make([]miner.PoStPartition, 0, len(batch)),
-			Proofs:     nil,
+		wg.Add(1)
+		go func(batchIdx, startIdx int, batch []api.Partition) {
+			defer func() {
+				<-throttle
+				wg.Done()
+			}()
+			batchPosts[batchIdx], batchErrs[batchIdx] = s.proveBatch(ctx, di, ts, rand, buf.Bytes(), batchIdx, startIdx, batch)
+		}(batchIdx, batchPartitionStartIdx, batch)
+		batchPartitionStartIdx += len(batch)
+	}
+	wg.Wait()
+
+	// Explicitly make sure we haven't aborted this PoSt
+	// (GenerateWindowPoSt may or may not check this).
+	// Otherwise, we could try to continue proving a
+	// deadline after the deadline has ended.
+	if ctx.Err() != nil {
+		log.Warnw("aborting PoSt due to context cancellation", "error", ctx.Err(), "deadline", di.Index)
+		return nil, ctx.Err()
+	}
+
+	posts := make([]miner.SubmitWindowedPoStParams, 0, len(partitionBatches))
+	var failed error
+	for batchIdx := range partitionBatches {
+		if err := batchErrs[batchIdx]; err != nil {
+			failed = multierror.Append(failed, xerrors.Errorf("batch %d: %w", batchIdx, err))
+			continue
 		}
 
-		skipCount := uint64(0)
-		postSkipped := bitfield.New()
-		somethingToProve := false
+		// Nothing to prove for this batch
+		if batchPosts[batchIdx] == nil {
+			continue
+		}
 
-		// Retry until we run out of sectors to prove.
-		for retries := 0; ; retries++ {
-			var partitions []miner.PoStPartition
-			var sinfos []proof2.SectorInfo
-			for partIdx, partition := range batch {
-				// TODO: Can do this in parallel
-				toProve, err := bitfield.SubtractBitField(partition.LiveSectors, partition.FaultySectors)
-				if err != nil {
-					return nil, xerrors.Errorf("removing faults from set of sectors to prove: %w", err)
-				}
-				toProve, err = bitfield.MergeBitFields(toProve, partition.RecoveringSectors)
-				if err != nil {
-					return nil, xerrors.Errorf("adding recoveries to set of sectors to prove: %w", err)
-				}
+		posts = append(posts, *batchPosts[batchIdx])
+	}
 
-				good, err := s.checkSectors(ctx, toProve, ts.Key())
-				if err != nil {
-					return nil, xerrors.Errorf("checking sectors to skip: %w", err)
-				}
+	if failed != nil {
+		if len(posts) == 0 {
+			return nil, failed
+		}
+		log.Errorw("proving some partitions failed, submitting the proven ones", "deadline", di.Index, "error", failed)
+	}
 
-				good, err = bitfield.SubtractBitField(good, postSkipped)
-				if err != nil {
-					return nil, xerrors.Errorf("toProve - postSkipped: %w", err)
-				}
+	return posts, nil
+}
 
-				skipped, err := bitfield.SubtractBitField(toProve, good)
-				if err != nil {
-					return nil, xerrors.Errorf("toProve - good: %w", err)
-				}
+// proveBatch generates the proof of a batch of partitions, nil if there is
+// nothing to prove. Sectors failing to be proven are skipped, retrying without
+// them until the proof of the others is generated.
+func (s *WindowPoStScheduler) proveBatch(ctx context.Context, di dline.Info, ts *types.TipSet, rand abi.Randomness, entropy []byte, batchIdx, batchPartitionStartIdx int, batch []api.Partition) (*miner.SubmitWindowedPoStParams, error) {
+	params := miner.SubmitWindowedPoStParams{
+		Deadline:   di.Index,
+		Partitions: make([]miner.PoStPartition, 0, len(batch)),
+		Proofs:     nil,
+	}
 
-				sc, err := skipped.Count()
-				if err != nil {
-					return nil, xerrors.Errorf("getting skipped sector count: %w", err)
-				}
+	fail := func(err error) (*miner.SubmitWindowedPoStParams, error) {
+		for partIdx := range batch {
+			s.setPartitionStatus(di, uint64(batchPartitionStartIdx+partIdx), api.WdPoStFailed, 0, err)
+		}
+		return nil, err
+	}
 
-				skipCount += sc
+	mid, err := address.IDFromAddress(s.actor)
+	if err != nil {
+		return fail(err)
+	}
 
-				ssi, err := s.sectorsForProof(ctx, good, partition.AllSectors, ts)
-				if err != nil {
-					return nil, xerrors.Errorf("getting sorted sector info: %w", err)
-				}
+	skipCount := uint64(0)
+	postSkipped := bitfield.New()
+	// the sectors found bad when checking them, journaled once each
+	checkedBad := map[abi.SectorNumber]struct{}{}
+	// every retry skips at least one more sector, so there can't be more
+	// retries than there are sectors to prove
+	skipRetries, maxSkipRetries := 0, -1
+
+	// Retry until we run out of sectors to prove.
+	for retries := 0; ; retries++ {
+		var partitions []miner.PoStPartition
+		var sinfos []proof2.SectorInfo
+		sectorPartition := map[abi.SectorNumber]uint64{}
+		for partIdx, partition := range batch {
+			partIdx := uint64(batchPartitionStartIdx + partIdx)
+
+			toProve, err := bitfield.SubtractBitField(partition.LiveSectors, partition.FaultySectors)
+			if err != nil {
+				return fail(xerrors.Errorf("removing faults from set of sectors to prove: %w", err))
+			}
+			toProve, err = bitfield.MergeBitFields(toProve, partition.RecoveringSectors)
+			if err != nil {
+				return fail(xerrors.Errorf("adding recoveries to set of sectors to prove: %w", err))
+			}
 
-				if len(ssi) == 0 {
-					continue
+			good, bad, err := s.provableSectors(ctx, toProve, ts.Key())
+			if err != nil {
+				return fail(xerrors.Errorf("checking sectors to skip: %w", err))
+			}
+			for sector, reason := range bad {
+				if _, ok := checkedBad[sector]; !ok {
+					checkedBad[sector] = struct{}{}
+					s.recordSkippedSector(partIdx, sector, reason)
 				}
+			}
 
-				sinfos = append(sinfos, ssi...)
-				partitions = append(partitions, miner.PoStPartition{
-					Index:   uint64(batchPartitionStartIdx + partIdx),
-					Skipped: skipped,
-				})
+			good, err = bitfield.SubtractBitField(good, postSkipped)
+			if err != nil {
+				return fail(xerrors.Errorf("toProve - postSkipped: %w", err))
 			}
 
-			if len(sinfos) == 0 {
-				// nothing to prove for this batch
-				break
+			skipped, err := bitfield.SubtractBitField(toProve, good)
+			if err != nil {
+				return fail(xerrors.Errorf("toProve - good: %w", err))
 			}
 
-			// Generate proof
-			log.Infow("running window post",
-				"chain-random", rand,
-				"deadline", di,
-				"height", ts.Height(),
-				"skipped", skipCount)
+			sc, err := skipped.Count()
+			if err != nil {
+				return fail(xerrors.Errorf("getting skipped sector count: %w", err))
+			}
 
-			tsStart := build.Clock.Now()
+			skipCount += sc
 
-			mid, err := address.IDFromAddress(s.actor)
+			ssi, err := s.sectorsForProof(ctx, good, partition.AllSectors, ts)
 			if err != nil {
-				return nil, err
+				return fail(xerrors.Errorf("getting sorted sector info: %w", err))
 			}
 
-			postOut, ps, err := s.prover.GenerateWindowPoSt(ctx, abi.ActorID(mid), sinfos, append(abi.PoStRandomness{}, rand...))
-			elapsed := time.Since(tsStart)
+			if len(ssi) == 0 {
+				s.setPartitionStatus(di, partIdx, api.WdPoStNoSectors, sc, nil)
+				continue
+			}
 
-			log.Infow("computing window post", "batch", batchIdx, "elapsed", elapsed)
+			for _, si := range ssi {
+				sectorPartition[si.SectorNumber] = partIdx
+			}
+			sinfos = append(sinfos, ssi...)
+			partitions = append(partitions, miner.PoStPartition{
+				Index:   partIdx,
+				Skipped: skipped,
+			})
+		}
 
-			if err == nil {
-				// If we proved nothing, something is very wrong.
-				if len(postOut) == 0 {
-					return nil, xerrors.Errorf("received no proofs back from generate window post")
-				}
+		if len(sinfos) == 0 {
+			// nothing to prove for this batch
+			return nil, nil
+		}
+		if maxSkipRetries < 0 {
+			maxSkipRetries = len(sinfos)
+		}
 
-				headTs, err := s.api.ChainHead(ctx)
-				if err != nil {
-					return nil, xerrors.Errorf("getting current head: %w", err)
-				}
+		// Generate proof
+		log.Infow("running window post",
+			"chain-random", rand,
+			"deadline", di,
+			"height", ts.Height(),
+			"batch", batchIdx,
+			"skipped", skipCount)
 
-				checkRand, err := s.api.ChainGetRandomnessFromBeacon(ctx, headTs.Key(), crypto.DomainSeparationTag_WindowedPoStChallengeSeed, di.Challenge, buf.Bytes())
-				if err != nil {
-					return nil, xerrors.Errorf("failed to get chain randomness from beacon for window post (ts=%d; deadline=%d): %w", ts.Height(), di, err)
-				}
+		tsStart := build.Clock.Now()
+		closes := time.Unix(int64(ts.MinTimestamp())+int64(di.Close-ts.Height())*int64(build.BlockDelaySecs), 0)
 
-				if !bytes.Equal(checkRand, rand) {
-					log.Warnw("windowpost randomness changed", "old", rand, "new", checkRand, "ts-height", ts.Height(), "challenge-height", di.Challenge, "tsk", ts.Key())
-					continue
-				}
+		postOut, ps, err := s.prover.GenerateWindowPoSt(withProvingDeadline(ctx, closes), abi.ActorID(mid), sinfos, append(abi.PoStRandomness{}, rand...))
+		elapsed := time.Since(tsStart)
 
-				// If we generated an incorrect proof, try again.
-				if correct, err := s.verifier.VerifyWindowPoSt(ctx, proof.WindowPoStVerifyInfo{
-					Randomness:        abi.PoStRandomness(checkRand),
-					Proofs:            postOut,
-					ChallengedSectors: sinfos,
-					Prover:            abi.ActorID(mid),
-				}); err != nil {
-					log.Errorw("window post verification failed", "post", postOut, "error", err)
-					time.Sleep(5 * time.Second)
-					continue
-				} else if !correct {
-					log.Errorw("generated incorrect window post proof", "post", postOut, "error", err)
-					continue
-				}
+		log.Infow("computing window post", "batch", batchIdx, "elapsed", elapsed)
 
-				// Proof generation successful, stop retrying
-				somethingToProve = true
-				params.Partitions = partitions
-				params.Proofs = postOut
-				break
+		if err == nil {
+			// If we proved nothing, something is very wrong.
+			if len(postOut) == 0 {
+				return fail(xerrors.Errorf("received no proofs back from generate window post"))
 			}
 
-			// Proof generation failed, so retry
+			headTs, err := s.api.ChainHead(ctx)
+			if err != nil {
+				return fail(xerrors.Errorf("getting current head: %w", err))
+			}
 
-			if len(ps) == 0 {
-				// If we didn't skip any new sectors, we failed
-				// for some other reason and we need to abort.
-				return nil, xerrors.Errorf("running window post failed: %w", err)
+			checkRand, err := s.api.ChainGetRandomnessFromBeacon(ctx, headTs.Key(), crypto.DomainSeparationTag_WindowedPoStChallengeSeed, di.Challenge, entropy)
+			if err != nil {
+				return fail(xerrors.Errorf("failed to get chain randomness from beacon for window post (ts=%d; deadline=%d): %w", ts.Height(), di, err))
 			}
-			// TODO: maybe mark these as faulty somewhere?
 
-			log.Warnw("generate window post skipped sectors", "sectors", ps, "error", err, "try", retries)
+			if !bytes.Equal(checkRand, rand) {
+				log.Warnw("windowpost randomness changed", "old", rand, "new", checkRand, "ts-height", ts.Height(), "challenge-height", di.Challenge, "tsk", ts.Key())
+				continue
+			}
 
-			// Explicitly make sure we haven't aborted this PoSt
-			// (GenerateWindowPoSt may or may not check this).
-			// Otherwise, we could try to continue proving a
-			// deadline after the deadline has ended.
-			if ctx.Err() != nil {
-				log.Warnw("aborting PoSt due to context cancellation", "error", ctx.Err(), "deadline", di.Index)
-				return nil, ctx.Err()
+			// If we generated an incorrect proof, try again.
+			if correct, err := s.verifier.VerifyWindowPoSt(ctx, proof.WindowPoStVerifyInfo{
+				Randomness:        abi.PoStRandomness(checkRand),
+				Proofs:            postOut,
+				ChallengedSectors: sinfos,
+				Prover:            abi.ActorID(mid),
+			}); err != nil {
+				log.Errorw("window post verification failed", "post", postOut, "error", err)
+				time.Sleep(5 * time.Second)
+				continue
+			} else if !correct {
+				log.Errorw("generated incorrect window post proof", "post", postOut, "error", err)
+				continue
 			}
 
-			skipCount += uint64(len(ps))
-			for _, sector := range ps {
-				postSkipped.Set(uint64(sector.Number))
+			// Proof generation successful, stop retrying
+			params.Partitions = partitions
+			params.Proofs = postOut
+			for _, p := range partitions {
+				sc, err := p.Skipped.Count()
+				if err != nil {
+					return fail(xerrors.Errorf("getting skipped sector count: %w", err))
+				}
+				s.setPartitionStatus(di, p.Index, api.WdPoStProven, sc, nil)
 			}
+			return &params, nil
 		}
 
-		// Nothing to prove for this batch, try the next batch
-		if !somethingToProve {
-			continue
+		// Proof generation failed, so retry
+
+		if len(ps) == 0 {
+			// If we didn't skip any new sectors, we failed
+			// for some other reason and we need to abort.
+			return fail(xerrors.Errorf("running window post failed: %w", err))
+		}
+		if skipRetries >= maxSkipRetries {
+			return fail(xerrors.Errorf("running window post failed after %d retries skipping sectors: %w", skipRetries, err))
 		}
+		skipRetries++
+		// TODO: maybe mark these as faulty somewhere?
 
-		posts = append(posts, params)
+		log.Warnw("generate window post skipped sectors", "sectors", ps, "error", err, "try", retries)
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		skipCount += uint64(len(ps))
+		for _, sector := range ps {
+			postSkipped.Set(uint64(sector.Number))
+			s.recordSkippedSector(sectorPartition[sector.Number], sector.Number, fmt.Sprintf("generating the proof failed: %s", err))
+		}
 	}
+}
 
-	return posts, nil
+// recordSkippedSector records a sector left out of a proof in the journal
+func (s *WindowPoStScheduler) recordSkippedSector(partIdx uint64, sector abi.SectorNumber, reason string) {
+	log.Warnw("skipping sector in window post", "partition", partIdx, "sector", sector, "reason", reason)
+	s.journal.RecordEvent(s.evtTypes[evtTypeWdPoStSkipped], func() interface{} {
+		return WdPoStSectorSkippedEvt{
+			evtCommon: s.getEvtCommon(nil),
+			Partition: partIdx,
+			Sector:    sector,
+			Reason:    reason,
+		}
+	})
 }
 
 func (s *WindowPoStScheduler) batchPartitions(partitions []api.Partition, nv network.Version) ([][]api.Partition, error) {
@@ -765,7 +884,9 @@ func (s *WindowPoStScheduler) sectorsForProof(ctx context.Context, goodSectors,
 	return proofSectors, nil
 }
 
-func (s *WindowPoStScheduler) submitPost(ctx context.Context, proof *miner.SubmitWindowedPoStParams) (*types.SignedMessage, error) {
+// submitPost pushes the proofs, which must land before closeEpoch, and
+// escalates their fees as the close approaches
+func (s *WindowPoStScheduler) submitPost(ctx context.Context, proof *miner.SubmitWindowedPoStParams, closeEpoch abi.ChainEpoch) (*types.SignedMessage, error) {
 	ctx, span := trace.StartSpan(ctx, "storage.commitPost")
 	defer span.End()
 
@@ -810,9 +931,95 @@ func (s *WindowPoStScheduler) submitPost(ctx context.Context, proof *miner.Submi
 		log.Errorf("Submitting window post %s failed: exit %d", sm.Cid(), rec.Receipt.ExitCode)
 	}()
 
+	if s.feeEsc.Enabled() {
+		go s.escalateFees(context.TODO(), sm, closeEpoch)
+	}
+
 	return sm, nil
 }
 
+// escalateFees replaces the message, until it lands or the epoch it must land
+// by passes, with one paying the premium of the escalation curve for the
+// epochs left, and enough fee cap for the base fee
+func (s *WindowPoStScheduler) escalateFees(ctx context.Context, sm *types.SignedMessage, by abi.ChainEpoch) {
+	estimated := sm.Message.GasPremium
+	maxFee := abi.TokenAmount(s.feeCfg.MaxWindowPoStGasFee)
+
+	head, err := s.api.ChainHead(ctx)
+	if err != nil {
+		log.Errorw("escalating window post fees: getting chain head", "error", err)
+		return
+	}
+	sentAt := head.Height()
+
+	ticker := build.Clock.Ticker(time.Duration(build.BlockDelaySecs) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		head, err := s.api.ChainHead(ctx)
+		if err != nil {
+			log.Warnw("escalating window post fees: getting chain head", "error", err)
+			continue
+		}
+		remaining := by - head.Height()
+		if remaining <= 0 {
+			return
+		}
+
+		lookup, err := s.api.StateSearchMsg(ctx, head.Key(), sm.Cid(), head.Height()-sentAt+1, true)
+		if err != nil {
+			log.Warnw("escalating window post fees: searching message", "cid", sm.Cid(), "error", err)
+			continue
+		}
+		if lookup != nil {
+			return
+		}
+
+		baseFee := head.Blocks()[0].ParentBaseFee
+		premium, feeCap, ok := s.feeEsc.Escalate(&sm.Message, estimated, baseFee, maxFee, remaining)
+		if !ok {
+			continue
+		}
+
+		msg := sm.Message
+		msg.GasPremium, msg.GasFeeCap = premium, feeCap
+		replacement, err := s.api.WalletSignMessage(ctx, msg.From, &msg)
+		if err != nil {
+			log.Errorw("escalating window post fees: signing replacement message", "error", err)
+			continue
+		}
+		if _, err := s.api.MpoolPush(ctx, replacement); err != nil {
+			log.Errorw("escalating window post fees: pushing replacement message", "error", err)
+			continue
+		}
+
+		log.Warnw("replaced window post message paying a higher gas premium as the proving window closes",
+			"old", sm.Cid(), "new", replacement.Cid(), "remaining", remaining,
+			"premium", premium, "feecap", feeCap, "basefee", baseFee)
+		s.journal.RecordEvent(s.evtTypes[evtTypeWdPoStFeeEscalated], func() interface{} {
+			return WdPoStFeeEscalatedEvt{
+				evtCommon:       s.getEvtCommon(nil),
+				Method:          msg.Method,
+				OldMessageCID:   sm.Cid(),
+				NewMessageCID:   replacement.Cid(),
+				OldGasPremium:   sm.Message.GasPremium,
+				NewGasPremium:   premium,
+				OldGasFeeCap:    sm.Message.GasFeeCap,
+				NewGasFeeCap:    feeCap,
+				BaseFee:         baseFee,
+				RemainingEpochs: remaining,
+			}
+		})
+		sm = replacement
+	}
+}
+
 func (s *WindowPoStScheduler) setSender(ctx context.Context, msg *types.Message, spec *api.MessageSendSpec) error {
 	mi, err := s.api.StateMinerInfo(ctx, s.actor, types.EmptyTSK)
 	if err != nil {
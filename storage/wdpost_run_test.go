@@ -256,6 +256,158 @@ func TestWDPostDoPost(t *testing.T) {
 	}
 }
 
+// failingProver fails whenever a bad sector is challenged, skipping the first
+// one challenged if skip is set
+type failingProver struct {
+	mockProver
+	bad  map[abi.SectorNumber]bool
+	skip bool
+}
+
+func (m *failingProver) GenerateWindowPoSt(ctx context.Context, aid abi.ActorID, sis []proof2.SectorInfo, pr abi.PoStRandomness) ([]proof2.PoStProof, []abi.SectorID, error) {
+	for _, si := range sis {
+		if !m.bad[si.SectorNumber] {
+			continue
+		}
+		err := xerrors.Errorf("sector %d is unreadable", si.SectorNumber)
+		if !m.skip {
+			return nil, nil, err
+		}
+		return nil, []abi.SectorID{{Miner: aid, Number: si.SectorNumber}}, err
+	}
+	return m.mockProver.GenerateWindowPoSt(ctx, aid, sis, pr)
+}
+
+// testPartitions returns count partitions of consecutive live sectors
+func testPartitions(count int, sectorsPerPartition uint64) []api.Partition {
+	var partitions []api.Partition
+	for p := 0; p < count; p++ {
+		sectors := bitfield.New()
+		for s := uint64(0); s < sectorsPerPartition; s++ {
+			sectors.Set(uint64(p)*sectorsPerPartition + s)
+		}
+		partitions = append(partitions, api.Partition{
+			AllSectors:        sectors,
+			FaultySectors:     bitfield.New(),
+			RecoveringSectors: bitfield.New(),
+			LiveSectors:       sectors,
+			ActiveSectors:     sectors,
+		})
+	}
+	return partitions
+}
+
+// TestWDPostFailedBatch verifies that the partitions of the batches proven are
+// submitted when another batch keeps failing
+func TestWDPostFailedBatch(t *testing.T) {
+	ctx := context.Background()
+
+	proofType := abi.RegisteredPoStProof_StackedDrgWindow2KiBV1
+	postAct := tutils.NewIDAddr(t, 100)
+
+	mockStgMinerAPI := newMockStorageMinerAPI()
+
+	sectorsPerPartition, err := builtin5.PoStProofWindowPoStPartitionSectors(proofType)
+	require.NoError(t, err)
+	partitionsPerMsg, err := policy.GetMaxPoStPartitions(network.Version13, proofType)
+	require.NoError(t, err)
+	if partitionsPerMsg > miner5.AddressedPartitionsMax {
+		partitionsPerMsg = miner5.AddressedPartitionsMax
+	}
+
+	// A full batch, and a batch of a single partition failing to be proven
+	partitionCount := partitionsPerMsg + 1
+	mockStgMinerAPI.setPartitions(testPartitions(partitionCount, sectorsPerPartition))
+
+	bad := map[abi.SectorNumber]bool{}
+	for s := uint64(partitionCount-1) * sectorsPerPartition; s < uint64(partitionCount)*sectorsPerPartition; s++ {
+		bad[abi.SectorNumber(s)] = true
+	}
+	scheduler := &WindowPoStScheduler{
+		api:          mockStgMinerAPI,
+		prover:       &failingProver{bad: bad},
+		verifier:     &mockVerif{},
+		faultTracker: &mockFaultTracker{},
+		proofType:    proofType,
+		actor:        postAct,
+		journal:      journal.NilJournal(),
+		addrSel:      &AddressSelector{},
+	}
+
+	di := dline.Info{
+		WPoStPeriodDeadlines:   miner5.WPoStPeriodDeadlines,
+		WPoStProvingPeriod:     miner5.WPoStProvingPeriod,
+		WPoStChallengeWindow:   miner5.WPoStChallengeWindow,
+		WPoStChallengeLookback: miner5.WPoStChallengeLookback,
+		FaultDeclarationCutoff: miner5.FaultDeclarationCutoff,
+	}
+
+	posts, err := scheduler.runPost(ctx, di, mockTipSet(t))
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	require.Len(t, posts[0].Partitions, partitionsPerMsg)
+
+	st := scheduler.WdPoStStatus()
+	require.NotNil(t, st)
+	require.Len(t, st.Partitions, partitionCount)
+	for _, p := range st.Partitions[:partitionsPerMsg] {
+		require.Equal(t, api.WdPoStProven, p.State)
+	}
+	failed := st.Partitions[partitionCount-1]
+	require.Equal(t, api.WdPoStFailed, failed.State)
+	require.Equal(t, 1, failed.Batch)
+	require.Contains(t, failed.Error, "unreadable")
+}
+
+// TestWDPostSkippedRetries verifies that the proof is retried without the
+// skipped sectors for as long as the prover skips more of them
+func TestWDPostSkippedRetries(t *testing.T) {
+	ctx := context.Background()
+
+	proofType := abi.RegisteredPoStProof_StackedDrgWindow2KiBV1
+	postAct := tutils.NewIDAddr(t, 100)
+
+	mockStgMinerAPI := newMockStorageMinerAPI()
+
+	sectorsPerPartition, err := builtin5.PoStProofWindowPoStPartitionSectors(proofType)
+	require.NoError(t, err)
+	mockStgMinerAPI.setPartitions(testPartitions(2, sectorsPerPartition))
+
+	// the last sector of each partition is skipped, one per try
+	bad := map[abi.SectorNumber]bool{
+		abi.SectorNumber(sectorsPerPartition - 1):   true,
+		abi.SectorNumber(2*sectorsPerPartition - 1): true,
+	}
+	scheduler := &WindowPoStScheduler{
+		api:          mockStgMinerAPI,
+		prover:       &failingProver{bad: bad, skip: true},
+		verifier:     &mockVerif{},
+		faultTracker: &mockFaultTracker{},
+		proofType:    proofType,
+		actor:        postAct,
+		journal:      journal.NilJournal(),
+		addrSel:      &AddressSelector{},
+	}
+
+	di := dline.Info{
+		WPoStPeriodDeadlines:   miner5.WPoStPeriodDeadlines,
+		WPoStProvingPeriod:     miner5.WPoStProvingPeriod,
+		WPoStChallengeWindow:   miner5.WPoStChallengeWindow,
+		WPoStChallengeLookback: miner5.WPoStChallengeLookback,
+		FaultDeclarationCutoff: miner5.FaultDeclarationCutoff,
+	}
+
+	posts, err := scheduler.runPost(ctx, di, mockTipSet(t))
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	require.Len(t, posts[0].Partitions, 2)
+	for i, p := range posts[0].Partitions {
+		skipped, err := p.Skipped.All(sectorsPerPartition * 2)
+		require.NoError(t, err)
+		require.Equal(t, []uint64{uint64(i+1)*sectorsPerPartition - 1}, skipped)
+	}
+}
+
 func mockTipSet(t *testing.T) *types.TipSet {
 	minerAct := tutils.NewActorAddr(t, "miner")
 	c, err := cid.Decode("QmbFMke1KXqnYyBBWxB74N4c5SBnJMVAiMNRcGu6x1AwQH")
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"golang.org/x/xerrors"
@@ -9,7 +10,6 @@ import (
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/dline"
-	"github.com/filecoin-project/specs-storage/storage"
 
 	"github.com/filecoin-project/lotus/api"
 	"github.com/filecoin-project/lotus/build"
@@ -26,24 +26,31 @@ import (
 type WindowPoStScheduler struct {
 	api              storageMinerApi
 	feeCfg           config.MinerFeeConfig
+	feeEsc           *FeeEscalator
 	addrSel          *AddressSelector
-	prover           storage.Prover
+	prover           WindowPoStProver
 	verifier         ffiwrapper.Verifier
 	faultTracker     sectorstorage.FaultTracker
 	proofType        abi.RegisteredPoStProof
 	partitionSectors uint64
 	ch               *changeHandler
 
+	parallelLk      sync.Mutex
+	parallelBatches int
+
 	actor address.Address
 
-	evtTypes [4]journal.EventType
+	evtTypes [6]journal.EventType
 	journal  journal.Journal
 
+	statusLk sync.Mutex
+	status   *api.WdPoStStatus
+
 	// failed abi.ChainEpoch // eps
 	// failLk sync.Mutex
 }
 
-func NewWindowedPoStScheduler(api storageMinerApi, fc config.MinerFeeConfig, as *AddressSelector, sb storage.Prover, verif ffiwrapper.Verifier, ft sectorstorage.FaultTracker, j journal.Journal, actor address.Address) (*WindowPoStScheduler, error) {
+func NewWindowedPoStScheduler(api storageMinerApi, fc config.MinerFeeConfig, pc config.ProvingConfig, as *AddressSelector, sb WindowPoStProver, verif ffiwrapper.Verifier, ft sectorstorage.FaultTracker, j journal.Journal, actor address.Address) (*WindowPoStScheduler, error) {
 	mi, err := api.StateMinerInfo(context.TODO(), actor, types.EmptyTSK)
 	if err != nil {
 		return nil, xerrors.Errorf("getting sector size: %w", err)
@@ -52,19 +59,23 @@ func NewWindowedPoStScheduler(api storageMinerApi, fc config.MinerFeeConfig, as
 	return &WindowPoStScheduler{
 		api:              api,
 		feeCfg:           fc,
+		feeEsc:           NewFeeEscalator(fc.WindowPoStEscalation),
 		addrSel:          as,
 		prover:           sb,
 		verifier:         verif,
 		faultTracker:     ft,
 		proofType:        mi.WindowPoStProofType,
 		partitionSectors: mi.WindowPoStPartitionSectors,
+		parallelBatches:  pc.ParallelBatches,
 
 		actor: actor,
 		evtTypes: [...]journal.EventType{
-			evtTypeWdPoStScheduler:  j.RegisterEventType("wdpost", "scheduler"),
-			evtTypeWdPoStProofs:     j.RegisterEventType("wdpost", "proofs_processed"),
-			evtTypeWdPoStRecoveries: j.RegisterEventType("wdpost", "recoveries_processed"),
-			evtTypeWdPoStFaults:     j.RegisterEventType("wdpost", "faults_processed"),
+			evtTypeWdPoStScheduler:    j.RegisterEventType("wdpost", "scheduler"),
+			evtTypeWdPoStProofs:       j.RegisterEventType("wdpost", "proofs_processed"),
+			evtTypeWdPoStRecoveries:   j.RegisterEventType("wdpost", "recoveries_processed"),
+			evtTypeWdPoStFaults:       j.RegisterEventType("wdpost", "faults_processed"),
+			evtTypeWdPoStSkipped:      j.RegisterEventType("wdpost", "sector_skipped"),
+			evtTypeWdPoStFeeEscalated: j.RegisterEventType("wdpost", "fee_escalated"),
 		},
 		journal: j,
 	}, nil
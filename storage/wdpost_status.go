@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"github.com/filecoin-project/go-state-types/dline"
+
+	"github.com/filecoin-project/lotus/api"
+)
+
+// WdPoStStatus returns the progress of the proofs of the last deadline
+// WindowPoSt ran for, nil if it hasn't run yet
+func (s *WindowPoStScheduler) WdPoStStatus() *api.WdPoStStatus {
+	s.statusLk.Lock()
+	defer s.statusLk.Unlock()
+
+	if s.status == nil {
+		return nil
+	}
+	st := *s.status
+	st.Partitions = append([]api.WdPoStPartitionStatus{}, s.status.Partitions...)
+	return &st
+}
+
+// resetStatus starts the progress of the deadline, with the proofs of all
+// its partitions being generated
+func (s *WindowPoStScheduler) resetStatus(di dline.Info, batches [][]api.Partition) {
+	st := &api.WdPoStStatus{Deadline: di.Index, Open: di.Open, Close: di.Close}
+	for b, batch := range batches {
+		for range batch {
+			st.Partitions = append(st.Partitions, api.WdPoStPartitionStatus{
+				Index: uint64(len(st.Partitions)),
+				Batch: b,
+				State: api.WdPoStGenerating,
+			})
+		}
+	}
+
+	s.statusLk.Lock()
+	s.status = st
+	s.statusLk.Unlock()
+}
+
+// setPartitionStatus updates the progress of a partition of the deadline,
+// unless the progress of another deadline is tracked by now
+func (s *WindowPoStScheduler) setPartitionStatus(di dline.Info, partIdx uint64, state api.WdPoStPartitionState, skipped uint64, err error) {
+	s.statusLk.Lock()
+	defer s.statusLk.Unlock()
+
+	if s.status == nil || s.status.Deadline != di.Index || s.status.Open != di.Open || partIdx >= uint64(len(s.status.Partitions)) {
+		return
+	}
+	p := &s.status.Partitions[partIdx]
+	p.State = state
+	p.Skipped = skipped
+	p.Error = ""
+	if err != nil {
+		p.Error = err.Error()
+	}
+}
+
+// ProverStatus returns the prover computing the proofs, and its recent
+// requests
+func (s *WindowPoStScheduler) ProverStatus() api.WdPoStProverStatus {
+	st := api.WdPoStProverStatus{Prover: "local"}
+	if ps, ok := s.prover.(interface{ ProverStatus() api.WdPoStProverStatus }); ok {
+		st = ps.ProverStatus()
+	}
+	st.ParallelBatches = s.ParallelBatches()
+	return st
+}
+
+// ParallelBatches returns how many batches of partitions are proven at once
+func (s *WindowPoStScheduler) ParallelBatches() int {
+	s.parallelLk.Lock()
+	defer s.parallelLk.Unlock()
+
+	if s.parallelBatches < 1 {
+		return 1
+	}
+	return s.parallelBatches
+}
+
+// SetParallelBatches changes how many batches of partitions are proven at
+// once, from the next deadline on
+func (s *WindowPoStScheduler) SetParallelBatches(n int) {
+	s.parallelLk.Lock()
+	defer s.parallelLk.Unlock()
+
+	s.parallelBatches = n
+}